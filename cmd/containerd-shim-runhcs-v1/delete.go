@@ -6,9 +6,11 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/Microsoft/hcsshim/internal/hcs"
+	"github.com/Microsoft/hcsshim/internal/hcsoci"
 	"github.com/Microsoft/hcsshim/internal/oc"
 	"github.com/containerd/containerd/runtime/v2/task"
 	"github.com/gogo/protobuf/proto"
@@ -17,6 +19,10 @@ import (
 	"go.opencensus.io/trace"
 )
 
+// resourceJournalFile is the name of the file in the bundle that records the
+// resources allocated for the task. See hcsoci.RecoverJournal.
+const resourceJournalFile = "resources.journal"
+
 var deleteCommand = cli.Command{
 	Name: "delete",
 	Usage: `
@@ -82,6 +88,42 @@ The delete command will be executed in the container's bundle as its cwd.
 			}
 		}
 
+		// A utility VM created for restart recovery is not terminated when the
+		// shim that created it exits, so terminate the one in the shim state.
+		// It cannot be recovered anymore: containerd only runs delete once it
+		// gave up on the shim, and removes the bundle with the shim state
+		// afterwards.
+		if st, err := readShimState(bundleFlag); err == nil && st.HostID != "" {
+			if sys, _ := hcs.OpenComputeSystem(ctx, st.HostID); sys != nil {
+				if err := sys.Terminate(ctx); err != nil {
+					fmt.Fprintf(os.Stderr, "failed to terminate '%s': %v", st.HostID, err)
+				} else if err := sys.Wait(); err != nil {
+					fmt.Fprintf(os.Stderr, "failed to wait for '%s' to terminate: %v", st.HostID, err)
+				}
+				sys.Close()
+			}
+		}
+
+		// A pooled utility VM is recoverable and its compute system ID is not
+		// derived from the pod, so terminate the one recorded by the shim.
+		if b, err := ioutil.ReadFile(filepath.Join(bundleFlag, pooledVMFile)); err == nil {
+			systemID := string(b)
+			if sys, _ := hcs.OpenComputeSystem(ctx, systemID); sys != nil {
+				if err := sys.Terminate(ctx); err != nil {
+					fmt.Fprintf(os.Stderr, "failed to terminate '%s': %v", systemID, err)
+				} else if err := sys.Wait(); err != nil {
+					fmt.Fprintf(os.Stderr, "failed to wait for '%s' to terminate: %v", systemID, err)
+				}
+				sys.Close()
+			}
+		}
+
+		// Release the resources the shim allocated but did not get to release.
+		// Their attachments to the utility VMs terminated above are skipped.
+		if err := hcsoci.RecoverJournal(ctx, filepath.Join(bundleFlag, resourceJournalFile), nil); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to recover resources of '%s': %v", idFlag, err)
+		}
+
 		// Remove the bundle on disk
 		if err := os.RemoveAll(bundleFlag); err != nil && !os.IsNotExist(err) {
 			return err
@@ -2,17 +2,74 @@ package main
 
 import (
 	"context"
-	"errors"
 
 	"github.com/Microsoft/hcsshim/internal/hcsoci"
 	"github.com/Microsoft/hcsshim/internal/logfields"
 	"github.com/Microsoft/hcsshim/internal/oc"
 	"github.com/Microsoft/hcsshim/internal/shimdiag"
 	"github.com/Microsoft/hcsshim/internal/uvm"
+	"github.com/containerd/containerd/errdefs"
+	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"go.opencensus.io/trace"
 )
 
+// guestLogDir is the directory in the bundle that the guest logs of a utility
+// VM created for the task are captured to.
+const guestLogDir = "guest-logs"
+
+// maxGuestLogBytes limits the guest log returned by DiagGuestLog so that it
+// fits in a ttrpc message. The full guest log is in the files it is captured
+// to.
+const maxGuestLogBytes = 3 * 1024 * 1024
+
+// capturedGuestLog returns the end of the guest log `req.Source` captured for
+// `vm`.
+func capturedGuestLog(vm *uvm.UtilityVM, req *shimdiag.GuestLogRequest) (*shimdiag.GuestLogResponse, error) {
+	n := req.TailBytes
+	if n == 0 || n > maxGuestLogBytes {
+		n = maxGuestLogBytes
+	}
+	b, truncated, files, err := vm.CapturedLog(req.Source, int64(n))
+	if err != nil {
+		if err == uvm.ErrLogNotCaptured {
+			return nil, errors.Wrapf(errdefs.ErrNotFound, "guest log '%s' is not captured for utility VM '%s'", req.Source, vm.ID())
+		}
+		return nil, err
+	}
+	return &shimdiag.GuestLogResponse{
+		Data:      b,
+		Truncated: truncated,
+		Files:     files,
+	}, nil
+}
+
+// uvmInventory returns the devices attached to `vm` and the containers using
+// them.
+func uvmInventory(vm *uvm.UtilityVM) *shimdiag.InventoryResponse {
+	inv := hcsoci.DeviceInventory(vm)
+	resp := &shimdiag.InventoryResponse{
+		ID:              inv.ID,
+		Os:              inv.OS,
+		VpmemDevices:    inv.VPMemDevices,
+		VpmemMaxDevices: inv.VPMemMaxDevices,
+		ScsiDevices:     inv.SCSIDevices,
+		ScsiMaxDevices:  inv.SCSIMaxDevices,
+	}
+	for _, d := range inv.Devices {
+		resp.Devices = append(resp.Devices, &shimdiag.Device{
+			Kind:             d.Kind,
+			Location:         d.Location,
+			HostPath:         d.HostPath,
+			UvmPath:          d.UVMPath,
+			NetworkNamespace: d.NetNS,
+			RefCount:         d.RefCount,
+			Owners:           d.Owners,
+		})
+	}
+	return resp
+}
+
 func execInUvm(ctx context.Context, vm *uvm.UtilityVM, req *shimdiag.ExecProcessRequest) (_ int, err error) {
 	ctx, span := trace.StartSpan(ctx, "execInUvm")
 	defer span.End()
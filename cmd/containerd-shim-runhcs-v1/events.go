@@ -2,39 +2,181 @@ package main
 
 import (
 	"context"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/Microsoft/hcsshim/internal/log"
 	"github.com/Microsoft/hcsshim/internal/oc"
+	eventsapi "github.com/containerd/containerd/api/services/ttrpc/events/v1"
+	"github.com/containerd/containerd/errdefs"
 	"github.com/containerd/containerd/namespaces"
-	shim "github.com/containerd/containerd/runtime/v2/shim"
+	"github.com/containerd/containerd/pkg/ttrpcutil"
+	"github.com/containerd/ttrpc"
+	"github.com/containerd/typeurl"
+	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"go.opencensus.io/trace"
 )
 
+const (
+	// eventQueueSize is how many events can wait to be published before
+	// `publishEvent` blocks until there is room in the queue.
+	eventQueueSize = 1024
+	// eventForwardTimeout is how long containerd has to accept each attempt to
+	// forward an event.
+	eventForwardTimeout = 5 * time.Second
+	// eventRetryInitialDelay is the delay before the first retry of an event
+	// that failed to forward. The delay doubles on each retry up to
+	// `eventRetryMaxDelay`.
+	eventRetryInitialDelay = 100 * time.Millisecond
+	eventRetryMaxDelay     = 5 * time.Second
+	// eventRetryTimeout is how long after it was published an event is retried
+	// before it is dropped.
+	eventRetryTimeout = 2 * time.Minute
+	// eventFlushTimeout is how long `close` waits for the queued events to be
+	// published before they are dropped.
+	eventFlushTimeout = 5 * time.Second
+)
+
 type publisher interface {
 	publishEvent(ctx context.Context, topic string, event interface{}) (err error)
 }
 
+// eventForwarder forwards `env` to containerd.
+type eventForwarder func(ctx context.Context, env *eventsapi.Envelope) error
+
+// queuedEvent is an event waiting to be forwarded.
+type queuedEvent struct {
+	env *eventsapi.Envelope
+	// queued is when the event was published.
+	queued time.Time
+	// sc is the span of the `publishEvent` call, which is the parent of the
+	// spans that forward the event.
+	sc trace.SpanContext
+}
+
+// eventPublisher publishes events to containerd asynchronously. Events are
+// queued and forwarded in the order they were published by a single goroutine
+// that retries each event with backoff, so an event such as `TaskExit` is not
+// lost if containerd is briefly unavailable and publishing a burst of events
+// does not block the caller.
 type eventPublisher struct {
-	remotePublisher *shim.RemoteEventsPublisher
+	// client is the connection to containerd, or `nil` if `forward` does not
+	// use one.
+	client  *ttrpcutil.Client
+	forward eventForwarder
+
+	queue chan *queuedEvent
+	// closing is closed by `close` to flush the queue and stop `run`.
+	closing   chan struct{}
+	closeOnce sync.Once
+	// done is closed when `run` has returned.
+	done chan struct{}
+	// ctx is the context of `run`. It is canceled if the queue is not flushed
+	// within `eventFlushTimeout`, which drops the remaining events.
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// sending is `1` while `run` forwards an event taken off the queue.
+	//
+	// NOTE: All accesses to this MUST be done atomically.
+	sending int32
+	// published, retries and dropped count the events that were forwarded, the
+	// attempts to forward an event again after a failure, and the events that
+	// were given up on.
+	//
+	// NOTE: All accesses to these MUST be done atomically.
+	published uint64
+	retries   uint64
+	dropped   uint64
 }
 
 var _ = (publisher)(&eventPublisher{})
 
+// newEventPublisher connects to containerd at `address` and starts publishing
+// events to it.
 func newEventPublisher(address string) (*eventPublisher, error) {
-	p, err := shim.NewPublisher(address)
+	client, err := ttrpcutil.NewClient(address)
 	if err != nil {
 		return nil, err
 	}
-	return &eventPublisher{
-		remotePublisher: p,
-	}, nil
+	e := startEventPublisher(clientForwarder(client))
+	e.client = client
+	return e, nil
 }
 
+// startEventPublisher returns a publisher that forwards its events with
+// `forward`.
+func startEventPublisher(forward eventForwarder) *eventPublisher {
+	ctx, cancel := context.WithCancel(context.Background())
+	e := &eventPublisher{
+		forward: forward,
+		queue:   make(chan *queuedEvent, eventQueueSize),
+		closing: make(chan struct{}),
+		done:    make(chan struct{}),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+	go e.run()
+	return e
+}
+
+// clientForwarder returns a forwarder that forwards events over `client`,
+// reconnecting to containerd if the connection was closed.
+func clientForwarder(client *ttrpcutil.Client) eventForwarder {
+	return func(ctx context.Context, env *eventsapi.Envelope) error {
+		ctx, cancel := context.WithTimeout(ctx, eventForwardTimeout)
+		defer cancel()
+
+		req := &eventsapi.ForwardRequest{Envelope: env}
+		_, err := client.EventsService().Forward(ctx, req)
+		if err == ttrpc.ErrClosed {
+			if err := client.Reconnect(); err != nil {
+				return errors.Wrapf(errdefs.ErrUnavailable, "failed to reconnect to containerd: %s", err)
+			}
+			_, err = client.EventsService().Forward(ctx, req)
+		}
+		return err
+	}
+}
+
+// close stops accepting events and waits up to `eventFlushTimeout` for the
+// queued events to be published before it drops them and closes the
+// connection to containerd.
 func (e *eventPublisher) close() error {
-	return e.remotePublisher.Close()
+	e.closeOnce.Do(func() {
+		close(e.closing)
+	})
+	t := time.NewTimer(eventFlushTimeout)
+	select {
+	case <-e.done:
+		t.Stop()
+	case <-t.C:
+		e.cancel()
+		<-e.done
+	}
+	e.cancel()
+	if e.client != nil {
+		return e.client.Close()
+	}
+	return nil
 }
 
+// backlog returns the number of events that are not published yet.
+func (e *eventPublisher) backlog() int {
+	return len(e.queue) + int(atomic.LoadInt32(&e.sending))
+}
+
+// stats returns the number of events that were published, the number of
+// retries to publish an event, and the number of events that were dropped.
+func (e *eventPublisher) stats() (published, retries, dropped uint64) {
+	return atomic.LoadUint64(&e.published), atomic.LoadUint64(&e.retries), atomic.LoadUint64(&e.dropped)
+}
+
+// publishEvent queues `event` to be published on `topic` and returns once it
+// is queued. If the queue is full it waits for room rather than dropping the
+// event.
 func (e *eventPublisher) publishEvent(ctx context.Context, topic string, event interface{}) (err error) {
 	ctx, span := trace.StartSpan(ctx, "publishEvent")
 	defer span.End()
@@ -50,5 +192,117 @@ func (e *eventPublisher) publishEvent(ctx context.Context, topic string, event i
 		return nil
 	}
 
-	return e.remotePublisher.Publish(namespaces.WithNamespace(ctx, namespaceFlag), topic, event)
+	any, err := typeurl.MarshalAny(event)
+	if err != nil {
+		return err
+	}
+	qe := &queuedEvent{
+		env: &eventsapi.Envelope{
+			Timestamp: time.Now(),
+			Namespace: namespaceFlag,
+			Topic:     topic,
+			Event:     any,
+		},
+		queued: time.Now(),
+		sc:     span.SpanContext(),
+	}
+	select {
+	case <-e.closing:
+		return errors.Wrapf(errdefs.ErrUnavailable, "event publisher is closed, dropping '%s' event", topic)
+	default:
+	}
+	select {
+	case e.queue <- qe:
+		return nil
+	default:
+	}
+	log.G(ctx).WithField("topic", topic).Warn("event queue is full, waiting")
+	select {
+	case e.queue <- qe:
+		return nil
+	case <-e.closing:
+		return errors.Wrapf(errdefs.ErrUnavailable, "event publisher is closed, dropping '%s' event", topic)
+	}
+}
+
+// run forwards the queued events in order until the publisher is closed, and
+// then flushes the queue.
+//
+// This MUST be called via a goroutine.
+func (e *eventPublisher) run() {
+	defer close(e.done)
+	for {
+		select {
+		case qe := <-e.queue:
+			e.send(qe)
+		case <-e.closing:
+			for {
+				select {
+				case qe := <-e.queue:
+					e.send(qe)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// isTransportError returns true if `err` means that containerd could not be
+// reached, rather than that it rejected the event.
+func isTransportError(err error) bool {
+	if errors.Cause(err) == ttrpc.ErrClosed || errdefs.IsUnavailable(err) || errdefs.IsDeadlineExceeded(err) {
+		return true
+	}
+	err = errdefs.FromGRPC(err)
+	return errdefs.IsUnavailable(err) || errdefs.IsDeadlineExceeded(err)
+}
+
+// send forwards `qe` to containerd, retrying with backoff while containerd
+// cannot be reached. It is dropped at once if containerd rejects it, if it is
+// not forwarded within `eventRetryTimeout` of being published, or once `e.ctx`
+// is canceled.
+func (e *eventPublisher) send(qe *queuedEvent) {
+	atomic.StoreInt32(&e.sending, 1)
+	defer atomic.StoreInt32(&e.sending, 0)
+
+	ctx, span := trace.StartSpanWithRemoteParent(e.ctx, "eventPublisher::send", qe.sc)
+	defer span.End()
+	span.AddAttributes(trace.StringAttribute("topic", qe.env.Topic))
+	ctx = namespaces.WithNamespace(ctx, qe.env.Namespace)
+
+	delay := eventRetryInitialDelay
+	for attempt := 1; ; attempt++ {
+		err := ctx.Err()
+		if err == nil {
+			err = e.forward(ctx, qe.env)
+		}
+		if err == nil {
+			atomic.AddUint64(&e.published, 1)
+			return
+		}
+		entry := log.G(ctx).WithFields(logrus.Fields{
+			"topic":         qe.env.Topic,
+			"attempt":       attempt,
+			logrus.ErrorKey: err,
+		})
+		if ctx.Err() != nil || !isTransportError(err) || time.Since(qe.queued) >= eventRetryTimeout {
+			entry.Error("failed to publish event, dropping")
+			atomic.AddUint64(&e.dropped, 1)
+			oc.SetSpanStatus(span, err)
+			return
+		}
+		entry.Warn("failed to publish event, retrying")
+		atomic.AddUint64(&e.retries, 1)
+
+		t := time.NewTimer(delay)
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			t.Stop()
+		}
+		if delay *= 2; delay > eventRetryMaxDelay {
+			delay = eventRetryMaxDelay
+		}
+	}
 }
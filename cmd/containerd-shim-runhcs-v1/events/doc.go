@@ -0,0 +1,34 @@
+// Package events defines the events published by the runhcs shim in addition
+// to the containerd task events.
+package events
+
+// TaskExitReasonEventTopic is the topic of `TaskExitReason` events.
+const TaskExitReasonEventTopic = "/runhcs/tasks/exit-reason"
+
+// TaskExecUsageEventTopic is the topic of `TaskExecUsage` events.
+const TaskExecUsageEventTopic = "/runhcs/tasks/exec-usage"
+
+// Reasons of `TaskExitReason` events.
+const (
+	// ExitReasonGuestKernelPanic is the reason of an exit caused by a kernel
+	// panic in the hosting utility VM.
+	ExitReasonGuestKernelPanic = "GuestKernelPanic"
+	// ExitReasonUtilityVMCrash is the reason of an exit caused by the hosting
+	// utility VM terminating unexpectedly for any other reason.
+	ExitReasonUtilityVMCrash = "UtilityVMCrash"
+	// ExitReasonStopTimeout is the reason of an exit forced after the exec did
+	// not stop within the grace period of a kill.
+	ExitReasonStopTimeout = "StopTimeout"
+	// ExitReasonKilled is the reason of an exit with a non-zero status after a
+	// signal was delivered to the exec by a kill.
+	ExitReasonKilled = "Killed"
+	// ExitReasonHcsTerminate is the reason of an exit caused by the container
+	// being terminated outside of the shim, such as by the HCS.
+	ExitReasonHcsTerminate = "HcsTerminate"
+	// ExitReasonOOMKilled is the reason of an exit with a non-zero status of
+	// the init exec of a container that ran out of memory, as reported by the
+	// guest for a LCOW container and by the job object of the container for a
+	// process isolated WCOW container. It is not reported for a hypervisor
+	// isolated WCOW container.
+	ExitReasonOOMKilled = "OOMKilled"
+)
@@ -0,0 +1,1048 @@
+// Code generated by protoc-gen-gogo. DO NOT EDIT.
+// source: github.com/Microsoft/hcsshim/cmd/containerd-shim-runhcs-v1/events/events.proto
+
+package events
+
+import (
+	fmt "fmt"
+	proto "github.com/gogo/protobuf/proto"
+	io "io"
+	math "math"
+	reflect "reflect"
+	strings "strings"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.GoGoProtoPackageIsVersion2 // please upgrade the proto package
+
+// TaskExitReason is published before the TaskExit event of an exec whose exit
+// has a known reason.
+type TaskExitReason struct {
+	ContainerID          string   `protobuf:"bytes,1,opt,name=container_id,json=containerId,proto3" json:"container_id,omitempty"`
+	ID                   string   `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	Reason               string   `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	Message              string   `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TaskExitReason) Reset()      { *m = TaskExitReason{} }
+func (*TaskExitReason) ProtoMessage() {}
+func (*TaskExitReason) Descriptor() ([]byte, []int) {
+	return fileDescriptor_621a8fd92a53f22e, []int{0}
+}
+func (m *TaskExitReason) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TaskExitReason) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TaskExitReason.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *TaskExitReason) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TaskExitReason.Merge(m, src)
+}
+func (m *TaskExitReason) XXX_Size() int {
+	return m.Size()
+}
+func (m *TaskExitReason) XXX_DiscardUnknown() {
+	xxx_messageInfo_TaskExitReason.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TaskExitReason proto.InternalMessageInfo
+
+// TaskExecUsage is published before the TaskExit event of an exec with the
+// resources used by its process. The usage of a process in a Linux container
+// is the rusage the guest reports for it, whose I/O is counted in block
+// operations with no bytes.
+type TaskExecUsage struct {
+	ContainerID               string `protobuf:"bytes,1,opt,name=container_id,json=containerId,proto3" json:"container_id,omitempty"`
+	ID                        string `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	Pid                       uint32 `protobuf:"varint,3,opt,name=pid,proto3" json:"pid,omitempty"`
+	KernelTime_100Ns          uint64 `protobuf:"varint,4,opt,name=kernel_time_100_ns,json=kernelTime100Ns,proto3" json:"kernel_time_100_ns,omitempty"`
+	UserTime_100Ns            uint64 `protobuf:"varint,5,opt,name=user_time_100_ns,json=userTime100Ns,proto3" json:"user_time_100_ns,omitempty"`
+	MemoryCommitPeakBytes     uint64 `protobuf:"varint,6,opt,name=memory_commit_peak_bytes,json=memoryCommitPeakBytes,proto3" json:"memory_commit_peak_bytes,omitempty"`
+	MemoryWorkingSetPeakBytes uint64 `protobuf:"varint,7,opt,name=memory_working_set_peak_bytes,json=memoryWorkingSetPeakBytes,proto3" json:"memory_working_set_peak_bytes,omitempty"`
+	ReadOperations            uint64 `protobuf:"varint,8,opt,name=read_operations,json=readOperations,proto3" json:"read_operations,omitempty"`
+	ReadBytes                 uint64 `protobuf:"varint,9,opt,name=read_bytes,json=readBytes,proto3" json:"read_bytes,omitempty"`
+	WriteOperations           uint64 `protobuf:"varint,10,opt,name=write_operations,json=writeOperations,proto3" json:"write_operations,omitempty"`
+	WriteBytes                uint64 `protobuf:"varint,11,opt,name=write_bytes,json=writeBytes,proto3" json:"write_bytes,omitempty"`
+	// sampled is set if the usage was sampled while the process was running
+	// rather than read after it exited, in which case the usage after the
+	// last sample is missing.
+	Sampled              bool     `protobuf:"varint,12,opt,name=sampled,proto3" json:"sampled,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TaskExecUsage) Reset()      { *m = TaskExecUsage{} }
+func (*TaskExecUsage) ProtoMessage() {}
+func (*TaskExecUsage) Descriptor() ([]byte, []int) {
+	return fileDescriptor_621a8fd92a53f22e, []int{1}
+}
+func (m *TaskExecUsage) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TaskExecUsage) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TaskExecUsage.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *TaskExecUsage) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TaskExecUsage.Merge(m, src)
+}
+func (m *TaskExecUsage) XXX_Size() int {
+	return m.Size()
+}
+func (m *TaskExecUsage) XXX_DiscardUnknown() {
+	xxx_messageInfo_TaskExecUsage.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TaskExecUsage proto.InternalMessageInfo
+
+func init() {
+	proto.RegisterType((*TaskExitReason)(nil), "containerd.runhcs.events.v1.TaskExitReason")
+	proto.RegisterType((*TaskExecUsage)(nil), "containerd.runhcs.events.v1.TaskExecUsage")
+}
+
+func init() {
+	proto.RegisterFile("github.com/Microsoft/hcsshim/cmd/containerd-shim-runhcs-v1/events/events.proto", fileDescriptor_621a8fd92a53f22e)
+}
+
+var fileDescriptor_621a8fd92a53f22e = []byte{
+	// 483 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x93, 0xc1, 0x6e, 0xd3, 0x4c,
+	0x14, 0x85, 0xe3, 0xa4, 0x7f, 0xd2, 0xdc, 0x34, 0x4d, 0x64, 0xfd, 0xa0, 0x01, 0x54, 0xa7, 0xea,
+	0xa6, 0x45, 0x28, 0x71, 0x02, 0x0b, 0x16, 0x6c, 0x50, 0x5a, 0x24, 0xb2, 0xa0, 0x20, 0x53, 0x04,
+	0x62, 0x63, 0x39, 0xf6, 0xc5, 0x19, 0xb9, 0xe3, 0xb1, 0x66, 0x26, 0x29, 0xdd, 0xf1, 0x04, 0x3c,
+	0x57, 0x97, 0x2c, 0x59, 0x55, 0xd4, 0x6b, 0x1e, 0x02, 0xcd, 0x4c, 0x92, 0xa6, 0x6b, 0x58, 0xcd,
+	0xdc, 0x73, 0xbe, 0x73, 0x74, 0x65, 0x79, 0xe0, 0x34, 0xa5, 0x6a, 0x36, 0x9f, 0x0e, 0x62, 0xce,
+	0xfc, 0x37, 0x34, 0x16, 0x5c, 0xf2, 0x2f, 0xca, 0x9f, 0xc5, 0x52, 0xce, 0x28, 0xf3, 0x63, 0x96,
+	0xf8, 0x31, 0xcf, 0x55, 0x44, 0x73, 0x14, 0x49, 0x5f, 0x6b, 0x7d, 0x31, 0xcf, 0x67, 0xb1, 0xec,
+	0x2f, 0x46, 0x3e, 0x2e, 0x30, 0x57, 0x72, 0x79, 0x0c, 0x0a, 0xc1, 0x15, 0x77, 0x1f, 0xdd, 0xe2,
+	0x03, 0x4b, 0x0e, 0x96, 0xfe, 0x62, 0xf4, 0xf0, 0xff, 0x94, 0xa7, 0xdc, 0x70, 0xbe, 0xbe, 0xd9,
+	0xc8, 0xc1, 0x77, 0x07, 0x76, 0xcf, 0x22, 0x99, 0xbd, 0xfa, 0x4a, 0x55, 0x80, 0x91, 0xe4, 0xb9,
+	0xfb, 0x14, 0x76, 0xd6, 0x3d, 0x21, 0x4d, 0x88, 0xb3, 0xef, 0x1c, 0x35, 0xc7, 0x9d, 0xf2, 0xba,
+	0xd7, 0x3a, 0x5e, 0xe9, 0x93, 0x93, 0xa0, 0xb5, 0x86, 0x26, 0x89, 0x7b, 0x1f, 0xaa, 0x34, 0x21,
+	0x55, 0x43, 0xd6, 0xcb, 0xeb, 0x5e, 0x75, 0x72, 0x12, 0x54, 0xa9, 0xd6, 0xeb, 0xc2, 0xb4, 0x92,
+	0x9a, 0xf6, 0x82, 0xe5, 0xe4, 0x12, 0x68, 0x30, 0x94, 0x32, 0x4a, 0x91, 0x6c, 0x19, 0x63, 0x35,
+	0x1e, 0xfc, 0xae, 0x41, 0xdb, 0x2e, 0x84, 0xf1, 0x07, 0xad, 0xfc, 0xd3, 0x7d, 0xba, 0x50, 0x2b,
+	0x68, 0x62, 0x96, 0x69, 0x07, 0xfa, 0xea, 0x3e, 0x01, 0x37, 0x43, 0x91, 0xe3, 0x79, 0xa8, 0x28,
+	0xc3, 0x70, 0x34, 0x1c, 0x86, 0xb9, 0x34, 0x4b, 0x6d, 0x05, 0x1d, 0xeb, 0x9c, 0x51, 0x86, 0xa3,
+	0xe1, 0xf0, 0x54, 0xba, 0x87, 0xd0, 0x9d, 0x4b, 0x14, 0x77, 0xd0, 0xff, 0x0c, 0xda, 0xd6, 0xfa,
+	0x2d, 0xf8, 0x1c, 0x08, 0x43, 0xc6, 0xc5, 0x65, 0x18, 0x73, 0xc6, 0xa8, 0x0a, 0x0b, 0x8c, 0xb2,
+	0x70, 0x7a, 0xa9, 0x50, 0x92, 0xba, 0x09, 0xdc, 0xb3, 0xfe, 0xb1, 0xb1, 0xdf, 0x61, 0x94, 0x8d,
+	0xb5, 0xe9, 0xbe, 0x84, 0xbd, 0x65, 0xf0, 0x82, 0x8b, 0x8c, 0xe6, 0x69, 0x28, 0xf1, 0x4e, 0xba,
+	0x61, 0xd2, 0x0f, 0x2c, 0xf4, 0xd1, 0x32, 0xef, 0x71, 0xa3, 0xe1, 0x10, 0x3a, 0x02, 0xa3, 0x24,
+	0xe4, 0x05, 0x8a, 0x48, 0x51, 0x9e, 0x4b, 0xb2, 0x6d, 0x32, 0xbb, 0x5a, 0x7e, 0xbb, 0x56, 0xdd,
+	0x3d, 0x00, 0x03, 0xda, 0xde, 0xa6, 0x61, 0x9a, 0x5a, 0xb1, 0x3d, 0x8f, 0xa1, 0x7b, 0x21, 0xa8,
+	0xc2, 0xcd, 0x22, 0xb0, 0x9f, 0xc5, 0xe8, 0x1b, 0x4d, 0x3d, 0x68, 0x59, 0xd4, 0x56, 0xb5, 0x0c,
+	0x05, 0x46, 0xb2, 0x5d, 0x04, 0x1a, 0x32, 0x62, 0xc5, 0x39, 0x26, 0x64, 0x67, 0xdf, 0x39, 0xda,
+	0x0e, 0x56, 0xe3, 0x78, 0x7a, 0x75, 0xe3, 0x55, 0x7e, 0xde, 0x78, 0x95, 0x6f, 0xa5, 0xe7, 0x5c,
+	0x95, 0x9e, 0xf3, 0xa3, 0xf4, 0x9c, 0x5f, 0xa5, 0xe7, 0x7c, 0x7e, 0xfd, 0xd7, 0x8f, 0xe3, 0x85,
+	0x3d, 0x3e, 0x55, 0xa6, 0x75, 0xf3, 0xb3, 0x3f, 0xfb, 0x33, 0x00, 0xf0, 0xf3, 0x2b, 0x3b, 0x71,
+	0x03, 0x00, 0x00,
+}
+
+func (m *TaskExitReason) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TaskExitReason) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.ContainerID) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintEvents(dAtA, i, uint64(len(m.ContainerID)))
+		i += copy(dAtA[i:], m.ContainerID)
+	}
+	if len(m.ID) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintEvents(dAtA, i, uint64(len(m.ID)))
+		i += copy(dAtA[i:], m.ID)
+	}
+	if len(m.Reason) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintEvents(dAtA, i, uint64(len(m.Reason)))
+		i += copy(dAtA[i:], m.Reason)
+	}
+	if len(m.Message) > 0 {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintEvents(dAtA, i, uint64(len(m.Message)))
+		i += copy(dAtA[i:], m.Message)
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func (m *TaskExecUsage) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TaskExecUsage) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.ContainerID) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintEvents(dAtA, i, uint64(len(m.ContainerID)))
+		i += copy(dAtA[i:], m.ContainerID)
+	}
+	if len(m.ID) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintEvents(dAtA, i, uint64(len(m.ID)))
+		i += copy(dAtA[i:], m.ID)
+	}
+	if m.Pid != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintEvents(dAtA, i, uint64(m.Pid))
+	}
+	if m.KernelTime_100Ns != 0 {
+		dAtA[i] = 0x20
+		i++
+		i = encodeVarintEvents(dAtA, i, uint64(m.KernelTime_100Ns))
+	}
+	if m.UserTime_100Ns != 0 {
+		dAtA[i] = 0x28
+		i++
+		i = encodeVarintEvents(dAtA, i, uint64(m.UserTime_100Ns))
+	}
+	if m.MemoryCommitPeakBytes != 0 {
+		dAtA[i] = 0x30
+		i++
+		i = encodeVarintEvents(dAtA, i, uint64(m.MemoryCommitPeakBytes))
+	}
+	if m.MemoryWorkingSetPeakBytes != 0 {
+		dAtA[i] = 0x38
+		i++
+		i = encodeVarintEvents(dAtA, i, uint64(m.MemoryWorkingSetPeakBytes))
+	}
+	if m.ReadOperations != 0 {
+		dAtA[i] = 0x40
+		i++
+		i = encodeVarintEvents(dAtA, i, uint64(m.ReadOperations))
+	}
+	if m.ReadBytes != 0 {
+		dAtA[i] = 0x48
+		i++
+		i = encodeVarintEvents(dAtA, i, uint64(m.ReadBytes))
+	}
+	if m.WriteOperations != 0 {
+		dAtA[i] = 0x50
+		i++
+		i = encodeVarintEvents(dAtA, i, uint64(m.WriteOperations))
+	}
+	if m.WriteBytes != 0 {
+		dAtA[i] = 0x58
+		i++
+		i = encodeVarintEvents(dAtA, i, uint64(m.WriteBytes))
+	}
+	if m.Sampled {
+		dAtA[i] = 0x60
+		i++
+		if m.Sampled {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func encodeVarintEvents(dAtA []byte, offset int, v uint64) int {
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return offset + 1
+}
+func (m *TaskExitReason) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.ContainerID)
+	if l > 0 {
+		n += 1 + l + sovEvents(uint64(l))
+	}
+	l = len(m.ID)
+	if l > 0 {
+		n += 1 + l + sovEvents(uint64(l))
+	}
+	l = len(m.Reason)
+	if l > 0 {
+		n += 1 + l + sovEvents(uint64(l))
+	}
+	l = len(m.Message)
+	if l > 0 {
+		n += 1 + l + sovEvents(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *TaskExecUsage) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.ContainerID)
+	if l > 0 {
+		n += 1 + l + sovEvents(uint64(l))
+	}
+	l = len(m.ID)
+	if l > 0 {
+		n += 1 + l + sovEvents(uint64(l))
+	}
+	if m.Pid != 0 {
+		n += 1 + sovEvents(uint64(m.Pid))
+	}
+	if m.KernelTime_100Ns != 0 {
+		n += 1 + sovEvents(uint64(m.KernelTime_100Ns))
+	}
+	if m.UserTime_100Ns != 0 {
+		n += 1 + sovEvents(uint64(m.UserTime_100Ns))
+	}
+	if m.MemoryCommitPeakBytes != 0 {
+		n += 1 + sovEvents(uint64(m.MemoryCommitPeakBytes))
+	}
+	if m.MemoryWorkingSetPeakBytes != 0 {
+		n += 1 + sovEvents(uint64(m.MemoryWorkingSetPeakBytes))
+	}
+	if m.ReadOperations != 0 {
+		n += 1 + sovEvents(uint64(m.ReadOperations))
+	}
+	if m.ReadBytes != 0 {
+		n += 1 + sovEvents(uint64(m.ReadBytes))
+	}
+	if m.WriteOperations != 0 {
+		n += 1 + sovEvents(uint64(m.WriteOperations))
+	}
+	if m.WriteBytes != 0 {
+		n += 1 + sovEvents(uint64(m.WriteBytes))
+	}
+	if m.Sampled {
+		n += 2
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func sovEvents(x uint64) (n int) {
+	for {
+		n++
+		x >>= 7
+		if x == 0 {
+			break
+		}
+	}
+	return n
+}
+func sozEvents(x uint64) (n int) {
+	return sovEvents(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (this *TaskExitReason) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&TaskExitReason{`,
+		`ContainerID:` + fmt.Sprintf("%v", this.ContainerID) + `,`,
+		`ID:` + fmt.Sprintf("%v", this.ID) + `,`,
+		`Reason:` + fmt.Sprintf("%v", this.Reason) + `,`,
+		`Message:` + fmt.Sprintf("%v", this.Message) + `,`,
+		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *TaskExecUsage) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&TaskExecUsage{`,
+		`ContainerID:` + fmt.Sprintf("%v", this.ContainerID) + `,`,
+		`ID:` + fmt.Sprintf("%v", this.ID) + `,`,
+		`Pid:` + fmt.Sprintf("%v", this.Pid) + `,`,
+		`KernelTime_100Ns:` + fmt.Sprintf("%v", this.KernelTime_100Ns) + `,`,
+		`UserTime_100Ns:` + fmt.Sprintf("%v", this.UserTime_100Ns) + `,`,
+		`MemoryCommitPeakBytes:` + fmt.Sprintf("%v", this.MemoryCommitPeakBytes) + `,`,
+		`MemoryWorkingSetPeakBytes:` + fmt.Sprintf("%v", this.MemoryWorkingSetPeakBytes) + `,`,
+		`ReadOperations:` + fmt.Sprintf("%v", this.ReadOperations) + `,`,
+		`ReadBytes:` + fmt.Sprintf("%v", this.ReadBytes) + `,`,
+		`WriteOperations:` + fmt.Sprintf("%v", this.WriteOperations) + `,`,
+		`WriteBytes:` + fmt.Sprintf("%v", this.WriteBytes) + `,`,
+		`Sampled:` + fmt.Sprintf("%v", this.Sampled) + `,`,
+		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func valueToStringEvents(v interface{}) string {
+	rv := reflect.ValueOf(v)
+	if rv.IsNil() {
+		return "nil"
+	}
+	pv := reflect.Indirect(rv).Interface()
+	return fmt.Sprintf("*%v", pv)
+}
+func (m *TaskExitReason) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowEvents
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TaskExitReason: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TaskExitReason: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ContainerID", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEvents
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEvents
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEvents
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ContainerID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ID", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEvents
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEvents
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEvents
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Reason", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEvents
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEvents
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEvents
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Reason = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Message", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEvents
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEvents
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEvents
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Message = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipEvents(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthEvents
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthEvents
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *TaskExecUsage) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowEvents
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TaskExecUsage: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TaskExecUsage: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ContainerID", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEvents
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEvents
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEvents
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ContainerID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ID", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEvents
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEvents
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEvents
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Pid", wireType)
+			}
+			m.Pid = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEvents
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Pid |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field KernelTime_100Ns", wireType)
+			}
+			m.KernelTime_100Ns = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEvents
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.KernelTime_100Ns |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field UserTime_100Ns", wireType)
+			}
+			m.UserTime_100Ns = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEvents
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.UserTime_100Ns |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MemoryCommitPeakBytes", wireType)
+			}
+			m.MemoryCommitPeakBytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEvents
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MemoryCommitPeakBytes |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MemoryWorkingSetPeakBytes", wireType)
+			}
+			m.MemoryWorkingSetPeakBytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEvents
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MemoryWorkingSetPeakBytes |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 8:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ReadOperations", wireType)
+			}
+			m.ReadOperations = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEvents
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ReadOperations |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 9:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ReadBytes", wireType)
+			}
+			m.ReadBytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEvents
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ReadBytes |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 10:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field WriteOperations", wireType)
+			}
+			m.WriteOperations = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEvents
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.WriteOperations |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 11:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field WriteBytes", wireType)
+			}
+			m.WriteBytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEvents
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.WriteBytes |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 12:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Sampled", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEvents
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Sampled = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipEvents(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthEvents
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthEvents
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func skipEvents(dAtA []byte) (n int, err error) {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return 0, ErrIntOverflowEvents
+			}
+			if iNdEx >= l {
+				return 0, io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		wireType := int(wire & 0x7)
+		switch wireType {
+		case 0:
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowEvents
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				iNdEx++
+				if dAtA[iNdEx-1] < 0x80 {
+					break
+				}
+			}
+			return iNdEx, nil
+		case 1:
+			iNdEx += 8
+			return iNdEx, nil
+		case 2:
+			var length int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowEvents
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				length |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if length < 0 {
+				return 0, ErrInvalidLengthEvents
+			}
+			iNdEx += length
+			if iNdEx < 0 {
+				return 0, ErrInvalidLengthEvents
+			}
+			return iNdEx, nil
+		case 3:
+			for {
+				var innerWire uint64
+				var start int = iNdEx
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return 0, ErrIntOverflowEvents
+					}
+					if iNdEx >= l {
+						return 0, io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					innerWire |= (uint64(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				innerWireType := int(innerWire & 0x7)
+				if innerWireType == 4 {
+					break
+				}
+				next, err := skipEvents(dAtA[start:])
+				if err != nil {
+					return 0, err
+				}
+				iNdEx = start + next
+				if iNdEx < 0 {
+					return 0, ErrInvalidLengthEvents
+				}
+			}
+			return iNdEx, nil
+		case 4:
+			return iNdEx, nil
+		case 5:
+			iNdEx += 4
+			return iNdEx, nil
+		default:
+			return 0, fmt.Errorf("proto: illegal wireType %d", wireType)
+		}
+	}
+	panic("unreachable")
+}
+
+var (
+	ErrInvalidLengthEvents = fmt.Errorf("proto: negative length found during unmarshaling")
+	ErrIntOverflowEvents   = fmt.Errorf("proto: integer overflow")
+)
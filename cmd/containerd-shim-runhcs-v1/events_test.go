@@ -1,6 +1,16 @@
 package main
 
-import "context"
+import (
+	"context"
+	"math"
+	"reflect"
+	"sync"
+	"testing"
+
+	eventstypes "github.com/containerd/containerd/api/events"
+	eventsapi "github.com/containerd/containerd/api/services/ttrpc/events/v1"
+	"github.com/containerd/containerd/errdefs"
+)
 
 type fakePublisher struct {
 	events []interface{}
@@ -23,3 +33,113 @@ func (p *fakePublisher) publishEvent(ctx context.Context, topic string, event in
 func (p *fakePublisher) getEvents() []interface{} {
 	return p.events
 }
+
+// fakeForwarder records the topics of the events it forwards, failing the
+// first `failures` attempts with `err`, or `errdefs.ErrUnavailable` if `err` is
+// `nil`.
+type fakeForwarder struct {
+	m        sync.Mutex
+	failures int
+	err      error
+	attempts int
+	topics   []string
+}
+
+func (f *fakeForwarder) forward(ctx context.Context, env *eventsapi.Envelope) error {
+	f.m.Lock()
+	defer f.m.Unlock()
+	f.attempts++
+	if f.failures > 0 {
+		f.failures--
+		if f.err != nil {
+			return f.err
+		}
+		return errdefs.ErrUnavailable
+	}
+	f.topics = append(f.topics, env.Topic)
+	return nil
+}
+
+func (f *fakeForwarder) getTopics() []string {
+	f.m.Lock()
+	defer f.m.Unlock()
+	return append([]string(nil), f.topics...)
+}
+
+func Test_eventPublisher_RetriesInOrder(t *testing.T) {
+	f := &fakeForwarder{failures: 2}
+	e := startEventPublisher(f.forward)
+
+	topics := []string{"/tasks/exec-started", "/tasks/exit", "/tasks/delete"}
+	for _, topic := range topics {
+		if err := e.publishEvent(context.TODO(), topic, &eventstypes.TaskExit{ContainerID: t.Name()}); err != nil {
+			t.Fatalf("should not have failed, got: %v", err)
+		}
+	}
+	if err := e.close(); err != nil {
+		t.Fatalf("should not have failed to close, got: %v", err)
+	}
+
+	if actual := f.getTopics(); !reflect.DeepEqual(actual, topics) {
+		t.Fatalf("expected topics %v, got: %v", topics, actual)
+	}
+	if published, retries, dropped := e.stats(); published != 3 || retries != 2 || dropped != 0 {
+		t.Fatalf("expected 3 published, 2 retries and 0 dropped, got: %d, %d, %d", published, retries, dropped)
+	}
+	if e.backlog() != 0 {
+		t.Fatalf("expected empty backlog, got: %d", e.backlog())
+	}
+}
+
+func Test_eventPublisher_DropsRejected(t *testing.T) {
+	f := &fakeForwarder{failures: 1, err: errdefs.ErrInvalidArgument}
+	e := startEventPublisher(f.forward)
+
+	topics := []string{"/tasks/exit", "/tasks/delete"}
+	for _, topic := range topics {
+		if err := e.publishEvent(context.TODO(), topic, &eventstypes.TaskExit{ContainerID: t.Name()}); err != nil {
+			t.Fatalf("should not have failed, got: %v", err)
+		}
+	}
+	if err := e.close(); err != nil {
+		t.Fatalf("should not have failed to close, got: %v", err)
+	}
+
+	if actual := f.getTopics(); !reflect.DeepEqual(actual, topics[1:]) {
+		t.Fatalf("expected topics %v, got: %v", topics[1:], actual)
+	}
+	if published, retries, dropped := e.stats(); published != 1 || retries != 0 || dropped != 1 {
+		t.Fatalf("expected 1 published, 0 retries and 1 dropped, got: %d, %d, %d", published, retries, dropped)
+	}
+}
+
+func Test_eventPublisher_Closed(t *testing.T) {
+	f := &fakeForwarder{}
+	e := startEventPublisher(f.forward)
+	if err := e.close(); err != nil {
+		t.Fatalf("should not have failed to close, got: %v", err)
+	}
+
+	err := e.publishEvent(context.TODO(), "/tasks/exit", &eventstypes.TaskExit{ContainerID: t.Name()})
+	verifyExpectedError(t, nil, err, errdefs.ErrUnavailable)
+	if len(f.getTopics()) != 0 {
+		t.Fatalf("expected no events to be forwarded, got: %v", f.getTopics())
+	}
+}
+
+func Test_eventPublisher_Close_DropsAfterFlushTimeout(t *testing.T) {
+	// Fail every attempt so that the event is still queued when the flush
+	// times out.
+	f := &fakeForwarder{failures: math.MaxInt32}
+	e := startEventPublisher(f.forward)
+	if err := e.publishEvent(context.TODO(), "/tasks/exit", &eventstypes.TaskExit{ContainerID: t.Name()}); err != nil {
+		t.Fatalf("should not have failed, got: %v", err)
+	}
+	if err := e.close(); err != nil {
+		t.Fatalf("should not have failed to close, got: %v", err)
+	}
+
+	if published, _, dropped := e.stats(); published != 0 || dropped != 1 {
+		t.Fatalf("expected 0 published and 1 dropped, got: %d, %d", published, dropped)
+	}
+}
@@ -6,10 +6,13 @@ import (
 	"sync"
 	"time"
 
+	runhcsevents "github.com/Microsoft/hcsshim/cmd/containerd-shim-runhcs-v1/events"
 	"github.com/Microsoft/hcsshim/internal/cow"
 	"github.com/Microsoft/hcsshim/internal/guestrequest"
+	"github.com/Microsoft/hcsshim/internal/hcs"
 	"github.com/Microsoft/hcsshim/internal/hcsoci"
 	"github.com/Microsoft/hcsshim/internal/log"
+	"github.com/Microsoft/hcsshim/internal/oc"
 	"github.com/Microsoft/hcsshim/internal/signals"
 	"github.com/Microsoft/hcsshim/internal/uvm"
 	"github.com/Microsoft/hcsshim/osversion"
@@ -46,6 +49,7 @@ func newHcsExec(
 	tid string,
 	host *uvm.UtilityVM,
 	c cow.Container,
+	sampler *usageSampler,
 	id, bundle string,
 	isWCOW bool,
 	spec *specs.Process,
@@ -63,6 +67,7 @@ func newHcsExec(
 		tid:         tid,
 		host:        host,
 		c:           c,
+		sampler:     sampler,
 		id:          id,
 		bundle:      bundle,
 		isWCOW:      isWCOW,
@@ -77,6 +82,138 @@ func newHcsExec(
 	return he
 }
 
+// newRecoveredHcsExec rebuilds the exec `es` in `c` recorded by a shim that
+// exited. A running process is reopened and its stdio, reopened through the
+// HCS, is relayed again to the io `es` was created with. If the process is no
+// longer running it exited while no shim was tracking it, so its exit status is
+// unknown and the exec is exited with status 255.
+//
+// A created exec reconnects its io and is started as usual. Recovery fails if
+// the io of a created or running exec cannot be reconnected, rather than
+// losing its output.
+func newRecoveredHcsExec(
+	ctx context.Context,
+	events publisher,
+	tid string,
+	host *uvm.UtilityVM,
+	c cow.Container,
+	sampler *usageSampler,
+	bundle string,
+	isWCOW bool,
+	es *execState) (_ shimExec, err error) {
+	ctx, span := trace.StartSpan(ctx, "newRecoveredHcsExec")
+	defer span.End()
+	defer func() { oc.SetSpanStatus(span, err) }()
+	span.AddAttributes(
+		trace.StringAttribute("tid", tid),
+		trace.StringAttribute("eid", es.ID),
+		trace.StringAttribute("state", string(es.State)))
+
+	if es.State == shimExecStateCreated {
+		io, err := newUpstreamIO(ctx, tid, es.ID, es.Stdin, es.Stdout, es.Stderr, es.Terminal)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to reconnect the io of exec: '%s' in task: '%s'", es.ID, tid)
+		}
+		return newHcsExec(ctx, events, tid, host, c, sampler, es.ID, bundle, isWCOW, es.Spec, io), nil
+	}
+
+	he := &hcsExec{
+		events:      events,
+		tid:         tid,
+		host:        host,
+		c:           c,
+		sampler:     sampler,
+		id:          es.ID,
+		bundle:      bundle,
+		isWCOW:      isWCOW,
+		spec:        es.Spec,
+		io:          newDisconnectedIO(es.Stdin, es.Stdout, es.Stderr, es.Terminal),
+		processDone: make(chan struct{}),
+		state:       shimExecStateExited,
+		pid:         es.Pid,
+		exitStatus:  es.ExitStatus,
+		exitedAt:    es.ExitedAt,
+		exited:      make(chan struct{}),
+	}
+	if es.State == shimExecStateExited {
+		he.processDoneOnce.Do(func() { close(he.processDone) })
+		he.exitedOnce.Do(func() { close(he.exited) })
+		return he, nil
+	}
+
+	system, ok := c.(*hcs.System)
+	if !ok {
+		return nil, errors.Wrapf(errdefs.ErrFailedPrecondition, "exec: '%s' in task: '%s' cannot be reopened", es.ID, tid)
+	}
+	p, err := system.OpenProcess(ctx, es.Pid)
+	if err != nil {
+		log.G(ctx).WithError(err).Warn("process exited while no shim was running")
+		he.processDoneOnce.Do(func() { close(he.processDone) })
+		he.exitStatus = 255
+		he.exitedAt = time.Now()
+		if tid != es.ID {
+			he.events.publishEvent(
+				ctx,
+				runtime.TaskExitEventTopic,
+				&eventstypes.TaskExit{
+					ContainerID: tid,
+					ID:          es.ID,
+					Pid:         uint32(he.pid),
+					ExitStatus:  he.exitStatus,
+					ExitedAt:    he.exitedAt,
+				})
+		}
+		he.exitedOnce.Do(func() { close(he.exited) })
+		return he, nil
+	}
+	if err := he.attach(ctx, p, es); err != nil {
+		p.Close()
+		return nil, err
+	}
+	he.state = shimExecStateRunning
+	he.usage = startExecUsage(ctx, sampler, he.pid, isWCOW && host == nil)
+	he.exitStatus = 255 // By design for non-exited process status.
+	he.exitedAt = time.Time{}
+	go he.waitForExit()
+	go he.waitForContainerExit()
+	return he, nil
+}
+
+// attach relays the stdio of the reopened running process `p` of the exec `es`
+// to the io it was created with.
+func (he *hcsExec) attach(ctx context.Context, p *hcs.Process, es *execState) error {
+	stdin, stdout, stderr, err := p.StdioLegacy()
+	if err != nil {
+		return errors.Wrapf(err, "failed to reopen the stdio of exec: '%s' in task: '%s'", es.ID, he.tid)
+	}
+	io, err := newUpstreamIO(ctx, he.tid, es.ID, es.Stdin, es.Stdout, es.Stderr, es.Terminal)
+	if err != nil {
+		if stdin != nil {
+			stdin.Close()
+		}
+		if stdout != nil {
+			stdout.Close()
+		}
+		if stderr != nil {
+			stderr.Close()
+		}
+		return errors.Wrapf(err, "failed to reconnect the io of exec: '%s' in task: '%s'", es.ID, he.tid)
+	}
+	he.io = io
+	he.p = &hcsoci.Cmd{
+		Host:   he.c,
+		Stdin:  io.Stdin(),
+		Stdout: io.Stdout(),
+		Stderr: io.Stderr(),
+		Log: log.G(ctx).WithFields(logrus.Fields{
+			"tid": he.tid,
+			"eid": he.id,
+		}),
+		CopyAfterExitTimeout: time.Second * 1,
+	}
+	return he.p.Attach(p, stdin, stdout, stderr)
+}
+
 var _ = (shimExec)(&hcsExec{})
 
 type hcsExec struct {
@@ -94,6 +231,11 @@ type hcsExec struct {
 	//
 	// This MUST be treated as read only in the lifetime of the exec.
 	c cow.Container
+	// sampler is the usage sampler of `c` shared by its execs, or nil if the
+	// usage of the processes of `c` is not sampled.
+	//
+	// This MUST be treated as read only in the lifetime of the exec.
+	sampler *usageSampler
 	// id is the id of this process.
 	//
 	// This MUST be treated as read only in the lifetime of the exec.
@@ -131,6 +273,16 @@ type hcsExec struct {
 	exitStatus uint32
 	exitedAt   time.Time
 	p          *hcsoci.Cmd
+	// killSignal is the last signal delivered to the process by `Kill`, or 0.
+	killSignal uint32
+	// terminated is set if `c` exited while the process was running without
+	// the shim stopping it, and terminateErr and terminateExitType are the
+	// exit error and the exit type of `c` if any.
+	terminated        bool
+	terminateErr      error
+	terminateExitType string
+	// usage tracks the resources used by the process once it is running.
+	usage *execUsage
 
 	// exited is a wait block which waits async for the process to exit.
 	exited     chan struct{}
@@ -231,6 +383,7 @@ func (he *hcsExec) Start(ctx context.Context) (err error) {
 	// Assign the PID and transition the state.
 	he.pid = he.p.Process.Pid()
 	he.state = shimExecStateRunning
+	he.usage = startExecUsage(ctx, he.sampler, he.pid, he.isWCOW && he.host == nil)
 
 	// Publish the task/exec start event. This MUST happen before waitForExit to
 	// avoid publishing the exit previous to the start.
@@ -302,6 +455,7 @@ func (he *hcsExec) Kill(ctx context.Context, signal uint32) error {
 		if !delivered {
 			return errors.Wrapf(errdefs.ErrNotFound, "exec: '%s' in task: '%s' not found", he.id, he.tid)
 		}
+		he.killSignal = signal
 		return nil
 	case shimExecStateExited:
 		return errors.Wrapf(errdefs.ErrNotFound, "exec: '%s' in task: '%s' not found", he.id, he.tid)
@@ -421,7 +575,8 @@ func (he *hcsExec) exitFromCreatedL(ctx context.Context, status int) {
 //
 // 4. Wait for all IO to complete and release any upstream IO connections.
 //
-// 5. Send the async `TaskExit` to upstream listeners of any events.
+// 5. Send the async `TaskExecUsage` and `TaskExit` to upstream listeners of
+// any events.
 //
 // 6. Close `he.exited` channel to unblock any waiters who might have called
 // `Create`/`Wait`/`Start` which is a valid pattern.
@@ -448,6 +603,9 @@ func (he *hcsExec) waitForExit() {
 		log.G(ctx).WithField("exitCode", code).Debug("exited")
 	}
 
+	// Collect the final usage of the process now that it exited.
+	usage := he.usage.stop(ctx, he.p.Process)
+
 	he.sl.Lock()
 	he.state = shimExecStateExited
 	he.exitStatus = uint32(code)
@@ -461,6 +619,9 @@ func (he *hcsExec) waitForExit() {
 	// Only send the `runtime.TaskExitEventTopic` notification if this is a true
 	// exec. For the `init` exec this is handled in task teardown.
 	if he.tid != he.id {
+		publishExecUsage(ctx, he.events, he.tid, he.id, usage)
+		f := he.exitFacts()
+		publishExitReason(ctx, he.events, he.tid, he.id, &f)
 		// We had a valid process so send the exited notification.
 		he.events.publishEvent(
 			ctx,
@@ -506,6 +667,17 @@ func (he *hcsExec) waitForContainerExit() {
 		case shimExecStateCreated:
 			he.exitFromCreatedL(ctx, 1)
 		case shimExecStateRunning:
+			// The shim only stops the container after the init process exits,
+			// so the container was terminated if the init process is running.
+			// An additional exec can only tell from the exit error or the
+			// exit type reported by the HCS.
+			err := containerExitError(he.c)
+			exitType := containerExitType(he.c)
+			if he.id == he.tid || err != nil || (exitType != "" && exitType != hcs.GracefulExit) {
+				he.terminated = true
+				he.terminateErr = err
+				he.terminateExitType = exitType
+			}
 			// Kill the process to unblock `he.waitForExit`.
 			he.p.Process.Kill(ctx)
 		}
@@ -516,6 +688,37 @@ func (he *hcsExec) waitForContainerExit() {
 	}
 }
 
+// exitFacts returns what this exec observed about the exit of its process.
+func (he *hcsExec) exitFacts() exitFacts {
+	he.sl.Lock()
+	defer he.sl.Unlock()
+	f := exitFacts{
+		exitStatus:        he.exitStatus,
+		killSignal:        he.killSignal,
+		terminated:        he.terminated,
+		terminateErr:      he.terminateErr,
+		terminateExitType: he.terminateExitType,
+	}
+	f.setHost(he.host)
+	return f
+}
+
+// exitUsage returns the resources used by the exited process, or nil if the
+// process never ran in this shim.
+func (he *hcsExec) exitUsage(ctx context.Context) *runhcsevents.TaskExecUsage {
+	he.sl.Lock()
+	usage := he.usage
+	var p cow.Process
+	if he.p != nil {
+		p = he.p.Process
+	}
+	he.sl.Unlock()
+	if usage == nil {
+		return nil
+	}
+	return usage.stop(ctx, p)
+}
+
 // escapeArgs makes a Windows-style escaped command line from a set of arguments
 func escapeArgs(args []string) string {
 	escapedArgs := make([]string, len(args))
@@ -0,0 +1,278 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Microsoft/go-winio/pkg/process"
+	runhcsevents "github.com/Microsoft/hcsshim/cmd/containerd-shim-runhcs-v1/events"
+	"github.com/Microsoft/hcsshim/internal/cow"
+	"github.com/Microsoft/hcsshim/internal/gcs"
+	"github.com/Microsoft/hcsshim/internal/log"
+	"github.com/Microsoft/hcsshim/internal/schema1"
+	"golang.org/x/sys/windows"
+)
+
+//go:generate go run ../../mksyscall_windows.go -output zsyscall_windows.go exec_usage.go health.go oom.go
+
+//sys getProcessIoCounters(process windows.Handle, counters *ioCounters) (err error) = kernel32.GetProcessIoCounters
+
+// execUsageSampleInterval is how often the usage of the running processes
+// that are not on the host is sampled from the process list of their
+// container.
+const execUsageSampleInterval = 2 * time.Second
+
+// ioCounters is the IO_COUNTERS struct from Windows.
+type ioCounters struct {
+	ReadOperationCount  uint64
+	WriteOperationCount uint64
+	OtherOperationCount uint64
+	ReadTransferCount   uint64
+	WriteTransferCount  uint64
+	OtherTransferCount  uint64
+}
+
+// execUsage tracks the resources used by the process of an exec.
+//
+// The usage of a process on the host, IE: a process isolated WCOW process, is
+// read from a handle to the process after it exits. The handle keeps the
+// accounting of the process after it exits so this usage is complete.
+//
+// The usage of a process in a WCOW utility VM is sampled from the process list
+// of its container by the `usageSampler` of the container while it runs so
+// the usage after the last sample is missing, and there are no I/O counters.
+//
+// The usage of a process in a LCOW container is the rusage of the process the
+// guest reports when it exits. The guest reports block operations rather than
+// bytes, and a LCOW container not created through a guest connection of the
+// shim, or a guest that does not report it, leaves only the pid set.
+type execUsage struct {
+	pid int
+	// h is a handle to the process if it is on the host.
+	h windows.Handle
+	// sampler is the sampler of the container of the process if its usage is
+	// sampled.
+	sampler *usageSampler
+
+	stopOnce sync.Once
+
+	// m MUST be held to safely read/write `usage`.
+	m     sync.Mutex
+	usage runhcsevents.TaskExecUsage
+}
+
+// startExecUsage starts tracking the usage of the running process `pid`. If
+// `onHost` the process is on the host and its usage is read from the process
+// directly, otherwise it is sampled by `sampler`. If `sampler` is nil, IE: for
+// a LCOW container, the usage of a process that is not on the host is not
+// tracked.
+func startExecUsage(ctx context.Context, sampler *usageSampler, pid int, onHost bool) *execUsage {
+	u := &execUsage{
+		pid: pid,
+	}
+	u.usage.Pid = uint32(pid)
+	if onHost {
+		h, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION|windows.PROCESS_VM_READ, false, uint32(pid))
+		if err == nil {
+			u.h = h
+			return u
+		}
+		log.G(ctx).WithError(err).Warn("failed to open process, sampling its usage instead")
+	}
+	if sampler != nil {
+		u.usage.Sampled = true
+		u.sampler = sampler
+		sampler.add(u)
+	}
+	return u
+}
+
+// usageSampler samples the usage of the running processes of a container from
+// its process list. It is shared by the execs of the container so that the
+// process list is queried once per `execUsageSampleInterval` no matter how
+// many of its processes are running.
+type usageSampler struct {
+	c cow.Container
+
+	// m MUST be held to safely read/write `usages` and `done`.
+	m sync.Mutex
+	// usages are the usages being sampled by pid.
+	usages map[int]*execUsage
+	// done is closed to stop the sampling goroutine once there are no more
+	// usages to sample, or nil if it is not running.
+	done chan struct{}
+}
+
+// newUsageSampler creates a sampler for the processes of `c`. The sampler only
+// runs while it has processes to sample.
+func newUsageSampler(c cow.Container) *usageSampler {
+	return &usageSampler{
+		c:      c,
+		usages: make(map[int]*execUsage),
+	}
+}
+
+// add starts sampling the usage `u`, starting the sampling goroutine if it is
+// not running.
+func (s *usageSampler) add(u *execUsage) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.usages[u.pid] = u
+	if s.done == nil {
+		s.done = make(chan struct{})
+		go s.run(s.done)
+	}
+}
+
+// remove stops sampling the usage `u`, stopping the sampling goroutine if it
+// was the last one. Once it returns `u` is not updated by the sampler.
+func (s *usageSampler) remove(u *execUsage) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	if s.usages[u.pid] == u {
+		delete(s.usages, u.pid)
+	}
+	if len(s.usages) == 0 && s.done != nil {
+		close(s.done)
+		s.done = nil
+	}
+}
+
+// run samples the process list every `execUsageSampleInterval` until `done` is
+// closed.
+func (s *usageSampler) run(done <-chan struct{}) {
+	t := time.NewTicker(execUsageSampleInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-t.C:
+			s.sample(context.Background())
+		}
+	}
+}
+
+// sample updates the usages from the entries of their processes in the
+// process list of the container.
+func (s *usageSampler) sample(ctx context.Context) {
+	props, err := s.c.Properties(ctx, schema1.PropertyTypeProcessList)
+	if err != nil {
+		log.G(ctx).WithError(err).Debug("failed to sample process usage")
+		return
+	}
+	s.m.Lock()
+	defer s.m.Unlock()
+	for i := range props.ProcessList {
+		p := &props.ProcessList[i]
+		if u, ok := s.usages[int(p.ProcessId)]; ok {
+			u.m.Lock()
+			updateExecUsage(&u.usage, p)
+			u.m.Unlock()
+		}
+	}
+}
+
+// updateExecUsage updates `usage` from the process list entry `p` of its
+// process. The times only grow and the memory is the peak of all samples.
+func updateExecUsage(usage *runhcsevents.TaskExecUsage, p *schema1.ProcessListItem) {
+	if p.KernelTime100ns > usage.KernelTime_100Ns {
+		usage.KernelTime_100Ns = p.KernelTime100ns
+	}
+	if p.UserTime100ns > usage.UserTime_100Ns {
+		usage.UserTime_100Ns = p.UserTime100ns
+	}
+	if p.MemoryCommitBytes > usage.MemoryCommitPeakBytes {
+		usage.MemoryCommitPeakBytes = p.MemoryCommitBytes
+	}
+	if ws := p.MemoryWorkingSetPrivateBytes + p.MemoryWorkingSetSharedBytes; ws > usage.MemoryWorkingSetPeakBytes {
+		usage.MemoryWorkingSetPeakBytes = ws
+	}
+}
+
+// readProcess reads the final usage from the handle to the exited process.
+func (u *execUsage) readProcess(ctx context.Context) {
+	u.m.Lock()
+	defer u.m.Unlock()
+
+	var creation, exit, kernel, user windows.Filetime
+	if err := windows.GetProcessTimes(u.h, &creation, &exit, &kernel, &user); err != nil {
+		log.G(ctx).WithError(err).Warn("failed to get process times")
+	} else {
+		u.usage.KernelTime_100Ns = uint64(kernel.HighDateTime)<<32 | uint64(kernel.LowDateTime)
+		u.usage.UserTime_100Ns = uint64(user.HighDateTime)<<32 | uint64(user.LowDateTime)
+	}
+	if mem, err := process.GetProcessMemoryInfo(u.h); err != nil {
+		log.G(ctx).WithError(err).Warn("failed to get process memory info")
+	} else {
+		u.usage.MemoryCommitPeakBytes = uint64(mem.PeakPagefileUsage)
+		u.usage.MemoryWorkingSetPeakBytes = uint64(mem.PeakWorkingSetSize)
+	}
+	var io ioCounters
+	if err := getProcessIoCounters(u.h, &io); err != nil {
+		log.G(ctx).WithError(err).Warn("failed to get process io counters")
+	} else {
+		u.usage.ReadOperations = io.ReadOperationCount
+		u.usage.ReadBytes = io.ReadTransferCount
+		u.usage.WriteOperations = io.WriteOperationCount
+		u.usage.WriteBytes = io.WriteTransferCount
+	}
+}
+
+// readGuest reads the final usage of the exited process `p` in a LCOW
+// container from the rusage the guest reported for it, if any.
+func (u *execUsage) readGuest(p cow.Process) {
+	g, ok := p.(interface{ Usage() *gcs.ProcessUsage })
+	if !ok {
+		return
+	}
+	if r := g.Usage(); r != nil {
+		u.m.Lock()
+		updateGuestExecUsage(&u.usage, r)
+		u.m.Unlock()
+	}
+}
+
+// updateGuestExecUsage sets `usage` from the rusage `r` of its process.
+func updateGuestExecUsage(usage *runhcsevents.TaskExecUsage, r *gcs.ProcessUsage) {
+	usage.KernelTime_100Ns = r.SystemTimeInUs * 10
+	usage.UserTime_100Ns = r.UserTimeInUs * 10
+	usage.MemoryWorkingSetPeakBytes = r.MaxRssInKb * 1024
+	usage.ReadOperations = r.InBlocks
+	usage.WriteOperations = r.OutBlocks
+}
+
+// stop stops tracking the usage once the process `p` has exited and returns
+// its final usage. `p` may be nil if the process is not known.
+//
+// This call is idempotent and safe to call multiple times.
+func (u *execUsage) stop(ctx context.Context, p cow.Process) *runhcsevents.TaskExecUsage {
+	u.stopOnce.Do(func() {
+		if u.sampler != nil {
+			u.sampler.remove(u)
+		}
+		if u.h != 0 {
+			u.readProcess(ctx)
+			windows.CloseHandle(u.h)
+			u.h = 0
+		} else if u.sampler == nil && p != nil {
+			u.readGuest(p)
+		}
+	})
+	u.m.Lock()
+	defer u.m.Unlock()
+	usage := u.usage
+	return &usage
+}
+
+// publishExecUsage publishes `usage` as the `TaskExecUsage` of the exec `eid`
+// in the task `tid`. It MUST be published before the `TaskExit` of the exec.
+func publishExecUsage(ctx context.Context, events publisher, tid, eid string, usage *runhcsevents.TaskExecUsage) {
+	usage.ContainerID = tid
+	usage.ID = eid
+	events.publishEvent(
+		ctx,
+		runhcsevents.TaskExecUsageEventTopic,
+		usage)
+}
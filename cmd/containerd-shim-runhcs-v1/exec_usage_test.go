@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	runhcsevents "github.com/Microsoft/hcsshim/cmd/containerd-shim-runhcs-v1/events"
+	"github.com/Microsoft/hcsshim/internal/cow"
+	"github.com/Microsoft/hcsshim/internal/gcs"
+	"github.com/Microsoft/hcsshim/internal/schema1"
+)
+
+func Test_UpdateExecUsage_KeepsPeaks(t *testing.T) {
+	var usage runhcsevents.TaskExecUsage
+
+	updateExecUsage(&usage, &schema1.ProcessListItem{
+		KernelTime100ns:              10,
+		UserTime100ns:                20,
+		MemoryCommitBytes:            300,
+		MemoryWorkingSetPrivateBytes: 100,
+		MemoryWorkingSetSharedBytes:  50,
+	})
+	updateExecUsage(&usage, &schema1.ProcessListItem{
+		KernelTime100ns:              15,
+		UserTime100ns:                25,
+		MemoryCommitBytes:            200,
+		MemoryWorkingSetPrivateBytes: 20,
+	})
+
+	if usage.KernelTime_100Ns != 15 || usage.UserTime_100Ns != 25 {
+		t.Fatalf("expected the latest times, got: %+v", usage)
+	}
+	if usage.MemoryCommitPeakBytes != 300 || usage.MemoryWorkingSetPeakBytes != 150 {
+		t.Fatalf("expected the peak memory, got: %+v", usage)
+	}
+}
+
+func Test_PublishExecUsage(t *testing.T) {
+	events := newFakePublisher()
+
+	publishExecUsage(context.TODO(), events, t.Name(), "exec", &runhcsevents.TaskExecUsage{Pid: 10, UserTime_100Ns: 20})
+	if len(events.getEvents()) != 1 {
+		t.Fatalf("expected 1 event, got: %d", len(events.getEvents()))
+	}
+	e, ok := events.getEvents()[0].(*runhcsevents.TaskExecUsage)
+	if !ok || e.ContainerID != t.Name() || e.ID != "exec" || e.Pid != 10 || e.UserTime_100Ns != 20 {
+		t.Fatalf("unexpected event: %+v", events.getEvents()[0])
+	}
+}
+
+func Test_UsageSampler_SharedByExecs(t *testing.T) {
+	c := &testContainer{
+		id: t.Name(),
+		processList: []schema1.ProcessListItem{
+			{ProcessId: 1, UserTime100ns: 10},
+			{ProcessId: 2, UserTime100ns: 20},
+		},
+	}
+	s := newUsageSampler(c)
+	u1 := startExecUsage(context.TODO(), s, 1, false)
+	u2 := startExecUsage(context.TODO(), s, 2, false)
+
+	s.sample(context.TODO())
+	if c.properties != 1 {
+		t.Fatalf("expected the process list to be queried once, got: %d", c.properties)
+	}
+	usage1 := u1.stop(context.TODO(), nil)
+	if !usage1.Sampled || usage1.UserTime_100Ns != 10 {
+		t.Fatalf("unexpected usage: %+v", usage1)
+	}
+
+	c.processList[1].UserTime100ns = 30
+	s.sample(context.TODO())
+	if u1.stop(context.TODO(), nil).UserTime_100Ns != 10 {
+		t.Fatal("expected a stopped usage to no longer be sampled")
+	}
+	if usage2 := u2.stop(context.TODO(), nil); usage2.UserTime_100Ns != 30 {
+		t.Fatalf("unexpected usage: %+v", usage2)
+	}
+	if s.done != nil {
+		t.Fatal("expected the sampler to stop once there is no usage to sample")
+	}
+}
+
+func Test_StartExecUsage_NoSampler(t *testing.T) {
+	u := startExecUsage(context.TODO(), nil, 10, false)
+
+	usage := u.stop(context.TODO(), nil)
+	if usage.Pid != 10 || usage.Sampled {
+		t.Fatalf("expected only the pid to be set, got: %+v", usage)
+	}
+}
+
+// testGuestProcess is a process that reports the usage the guest returned
+// when it exited.
+type testGuestProcess struct {
+	cow.Process
+	usage *gcs.ProcessUsage
+}
+
+func (p *testGuestProcess) Usage() *gcs.ProcessUsage {
+	return p.usage
+}
+
+func Test_ExecUsage_Stop_Guest(t *testing.T) {
+	u := startExecUsage(context.TODO(), nil, 10, false)
+	p := &testGuestProcess{
+		usage: &gcs.ProcessUsage{
+			UserTimeInUs:   2,
+			SystemTimeInUs: 1,
+			MaxRssInKb:     4,
+			InBlocks:       5,
+			OutBlocks:      6,
+		},
+	}
+
+	usage := u.stop(context.TODO(), p)
+	if usage.Pid != 10 || usage.Sampled {
+		t.Fatalf("expected an unsampled usage for pid 10, got: %+v", usage)
+	}
+	if usage.KernelTime_100Ns != 10 || usage.UserTime_100Ns != 20 || usage.MemoryWorkingSetPeakBytes != 4096 {
+		t.Fatalf("expected the guest times and peak memory, got: %+v", usage)
+	}
+	if usage.ReadOperations != 5 || usage.WriteOperations != 6 {
+		t.Fatalf("expected the guest block operations, got: %+v", usage)
+	}
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	runhcsevents "github.com/Microsoft/hcsshim/cmd/containerd-shim-runhcs-v1/events"
+	"github.com/Microsoft/hcsshim/internal/cow"
+	"github.com/Microsoft/hcsshim/internal/uvm"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+// exitFacts is what the shim observed about the exit of an exec, from which
+// the reason of the exit is classified.
+type exitFacts struct {
+	// exitStatus is the exit status of the exec.
+	exitStatus uint32
+	// hostExited is set if the hosting utility VM has terminated, and hostErr
+	// is the reason if it terminated unexpectedly.
+	hostExited bool
+	hostErr    error
+	// stopTimedOut is set if the exec was forcibly stopped because it did not
+	// stop within the grace period of a kill.
+	stopTimedOut bool
+	// killSignal is the last signal delivered to the exec by a kill, or 0.
+	killSignal uint32
+	// terminated is set if the container exited while the exec was running
+	// without the shim stopping it, and terminateErr and terminateExitType are
+	// the exit error and the exit type the HCS reported for the container if
+	// any.
+	terminated        bool
+	terminateErr      error
+	terminateExitType string
+	// oomKilled is set if the container of an init exec reported that it ran
+	// out of memory: the guest reports it for a LCOW container and the job
+	// object of the container for a process isolated WCOW container. It is
+	// never set for a hypervisor isolated WCOW container, whose job object is
+	// in its utility VM.
+	oomKilled bool
+}
+
+// setHost records the exit of `host`, if it has terminated.
+func (f *exitFacts) setHost(host *uvm.UtilityVM) {
+	if host != nil && host.Exited() {
+		f.hostExited = true
+		f.hostErr = host.ExitError()
+	}
+}
+
+// reason returns the reason of the exit and a message describing it, or "" if
+// the exit has no known reason.
+func (f *exitFacts) reason() (string, string) {
+	if f.hostExited && f.hostErr != nil {
+		if _, ok := f.hostErr.(*uvm.GuestPanicError); ok {
+			return runhcsevents.ExitReasonGuestKernelPanic, f.hostErr.Error()
+		}
+		return runhcsevents.ExitReasonUtilityVMCrash, f.hostErr.Error()
+	}
+	if f.stopTimedOut {
+		return runhcsevents.ExitReasonStopTimeout, "exec did not stop within the grace period of the kill"
+	}
+	if f.terminated {
+		if f.terminateErr != nil {
+			return runhcsevents.ExitReasonHcsTerminate, f.terminateErr.Error()
+		}
+		if f.terminateExitType != "" {
+			return runhcsevents.ExitReasonHcsTerminate, fmt.Sprintf("container exited with exit type %s while the exec was running", f.terminateExitType)
+		}
+		return runhcsevents.ExitReasonHcsTerminate, "container exited while the exec was running"
+	}
+	if f.exitStatus == 0 {
+		return "", ""
+	}
+	if f.oomKilled {
+		return runhcsevents.ExitReasonOOMKilled, "container ran out of memory"
+	}
+	if f.killSignal != 0 {
+		return runhcsevents.ExitReasonKilled, fmt.Sprintf("killed by signal %d", f.killSignal)
+	}
+	return "", ""
+}
+
+// publishExitReason publishes the `TaskExitReason` of the exec `eid` in the
+// task `tid` classified from `f`, if the exit has a known reason. It MUST be
+// published before the `TaskExit` of the exec.
+func publishExitReason(ctx context.Context, events publisher, tid, eid string, f *exitFacts) {
+	reason, message := f.reason()
+	if reason == "" {
+		return
+	}
+	events.publishEvent(
+		ctx,
+		runhcsevents.TaskExitReasonEventTopic,
+		&runhcsevents.TaskExitReason{
+			ContainerID: tid,
+			ID:          eid,
+			Reason:      reason,
+			Message:     message,
+		})
+}
+
+// containerExitError returns the reason `c` exited, if it exited unexpectedly
+// and the container reports it.
+func containerExitError(c cow.Container) error {
+	if e, ok := c.(interface{ ExitError() error }); ok {
+		return e.ExitError()
+	}
+	return nil
+}
+
+// containerExitType returns the exit type the HCS reported for `c`, if it
+// exited and the container reports it.
+func containerExitType(c cow.Container) string {
+	if e, ok := c.(interface{ ExitType() string }); ok {
+		return e.ExitType()
+	}
+	return ""
+}
+
+// containerOOMKilled returns true if `c` reports that it ran out of memory.
+func containerOOMKilled(c cow.Container) bool {
+	if o, ok := c.(interface{ OOMKilled() bool }); ok {
+		return o.OOMKilled()
+	}
+	return false
+}
+
+// specMemoryLimitBytes returns the memory limit of the container of `s`, or 0
+// if it is not limited.
+func specMemoryLimitBytes(s *specs.Spec) uint64 {
+	if s.Windows != nil &&
+		s.Windows.Resources != nil &&
+		s.Windows.Resources.Memory != nil &&
+		s.Windows.Resources.Memory.Limit != nil {
+		return *s.Windows.Resources.Memory.Limit
+	}
+	if s.Linux != nil &&
+		s.Linux.Resources != nil &&
+		s.Linux.Resources.Memory != nil &&
+		s.Linux.Resources.Memory.Limit != nil &&
+		*s.Linux.Resources.Memory.Limit > 0 {
+		return uint64(*s.Linux.Resources.Memory.Limit)
+	}
+	return 0
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	runhcsevents "github.com/Microsoft/hcsshim/cmd/containerd-shim-runhcs-v1/events"
+	"github.com/Microsoft/hcsshim/internal/uvm"
+)
+
+func Test_ExitFacts_Reason(t *testing.T) {
+	tests := []struct {
+		name   string
+		facts  exitFacts
+		reason string
+	}{
+		{"Exited", exitFacts{exitStatus: 1}, ""},
+		{"ExitedZero", exitFacts{exitStatus: 0, killSignal: 15}, ""},
+		{"HostExitedCleanly", exitFacts{exitStatus: 1, hostExited: true}, ""},
+		{"GuestKernelPanic", exitFacts{exitStatus: 1, hostExited: true, hostErr: &uvm.GuestPanicError{}}, runhcsevents.ExitReasonGuestKernelPanic},
+		{"UtilityVMCrash", exitFacts{exitStatus: 1, hostExited: true, hostErr: errors.New("crash"), terminated: true}, runhcsevents.ExitReasonUtilityVMCrash},
+		{"StopTimeout", exitFacts{exitStatus: 1, stopTimedOut: true, killSignal: 9}, runhcsevents.ExitReasonStopTimeout},
+		{"HcsTerminate", exitFacts{exitStatus: 0, terminated: true}, runhcsevents.ExitReasonHcsTerminate},
+		{"HcsTerminateExitType", exitFacts{exitStatus: 1, terminated: true, terminateExitType: "UnexpectedExit"}, runhcsevents.ExitReasonHcsTerminate},
+		{"OOMKilled", exitFacts{exitStatus: 137, killSignal: 9, oomKilled: true}, runhcsevents.ExitReasonOOMKilled},
+		{"OOMKilledExitedCleanly", exitFacts{exitStatus: 0, oomKilled: true}, ""},
+		{"Killed", exitFacts{exitStatus: 137, killSignal: 9}, runhcsevents.ExitReasonKilled},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			reason, message := test.facts.reason()
+			if reason != test.reason {
+				t.Fatalf("expected reason: '%s', got: '%s'", test.reason, reason)
+			}
+			if reason != "" && message == "" {
+				t.Fatal("expected a message for the reason")
+			}
+		})
+	}
+}
+
+func Test_PublishExitReason(t *testing.T) {
+	events := newFakePublisher()
+
+	publishExitReason(context.TODO(), events, t.Name(), "", &exitFacts{exitStatus: 1})
+	if len(events.getEvents()) != 0 {
+		t.Fatalf("expected no event for an exit without a reason, got: %d", len(events.getEvents()))
+	}
+	publishExitReason(context.TODO(), events, t.Name(), "", &exitFacts{exitStatus: 1, killSignal: 9})
+	if len(events.getEvents()) != 1 {
+		t.Fatalf("expected 1 event, got: %d", len(events.getEvents()))
+	}
+	e, ok := events.getEvents()[0].(*runhcsevents.TaskExitReason)
+	if !ok || e.ContainerID != t.Name() || e.Reason != runhcsevents.ExitReasonKilled {
+		t.Fatalf("unexpected event: %+v", events.getEvents()[0])
+	}
+}
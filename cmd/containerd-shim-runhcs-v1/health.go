@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"runtime"
+	"sync/atomic"
+	"time"
+
+	"github.com/Microsoft/hcsshim/internal/log"
+	"github.com/Microsoft/hcsshim/internal/shimdiag"
+	"github.com/Microsoft/hcsshim/internal/uvm"
+	"github.com/containerd/containerd/errdefs"
+	"github.com/containerd/ttrpc"
+	"golang.org/x/sys/windows"
+)
+
+//sys getProcessHandleCount(process windows.Handle, count *uint32) (err error) = kernel32.GetProcessHandleCount
+
+// defaultHealthPingTimeout is how long the guest has to respond to the ping of
+// `DiagHealth` if the request does not set a timeout.
+const defaultHealthPingTimeout = 5 * time.Second
+
+// taskError is the last error returned for a task.
+type taskError struct {
+	method string
+	err    string
+	at     time.Time
+}
+
+// recordTaskError records `err`, if any, as the last error returned by
+// `method` for the task `tid`, and returns `err`. A `errdefs.ErrNotFound` is
+// not recorded as it is not an error of an existing task.
+func (s *service) recordTaskError(method, tid string, err error) error {
+	if err != nil && !errdefs.IsNotFound(err) {
+		s.lastErrors.Store(tid, &taskError{
+			method: method,
+			err:    err.Error(),
+			at:     time.Now(),
+		})
+	}
+	return err
+}
+
+// forgetTaskError forgets the last error of the task `tid` once it is deleted.
+func (s *service) forgetTaskError(tid string) {
+	s.lastErrors.Delete(tid)
+}
+
+// countRPCs returns an interceptor that counts the requests to the shim in
+// flight in `s.pendingRPCs` and calls `next`.
+func (s *service) countRPCs(next ttrpc.UnaryServerInterceptor) ttrpc.UnaryServerInterceptor {
+	return func(ctx context.Context, unmarshal ttrpc.Unmarshaler, info *ttrpc.UnaryServerInfo, method ttrpc.Method) (interface{}, error) {
+		atomic.AddInt32(&s.pendingRPCs, 1)
+		defer atomic.AddInt32(&s.pendingRPCs, -1)
+		return next(ctx, unmarshal, info, method)
+	}
+}
+
+// uvmHealth returns the health of `vm`, pinging its guest for at most
+// `pingTimeout`.
+func uvmHealth(ctx context.Context, vm *uvm.UtilityVM, pingTimeout time.Duration) *shimdiag.UtilityVMHealth {
+	h := &shimdiag.UtilityVMHealth{
+		ID:               vm.ID(),
+		State:            "running",
+		PendingGuestRpcs: uint32(vm.PendingGuestRPCs()),
+	}
+	if vm.Exited() {
+		h.State = "exited"
+		if err := vm.ExitError(); err != nil {
+			h.ExitError = err.Error()
+		}
+		return h
+	}
+	ctx, cancel := context.WithTimeout(ctx, pingTimeout)
+	defer cancel()
+	d, err := vm.PingGuest(ctx)
+	if err != nil {
+		h.PingError = err.Error()
+	} else {
+		h.GuestResponsive = true
+		h.PingMicroseconds = uint64(d / time.Microsecond)
+	}
+	return h
+}
+
+// taskHealth returns the health of `t`.
+func (s *service) taskHealth(t shimTask) *shimdiag.TaskHealth {
+	h := &shimdiag.TaskHealth{ID: t.ID()}
+	if ts := describeTask(t); ts != nil {
+		h.State = string(ts.Init.State)
+		h.Execs = uint32(len(ts.Execs))
+	} else if e, err := t.GetExec(""); err == nil {
+		h.State = string(e.State())
+	}
+	if raw, ok := s.lastErrors.Load(t.ID()); ok {
+		te := raw.(*taskError)
+		h.LastError = te.err
+		h.LastErrorMethod = te.method
+		h.LastErrorAt = te.at.Format(time.RFC3339Nano)
+	}
+	return h
+}
+
+func (s *service) diagHealthInternal(ctx context.Context, req *shimdiag.HealthRequest) (*shimdiag.HealthResponse, error) {
+	resp := &shimdiag.HealthResponse{
+		ID:          s.tid,
+		Goroutines:  int64(runtime.NumGoroutine()),
+		PendingRpcs: uint32(atomic.LoadInt32(&s.pendingRPCs)),
+	}
+	if h, err := windows.GetCurrentProcess(); err == nil {
+		var handles uint32
+		if err := getProcessHandleCount(h, &handles); err != nil {
+			log.G(ctx).WithError(err).Debug("failed to get process handle count")
+		} else {
+			resp.Handles = handles
+		}
+	}
+	if ep, ok := s.events.(*eventPublisher); ok {
+		resp.EventBacklog = uint32(ep.backlog())
+		resp.EventsPublished, resp.EventsRetried, resp.EventsDropped = ep.stats()
+	}
+
+	// The task of the shim itself is first.
+	var tasks []shimTask
+	switch t := s.taskOrPod.Load().(type) {
+	case *pod:
+		tasks = append(tasks, t.sandboxTask)
+		t.workloadTasks.Range(func(key, value interface{}) bool {
+			// A workload task that is still being created is `nil`.
+			if value != nil {
+				tasks = append(tasks, value.(shimTask))
+			}
+
+			// iterate all
+			return true
+		})
+	case shimTask:
+		tasks = append(tasks, t)
+	}
+	if len(tasks) == 0 {
+		return resp, nil
+	}
+
+	pingTimeout := defaultHealthPingTimeout
+	if req.PingTimeoutMs != 0 {
+		pingTimeout = time.Duration(req.PingTimeoutMs) * time.Millisecond
+	}
+	if vmh, err := tasks[0].HostHealth(ctx, pingTimeout); err == nil {
+		resp.UtilityVm = vmh
+	}
+	for _, t := range tasks {
+		resp.Tasks = append(resp.Tasks, s.taskHealth(t))
+	}
+	return resp, nil
+}
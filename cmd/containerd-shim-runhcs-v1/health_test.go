@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Microsoft/hcsshim/internal/shimdiag"
+	"github.com/containerd/containerd/errdefs"
+)
+
+func Test_diagHealthInternal_NotCreated(t *testing.T) {
+	s := service{
+		tid:       t.Name(),
+		isSandbox: true,
+	}
+
+	resp, err := s.diagHealthInternal(context.TODO(), &shimdiag.HealthRequest{})
+	if err != nil {
+		t.Fatalf("should not have failed, got: %v", err)
+	}
+	if resp.ID != t.Name() || resp.Goroutines == 0 || len(resp.Tasks) != 0 || resp.UtilityVm != nil {
+		t.Fatalf("unexpected health: %+v", resp)
+	}
+}
+
+func Test_diagHealthInternal_Pod_LastError(t *testing.T) {
+	p, _ := setupTestPodWithFakes(t)
+	wt := setupTestTaskInPod(t, p)
+	s := service{
+		tid:       t.Name(),
+		isSandbox: true,
+	}
+	s.taskOrPod.Store(p)
+
+	err := s.recordTaskError("Kill", wt.ID(), errdefs.ErrFailedPrecondition)
+	if err != errdefs.ErrFailedPrecondition {
+		t.Fatalf("expected the recorded error to be returned, got: %v", err)
+	}
+	s.recordTaskError("State", wt.ID(), errdefs.ErrNotFound)
+	s.recordTaskError("State", t.Name(), nil)
+
+	resp, err := s.diagHealthInternal(context.TODO(), &shimdiag.HealthRequest{})
+	if err != nil {
+		t.Fatalf("should not have failed, got: %v", err)
+	}
+	if len(resp.Tasks) != 2 || resp.Tasks[0].ID != t.Name() {
+		t.Fatalf("expected the sandbox task first and the workload task, got: %+v", resp.Tasks)
+	}
+	if resp.Tasks[0].LastError != "" {
+		t.Fatalf("expected no error for the sandbox task, got: %+v", resp.Tasks[0])
+	}
+	if wth := resp.Tasks[1]; wth.ID != wt.ID() || wth.LastErrorMethod != "Kill" || wth.LastError != errdefs.ErrFailedPrecondition.Error() || wth.LastErrorAt == "" {
+		t.Fatalf("expected the last error of the workload task, got: %+v", wth)
+	}
+	if resp.UtilityVm != nil {
+		t.Fatalf("expected no utility VM health, got: %+v", resp.UtilityVm)
+	}
+}
+
+func Test_forgetTaskError(t *testing.T) {
+	s, task, _ := setupTaskServiceWithFakes(t)
+
+	s.recordTaskError("Kill", task.ID(), errdefs.ErrFailedPrecondition)
+	s.forgetTaskError(task.ID())
+
+	if h := s.taskHealth(task); h.LastError != "" || h.LastErrorMethod != "" {
+		t.Fatalf("expected the last error to be forgotten, got: %+v", h)
+	}
+}
@@ -3,6 +3,11 @@ package main
 import (
 	"context"
 	"io"
+	"net/url"
+	"strings"
+
+	"github.com/containerd/containerd/errdefs"
+	"github.com/pkg/errors"
 )
 
 // upstreamIO is an interface describing the IO to connect to above the shim.
@@ -36,3 +41,68 @@ type upstreamIO interface {
 	// return `""`.
 	Terminal() bool
 }
+
+// newUpstreamIO creates connected upstream io for the exec `eid` in the task
+// `tid`. The scheme of `stdout` selects the io:
+//
+// - `file://` writes `stdout` and `stderr` to the file in the URI.
+//
+// - `binary://` starts the logging binary in the URI and connects `stdout` and
+// `stderr` to it.
+//
+// - Any other path is a named pipe.
+//
+// `stdin` is always a named pipe. It is the callers responsibility to validate
+// that `if terminal == true`, `stderr == ""`.
+func newUpstreamIO(ctx context.Context, tid, eid, stdin, stdout, stderr string, terminal bool) (upstreamIO, error) {
+	scheme := ioScheme(stdout)
+	if stderr != "" && ioScheme(stderr) != scheme {
+		return nil, errors.Wrapf(errdefs.ErrInvalidArgument, "stdout '%s' and stderr '%s' must use the same scheme", stdout, stderr)
+	}
+	switch scheme {
+	case "file":
+		return newFileIO(ctx, stdin, stdout, stderr, terminal)
+	case "binary":
+		return newBinaryIO(ctx, tid, eid, stdin, stdout, stderr, terminal)
+	}
+	return newNpipeIO(ctx, stdin, stdout, stderr, terminal)
+}
+
+// ioScheme returns the scheme of the stdio URI `p`, or "" if `p` is a named
+// pipe.
+func ioScheme(p string) string {
+	if !strings.Contains(p, "://") {
+		return ""
+	}
+	u, err := url.Parse(p)
+	if err != nil {
+		return ""
+	}
+	return u.Scheme
+}
+
+// ioURIPath returns the Windows path in the stdio URI `u`, such as
+// `C:\logs\ctr.log` for `file:///C:/logs/ctr.log`.
+func ioURIPath(u *url.URL) string {
+	p := u.Path
+	if u.Host != "" {
+		// `file://C:/logs/ctr.log` parses `C:` as the host.
+		p = u.Host + p
+	}
+	if len(p) > 2 && p[0] == '/' && p[2] == ':' {
+		p = p[1:]
+	}
+	return strings.Replace(p, "/", "\\", -1)
+}
+
+// newDisconnectedIO returns upstream io that reports the paths of the io of an
+// exec but relays nothing, for an exec that had already exited when the shim
+// that connected its io exited.
+func newDisconnectedIO(stdin, stdout, stderr string, terminal bool) upstreamIO {
+	return &npipeio{
+		stdin:    stdin,
+		stdout:   stdout,
+		stderr:   stderr,
+		terminal: terminal,
+	}
+}
@@ -0,0 +1,333 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"os/exec"
+	"sort"
+	"sync"
+	"time"
+
+	winio "github.com/Microsoft/go-winio"
+	"github.com/Microsoft/hcsshim/internal/log"
+	"github.com/Microsoft/hcsshim/internal/oc"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"go.opencensus.io/trace"
+)
+
+const (
+	// binaryPipeFmt is the format of the named pipes that the logging binary
+	// connects to. It is formatted with the task ID, the exec ID and the name
+	// of the pipe.
+	binaryPipeFmt = `\\.\pipe\binary-%s-%s-%s`
+	// binaryStartTimeout is how long the logging binary has to connect to
+	// its pipes and close the wait pipe.
+	binaryStartTimeout = 10 * time.Second
+	// binaryExitTimeout is how long the logging binary has to exit after its
+	// pipes are closed before it is killed.
+	binaryExitTimeout = 5 * time.Second
+)
+
+// newBinaryIO creates upstream io that starts the logging binary in the
+// `binary://` URI `stdout` and connects `stdout` and `stderr` to it, as the
+// runc shim does.
+//
+// The binary is started with the query parameters of the URI as arguments and
+// with the environment variables:
+//
+// - `CONTAINER_ID` and `CONTAINER_NAMESPACE` identifying the task.
+//
+// - `CONTAINER_STDOUT` and `CONTAINER_STDERR` naming the pipes to read.
+//
+// - `CONTAINER_WAIT` naming a pipe to close once it is reading.
+//
+// It is the callers responsibility to validate that `if terminal == true`,
+// `stderr == ""`.
+func newBinaryIO(ctx context.Context, tid, eid, stdin, stdout, stderr string, terminal bool) (_ upstreamIO, err error) {
+	ctx, span := trace.StartSpan(ctx, "newBinaryIO")
+	defer span.End()
+	defer func() { oc.SetSpanStatus(span, err) }()
+	span.AddAttributes(
+		trace.StringAttribute("tid", tid),
+		trace.StringAttribute("eid", eid),
+		trace.StringAttribute("stdin", stdin),
+		trace.StringAttribute("stdout", stdout),
+		trace.StringAttribute("stderr", stderr),
+		trace.BoolAttribute("terminal", terminal))
+
+	u, err := url.Parse(stdout)
+	if err != nil {
+		return nil, err
+	}
+	path, args, err := binaryCommand(u)
+	if err != nil {
+		return nil, err
+	}
+
+	bio := &binaryio{
+		stdin:    stdin,
+		stdout:   stdout,
+		stderr:   stderr,
+		terminal: terminal,
+	}
+	defer func() {
+		if err != nil {
+			bio.Close(ctx)
+		}
+	}()
+	if stdin != "" {
+		c, err := winio.DialPipe(stdin, nil)
+		if err != nil {
+			return nil, err
+		}
+		bio.sin = c
+	}
+
+	pipePath := func(name string) string { return fmt.Sprintf(binaryPipeFmt, tid, eid, name) }
+	env := []string{
+		"CONTAINER_ID=" + tid,
+		"CONTAINER_NAMESPACE=" + namespaceFlag,
+	}
+	if bio.sout, err = listenBinaryPipe(pipePath("stdout")); err != nil {
+		return nil, err
+	}
+	env = append(env, "CONTAINER_STDOUT="+pipePath("stdout"))
+	if stderr != "" {
+		if bio.serr, err = listenBinaryPipe(pipePath("stderr")); err != nil {
+			return nil, err
+		}
+		env = append(env, "CONTAINER_STDERR="+pipePath("stderr"))
+	}
+	wait, err := listenBinaryPipe(pipePath("wait"))
+	if err != nil {
+		return nil, err
+	}
+	defer wait.Close()
+	env = append(env, "CONTAINER_WAIT="+pipePath("wait"))
+
+	bio.cmd = exec.Command(path, args...)
+	bio.cmd.Env = append(os.Environ(), env...)
+	if err := bio.cmd.Start(); err != nil {
+		return nil, errors.Wrapf(err, "failed to start logging binary '%s'", path)
+	}
+	bio.exited = make(chan struct{})
+	go func() {
+		bio.cmd.Wait()
+		close(bio.exited)
+	}()
+
+	// The binary closes the wait pipe once it is reading its pipes.
+	waitErr := make(chan error, 1)
+	go func() {
+		_, err := wait.Read(make([]byte, 1))
+		if err == io.EOF {
+			err = nil
+		}
+		waitErr <- err
+	}()
+	select {
+	case err := <-waitErr:
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to wait for logging binary '%s'", path)
+		}
+	case <-bio.exited:
+		return nil, errors.Errorf("logging binary '%s' exited before it was ready", path)
+	case <-time.After(binaryStartTimeout):
+		return nil, errors.Errorf("timed out waiting for logging binary '%s' to start", path)
+	}
+	log.G(ctx).WithFields(logrus.Fields{
+		"binary": path,
+		"pid":    bio.cmd.Process.Pid,
+	}).Debug("started logging binary")
+	return bio, nil
+}
+
+// binaryCommand returns the path and the arguments of the logging binary in
+// the `binary://` URI `u`. Each query parameter is an argument, followed by its
+// value if any.
+func binaryCommand(u *url.URL) (string, []string, error) {
+	path := ioURIPath(u)
+	if path == "" {
+		return "", nil, errors.Errorf("no logging binary in '%s'", u)
+	}
+	q := u.Query()
+	keys := make([]string, 0, len(q))
+	for k := range q {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var args []string
+	for _, k := range keys {
+		args = append(args, k)
+		if v := q.Get(k); v != "" {
+			args = append(args, v)
+		}
+	}
+	return path, args, nil
+}
+
+// binaryPipe is a named pipe served to the logging binary. The connection of
+// the binary is accepted on the first read or write.
+type binaryPipe struct {
+	l      net.Listener
+	accept sync.Once
+	c      net.Conn
+	err    error
+}
+
+func listenBinaryPipe(path string) (*binaryPipe, error) {
+	l, err := winio.ListenPipe(path, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &binaryPipe{l: l}, nil
+}
+
+func (p *binaryPipe) conn() (net.Conn, error) {
+	p.accept.Do(func() {
+		p.c, p.err = p.l.Accept()
+		p.l.Close()
+	})
+	return p.c, p.err
+}
+
+func (p *binaryPipe) Read(b []byte) (int, error) {
+	c, err := p.conn()
+	if err != nil {
+		return 0, err
+	}
+	return c.Read(b)
+}
+
+func (p *binaryPipe) Write(b []byte) (int, error) {
+	c, err := p.conn()
+	if err != nil {
+		return 0, err
+	}
+	return c.Write(b)
+}
+
+// Close closes the pipe. A pending accept fails once the listener is closed.
+func (p *binaryPipe) Close() error {
+	p.l.Close()
+	p.accept.Do(func() { p.err = errors.New("pipe closed") })
+	if p.c != nil {
+		return p.c.Close()
+	}
+	return nil
+}
+
+var _ = (upstreamIO)(&binaryio{})
+
+type binaryio struct {
+	// stdin, stdout, stderr are the original paths used to open the io.
+	//
+	// They MUST be treated as readonly in the lifetime of the binary io.
+	stdin, stdout, stderr string
+	// terminal is the original setting passed in on open.
+	//
+	// This MUST be treated as readonly in the lifetime of the binary io.
+	terminal bool
+
+	// sin is the upstream `stdin` connection.
+	//
+	// `sin` MUST be treated as readonly in the lifetime of the binary io after
+	// the return from `newBinaryIO`.
+	sin       io.ReadCloser
+	sinCloser sync.Once
+
+	// sout and serr are the pipes the logging binary reads.
+	//
+	// `sout` and `serr` MUST be treated as readonly in the lifetime of the
+	// binary io after the return from `newBinaryIO`.
+	sout, serr   *binaryPipe
+	outErrCloser sync.Once
+
+	// cmd is the logging binary and `exited` is closed once it exits.
+	//
+	// They MUST be treated as readonly in the lifetime of the binary io after
+	// the return from `newBinaryIO`.
+	cmd    *exec.Cmd
+	exited chan struct{}
+}
+
+// Close closes the pipes of the logging binary so that it drains them and
+// exits. It is killed if it does not exit in time.
+func (bio *binaryio) Close(ctx context.Context) {
+	ctx, span := trace.StartSpan(ctx, "binaryio::Close")
+	defer span.End()
+
+	bio.sinCloser.Do(func() {
+		if bio.sin != nil {
+			bio.sin.Close()
+		}
+	})
+	bio.outErrCloser.Do(func() {
+		if bio.sout != nil {
+			bio.sout.Close()
+		}
+		if bio.serr != nil {
+			bio.serr.Close()
+		}
+		if bio.exited == nil {
+			return
+		}
+		select {
+		case <-bio.exited:
+		case <-time.After(binaryExitTimeout):
+			log.G(ctx).WithField("pid", bio.cmd.Process.Pid).Warn("killing logging binary that did not exit")
+			bio.cmd.Process.Kill()
+			<-bio.exited
+		}
+	})
+}
+
+func (bio *binaryio) CloseStdin(ctx context.Context) {
+	ctx, span := trace.StartSpan(ctx, "binaryio::CloseStdin")
+	defer span.End()
+
+	bio.sinCloser.Do(func() {
+		if bio.sin != nil {
+			bio.sin.Close()
+		}
+	})
+}
+
+func (bio *binaryio) Stdin() io.Reader {
+	return bio.sin
+}
+
+func (bio *binaryio) StdinPath() string {
+	return bio.stdin
+}
+
+func (bio *binaryio) Stdout() io.Writer {
+	if bio.sout == nil {
+		return nil
+	}
+	return bio.sout
+}
+
+func (bio *binaryio) StdoutPath() string {
+	return bio.stdout
+}
+
+func (bio *binaryio) Stderr() io.Writer {
+	if bio.serr == nil {
+		return nil
+	}
+	return bio.serr
+}
+
+func (bio *binaryio) StderrPath() string {
+	return bio.stderr
+}
+
+func (bio *binaryio) Terminal() bool {
+	return bio.terminal
+}
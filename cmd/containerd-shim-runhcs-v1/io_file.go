@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+
+	winio "github.com/Microsoft/go-winio"
+	"github.com/Microsoft/hcsshim/internal/oc"
+	"go.opencensus.io/trace"
+)
+
+// newFileIO creates upstream io that appends `stdout` and `stderr` to the
+// files in their `file://` URIs. The same file is opened once for both. It is
+// the callers responsibility to validate that `if terminal == true`,
+// `stderr == ""`.
+func newFileIO(ctx context.Context, stdin, stdout, stderr string, terminal bool) (_ upstreamIO, err error) {
+	ctx, span := trace.StartSpan(ctx, "newFileIO")
+	defer span.End()
+	defer func() { oc.SetSpanStatus(span, err) }()
+	span.AddAttributes(
+		trace.StringAttribute("stdin", stdin),
+		trace.StringAttribute("stdout", stdout),
+		trace.StringAttribute("stderr", stderr),
+		trace.BoolAttribute("terminal", terminal))
+
+	fio := &fileio{
+		stdin:    stdin,
+		stdout:   stdout,
+		stderr:   stderr,
+		terminal: terminal,
+	}
+	defer func() {
+		if err != nil {
+			fio.Close(ctx)
+		}
+	}()
+	if stdin != "" {
+		c, err := winio.DialPipe(stdin, nil)
+		if err != nil {
+			return nil, err
+		}
+		fio.sin = c
+	}
+	if stdout != "" {
+		fio.sout, err = openLogFile(stdout)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if stderr != "" {
+		if stderr == stdout {
+			fio.serr = fio.sout
+		} else {
+			fio.serr, err = openLogFile(stderr)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return fio, nil
+}
+
+// openLogFile opens the file in the `file://` URI `uri` for appending,
+// creating it and its directory if needed.
+func openLogFile(uri string) (*os.File, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, err
+	}
+	p := ioURIPath(u)
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return nil, err
+	}
+	return os.OpenFile(p, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+}
+
+var _ = (upstreamIO)(&fileio{})
+
+type fileio struct {
+	// stdin, stdout, stderr are the original paths used to open the io.
+	//
+	// They MUST be treated as readonly in the lifetime of the file io.
+	stdin, stdout, stderr string
+	// terminal is the original setting passed in on open.
+	//
+	// This MUST be treated as readonly in the lifetime of the file io.
+	terminal bool
+
+	// sin is the upstream `stdin` connection.
+	//
+	// `sin` MUST be treated as readonly in the lifetime of the file io after
+	// the return from `newFileIO`.
+	sin       io.ReadCloser
+	sinCloser sync.Once
+
+	// sout and serr are the `stdout` and `stderr` files. They are the same
+	// file if `stdout == stderr`.
+	//
+	// `sout` and `serr` MUST be treated as readonly in the lifetime of the file
+	// io after the return from `newFileIO`.
+	sout, serr   *os.File
+	outErrCloser sync.Once
+}
+
+func (fio *fileio) Close(ctx context.Context) {
+	ctx, span := trace.StartSpan(ctx, "fileio::Close")
+	defer span.End()
+
+	fio.sinCloser.Do(func() {
+		if fio.sin != nil {
+			fio.sin.Close()
+		}
+	})
+	fio.outErrCloser.Do(func() {
+		if fio.sout != nil {
+			fio.sout.Close()
+		}
+		if fio.serr != nil && fio.serr != fio.sout {
+			fio.serr.Close()
+		}
+	})
+}
+
+func (fio *fileio) CloseStdin(ctx context.Context) {
+	ctx, span := trace.StartSpan(ctx, "fileio::CloseStdin")
+	defer span.End()
+
+	fio.sinCloser.Do(func() {
+		if fio.sin != nil {
+			fio.sin.Close()
+		}
+	})
+}
+
+func (fio *fileio) Stdin() io.Reader {
+	return fio.sin
+}
+
+func (fio *fileio) StdinPath() string {
+	return fio.stdin
+}
+
+func (fio *fileio) Stdout() io.Writer {
+	if fio.sout == nil {
+		return nil
+	}
+	return fio.sout
+}
+
+func (fio *fileio) StdoutPath() string {
+	return fio.stdout
+}
+
+func (fio *fileio) Stderr() io.Writer {
+	if fio.serr == nil {
+		return nil
+	}
+	return fio.serr
+}
+
+func (fio *fileio) StderrPath() string {
+	return fio.stderr
+}
+
+func (fio *fileio) Terminal() bool {
+	return fio.terminal
+}
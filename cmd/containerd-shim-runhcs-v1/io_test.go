@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/containerd/containerd/errdefs"
+)
+
+func Test_ioScheme(t *testing.T) {
+	for p, scheme := range map[string]string{
+		`\\.\pipe\stdout`:                  "",
+		"":                                 "",
+		"file:///C:/logs/ctr.log":          "file",
+		"binary:///C:/bin/logger.exe?id=1": "binary",
+	} {
+		if s := ioScheme(p); s != scheme {
+			t.Fatalf("expected scheme %q for %q, got %q", scheme, p, s)
+		}
+	}
+}
+
+func Test_ioURIPath(t *testing.T) {
+	for uri, p := range map[string]string{
+		"file:///C:/logs/ctr.log": `C:\logs\ctr.log`,
+		"file://C:/logs/ctr.log":  `C:\logs\ctr.log`,
+		"file:////server/share/a": `\\server\share\a`,
+	} {
+		u, err := url.Parse(uri)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual := ioURIPath(u); actual != p {
+			t.Fatalf("expected path %q for %q, got %q", p, uri, actual)
+		}
+	}
+}
+
+func Test_binaryCommand(t *testing.T) {
+	u, err := url.Parse("binary:///C:/bin/logger.exe?verbose&id=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path, args, err := binaryCommand(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != `C:\bin\logger.exe` {
+		t.Fatalf("expected path %q, got %q", `C:\bin\logger.exe`, path)
+	}
+	if expected := []string{"id", "1", "verbose"}; !reflect.DeepEqual(args, expected) {
+		t.Fatalf("expected args %q, got %q", expected, args)
+	}
+}
+
+func Test_newUpstreamIO_MixedSchemes_Error(t *testing.T) {
+	_, err := newUpstreamIO(context.TODO(), t.Name(), t.Name(), "", "file:///C:/logs/ctr.log", `\\.\pipe\stderr`, false)
+
+	verifyExpectedError(t, nil, err, errdefs.ErrInvalidArgument)
+}
+
+func Test_newFileIO_SharedFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fileio")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	p := filepath.Join(dir, "logs", "ctr.log")
+	uri := "file:///" + filepath.ToSlash(p)
+
+	fio, err := newUpstreamIO(context.TODO(), t.Name(), t.Name(), "", uri, uri, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fio.Stdin() != nil || fio.StdoutPath() != uri || fio.StderrPath() != uri {
+		t.Fatalf("unexpected io %+v", fio)
+	}
+	fio.Stdout().Write([]byte("out\n"))
+	fio.Stderr().Write([]byte("err\n"))
+	fio.Close(context.TODO())
+
+	b, err := ioutil.ReadFile(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := string(b); !strings.Contains(s, "out\n") || !strings.Contains(s, "err\n") {
+		t.Fatalf("unexpected log file contents %q", s)
+	}
+}
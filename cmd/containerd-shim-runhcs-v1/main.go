@@ -150,6 +150,7 @@ func main() {
 		startCommand,
 		deleteCommand,
 		serveCommand,
+		serveUVMPoolCommand,
 	}
 	app.Before = func(context *cli.Context) error {
 		if namespaceFlag = context.GlobalString("namespace"); namespaceFlag == "" {
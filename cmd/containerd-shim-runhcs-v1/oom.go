@@ -0,0 +1,165 @@
+package main
+
+import (
+	"sync"
+	"sync/atomic"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+//sys ntOpenJobObject(job *windows.Handle, access uint32, oa *objectAttributes) (status uint32) = ntdll.NtOpenJobObject
+//sys rtlNtStatusToDosError(status uint32) (winerr error) = ntdll.RtlNtStatusToDosErrorNoTeb
+//sys getQueuedCompletionStatus(port windows.Handle, code *uint32, key *uintptr, overlapped **windows.Overlapped, timeout uint32) (err error) = kernel32.GetQueuedCompletionStatus
+
+const (
+	jobObjectQuery         = 0x0004
+	jobObjectSetAttributes = 0x0010
+
+	jobObjectMsgProcessMemoryLimit = 9
+	jobObjectMsgJobMemoryLimit     = 10
+
+	// jobMemoryWatcherJobKey and jobMemoryWatcherStopKey are the completion
+	// keys of the notifications of the job object and of the stop request of
+	// a `jobMemoryWatcher`.
+	jobMemoryWatcherJobKey  = 1
+	jobMemoryWatcherStopKey = 2
+)
+
+type objectAttributes struct {
+	Length             uintptr
+	RootDirectory      uintptr
+	ObjectName         *unicodeString
+	Attributes         uintptr
+	SecurityDescriptor uintptr
+	SecurityQoS        uintptr
+}
+
+type unicodeString struct {
+	Length        uint16
+	MaximumLength uint16
+	Buffer        *uint16
+}
+
+// jobObjectAssociateCompletionPort is the JOBOBJECT_ASSOCIATE_COMPLETION_PORT
+// struct from Windows.
+type jobObjectAssociateCompletionPort struct {
+	CompletionKey  uintptr
+	CompletionPort windows.Handle
+}
+
+// siloJobObjectName returns the name of the job object of the server silo of
+// the process isolated container `id`.
+func siloJobObjectName(id string) string {
+	return `\Container_` + id
+}
+
+// jobMemoryWatcher watches a job object for the notification it sends when
+// the memory limit of the job, or of one of its processes, is exceeded. This
+// is how the platform reports that a process isolated WCOW container ran out
+// of memory.
+type jobMemoryWatcher struct {
+	job  windows.Handle
+	port windows.Handle
+	// done is closed once the watcher stops reading the notifications.
+	done      chan struct{}
+	closeOnce sync.Once
+	// limitExceeded is set to 1 once the job object reports that a memory
+	// limit was exceeded.
+	//
+	// NOTE: All accesses to this MUST be done atomically.
+	limitExceeded uint32
+}
+
+// watchJobMemoryLimit opens the job object `name` and starts watching it for
+// memory limit notifications. It fails if the job object already reports its
+// notifications to another completion port.
+func watchJobMemoryLimit(name string) (_ *jobMemoryWatcher, err error) {
+	name16, err := windows.UTF16FromString(name)
+	if err != nil {
+		return nil, err
+	}
+	us := unicodeString{
+		Length:        uint16((len(name16) - 1) * 2),
+		MaximumLength: uint16(len(name16) * 2),
+		Buffer:        &name16[0],
+	}
+	oa := objectAttributes{ObjectName: &us}
+	oa.Length = unsafe.Sizeof(oa)
+	var job windows.Handle
+	if status := ntOpenJobObject(&job, jobObjectQuery|jobObjectSetAttributes, &oa); status != 0 {
+		return nil, rtlNtStatusToDosError(status)
+	}
+	defer func() {
+		if err != nil {
+			windows.CloseHandle(job)
+		}
+	}()
+	port, err := windows.CreateIoCompletionPort(windows.InvalidHandle, 0, 0, 1)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			windows.CloseHandle(port)
+		}
+	}()
+	info := jobObjectAssociateCompletionPort{
+		CompletionKey:  jobMemoryWatcherJobKey,
+		CompletionPort: port,
+	}
+	if _, err := windows.SetInformationJobObject(job, windows.JobObjectAssociateCompletionPortInformation, uintptr(unsafe.Pointer(&info)), uint32(unsafe.Sizeof(info))); err != nil {
+		return nil, err
+	}
+	w := &jobMemoryWatcher{
+		job:  job,
+		port: port,
+		done: make(chan struct{}),
+	}
+	go w.run()
+	return w, nil
+}
+
+// run reads the notifications of the job object until the watcher is closed.
+func (w *jobMemoryWatcher) run() {
+	defer close(w.done)
+	for {
+		var (
+			code       uint32
+			key        uintptr
+			overlapped *windows.Overlapped
+		)
+		if err := getQueuedCompletionStatus(w.port, &code, &key, &overlapped, windows.INFINITE); err != nil {
+			return
+		}
+		if key == jobMemoryWatcherStopKey {
+			return
+		}
+		if code == jobObjectMsgJobMemoryLimit || code == jobObjectMsgProcessMemoryLimit {
+			atomic.StoreUint32(&w.limitExceeded, 1)
+		}
+	}
+}
+
+// OOMKilled returns true if the job object reported that a memory limit was
+// exceeded.
+func (w *jobMemoryWatcher) OOMKilled() bool {
+	return atomic.LoadUint32(&w.limitExceeded) == 1
+}
+
+// close stops the watcher once it has read the notifications sent before the
+// call, so that `OOMKilled` accounts for them.
+//
+// This call is idempotent and safe to call multiple times.
+func (w *jobMemoryWatcher) close() {
+	w.closeOnce.Do(func() {
+		// The notifications are read in order, so the stop request is read
+		// after all the notifications already sent.
+		if err := windows.PostQueuedCompletionStatus(w.port, 0, jobMemoryWatcherStopKey, nil); err == nil {
+			<-w.done
+		}
+		windows.CloseHandle(w.port)
+		<-w.done
+		windows.CloseHandle(w.job)
+	})
+}
@@ -101,7 +101,12 @@ type Options struct {
 	SandboxIsolation Options_SandboxIsolation `protobuf:"varint,6,opt,name=sandbox_isolation,json=sandboxIsolation,proto3,enum=containerd.runhcs.v1.Options_SandboxIsolation" json:"sandbox_isolation,omitempty"`
 	// boot_files_root_path is the path to the directory containing the LCOW
 	// kernel and root FS files.
-	BootFilesRootPath    string   `protobuf:"bytes,7,opt,name=boot_files_root_path,json=bootFilesRootPath,proto3" json:"boot_files_root_path,omitempty"`
+	BootFilesRootPath string `protobuf:"bytes,7,opt,name=boot_files_root_path,json=bootFilesRootPath,proto3" json:"boot_files_root_path,omitempty"`
+	// pod_share_roots are the host folders that the shares of a hypervisor
+	// isolated pod set by the io.microsoft.pod.shares annotation may be in,
+	// in addition to the bundle of the pod sandbox. A share anywhere else is
+	// rejected.
+	PodShareRoots        []string `protobuf:"bytes,8,rep,name=pod_share_roots,json=podShareRoots,proto3" json:"pod_share_roots,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -151,9 +156,18 @@ type ProcessDetails struct {
 	ProcessID                    uint32    `protobuf:"varint,7,opt,name=process_id,json=processId,proto3" json:"process_id,omitempty"`
 	UserTime_100Ns               uint64    `protobuf:"varint,8,opt,name=user_time_100_ns,json=userTime100Ns,proto3" json:"user_time_100_ns,omitempty"`
 	ExecID                       string    `protobuf:"bytes,9,opt,name=exec_id,json=execId,proto3" json:"exec_id,omitempty"`
-	XXX_NoUnkeyedLiteral         struct{}  `json:"-"`
-	XXX_unrecognized             []byte    `json:"-"`
-	XXX_sizecache                int32     `json:"-"`
+	// exited is set for the process of an exec that exited but is not deleted
+	// yet. Its times and the fields below are its final usage.
+	Exited                    bool     `protobuf:"varint,10,opt,name=exited,proto3" json:"exited,omitempty"`
+	MemoryCommitPeakBytes     uint64   `protobuf:"varint,11,opt,name=memory_commit_peak_bytes,json=memoryCommitPeakBytes,proto3" json:"memory_commit_peak_bytes,omitempty"`
+	MemoryWorkingSetPeakBytes uint64   `protobuf:"varint,12,opt,name=memory_working_set_peak_bytes,json=memoryWorkingSetPeakBytes,proto3" json:"memory_working_set_peak_bytes,omitempty"`
+	ReadOperations            uint64   `protobuf:"varint,13,opt,name=read_operations,json=readOperations,proto3" json:"read_operations,omitempty"`
+	ReadBytes                 uint64   `protobuf:"varint,14,opt,name=read_bytes,json=readBytes,proto3" json:"read_bytes,omitempty"`
+	WriteOperations           uint64   `protobuf:"varint,15,opt,name=write_operations,json=writeOperations,proto3" json:"write_operations,omitempty"`
+	WriteBytes                uint64   `protobuf:"varint,16,opt,name=write_bytes,json=writeBytes,proto3" json:"write_bytes,omitempty"`
+	XXX_NoUnkeyedLiteral      struct{} `json:"-"`
+	XXX_unrecognized          []byte   `json:"-"`
+	XXX_sizecache             int32    `json:"-"`
 }
 
 func (m *ProcessDetails) Reset()      { *m = ProcessDetails{} }
@@ -188,11 +202,54 @@ func (m *ProcessDetails) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_ProcessDetails proto.InternalMessageInfo
 
+// CheckpointOptions are the set of customizations that can be passed at
+// Checkpoint time.
+type CheckpointOptions struct {
+	// exit terminates the UVM once it is saved instead of resuming it.
+	Exit                 bool     `protobuf:"varint,1,opt,name=exit,proto3" json:"exit,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CheckpointOptions) Reset()      { *m = CheckpointOptions{} }
+func (*CheckpointOptions) ProtoMessage() {}
+func (*CheckpointOptions) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b643df6839c75082, []int{2}
+}
+func (m *CheckpointOptions) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *CheckpointOptions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_CheckpointOptions.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *CheckpointOptions) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CheckpointOptions.Merge(m, src)
+}
+func (m *CheckpointOptions) XXX_Size() int {
+	return m.Size()
+}
+func (m *CheckpointOptions) XXX_DiscardUnknown() {
+	xxx_messageInfo_CheckpointOptions.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CheckpointOptions proto.InternalMessageInfo
+
 func init() {
 	proto.RegisterEnum("containerd.runhcs.v1.Options_DebugType", Options_DebugType_name, Options_DebugType_value)
 	proto.RegisterEnum("containerd.runhcs.v1.Options_SandboxIsolation", Options_SandboxIsolation_name, Options_SandboxIsolation_value)
 	proto.RegisterType((*Options)(nil), "containerd.runhcs.v1.Options")
 	proto.RegisterType((*ProcessDetails)(nil), "containerd.runhcs.v1.ProcessDetails")
+	proto.RegisterType((*CheckpointOptions)(nil), "containerd.runhcs.v1.CheckpointOptions")
 }
 
 func init() {
@@ -200,51 +257,61 @@ func init() {
 }
 
 var fileDescriptor_b643df6839c75082 = []byte{
-	// 704 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x54, 0x4d, 0x6f, 0xda, 0x48,
-	0x18, 0xc6, 0xe1, 0xd3, 0x6f, 0x96, 0xc4, 0x99, 0xe5, 0x80, 0xb2, 0xbb, 0x80, 0xc8, 0x21, 0x89,
-	0x76, 0x63, 0x43, 0xf6, 0xd8, 0x53, 0x09, 0xa0, 0xba, 0x6a, 0x83, 0x65, 0xa2, 0xa6, 0x1f, 0x07,
-	0xcb, 0xd8, 0x83, 0xb1, 0x82, 0x3d, 0xd6, 0xcc, 0x90, 0x86, 0x5b, 0x7f, 0x42, 0x7f, 0x55, 0x95,
-	0x63, 0x8f, 0x95, 0x2a, 0xa5, 0x0d, 0xbf, 0xa4, 0x9a, 0xb1, 0x49, 0xd4, 0x28, 0xea, 0xa5, 0x27,
-	0xc6, 0xcf, 0xf3, 0xbc, 0xcf, 0xfb, 0x29, 0x60, 0x14, 0x84, 0x7c, 0xb6, 0x98, 0xe8, 0x1e, 0x89,
-	0x8c, 0x97, 0xa1, 0x47, 0x09, 0x23, 0x53, 0x6e, 0xcc, 0x3c, 0xc6, 0x66, 0x61, 0x64, 0x78, 0x91,
-	0x6f, 0x78, 0x24, 0xe6, 0x6e, 0x18, 0x63, 0xea, 0x1f, 0x09, 0xec, 0x88, 0x2e, 0xe2, 0x99, 0xc7,
-	0x8e, 0x2e, 0xbb, 0x06, 0x49, 0x78, 0x48, 0x62, 0x66, 0xa4, 0x88, 0x9e, 0x50, 0xc2, 0x09, 0xaa,
-	0xdd, 0xeb, 0xf5, 0x8c, 0xb8, 0xec, 0xee, 0xd6, 0x02, 0x12, 0x10, 0x29, 0x30, 0xc4, 0x2b, 0xd5,
-	0xee, 0x36, 0x03, 0x42, 0x82, 0x39, 0x36, 0xe4, 0xd7, 0x64, 0x31, 0x35, 0x78, 0x18, 0x61, 0xc6,
-	0xdd, 0x28, 0x49, 0x05, 0xed, 0x4f, 0x79, 0x28, 0x8f, 0xd2, 0x2c, 0xa8, 0x06, 0x45, 0x1f, 0x4f,
-	0x16, 0x41, 0x5d, 0x69, 0x29, 0x07, 0x15, 0x3b, 0xfd, 0x40, 0x43, 0x00, 0xf9, 0x70, 0xf8, 0x32,
-	0xc1, 0xf5, 0x8d, 0x96, 0x72, 0xb0, 0x75, 0xbc, 0xaf, 0x3f, 0x56, 0x83, 0x9e, 0x19, 0xe9, 0x7d,
-	0xa1, 0x3f, 0x5b, 0x26, 0xd8, 0x56, 0xfd, 0xf5, 0x13, 0xed, 0x41, 0x95, 0xe2, 0x20, 0x64, 0x9c,
-	0x2e, 0x1d, 0x4a, 0x08, 0xaf, 0xe7, 0x5b, 0xca, 0x81, 0x6a, 0xff, 0xb1, 0x06, 0x6d, 0x42, 0xb8,
-	0x10, 0x31, 0x37, 0xf6, 0x27, 0xe4, 0xca, 0x09, 0x23, 0x37, 0xc0, 0xf5, 0x42, 0x2a, 0xca, 0x40,
-	0x53, 0x60, 0xe8, 0x10, 0xb4, 0xb5, 0x28, 0x99, 0xbb, 0x7c, 0x4a, 0x68, 0x54, 0x2f, 0x4a, 0xdd,
-	0x76, 0x86, 0x5b, 0x19, 0x8c, 0xde, 0xc1, 0xce, 0x9d, 0x1f, 0x23, 0x73, 0x57, 0xd4, 0x57, 0x2f,
-	0xc9, 0x1e, 0xf4, 0x5f, 0xf7, 0x30, 0xce, 0x32, 0xae, 0xa3, 0xec, 0x75, 0xce, 0x3b, 0x04, 0x19,
-	0x50, 0x9b, 0x10, 0xc2, 0x9d, 0x69, 0x38, 0xc7, 0x4c, 0xf6, 0xe4, 0x24, 0x2e, 0x9f, 0xd5, 0xcb,
-	0xb2, 0x96, 0x1d, 0xc1, 0x0d, 0x05, 0x25, 0x3a, 0xb3, 0x5c, 0x3e, 0x6b, 0x1f, 0x82, 0x7a, 0x37,
-	0x1a, 0xa4, 0x42, 0xf1, 0xd4, 0x32, 0xad, 0x81, 0x96, 0x43, 0x15, 0x28, 0x0c, 0xcd, 0x17, 0x03,
-	0x4d, 0x41, 0x65, 0xc8, 0x0f, 0xce, 0xce, 0xb5, 0x8d, 0xb6, 0x01, 0xda, 0xc3, 0x0a, 0xd0, 0x26,
-	0x94, 0x2d, 0x7b, 0x74, 0x32, 0x18, 0x8f, 0xb5, 0x1c, 0xda, 0x02, 0x78, 0xf6, 0xc6, 0x1a, 0xd8,
-	0xaf, 0xcc, 0xf1, 0xc8, 0xd6, 0x94, 0xf6, 0xd7, 0x3c, 0x6c, 0x59, 0x94, 0x78, 0x98, 0xb1, 0x3e,
-	0xe6, 0x6e, 0x38, 0x67, 0xe8, 0x1f, 0x00, 0x39, 0x44, 0x27, 0x76, 0x23, 0x2c, 0x97, 0xaa, 0xda,
-	0xaa, 0x44, 0x4e, 0xdd, 0x08, 0xa3, 0x13, 0x00, 0x8f, 0x62, 0x97, 0x63, 0xdf, 0x71, 0xb9, 0x5c,
-	0xec, 0xe6, 0xf1, 0xae, 0x9e, 0x1e, 0x8c, 0xbe, 0x3e, 0x18, 0xfd, 0x6c, 0x7d, 0x30, 0xbd, 0xca,
-	0xf5, 0x4d, 0x33, 0xf7, 0xf1, 0x5b, 0x53, 0xb1, 0xd5, 0x2c, 0xee, 0x29, 0x47, 0xff, 0x02, 0xba,
-	0xc0, 0x34, 0xc6, 0x73, 0x47, 0x5c, 0x96, 0xd3, 0xed, 0x74, 0x9c, 0x98, 0xc9, 0xd5, 0x16, 0xec,
-	0xed, 0x94, 0x11, 0x0e, 0xdd, 0x4e, 0xe7, 0x94, 0x21, 0x1d, 0xfe, 0x8c, 0x70, 0x44, 0xe8, 0xd2,
-	0xf1, 0x48, 0x14, 0x85, 0xdc, 0x99, 0x2c, 0x39, 0x66, 0x72, 0xc7, 0x05, 0x7b, 0x27, 0xa5, 0x4e,
-	0x24, 0xd3, 0x13, 0x04, 0x1a, 0x42, 0x2b, 0xd3, 0xbf, 0x27, 0xf4, 0x22, 0x8c, 0x03, 0x87, 0x61,
-	0xee, 0x24, 0x34, 0xbc, 0x74, 0x39, 0xce, 0x82, 0x8b, 0x32, 0xf8, 0xef, 0x54, 0x77, 0x9e, 0xca,
-	0xc6, 0x98, 0x5b, 0xa9, 0x28, 0xf5, 0xe9, 0x43, 0xf3, 0x11, 0x1f, 0x36, 0x73, 0x29, 0xf6, 0x33,
-	0x9b, 0x92, 0xb4, 0xf9, 0xeb, 0xa1, 0xcd, 0x58, 0x6a, 0x52, 0x97, 0xff, 0x00, 0x92, 0x74, 0xc0,
-	0x4e, 0xe8, 0xcb, 0x25, 0x57, 0x7b, 0xd5, 0xd5, 0x4d, 0x53, 0xcd, 0xc6, 0x6e, 0xf6, 0x6d, 0x35,
-	0x13, 0x98, 0x3e, 0xda, 0x07, 0x6d, 0xc1, 0x30, 0xfd, 0x69, 0x2c, 0x15, 0x99, 0xa4, 0x2a, 0xf0,
-	0xfb, 0xa1, 0xec, 0x41, 0x19, 0x5f, 0x61, 0x4f, 0x78, 0xaa, 0x62, 0x45, 0x3d, 0x58, 0xdd, 0x34,
-	0x4b, 0x83, 0x2b, 0xec, 0x99, 0x7d, 0xbb, 0x24, 0x28, 0xd3, 0xef, 0xf9, 0xd7, 0xb7, 0x8d, 0xdc,
-	0x97, 0xdb, 0x46, 0xee, 0xc3, 0xaa, 0xa1, 0x5c, 0xaf, 0x1a, 0xca, 0xe7, 0x55, 0x43, 0xf9, 0xbe,
-	0x6a, 0x28, 0x6f, 0x9f, 0xff, 0xfe, 0xdf, 0xcb, 0x93, 0xec, 0xf7, 0x75, 0x6e, 0x52, 0x92, 0x7b,
-	0xff, 0xff, 0x47, 0x00, 0x00, 0x00, 0xff, 0xff, 0xa3, 0x9a, 0x54, 0x17, 0xb5, 0x04, 0x00, 0x00,
+	// 862 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x54, 0x4d, 0x73, 0xe3, 0x34,
+	0x18, 0x8e, 0xb7, 0x69, 0x12, 0xbf, 0xdd, 0x24, 0xae, 0x28, 0x8c, 0x29, 0x6c, 0x92, 0xc9, 0xce,
+	0xd0, 0x76, 0xa0, 0x76, 0xbb, 0x1c, 0x38, 0x70, 0x81, 0x34, 0xe9, 0x10, 0x06, 0x1a, 0x8f, 0xd3,
+	0x61, 0xf9, 0x38, 0x78, 0x14, 0x5b, 0x75, 0x3c, 0x89, 0x2d, 0x8f, 0xa4, 0x74, 0x9b, 0x1b, 0xc3,
+	0x2f, 0xe0, 0x67, 0xf5, 0xc8, 0x91, 0x53, 0x61, 0xf3, 0x03, 0xf8, 0x0d, 0x8c, 0x24, 0xa7, 0x5f,
+	0x74, 0xb8, 0xec, 0x29, 0xf2, 0xf3, 0x3e, 0xef, 0x23, 0xbd, 0x8f, 0x9e, 0x08, 0x46, 0x71, 0x22,
+	0xa6, 0x8b, 0x89, 0x13, 0xd2, 0xd4, 0xfd, 0x3e, 0x09, 0x19, 0xe5, 0xf4, 0x42, 0xb8, 0xd3, 0x90,
+	0xf3, 0x69, 0x92, 0xba, 0x61, 0x1a, 0xb9, 0x21, 0xcd, 0x04, 0x4e, 0x32, 0xc2, 0xa2, 0x43, 0x89,
+	0x1d, 0xb2, 0x45, 0x36, 0x0d, 0xf9, 0xe1, 0xe5, 0xb1, 0x4b, 0x73, 0x91, 0xd0, 0x8c, 0xbb, 0x1a,
+	0x71, 0x72, 0x46, 0x05, 0x45, 0x3b, 0x77, 0x7c, 0xa7, 0x28, 0x5c, 0x1e, 0xef, 0xee, 0xc4, 0x34,
+	0xa6, 0x8a, 0xe0, 0xca, 0x95, 0xe6, 0xee, 0xb6, 0x63, 0x4a, 0xe3, 0x39, 0x71, 0xd5, 0xd7, 0x64,
+	0x71, 0xe1, 0x8a, 0x24, 0x25, 0x5c, 0xe0, 0x34, 0xd7, 0x84, 0xee, 0x3f, 0x1b, 0x50, 0x1d, 0xe9,
+	0x5d, 0xd0, 0x0e, 0x6c, 0x46, 0x64, 0xb2, 0x88, 0x6d, 0xa3, 0x63, 0xec, 0xd7, 0x7c, 0xfd, 0x81,
+	0x4e, 0x01, 0xd4, 0x22, 0x10, 0xcb, 0x9c, 0xd8, 0xcf, 0x3a, 0xc6, 0x7e, 0xe3, 0xd5, 0x9e, 0xf3,
+	0xd4, 0x19, 0x9c, 0x42, 0xc8, 0xe9, 0x4b, 0xfe, 0xf9, 0x32, 0x27, 0xbe, 0x19, 0xad, 0x97, 0xe8,
+	0x25, 0xd4, 0x19, 0x89, 0x13, 0x2e, 0xd8, 0x32, 0x60, 0x94, 0x0a, 0x7b, 0xa3, 0x63, 0xec, 0x9b,
+	0xfe, 0xf3, 0x35, 0xe8, 0x53, 0x2a, 0x24, 0x89, 0xe3, 0x2c, 0x9a, 0xd0, 0xab, 0x20, 0x49, 0x71,
+	0x4c, 0xec, 0xb2, 0x26, 0x15, 0xe0, 0x50, 0x62, 0xe8, 0x00, 0xac, 0x35, 0x29, 0x9f, 0x63, 0x71,
+	0x41, 0x59, 0x6a, 0x6f, 0x2a, 0x5e, 0xb3, 0xc0, 0xbd, 0x02, 0x46, 0xbf, 0xc0, 0xf6, 0xad, 0x1e,
+	0xa7, 0x73, 0x2c, 0xcf, 0x67, 0x57, 0xd4, 0x0c, 0xce, 0xff, 0xcf, 0x30, 0x2e, 0x76, 0x5c, 0x77,
+	0xf9, 0x16, 0x7f, 0x84, 0x20, 0x17, 0x76, 0x26, 0x94, 0x8a, 0xe0, 0x22, 0x99, 0x13, 0xae, 0x66,
+	0x0a, 0x72, 0x2c, 0xa6, 0x76, 0x55, 0x9d, 0x65, 0x5b, 0xd6, 0x4e, 0x65, 0x49, 0x4e, 0xe6, 0x61,
+	0x31, 0x45, 0x9f, 0x40, 0x33, 0xa7, 0x51, 0xc0, 0xa7, 0x98, 0x11, 0xc5, 0xe7, 0x76, 0xad, 0xb3,
+	0xb1, 0x6f, 0xfa, 0xf5, 0x9c, 0x46, 0x63, 0x89, 0x4a, 0x2a, 0xef, 0x1e, 0x80, 0x79, 0x6b, 0x21,
+	0x32, 0x61, 0xf3, 0xcc, 0x1b, 0x7a, 0x03, 0xab, 0x84, 0x6a, 0x50, 0x3e, 0x1d, 0x7e, 0x37, 0xb0,
+	0x0c, 0x54, 0x85, 0x8d, 0xc1, 0xf9, 0x6b, 0xeb, 0x59, 0xd7, 0x05, 0xeb, 0xf1, 0x49, 0xd1, 0x16,
+	0x54, 0x3d, 0x7f, 0x74, 0x32, 0x18, 0x8f, 0xad, 0x12, 0x6a, 0x00, 0x7c, 0xf3, 0x93, 0x37, 0xf0,
+	0x7f, 0x18, 0x8e, 0x47, 0xbe, 0x65, 0x74, 0x7f, 0xab, 0x40, 0xc3, 0x63, 0x34, 0x24, 0x9c, 0xf7,
+	0x89, 0xc0, 0xc9, 0x9c, 0xa3, 0x17, 0x00, 0xca, 0xec, 0x20, 0xc3, 0x29, 0x51, 0x97, 0x6f, 0xfa,
+	0xa6, 0x42, 0xce, 0x70, 0x4a, 0xd0, 0x09, 0x40, 0xc8, 0x08, 0x16, 0x24, 0x0a, 0xb0, 0x50, 0x01,
+	0xd8, 0x7a, 0xb5, 0xeb, 0xe8, 0x60, 0x39, 0xeb, 0x60, 0x39, 0xe7, 0xeb, 0x60, 0xf5, 0x6a, 0xd7,
+	0x37, 0xed, 0xd2, 0xef, 0x7f, 0xb5, 0x0d, 0xdf, 0x2c, 0xfa, 0xbe, 0x16, 0xe8, 0x53, 0x40, 0x33,
+	0xc2, 0x32, 0x32, 0x0f, 0x64, 0x02, 0x83, 0xe3, 0xa3, 0xa3, 0x20, 0xe3, 0x2a, 0x02, 0x65, 0xbf,
+	0xa9, 0x2b, 0x52, 0xe1, 0xf8, 0xe8, 0xe8, 0x8c, 0x23, 0x07, 0xde, 0x4b, 0x49, 0x4a, 0xd9, 0x32,
+	0x08, 0x69, 0x9a, 0x26, 0x22, 0x98, 0x2c, 0x05, 0xe1, 0x2a, 0x0b, 0x65, 0x7f, 0x5b, 0x97, 0x4e,
+	0x54, 0xa5, 0x27, 0x0b, 0xe8, 0x14, 0x3a, 0x05, 0xff, 0x0d, 0x65, 0xb3, 0x24, 0x8b, 0x03, 0x4e,
+	0x44, 0x90, 0xb3, 0xe4, 0x12, 0x0b, 0x52, 0x34, 0x6f, 0xaa, 0xe6, 0x8f, 0x35, 0xef, 0xb5, 0xa6,
+	0x8d, 0x89, 0xf0, 0x34, 0x49, 0xeb, 0xf4, 0xa1, 0xfd, 0x84, 0x8e, 0xba, 0xae, 0xa8, 0x90, 0xa9,
+	0x28, 0x99, 0x8f, 0x1e, 0xcb, 0xa8, 0xcb, 0x8b, 0xb4, 0xca, 0x67, 0x00, 0xb9, 0x36, 0x38, 0x48,
+	0x22, 0x15, 0x86, 0x7a, 0xaf, 0xbe, 0xba, 0x69, 0x9b, 0x85, 0xed, 0xc3, 0xbe, 0x6f, 0x16, 0x84,
+	0x61, 0x84, 0xf6, 0xc0, 0x5a, 0x70, 0xc2, 0x1e, 0xd8, 0x52, 0x53, 0x9b, 0xd4, 0x25, 0x7e, 0x67,
+	0xca, 0x4b, 0xa8, 0x92, 0x2b, 0x12, 0x4a, 0x4d, 0x53, 0x5e, 0x51, 0x0f, 0x56, 0x37, 0xed, 0xca,
+	0xe0, 0x8a, 0x84, 0xc3, 0xbe, 0x5f, 0x91, 0xa5, 0x61, 0x84, 0x3e, 0x80, 0x0a, 0xb9, 0x4a, 0x04,
+	0x89, 0x6c, 0x50, 0xff, 0xe1, 0xe2, 0x0b, 0x7d, 0x01, 0xf6, 0x43, 0x47, 0x73, 0x82, 0x67, 0xc5,
+	0x48, 0x5b, 0x6a, 0xb7, 0xf7, 0xef, 0xdb, 0xea, 0x11, 0x3c, 0xd3, 0xc3, 0x7c, 0x05, 0x2f, 0x9e,
+	0xb2, 0xf6, 0xae, 0xfb, 0xb9, 0xea, 0xfe, 0xf0, 0x3f, 0xbe, 0xde, 0x2a, 0xec, 0x41, 0x93, 0x11,
+	0x1c, 0x05, 0x34, 0x27, 0x4c, 0xe5, 0x93, 0xdb, 0x75, 0xd5, 0xd3, 0x90, 0xf0, 0xe8, 0x16, 0x95,
+	0x31, 0x54, 0x44, 0xad, 0xdb, 0x50, 0x1c, 0x53, 0x22, 0x5a, 0xe7, 0x00, 0xac, 0x37, 0x2c, 0x11,
+	0xe4, 0xbe, 0x50, 0x53, 0xe7, 0x47, 0xe1, 0xf7, 0x94, 0xda, 0xb0, 0xa5, 0xa9, 0x5a, 0xca, 0x52,
+	0x2c, 0x50, 0x90, 0xd2, 0xea, 0xee, 0xc1, 0xf6, 0xc9, 0x94, 0x84, 0xb3, 0x9c, 0x26, 0x99, 0x58,
+	0x3f, 0x7f, 0x08, 0xca, 0xd2, 0xad, 0xe2, 0xf5, 0x53, 0xeb, 0x5e, 0x74, 0xfd, 0xb6, 0x55, 0xfa,
+	0xf3, 0x6d, 0xab, 0xf4, 0xeb, 0xaa, 0x65, 0x5c, 0xaf, 0x5a, 0xc6, 0x1f, 0xab, 0x96, 0xf1, 0xf7,
+	0xaa, 0x65, 0xfc, 0xfc, 0xed, 0xbb, 0x3f, 0xeb, 0x5f, 0x16, 0xbf, 0x3f, 0x96, 0x26, 0x15, 0xf5,
+	0x3f, 0xfa, 0xfc, 0xdf, 0x01, 0x00, 0x94, 0x82, 0xb3, 0x63, 0x2d, 0x06, 0x00, 0x00,
 }
 
 func (m *Options) Marshal() (dAtA []byte, err error) {
@@ -306,6 +373,21 @@ func (m *Options) MarshalTo(dAtA []byte) (int, error) {
 		i = encodeVarintRunhcs(dAtA, i, uint64(len(m.BootFilesRootPath)))
 		i += copy(dAtA[i:], m.BootFilesRootPath)
 	}
+	if len(m.PodShareRoots) > 0 {
+		for _, s := range m.PodShareRoots {
+			dAtA[i] = 0x42
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
 	if m.XXX_unrecognized != nil {
 		i += copy(dAtA[i:], m.XXX_unrecognized)
 	}
@@ -377,6 +459,79 @@ func (m *ProcessDetails) MarshalTo(dAtA []byte) (int, error) {
 		i = encodeVarintRunhcs(dAtA, i, uint64(len(m.ExecID)))
 		i += copy(dAtA[i:], m.ExecID)
 	}
+	if m.Exited {
+		dAtA[i] = 0x50
+		i++
+		if m.Exited {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if m.MemoryCommitPeakBytes != 0 {
+		dAtA[i] = 0x58
+		i++
+		i = encodeVarintRunhcs(dAtA, i, uint64(m.MemoryCommitPeakBytes))
+	}
+	if m.MemoryWorkingSetPeakBytes != 0 {
+		dAtA[i] = 0x60
+		i++
+		i = encodeVarintRunhcs(dAtA, i, uint64(m.MemoryWorkingSetPeakBytes))
+	}
+	if m.ReadOperations != 0 {
+		dAtA[i] = 0x68
+		i++
+		i = encodeVarintRunhcs(dAtA, i, uint64(m.ReadOperations))
+	}
+	if m.ReadBytes != 0 {
+		dAtA[i] = 0x70
+		i++
+		i = encodeVarintRunhcs(dAtA, i, uint64(m.ReadBytes))
+	}
+	if m.WriteOperations != 0 {
+		dAtA[i] = 0x78
+		i++
+		i = encodeVarintRunhcs(dAtA, i, uint64(m.WriteOperations))
+	}
+	if m.WriteBytes != 0 {
+		dAtA[i] = 0x80
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintRunhcs(dAtA, i, uint64(m.WriteBytes))
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func (m *CheckpointOptions) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *CheckpointOptions) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Exit {
+		dAtA[i] = 0x8
+		i++
+		if m.Exit {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
 	if m.XXX_unrecognized != nil {
 		i += copy(dAtA[i:], m.XXX_unrecognized)
 	}
@@ -423,6 +578,12 @@ func (m *Options) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovRunhcs(uint64(l))
 	}
+	if len(m.PodShareRoots) > 0 {
+		for _, s := range m.PodShareRoots {
+			l = len(s)
+			n += 1 + l + sovRunhcs(uint64(l))
+		}
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -463,6 +624,42 @@ func (m *ProcessDetails) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovRunhcs(uint64(l))
 	}
+	if m.Exited {
+		n += 2
+	}
+	if m.MemoryCommitPeakBytes != 0 {
+		n += 1 + sovRunhcs(uint64(m.MemoryCommitPeakBytes))
+	}
+	if m.MemoryWorkingSetPeakBytes != 0 {
+		n += 1 + sovRunhcs(uint64(m.MemoryWorkingSetPeakBytes))
+	}
+	if m.ReadOperations != 0 {
+		n += 1 + sovRunhcs(uint64(m.ReadOperations))
+	}
+	if m.ReadBytes != 0 {
+		n += 1 + sovRunhcs(uint64(m.ReadBytes))
+	}
+	if m.WriteOperations != 0 {
+		n += 1 + sovRunhcs(uint64(m.WriteOperations))
+	}
+	if m.WriteBytes != 0 {
+		n += 2 + sovRunhcs(uint64(m.WriteBytes))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *CheckpointOptions) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Exit {
+		n += 2
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -494,6 +691,7 @@ func (this *Options) String() string {
 		`SandboxPlatform:` + fmt.Sprintf("%v", this.SandboxPlatform) + `,`,
 		`SandboxIsolation:` + fmt.Sprintf("%v", this.SandboxIsolation) + `,`,
 		`BootFilesRootPath:` + fmt.Sprintf("%v", this.BootFilesRootPath) + `,`,
+		`PodShareRoots:` + fmt.Sprintf("%v", this.PodShareRoots) + `,`,
 		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
 		`}`,
 	}, "")
@@ -513,6 +711,24 @@ func (this *ProcessDetails) String() string {
 		`ProcessID:` + fmt.Sprintf("%v", this.ProcessID) + `,`,
 		`UserTime_100Ns:` + fmt.Sprintf("%v", this.UserTime_100Ns) + `,`,
 		`ExecID:` + fmt.Sprintf("%v", this.ExecID) + `,`,
+		`Exited:` + fmt.Sprintf("%v", this.Exited) + `,`,
+		`MemoryCommitPeakBytes:` + fmt.Sprintf("%v", this.MemoryCommitPeakBytes) + `,`,
+		`MemoryWorkingSetPeakBytes:` + fmt.Sprintf("%v", this.MemoryWorkingSetPeakBytes) + `,`,
+		`ReadOperations:` + fmt.Sprintf("%v", this.ReadOperations) + `,`,
+		`ReadBytes:` + fmt.Sprintf("%v", this.ReadBytes) + `,`,
+		`WriteOperations:` + fmt.Sprintf("%v", this.WriteOperations) + `,`,
+		`WriteBytes:` + fmt.Sprintf("%v", this.WriteBytes) + `,`,
+		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *CheckpointOptions) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&CheckpointOptions{`,
+		`Exit:` + fmt.Sprintf("%v", this.Exit) + `,`,
 		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
 		`}`,
 	}, "")
@@ -741,6 +957,38 @@ func (m *Options) Unmarshal(dAtA []byte) error {
 			}
 			m.BootFilesRootPath = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PodShareRoots", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRunhcs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRunhcs
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRunhcs
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.PodShareRoots = append(m.PodShareRoots, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipRunhcs(dAtA[iNdEx:])
@@ -1006,6 +1254,214 @@ func (m *ProcessDetails) Unmarshal(dAtA []byte) error {
 			}
 			m.ExecID = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 10:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Exited", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRunhcs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Exited = bool(v != 0)
+		case 11:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MemoryCommitPeakBytes", wireType)
+			}
+			m.MemoryCommitPeakBytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRunhcs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MemoryCommitPeakBytes |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 12:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MemoryWorkingSetPeakBytes", wireType)
+			}
+			m.MemoryWorkingSetPeakBytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRunhcs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MemoryWorkingSetPeakBytes |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 13:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ReadOperations", wireType)
+			}
+			m.ReadOperations = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRunhcs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ReadOperations |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 14:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ReadBytes", wireType)
+			}
+			m.ReadBytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRunhcs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ReadBytes |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 15:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field WriteOperations", wireType)
+			}
+			m.WriteOperations = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRunhcs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.WriteOperations |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 16:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field WriteBytes", wireType)
+			}
+			m.WriteBytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRunhcs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.WriteBytes |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRunhcs(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthRunhcs
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthRunhcs
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *CheckpointOptions) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRunhcs
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: CheckpointOptions: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: CheckpointOptions: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Exit", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRunhcs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Exit = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipRunhcs(dAtA[iNdEx:])
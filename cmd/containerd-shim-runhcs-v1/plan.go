@@ -0,0 +1,69 @@
+package main
+
+import (
+	gcontext "context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/Microsoft/hcsshim/internal/hcsoci"
+	"github.com/Microsoft/hcsshim/internal/oci"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+// printTaskPlan writes the resources, utility VM paths and HCS documents that
+// would be used to create the task in `bundle` to `w` as JSON, without creating
+// the task or its utility VM. For a hypervisor isolated task the plan is made
+// against an empty utility VM built from the spec's annotations, and the
+// utility VM options are included.
+func printTaskPlan(bundle string, w io.Writer) error {
+	f, err := os.Open(filepath.Join(bundle, "config.json"))
+	if err != nil {
+		return err
+	}
+	var s specs.Spec
+	err = json.NewDecoder(f).Decode(&s)
+	f.Close()
+	if err != nil {
+		return err
+	}
+
+	ctx := gcontext.Background()
+	owner := filepath.Base(os.Args[0])
+	opts := &hcsoci.CreateOptions{
+		ID:    idFlag,
+		Owner: owner,
+		Spec:  &s,
+	}
+	if s.Windows != nil && s.Windows.Network != nil {
+		opts.NetworkNamespace = s.Windows.Network.NetworkNamespace
+	}
+
+	var uvmOpts interface{}
+	if oci.IsIsolated(&s) {
+		uvmOpts, err = oci.SpecToUVMCreateOpts(ctx, &s, fmt.Sprintf("%s@vm", idFlag), owner)
+		if err != nil {
+			return err
+		}
+		opts.DryRunHost, err = hcsoci.NewDryRunHost(uvmOpts)
+		if err != nil {
+			return err
+		}
+	}
+
+	plan, err := hcsoci.PlanContainer(ctx, opts)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(struct {
+		UVM  interface{} `json:",omitempty"`
+		Task *hcsoci.Plan
+	}{
+		UVM:  uvmOpts,
+		Task: plan,
+	})
+}
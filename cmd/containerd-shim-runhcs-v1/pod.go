@@ -7,10 +7,12 @@ import (
 	"path/filepath"
 	"sync"
 
+	"github.com/Microsoft/hcsshim/cmd/containerd-shim-runhcs-v1/options"
 	"github.com/Microsoft/hcsshim/internal/oc"
 	"go.opencensus.io/trace"
 
 	"github.com/Microsoft/hcsshim/internal/hcsoci"
+	"github.com/Microsoft/hcsshim/internal/log"
 	"github.com/Microsoft/hcsshim/internal/oci"
 	"github.com/Microsoft/hcsshim/internal/uvm"
 	"github.com/Microsoft/hcsshim/osversion"
@@ -46,6 +48,12 @@ type shimPod interface {
 	// If `tid==ID() && eid == "" && all == true` this pod will send `signal` to
 	// all tasks in the pod and lastly send `signal` to the sandbox itself.
 	//
+	// Unless `signal` is SIGKILL, `signal` is sent to the sidecar tasks once
+	// the init exec of every other workload task has exited, and to the
+	// sandbox once the sidecars have exited. The call returns once the other
+	// workload tasks are signaled and the sidecars and the sandbox are signaled
+	// in the background. A sidecar task killed on its own is signaled at once.
+	//
 	// If `all == true && eid != ""` this pod MUST return
 	// `errdefs.ErrFailedPrecondition`.
 	//
@@ -55,7 +63,7 @@ type shimPod interface {
 	KillTask(ctx context.Context, tid, eid string, signal uint32, all bool) error
 }
 
-func createPod(ctx context.Context, events publisher, req *task.CreateTaskRequest, s *specs.Spec) (_ shimPod, err error) {
+func createPod(ctx context.Context, events publisher, req *task.CreateTaskRequest, s *specs.Spec, shimOpts *options.Options) (_ shimPod, err error) {
 	ctx, span := trace.StartSpan(ctx, "createPod")
 	defer span.End()
 	defer func() {
@@ -91,6 +99,10 @@ func createPod(ctx context.Context, events publisher, req *task.CreateTaskReques
 	owner := filepath.Base(os.Args[0])
 	isWCOW := oci.IsWCOW(s)
 
+	if req.Checkpoint != "" && !(isWCOW && oci.IsIsolated(s)) {
+		return nil, errors.Wrapf(errdefs.ErrNotImplemented, "restoring POD '%s' from a checkpoint is only supported for a hypervisor isolated WCOW POD", req.ID)
+	}
+
 	var parent *uvm.UtilityVM
 	if oci.IsIsolated(s) {
 		// Create the UVM parent
@@ -101,12 +113,32 @@ func createPod(ctx context.Context, events publisher, req *task.CreateTaskReques
 		switch opts.(type) {
 		case *uvm.OptionsLCOW:
 			lopts := (opts).(*uvm.OptionsLCOW)
-			parent, err = uvm.CreateLCOW(ctx, lopts)
-			if err != nil {
-				return nil, err
+			lopts.LogCaptureDir = filepath.Join(req.Bundle, guestLogDir)
+			// Kernel panics are reported by default with the captured logs.
+			lopts.EnableCrashReporting = oci.ParseAnnotationsCrashReporting(ctx, s, true)
+			if config := oci.SpecToUVMPoolConfig(ctx, s); config.Size > 0 {
+				// The pool returns an already started UVM.
+				parent, err = getPooledUVM(ctx, config, lopts, req.Bundle, shimOpts)
+				if err != nil {
+					log.G(ctx).WithError(err).Warn("failed to get utility VM from pool")
+				}
+			}
+			if parent == nil {
+				parent, err = uvm.CreateLCOW(ctx, lopts)
+				if err != nil {
+					return nil, err
+				}
+				err = parent.Start(ctx)
+				if err != nil {
+					parent.Close()
+					return nil, err
+				}
 			}
 		case *uvm.OptionsWCOW:
 			wopts := (opts).(*uvm.OptionsWCOW)
+			if oci.SpecToUVMPoolConfig(ctx, s).Size > 0 {
+				log.G(ctx).Warn("utility VM pooling is not supported for WCOW pods, ignoring")
+			}
 
 			// In order for the UVM sandbox.vhdx not to collide with the actual
 			// nested Argon sandbox.vhdx we append the \vm folder to the last
@@ -123,15 +155,21 @@ func createPod(ctx context.Context, events publisher, req *task.CreateTaskReques
 			layers[layersLen-1] = vmPath
 			wopts.LayerFolders = layers
 
-			parent, err = uvm.CreateWCOW(ctx, wopts)
+			if req.Checkpoint != "" {
+				// The restored UVM keeps the devices and network namespace
+				// it was saved with.
+				parent, err = uvm.Restore(ctx, wopts.ID, filepath.Join(req.Checkpoint, uvmCheckpointFile))
+			} else {
+				parent, err = uvm.CreateWCOW(ctx, wopts)
+			}
 			if err != nil {
 				return nil, err
 			}
-		}
-		err = parent.Start(ctx)
-		if err != nil {
-			parent.Close()
-			return nil, err
+			err = parent.Start(ctx)
+			if err != nil {
+				parent.Close()
+				return nil, err
+			}
 		}
 	} else if !isWCOW {
 		return nil, errors.Wrap(errdefs.ErrFailedPrecondition, "oci spec does not contain WCOW or LCOW spec")
@@ -143,10 +181,20 @@ func createPod(ctx context.Context, events publisher, req *task.CreateTaskReques
 		}
 	}()
 
+	var shareRoots []string
+	if shimOpts != nil {
+		shareRoots = shimOpts.PodShareRoots
+	}
+	resources, err := newPodResources(ctx, parent, req.Bundle, shareRoots, s)
+	if err != nil {
+		return nil, err
+	}
+
 	p := pod{
-		events: events,
-		id:     req.ID,
-		host:   parent,
+		events:    events,
+		id:        req.ID,
+		host:      parent,
+		resources: resources,
 	}
 	// TOOD: JTERRY75 - There is a bug in the compartment activation for Windows
 	// Process isolated that requires us to create the real pause container to
@@ -170,7 +218,7 @@ func createPod(ctx context.Context, events publisher, req *task.CreateTaskReques
 					return nil, err
 				}
 				err = parent.AddNetNS(ctx, nsid)
-				if err != nil {
+				if err != nil && !(req.Checkpoint != "" && err == uvm.ErrNetNSAlreadyAttached) {
 					return nil, err
 				}
 				err = parent.AddEndpointsToNS(ctx, nsid, endpoints)
@@ -195,7 +243,7 @@ func createPod(ctx context.Context, events publisher, req *task.CreateTaskReques
 					Stderr:   req.Stderr,
 					Terminal: req.Terminal,
 				},
-				Checkpoint: "",
+				Checkpoint: req.Checkpoint,
 				Pid:        0,
 			})
 	} else {
@@ -236,6 +284,10 @@ type pod struct {
 	//
 	// It MUST be treated as read only in the lifetime of the pod.
 	host *uvm.UtilityVM
+	// resources are the resources shared by all tasks in the pod.
+	//
+	// It MUST be treated as read only in the lifetime of the pod.
+	resources *podResources
 
 	// wcl is the worload create mutex. All calls to CreateTask must hold this
 	// lock while the ID reservation takes place. Once the ID is held it is safe
@@ -290,6 +342,10 @@ func (p *pod) CreateTask(ctx context.Context, req *task.CreateTaskRequest, s *sp
 			sid)
 	}
 
+	if err := p.resources.translateMounts(ctx, p.host, s); err != nil {
+		return nil, err
+	}
+
 	st, err := newHcsTask(ctx, p.events, p.host, false, req, s)
 	if err != nil {
 		return nil, err
@@ -318,21 +374,46 @@ func (p *pod) KillTask(ctx context.Context, tid, eid string, signal uint32, all
 	if all && eid != "" {
 		return errors.Wrapf(errdefs.ErrFailedPrecondition, "cannot signal all with non empty ExecID: '%s'", eid)
 	}
-	eg := errgroup.Group{}
+	var workloads, sidecars []shimTask
 	if all && tid == p.id {
 		// We are in a kill all on the sandbox task. Signal everything.
 		p.workloadTasks.Range(func(key, value interface{}) bool {
-			wt := value.(shimTask)
-			eg.Go(func() error {
-				return wt.KillExec(ctx, eid, signal, all)
-			})
+			// A workload task that is still being created is `nil`.
+			if value != nil {
+				wt := value.(shimTask)
+				if signal != 0x9 && isSidecar(wt) {
+					sidecars = append(sidecars, wt)
+				} else {
+					workloads = append(workloads, wt)
+				}
+			}
 
 			// iterate all
-			return false
+			return true
+		})
+	}
+	eg := errgroup.Group{}
+	for _, wt := range workloads {
+		wt := wt
+		eg.Go(func() error {
+			return wt.KillExec(ctx, eid, signal, all)
 		})
 	}
-	eg.Go(func() error {
-		return t.KillExec(ctx, eid, signal, all)
-	})
-	return eg.Wait()
+	if len(sidecars) == 0 {
+		eg.Go(func() error {
+			return t.KillExec(ctx, eid, signal, all)
+		})
+		return eg.Wait()
+	}
+	if err := eg.Wait(); err != nil {
+		return err
+	}
+	// The sidecars are stopped once the other tasks have exited so that they
+	// can serve them until then, and the sandbox task is stopped last as its
+	// exit tears down the pod. A SIGKILL stops everything at once.
+	go func() {
+		killAfterExit(workloads, sidecars, signal, all)
+		killAfterExit(sidecars, []shimTask{t}, signal, all)
+	}()
+	return nil
 }
@@ -0,0 +1,305 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/Microsoft/hcsshim/internal/hcsoci"
+	"github.com/Microsoft/hcsshim/internal/lcow"
+	"github.com/Microsoft/hcsshim/internal/log"
+	"github.com/Microsoft/hcsshim/internal/oc"
+	"github.com/Microsoft/hcsshim/internal/oci"
+	hcsschema "github.com/Microsoft/hcsshim/internal/schema2"
+	"github.com/Microsoft/hcsshim/internal/uvm"
+	"github.com/Microsoft/hcsshim/internal/wclayer"
+	"github.com/containerd/containerd/errdefs"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"go.opencensus.io/trace"
+)
+
+const (
+	// podScratchFolder is the folder in the bundle of the pod sandbox that
+	// holds the pod scratch disk.
+	podScratchFolder = "podscratch"
+
+	wcowPodScratchGuestPath = `C:\pod\scratch`
+	lcowPodSharesGuestPath  = "/run/gcs/pod/shares"
+	lcowPodScratchGuestPath = "/run/gcs/pod/scratch"
+)
+
+// podShare is a host folder added to the utility VM of a pod to be shared by
+// its containers.
+type podShare struct {
+	HostPath string
+	// GuestPath is where the share is exposed in the utility VM.
+	GuestPath string
+	ReadOnly  bool
+}
+
+// podResources are the resources of a pod that are shared by all of its
+// containers. They are added to the utility VM of a hypervisor isolated pod
+// when the pod is created, and are released when the utility VM is closed.
+// The containers of a pod do not share a process namespace.
+//
+// It MUST be treated as read only once created.
+type podResources struct {
+	// IsWCOW is set if the pod is a WCOW pod.
+	IsWCOW bool
+	// Shares are the shares of the pod by name.
+	Shares map[string]*podShare `json:",omitempty"`
+	// ScratchHostPath is the pod scratch disk and ScratchGuestPath is where it
+	// is mounted in the utility VM. They are empty if the pod has no scratch
+	// disk.
+	ScratchHostPath  string `json:",omitempty"`
+	ScratchGuestPath string `json:",omitempty"`
+}
+
+// newPodResources adds the resources requested by the annotations of the pod
+// sandbox spec `s` to `host`, the utility VM of the pod, which is `nil` for a
+// process isolated pod. `bundle` is the bundle of the pod sandbox. A share MUST
+// be in `bundle` or in one of `shareRoots`, the roots allowed by the shim
+// options.
+//
+// The caller is expected to close `host` on failure, which releases any
+// resource already added.
+func newPodResources(ctx context.Context, host *uvm.UtilityVM, bundle string, shareRoots []string, s *specs.Spec) (_ *podResources, err error) {
+	ctx, span := trace.StartSpan(ctx, "newPodResources")
+	defer span.End()
+	defer func() { oc.SetSpanStatus(span, err) }()
+
+	pr := &podResources{
+		IsWCOW: oci.IsWCOW(s),
+	}
+	shares, err := oci.ParseAnnotationsPodShares(s)
+	if err != nil {
+		return nil, errors.Wrap(errdefs.ErrInvalidArgument, err.Error())
+	}
+	scratchSizeGB := oci.ParseAnnotationsPodScratchSizeInGB(ctx, s)
+	if host == nil {
+		if len(shares) > 0 || scratchSizeGB > 0 {
+			return nil, errors.Wrapf(errdefs.ErrFailedPrecondition, "pod shares and scratch disks require a hypervisor isolated pod")
+		}
+		return pr, nil
+	}
+
+	roots := append([]string{bundle}, shareRoots...)
+	for _, share := range shares {
+		if share.HostPath, err = resolvePodSharePath(share, roots); err != nil {
+			return nil, err
+		}
+		if err := pr.addShare(ctx, host, share); err != nil {
+			return nil, err
+		}
+	}
+	if scratchSizeGB > 0 {
+		if err := pr.addScratch(ctx, host, bundle, s, scratchSizeGB); err != nil {
+			return nil, err
+		}
+	}
+	return pr, nil
+}
+
+// resolvePodSharePath returns the host path of `share` with any links
+// resolved, which MUST be in one of `roots`. The roots are resolved the same
+// way so that a link cannot escape them.
+func resolvePodSharePath(share oci.PodShare, roots []string) (string, error) {
+	p, err := filepath.EvalSymlinks(share.HostPath)
+	if err != nil {
+		return "", errors.Wrapf(errdefs.ErrInvalidArgument, "pod share '%s': %v", share.Name, err)
+	}
+	for _, root := range roots {
+		if root == "" {
+			continue
+		}
+		r, err := filepath.EvalSymlinks(root)
+		if err != nil {
+			continue
+		}
+		if isPathUnder(p, r) {
+			return p, nil
+		}
+	}
+	return "", errors.Wrapf(errdefs.ErrInvalidArgument, "pod share '%s': '%s' is not in the pod sandbox bundle or an allowed share root", share.Name, share.HostPath)
+}
+
+// isPathUnder returns `true` if the clean path `p` is `root` or is in `root`.
+// The comparison ignores case as Windows paths are case insensitive.
+func isPathUnder(p, root string) bool {
+	rel, err := filepath.Rel(strings.ToLower(root), strings.ToLower(p))
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) && !filepath.IsAbs(rel)
+}
+
+// addShare adds `share` to `host`, over VSMB for a WCOW pod and over Plan9 for
+// an LCOW pod.
+func (pr *podResources) addShare(ctx context.Context, host *uvm.UtilityVM, share oci.PodShare) error {
+	log.G(ctx).WithFields(logrus.Fields{
+		"name":     share.Name,
+		"hostPath": share.HostPath,
+		"readOnly": share.ReadOnly,
+	}).Debug("adding pod share")
+
+	st, err := os.Stat(share.HostPath)
+	if err != nil {
+		return errors.Wrapf(errdefs.ErrInvalidArgument, "pod share '%s': %v", share.Name, err)
+	}
+	if !st.IsDir() {
+		return errors.Wrapf(errdefs.ErrInvalidArgument, "pod share '%s': '%s' is not a directory", share.Name, share.HostPath)
+	}
+
+	ps := &podShare{
+		HostPath: share.HostPath,
+		ReadOnly: share.ReadOnly,
+	}
+	if pr.IsWCOW {
+		options := &hcsschema.VirtualSmbShareOptions{}
+		if share.ReadOnly {
+			options.ReadOnly = true
+			options.CacheIo = true
+			options.ShareRead = true
+			options.ForceLevelIIOplocks = true
+		}
+		if err := host.AddVSMB(ctx, share.HostPath, "", options); err != nil {
+			return errors.Wrapf(err, "failed to add pod share '%s'", share.Name)
+		}
+		if ps.GuestPath, err = host.GetVSMBUvmPath(ctx, share.HostPath); err != nil {
+			return err
+		}
+	} else {
+		ps.GuestPath = path.Join(lcowPodSharesGuestPath, share.Name)
+		if _, err := host.AddPlan9(ctx, share.HostPath, ps.GuestPath, share.ReadOnly, false, nil); err != nil {
+			return errors.Wrapf(err, "failed to add pod share '%s'", share.Name)
+		}
+	}
+	if pr.Shares == nil {
+		pr.Shares = make(map[string]*podShare)
+	}
+	pr.Shares[share.Name] = ps
+	return nil
+}
+
+// addScratch creates a scratch disk of `sizeGB` in `bundle` and attaches it to
+// `host` over SCSI. The disk of a WCOW pod is created on the layers of the pod
+// sandbox spec `s`.
+func (pr *podResources) addScratch(ctx context.Context, host *uvm.UtilityVM, bundle string, s *specs.Spec, sizeGB uint64) error {
+	log.G(ctx).WithField("sizeGB", sizeGB).Debug("adding pod scratch disk")
+
+	folder := filepath.Join(bundle, podScratchFolder)
+	if err := os.MkdirAll(folder, 0); err != nil {
+		return err
+	}
+	if pr.IsWCOW {
+		if s.Windows == nil || len(s.Windows.LayerFolders) < 2 {
+			return errors.Wrap(errdefs.ErrInvalidArgument, "a pod scratch disk requires the layers of the pod sandbox")
+		}
+		layers := s.Windows.LayerFolders[:len(s.Windows.LayerFolders)-1]
+		if err := wclayer.CreateScratchLayer(folder, layers); err != nil {
+			return errors.Wrap(err, "failed to create pod scratch disk")
+		}
+		if err := wclayer.ExpandScratchSize(folder, sizeGB<<30); err != nil {
+			return errors.Wrap(err, "failed to expand pod scratch disk")
+		}
+		pr.ScratchHostPath = filepath.Join(folder, "sandbox.vhdx")
+		pr.ScratchGuestPath = wcowPodScratchGuestPath
+	} else {
+		pr.ScratchHostPath = filepath.Join(folder, "scratch.vhdx")
+		if err := lcow.CreateScratch(ctx, host, pr.ScratchHostPath, uint32(sizeGB), ""); err != nil {
+			return errors.Wrap(err, "failed to create pod scratch disk")
+		}
+		pr.ScratchGuestPath = lcowPodScratchGuestPath
+	}
+	if _, _, err := host.AddSCSI(ctx, pr.ScratchHostPath, pr.ScratchGuestPath, false); err != nil {
+		return errors.Wrap(err, "failed to attach pod scratch disk")
+	}
+	return nil
+}
+
+// translateMounts replaces the source of each mount of a pod share or of a pod
+// scratch volume in the spec `s` of a container in the pod with its path in
+// `host`, the utility VM of the pod. A scratch volume is created the first time
+// it is mounted.
+func (pr *podResources) translateMounts(ctx context.Context, host *uvm.UtilityVM, s *specs.Spec) error {
+	for i := range s.Mounts {
+		m := &s.Mounts[i]
+		var guestPath string
+		switch {
+		case strings.HasPrefix(m.Source, oci.PodShareMountPrefix):
+			name := strings.TrimPrefix(m.Source, oci.PodShareMountPrefix)
+			sub := ""
+			if j := strings.IndexAny(name, `/\`); j >= 0 {
+				name, sub = name[:j], name[j+1:]
+			}
+			var share *podShare
+			if pr != nil {
+				share = pr.Shares[name]
+			}
+			if share == nil {
+				return errors.Wrapf(errdefs.ErrInvalidArgument, "mount '%s': pod share '%s' not found", m.Source, name)
+			}
+			var err error
+			if guestPath, err = pr.guestPath(share.GuestPath, sub); err != nil {
+				return errors.Wrapf(err, "mount '%s'", m.Source)
+			}
+			if share.ReadOnly && !hasMountOption(m.Options, "ro") {
+				m.Options = append(m.Options, "ro")
+			}
+		case strings.HasPrefix(m.Source, oci.PodScratchMountPrefix):
+			name := strings.TrimPrefix(m.Source, oci.PodScratchMountPrefix)
+			if !oci.IsValidPodResourceName(name) {
+				return errors.Wrapf(errdefs.ErrInvalidArgument, "mount '%s': invalid pod scratch volume name '%s'", m.Source, name)
+			}
+			if pr == nil || pr.ScratchGuestPath == "" {
+				return errors.Wrapf(errdefs.ErrInvalidArgument, "mount '%s': pod has no scratch disk", m.Source)
+			}
+			var err error
+			if guestPath, err = pr.guestPath(pr.ScratchGuestPath, name); err != nil {
+				return errors.Wrapf(err, "mount '%s'", m.Source)
+			}
+			if err := host.MakeGuestDirectory(ctx, guestPath); err != nil {
+				return err
+			}
+		default:
+			continue
+		}
+		m.Source = hcsoci.UVMPathPrefix + guestPath
+		if !pr.IsWCOW {
+			m.Type = "bind"
+			if !hasMountOption(m.Options, "bind") && !hasMountOption(m.Options, "rbind") {
+				m.Options = append(m.Options, "rbind")
+			}
+		}
+	}
+	return nil
+}
+
+// guestPath returns the path of `sub`, a relative path with either separator,
+// under `base` in the utility VM.
+func (pr *podResources) guestPath(base, sub string) (string, error) {
+	parts := strings.FieldsFunc(sub, func(r rune) bool { return r == '/' || r == '\\' })
+	for _, p := range parts {
+		if p == "." || p == ".." {
+			return "", errors.Wrapf(errdefs.ErrInvalidArgument, "path '%s' must not contain '%s'", sub, p)
+		}
+	}
+	if pr.IsWCOW {
+		return strings.Join(append([]string{base}, parts...), `\`), nil
+	}
+	return path.Join(append([]string{base}, parts...)...), nil
+}
+
+// hasMountOption returns `true` if `options` contains `option` in any case.
+func hasMountOption(options []string, option string) bool {
+	for _, o := range options {
+		if strings.EqualFold(o, option) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Microsoft/hcsshim/internal/hcsoci"
+	"github.com/Microsoft/hcsshim/internal/oci"
+	"github.com/containerd/containerd/errdefs"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+func Test_podResources_TranslateMounts_Share_WCOW(t *testing.T) {
+	pr := &podResources{
+		IsWCOW: true,
+		Shares: map[string]*podShare{
+			"data": {HostPath: `C:\data`, GuestPath: `\\?\VMSMB\VSMB-{dcc079ae-60ba-4d07-847c-3493609c0870}\s1`, ReadOnly: true},
+		},
+	}
+	s := &specs.Spec{
+		Mounts: []specs.Mount{
+			{Destination: `C:\data`, Source: "podshare://data/sub/dir"},
+			{Destination: `C:\other`, Source: `C:\other`},
+		},
+	}
+
+	if err := pr.translateMounts(context.TODO(), nil, s); err != nil {
+		t.Fatalf("should not have failed, got: %v", err)
+	}
+	expected := hcsoci.UVMPathPrefix + `\\?\VMSMB\VSMB-{dcc079ae-60ba-4d07-847c-3493609c0870}\s1\sub\dir`
+	if s.Mounts[0].Source != expected {
+		t.Fatalf("expected source: '%s', got: '%s'", expected, s.Mounts[0].Source)
+	}
+	if !hasMountOption(s.Mounts[0].Options, "ro") {
+		t.Fatal("expected a read only pod share to be mounted read only")
+	}
+	if s.Mounts[1].Source != `C:\other` {
+		t.Fatalf("expected an unchanged source, got: '%s'", s.Mounts[1].Source)
+	}
+}
+
+func Test_podResources_TranslateMounts_Share_LCOW(t *testing.T) {
+	pr := &podResources{
+		Shares: map[string]*podShare{
+			"data": {HostPath: `C:\data`, GuestPath: "/run/gcs/pod/shares/data"},
+		},
+	}
+	s := &specs.Spec{
+		Mounts: []specs.Mount{
+			{Destination: "/data", Source: "podshare://data"},
+		},
+	}
+
+	if err := pr.translateMounts(context.TODO(), nil, s); err != nil {
+		t.Fatalf("should not have failed, got: %v", err)
+	}
+	m := s.Mounts[0]
+	if m.Source != hcsoci.UVMPathPrefix+"/run/gcs/pod/shares/data" || m.Type != "bind" || !hasMountOption(m.Options, "rbind") {
+		t.Fatalf("unexpected mount: %+v", m)
+	}
+	if hasMountOption(m.Options, "ro") {
+		t.Fatal("expected a read/write pod share to be mounted read/write")
+	}
+}
+
+func Test_podResources_TranslateMounts_Invalid_Error(t *testing.T) {
+	pr := &podResources{
+		Shares: map[string]*podShare{
+			"data": {HostPath: `C:\data`, GuestPath: "/run/gcs/pod/shares/data"},
+		},
+	}
+	sources := []string{
+		"podshare://missing",
+		"podshare://data/../escape",
+		"podscratch://cache",
+		"podscratch://",
+	}
+	for _, source := range sources {
+		s := &specs.Spec{
+			Mounts: []specs.Mount{
+				{Destination: "/data", Source: source},
+			},
+		}
+		err := pr.translateMounts(context.TODO(), nil, s)
+
+		verifyExpectedError(t, nil, err, errdefs.ErrInvalidArgument)
+	}
+}
+
+func Test_podResources_TranslateMounts_NoResources_Error(t *testing.T) {
+	var pr *podResources
+	s := &specs.Spec{
+		Mounts: []specs.Mount{
+			{Destination: "/data", Source: "podshare://data"},
+		},
+	}
+	err := pr.translateMounts(context.TODO(), nil, s)
+
+	verifyExpectedError(t, nil, err, errdefs.ErrInvalidArgument)
+}
+
+func Test_resolvePodSharePath(t *testing.T) {
+	dir, err := ioutil.TempDir("", t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	bundle := filepath.Join(dir, "bundle")
+	allowed := filepath.Join(dir, "allowed")
+	other := filepath.Join(dir, "other")
+	for _, p := range []string{filepath.Join(bundle, "data"), allowed, other} {
+		if err := os.MkdirAll(p, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+	roots := []string{bundle, allowed}
+
+	for _, p := range []string{filepath.Join(bundle, "data"), allowed} {
+		if _, err := resolvePodSharePath(oci.PodShare{Name: "data", HostPath: p}, roots); err != nil {
+			t.Fatalf("expected '%s' to be allowed, got: %v", p, err)
+		}
+	}
+	for _, p := range []string{other, filepath.Join(bundle, "..", "other"), filepath.Join(dir, "missing")} {
+		_, err := resolvePodSharePath(oci.PodShare{Name: "data", HostPath: p}, roots)
+		verifyExpectedError(t, nil, err, errdefs.ErrInvalidArgument)
+	}
+}
+
+func Test_isPathUnder(t *testing.T) {
+	root := filepath.Join(string(filepath.Separator), "root")
+	tests := []struct {
+		p     string
+		under bool
+	}{
+		{root, true},
+		{filepath.Join(root, "data"), true},
+		{strings.ToUpper(filepath.Join(root, "data")), true},
+		{filepath.Join(root, "..data"), true},
+		{filepath.Join(string(filepath.Separator), "rootdata"), false},
+		{filepath.Join(string(filepath.Separator), "other"), false},
+	}
+	for _, test := range tests {
+		if under := isPathUnder(test.p, root); under != test.under {
+			t.Fatalf("expected isPathUnder('%s', '%s') to be %v", test.p, root, test.under)
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"strconv"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/containerd/containerd/errdefs"
 	"github.com/containerd/containerd/runtime/v2/task"
@@ -205,3 +206,129 @@ func Test_pod_KillTask_WorkloadID_2ndExecID_All_Error(t *testing.T) {
 		verifyExpectedError(t, nil, err, errdefs.ErrFailedPrecondition)
 	}
 }
+
+func Test_pod_KillTask_SandboxID_All_SIGKILL_Sidecar_Success(t *testing.T) {
+	p, st := setupTestPodWithFakes(t)
+	t1 := setupTestTaskInPod(t, p)
+	t2 := setupTestTaskInPod(t, p)
+	t2.sidecar = true
+
+	err := p.KillTask(context.TODO(), t.Name(), "", 0x9, true)
+	if err != nil {
+		t.Fatalf("should not have failed, got: %v", err)
+	}
+	for _, e := range []*testShimExec{st.exec, t1.exec, t2.exec} {
+		if e.State() != shimExecStateExited {
+			t.Fatalf("expected a SIGKILL to kill task: '%s' at once", e.tid)
+		}
+	}
+}
+
+// blockingShimTask is a testShimTask that records the signals sent to it on
+// `killed`, and whose init exec runs until `exit` is closed.
+type blockingShimTask struct {
+	*testShimTask
+	killed chan uint32
+	exit   chan struct{}
+}
+
+func newBlockingShimTask(t *testShimTask) *blockingShimTask {
+	return &blockingShimTask{
+		testShimTask: t,
+		killed:       make(chan uint32, 1),
+		exit:         make(chan struct{}),
+	}
+}
+
+func (bst *blockingShimTask) GetExec(eid string) (shimExec, error) {
+	e, err := bst.testShimTask.GetExec(eid)
+	if err != nil || eid != "" {
+		return e, err
+	}
+	return &blockingShimExec{testShimExec: bst.exec, exit: bst.exit}, nil
+}
+
+func (bst *blockingShimTask) KillExec(ctx context.Context, eid string, signal uint32, all bool) error {
+	bst.killed <- signal
+	return nil
+}
+
+type blockingShimExec struct {
+	*testShimExec
+	exit chan struct{}
+}
+
+func (bse *blockingShimExec) State() shimExecState {
+	select {
+	case <-bse.exit:
+		return shimExecStateExited
+	default:
+		return shimExecStateRunning
+	}
+}
+
+func (bse *blockingShimExec) Wait() *task.StateResponse {
+	<-bse.exit
+	return bse.Status()
+}
+
+// verifyKilled verifies that `bst` is sent `signal` within `timeout`.
+func verifyKilled(t *testing.T, bst *blockingShimTask, signal uint32, timeout time.Duration) {
+	select {
+	case s := <-bst.killed:
+		if s != signal {
+			t.Fatalf("expected task: '%s' to be sent signal %d, got: %d", bst.ID(), signal, s)
+		}
+	case <-time.After(timeout):
+		t.Fatalf("expected task: '%s' to be signaled", bst.ID())
+	}
+}
+
+// verifyNotKilled verifies that `bst` has not been sent a signal.
+func verifyNotKilled(t *testing.T, bst *blockingShimTask) {
+	select {
+	case s := <-bst.killed:
+		t.Fatalf("expected task: '%s' not to be signaled yet, got: %d", bst.ID(), s)
+	default:
+	}
+}
+
+func Test_pod_KillTask_Sidecar_SignaledAtOnce(t *testing.T) {
+	p, _ := setupTestPodWithFakes(t)
+	w := newBlockingShimTask(setupTestTaskInPod(t, p))
+	p.workloadTasks.Store(w.ID(), w)
+	sc := newBlockingShimTask(setupTestTaskInPod(t, p))
+	sc.sidecar = true
+	p.workloadTasks.Store(sc.ID(), sc)
+
+	if err := p.KillTask(context.TODO(), sc.ID(), "", 0xf, false); err != nil {
+		t.Fatalf("should not have failed, got: %v", err)
+	}
+	verifyKilled(t, sc, 0xf, time.Second)
+	verifyNotKilled(t, w)
+}
+
+func Test_pod_KillTask_SandboxID_All_Sidecar_StoppedInBackground(t *testing.T) {
+	p, st := setupTestPodWithFakes(t)
+	s := newBlockingShimTask(st)
+	p.sandboxTask = s
+	w := newBlockingShimTask(setupTestTaskInPod(t, p))
+	p.workloadTasks.Store(w.ID(), w)
+	sc := newBlockingShimTask(setupTestTaskInPod(t, p))
+	sc.sidecar = true
+	p.workloadTasks.Store(sc.ID(), sc)
+
+	if err := p.KillTask(context.TODO(), t.Name(), "", 0xf, true); err != nil {
+		t.Fatalf("should not have failed, got: %v", err)
+	}
+	verifyKilled(t, w, 0xf, time.Second)
+	verifyNotKilled(t, sc)
+	verifyNotKilled(t, s)
+
+	close(w.exit)
+	verifyKilled(t, sc, 0xf, 5*time.Second)
+	verifyNotKilled(t, s)
+
+	close(sc.exit)
+	verifyKilled(t, s, 0xf, 5*time.Second)
+}
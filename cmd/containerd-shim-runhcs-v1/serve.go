@@ -42,6 +42,10 @@ var serveCommand = cli.Command{
 			Name:  "is-sandbox",
 			Usage: "is the task id a Kubernetes sandbox id",
 		},
+		cli.BoolFlag{
+			Name:  "recover",
+			Usage: "recover the tasks saved in the bundle by a shim that exited",
+		},
 	},
 	Action: func(ctx *cli.Context) error {
 		// On Windows the serve command is internally used to actually create
@@ -65,8 +69,6 @@ var serveCommand = cli.Command{
 		// the upstream caller by listening for a log connection and streaming
 		// the events.
 
-		var lerrs chan error
-
 		// Default values for shim options.
 		shimOpts := &runhcsopts.Options{
 			Debug:     false,
@@ -82,61 +84,12 @@ var serveCommand = cli.Command{
 			shimOpts = newShimOpts
 		}
 
-		if shimOpts.Debug {
-			logrus.SetLevel(logrus.DebugLevel)
+		lerrs, logl, err := setupLogOutput(shimOpts)
+		if err != nil {
+			return err
 		}
-
-		switch shimOpts.DebugType {
-		case runhcsopts.Options_NPIPE:
-			logrus.SetFormatter(&logrus.TextFormatter{
-				TimestampFormat: log.RFC3339NanoFixed,
-				FullTimestamp:   true,
-			})
-			// Setup the log listener
-			//
-			// TODO: JTERRY75 we need this to be the reconnect log listener or
-			// switch to events
-			// TODO: JTERRY75 switch containerd to use the protected path.
-			//const logAddrFmt = "\\\\.\\pipe\\ProtectedPrefix\\Administrators\\containerd-shim-%s-%s-log"
-			const logAddrFmt = "\\\\.\\pipe\\containerd-shim-%s-%s-log"
-			logl, err := winio.ListenPipe(fmt.Sprintf(logAddrFmt, namespaceFlag, idFlag), nil)
-			if err != nil {
-				return err
-			}
+		if logl != nil {
 			defer logl.Close()
-
-			lerrs = make(chan error, 1)
-			go func() {
-				var cur net.Conn
-				for {
-					// Listen for log connections in the background
-					// We assume that there is always only one client
-					// which is containerd. If a new connection is
-					// accepted, it means that containerd is restarted.
-					// Note that logs generated during containerd restart
-					// may be lost.
-					new, err := logl.Accept()
-					if err != nil {
-						lerrs <- err
-						return
-					}
-					if cur != nil {
-						cur.Close()
-					}
-					cur = new
-					// Switch the logrus output to here. Note: we wont get this
-					// connection until the return from `shim start` so we still
-					// havent transitioned the error model yet.
-					logrus.SetOutput(cur)
-				}
-			}()
-			// Logrus output will be redirected in the goroutine below that
-			// handles the pipe connection.
-		case runhcsopts.Options_FILE:
-			panic("file log output mode is not supported")
-		case runhcsopts.Options_ETW:
-			logrus.SetFormatter(nopFormatter{})
-			logrus.SetOutput(ioutil.Discard)
 		}
 
 		os.Stdin.Close()
@@ -169,7 +122,16 @@ var serveCommand = cli.Command{
 			tid:       idFlag,
 			isSandbox: ctx.Bool("is-sandbox"),
 		}
-		s, err := ttrpc.NewServer(ttrpc.WithUnaryServerInterceptor(octtrpc.ServerInterceptor()))
+		if ctx.Bool("recover") {
+			cwd, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+			if err := svc.recover(context.Background(), cwd); err != nil {
+				return errors.Wrap(err, "failed to recover shim state")
+			}
+		}
+		s, err := ttrpc.NewServer(ttrpc.WithUnaryServerInterceptor(svc.countRPCs(octtrpc.ServerInterceptor())))
 		if err != nil {
 			return err
 		}
@@ -224,6 +186,70 @@ var serveCommand = cli.Command{
 	},
 }
 
+// setupLogOutput sets the logrus output of the shim `idFlag` as configured by
+// `shimOpts`. With Options_NPIPE the logs are written to the client of a log
+// pipe, which the returned listener serves until it is closed. The returned
+// channel receives the error that stopped the listener.
+func setupLogOutput(shimOpts *runhcsopts.Options) (chan error, net.Listener, error) {
+	if shimOpts.Debug {
+		logrus.SetLevel(logrus.DebugLevel)
+	}
+
+	switch shimOpts.DebugType {
+	case runhcsopts.Options_NPIPE:
+		logrus.SetFormatter(&logrus.TextFormatter{
+			TimestampFormat: log.RFC3339NanoFixed,
+			FullTimestamp:   true,
+		})
+		// Setup the log listener
+		//
+		// TODO: JTERRY75 we need this to be the reconnect log listener or
+		// switch to events
+		// TODO: JTERRY75 switch containerd to use the protected path.
+		//const logAddrFmt = "\\\\.\\pipe\\ProtectedPrefix\\Administrators\\containerd-shim-%s-%s-log"
+		const logAddrFmt = "\\\\.\\pipe\\containerd-shim-%s-%s-log"
+		logl, err := winio.ListenPipe(fmt.Sprintf(logAddrFmt, namespaceFlag, idFlag), nil)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		lerrs := make(chan error, 1)
+		go func() {
+			var cur net.Conn
+			for {
+				// Listen for log connections in the background
+				// We assume that there is always only one client
+				// which is containerd. If a new connection is
+				// accepted, it means that containerd is restarted.
+				// Note that logs generated during containerd restart
+				// may be lost.
+				new, err := logl.Accept()
+				if err != nil {
+					lerrs <- err
+					return
+				}
+				if cur != nil {
+					cur.Close()
+				}
+				cur = new
+				// Switch the logrus output to here. Note: we wont get this
+				// connection until the return from `shim start` so we still
+				// havent transitioned the error model yet.
+				logrus.SetOutput(cur)
+			}
+		}()
+		// Logrus output will be redirected in the goroutine above that
+		// handles the pipe connection.
+		return lerrs, logl, nil
+	case runhcsopts.Options_FILE:
+		panic("file log output mode is not supported")
+	case runhcsopts.Options_ETW:
+		logrus.SetFormatter(nopFormatter{})
+		logrus.SetOutput(ioutil.Discard)
+	}
+	return nil, nil, nil
+}
+
 // readOptions reads in bytes from the reader and converts it to a shim options
 // struct. If no data is available from the reader, returns (nil, nil).
 func readOptions(r io.Reader) (*runhcsopts.Options, error) {
@@ -49,6 +49,21 @@ type service struct {
 	// taken when creating tasks in a POD sandbox as they can happen
 	// concurrently.
 	cl sync.Mutex
+
+	// sl is the state lock that MUST be held to read/write `stateBundle` and
+	// to save the shim state.
+	sl sync.Mutex
+	// stateBundle is the bundle the shim state is saved to if restart recovery
+	// was requested for the task or POD, or "" otherwise.
+	stateBundle string
+
+	// pendingRPCs is the number of requests to the shim in flight.
+	//
+	// NOTE: All accesses to this MUST be done atomically.
+	pendingRPCs int32
+	// lastErrors is the last error returned for each task id, as a
+	// `*taskError`, until the task is deleted.
+	lastErrors sync.Map
 }
 
 func (s *service) State(ctx context.Context, req *task.StateRequest) (resp *task.StateResponse, err error) {
@@ -70,7 +85,7 @@ func (s *service) State(ctx context.Context, req *task.StateRequest) (resp *task
 		trace.StringAttribute("eid", req.ExecID))
 
 	r, e := s.stateInternal(ctx, req)
-	return r, errdefs.ToGRPC(e)
+	return r, errdefs.ToGRPC(s.recordTaskError("State", req.ID, e))
 }
 
 func (s *service) Create(ctx context.Context, req *task.CreateTaskRequest) (resp *task.CreateTaskResponse, err error) {
@@ -97,7 +112,7 @@ func (s *service) Create(ctx context.Context, req *task.CreateTaskRequest) (resp
 		trace.StringAttribute("parentcheckpoint", req.ParentCheckpoint))
 
 	r, e := s.createInternal(ctx, req)
-	return r, errdefs.ToGRPC(e)
+	return r, errdefs.ToGRPC(s.recordTaskError("Create", req.ID, e))
 }
 
 func (s *service) Start(ctx context.Context, req *task.StartRequest) (resp *task.StartResponse, err error) {
@@ -116,7 +131,7 @@ func (s *service) Start(ctx context.Context, req *task.StartRequest) (resp *task
 		trace.StringAttribute("eid", req.ExecID))
 
 	r, e := s.startInternal(ctx, req)
-	return r, errdefs.ToGRPC(e)
+	return r, errdefs.ToGRPC(s.recordTaskError("Start", req.ID, e))
 }
 
 func (s *service) Delete(ctx context.Context, req *task.DeleteRequest) (resp *task.DeleteResponse, err error) {
@@ -138,7 +153,10 @@ func (s *service) Delete(ctx context.Context, req *task.DeleteRequest) (resp *ta
 		trace.StringAttribute("eid", req.ExecID))
 
 	r, e := s.deleteInternal(ctx, req)
-	return r, errdefs.ToGRPC(e)
+	if e == nil && req.ExecID == "" {
+		s.forgetTaskError(req.ID)
+	}
+	return r, errdefs.ToGRPC(s.recordTaskError("Delete", req.ID, e))
 }
 
 func (s *service) Pids(ctx context.Context, req *task.PidsRequest) (_ *task.PidsResponse, err error) {
@@ -150,7 +168,7 @@ func (s *service) Pids(ctx context.Context, req *task.PidsRequest) (_ *task.Pids
 	span.AddAttributes(trace.StringAttribute("tid", req.ID))
 
 	r, e := s.pidsInternal(ctx, req)
-	return r, errdefs.ToGRPC(e)
+	return r, errdefs.ToGRPC(s.recordTaskError("Pids", req.ID, e))
 }
 
 func (s *service) Pause(ctx context.Context, req *task.PauseRequest) (_ *google_protobuf1.Empty, err error) {
@@ -162,7 +180,7 @@ func (s *service) Pause(ctx context.Context, req *task.PauseRequest) (_ *google_
 	span.AddAttributes(trace.StringAttribute("tid", req.ID))
 
 	r, e := s.pauseInternal(ctx, req)
-	return r, errdefs.ToGRPC(e)
+	return r, errdefs.ToGRPC(s.recordTaskError("Pause", req.ID, e))
 }
 
 func (s *service) Resume(ctx context.Context, req *task.ResumeRequest) (_ *google_protobuf1.Empty, err error) {
@@ -174,7 +192,7 @@ func (s *service) Resume(ctx context.Context, req *task.ResumeRequest) (_ *googl
 	span.AddAttributes(trace.StringAttribute("tid", req.ID))
 
 	r, e := s.resumeInternal(ctx, req)
-	return r, errdefs.ToGRPC(e)
+	return r, errdefs.ToGRPC(s.recordTaskError("Resume", req.ID, e))
 }
 
 func (s *service) Checkpoint(ctx context.Context, req *task.CheckpointTaskRequest) (_ *google_protobuf1.Empty, err error) {
@@ -188,7 +206,7 @@ func (s *service) Checkpoint(ctx context.Context, req *task.CheckpointTaskReques
 		trace.StringAttribute("path", req.Path))
 
 	r, e := s.checkpointInternal(ctx, req)
-	return r, errdefs.ToGRPC(e)
+	return r, errdefs.ToGRPC(s.recordTaskError("Checkpoint", req.ID, e))
 }
 
 func (s *service) Kill(ctx context.Context, req *task.KillRequest) (_ *google_protobuf1.Empty, err error) {
@@ -204,7 +222,7 @@ func (s *service) Kill(ctx context.Context, req *task.KillRequest) (_ *google_pr
 		trace.BoolAttribute("all", req.All))
 
 	r, e := s.killInternal(ctx, req)
-	return r, errdefs.ToGRPC(e)
+	return r, errdefs.ToGRPC(s.recordTaskError("Kill", req.ID, e))
 }
 
 func (s *service) Exec(ctx context.Context, req *task.ExecProcessRequest) (_ *google_protobuf1.Empty, err error) {
@@ -222,7 +240,7 @@ func (s *service) Exec(ctx context.Context, req *task.ExecProcessRequest) (_ *go
 		trace.StringAttribute("stderr", req.Stderr))
 
 	r, e := s.execInternal(ctx, req)
-	return r, errdefs.ToGRPC(e)
+	return r, errdefs.ToGRPC(s.recordTaskError("Exec", req.ID, e))
 }
 
 func (s *service) DiagExecInHost(ctx context.Context, req *shimdiag.ExecProcessRequest) (_ *shimdiag.ExecProcessResponse, err error) {
@@ -243,6 +261,44 @@ func (s *service) DiagExecInHost(ctx context.Context, req *shimdiag.ExecProcessR
 	return r, errdefs.ToGRPC(e)
 }
 
+func (s *service) DiagInventory(ctx context.Context, req *shimdiag.InventoryRequest) (_ *shimdiag.InventoryResponse, err error) {
+	defer panicRecover()
+	ctx, span := trace.StartSpan(ctx, "DiagInventory")
+	defer span.End()
+	defer func() { oc.SetSpanStatus(span, err) }()
+
+	span.AddAttributes(trace.StringAttribute("tid", s.tid))
+
+	r, e := s.diagInventoryInternal(ctx, req)
+	return r, errdefs.ToGRPC(e)
+}
+
+func (s *service) DiagGuestLog(ctx context.Context, req *shimdiag.GuestLogRequest) (_ *shimdiag.GuestLogResponse, err error) {
+	defer panicRecover()
+	ctx, span := trace.StartSpan(ctx, "DiagGuestLog")
+	defer span.End()
+	defer func() { oc.SetSpanStatus(span, err) }()
+
+	span.AddAttributes(
+		trace.StringAttribute("tid", s.tid),
+		trace.StringAttribute("source", req.Source))
+
+	r, e := s.diagGuestLogInternal(ctx, req)
+	return r, errdefs.ToGRPC(e)
+}
+
+func (s *service) DiagHealth(ctx context.Context, req *shimdiag.HealthRequest) (_ *shimdiag.HealthResponse, err error) {
+	defer panicRecover()
+	ctx, span := trace.StartSpan(ctx, "DiagHealth")
+	defer span.End()
+	defer func() { oc.SetSpanStatus(span, err) }()
+
+	span.AddAttributes(trace.StringAttribute("tid", s.tid))
+
+	r, e := s.diagHealthInternal(ctx, req)
+	return r, errdefs.ToGRPC(e)
+}
+
 func (s *service) ResizePty(ctx context.Context, req *task.ResizePtyRequest) (_ *google_protobuf1.Empty, err error) {
 	defer panicRecover()
 	ctx, span := trace.StartSpan(ctx, "ResizePty")
@@ -256,7 +312,7 @@ func (s *service) ResizePty(ctx context.Context, req *task.ResizePtyRequest) (_
 		trace.Int64Attribute("height", int64(req.Height)))
 
 	r, e := s.resizePtyInternal(ctx, req)
-	return r, errdefs.ToGRPC(e)
+	return r, errdefs.ToGRPC(s.recordTaskError("ResizePty", req.ID, e))
 }
 
 func (s *service) CloseIO(ctx context.Context, req *task.CloseIORequest) (_ *google_protobuf1.Empty, err error) {
@@ -271,7 +327,7 @@ func (s *service) CloseIO(ctx context.Context, req *task.CloseIORequest) (_ *goo
 		trace.BoolAttribute("stdin", req.Stdin))
 
 	r, e := s.closeIOInternal(ctx, req)
-	return r, errdefs.ToGRPC(e)
+	return r, errdefs.ToGRPC(s.recordTaskError("CloseIO", req.ID, e))
 }
 
 func (s *service) Update(ctx context.Context, req *task.UpdateTaskRequest) (_ *google_protobuf1.Empty, err error) {
@@ -283,7 +339,7 @@ func (s *service) Update(ctx context.Context, req *task.UpdateTaskRequest) (_ *g
 	span.AddAttributes(trace.StringAttribute("tid", req.ID))
 
 	r, e := s.updateInternal(ctx, req)
-	return r, errdefs.ToGRPC(e)
+	return r, errdefs.ToGRPC(s.recordTaskError("Update", req.ID, e))
 }
 
 func (s *service) Wait(ctx context.Context, req *task.WaitRequest) (resp *task.WaitResponse, err error) {
@@ -304,7 +360,7 @@ func (s *service) Wait(ctx context.Context, req *task.WaitRequest) (resp *task.W
 		trace.StringAttribute("eid", req.ExecID))
 
 	r, e := s.waitInternal(ctx, req)
-	return r, errdefs.ToGRPC(e)
+	return r, errdefs.ToGRPC(s.recordTaskError("Wait", req.ID, e))
 }
 
 func (s *service) Stats(ctx context.Context, req *task.StatsRequest) (_ *task.StatsResponse, err error) {
@@ -316,7 +372,7 @@ func (s *service) Stats(ctx context.Context, req *task.StatsRequest) (_ *task.St
 	span.AddAttributes(trace.StringAttribute("tid", req.ID))
 
 	r, e := s.statsInternal(ctx, req)
-	return r, errdefs.ToGRPC(e)
+	return r, errdefs.ToGRPC(s.recordTaskError("Stats", req.ID, e))
 }
 
 func (s *service) Connect(ctx context.Context, req *task.ConnectRequest) (resp *task.ConnectResponse, err error) {
@@ -336,7 +392,7 @@ func (s *service) Connect(ctx context.Context, req *task.ConnectRequest) (resp *
 	span.AddAttributes(trace.StringAttribute("tid", req.ID))
 
 	r, e := s.connectInternal(ctx, req)
-	return r, errdefs.ToGRPC(e)
+	return r, errdefs.ToGRPC(s.recordTaskError("Connect", req.ID, e))
 }
 
 func (s *service) Shutdown(ctx context.Context, req *task.ShutdownRequest) (_ *google_protobuf1.Empty, err error) {
@@ -348,7 +404,7 @@ func (s *service) Shutdown(ctx context.Context, req *task.ShutdownRequest) (_ *g
 	span.AddAttributes(trace.StringAttribute("tid", req.ID))
 
 	r, e := s.shutdownInternal(ctx, req)
-	return r, errdefs.ToGRPC(e)
+	return r, errdefs.ToGRPC(s.recordTaskError("Shutdown", req.ID, e))
 }
 
 func (s *service) DiagStacks(ctx context.Context, req *shimdiag.StacksRequest) (*shimdiag.StacksResponse, error) {
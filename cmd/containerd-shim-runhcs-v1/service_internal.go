@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	runhcsopts "github.com/Microsoft/hcsshim/cmd/containerd-shim-runhcs-v1/options"
+	"github.com/Microsoft/hcsshim/internal/log"
 	"github.com/Microsoft/hcsshim/internal/oci"
 	"github.com/Microsoft/hcsshim/internal/shimdiag"
 	containerd_v1_types "github.com/containerd/containerd/api/types/task"
@@ -71,6 +72,17 @@ func (s *service) stateInternal(ctx context.Context, req *task.StateRequest) (*t
 func (s *service) createInternal(ctx context.Context, req *task.CreateTaskRequest) (*task.CreateTaskResponse, error) {
 	setupDebuggerEvent()
 
+	if req.Checkpoint != "" {
+		// Only the UVM of a POD sandbox is restored from a checkpoint. The
+		// containers that ran in it are not.
+		if !s.isSandbox {
+			return nil, errors.Wrapf(errdefs.ErrNotImplemented, "restoring task '%s' from a checkpoint is only supported for a POD sandbox", req.ID)
+		}
+		if _, err := s.getPod(); err == nil {
+			return nil, errors.Wrapf(errdefs.ErrNotImplemented, "restoring task '%s' in a POD from a checkpoint is not supported", req.ID)
+		}
+	}
+
 	var shimOpts *runhcsopts.Options
 	if req.Options != nil {
 		v, err := typeurl.UnmarshalAny(req.Options)
@@ -161,9 +173,10 @@ func (s *service) createInternal(ctx context.Context, req *task.CreateTaskReques
 			}
 			e, _ := t.GetExec("")
 			resp.Pid = uint32(e.Pid())
+			s.saveState(ctx)
 			return resp, nil
 		}
-		pod, err = createPod(ctx, s.events, req, &spec)
+		pod, err = createPod(ctx, s.events, req, &spec, shimOpts)
 		if err != nil {
 			s.cl.Unlock()
 			return nil, err
@@ -182,7 +195,14 @@ func (s *service) createInternal(ctx context.Context, req *task.CreateTaskReques
 		resp.Pid = uint32(e.Pid())
 		s.taskOrPod.Store(t)
 	}
+	if oci.ParseAnnotationsRestartRecovery(ctx, &spec) {
+		// The shim state is saved to the bundle of the task or POD sandbox.
+		s.sl.Lock()
+		s.stateBundle = req.Bundle
+		s.sl.Unlock()
+	}
 	s.cl.Unlock()
+	s.saveState(ctx)
 	return resp, nil
 }
 
@@ -199,6 +219,7 @@ func (s *service) startInternal(ctx context.Context, req *task.StartRequest) (*t
 	if err != nil {
 		return nil, err
 	}
+	s.saveState(ctx)
 	return &task.StartResponse{
 		Pid: uint32(e.Pid()),
 	}, nil
@@ -215,6 +236,7 @@ func (s *service) deleteInternal(ctx context.Context, req *task.DeleteRequest) (
 	if err != nil {
 		return nil, err
 	}
+	s.saveState(ctx)
 	// TODO: We should be removing the task after this right?
 	return &task.DeleteResponse{
 		Pid:        uint32(pid),
@@ -274,7 +296,29 @@ func (s *service) resumeInternal(ctx context.Context, req *task.ResumeRequest) (
 }
 
 func (s *service) checkpointInternal(ctx context.Context, req *task.CheckpointTaskRequest) (*google_protobuf1.Empty, error) {
-	return nil, errdefs.ErrNotImplemented
+	if req.Path == "" {
+		return nil, errors.Wrapf(errdefs.ErrInvalidArgument, "checkpoint path for task '%s' must not be empty", req.ID)
+	}
+	var exit bool
+	if req.Options != nil {
+		v, err := typeurl.UnmarshalAny(req.Options)
+		if err != nil {
+			return nil, errors.Wrapf(errdefs.ErrInvalidArgument, "failed to read checkpoint options of task '%s': %v", req.ID, err)
+		}
+		opts, ok := v.(*runhcsopts.CheckpointOptions)
+		if !ok {
+			return nil, errors.Wrapf(errdefs.ErrInvalidArgument, "invalid checkpoint options type %T", v)
+		}
+		exit = opts.Exit
+	}
+	t, err := s.getTask(req.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.Checkpoint(ctx, req.Path, exit); err != nil {
+		return nil, err
+	}
+	return empty, nil
 }
 
 func (s *service) killInternal(ctx context.Context, req *task.KillRequest) (*google_protobuf1.Empty, error) {
@@ -318,6 +362,7 @@ func (s *service) execInternal(ctx context.Context, req *task.ExecProcessRequest
 	if err != nil {
 		return nil, err
 	}
+	s.saveState(ctx)
 	return empty, nil
 }
 
@@ -336,6 +381,22 @@ func (s *service) diagExecInHostInternal(ctx context.Context, req *shimdiag.Exec
 	return &shimdiag.ExecProcessResponse{ExitCode: int32(ec)}, nil
 }
 
+func (s *service) diagInventoryInternal(ctx context.Context, req *shimdiag.InventoryRequest) (*shimdiag.InventoryResponse, error) {
+	t, err := s.getTask(s.tid)
+	if err != nil {
+		return nil, err
+	}
+	return t.DeviceInventory(ctx)
+}
+
+func (s *service) diagGuestLogInternal(ctx context.Context, req *shimdiag.GuestLogRequest) (*shimdiag.GuestLogResponse, error) {
+	t, err := s.getTask(s.tid)
+	if err != nil {
+		return nil, err
+	}
+	return t.GuestLog(ctx, req)
+}
+
 func (s *service) resizePtyInternal(ctx context.Context, req *task.ResizePtyRequest) (*google_protobuf1.Empty, error) {
 	t, err := s.getTask(req.ID)
 	if err != nil {
@@ -369,7 +430,21 @@ func (s *service) closeIOInternal(ctx context.Context, req *task.CloseIORequest)
 }
 
 func (s *service) updateInternal(ctx context.Context, req *task.UpdateTaskRequest) (*google_protobuf1.Empty, error) {
-	return nil, errdefs.ErrNotImplemented
+	if req.Resources == nil {
+		return nil, errors.Wrapf(errdefs.ErrInvalidArgument, "resources for task '%s' must not be nil", req.ID)
+	}
+	t, err := s.getTask(req.ID)
+	if err != nil {
+		return nil, err
+	}
+	resources, err := typeurl.UnmarshalAny(req.Resources)
+	if err != nil {
+		return nil, errors.Wrapf(errdefs.ErrInvalidArgument, "failed to unmarshal resources for task '%s': %s", req.ID, err)
+	}
+	if err := t.Update(ctx, resources); err != nil {
+		return nil, err
+	}
+	return empty, nil
 }
 
 func (s *service) waitInternal(ctx context.Context, req *task.WaitRequest) (*task.WaitResponse, error) {
@@ -425,6 +500,14 @@ func (s *service) shutdownInternal(ctx context.Context, req *task.ShutdownReques
 		return empty, nil
 	}
 
+	// Flush the events that are not published yet, such as the `TaskExit` of
+	// the init task, before the shim exits.
+	if ep, ok := s.events.(*eventPublisher); ok {
+		if err := ep.close(); err != nil {
+			log.G(ctx).WithError(err).Warn("failed to close event publisher")
+		}
+	}
+
 	if req.Now {
 		os.Exit(0)
 	}
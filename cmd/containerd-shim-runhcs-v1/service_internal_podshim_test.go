@@ -10,6 +10,7 @@ import (
 	"github.com/containerd/containerd/errdefs"
 	"github.com/containerd/containerd/runtime/v2/task"
 	"github.com/containerd/typeurl"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
 )
 
 func setupPodServiceWithFakes(t *testing.T) (*service, *testShimTask, *testShimTask, *testShimExec) {
@@ -184,6 +185,14 @@ func Test_PodShim_stateInternal_2ndTaskID_2ndExecID_Success(t *testing.T) {
 
 // TODO: Test_PodShim_createInternal_*
 
+func Test_PodShim_createInternal_Checkpoint_PodCreated_Error(t *testing.T) {
+	s, _, _, _ := setupPodServiceWithFakes(t)
+
+	resp, err := s.createInternal(context.TODO(), &task.CreateTaskRequest{ID: t.Name(), Checkpoint: t.Name()})
+
+	verifyExpectedError(t, resp, err, errdefs.ErrNotImplemented)
+}
+
 func Test_PodShim_startInternal_NoTask_Error(t *testing.T) {
 	s := service{
 		tid:       t.Name(),
@@ -403,15 +412,31 @@ func Test_PodShim_resumeInternal_Error(t *testing.T) {
 	verifyExpectedError(t, resp, err, errdefs.ErrNotImplemented)
 }
 
-func Test_PodShim_checkpointInternal_Error(t *testing.T) {
+func Test_PodShim_checkpointInternal_NoTask_Error(t *testing.T) {
 	s := service{
 		tid:       t.Name(),
 		isSandbox: true,
 	}
 
-	resp, err := s.checkpointInternal(context.TODO(), &task.CheckpointTaskRequest{ID: t.Name()})
+	resp, err := s.checkpointInternal(context.TODO(), &task.CheckpointTaskRequest{ID: t.Name(), Path: t.Name()})
 
-	verifyExpectedError(t, resp, err, errdefs.ErrNotImplemented)
+	verifyExpectedError(t, resp, err, errdefs.ErrNotFound)
+}
+
+func Test_PodShim_checkpointInternal_NoPath_Error(t *testing.T) {
+	s, t1, _, _ := setupPodServiceWithFakes(t)
+
+	resp, err := s.checkpointInternal(context.TODO(), &task.CheckpointTaskRequest{ID: t1.ID()})
+
+	verifyExpectedError(t, resp, err, errdefs.ErrInvalidArgument)
+}
+
+func Test_PodShim_checkpointInternal_NotIsolated_Error(t *testing.T) {
+	s, t1, _, _ := setupPodServiceWithFakes(t)
+
+	resp, err := s.checkpointInternal(context.TODO(), &task.CheckpointTaskRequest{ID: t1.ID(), Path: t.Name()})
+
+	verifyExpectedError(t, resp, err, errdefs.ErrFailedPrecondition)
 }
 
 func Test_PodShim_killInternal_NoTask_Error(t *testing.T) {
@@ -572,7 +597,7 @@ func Test_PodShim_closeIOInternal_2ndTaskID_2ndExecID_Success(t *testing.T) {
 	}
 }
 
-func Test_PodShim_updateInternal_Error(t *testing.T) {
+func Test_PodShim_updateInternal_NilResources_Error(t *testing.T) {
 	s := service{
 		tid:       t.Name(),
 		isSandbox: true,
@@ -580,6 +605,33 @@ func Test_PodShim_updateInternal_Error(t *testing.T) {
 
 	resp, err := s.updateInternal(context.TODO(), &task.UpdateTaskRequest{ID: t.Name()})
 
+	verifyExpectedError(t, resp, err, errdefs.ErrInvalidArgument)
+}
+
+func Test_PodShim_updateInternal_NoTask_Error(t *testing.T) {
+	s := service{
+		tid:       t.Name(),
+		isSandbox: true,
+	}
+	any, err := typeurl.MarshalAny(&specs.WindowsResources{})
+	if err != nil {
+		t.Fatalf("failed to marshal resources: %v", err)
+	}
+
+	resp, err := s.updateInternal(context.TODO(), &task.UpdateTaskRequest{ID: t.Name(), Resources: any})
+
+	verifyExpectedError(t, resp, err, errdefs.ErrNotFound)
+}
+
+func Test_PodShim_updateInternal_InitTask_Error(t *testing.T) {
+	s, _, _, _ := setupPodServiceWithFakes(t)
+	any, err := typeurl.MarshalAny(&specs.WindowsResources{})
+	if err != nil {
+		t.Fatalf("failed to marshal resources: %v", err)
+	}
+
+	resp, err := s.updateInternal(context.TODO(), &task.UpdateTaskRequest{ID: s.tid, Resources: any})
+
 	verifyExpectedError(t, resp, err, errdefs.ErrNotImplemented)
 }
 
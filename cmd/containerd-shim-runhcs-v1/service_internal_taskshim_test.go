@@ -7,9 +7,11 @@ import (
 	"testing"
 
 	"github.com/Microsoft/hcsshim/cmd/containerd-shim-runhcs-v1/options"
+	"github.com/Microsoft/hcsshim/internal/shimdiag"
 	"github.com/containerd/containerd/errdefs"
 	"github.com/containerd/containerd/runtime/v2/task"
 	"github.com/containerd/typeurl"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
 )
 
 func setupTaskServiceWithFakes(t *testing.T) (*service, *testShimTask, *testShimExec) {
@@ -133,6 +135,17 @@ func Test_TaskShim_stateInternal_InitTaskID_2ndExecID_Success(t *testing.T) {
 
 // TODO: Test_TaskShim_createInternal_*
 
+func Test_TaskShim_createInternal_Checkpoint_Error(t *testing.T) {
+	s := service{
+		tid:       t.Name(),
+		isSandbox: false,
+	}
+
+	resp, err := s.createInternal(context.TODO(), &task.CreateTaskRequest{ID: t.Name(), Checkpoint: t.Name()})
+
+	verifyExpectedError(t, resp, err, errdefs.ErrNotImplemented)
+}
+
 func Test_TaskShim_startInternal_NoTask_Error(t *testing.T) {
 	s := service{
 		tid:       t.Name(),
@@ -323,15 +336,58 @@ func Test_TaskShim_resumeInternal_Error(t *testing.T) {
 	verifyExpectedError(t, resp, err, errdefs.ErrNotImplemented)
 }
 
-func Test_TaskShim_checkpointInternal_Error(t *testing.T) {
+func Test_TaskShim_checkpointInternal_NoTask_Error(t *testing.T) {
 	s := service{
 		tid:       t.Name(),
-		isSandbox: true,
+		isSandbox: false,
 	}
 
-	resp, err := s.checkpointInternal(context.TODO(), &task.CheckpointTaskRequest{ID: t.Name()})
+	resp, err := s.checkpointInternal(context.TODO(), &task.CheckpointTaskRequest{ID: t.Name(), Path: t.Name()})
 
-	verifyExpectedError(t, resp, err, errdefs.ErrNotImplemented)
+	verifyExpectedError(t, resp, err, errdefs.ErrNotFound)
+}
+
+func Test_TaskShim_checkpointInternal_NoPath_Error(t *testing.T) {
+	s, t1, _ := setupTaskServiceWithFakes(t)
+
+	resp, err := s.checkpointInternal(context.TODO(), &task.CheckpointTaskRequest{ID: t1.ID()})
+
+	verifyExpectedError(t, resp, err, errdefs.ErrInvalidArgument)
+}
+
+func Test_TaskShim_checkpointInternal_InvalidOptions_Error(t *testing.T) {
+	s, t1, _ := setupTaskServiceWithFakes(t)
+	any, err := typeurl.MarshalAny(&options.Options{})
+	if err != nil {
+		t.Fatalf("failed to marshal options: %v", err)
+	}
+
+	resp, err := s.checkpointInternal(context.TODO(), &task.CheckpointTaskRequest{ID: t1.ID(), Path: t.Name(), Options: any})
+
+	verifyExpectedError(t, resp, err, errdefs.ErrInvalidArgument)
+}
+
+func Test_TaskShim_checkpointInternal_Exit(t *testing.T) {
+	s, t1, _ := setupTaskServiceWithFakes(t)
+	any, err := typeurl.MarshalAny(&options.CheckpointOptions{Exit: true})
+	if err != nil {
+		t.Fatalf("failed to marshal options: %v", err)
+	}
+
+	resp, err := s.checkpointInternal(context.TODO(), &task.CheckpointTaskRequest{ID: t1.ID(), Path: t.Name(), Options: any})
+
+	verifyExpectedError(t, resp, err, errdefs.ErrFailedPrecondition)
+	if !t1.checkpointExit {
+		t.Fatal("expected the exit checkpoint option to be passed to the task")
+	}
+}
+
+func Test_TaskShim_checkpointInternal_NotIsolated_Error(t *testing.T) {
+	s, t1, _ := setupTaskServiceWithFakes(t)
+
+	resp, err := s.checkpointInternal(context.TODO(), &task.CheckpointTaskRequest{ID: t1.ID(), Path: t.Name()})
+
+	verifyExpectedError(t, resp, err, errdefs.ErrFailedPrecondition)
 }
 
 func Test_TaskShim_killInternal_NoTask_Error(t *testing.T) {
@@ -492,7 +548,7 @@ func Test_TaskShim_closeIOInternal_InitTaskID_2ndExecID_Success(t *testing.T) {
 	}
 }
 
-func Test_TaskShim_updateInternal_Error(t *testing.T) {
+func Test_TaskShim_updateInternal_NilResources_Error(t *testing.T) {
 	s := service{
 		tid:       t.Name(),
 		isSandbox: true,
@@ -500,6 +556,33 @@ func Test_TaskShim_updateInternal_Error(t *testing.T) {
 
 	resp, err := s.updateInternal(context.TODO(), &task.UpdateTaskRequest{ID: t.Name()})
 
+	verifyExpectedError(t, resp, err, errdefs.ErrInvalidArgument)
+}
+
+func Test_TaskShim_updateInternal_NoTask_Error(t *testing.T) {
+	s := service{
+		tid:       t.Name(),
+		isSandbox: true,
+	}
+	any, err := typeurl.MarshalAny(&specs.WindowsResources{})
+	if err != nil {
+		t.Fatalf("failed to marshal resources: %v", err)
+	}
+
+	resp, err := s.updateInternal(context.TODO(), &task.UpdateTaskRequest{ID: t.Name(), Resources: any})
+
+	verifyExpectedError(t, resp, err, errdefs.ErrNotFound)
+}
+
+func Test_TaskShim_updateInternal_InitTask_Error(t *testing.T) {
+	s, _, _ := setupTaskServiceWithFakes(t)
+	any, err := typeurl.MarshalAny(&specs.WindowsResources{})
+	if err != nil {
+		t.Fatalf("failed to marshal resources: %v", err)
+	}
+
+	resp, err := s.updateInternal(context.TODO(), &task.UpdateTaskRequest{ID: s.tid, Resources: any})
+
 	verifyExpectedError(t, resp, err, errdefs.ErrNotImplemented)
 }
 
@@ -571,3 +654,41 @@ func Test_TaskShim_statsInternal_Error(t *testing.T) {
 
 	verifyExpectedError(t, resp, err, errdefs.ErrNotFound)
 }
+
+func Test_TaskShim_diagInventoryInternal_NoTask_Error(t *testing.T) {
+	s := service{
+		tid:       t.Name(),
+		isSandbox: false,
+	}
+
+	resp, err := s.diagInventoryInternal(context.TODO(), &shimdiag.InventoryRequest{})
+
+	verifyExpectedError(t, resp, err, errdefs.ErrNotFound)
+}
+
+func Test_TaskShim_diagInventoryInternal_NotIsolated_Error(t *testing.T) {
+	s, _, _ := setupTaskServiceWithFakes(t)
+
+	resp, err := s.diagInventoryInternal(context.TODO(), &shimdiag.InventoryRequest{})
+
+	verifyExpectedError(t, resp, err, errdefs.ErrFailedPrecondition)
+}
+
+func Test_TaskShim_diagGuestLogInternal_NoTask_Error(t *testing.T) {
+	s := service{
+		tid:       t.Name(),
+		isSandbox: false,
+	}
+
+	resp, err := s.diagGuestLogInternal(context.TODO(), &shimdiag.GuestLogRequest{Source: "gcs"})
+
+	verifyExpectedError(t, resp, err, errdefs.ErrNotFound)
+}
+
+func Test_TaskShim_diagGuestLogInternal_NotIsolated_Error(t *testing.T) {
+	s, _, _ := setupTaskServiceWithFakes(t)
+
+	resp, err := s.diagGuestLogInternal(context.TODO(), &shimdiag.GuestLogRequest{Source: "gcs"})
+
+	verifyExpectedError(t, resp, err, errdefs.ErrFailedPrecondition)
+}
@@ -30,7 +30,15 @@ The start command, as well as all binary calls to the shim, has the bundle for t
 The start command MUST return an address to a shim for containerd to issue API requests for container operations.
 
 The start command can either start a new shim or return an address to an existing shim based on the shim's logic.
+
+With --dry-run the start command instead prints the resources, utility VM paths and HCS documents that would be used to create the task, as JSON, without starting a shim or creating the task or its utility VM. It is a debugging aid for placement and annotation effects and is never used by containerd.
 `,
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:  "dry-run",
+			Usage: "print the plan to create the task as JSON without starting a shim",
+		},
+	},
 	SkipArgReorder: true,
 	Action: func(context *cli.Context) (err error) {
 		// We cant write anything to stdout/stderr for this cmd.
@@ -64,6 +72,10 @@ The start command can either start a new shim or return an address to an existin
 			return err
 		}
 
+		if context.Bool("dry-run") {
+			return printTaskPlan(cwd, os.Stdout)
+		}
+
 		a, err := getSpecAnnotations(cwd)
 		if err != nil {
 			return err
@@ -78,22 +90,29 @@ The start command can either start a new shim or return an address to an existin
 			address = fmt.Sprintf(addrFmt, namespaceFlag, sbid)
 
 			// Connect to the hosting shim and get the pid
-			c, err := winio.DialPipe(address, nil)
+			pid, err = connectShim(address, sbid)
 			if err != nil {
-				return errors.Wrap(err, "failed to connect to hosting shim")
-			}
-			cl := ttrpc.NewClient(c, ttrpc.WithOnClose(func() { c.Close() }))
-			t := task.NewTaskClient(cl)
-			ctx := gocontext.Background()
-			req := &task.ConnectRequest{ID: sbid}
-			cr, err := t.Connect(ctx, req)
-
-			cl.Close()
-			c.Close()
-			if err != nil {
-				return errors.Wrap(err, "failed to get shim pid from hosting shim")
+				// If the hosting shim exited after saving its state restart
+				// it to recover the POD.
+				sbBundle := filepath.Join(filepath.Dir(cwd), sbid)
+				if !hasShimState(sbBundle) {
+					return err
+				}
+				var p *os.Process
+				p, err = serveShim(sbBundle, sbid, address, true, true)
+				if err != nil {
+					return err
+				}
+				defer func() {
+					if err != nil {
+						p.Kill()
+					}
+				}()
+				if err := shim.WritePidFile(filepath.Join(sbBundle, "shim.pid"), p.Pid); err != nil {
+					return err
+				}
+				pid = p.Pid
 			}
-			pid = int(cr.ShimPid)
 		}
 
 		// We need to serve a new one.
@@ -107,63 +126,26 @@ The start command can either start a new shim or return an address to an existin
 					oci.KubernetesContainerTypeSandbox)
 			}
 
-			self, err := os.Executable()
-			if err != nil {
-				return err
-			}
-
-			r, w, err := os.Pipe()
-			if err != nil {
-				return err
-			}
-			defer r.Close()
-			defer w.Close()
-
-			f, err := os.Create(filepath.Join(cwd, "panic.log"))
-			if err != nil {
-				return err
-			}
-			defer f.Close()
-
 			address = fmt.Sprintf(addrFmt, namespaceFlag, idFlag)
-			args := []string{
-				self,
-				"--namespace", namespaceFlag,
-				"--address", addressFlag,
-				"--publish-binary", containerdBinaryFlag,
-				"--id", idFlag,
-				"serve",
-				"--socket", address,
-			}
-			if isSandbox {
-				args = append(args, "--is-sandbox")
-			}
-			cmd := &exec.Cmd{
-				Path:   self,
-				Args:   args,
-				Env:    os.Environ(),
-				Dir:    cwd,
-				Stdin:  os.Stdin,
-				Stdout: w,
-				Stderr: f,
-			}
-
-			if err := cmd.Start(); err != nil {
-				return err
+			// If a shim saved its state in the bundle return its address if it
+			// is still running, or else restart it to recover the task.
+			recover := hasShimState(cwd)
+			if recover {
+				pid, err = connectShim(address, idFlag)
 			}
-			w.Close()
-			defer func() {
+			if !recover || err != nil {
+				var p *os.Process
+				p, err = serveShim(cwd, idFlag, address, isSandbox, recover)
 				if err != nil {
-					cmd.Process.Kill()
+					return err
 				}
-			}()
-
-			// Forward the invocation stderr until the serve command closes it.
-			_, err = io.Copy(os.Stderr, r)
-			if err != nil {
-				return err
+				defer func() {
+					if err != nil {
+						p.Kill()
+					}
+				}()
+				pid = p.Pid
 			}
-			pid = cmd.Process.Pid
 		}
 
 		if err := shim.WritePidFile(filepath.Join(cwd, "shim.pid"), pid); err != nil {
@@ -181,6 +163,92 @@ The start command can either start a new shim or return an address to an existin
 	},
 }
 
+// connectShim connects to the shim serving the task `id` at `address` and
+// returns the pid of the shim.
+func connectShim(address, id string) (int, error) {
+	c, err := winio.DialPipe(address, nil)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to connect to hosting shim")
+	}
+	cl := ttrpc.NewClient(c, ttrpc.WithOnClose(func() { c.Close() }))
+	t := task.NewTaskClient(cl)
+	ctx := gocontext.Background()
+	req := &task.ConnectRequest{ID: id}
+	cr, err := t.Connect(ctx, req)
+
+	cl.Close()
+	c.Close()
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get shim pid from hosting shim")
+	}
+	return int(cr.ShimPid), nil
+}
+
+// serveShim starts a shim serving the task `id` in `bundle` at `address` and
+// returns once it is serving. If `recover` the shim recovers the tasks saved in
+// `bundle` by a shim that exited.
+func serveShim(bundle, id, address string, isSandbox, recover bool) (_ *os.Process, err error) {
+	self, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	defer w.Close()
+
+	f, err := os.Create(filepath.Join(bundle, "panic.log"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	args := []string{
+		self,
+		"--namespace", namespaceFlag,
+		"--address", addressFlag,
+		"--publish-binary", containerdBinaryFlag,
+		"--id", id,
+		"serve",
+		"--socket", address,
+	}
+	if isSandbox {
+		args = append(args, "--is-sandbox")
+	}
+	if recover {
+		args = append(args, "--recover")
+	}
+	cmd := &exec.Cmd{
+		Path:   self,
+		Args:   args,
+		Env:    os.Environ(),
+		Dir:    bundle,
+		Stdin:  os.Stdin,
+		Stdout: w,
+		Stderr: f,
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	w.Close()
+	defer func() {
+		if err != nil {
+			cmd.Process.Kill()
+		}
+	}()
+
+	// Forward the invocation stderr until the serve command closes it.
+	_, err = io.Copy(os.Stderr, r)
+	if err != nil {
+		return nil, err
+	}
+	return cmd.Process, nil
+}
+
 func getSpecAnnotations(bundlePath string) (map[string]string, error) {
 	// specAnnotations is a minimal representation for oci.Spec that we need
 	// to serve a shim.
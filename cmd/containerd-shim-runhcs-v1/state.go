@@ -0,0 +1,321 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/Microsoft/hcsshim/internal/log"
+	"github.com/Microsoft/hcsshim/internal/oc"
+	"github.com/Microsoft/hcsshim/internal/oci"
+	"github.com/Microsoft/hcsshim/internal/uvm"
+	eventstypes "github.com/containerd/containerd/api/events"
+	"github.com/containerd/containerd/errdefs"
+	"github.com/containerd/containerd/runtime"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"go.opencensus.io/trace"
+)
+
+// shimStateFile is the name of the file in the bundle that the shim saves its
+// state to when restart recovery is requested, and that a restarted shim
+// recovers its tasks from.
+const shimStateFile = "shim-state.json"
+
+// shimState is the state a restarted shim needs to reopen the compute systems
+// and processes of the shim that exited, and to resume serving their tasks.
+type shimState struct {
+	ID        string
+	IsSandbox bool
+	// HostID is the compute system id of the utility VM owned by the shim, if
+	// any, and Host its description to reopen it.
+	HostID string          `json:",omitempty"`
+	Host   json.RawMessage `json:",omitempty"`
+	// Pod is the resources shared by the tasks of the pod, if this is a pod
+	// shim.
+	Pod *podResources `json:",omitempty"`
+	// Tasks are the tasks of the shim. The task of the shim itself is first.
+	Tasks []*taskState
+}
+
+type taskState struct {
+	ID       string
+	Bundle   string
+	IsWCOW   bool
+	OwnsHost bool
+	// Stop is the stop policy of the task, if any.
+	Stop *oci.StopPolicy `json:",omitempty"`
+	// Sidecar is set if the task is a sidecar in a pod.
+	Sidecar bool `json:",omitempty"`
+	// PodSandbox is set if this is the fake WCOW pod sandbox task.
+	PodSandbox bool `json:",omitempty"`
+	Init       *execState
+	Execs      []*execState `json:",omitempty"`
+}
+
+type execState struct {
+	ID    string
+	Pid   int
+	State shimExecState
+	// Spec is the process of an exec that is not yet started.
+	Spec       *specs.Process `json:",omitempty"`
+	Stdin      string         `json:",omitempty"`
+	Stdout     string         `json:",omitempty"`
+	Stderr     string         `json:",omitempty"`
+	Terminal   bool           `json:",omitempty"`
+	ExitStatus uint32
+	ExitedAt   time.Time
+}
+
+// readShimState reads the shim state saved in `bundle`.
+func readShimState(bundle string) (*shimState, error) {
+	b, err := ioutil.ReadFile(filepath.Join(bundle, shimStateFile))
+	if err != nil {
+		return nil, err
+	}
+	var st shimState
+	if err := json.Unmarshal(b, &st); err != nil {
+		return nil, errors.Wrapf(err, "failed to read shim state in bundle '%s'", bundle)
+	}
+	return &st, nil
+}
+
+// writeShimState saves `st` in `bundle`, replacing the previous state only
+// once it is completely written.
+func writeShimState(bundle string, st *shimState) error {
+	b, err := json.Marshal(st)
+	if err != nil {
+		return err
+	}
+	p := filepath.Join(bundle, shimStateFile)
+	if err := ioutil.WriteFile(p+".tmp", b, 0600); err != nil {
+		return err
+	}
+	return os.Rename(p+".tmp", p)
+}
+
+// hasShimState returns `true` if a shim saved its state in `bundle`.
+func hasShimState(bundle string) bool {
+	_, err := os.Stat(filepath.Join(bundle, shimStateFile))
+	return err == nil
+}
+
+// describeExec returns the state of `e`.
+func describeExec(e shimExec) *execState {
+	status := e.Status()
+	es := &execState{
+		ID:         e.ID(),
+		Pid:        int(status.Pid),
+		State:      e.State(),
+		Stdin:      status.Stdin,
+		Stdout:     status.Stdout,
+		Stderr:     status.Stderr,
+		Terminal:   status.Terminal,
+		ExitStatus: status.ExitStatus,
+		ExitedAt:   status.ExitedAt,
+	}
+	if he, ok := e.(*hcsExec); ok && es.State == shimExecStateCreated {
+		es.Spec = he.spec
+	}
+	return es
+}
+
+// describeTask returns the state of `t`, or `nil` if `t` cannot be recovered.
+func describeTask(t shimTask) *taskState {
+	switch t := t.(type) {
+	case *hcsTask:
+		ts := &taskState{
+			ID:       t.id,
+			Bundle:   t.init.Status().Bundle,
+			IsWCOW:   t.isWCOW,
+			OwnsHost: t.ownsHost,
+			Stop:     t.stop,
+			Sidecar:  t.sidecar,
+			Init:     describeExec(t.init),
+		}
+		t.execs.Range(func(key, value interface{}) bool {
+			ts.Execs = append(ts.Execs, describeExec(value.(shimExec)))
+
+			// iterate all
+			return true
+		})
+		sort.Slice(ts.Execs, func(i, j int) bool { return ts.Execs[i].ID < ts.Execs[j].ID })
+		return ts
+	case *wcowPodSandboxTask:
+		return &taskState{
+			ID:         t.id,
+			Bundle:     t.init.bundle,
+			IsWCOW:     true,
+			OwnsHost:   true,
+			PodSandbox: true,
+			Init:       describeExec(t.init),
+		}
+	}
+	return nil
+}
+
+// describe returns the state of the task or POD this shim is tracking.
+func (s *service) describe() (*shimState, error) {
+	raw := s.taskOrPod.Load()
+	if raw == nil {
+		return nil, errors.Wrapf(errdefs.ErrFailedPrecondition, "task with id: '%s' must be created first", s.tid)
+	}
+	st := &shimState{
+		ID:        s.tid,
+		IsSandbox: s.isSandbox,
+	}
+	var host *uvm.UtilityVM
+	switch t := raw.(type) {
+	case *pod:
+		host = t.host
+		st.Pod = t.resources
+		if ts := describeTask(t.sandboxTask); ts != nil {
+			st.Tasks = append(st.Tasks, ts)
+		}
+		t.workloadTasks.Range(func(key, value interface{}) bool {
+			// A workload task that is still being created is `nil`.
+			if value != nil {
+				if ts := describeTask(value.(shimTask)); ts != nil {
+					st.Tasks = append(st.Tasks, ts)
+				}
+			}
+
+			// iterate all
+			return true
+		})
+	case *hcsTask:
+		if t.ownsHost {
+			host = t.host
+		}
+		if ts := describeTask(t); ts != nil {
+			st.Tasks = append(st.Tasks, ts)
+		}
+	}
+	if len(st.Tasks) == 0 || st.Tasks[0].ID != s.tid {
+		return nil, errors.Wrapf(errdefs.ErrNotImplemented, "task with id: '%s' cannot be recovered", s.tid)
+	}
+	if host != nil {
+		b, err := host.Describe()
+		if err != nil {
+			return nil, err
+		}
+		st.HostID = host.SystemID()
+		st.Host = b
+	}
+	return st, nil
+}
+
+// saveState saves the state of the shim to its bundle if restart recovery was
+// requested. A failure is logged, as the shim only fails to recover its tasks
+// if it is restarted.
+func (s *service) saveState(ctx context.Context) {
+	s.sl.Lock()
+	defer s.sl.Unlock()
+	if s.stateBundle == "" {
+		return
+	}
+	st, err := s.describe()
+	if err == nil {
+		err = writeShimState(s.stateBundle, st)
+	}
+	if err != nil {
+		log.G(ctx).WithError(err).Warn("failed to save shim state")
+	}
+}
+
+// recover reopens the tasks saved in `bundle` by a shim that exited and resumes
+// tracking them. It MUST be called before the shim serves any requests.
+//
+// A workload task whose container exited while no shim was running is not
+// recovered, and its exit is published with status 255. If the task of the
+// shim itself cannot be recovered the recovery fails and the caller is expected
+// to delete the task.
+func (s *service) recover(ctx context.Context, bundle string) (err error) {
+	ctx, span := trace.StartSpan(ctx, "service::recover")
+	defer span.End()
+	defer func() { oc.SetSpanStatus(span, err) }()
+	span.AddAttributes(
+		trace.StringAttribute("tid", s.tid),
+		trace.StringAttribute("bundle", bundle))
+
+	st, err := readShimState(bundle)
+	if err != nil {
+		return err
+	}
+	if st.ID != s.tid || st.IsSandbox != s.isSandbox || len(st.Tasks) == 0 || st.Tasks[0].ID != s.tid {
+		return errors.Wrapf(errdefs.ErrFailedPrecondition, "shim state in bundle '%s' is not for task with id: '%s'", bundle, s.tid)
+	}
+
+	var host *uvm.UtilityVM
+	if len(st.Host) != 0 {
+		host, err = uvm.Open(ctx, st.Host)
+		if err != nil {
+			return err
+		}
+	}
+
+	tasks := make([]shimTask, 0, len(st.Tasks))
+	for _, ts := range st.Tasks {
+		if ts.PodSandbox {
+			tasks = append(tasks, newRecoveredWcowPodSandboxTask(ctx, s.events, host, ts))
+			continue
+		}
+		if ts.ID != s.tid && ts.Init.State == shimExecStateExited {
+			// The exit of the task was published by the shim that exited.
+			continue
+		}
+		t, err := newRecoveredHcsTask(ctx, s.events, host, ts)
+		if err != nil {
+			if ts.ID == s.tid {
+				return err
+			}
+			log.G(ctx).WithFields(logrus.Fields{
+				"tid":           ts.ID,
+				logrus.ErrorKey: err,
+			}).Warn("failed to recover task")
+			s.events.publishEvent(
+				ctx,
+				runtime.TaskExitEventTopic,
+				&eventstypes.TaskExit{
+					ContainerID: ts.ID,
+					ID:          ts.ID,
+					Pid:         uint32(ts.Init.Pid),
+					ExitStatus:  255,
+					ExitedAt:    time.Now(),
+				})
+			continue
+		}
+		tasks = append(tasks, t)
+	}
+
+	if s.isSandbox {
+		p := &pod{
+			events:      s.events,
+			id:          s.tid,
+			sandboxTask: tasks[0],
+			host:        host,
+			resources:   st.Pod,
+		}
+		for _, t := range tasks[1:] {
+			p.workloadTasks.Store(t.ID(), t)
+		}
+		s.taskOrPod.Store(p)
+	} else {
+		s.taskOrPod.Store(tasks[0])
+	}
+
+	s.sl.Lock()
+	s.stateBundle = bundle
+	s.sl.Unlock()
+
+	log.G(ctx).WithFields(logrus.Fields{
+		"tid":   s.tid,
+		"tasks": len(tasks),
+	}).Info("recovered shim state")
+	return nil
+}
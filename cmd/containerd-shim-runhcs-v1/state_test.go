@@ -0,0 +1,68 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/containerd/containerd/errdefs"
+)
+
+func Test_Service_describe_NotCreated_Error(t *testing.T) {
+	s := service{
+		tid:       t.Name(),
+		isSandbox: false,
+	}
+
+	st, err := s.describe()
+
+	verifyExpectedError(t, st, err, errdefs.ErrFailedPrecondition)
+}
+
+func Test_ShimState_RoundTrip(t *testing.T) {
+	ht, initExec, secondExec := setupTestHcsTask(t)
+	initExec.state = shimExecStateRunning
+	s := service{
+		tid:       t.Name(),
+		isSandbox: false,
+	}
+	s.taskOrPod.Store(ht)
+
+	st, err := s.describe()
+	if err != nil {
+		t.Fatalf("should not have failed with error: %v", err)
+	}
+
+	dir, err := ioutil.TempDir("", "shimstate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if hasShimState(dir) {
+		t.Fatal("should not have shim state before it is written")
+	}
+	if err := writeShimState(dir, st); err != nil {
+		t.Fatalf("should not have failed to write with error: %v", err)
+	}
+	if !hasShimState(dir) {
+		t.Fatal("should have shim state after it is written")
+	}
+	read, err := readShimState(dir)
+	if err != nil {
+		t.Fatalf("should not have failed to read with error: %v", err)
+	}
+
+	if read.ID != t.Name() || read.IsSandbox || read.HostID != "" {
+		t.Fatalf("unexpected shim state: %+v", read)
+	}
+	if len(read.Tasks) != 1 {
+		t.Fatalf("expected 1 task, got: %d", len(read.Tasks))
+	}
+	ts := read.Tasks[0]
+	if ts.ID != t.Name() || ts.Init.ID != t.Name() || ts.Init.Pid != initExec.pid || ts.Init.State != shimExecStateRunning {
+		t.Fatalf("unexpected init exec state: %+v", ts.Init)
+	}
+	if len(ts.Execs) != 1 || ts.Execs[0].ID != secondExec.id || ts.Execs[0].State != shimExecStateCreated {
+		t.Fatalf("unexpected exec state: %+v", ts.Execs)
+	}
+}
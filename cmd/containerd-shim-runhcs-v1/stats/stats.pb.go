@@ -1,37 +1,25 @@
 // Code generated by protoc-gen-gogo. DO NOT EDIT.
 // source: github.com/Microsoft/hcsshim/cmd/containerd-shim-runhcs-v1/stats/stats.proto
 
-/*
-	Package stats is a generated protocol buffer package.
-
-	It is generated from these files:
-		github.com/Microsoft/hcsshim/cmd/containerd-shim-runhcs-v1/stats/stats.proto
-
-	It has these top-level messages:
-		Statistics
-		WindowsContainerStatistics
-		LinuxContainerStatistics
-		VirtualMachineStatistics
-		VirtualMachineProcessorStatistics
-		VirtualMachineMemoryStatistics
-*/
 package stats
 
-import proto "github.com/gogo/protobuf/proto"
-import fmt "fmt"
-import math "math"
-
-// skipping weak import gogoproto "github.com/gogo/protobuf/gogoproto"
-
-import strings "strings"
-import reflect "reflect"
-
-import io "io"
+import (
+	fmt "fmt"
+	proto "github.com/gogo/protobuf/proto"
+	_ "github.com/gogo/protobuf/types"
+	github_com_gogo_protobuf_types "github.com/gogo/protobuf/types"
+	io "io"
+	math "math"
+	reflect "reflect"
+	strings "strings"
+	time "time"
+)
 
 // Reference imports to suppress errors if they are not otherwise used.
 var _ = proto.Marshal
 var _ = fmt.Errorf
 var _ = math.Inf
+var _ = time.Kitchen
 
 // This is a compile-time assertion to ensure that this generated file
 // is compatible with the proto package it is being compiled against.
@@ -43,13 +31,44 @@ type Statistics struct {
 	// Types that are valid to be assigned to Container:
 	//	*Statistics_Windows
 	//	*Statistics_Linux
-	Container isStatistics_Container    `protobuf_oneof:"container"`
-	VM        *VirtualMachineStatistics `protobuf:"bytes,3,opt,name=vm" json:"vm,omitempty"`
+	Container            isStatistics_Container    `protobuf_oneof:"container"`
+	VM                   *VirtualMachineStatistics `protobuf:"bytes,3,opt,name=vm,proto3" json:"vm,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                  `json:"-"`
+	XXX_unrecognized     []byte                    `json:"-"`
+	XXX_sizecache        int32                     `json:"-"`
+}
+
+func (m *Statistics) Reset()      { *m = Statistics{} }
+func (*Statistics) ProtoMessage() {}
+func (*Statistics) Descriptor() ([]byte, []int) {
+	return fileDescriptor_23217f96da3a05cc, []int{0}
+}
+func (m *Statistics) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Statistics) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Statistics.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *Statistics) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Statistics.Merge(m, src)
+}
+func (m *Statistics) XXX_Size() int {
+	return m.Size()
+}
+func (m *Statistics) XXX_DiscardUnknown() {
+	xxx_messageInfo_Statistics.DiscardUnknown(m)
 }
 
-func (m *Statistics) Reset()                    { *m = Statistics{} }
-func (*Statistics) ProtoMessage()               {}
-func (*Statistics) Descriptor() ([]byte, []int) { return fileDescriptorStats, []int{0} }
+var xxx_messageInfo_Statistics proto.InternalMessageInfo
 
 type isStatistics_Container interface {
 	isStatistics_Container()
@@ -58,10 +77,10 @@ type isStatistics_Container interface {
 }
 
 type Statistics_Windows struct {
-	Windows *WindowsContainerStatistics `protobuf:"bytes,1,opt,name=windows,oneof"`
+	Windows *WindowsContainerStatistics `protobuf:"bytes,1,opt,name=windows,proto3,oneof"`
 }
 type Statistics_Linux struct {
-	Linux *LinuxContainerStatistics `protobuf:"bytes,2,opt,name=linux,oneof"`
+	Linux *LinuxContainerStatistics `protobuf:"bytes,2,opt,name=linux,proto3,oneof"`
 }
 
 func (*Statistics_Windows) isStatistics_Container() {}
@@ -147,12 +166,12 @@ func _Statistics_OneofSizer(msg proto.Message) (n int) {
 	switch x := m.Container.(type) {
 	case *Statistics_Windows:
 		s := proto.Size(x.Windows)
-		n += proto.SizeVarint(1<<3 | proto.WireBytes)
+		n += 1 // tag and wire
 		n += proto.SizeVarint(uint64(s))
 		n += s
 	case *Statistics_Linux:
 		s := proto.Size(x.Linux)
-		n += proto.SizeVarint(2<<3 | proto.WireBytes)
+		n += 1 // tag and wire
 		n += proto.SizeVarint(uint64(s))
 		n += s
 	case nil:
@@ -163,56 +182,745 @@ func _Statistics_OneofSizer(msg proto.Message) (n int) {
 }
 
 type WindowsContainerStatistics struct {
+	Timestamp            time.Time                            `protobuf:"bytes,1,opt,name=timestamp,proto3,stdtime" json:"timestamp"`
+	ContainerStartTime   time.Time                            `protobuf:"bytes,2,opt,name=container_start_time,json=containerStartTime,proto3,stdtime" json:"container_start_time"`
+	UptimeNS             uint64                               `protobuf:"varint,3,opt,name=uptime_ns,json=uptimeNs,proto3" json:"uptime_ns,omitempty"`
+	Processor            *WindowsContainerProcessorStatistics `protobuf:"bytes,4,opt,name=processor,proto3" json:"processor,omitempty"`
+	Memory               *WindowsContainerMemoryStatistics    `protobuf:"bytes,5,opt,name=memory,proto3" json:"memory,omitempty"`
+	Storage              *WindowsContainerStorageStatistics   `protobuf:"bytes,6,opt,name=storage,proto3" json:"storage,omitempty"`
+	Network              []*ContainerNetworkStatistics        `protobuf:"bytes,7,rep,name=network,proto3" json:"network,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                             `json:"-"`
+	XXX_unrecognized     []byte                               `json:"-"`
+	XXX_sizecache        int32                                `json:"-"`
+}
+
+func (m *WindowsContainerStatistics) Reset()      { *m = WindowsContainerStatistics{} }
+func (*WindowsContainerStatistics) ProtoMessage() {}
+func (*WindowsContainerStatistics) Descriptor() ([]byte, []int) {
+	return fileDescriptor_23217f96da3a05cc, []int{1}
+}
+func (m *WindowsContainerStatistics) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *WindowsContainerStatistics) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_WindowsContainerStatistics.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *WindowsContainerStatistics) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WindowsContainerStatistics.Merge(m, src)
+}
+func (m *WindowsContainerStatistics) XXX_Size() int {
+	return m.Size()
+}
+func (m *WindowsContainerStatistics) XXX_DiscardUnknown() {
+	xxx_messageInfo_WindowsContainerStatistics.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WindowsContainerStatistics proto.InternalMessageInfo
+
+type WindowsContainerProcessorStatistics struct {
+	TotalRuntimeNS       uint64   `protobuf:"varint,1,opt,name=total_runtime_ns,json=totalRuntimeNs,proto3" json:"total_runtime_ns,omitempty"`
+	RuntimeUserNS        uint64   `protobuf:"varint,2,opt,name=runtime_user_ns,json=runtimeUserNs,proto3" json:"runtime_user_ns,omitempty"`
+	RuntimeKernelNS      uint64   `protobuf:"varint,3,opt,name=runtime_kernel_ns,json=runtimeKernelNs,proto3" json:"runtime_kernel_ns,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WindowsContainerProcessorStatistics) Reset()      { *m = WindowsContainerProcessorStatistics{} }
+func (*WindowsContainerProcessorStatistics) ProtoMessage() {}
+func (*WindowsContainerProcessorStatistics) Descriptor() ([]byte, []int) {
+	return fileDescriptor_23217f96da3a05cc, []int{2}
+}
+func (m *WindowsContainerProcessorStatistics) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *WindowsContainerProcessorStatistics) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_WindowsContainerProcessorStatistics.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *WindowsContainerProcessorStatistics) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WindowsContainerProcessorStatistics.Merge(m, src)
+}
+func (m *WindowsContainerProcessorStatistics) XXX_Size() int {
+	return m.Size()
+}
+func (m *WindowsContainerProcessorStatistics) XXX_DiscardUnknown() {
+	xxx_messageInfo_WindowsContainerProcessorStatistics.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WindowsContainerProcessorStatistics proto.InternalMessageInfo
+
+type WindowsContainerMemoryStatistics struct {
+	MemoryUsageCommitBytes            uint64   `protobuf:"varint,1,opt,name=memory_usage_commit_bytes,json=memoryUsageCommitBytes,proto3" json:"memory_usage_commit_bytes,omitempty"`
+	MemoryUsageCommitPeakBytes        uint64   `protobuf:"varint,2,opt,name=memory_usage_commit_peak_bytes,json=memoryUsageCommitPeakBytes,proto3" json:"memory_usage_commit_peak_bytes,omitempty"`
+	MemoryUsagePrivateWorkingSetBytes uint64   `protobuf:"varint,3,opt,name=memory_usage_private_working_set_bytes,json=memoryUsagePrivateWorkingSetBytes,proto3" json:"memory_usage_private_working_set_bytes,omitempty"`
+	XXX_NoUnkeyedLiteral              struct{} `json:"-"`
+	XXX_unrecognized                  []byte   `json:"-"`
+	XXX_sizecache                     int32    `json:"-"`
+}
+
+func (m *WindowsContainerMemoryStatistics) Reset()      { *m = WindowsContainerMemoryStatistics{} }
+func (*WindowsContainerMemoryStatistics) ProtoMessage() {}
+func (*WindowsContainerMemoryStatistics) Descriptor() ([]byte, []int) {
+	return fileDescriptor_23217f96da3a05cc, []int{3}
+}
+func (m *WindowsContainerMemoryStatistics) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *WindowsContainerMemoryStatistics) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_WindowsContainerMemoryStatistics.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *WindowsContainerMemoryStatistics) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WindowsContainerMemoryStatistics.Merge(m, src)
+}
+func (m *WindowsContainerMemoryStatistics) XXX_Size() int {
+	return m.Size()
+}
+func (m *WindowsContainerMemoryStatistics) XXX_DiscardUnknown() {
+	xxx_messageInfo_WindowsContainerMemoryStatistics.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WindowsContainerMemoryStatistics proto.InternalMessageInfo
+
+type WindowsContainerStorageStatistics struct {
+	ReadCountNormalized  uint64   `protobuf:"varint,1,opt,name=read_count_normalized,json=readCountNormalized,proto3" json:"read_count_normalized,omitempty"`
+	ReadSizeBytes        uint64   `protobuf:"varint,2,opt,name=read_size_bytes,json=readSizeBytes,proto3" json:"read_size_bytes,omitempty"`
+	WriteCountNormalized uint64   `protobuf:"varint,3,opt,name=write_count_normalized,json=writeCountNormalized,proto3" json:"write_count_normalized,omitempty"`
+	WriteSizeBytes       uint64   `protobuf:"varint,4,opt,name=write_size_bytes,json=writeSizeBytes,proto3" json:"write_size_bytes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WindowsContainerStorageStatistics) Reset()      { *m = WindowsContainerStorageStatistics{} }
+func (*WindowsContainerStorageStatistics) ProtoMessage() {}
+func (*WindowsContainerStorageStatistics) Descriptor() ([]byte, []int) {
+	return fileDescriptor_23217f96da3a05cc, []int{4}
+}
+func (m *WindowsContainerStorageStatistics) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *WindowsContainerStorageStatistics) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_WindowsContainerStorageStatistics.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *WindowsContainerStorageStatistics) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WindowsContainerStorageStatistics.Merge(m, src)
+}
+func (m *WindowsContainerStorageStatistics) XXX_Size() int {
+	return m.Size()
+}
+func (m *WindowsContainerStorageStatistics) XXX_DiscardUnknown() {
+	xxx_messageInfo_WindowsContainerStorageStatistics.DiscardUnknown(m)
 }
 
-func (m *WindowsContainerStatistics) Reset()                    { *m = WindowsContainerStatistics{} }
-func (*WindowsContainerStatistics) ProtoMessage()               {}
-func (*WindowsContainerStatistics) Descriptor() ([]byte, []int) { return fileDescriptorStats, []int{1} }
+var xxx_messageInfo_WindowsContainerStorageStatistics proto.InternalMessageInfo
 
+// LinuxContainerStatistics are the cgroup statistics of a Linux container,
+// queried from the guest.
 type LinuxContainerStatistics struct {
+	// timestamp is when the statistics were queried.
+	Timestamp            time.Time                       `protobuf:"bytes,1,opt,name=timestamp,proto3,stdtime" json:"timestamp"`
+	CPU                  *LinuxContainerCPUStatistics    `protobuf:"bytes,3,opt,name=cpu,proto3" json:"cpu,omitempty"`
+	Memory               *LinuxContainerMemoryStatistics `protobuf:"bytes,4,opt,name=memory,proto3" json:"memory,omitempty"`
+	Blkio                *LinuxContainerBlkioStatistics  `protobuf:"bytes,5,opt,name=blkio,proto3" json:"blkio,omitempty"`
+	Pids                 *LinuxContainerPidsStatistics   `protobuf:"bytes,7,opt,name=pids,proto3" json:"pids,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                        `json:"-"`
+	XXX_unrecognized     []byte                          `json:"-"`
+	XXX_sizecache        int32                           `json:"-"`
+}
+
+func (m *LinuxContainerStatistics) Reset()      { *m = LinuxContainerStatistics{} }
+func (*LinuxContainerStatistics) ProtoMessage() {}
+func (*LinuxContainerStatistics) Descriptor() ([]byte, []int) {
+	return fileDescriptor_23217f96da3a05cc, []int{5}
+}
+func (m *LinuxContainerStatistics) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *LinuxContainerStatistics) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_LinuxContainerStatistics.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *LinuxContainerStatistics) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LinuxContainerStatistics.Merge(m, src)
+}
+func (m *LinuxContainerStatistics) XXX_Size() int {
+	return m.Size()
+}
+func (m *LinuxContainerStatistics) XXX_DiscardUnknown() {
+	xxx_messageInfo_LinuxContainerStatistics.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LinuxContainerStatistics proto.InternalMessageInfo
+
+type LinuxContainerCPUStatistics struct {
+	UsageTotalNS         uint64   `protobuf:"varint,1,opt,name=usage_total_ns,json=usageTotalNs,proto3" json:"usage_total_ns,omitempty"`
+	UsageUserNS          uint64   `protobuf:"varint,2,opt,name=usage_user_ns,json=usageUserNs,proto3" json:"usage_user_ns,omitempty"`
+	UsageKernelNS        uint64   `protobuf:"varint,3,opt,name=usage_kernel_ns,json=usageKernelNs,proto3" json:"usage_kernel_ns,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LinuxContainerCPUStatistics) Reset()      { *m = LinuxContainerCPUStatistics{} }
+func (*LinuxContainerCPUStatistics) ProtoMessage() {}
+func (*LinuxContainerCPUStatistics) Descriptor() ([]byte, []int) {
+	return fileDescriptor_23217f96da3a05cc, []int{6}
+}
+func (m *LinuxContainerCPUStatistics) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *LinuxContainerCPUStatistics) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_LinuxContainerCPUStatistics.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *LinuxContainerCPUStatistics) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LinuxContainerCPUStatistics.Merge(m, src)
+}
+func (m *LinuxContainerCPUStatistics) XXX_Size() int {
+	return m.Size()
+}
+func (m *LinuxContainerCPUStatistics) XXX_DiscardUnknown() {
+	xxx_messageInfo_LinuxContainerCPUStatistics.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LinuxContainerCPUStatistics proto.InternalMessageInfo
+
+type LinuxContainerMemoryStatistics struct {
+	UsageBytes    uint64 `protobuf:"varint,1,opt,name=usage_bytes,json=usageBytes,proto3" json:"usage_bytes,omitempty"`
+	MaxUsageBytes uint64 `protobuf:"varint,2,opt,name=max_usage_bytes,json=maxUsageBytes,proto3" json:"max_usage_bytes,omitempty"`
+	// working_set_bytes is the usage without the inactive file cache.
+	WorkingSetBytes      uint64   `protobuf:"varint,3,opt,name=working_set_bytes,json=workingSetBytes,proto3" json:"working_set_bytes,omitempty"`
+	LimitBytes           uint64   `protobuf:"varint,4,opt,name=limit_bytes,json=limitBytes,proto3" json:"limit_bytes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LinuxContainerMemoryStatistics) Reset()      { *m = LinuxContainerMemoryStatistics{} }
+func (*LinuxContainerMemoryStatistics) ProtoMessage() {}
+func (*LinuxContainerMemoryStatistics) Descriptor() ([]byte, []int) {
+	return fileDescriptor_23217f96da3a05cc, []int{7}
+}
+func (m *LinuxContainerMemoryStatistics) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *LinuxContainerMemoryStatistics) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_LinuxContainerMemoryStatistics.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *LinuxContainerMemoryStatistics) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LinuxContainerMemoryStatistics.Merge(m, src)
+}
+func (m *LinuxContainerMemoryStatistics) XXX_Size() int {
+	return m.Size()
+}
+func (m *LinuxContainerMemoryStatistics) XXX_DiscardUnknown() {
+	xxx_messageInfo_LinuxContainerMemoryStatistics.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LinuxContainerMemoryStatistics proto.InternalMessageInfo
+
+type LinuxContainerBlkioStatistics struct {
+	ReadOps              uint64   `protobuf:"varint,1,opt,name=read_ops,json=readOps,proto3" json:"read_ops,omitempty"`
+	ReadBytes            uint64   `protobuf:"varint,2,opt,name=read_bytes,json=readBytes,proto3" json:"read_bytes,omitempty"`
+	WriteOps             uint64   `protobuf:"varint,3,opt,name=write_ops,json=writeOps,proto3" json:"write_ops,omitempty"`
+	WriteBytes           uint64   `protobuf:"varint,4,opt,name=write_bytes,json=writeBytes,proto3" json:"write_bytes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LinuxContainerBlkioStatistics) Reset()      { *m = LinuxContainerBlkioStatistics{} }
+func (*LinuxContainerBlkioStatistics) ProtoMessage() {}
+func (*LinuxContainerBlkioStatistics) Descriptor() ([]byte, []int) {
+	return fileDescriptor_23217f96da3a05cc, []int{8}
+}
+func (m *LinuxContainerBlkioStatistics) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *LinuxContainerBlkioStatistics) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_LinuxContainerBlkioStatistics.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *LinuxContainerBlkioStatistics) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LinuxContainerBlkioStatistics.Merge(m, src)
+}
+func (m *LinuxContainerBlkioStatistics) XXX_Size() int {
+	return m.Size()
+}
+func (m *LinuxContainerBlkioStatistics) XXX_DiscardUnknown() {
+	xxx_messageInfo_LinuxContainerBlkioStatistics.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LinuxContainerBlkioStatistics proto.InternalMessageInfo
+
+type LinuxContainerPidsStatistics struct {
+	Current              uint64   `protobuf:"varint,1,opt,name=current,proto3" json:"current,omitempty"`
+	Limit                uint64   `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LinuxContainerPidsStatistics) Reset()      { *m = LinuxContainerPidsStatistics{} }
+func (*LinuxContainerPidsStatistics) ProtoMessage() {}
+func (*LinuxContainerPidsStatistics) Descriptor() ([]byte, []int) {
+	return fileDescriptor_23217f96da3a05cc, []int{9}
+}
+func (m *LinuxContainerPidsStatistics) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *LinuxContainerPidsStatistics) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_LinuxContainerPidsStatistics.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *LinuxContainerPidsStatistics) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LinuxContainerPidsStatistics.Merge(m, src)
+}
+func (m *LinuxContainerPidsStatistics) XXX_Size() int {
+	return m.Size()
+}
+func (m *LinuxContainerPidsStatistics) XXX_DiscardUnknown() {
+	xxx_messageInfo_LinuxContainerPidsStatistics.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LinuxContainerPidsStatistics proto.InternalMessageInfo
+
+type ContainerNetworkStatistics struct {
+	EndpointID             string   `protobuf:"bytes,1,opt,name=endpoint_id,json=endpointId,proto3" json:"endpoint_id,omitempty"`
+	InstanceID             string   `protobuf:"bytes,2,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
+	BytesReceived          uint64   `protobuf:"varint,3,opt,name=bytes_received,json=bytesReceived,proto3" json:"bytes_received,omitempty"`
+	BytesSent              uint64   `protobuf:"varint,4,opt,name=bytes_sent,json=bytesSent,proto3" json:"bytes_sent,omitempty"`
+	PacketsReceived        uint64   `protobuf:"varint,5,opt,name=packets_received,json=packetsReceived,proto3" json:"packets_received,omitempty"`
+	PacketsSent            uint64   `protobuf:"varint,6,opt,name=packets_sent,json=packetsSent,proto3" json:"packets_sent,omitempty"`
+	DroppedPacketsIncoming uint64   `protobuf:"varint,7,opt,name=dropped_packets_incoming,json=droppedPacketsIncoming,proto3" json:"dropped_packets_incoming,omitempty"`
+	DroppedPacketsOutgoing uint64   `protobuf:"varint,8,opt,name=dropped_packets_outgoing,json=droppedPacketsOutgoing,proto3" json:"dropped_packets_outgoing,omitempty"`
+	XXX_NoUnkeyedLiteral   struct{} `json:"-"`
+	XXX_unrecognized       []byte   `json:"-"`
+	XXX_sizecache          int32    `json:"-"`
 }
 
-func (m *LinuxContainerStatistics) Reset()                    { *m = LinuxContainerStatistics{} }
-func (*LinuxContainerStatistics) ProtoMessage()               {}
-func (*LinuxContainerStatistics) Descriptor() ([]byte, []int) { return fileDescriptorStats, []int{2} }
+func (m *ContainerNetworkStatistics) Reset()      { *m = ContainerNetworkStatistics{} }
+func (*ContainerNetworkStatistics) ProtoMessage() {}
+func (*ContainerNetworkStatistics) Descriptor() ([]byte, []int) {
+	return fileDescriptor_23217f96da3a05cc, []int{10}
+}
+func (m *ContainerNetworkStatistics) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ContainerNetworkStatistics) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ContainerNetworkStatistics.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ContainerNetworkStatistics) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ContainerNetworkStatistics.Merge(m, src)
+}
+func (m *ContainerNetworkStatistics) XXX_Size() int {
+	return m.Size()
+}
+func (m *ContainerNetworkStatistics) XXX_DiscardUnknown() {
+	xxx_messageInfo_ContainerNetworkStatistics.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ContainerNetworkStatistics proto.InternalMessageInfo
 
 type VirtualMachineStatistics struct {
-	Processor *VirtualMachineProcessorStatistics `protobuf:"bytes,1,opt,name=processor" json:"processor,omitempty"`
-	Memory    *VirtualMachineMemoryStatistics    `protobuf:"bytes,2,opt,name=memory" json:"memory,omitempty"`
+	Processor            *VirtualMachineProcessorStatistics `protobuf:"bytes,1,opt,name=processor,proto3" json:"processor,omitempty"`
+	Memory               *VirtualMachineMemoryStatistics    `protobuf:"bytes,2,opt,name=memory,proto3" json:"memory,omitempty"`
+	Boot                 *VirtualMachineBootStatistics      `protobuf:"bytes,3,opt,name=boot,proto3" json:"boot,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                           `json:"-"`
+	XXX_unrecognized     []byte                             `json:"-"`
+	XXX_sizecache        int32                              `json:"-"`
+}
+
+func (m *VirtualMachineStatistics) Reset()      { *m = VirtualMachineStatistics{} }
+func (*VirtualMachineStatistics) ProtoMessage() {}
+func (*VirtualMachineStatistics) Descriptor() ([]byte, []int) {
+	return fileDescriptor_23217f96da3a05cc, []int{11}
+}
+func (m *VirtualMachineStatistics) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *VirtualMachineStatistics) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_VirtualMachineStatistics.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *VirtualMachineStatistics) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_VirtualMachineStatistics.Merge(m, src)
+}
+func (m *VirtualMachineStatistics) XXX_Size() int {
+	return m.Size()
+}
+func (m *VirtualMachineStatistics) XXX_DiscardUnknown() {
+	xxx_messageInfo_VirtualMachineStatistics.DiscardUnknown(m)
 }
 
-func (m *VirtualMachineStatistics) Reset()                    { *m = VirtualMachineStatistics{} }
-func (*VirtualMachineStatistics) ProtoMessage()               {}
-func (*VirtualMachineStatistics) Descriptor() ([]byte, []int) { return fileDescriptorStats, []int{3} }
+var xxx_messageInfo_VirtualMachineStatistics proto.InternalMessageInfo
 
 type VirtualMachineProcessorStatistics struct {
-	TotalRuntimeNS uint64 `protobuf:"varint,1,opt,name=total_runtime_ns,json=totalRuntimeNs,proto3" json:"total_runtime_ns,omitempty"`
+	TotalRuntimeNS       uint64   `protobuf:"varint,1,opt,name=total_runtime_ns,json=totalRuntimeNs,proto3" json:"total_runtime_ns,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *VirtualMachineProcessorStatistics) Reset()      { *m = VirtualMachineProcessorStatistics{} }
 func (*VirtualMachineProcessorStatistics) ProtoMessage() {}
 func (*VirtualMachineProcessorStatistics) Descriptor() ([]byte, []int) {
-	return fileDescriptorStats, []int{4}
+	return fileDescriptor_23217f96da3a05cc, []int{12}
+}
+func (m *VirtualMachineProcessorStatistics) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *VirtualMachineProcessorStatistics) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_VirtualMachineProcessorStatistics.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *VirtualMachineProcessorStatistics) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_VirtualMachineProcessorStatistics.Merge(m, src)
+}
+func (m *VirtualMachineProcessorStatistics) XXX_Size() int {
+	return m.Size()
+}
+func (m *VirtualMachineProcessorStatistics) XXX_DiscardUnknown() {
+	xxx_messageInfo_VirtualMachineProcessorStatistics.DiscardUnknown(m)
 }
 
+var xxx_messageInfo_VirtualMachineProcessorStatistics proto.InternalMessageInfo
+
 type VirtualMachineMemoryStatistics struct {
+	// working_set_bytes is the working set of the vmmem process of the VM.
 	WorkingSetBytes uint64 `protobuf:"varint,1,opt,name=working_set_bytes,json=workingSetBytes,proto3" json:"working_set_bytes,omitempty"`
+	// guest_available_bytes is the memory a Linux guest reports as available,
+	// sampled at most every 30 seconds. It is the view of the guest and is not
+	// part of the working set.
+	GuestAvailableBytes uint64 `protobuf:"varint,2,opt,name=guest_available_bytes,json=guestAvailableBytes,proto3" json:"guest_available_bytes,omitempty"`
+	// The working set of a VM backed by virtual memory is trimmed when it is
+	// idle, if its policy says so. A trim does not change the memory of the
+	// guest, which pages the trimmed memory back in as it uses it.
+	TrimmedBytes uint64 `protobuf:"varint,3,opt,name=trimmed_bytes,json=trimmedBytes,proto3" json:"trimmed_bytes,omitempty"`
+	Trims        uint64 `protobuf:"varint,4,opt,name=trims,proto3" json:"trims,omitempty"`
+	// assigned_bytes is the memory the host has assigned to the VM.
+	AssignedBytes uint64 `protobuf:"varint,5,opt,name=assigned_bytes,json=assignedBytes,proto3" json:"assigned_bytes,omitempty"`
+	// balancing_enabled is whether the host balances the memory of the VM
+	// with dynamic memory.
+	BalancingEnabled bool `protobuf:"varint,6,opt,name=balancing_enabled,json=balancingEnabled,proto3" json:"balancing_enabled,omitempty"`
+	// balancing_in_progress is whether the host is adding or removing memory.
+	BalancingInProgress bool `protobuf:"varint,7,opt,name=balancing_in_progress,json=balancingInProgress,proto3" json:"balancing_in_progress,omitempty"`
+	// idle is whether the VM is idle according to its working set trim
+	// policy.
+	Idle                 bool      `protobuf:"varint,8,opt,name=idle,proto3" json:"idle,omitempty"`
+	LastTrim             time.Time `protobuf:"bytes,9,opt,name=last_trim,json=lastTrim,proto3,stdtime" json:"last_trim"`
+	LastTrimmedBytes     uint64    `protobuf:"varint,10,opt,name=last_trimmed_bytes,json=lastTrimmedBytes,proto3" json:"last_trimmed_bytes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
 }
 
 func (m *VirtualMachineMemoryStatistics) Reset()      { *m = VirtualMachineMemoryStatistics{} }
 func (*VirtualMachineMemoryStatistics) ProtoMessage() {}
 func (*VirtualMachineMemoryStatistics) Descriptor() ([]byte, []int) {
-	return fileDescriptorStats, []int{5}
+	return fileDescriptor_23217f96da3a05cc, []int{13}
+}
+func (m *VirtualMachineMemoryStatistics) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *VirtualMachineMemoryStatistics) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_VirtualMachineMemoryStatistics.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *VirtualMachineMemoryStatistics) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_VirtualMachineMemoryStatistics.Merge(m, src)
+}
+func (m *VirtualMachineMemoryStatistics) XXX_Size() int {
+	return m.Size()
+}
+func (m *VirtualMachineMemoryStatistics) XXX_DiscardUnknown() {
+	xxx_messageInfo_VirtualMachineMemoryStatistics.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_VirtualMachineMemoryStatistics proto.InternalMessageInfo
+
+type VirtualMachineBootStatistics struct {
+	BuildDocumentNS      uint64   `protobuf:"varint,1,opt,name=build_document_ns,json=buildDocumentNs,proto3" json:"build_document_ns,omitempty"`
+	HCSCreateNS          uint64   `protobuf:"varint,2,opt,name=hcs_create_ns,json=hcsCreateNs,proto3" json:"hcs_create_ns,omitempty"`
+	HCSStartNS           uint64   `protobuf:"varint,3,opt,name=hcs_start_ns,json=hcsStartNs,proto3" json:"hcs_start_ns,omitempty"`
+	GuestConnectNS       uint64   `protobuf:"varint,4,opt,name=guest_connect_ns,json=guestConnectNs,proto3" json:"guest_connect_ns,omitempty"`
+	GCSNegotiateNS       uint64   `protobuf:"varint,5,opt,name=gcs_negotiate_ns,json=gcsNegotiateNs,proto3" json:"gcs_negotiate_ns,omitempty"`
+	FirstContainerNS     uint64   `protobuf:"varint,6,opt,name=first_container_ns,json=firstContainerNs,proto3" json:"first_container_ns,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *VirtualMachineBootStatistics) Reset()      { *m = VirtualMachineBootStatistics{} }
+func (*VirtualMachineBootStatistics) ProtoMessage() {}
+func (*VirtualMachineBootStatistics) Descriptor() ([]byte, []int) {
+	return fileDescriptor_23217f96da3a05cc, []int{14}
+}
+func (m *VirtualMachineBootStatistics) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *VirtualMachineBootStatistics) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_VirtualMachineBootStatistics.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *VirtualMachineBootStatistics) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_VirtualMachineBootStatistics.Merge(m, src)
+}
+func (m *VirtualMachineBootStatistics) XXX_Size() int {
+	return m.Size()
+}
+func (m *VirtualMachineBootStatistics) XXX_DiscardUnknown() {
+	xxx_messageInfo_VirtualMachineBootStatistics.DiscardUnknown(m)
 }
 
+var xxx_messageInfo_VirtualMachineBootStatistics proto.InternalMessageInfo
+
 func init() {
 	proto.RegisterType((*Statistics)(nil), "containerd.runhcs.stats.v1.Statistics")
 	proto.RegisterType((*WindowsContainerStatistics)(nil), "containerd.runhcs.stats.v1.WindowsContainerStatistics")
+	proto.RegisterType((*WindowsContainerProcessorStatistics)(nil), "containerd.runhcs.stats.v1.WindowsContainerProcessorStatistics")
+	proto.RegisterType((*WindowsContainerMemoryStatistics)(nil), "containerd.runhcs.stats.v1.WindowsContainerMemoryStatistics")
+	proto.RegisterType((*WindowsContainerStorageStatistics)(nil), "containerd.runhcs.stats.v1.WindowsContainerStorageStatistics")
 	proto.RegisterType((*LinuxContainerStatistics)(nil), "containerd.runhcs.stats.v1.LinuxContainerStatistics")
+	proto.RegisterType((*LinuxContainerCPUStatistics)(nil), "containerd.runhcs.stats.v1.LinuxContainerCPUStatistics")
+	proto.RegisterType((*LinuxContainerMemoryStatistics)(nil), "containerd.runhcs.stats.v1.LinuxContainerMemoryStatistics")
+	proto.RegisterType((*LinuxContainerBlkioStatistics)(nil), "containerd.runhcs.stats.v1.LinuxContainerBlkioStatistics")
+	proto.RegisterType((*LinuxContainerPidsStatistics)(nil), "containerd.runhcs.stats.v1.LinuxContainerPidsStatistics")
+	proto.RegisterType((*ContainerNetworkStatistics)(nil), "containerd.runhcs.stats.v1.ContainerNetworkStatistics")
 	proto.RegisterType((*VirtualMachineStatistics)(nil), "containerd.runhcs.stats.v1.VirtualMachineStatistics")
 	proto.RegisterType((*VirtualMachineProcessorStatistics)(nil), "containerd.runhcs.stats.v1.VirtualMachineProcessorStatistics")
 	proto.RegisterType((*VirtualMachineMemoryStatistics)(nil), "containerd.runhcs.stats.v1.VirtualMachineMemoryStatistics")
+	proto.RegisterType((*VirtualMachineBootStatistics)(nil), "containerd.runhcs.stats.v1.VirtualMachineBootStatistics")
+}
+
+func init() {
+	proto.RegisterFile("github.com/Microsoft/hcsshim/cmd/containerd-shim-runhcs-v1/stats/stats.proto", fileDescriptor_23217f96da3a05cc)
+}
+
+var fileDescriptor_23217f96da3a05cc = []byte{
+	// 1617 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x58, 0xdd, 0x6e, 0x1b, 0xb9,
+	0x15, 0xb6, 0x7e, 0x6c, 0x49, 0xc7, 0x96, 0x2d, 0xd3, 0xde, 0x40, 0xeb, 0xdd, 0x95, 0x62, 0x2d,
+	0x1a, 0xec, 0xb6, 0x8d, 0xd4, 0xcd, 0x2e, 0xd2, 0xa4, 0x4d, 0x11, 0x44, 0x4a, 0xd2, 0x18, 0xb5,
+	0x15, 0x75, 0x64, 0x27, 0x45, 0x8b, 0x62, 0x3a, 0x9a, 0xa1, 0x25, 0xc2, 0x9a, 0xe1, 0x80, 0xe4,
+	0xd8, 0x49, 0xae, 0x7a, 0x55, 0xa0, 0x77, 0xbd, 0x2b, 0xfa, 0x00, 0x7d, 0x85, 0xbe, 0x40, 0x51,
+	0xc0, 0x97, 0xbd, 0xec, 0x95, 0xd2, 0xe8, 0x19, 0xfa, 0x00, 0x05, 0xc9, 0xf9, 0x53, 0x62, 0x59,
+	0x36, 0xb2, 0x37, 0x86, 0x78, 0xbe, 0xf3, 0x7d, 0xc3, 0x43, 0x1e, 0x7e, 0xc3, 0x31, 0xec, 0x0f,
+	0x89, 0x18, 0x05, 0x83, 0xa6, 0x4d, 0xdd, 0xd6, 0x01, 0xb1, 0x19, 0xe5, 0xf4, 0x58, 0xb4, 0x46,
+	0x36, 0xe7, 0x23, 0xe2, 0xb6, 0x6c, 0xd7, 0x69, 0xd9, 0xd4, 0x13, 0x16, 0xf1, 0x30, 0x73, 0x6e,
+	0xcb, 0xd8, 0x6d, 0x16, 0x78, 0x23, 0x9b, 0xdf, 0x3e, 0xfd, 0xa6, 0xc5, 0x85, 0x25, 0xb8, 0xfe,
+	0xdb, 0xf4, 0x19, 0x15, 0x14, 0xed, 0x24, 0xc9, 0x4d, 0x9d, 0xd7, 0xd4, 0xf0, 0xe9, 0x37, 0x3b,
+	0xdb, 0x43, 0x3a, 0xa4, 0x2a, 0xad, 0x25, 0x7f, 0x69, 0xc6, 0x4e, 0x7d, 0x48, 0xe9, 0x70, 0x8c,
+	0x5b, 0x6a, 0x34, 0x08, 0x8e, 0x5b, 0x82, 0xb8, 0x98, 0x0b, 0xcb, 0xf5, 0x75, 0x42, 0xe3, 0xcf,
+	0x59, 0x80, 0xbe, 0xb0, 0x04, 0xe1, 0x82, 0xd8, 0x1c, 0x19, 0x50, 0x38, 0x23, 0x9e, 0x43, 0xcf,
+	0x78, 0x35, 0x73, 0x33, 0xf3, 0xd5, 0xea, 0x9d, 0xbb, 0xcd, 0xf9, 0xcf, 0x6c, 0xbe, 0xd4, 0xa9,
+	0x9d, 0x28, 0x23, 0x11, 0x7a, 0xb6, 0x64, 0x44, 0x42, 0x68, 0x1f, 0x96, 0xc7, 0xc4, 0x0b, 0x5e,
+	0x55, 0xb3, 0x4a, 0xf1, 0xbb, 0xcb, 0x14, 0xf7, 0x65, 0xe2, 0xc5, 0x7a, 0x5a, 0x04, 0xed, 0x43,
+	0xf6, 0xd4, 0xad, 0xe6, 0x16, 0x4b, 0xbd, 0x20, 0x4c, 0x04, 0xd6, 0xf8, 0xc0, 0xb2, 0x47, 0xc4,
+	0xc3, 0x89, 0x54, 0x7b, 0x65, 0x3a, 0xa9, 0x67, 0x5f, 0x1c, 0x18, 0xd9, 0x53, 0xb7, 0xbd, 0x0a,
+	0xa5, 0x58, 0xa2, 0xf1, 0xcf, 0x3c, 0xec, 0xcc, 0x2f, 0x09, 0xb5, 0xa1, 0x14, 0xaf, 0x5e, 0xb8,
+	0x3a, 0x3b, 0x4d, 0xbd, 0xbe, 0xcd, 0x68, 0x7d, 0x9b, 0x87, 0x51, 0x46, 0xbb, 0x78, 0x3e, 0xa9,
+	0x2f, 0xfd, 0xe5, 0x6d, 0x3d, 0x63, 0x24, 0x34, 0xf4, 0x02, 0xb6, 0xe3, 0xe7, 0x99, 0x5c, 0x58,
+	0x4c, 0x98, 0x12, 0xac, 0x66, 0xaf, 0x21, 0x87, 0xec, 0xd4, 0xe4, 0x98, 0x90, 0x29, 0xe8, 0x6b,
+	0x28, 0x05, 0xbe, 0x54, 0x32, 0x3d, 0xae, 0x16, 0x27, 0xdf, 0x5e, 0x9b, 0x4e, 0xea, 0xc5, 0x23,
+	0x15, 0xec, 0xf6, 0x8d, 0xa2, 0x86, 0xbb, 0x1c, 0xfd, 0x1e, 0x4a, 0x3e, 0xa3, 0x36, 0xe6, 0x9c,
+	0xb2, 0x6a, 0x5e, 0x3d, 0xf7, 0xe1, 0x75, 0x36, 0xb9, 0x17, 0x91, 0x93, 0xa5, 0x31, 0x12, 0x45,
+	0x74, 0x08, 0x2b, 0x2e, 0x76, 0x29, 0x7b, 0x5d, 0x5d, 0x56, 0xda, 0x0f, 0xae, 0xa3, 0x7d, 0xa0,
+	0x98, 0x29, 0xe1, 0x50, 0x0b, 0xbd, 0x84, 0x02, 0x17, 0x94, 0x59, 0x43, 0x5c, 0x5d, 0x51, 0xb2,
+	0xbf, 0xb8, 0x5e, 0x5f, 0x2a, 0x6a, 0x4a, 0x37, 0x52, 0x43, 0x3d, 0x28, 0x78, 0x58, 0x9c, 0x51,
+	0x76, 0x52, 0x2d, 0xdc, 0xcc, 0x2d, 0x6a, 0xf8, 0x58, 0xb1, 0xab, 0x39, 0x69, 0xc5, 0x50, 0xa6,
+	0xf1, 0x36, 0x03, 0x5f, 0x5e, 0x61, 0xcd, 0xd0, 0x03, 0xa8, 0x08, 0x2a, 0xac, 0xb1, 0xc9, 0x02,
+	0x2f, 0xda, 0xb9, 0x8c, 0xda, 0x39, 0x34, 0x9d, 0xd4, 0xd7, 0x0f, 0x25, 0x66, 0x68, 0xa8, 0xdb,
+	0x37, 0xd6, 0x45, 0x7a, 0xcc, 0xd1, 0x7d, 0xd8, 0x88, 0x78, 0x01, 0xc7, 0x4c, 0x92, 0xb3, 0x8a,
+	0xbc, 0x39, 0x9d, 0xd4, 0xcb, 0x61, 0xde, 0x11, 0xc7, 0xac, 0xdb, 0x37, 0xca, 0x2c, 0x35, 0xe4,
+	0xe8, 0x21, 0x6c, 0x46, 0xd4, 0x13, 0xcc, 0x3c, 0x3c, 0x4e, 0x7a, 0x66, 0x6b, 0x3a, 0xa9, 0x6f,
+	0x84, 0xe4, 0x5f, 0x29, 0xac, 0xdb, 0x37, 0x36, 0xd8, 0x4c, 0x80, 0x37, 0xfe, 0x97, 0x81, 0x9b,
+	0x8b, 0x76, 0x0e, 0xdd, 0x87, 0x4f, 0xf5, 0xde, 0x99, 0x01, 0xb7, 0x86, 0xd8, 0xb4, 0xa9, 0xeb,
+	0x12, 0x61, 0x0e, 0x5e, 0x0b, 0x1c, 0xd6, 0x69, 0xdc, 0xd0, 0x09, 0x47, 0x12, 0xef, 0x28, 0xb8,
+	0x2d, 0x51, 0xd4, 0x86, 0xda, 0x45, 0x54, 0x1f, 0x5b, 0x27, 0x21, 0x5f, 0x95, 0x6a, 0xec, 0x7c,
+	0xc0, 0xef, 0x61, 0xeb, 0x44, 0x6b, 0xfc, 0x1a, 0x6e, 0xcd, 0x68, 0xf8, 0x8c, 0x9c, 0x5a, 0x02,
+	0x9b, 0x72, 0x8b, 0x88, 0x37, 0x34, 0x39, 0x8e, 0xe6, 0xa2, 0x2a, 0x37, 0x76, 0x53, 0x5a, 0x3d,
+	0x9d, 0xfb, 0x52, 0xa7, 0xf6, 0xb1, 0x9e, 0x96, 0xdc, 0xd8, 0xdd, 0x85, 0x9d, 0x85, 0xee, 0xc0,
+	0x27, 0x0c, 0x5b, 0x8e, 0x69, 0xd3, 0xc0, 0x13, 0xa6, 0x47, 0x99, 0x6b, 0x8d, 0xc9, 0x1b, 0xec,
+	0x84, 0x35, 0x6f, 0x49, 0xb0, 0x23, 0xb1, 0x6e, 0x0c, 0xa1, 0x5b, 0xb0, 0xa1, 0x38, 0x9c, 0xbc,
+	0xc1, 0x33, 0x15, 0x96, 0x65, 0xb8, 0x4f, 0xde, 0x60, 0x5d, 0xd4, 0x77, 0x70, 0xe3, 0x8c, 0x11,
+	0x81, 0x3f, 0x14, 0xd7, 0x45, 0x6c, 0x2b, 0xf4, 0x7d, 0xf5, 0xaf, 0xa0, 0xa2, 0x59, 0x29, 0xf9,
+	0xbc, 0xca, 0x5f, 0x57, 0xf1, 0x58, 0xbf, 0xf1, 0xf7, 0x1c, 0x54, 0xe7, 0x39, 0xf0, 0xf7, 0x62,
+	0x7f, 0x06, 0xe4, 0x6c, 0x3f, 0x08, 0xdd, 0xfb, 0xa7, 0x57, 0x7f, 0x11, 0x74, 0x7a, 0x47, 0x29,
+	0x03, 0x2f, 0x4c, 0x27, 0xf5, 0x5c, 0xa7, 0x77, 0x64, 0x48, 0x31, 0x64, 0xc4, 0x86, 0xa3, 0xcd,
+	0xec, 0x67, 0x57, 0x97, 0x9d, 0x6b, 0x37, 0xcf, 0x61, 0x79, 0x30, 0x3e, 0x21, 0x34, 0xf4, 0xb0,
+	0xfb, 0x57, 0x97, 0x6c, 0x4b, 0x5a, 0x4a, 0x51, 0xeb, 0xa0, 0x7d, 0xc8, 0xfb, 0xc4, 0xe1, 0xd5,
+	0x82, 0xd2, 0xbb, 0x77, 0x75, 0xbd, 0x1e, 0x71, 0x78, 0x4a, 0x4e, 0xa9, 0x34, 0xfe, 0x95, 0x81,
+	0xcf, 0x2e, 0x59, 0x20, 0x74, 0x17, 0xd6, 0x75, 0xd7, 0x6b, 0x83, 0x89, 0x8d, 0xa5, 0x32, 0x9d,
+	0xd4, 0xd7, 0x54, 0x8b, 0x2b, 0x77, 0xe9, 0xf6, 0x8d, 0xb5, 0x20, 0x19, 0x71, 0xf4, 0x2d, 0x94,
+	0x35, 0x6f, 0xd6, 0x52, 0x36, 0xa6, 0x93, 0xfa, 0xaa, 0xa2, 0x85, 0x86, 0xb2, 0x1a, 0xc4, 0x03,
+	0xe5, 0x44, 0x9a, 0xf4, 0xbe, 0x99, 0x28, 0x27, 0x52, 0xb4, 0xd8, 0x4a, 0xca, 0x41, 0x6a, 0xc8,
+	0x1b, 0xff, 0xc8, 0x40, 0xed, 0xf2, 0x1d, 0x41, 0x75, 0xd0, 0x0f, 0x9b, 0x31, 0x0e, 0x50, 0x21,
+	0x7d, 0x26, 0x6e, 0xc1, 0x86, 0x6b, 0xbd, 0x32, 0xd3, 0x49, 0xe1, 0xd9, 0x71, 0xad, 0x57, 0x47,
+	0x49, 0xde, 0x0f, 0x61, 0x73, 0xde, 0xd9, 0xdf, 0x38, 0x9b, 0x3d, 0xe9, 0xf2, 0xa1, 0x63, 0x92,
+	0xb8, 0x95, 0x3e, 0x2c, 0xa0, 0x42, 0xfa, 0xa0, 0xfc, 0x35, 0x03, 0x5f, 0x5c, 0xba, 0xef, 0xe8,
+	0x53, 0x28, 0xaa, 0x23, 0x4d, 0xfd, 0x68, 0xd2, 0x05, 0x39, 0x7e, 0xee, 0x73, 0xf4, 0x05, 0x80,
+	0x82, 0xd2, 0x93, 0x2d, 0xc9, 0x88, 0x7e, 0xf8, 0x67, 0x50, 0xd2, 0xc7, 0x95, 0xfa, 0xd1, 0x04,
+	0x8b, 0x2a, 0x20, 0xb9, 0x75, 0x58, 0xd5, 0xe0, 0xcc, 0xcc, 0x54, 0x48, 0xcf, 0xac, 0x0b, 0x9f,
+	0x5f, 0xd6, 0x40, 0xa8, 0x0a, 0x05, 0x3b, 0x60, 0x0c, 0x7b, 0x22, 0x9a, 0x56, 0x38, 0x44, 0xdb,
+	0xf2, 0x9a, 0xe6, 0x12, 0x11, 0xce, 0x48, 0x0f, 0x1a, 0x7f, 0xca, 0xc1, 0xce, 0xfc, 0xb7, 0x1e,
+	0x6a, 0xc1, 0x2a, 0xf6, 0x1c, 0x9f, 0x12, 0x4f, 0x98, 0x44, 0x7b, 0x5c, 0xa9, 0xbd, 0x3e, 0x9d,
+	0xd4, 0xe1, 0x49, 0x18, 0xde, 0x7b, 0x6c, 0x40, 0x94, 0xb2, 0xe7, 0x48, 0x02, 0xf1, 0xb8, 0xb0,
+	0x3c, 0x1b, 0x4b, 0x42, 0x36, 0x21, 0xec, 0x85, 0x61, 0x49, 0x88, 0x52, 0xf6, 0x1c, 0xf4, 0x03,
+	0x58, 0x57, 0xb5, 0x9a, 0x0c, 0xdb, 0x98, 0x9c, 0xc6, 0x5e, 0x57, 0x56, 0x51, 0x23, 0x0c, 0xca,
+	0x45, 0xd5, 0x69, 0x5c, 0x96, 0xa6, 0xd7, 0xa5, 0xa4, 0x22, 0x7d, 0x59, 0xdc, 0xd7, 0x50, 0xf1,
+	0x2d, 0xfb, 0x04, 0x8b, 0x94, 0xce, 0xb2, 0xde, 0xfc, 0x30, 0x1e, 0x2b, 0xed, 0xc2, 0x5a, 0x94,
+	0xaa, 0xb4, 0x56, 0x54, 0xda, 0x6a, 0x18, 0x53, 0x6a, 0xf7, 0xa0, 0xea, 0x30, 0xea, 0xfb, 0xd8,
+	0x31, 0xa3, 0x54, 0xe2, 0xd9, 0xd4, 0x25, 0xde, 0x50, 0x9d, 0xf0, 0xbc, 0x71, 0x23, 0xc4, 0x7b,
+	0x1a, 0xde, 0x0b, 0xd1, 0x8b, 0x98, 0x34, 0x10, 0x43, 0x2a, 0x99, 0xc5, 0x8b, 0x98, 0xcf, 0x43,
+	0xb4, 0xf1, 0xb7, 0x2c, 0x54, 0xe7, 0x5d, 0x69, 0xd1, 0xef, 0xd2, 0x77, 0xba, 0xcc, 0xe2, 0x0b,
+	0xd2, 0xac, 0xd0, 0x82, 0x1b, 0x5d, 0x62, 0xb0, 0xd9, 0xc5, 0x06, 0x3b, 0xab, 0x3c, 0xd7, 0x60,
+	0xf7, 0x21, 0x3f, 0xa0, 0x54, 0x54, 0x73, 0x8b, 0xfd, 0x70, 0x56, 0xb1, 0x4d, 0xa9, 0x48, 0xfb,
+	0xa1, 0x54, 0x69, 0x58, 0xb0, 0xbb, 0xb0, 0xa2, 0x8f, 0xbb, 0x6f, 0x35, 0xce, 0x73, 0x50, 0xbb,
+	0xbc, 0xb6, 0x8b, 0x1d, 0x26, 0x73, 0xb1, 0xc3, 0xdc, 0x81, 0x4f, 0x86, 0x01, 0xe6, 0xc2, 0xb4,
+	0x4e, 0x2d, 0x32, 0xb6, 0x06, 0xe3, 0x59, 0xef, 0xda, 0x52, 0xe0, 0xa3, 0x08, 0xd3, 0x9c, 0x2f,
+	0xa1, 0x2c, 0x18, 0x71, 0x5d, 0xec, 0xcc, 0xb8, 0xd7, 0x5a, 0x18, 0xd4, 0x49, 0xdb, 0xb0, 0x2c,
+	0xc7, 0x91, 0x35, 0xe8, 0x81, 0x3c, 0x44, 0x16, 0xe7, 0x64, 0xe8, 0xc5, 0x5c, 0xdd, 0xfc, 0xe5,
+	0x28, 0xaa, 0xc9, 0x3f, 0x82, 0xcd, 0x81, 0x35, 0xb6, 0x3c, 0x5b, 0xd6, 0x80, 0x3d, 0xf9, 0x68,
+	0x47, 0xf5, 0x7f, 0xd1, 0xa8, 0xc4, 0xc0, 0x13, 0x1d, 0x97, 0x25, 0x24, 0xc9, 0xc4, 0x33, 0x7d,
+	0x46, 0x87, 0x0c, 0x73, 0xfd, 0x8e, 0x2b, 0x1a, 0x5b, 0x31, 0xb8, 0xe7, 0xf5, 0x42, 0x08, 0x21,
+	0xc8, 0x13, 0x67, 0x8c, 0x55, 0xab, 0x17, 0x0d, 0xf5, 0x1b, 0x3d, 0x82, 0xd2, 0xd8, 0xe2, 0xc2,
+	0x94, 0x33, 0xad, 0x96, 0xae, 0x71, 0xaf, 0x28, 0x4a, 0xda, 0x21, 0x23, 0x2e, 0xfa, 0x31, 0xa0,
+	0x58, 0x22, 0x59, 0x1e, 0x50, 0x25, 0x56, 0xa2, 0xac, 0x68, 0x89, 0xa4, 0xa5, 0x7d, 0x7e, 0x59,
+	0x53, 0xc9, 0x0b, 0xf2, 0x20, 0x20, 0x63, 0xc7, 0x74, 0xa8, 0x1d, 0xb8, 0x58, 0xde, 0xb4, 0xa2,
+	0x56, 0x51, 0x17, 0xe4, 0xb6, 0x04, 0x1f, 0x87, 0x98, 0xbc, 0x20, 0x0f, 0x66, 0x02, 0xea, 0x3d,
+	0x3a, 0xb2, 0xb9, 0x69, 0x33, 0x2c, 0xaf, 0x9c, 0xb3, 0xef, 0xd1, 0x67, 0x9d, 0x7e, 0x47, 0xc5,
+	0xe5, 0x7b, 0x74, 0x64, 0xf3, 0x70, 0xc0, 0xd1, 0x4f, 0x60, 0x4d, 0x92, 0xf4, 0x47, 0x61, 0xfc,
+	0x12, 0x55, 0xd6, 0xf8, 0xac, 0xd3, 0x57, 0x9f, 0x7a, 0xdd, 0xbe, 0x01, 0x23, 0x9b, 0xeb, 0xdf,
+	0xaa, 0xa3, 0x75, 0x13, 0xd9, 0xd4, 0xf3, 0xb0, 0xad, 0x58, 0xf9, 0xa4, 0xa3, 0x7f, 0x29, 0xb1,
+	0x8e, 0x86, 0x64, 0x47, 0x0f, 0xd3, 0x63, 0xcd, 0xb6, 0xb9, 0xe9, 0xe1, 0x21, 0x15, 0x24, 0x9c,
+	0xe7, 0x72, 0x8a, 0xdd, 0xe9, 0x77, 0x23, 0x48, 0xb1, 0x6d, 0x9e, 0x8c, 0xe5, 0x6d, 0x10, 0x1d,
+	0x13, 0xa6, 0x9f, 0x1d, 0x7e, 0xce, 0x7a, 0x5c, 0x7b, 0x65, 0x7b, 0x7b, 0x3a, 0xa9, 0x57, 0x9e,
+	0x4a, 0x34, 0x79, 0x73, 0xf4, 0x8d, 0xca, 0xf1, 0x6c, 0x84, 0xb7, 0xff, 0x70, 0xfe, 0xae, 0xb6,
+	0xf4, 0x9f, 0x77, 0xb5, 0xa5, 0x3f, 0x4e, 0x6b, 0x99, 0xf3, 0x69, 0x2d, 0xf3, 0xef, 0x69, 0x2d,
+	0xf3, 0xdf, 0x69, 0x2d, 0xf3, 0xdb, 0xa7, 0x1f, 0xfb, 0x6f, 0x93, 0x9f, 0xab, 0xbf, 0xbf, 0x59,
+	0x1a, 0xac, 0xa8, 0x16, 0xfa, 0xf6, 0xff, 0x03, 0x00, 0xd1, 0xaa, 0xee, 0x13, 0x89, 0x11, 0x00,
+	0x00,
 }
+
 func (m *Statistics) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -245,6 +953,9 @@ func (m *Statistics) MarshalTo(dAtA []byte) (int, error) {
 		}
 		i += n2
 	}
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
 	return i, nil
 }
 
@@ -291,66 +1002,76 @@ func (m *WindowsContainerStatistics) MarshalTo(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
-	return i, nil
-}
-
-func (m *LinuxContainerStatistics) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
+	dAtA[i] = 0xa
+	i++
+	i = encodeVarintStats(dAtA, i, uint64(github_com_gogo_protobuf_types.SizeOfStdTime(m.Timestamp)))
+	n5, err := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.Timestamp, dAtA[i:])
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
-	return dAtA[:n], nil
-}
-
-func (m *LinuxContainerStatistics) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	return i, nil
-}
-
-func (m *VirtualMachineStatistics) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
+	i += n5
+	dAtA[i] = 0x12
+	i++
+	i = encodeVarintStats(dAtA, i, uint64(github_com_gogo_protobuf_types.SizeOfStdTime(m.ContainerStartTime)))
+	n6, err := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.ContainerStartTime, dAtA[i:])
 	if err != nil {
-		return nil, err
+		return 0, err
+	}
+	i += n6
+	if m.UptimeNS != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintStats(dAtA, i, uint64(m.UptimeNS))
 	}
-	return dAtA[:n], nil
-}
-
-func (m *VirtualMachineStatistics) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
 	if m.Processor != nil {
-		dAtA[i] = 0xa
+		dAtA[i] = 0x22
 		i++
 		i = encodeVarintStats(dAtA, i, uint64(m.Processor.Size()))
-		n5, err := m.Processor.MarshalTo(dAtA[i:])
+		n7, err := m.Processor.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n5
+		i += n7
 	}
 	if m.Memory != nil {
-		dAtA[i] = 0x12
+		dAtA[i] = 0x2a
 		i++
 		i = encodeVarintStats(dAtA, i, uint64(m.Memory.Size()))
-		n6, err := m.Memory.MarshalTo(dAtA[i:])
+		n8, err := m.Memory.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n8
+	}
+	if m.Storage != nil {
+		dAtA[i] = 0x32
+		i++
+		i = encodeVarintStats(dAtA, i, uint64(m.Storage.Size()))
+		n9, err := m.Storage.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n6
+		i += n9
+	}
+	if len(m.Network) > 0 {
+		for _, msg := range m.Network {
+			dAtA[i] = 0x3a
+			i++
+			i = encodeVarintStats(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
 	}
 	return i, nil
 }
 
-func (m *VirtualMachineProcessorStatistics) Marshal() (dAtA []byte, err error) {
+func (m *WindowsContainerProcessorStatistics) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalTo(dAtA)
@@ -360,7 +1081,7 @@ func (m *VirtualMachineProcessorStatistics) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *VirtualMachineProcessorStatistics) MarshalTo(dAtA []byte) (int, error) {
+func (m *WindowsContainerProcessorStatistics) MarshalTo(dAtA []byte) (int, error) {
 	var i int
 	_ = i
 	var l int
@@ -370,10 +1091,23 @@ func (m *VirtualMachineProcessorStatistics) MarshalTo(dAtA []byte) (int, error)
 		i++
 		i = encodeVarintStats(dAtA, i, uint64(m.TotalRuntimeNS))
 	}
+	if m.RuntimeUserNS != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintStats(dAtA, i, uint64(m.RuntimeUserNS))
+	}
+	if m.RuntimeKernelNS != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintStats(dAtA, i, uint64(m.RuntimeKernelNS))
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
 	return i, nil
 }
 
-func (m *VirtualMachineMemoryStatistics) Marshal() (dAtA []byte, err error) {
+func (m *WindowsContainerMemoryStatistics) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalTo(dAtA)
@@ -383,205 +1117,2967 @@ func (m *VirtualMachineMemoryStatistics) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *VirtualMachineMemoryStatistics) MarshalTo(dAtA []byte) (int, error) {
+func (m *WindowsContainerMemoryStatistics) MarshalTo(dAtA []byte) (int, error) {
 	var i int
 	_ = i
 	var l int
 	_ = l
-	if m.WorkingSetBytes != 0 {
+	if m.MemoryUsageCommitBytes != 0 {
 		dAtA[i] = 0x8
 		i++
-		i = encodeVarintStats(dAtA, i, uint64(m.WorkingSetBytes))
+		i = encodeVarintStats(dAtA, i, uint64(m.MemoryUsageCommitBytes))
+	}
+	if m.MemoryUsageCommitPeakBytes != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintStats(dAtA, i, uint64(m.MemoryUsageCommitPeakBytes))
+	}
+	if m.MemoryUsagePrivateWorkingSetBytes != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintStats(dAtA, i, uint64(m.MemoryUsagePrivateWorkingSetBytes))
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
 	}
 	return i, nil
 }
 
-func encodeVarintStats(dAtA []byte, offset int, v uint64) int {
-	for v >= 1<<7 {
-		dAtA[offset] = uint8(v&0x7f | 0x80)
-		v >>= 7
-		offset++
-	}
-	dAtA[offset] = uint8(v)
-	return offset + 1
+func (m *WindowsContainerStorageStatistics) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
 }
-func (m *Statistics) Size() (n int) {
+
+func (m *WindowsContainerStorageStatistics) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
 	var l int
 	_ = l
-	if m.Container != nil {
-		n += m.Container.Size()
+	if m.ReadCountNormalized != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintStats(dAtA, i, uint64(m.ReadCountNormalized))
 	}
-	if m.VM != nil {
-		l = m.VM.Size()
-		n += 1 + l + sovStats(uint64(l))
+	if m.ReadSizeBytes != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintStats(dAtA, i, uint64(m.ReadSizeBytes))
 	}
-	return n
+	if m.WriteCountNormalized != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintStats(dAtA, i, uint64(m.WriteCountNormalized))
+	}
+	if m.WriteSizeBytes != 0 {
+		dAtA[i] = 0x20
+		i++
+		i = encodeVarintStats(dAtA, i, uint64(m.WriteSizeBytes))
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
 }
 
-func (m *Statistics_Windows) Size() (n int) {
+func (m *LinuxContainerStatistics) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *LinuxContainerStatistics) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
 	var l int
 	_ = l
-	if m.Windows != nil {
-		l = m.Windows.Size()
-		n += 1 + l + sovStats(uint64(l))
+	dAtA[i] = 0xa
+	i++
+	i = encodeVarintStats(dAtA, i, uint64(github_com_gogo_protobuf_types.SizeOfStdTime(m.Timestamp)))
+	n10, err := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.Timestamp, dAtA[i:])
+	if err != nil {
+		return 0, err
 	}
-	return n
+	i += n10
+	if m.CPU != nil {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintStats(dAtA, i, uint64(m.CPU.Size()))
+		n11, err := m.CPU.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n11
+	}
+	if m.Memory != nil {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintStats(dAtA, i, uint64(m.Memory.Size()))
+		n12, err := m.Memory.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n12
+	}
+	if m.Blkio != nil {
+		dAtA[i] = 0x2a
+		i++
+		i = encodeVarintStats(dAtA, i, uint64(m.Blkio.Size()))
+		n13, err := m.Blkio.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n13
+	}
+	if m.Pids != nil {
+		dAtA[i] = 0x3a
+		i++
+		i = encodeVarintStats(dAtA, i, uint64(m.Pids.Size()))
+		n14, err := m.Pids.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n14
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
 }
-func (m *Statistics_Linux) Size() (n int) {
+
+func (m *LinuxContainerCPUStatistics) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *LinuxContainerCPUStatistics) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
 	var l int
 	_ = l
-	if m.Linux != nil {
-		l = m.Linux.Size()
-		n += 1 + l + sovStats(uint64(l))
+	if m.UsageTotalNS != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintStats(dAtA, i, uint64(m.UsageTotalNS))
 	}
-	return n
+	if m.UsageUserNS != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintStats(dAtA, i, uint64(m.UsageUserNS))
+	}
+	if m.UsageKernelNS != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintStats(dAtA, i, uint64(m.UsageKernelNS))
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
 }
-func (m *WindowsContainerStatistics) Size() (n int) {
+
+func (m *LinuxContainerMemoryStatistics) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *LinuxContainerMemoryStatistics) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
 	var l int
 	_ = l
-	return n
+	if m.UsageBytes != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintStats(dAtA, i, uint64(m.UsageBytes))
+	}
+	if m.MaxUsageBytes != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintStats(dAtA, i, uint64(m.MaxUsageBytes))
+	}
+	if m.WorkingSetBytes != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintStats(dAtA, i, uint64(m.WorkingSetBytes))
+	}
+	if m.LimitBytes != 0 {
+		dAtA[i] = 0x20
+		i++
+		i = encodeVarintStats(dAtA, i, uint64(m.LimitBytes))
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
 }
 
-func (m *LinuxContainerStatistics) Size() (n int) {
+func (m *LinuxContainerBlkioStatistics) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *LinuxContainerBlkioStatistics) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
 	var l int
 	_ = l
-	return n
+	if m.ReadOps != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintStats(dAtA, i, uint64(m.ReadOps))
+	}
+	if m.ReadBytes != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintStats(dAtA, i, uint64(m.ReadBytes))
+	}
+	if m.WriteOps != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintStats(dAtA, i, uint64(m.WriteOps))
+	}
+	if m.WriteBytes != 0 {
+		dAtA[i] = 0x20
+		i++
+		i = encodeVarintStats(dAtA, i, uint64(m.WriteBytes))
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
 }
 
-func (m *VirtualMachineStatistics) Size() (n int) {
+func (m *LinuxContainerPidsStatistics) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *LinuxContainerPidsStatistics) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
 	var l int
 	_ = l
-	if m.Processor != nil {
-		l = m.Processor.Size()
-		n += 1 + l + sovStats(uint64(l))
+	if m.Current != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintStats(dAtA, i, uint64(m.Current))
 	}
-	if m.Memory != nil {
-		l = m.Memory.Size()
-		n += 1 + l + sovStats(uint64(l))
+	if m.Limit != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintStats(dAtA, i, uint64(m.Limit))
 	}
-	return n
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
 }
 
-func (m *VirtualMachineProcessorStatistics) Size() (n int) {
+func (m *ContainerNetworkStatistics) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ContainerNetworkStatistics) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
 	var l int
 	_ = l
-	if m.TotalRuntimeNS != 0 {
-		n += 1 + sovStats(uint64(m.TotalRuntimeNS))
+	if len(m.EndpointID) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintStats(dAtA, i, uint64(len(m.EndpointID)))
+		i += copy(dAtA[i:], m.EndpointID)
 	}
-	return n
+	if len(m.InstanceID) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintStats(dAtA, i, uint64(len(m.InstanceID)))
+		i += copy(dAtA[i:], m.InstanceID)
+	}
+	if m.BytesReceived != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintStats(dAtA, i, uint64(m.BytesReceived))
+	}
+	if m.BytesSent != 0 {
+		dAtA[i] = 0x20
+		i++
+		i = encodeVarintStats(dAtA, i, uint64(m.BytesSent))
+	}
+	if m.PacketsReceived != 0 {
+		dAtA[i] = 0x28
+		i++
+		i = encodeVarintStats(dAtA, i, uint64(m.PacketsReceived))
+	}
+	if m.PacketsSent != 0 {
+		dAtA[i] = 0x30
+		i++
+		i = encodeVarintStats(dAtA, i, uint64(m.PacketsSent))
+	}
+	if m.DroppedPacketsIncoming != 0 {
+		dAtA[i] = 0x38
+		i++
+		i = encodeVarintStats(dAtA, i, uint64(m.DroppedPacketsIncoming))
+	}
+	if m.DroppedPacketsOutgoing != 0 {
+		dAtA[i] = 0x40
+		i++
+		i = encodeVarintStats(dAtA, i, uint64(m.DroppedPacketsOutgoing))
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
 }
 
-func (m *VirtualMachineMemoryStatistics) Size() (n int) {
+func (m *VirtualMachineStatistics) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *VirtualMachineStatistics) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
 	var l int
 	_ = l
-	if m.WorkingSetBytes != 0 {
-		n += 1 + sovStats(uint64(m.WorkingSetBytes))
+	if m.Processor != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintStats(dAtA, i, uint64(m.Processor.Size()))
+		n15, err := m.Processor.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n15
 	}
-	return n
+	if m.Memory != nil {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintStats(dAtA, i, uint64(m.Memory.Size()))
+		n16, err := m.Memory.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n16
+	}
+	if m.Boot != nil {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintStats(dAtA, i, uint64(m.Boot.Size()))
+		n17, err := m.Boot.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n17
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
 }
 
-func sovStats(x uint64) (n int) {
-	for {
-		n++
-		x >>= 7
-		if x == 0 {
-			break
+func (m *VirtualMachineProcessorStatistics) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *VirtualMachineProcessorStatistics) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.TotalRuntimeNS != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintStats(dAtA, i, uint64(m.TotalRuntimeNS))
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func (m *VirtualMachineMemoryStatistics) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *VirtualMachineMemoryStatistics) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.WorkingSetBytes != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintStats(dAtA, i, uint64(m.WorkingSetBytes))
+	}
+	if m.GuestAvailableBytes != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintStats(dAtA, i, uint64(m.GuestAvailableBytes))
+	}
+	if m.TrimmedBytes != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintStats(dAtA, i, uint64(m.TrimmedBytes))
+	}
+	if m.Trims != 0 {
+		dAtA[i] = 0x20
+		i++
+		i = encodeVarintStats(dAtA, i, uint64(m.Trims))
+	}
+	if m.AssignedBytes != 0 {
+		dAtA[i] = 0x28
+		i++
+		i = encodeVarintStats(dAtA, i, uint64(m.AssignedBytes))
+	}
+	if m.BalancingEnabled {
+		dAtA[i] = 0x30
+		i++
+		if m.BalancingEnabled {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if m.BalancingInProgress {
+		dAtA[i] = 0x38
+		i++
+		if m.BalancingInProgress {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if m.Idle {
+		dAtA[i] = 0x40
+		i++
+		if m.Idle {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	dAtA[i] = 0x4a
+	i++
+	i = encodeVarintStats(dAtA, i, uint64(github_com_gogo_protobuf_types.SizeOfStdTime(m.LastTrim)))
+	n18, err := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.LastTrim, dAtA[i:])
+	if err != nil {
+		return 0, err
+	}
+	i += n18
+	if m.LastTrimmedBytes != 0 {
+		dAtA[i] = 0x50
+		i++
+		i = encodeVarintStats(dAtA, i, uint64(m.LastTrimmedBytes))
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func (m *VirtualMachineBootStatistics) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *VirtualMachineBootStatistics) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.BuildDocumentNS != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintStats(dAtA, i, uint64(m.BuildDocumentNS))
+	}
+	if m.HCSCreateNS != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintStats(dAtA, i, uint64(m.HCSCreateNS))
+	}
+	if m.HCSStartNS != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintStats(dAtA, i, uint64(m.HCSStartNS))
+	}
+	if m.GuestConnectNS != 0 {
+		dAtA[i] = 0x20
+		i++
+		i = encodeVarintStats(dAtA, i, uint64(m.GuestConnectNS))
+	}
+	if m.GCSNegotiateNS != 0 {
+		dAtA[i] = 0x28
+		i++
+		i = encodeVarintStats(dAtA, i, uint64(m.GCSNegotiateNS))
+	}
+	if m.FirstContainerNS != 0 {
+		dAtA[i] = 0x30
+		i++
+		i = encodeVarintStats(dAtA, i, uint64(m.FirstContainerNS))
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func encodeVarintStats(dAtA []byte, offset int, v uint64) int {
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return offset + 1
+}
+func (m *Statistics) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Container != nil {
+		n += m.Container.Size()
+	}
+	if m.VM != nil {
+		l = m.VM.Size()
+		n += 1 + l + sovStats(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *Statistics_Windows) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Windows != nil {
+		l = m.Windows.Size()
+		n += 1 + l + sovStats(uint64(l))
+	}
+	return n
+}
+func (m *Statistics_Linux) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Linux != nil {
+		l = m.Linux.Size()
+		n += 1 + l + sovStats(uint64(l))
+	}
+	return n
+}
+func (m *WindowsContainerStatistics) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.Timestamp)
+	n += 1 + l + sovStats(uint64(l))
+	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.ContainerStartTime)
+	n += 1 + l + sovStats(uint64(l))
+	if m.UptimeNS != 0 {
+		n += 1 + sovStats(uint64(m.UptimeNS))
+	}
+	if m.Processor != nil {
+		l = m.Processor.Size()
+		n += 1 + l + sovStats(uint64(l))
+	}
+	if m.Memory != nil {
+		l = m.Memory.Size()
+		n += 1 + l + sovStats(uint64(l))
+	}
+	if m.Storage != nil {
+		l = m.Storage.Size()
+		n += 1 + l + sovStats(uint64(l))
+	}
+	if len(m.Network) > 0 {
+		for _, e := range m.Network {
+			l = e.Size()
+			n += 1 + l + sovStats(uint64(l))
+		}
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *WindowsContainerProcessorStatistics) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.TotalRuntimeNS != 0 {
+		n += 1 + sovStats(uint64(m.TotalRuntimeNS))
+	}
+	if m.RuntimeUserNS != 0 {
+		n += 1 + sovStats(uint64(m.RuntimeUserNS))
+	}
+	if m.RuntimeKernelNS != 0 {
+		n += 1 + sovStats(uint64(m.RuntimeKernelNS))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *WindowsContainerMemoryStatistics) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.MemoryUsageCommitBytes != 0 {
+		n += 1 + sovStats(uint64(m.MemoryUsageCommitBytes))
+	}
+	if m.MemoryUsageCommitPeakBytes != 0 {
+		n += 1 + sovStats(uint64(m.MemoryUsageCommitPeakBytes))
+	}
+	if m.MemoryUsagePrivateWorkingSetBytes != 0 {
+		n += 1 + sovStats(uint64(m.MemoryUsagePrivateWorkingSetBytes))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *WindowsContainerStorageStatistics) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.ReadCountNormalized != 0 {
+		n += 1 + sovStats(uint64(m.ReadCountNormalized))
+	}
+	if m.ReadSizeBytes != 0 {
+		n += 1 + sovStats(uint64(m.ReadSizeBytes))
+	}
+	if m.WriteCountNormalized != 0 {
+		n += 1 + sovStats(uint64(m.WriteCountNormalized))
+	}
+	if m.WriteSizeBytes != 0 {
+		n += 1 + sovStats(uint64(m.WriteSizeBytes))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *LinuxContainerStatistics) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.Timestamp)
+	n += 1 + l + sovStats(uint64(l))
+	if m.CPU != nil {
+		l = m.CPU.Size()
+		n += 1 + l + sovStats(uint64(l))
+	}
+	if m.Memory != nil {
+		l = m.Memory.Size()
+		n += 1 + l + sovStats(uint64(l))
+	}
+	if m.Blkio != nil {
+		l = m.Blkio.Size()
+		n += 1 + l + sovStats(uint64(l))
+	}
+	if m.Pids != nil {
+		l = m.Pids.Size()
+		n += 1 + l + sovStats(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *LinuxContainerCPUStatistics) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.UsageTotalNS != 0 {
+		n += 1 + sovStats(uint64(m.UsageTotalNS))
+	}
+	if m.UsageUserNS != 0 {
+		n += 1 + sovStats(uint64(m.UsageUserNS))
+	}
+	if m.UsageKernelNS != 0 {
+		n += 1 + sovStats(uint64(m.UsageKernelNS))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *LinuxContainerMemoryStatistics) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.UsageBytes != 0 {
+		n += 1 + sovStats(uint64(m.UsageBytes))
+	}
+	if m.MaxUsageBytes != 0 {
+		n += 1 + sovStats(uint64(m.MaxUsageBytes))
+	}
+	if m.WorkingSetBytes != 0 {
+		n += 1 + sovStats(uint64(m.WorkingSetBytes))
+	}
+	if m.LimitBytes != 0 {
+		n += 1 + sovStats(uint64(m.LimitBytes))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *LinuxContainerBlkioStatistics) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.ReadOps != 0 {
+		n += 1 + sovStats(uint64(m.ReadOps))
+	}
+	if m.ReadBytes != 0 {
+		n += 1 + sovStats(uint64(m.ReadBytes))
+	}
+	if m.WriteOps != 0 {
+		n += 1 + sovStats(uint64(m.WriteOps))
+	}
+	if m.WriteBytes != 0 {
+		n += 1 + sovStats(uint64(m.WriteBytes))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *LinuxContainerPidsStatistics) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Current != 0 {
+		n += 1 + sovStats(uint64(m.Current))
+	}
+	if m.Limit != 0 {
+		n += 1 + sovStats(uint64(m.Limit))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *ContainerNetworkStatistics) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.EndpointID)
+	if l > 0 {
+		n += 1 + l + sovStats(uint64(l))
+	}
+	l = len(m.InstanceID)
+	if l > 0 {
+		n += 1 + l + sovStats(uint64(l))
+	}
+	if m.BytesReceived != 0 {
+		n += 1 + sovStats(uint64(m.BytesReceived))
+	}
+	if m.BytesSent != 0 {
+		n += 1 + sovStats(uint64(m.BytesSent))
+	}
+	if m.PacketsReceived != 0 {
+		n += 1 + sovStats(uint64(m.PacketsReceived))
+	}
+	if m.PacketsSent != 0 {
+		n += 1 + sovStats(uint64(m.PacketsSent))
+	}
+	if m.DroppedPacketsIncoming != 0 {
+		n += 1 + sovStats(uint64(m.DroppedPacketsIncoming))
+	}
+	if m.DroppedPacketsOutgoing != 0 {
+		n += 1 + sovStats(uint64(m.DroppedPacketsOutgoing))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *VirtualMachineStatistics) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Processor != nil {
+		l = m.Processor.Size()
+		n += 1 + l + sovStats(uint64(l))
+	}
+	if m.Memory != nil {
+		l = m.Memory.Size()
+		n += 1 + l + sovStats(uint64(l))
+	}
+	if m.Boot != nil {
+		l = m.Boot.Size()
+		n += 1 + l + sovStats(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *VirtualMachineProcessorStatistics) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.TotalRuntimeNS != 0 {
+		n += 1 + sovStats(uint64(m.TotalRuntimeNS))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *VirtualMachineMemoryStatistics) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.WorkingSetBytes != 0 {
+		n += 1 + sovStats(uint64(m.WorkingSetBytes))
+	}
+	if m.GuestAvailableBytes != 0 {
+		n += 1 + sovStats(uint64(m.GuestAvailableBytes))
+	}
+	if m.TrimmedBytes != 0 {
+		n += 1 + sovStats(uint64(m.TrimmedBytes))
+	}
+	if m.Trims != 0 {
+		n += 1 + sovStats(uint64(m.Trims))
+	}
+	if m.AssignedBytes != 0 {
+		n += 1 + sovStats(uint64(m.AssignedBytes))
+	}
+	if m.BalancingEnabled {
+		n += 2
+	}
+	if m.BalancingInProgress {
+		n += 2
+	}
+	if m.Idle {
+		n += 2
+	}
+	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.LastTrim)
+	n += 1 + l + sovStats(uint64(l))
+	if m.LastTrimmedBytes != 0 {
+		n += 1 + sovStats(uint64(m.LastTrimmedBytes))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *VirtualMachineBootStatistics) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.BuildDocumentNS != 0 {
+		n += 1 + sovStats(uint64(m.BuildDocumentNS))
+	}
+	if m.HCSCreateNS != 0 {
+		n += 1 + sovStats(uint64(m.HCSCreateNS))
+	}
+	if m.HCSStartNS != 0 {
+		n += 1 + sovStats(uint64(m.HCSStartNS))
+	}
+	if m.GuestConnectNS != 0 {
+		n += 1 + sovStats(uint64(m.GuestConnectNS))
+	}
+	if m.GCSNegotiateNS != 0 {
+		n += 1 + sovStats(uint64(m.GCSNegotiateNS))
+	}
+	if m.FirstContainerNS != 0 {
+		n += 1 + sovStats(uint64(m.FirstContainerNS))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func sovStats(x uint64) (n int) {
+	for {
+		n++
+		x >>= 7
+		if x == 0 {
+			break
+		}
+	}
+	return n
+}
+func sozStats(x uint64) (n int) {
+	return sovStats(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (this *Statistics) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Statistics{`,
+		`Container:` + fmt.Sprintf("%v", this.Container) + `,`,
+		`VM:` + strings.Replace(fmt.Sprintf("%v", this.VM), "VirtualMachineStatistics", "VirtualMachineStatistics", 1) + `,`,
+		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Statistics_Windows) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Statistics_Windows{`,
+		`Windows:` + strings.Replace(fmt.Sprintf("%v", this.Windows), "WindowsContainerStatistics", "WindowsContainerStatistics", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Statistics_Linux) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Statistics_Linux{`,
+		`Linux:` + strings.Replace(fmt.Sprintf("%v", this.Linux), "LinuxContainerStatistics", "LinuxContainerStatistics", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *WindowsContainerStatistics) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&WindowsContainerStatistics{`,
+		`Timestamp:` + strings.Replace(strings.Replace(this.Timestamp.String(), "Timestamp", "types.Timestamp", 1), `&`, ``, 1) + `,`,
+		`ContainerStartTime:` + strings.Replace(strings.Replace(this.ContainerStartTime.String(), "Timestamp", "types.Timestamp", 1), `&`, ``, 1) + `,`,
+		`UptimeNS:` + fmt.Sprintf("%v", this.UptimeNS) + `,`,
+		`Processor:` + strings.Replace(fmt.Sprintf("%v", this.Processor), "WindowsContainerProcessorStatistics", "WindowsContainerProcessorStatistics", 1) + `,`,
+		`Memory:` + strings.Replace(fmt.Sprintf("%v", this.Memory), "WindowsContainerMemoryStatistics", "WindowsContainerMemoryStatistics", 1) + `,`,
+		`Storage:` + strings.Replace(fmt.Sprintf("%v", this.Storage), "WindowsContainerStorageStatistics", "WindowsContainerStorageStatistics", 1) + `,`,
+		`Network:` + strings.Replace(fmt.Sprintf("%v", this.Network), "ContainerNetworkStatistics", "ContainerNetworkStatistics", 1) + `,`,
+		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *WindowsContainerProcessorStatistics) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&WindowsContainerProcessorStatistics{`,
+		`TotalRuntimeNS:` + fmt.Sprintf("%v", this.TotalRuntimeNS) + `,`,
+		`RuntimeUserNS:` + fmt.Sprintf("%v", this.RuntimeUserNS) + `,`,
+		`RuntimeKernelNS:` + fmt.Sprintf("%v", this.RuntimeKernelNS) + `,`,
+		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *WindowsContainerMemoryStatistics) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&WindowsContainerMemoryStatistics{`,
+		`MemoryUsageCommitBytes:` + fmt.Sprintf("%v", this.MemoryUsageCommitBytes) + `,`,
+		`MemoryUsageCommitPeakBytes:` + fmt.Sprintf("%v", this.MemoryUsageCommitPeakBytes) + `,`,
+		`MemoryUsagePrivateWorkingSetBytes:` + fmt.Sprintf("%v", this.MemoryUsagePrivateWorkingSetBytes) + `,`,
+		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *WindowsContainerStorageStatistics) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&WindowsContainerStorageStatistics{`,
+		`ReadCountNormalized:` + fmt.Sprintf("%v", this.ReadCountNormalized) + `,`,
+		`ReadSizeBytes:` + fmt.Sprintf("%v", this.ReadSizeBytes) + `,`,
+		`WriteCountNormalized:` + fmt.Sprintf("%v", this.WriteCountNormalized) + `,`,
+		`WriteSizeBytes:` + fmt.Sprintf("%v", this.WriteSizeBytes) + `,`,
+		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *LinuxContainerStatistics) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&LinuxContainerStatistics{`,
+		`Timestamp:` + strings.Replace(strings.Replace(this.Timestamp.String(), "Timestamp", "types.Timestamp", 1), `&`, ``, 1) + `,`,
+		`CPU:` + strings.Replace(fmt.Sprintf("%v", this.CPU), "LinuxContainerCPUStatistics", "LinuxContainerCPUStatistics", 1) + `,`,
+		`Memory:` + strings.Replace(fmt.Sprintf("%v", this.Memory), "LinuxContainerMemoryStatistics", "LinuxContainerMemoryStatistics", 1) + `,`,
+		`Blkio:` + strings.Replace(fmt.Sprintf("%v", this.Blkio), "LinuxContainerBlkioStatistics", "LinuxContainerBlkioStatistics", 1) + `,`,
+		`Pids:` + strings.Replace(fmt.Sprintf("%v", this.Pids), "LinuxContainerPidsStatistics", "LinuxContainerPidsStatistics", 1) + `,`,
+		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *LinuxContainerCPUStatistics) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&LinuxContainerCPUStatistics{`,
+		`UsageTotalNS:` + fmt.Sprintf("%v", this.UsageTotalNS) + `,`,
+		`UsageUserNS:` + fmt.Sprintf("%v", this.UsageUserNS) + `,`,
+		`UsageKernelNS:` + fmt.Sprintf("%v", this.UsageKernelNS) + `,`,
+		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *LinuxContainerMemoryStatistics) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&LinuxContainerMemoryStatistics{`,
+		`UsageBytes:` + fmt.Sprintf("%v", this.UsageBytes) + `,`,
+		`MaxUsageBytes:` + fmt.Sprintf("%v", this.MaxUsageBytes) + `,`,
+		`WorkingSetBytes:` + fmt.Sprintf("%v", this.WorkingSetBytes) + `,`,
+		`LimitBytes:` + fmt.Sprintf("%v", this.LimitBytes) + `,`,
+		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *LinuxContainerBlkioStatistics) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&LinuxContainerBlkioStatistics{`,
+		`ReadOps:` + fmt.Sprintf("%v", this.ReadOps) + `,`,
+		`ReadBytes:` + fmt.Sprintf("%v", this.ReadBytes) + `,`,
+		`WriteOps:` + fmt.Sprintf("%v", this.WriteOps) + `,`,
+		`WriteBytes:` + fmt.Sprintf("%v", this.WriteBytes) + `,`,
+		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *LinuxContainerPidsStatistics) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&LinuxContainerPidsStatistics{`,
+		`Current:` + fmt.Sprintf("%v", this.Current) + `,`,
+		`Limit:` + fmt.Sprintf("%v", this.Limit) + `,`,
+		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *ContainerNetworkStatistics) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&ContainerNetworkStatistics{`,
+		`EndpointID:` + fmt.Sprintf("%v", this.EndpointID) + `,`,
+		`InstanceID:` + fmt.Sprintf("%v", this.InstanceID) + `,`,
+		`BytesReceived:` + fmt.Sprintf("%v", this.BytesReceived) + `,`,
+		`BytesSent:` + fmt.Sprintf("%v", this.BytesSent) + `,`,
+		`PacketsReceived:` + fmt.Sprintf("%v", this.PacketsReceived) + `,`,
+		`PacketsSent:` + fmt.Sprintf("%v", this.PacketsSent) + `,`,
+		`DroppedPacketsIncoming:` + fmt.Sprintf("%v", this.DroppedPacketsIncoming) + `,`,
+		`DroppedPacketsOutgoing:` + fmt.Sprintf("%v", this.DroppedPacketsOutgoing) + `,`,
+		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *VirtualMachineStatistics) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&VirtualMachineStatistics{`,
+		`Processor:` + strings.Replace(fmt.Sprintf("%v", this.Processor), "VirtualMachineProcessorStatistics", "VirtualMachineProcessorStatistics", 1) + `,`,
+		`Memory:` + strings.Replace(fmt.Sprintf("%v", this.Memory), "VirtualMachineMemoryStatistics", "VirtualMachineMemoryStatistics", 1) + `,`,
+		`Boot:` + strings.Replace(fmt.Sprintf("%v", this.Boot), "VirtualMachineBootStatistics", "VirtualMachineBootStatistics", 1) + `,`,
+		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *VirtualMachineProcessorStatistics) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&VirtualMachineProcessorStatistics{`,
+		`TotalRuntimeNS:` + fmt.Sprintf("%v", this.TotalRuntimeNS) + `,`,
+		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *VirtualMachineMemoryStatistics) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&VirtualMachineMemoryStatistics{`,
+		`WorkingSetBytes:` + fmt.Sprintf("%v", this.WorkingSetBytes) + `,`,
+		`GuestAvailableBytes:` + fmt.Sprintf("%v", this.GuestAvailableBytes) + `,`,
+		`TrimmedBytes:` + fmt.Sprintf("%v", this.TrimmedBytes) + `,`,
+		`Trims:` + fmt.Sprintf("%v", this.Trims) + `,`,
+		`AssignedBytes:` + fmt.Sprintf("%v", this.AssignedBytes) + `,`,
+		`BalancingEnabled:` + fmt.Sprintf("%v", this.BalancingEnabled) + `,`,
+		`BalancingInProgress:` + fmt.Sprintf("%v", this.BalancingInProgress) + `,`,
+		`Idle:` + fmt.Sprintf("%v", this.Idle) + `,`,
+		`LastTrim:` + strings.Replace(strings.Replace(this.LastTrim.String(), "Timestamp", "types.Timestamp", 1), `&`, ``, 1) + `,`,
+		`LastTrimmedBytes:` + fmt.Sprintf("%v", this.LastTrimmedBytes) + `,`,
+		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *VirtualMachineBootStatistics) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&VirtualMachineBootStatistics{`,
+		`BuildDocumentNS:` + fmt.Sprintf("%v", this.BuildDocumentNS) + `,`,
+		`HCSCreateNS:` + fmt.Sprintf("%v", this.HCSCreateNS) + `,`,
+		`HCSStartNS:` + fmt.Sprintf("%v", this.HCSStartNS) + `,`,
+		`GuestConnectNS:` + fmt.Sprintf("%v", this.GuestConnectNS) + `,`,
+		`GCSNegotiateNS:` + fmt.Sprintf("%v", this.GCSNegotiateNS) + `,`,
+		`FirstContainerNS:` + fmt.Sprintf("%v", this.FirstContainerNS) + `,`,
+		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func valueToStringStats(v interface{}) string {
+	rv := reflect.ValueOf(v)
+	if rv.IsNil() {
+		return "nil"
+	}
+	pv := reflect.Indirect(rv).Interface()
+	return fmt.Sprintf("*%v", pv)
+}
+func (m *Statistics) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowStats
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Statistics: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Statistics: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Windows", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthStats
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthStats
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &WindowsContainerStatistics{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Container = &Statistics_Windows{v}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Linux", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthStats
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthStats
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &LinuxContainerStatistics{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Container = &Statistics_Linux{v}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field VM", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthStats
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthStats
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.VM == nil {
+				m.VM = &VirtualMachineStatistics{}
+			}
+			if err := m.VM.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStats(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthStats
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthStats
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *WindowsContainerStatistics) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowStats
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: WindowsContainerStatistics: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: WindowsContainerStatistics: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Timestamp", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthStats
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthStats
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := github_com_gogo_protobuf_types.StdTimeUnmarshal(&m.Timestamp, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ContainerStartTime", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthStats
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthStats
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := github_com_gogo_protobuf_types.StdTimeUnmarshal(&m.ContainerStartTime, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field UptimeNS", wireType)
+			}
+			m.UptimeNS = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.UptimeNS |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Processor", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthStats
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthStats
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Processor == nil {
+				m.Processor = &WindowsContainerProcessorStatistics{}
+			}
+			if err := m.Processor.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Memory", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthStats
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthStats
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Memory == nil {
+				m.Memory = &WindowsContainerMemoryStatistics{}
+			}
+			if err := m.Memory.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Storage", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthStats
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthStats
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Storage == nil {
+				m.Storage = &WindowsContainerStorageStatistics{}
+			}
+			if err := m.Storage.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Network", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthStats
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthStats
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Network = append(m.Network, &ContainerNetworkStatistics{})
+			if err := m.Network[len(m.Network)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStats(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthStats
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthStats
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *WindowsContainerProcessorStatistics) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowStats
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: WindowsContainerProcessorStatistics: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: WindowsContainerProcessorStatistics: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TotalRuntimeNS", wireType)
+			}
+			m.TotalRuntimeNS = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.TotalRuntimeNS |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RuntimeUserNS", wireType)
+			}
+			m.RuntimeUserNS = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.RuntimeUserNS |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RuntimeKernelNS", wireType)
+			}
+			m.RuntimeKernelNS = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.RuntimeKernelNS |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStats(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthStats
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthStats
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *WindowsContainerMemoryStatistics) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowStats
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: WindowsContainerMemoryStatistics: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: WindowsContainerMemoryStatistics: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MemoryUsageCommitBytes", wireType)
+			}
+			m.MemoryUsageCommitBytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MemoryUsageCommitBytes |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MemoryUsageCommitPeakBytes", wireType)
+			}
+			m.MemoryUsageCommitPeakBytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MemoryUsageCommitPeakBytes |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MemoryUsagePrivateWorkingSetBytes", wireType)
+			}
+			m.MemoryUsagePrivateWorkingSetBytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MemoryUsagePrivateWorkingSetBytes |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStats(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthStats
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthStats
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *WindowsContainerStorageStatistics) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowStats
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: WindowsContainerStorageStatistics: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: WindowsContainerStorageStatistics: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ReadCountNormalized", wireType)
+			}
+			m.ReadCountNormalized = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ReadCountNormalized |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ReadSizeBytes", wireType)
+			}
+			m.ReadSizeBytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ReadSizeBytes |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field WriteCountNormalized", wireType)
+			}
+			m.WriteCountNormalized = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.WriteCountNormalized |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field WriteSizeBytes", wireType)
+			}
+			m.WriteSizeBytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.WriteSizeBytes |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStats(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthStats
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthStats
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *LinuxContainerStatistics) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowStats
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: LinuxContainerStatistics: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: LinuxContainerStatistics: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Timestamp", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthStats
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthStats
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := github_com_gogo_protobuf_types.StdTimeUnmarshal(&m.Timestamp, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CPU", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthStats
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthStats
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.CPU == nil {
+				m.CPU = &LinuxContainerCPUStatistics{}
+			}
+			if err := m.CPU.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Memory", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthStats
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthStats
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Memory == nil {
+				m.Memory = &LinuxContainerMemoryStatistics{}
+			}
+			if err := m.Memory.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Blkio", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthStats
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthStats
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Blkio == nil {
+				m.Blkio = &LinuxContainerBlkioStatistics{}
+			}
+			if err := m.Blkio.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Pids", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthStats
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthStats
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Pids == nil {
+				m.Pids = &LinuxContainerPidsStatistics{}
+			}
+			if err := m.Pids.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStats(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthStats
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthStats
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *LinuxContainerCPUStatistics) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowStats
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: LinuxContainerCPUStatistics: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: LinuxContainerCPUStatistics: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field UsageTotalNS", wireType)
+			}
+			m.UsageTotalNS = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.UsageTotalNS |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field UsageUserNS", wireType)
+			}
+			m.UsageUserNS = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.UsageUserNS |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field UsageKernelNS", wireType)
+			}
+			m.UsageKernelNS = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.UsageKernelNS |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStats(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthStats
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthStats
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *LinuxContainerMemoryStatistics) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowStats
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: LinuxContainerMemoryStatistics: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: LinuxContainerMemoryStatistics: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field UsageBytes", wireType)
+			}
+			m.UsageBytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.UsageBytes |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxUsageBytes", wireType)
+			}
+			m.MaxUsageBytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxUsageBytes |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field WorkingSetBytes", wireType)
+			}
+			m.WorkingSetBytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.WorkingSetBytes |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LimitBytes", wireType)
+			}
+			m.LimitBytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.LimitBytes |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStats(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthStats
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthStats
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *LinuxContainerBlkioStatistics) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowStats
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: LinuxContainerBlkioStatistics: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: LinuxContainerBlkioStatistics: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ReadOps", wireType)
+			}
+			m.ReadOps = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ReadOps |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ReadBytes", wireType)
+			}
+			m.ReadBytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ReadBytes |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field WriteOps", wireType)
+			}
+			m.WriteOps = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.WriteOps |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field WriteBytes", wireType)
+			}
+			m.WriteBytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.WriteBytes |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStats(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthStats
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthStats
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *LinuxContainerPidsStatistics) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowStats
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: LinuxContainerPidsStatistics: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: LinuxContainerPidsStatistics: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Current", wireType)
+			}
+			m.Current = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Current |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Limit", wireType)
+			}
+			m.Limit = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Limit |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStats(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthStats
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthStats
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ContainerNetworkStatistics) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowStats
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ContainerNetworkStatistics: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ContainerNetworkStatistics: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EndpointID", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthStats
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthStats
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.EndpointID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field InstanceID", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthStats
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthStats
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.InstanceID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BytesReceived", wireType)
+			}
+			m.BytesReceived = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.BytesReceived |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BytesSent", wireType)
+			}
+			m.BytesSent = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.BytesSent |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PacketsReceived", wireType)
+			}
+			m.PacketsReceived = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.PacketsReceived |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PacketsSent", wireType)
+			}
+			m.PacketsSent = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.PacketsSent |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DroppedPacketsIncoming", wireType)
+			}
+			m.DroppedPacketsIncoming = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.DroppedPacketsIncoming |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 8:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DroppedPacketsOutgoing", wireType)
+			}
+			m.DroppedPacketsOutgoing = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.DroppedPacketsOutgoing |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStats(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthStats
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthStats
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
 		}
 	}
-	return n
-}
-func sozStats(x uint64) (n int) {
-	return sovStats(uint64((x << 1) ^ uint64((int64(x) >> 63))))
-}
-func (this *Statistics) String() string {
-	if this == nil {
-		return "nil"
-	}
-	s := strings.Join([]string{`&Statistics{`,
-		`Container:` + fmt.Sprintf("%v", this.Container) + `,`,
-		`VM:` + strings.Replace(fmt.Sprintf("%v", this.VM), "VirtualMachineStatistics", "VirtualMachineStatistics", 1) + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func (this *Statistics_Windows) String() string {
-	if this == nil {
-		return "nil"
-	}
-	s := strings.Join([]string{`&Statistics_Windows{`,
-		`Windows:` + strings.Replace(fmt.Sprintf("%v", this.Windows), "WindowsContainerStatistics", "WindowsContainerStatistics", 1) + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func (this *Statistics_Linux) String() string {
-	if this == nil {
-		return "nil"
-	}
-	s := strings.Join([]string{`&Statistics_Linux{`,
-		`Linux:` + strings.Replace(fmt.Sprintf("%v", this.Linux), "LinuxContainerStatistics", "LinuxContainerStatistics", 1) + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func (this *WindowsContainerStatistics) String() string {
-	if this == nil {
-		return "nil"
-	}
-	s := strings.Join([]string{`&WindowsContainerStatistics{`,
-		`}`,
-	}, "")
-	return s
-}
-func (this *LinuxContainerStatistics) String() string {
-	if this == nil {
-		return "nil"
-	}
-	s := strings.Join([]string{`&LinuxContainerStatistics{`,
-		`}`,
-	}, "")
-	return s
-}
-func (this *VirtualMachineStatistics) String() string {
-	if this == nil {
-		return "nil"
-	}
-	s := strings.Join([]string{`&VirtualMachineStatistics{`,
-		`Processor:` + strings.Replace(fmt.Sprintf("%v", this.Processor), "VirtualMachineProcessorStatistics", "VirtualMachineProcessorStatistics", 1) + `,`,
-		`Memory:` + strings.Replace(fmt.Sprintf("%v", this.Memory), "VirtualMachineMemoryStatistics", "VirtualMachineMemoryStatistics", 1) + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func (this *VirtualMachineProcessorStatistics) String() string {
-	if this == nil {
-		return "nil"
-	}
-	s := strings.Join([]string{`&VirtualMachineProcessorStatistics{`,
-		`TotalRuntimeNS:` + fmt.Sprintf("%v", this.TotalRuntimeNS) + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func (this *VirtualMachineMemoryStatistics) String() string {
-	if this == nil {
-		return "nil"
-	}
-	s := strings.Join([]string{`&VirtualMachineMemoryStatistics{`,
-		`WorkingSetBytes:` + fmt.Sprintf("%v", this.WorkingSetBytes) + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func valueToStringStats(v interface{}) string {
-	rv := reflect.ValueOf(v)
-	if rv.IsNil() {
-		return "nil"
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
 	}
-	pv := reflect.Indirect(rv).Interface()
-	return fmt.Sprintf("*%v", pv)
+	return nil
 }
-func (m *Statistics) Unmarshal(dAtA []byte) error {
+func (m *VirtualMachineStatistics) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -596,7 +4092,7 @@ func (m *Statistics) Unmarshal(dAtA []byte) error {
 			}
 			b := dAtA[iNdEx]
 			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
+			wire |= uint64(b&0x7F) << shift
 			if b < 0x80 {
 				break
 			}
@@ -604,15 +4100,15 @@ func (m *Statistics) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Statistics: wiretype end group for non-group")
+			return fmt.Errorf("proto: VirtualMachineStatistics: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Statistics: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: VirtualMachineStatistics: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Windows", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Processor", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -624,7 +4120,7 @@ func (m *Statistics) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -633,18 +4129,22 @@ func (m *Statistics) Unmarshal(dAtA []byte) error {
 				return ErrInvalidLengthStats
 			}
 			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthStats
+			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			v := &WindowsContainerStatistics{}
-			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if m.Processor == nil {
+				m.Processor = &VirtualMachineProcessorStatistics{}
+			}
+			if err := m.Processor.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
-			m.Container = &Statistics_Windows{v}
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Linux", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Memory", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -656,7 +4156,7 @@ func (m *Statistics) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -665,18 +4165,22 @@ func (m *Statistics) Unmarshal(dAtA []byte) error {
 				return ErrInvalidLengthStats
 			}
 			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthStats
+			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			v := &LinuxContainerStatistics{}
-			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if m.Memory == nil {
+				m.Memory = &VirtualMachineMemoryStatistics{}
+			}
+			if err := m.Memory.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
-			m.Container = &Statistics_Linux{v}
 			iNdEx = postIndex
 		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field VM", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Boot", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -688,7 +4192,7 @@ func (m *Statistics) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -697,13 +4201,16 @@ func (m *Statistics) Unmarshal(dAtA []byte) error {
 				return ErrInvalidLengthStats
 			}
 			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthStats
+			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.VM == nil {
-				m.VM = &VirtualMachineStatistics{}
+			if m.Boot == nil {
+				m.Boot = &VirtualMachineBootStatistics{}
 			}
-			if err := m.VM.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if err := m.Boot.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
@@ -716,9 +4223,13 @@ func (m *Statistics) Unmarshal(dAtA []byte) error {
 			if skippy < 0 {
 				return ErrInvalidLengthStats
 			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthStats
+			}
 			if (iNdEx + skippy) > l {
 				return io.ErrUnexpectedEOF
 			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
 			iNdEx += skippy
 		}
 	}
@@ -728,7 +4239,7 @@ func (m *Statistics) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *WindowsContainerStatistics) Unmarshal(dAtA []byte) error {
+func (m *VirtualMachineProcessorStatistics) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -743,7 +4254,7 @@ func (m *WindowsContainerStatistics) Unmarshal(dAtA []byte) error {
 			}
 			b := dAtA[iNdEx]
 			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
+			wire |= uint64(b&0x7F) << shift
 			if b < 0x80 {
 				break
 			}
@@ -751,12 +4262,31 @@ func (m *WindowsContainerStatistics) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: WindowsContainerStatistics: wiretype end group for non-group")
+			return fmt.Errorf("proto: VirtualMachineProcessorStatistics: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: WindowsContainerStatistics: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: VirtualMachineProcessorStatistics: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TotalRuntimeNS", wireType)
+			}
+			m.TotalRuntimeNS = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.TotalRuntimeNS |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipStats(dAtA[iNdEx:])
@@ -766,9 +4296,13 @@ func (m *WindowsContainerStatistics) Unmarshal(dAtA []byte) error {
 			if skippy < 0 {
 				return ErrInvalidLengthStats
 			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthStats
+			}
 			if (iNdEx + skippy) > l {
 				return io.ErrUnexpectedEOF
 			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
 			iNdEx += skippy
 		}
 	}
@@ -778,7 +4312,7 @@ func (m *WindowsContainerStatistics) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *LinuxContainerStatistics) Unmarshal(dAtA []byte) error {
+func (m *VirtualMachineMemoryStatistics) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -793,7 +4327,7 @@ func (m *LinuxContainerStatistics) Unmarshal(dAtA []byte) error {
 			}
 			b := dAtA[iNdEx]
 			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
+			wire |= uint64(b&0x7F) << shift
 			if b < 0x80 {
 				break
 			}
@@ -801,67 +4335,112 @@ func (m *LinuxContainerStatistics) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: LinuxContainerStatistics: wiretype end group for non-group")
+			return fmt.Errorf("proto: VirtualMachineMemoryStatistics: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: LinuxContainerStatistics: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: VirtualMachineMemoryStatistics: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
-		default:
-			iNdEx = preIndex
-			skippy, err := skipStats(dAtA[iNdEx:])
-			if err != nil {
-				return err
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field WorkingSetBytes", wireType)
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthStats
+			m.WorkingSetBytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.WorkingSetBytes |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field GuestAvailableBytes", wireType)
+			}
+			m.GuestAvailableBytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.GuestAvailableBytes |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TrimmedBytes", wireType)
+			}
+			m.TrimmedBytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.TrimmedBytes |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			if (iNdEx + skippy) > l {
-				return io.ErrUnexpectedEOF
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Trims", wireType)
 			}
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *VirtualMachineStatistics) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowStats
+			m.Trims = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Trims |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			if iNdEx >= l {
-				return io.ErrUnexpectedEOF
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AssignedBytes", wireType)
 			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
-			if b < 0x80 {
-				break
+			m.AssignedBytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.AssignedBytes |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: VirtualMachineStatistics: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: VirtualMachineStatistics: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Processor", wireType)
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BalancingEnabled", wireType)
 			}
-			var msglen int
+			var v int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowStats
@@ -871,28 +4450,55 @@ func (m *VirtualMachineStatistics) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				v |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthStats
+			m.BalancingEnabled = bool(v != 0)
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BalancingInProgress", wireType)
 			}
-			postIndex := iNdEx + msglen
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			if m.Processor == nil {
-				m.Processor = &VirtualMachineProcessorStatistics{}
+			m.BalancingInProgress = bool(v != 0)
+		case 8:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Idle", wireType)
 			}
-			if err := m.Processor.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			iNdEx = postIndex
-		case 2:
+			m.Idle = bool(v != 0)
+		case 9:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Memory", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field LastTrim", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -904,7 +4510,7 @@ func (m *VirtualMachineStatistics) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -913,16 +4519,35 @@ func (m *VirtualMachineStatistics) Unmarshal(dAtA []byte) error {
 				return ErrInvalidLengthStats
 			}
 			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthStats
+			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Memory == nil {
-				m.Memory = &VirtualMachineMemoryStatistics{}
-			}
-			if err := m.Memory.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if err := github_com_gogo_protobuf_types.StdTimeUnmarshal(&m.LastTrim, dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
+		case 10:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LastTrimmedBytes", wireType)
+			}
+			m.LastTrimmedBytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.LastTrimmedBytes |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipStats(dAtA[iNdEx:])
@@ -932,9 +4557,13 @@ func (m *VirtualMachineStatistics) Unmarshal(dAtA []byte) error {
 			if skippy < 0 {
 				return ErrInvalidLengthStats
 			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthStats
+			}
 			if (iNdEx + skippy) > l {
 				return io.ErrUnexpectedEOF
 			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
 			iNdEx += skippy
 		}
 	}
@@ -944,7 +4573,7 @@ func (m *VirtualMachineStatistics) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *VirtualMachineProcessorStatistics) Unmarshal(dAtA []byte) error {
+func (m *VirtualMachineBootStatistics) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -959,7 +4588,7 @@ func (m *VirtualMachineProcessorStatistics) Unmarshal(dAtA []byte) error {
 			}
 			b := dAtA[iNdEx]
 			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
+			wire |= uint64(b&0x7F) << shift
 			if b < 0x80 {
 				break
 			}
@@ -967,17 +4596,17 @@ func (m *VirtualMachineProcessorStatistics) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: VirtualMachineProcessorStatistics: wiretype end group for non-group")
+			return fmt.Errorf("proto: VirtualMachineBootStatistics: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: VirtualMachineProcessorStatistics: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: VirtualMachineBootStatistics: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field TotalRuntimeNS", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field BuildDocumentNS", wireType)
 			}
-			m.TotalRuntimeNS = 0
+			m.BuildDocumentNS = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowStats
@@ -987,66 +4616,92 @@ func (m *VirtualMachineProcessorStatistics) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.TotalRuntimeNS |= (uint64(b) & 0x7F) << shift
+				m.BuildDocumentNS |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		default:
-			iNdEx = preIndex
-			skippy, err := skipStats(dAtA[iNdEx:])
-			if err != nil {
-				return err
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field HCSCreateNS", wireType)
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthStats
+			m.HCSCreateNS = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.HCSCreateNS |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			if (iNdEx + skippy) > l {
-				return io.ErrUnexpectedEOF
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field HCSStartNS", wireType)
 			}
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *VirtualMachineMemoryStatistics) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowStats
+			m.HCSStartNS = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.HCSStartNS |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			if iNdEx >= l {
-				return io.ErrUnexpectedEOF
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field GuestConnectNS", wireType)
 			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
-			if b < 0x80 {
-				break
+			m.GuestConnectNS = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.GuestConnectNS |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: VirtualMachineMemoryStatistics: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: VirtualMachineMemoryStatistics: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
+		case 5:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field WorkingSetBytes", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field GCSNegotiateNS", wireType)
 			}
-			m.WorkingSetBytes = 0
+			m.GCSNegotiateNS = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.GCSNegotiateNS |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FirstContainerNS", wireType)
+			}
+			m.FirstContainerNS = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowStats
@@ -1056,7 +4711,7 @@ func (m *VirtualMachineMemoryStatistics) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.WorkingSetBytes |= (uint64(b) & 0x7F) << shift
+				m.FirstContainerNS |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -1070,9 +4725,13 @@ func (m *VirtualMachineMemoryStatistics) Unmarshal(dAtA []byte) error {
 			if skippy < 0 {
 				return ErrInvalidLengthStats
 			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthStats
+			}
 			if (iNdEx + skippy) > l {
 				return io.ErrUnexpectedEOF
 			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
 			iNdEx += skippy
 		}
 	}
@@ -1136,10 +4795,13 @@ func skipStats(dAtA []byte) (n int, err error) {
 					break
 				}
 			}
-			iNdEx += length
 			if length < 0 {
 				return 0, ErrInvalidLengthStats
 			}
+			iNdEx += length
+			if iNdEx < 0 {
+				return 0, ErrInvalidLengthStats
+			}
 			return iNdEx, nil
 		case 3:
 			for {
@@ -1168,6 +4830,9 @@ func skipStats(dAtA []byte) (n int, err error) {
 					return 0, err
 				}
 				iNdEx = start + next
+				if iNdEx < 0 {
+					return 0, ErrInvalidLengthStats
+				}
 			}
 			return iNdEx, nil
 		case 4:
@@ -1186,38 +4851,3 @@ var (
 	ErrInvalidLengthStats = fmt.Errorf("proto: negative length found during unmarshaling")
 	ErrIntOverflowStats   = fmt.Errorf("proto: integer overflow")
 )
-
-func init() {
-	proto.RegisterFile("github.com/Microsoft/hcsshim/cmd/containerd-shim-runhcs-v1/stats/stats.proto", fileDescriptorStats)
-}
-
-var fileDescriptorStats = []byte{
-	// 430 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x93, 0xcf, 0x6b, 0xd4, 0x40,
-	0x14, 0xc7, 0x37, 0x51, 0x57, 0xfa, 0x0a, 0x55, 0x07, 0x0f, 0x61, 0x91, 0x54, 0x73, 0x12, 0x61,
-	0x13, 0xaa, 0xe2, 0xc1, 0x1f, 0x97, 0x08, 0xe2, 0x21, 0x2b, 0x32, 0x2b, 0x55, 0xf4, 0xb0, 0x66,
-	0x67, 0xc7, 0xcd, 0xe0, 0xce, 0x4c, 0x99, 0x79, 0xd9, 0xb5, 0x37, 0xaf, 0xfe, 0x67, 0x05, 0x2f,
-	0x1e, 0x3d, 0x15, 0x9b, 0xbf, 0x44, 0x92, 0xc9, 0x9a, 0x1e, 0xdc, 0xb6, 0xd0, 0xcb, 0x90, 0xcc,
-	0xf7, 0xfb, 0x3e, 0xef, 0x9b, 0x47, 0x1e, 0x64, 0x73, 0x81, 0x45, 0x39, 0x8d, 0x99, 0x96, 0xc9,
-	0x48, 0x30, 0xa3, 0xad, 0xfe, 0x82, 0x49, 0xc1, 0xac, 0x2d, 0x84, 0x4c, 0x98, 0x9c, 0x25, 0x4c,
-	0x2b, 0xcc, 0x85, 0xe2, 0x66, 0x36, 0xac, 0xef, 0x86, 0xa6, 0x54, 0x05, 0xb3, 0xc3, 0xe5, 0x5e,
-	0x62, 0x31, 0x47, 0xeb, 0xce, 0xf8, 0xc0, 0x68, 0xd4, 0x64, 0xd0, 0x99, 0x63, 0xe7, 0x8b, 0x9d,
-	0xbc, 0xdc, 0x1b, 0xdc, 0x9e, 0xeb, 0xb9, 0x6e, 0x6c, 0x49, 0xfd, 0xe4, 0x2a, 0xa2, 0x1f, 0x3e,
-	0xc0, 0x18, 0x73, 0x14, 0x16, 0x05, 0xb3, 0x84, 0xc2, 0xf5, 0x95, 0x50, 0x33, 0xbd, 0xb2, 0x81,
-	0x77, 0xd7, 0xbb, 0xbf, 0xfd, 0xf0, 0x49, 0xbc, 0x19, 0x19, 0xbf, 0x77, 0xd6, 0x97, 0x6b, 0x47,
-	0x07, 0x7a, 0xdd, 0xa3, 0x6b, 0x10, 0xc9, 0xe0, 0xda, 0x42, 0xa8, 0xf2, 0x5b, 0xe0, 0x37, 0xc4,
-	0xc7, 0x67, 0x11, 0xb3, 0xda, 0xf8, 0x7f, 0x9e, 0x83, 0x90, 0x0c, 0xfc, 0xa5, 0x0c, 0xae, 0x9c,
-	0x8f, 0xda, 0x17, 0x06, 0xcb, 0x7c, 0x31, 0xca, 0x59, 0x21, 0x14, 0xef, 0x50, 0x69, 0xbf, 0x3a,
-	0xde, 0xf5, 0xf7, 0x47, 0xd4, 0x5f, 0xca, 0x74, 0x1b, 0xb6, 0xfe, 0x21, 0xa2, 0x3b, 0x30, 0xd8,
-	0xfc, 0x45, 0xd1, 0x00, 0x82, 0x4d, 0xe9, 0xa2, 0x9f, 0x1e, 0x04, 0x9b, 0xfa, 0x91, 0x4f, 0xb0,
-	0x75, 0x60, 0x34, 0xe3, 0xd6, 0x6a, 0xd3, 0x4e, 0xf5, 0xc5, 0xc5, 0x83, 0xbf, 0x5d, 0x97, 0x76,
-	0x44, 0xda, 0xf1, 0x08, 0x85, 0xbe, 0xe4, 0x52, 0x9b, 0xc3, 0x76, 0xba, 0x4f, 0x2f, 0x4e, 0x1e,
-	0x35, 0x75, 0xa7, 0xb0, 0x2d, 0x29, 0xca, 0xe1, 0xde, 0xb9, 0x19, 0xc8, 0x73, 0xb8, 0x89, 0x1a,
-	0xf3, 0xc5, 0xc4, 0x94, 0x0a, 0x85, 0xe4, 0x13, 0xe5, 0x7e, 0x99, 0xab, 0x29, 0xa9, 0x8e, 0x77,
-	0x77, 0xde, 0xd5, 0x1a, 0x75, 0xd2, 0x9b, 0x31, 0xdd, 0xc1, 0xd3, 0xef, 0x36, 0xca, 0x20, 0x3c,
-	0x3b, 0x0c, 0x79, 0x00, 0xb7, 0x56, 0xda, 0x7c, 0x15, 0x6a, 0x3e, 0xb1, 0x1c, 0x27, 0xd3, 0x43,
-	0xe4, 0x6d, 0x03, 0x7a, 0xa3, 0x15, 0xc6, 0x1c, 0xd3, 0xfa, 0x3a, 0xfd, 0x7c, 0x74, 0x12, 0xf6,
-	0x7e, 0x9f, 0x84, 0xbd, 0xef, 0x55, 0xe8, 0x1d, 0x55, 0xa1, 0xf7, 0xab, 0x0a, 0xbd, 0x3f, 0x55,
-	0xe8, 0x7d, 0x7c, 0x75, 0xd9, 0xf5, 0x7a, 0xd6, 0x9c, 0x1f, 0x7a, 0xd3, 0x7e, 0xb3, 0x2f, 0x8f,
-	0xfe, 0x06, 0x00, 0x00, 0xff, 0xff, 0x05, 0xa5, 0x30, 0x6f, 0xb1, 0x03, 0x00, 0x00,
-}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/Microsoft/hcsshim/internal/log"
+	"github.com/containerd/containerd/errdefs"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"go.opencensus.io/trace"
+)
+
+// defaultShutdownTimeout is how long the container of a task without a stop
+// policy has to shut down when the task is closed.
+const defaultShutdownTimeout = 30 * time.Second
+
+// stopInit stops the init exec `e`, and all execs if `all`, with the stop
+// policy of the task. The stop signal of the policy is sent in place of the
+// SIGTERM of the caller, and if `e` does not exit within the grace period the
+// escalation signals are sent in order, one per grace period, followed by a
+// SIGKILL.
+func (ht *hcsTask) stopInit(ctx context.Context, e shimExec, all bool) error {
+	signal := uint32(ht.stop.Signal)
+	err := ht.signalExec(ctx, e, signal, all)
+	if err != nil && signal != 0xf && errors.Cause(err) == errdefs.ErrFailedPrecondition {
+		// The stop signal is not supported on this platform, use the SIGTERM
+		// of the caller instead.
+		log.G(ctx).WithError(err).Warn("failed to send stop signal, sending SIGTERM")
+		err = ht.signalExec(ctx, e, 0xf, all)
+	}
+	if err != nil {
+		return err
+	}
+	go ht.escalateStop(e, all)
+	return nil
+}
+
+// escalateStop sends the escalation signals of the stop policy of the task,
+// followed by a SIGKILL, to `e`, and to all execs if `all`, for as long as `e`
+// has not exited at the end of each grace period.
+//
+// This MUST be called via a goroutine to wait on a background thread.
+func (ht *hcsTask) escalateStop(e shimExec, all bool) {
+	ctx, span := trace.StartSpan(context.Background(), "hcsTask::escalateStop")
+	defer span.End()
+	span.AddAttributes(trace.StringAttribute("tid", ht.id))
+
+	execExited := make(chan struct{})
+	go func() {
+		e.Wait()
+		close(execExited)
+	}()
+	signals := append(append([]int(nil), ht.stop.Escalation...), 0x9)
+	for _, signal := range signals {
+		t := time.NewTimer(ht.stop.GracePeriod)
+		select {
+		case <-execExited:
+			t.Stop()
+			return
+		case <-ht.closed:
+			t.Stop()
+			return
+		case <-t.C:
+		}
+		log.G(ctx).WithFields(logrus.Fields{
+			"tid":         ht.id,
+			"signal":      signal,
+			"gracePeriod": ht.stop.GracePeriod,
+		}).Warn("task did not stop within the grace period, escalating")
+		if signal == 0x9 {
+			atomic.StoreUint32(&ht.stopTimedOut, 1)
+		}
+		if err := ht.signalExec(ctx, e, uint32(signal), all); err != nil {
+			log.G(ctx).WithError(err).Warn("failed to escalate stop")
+		}
+	}
+}
+
+// shutdownTimeout returns how long the container has to shut down when the task
+// is closed, which is the grace period of the stop policy of the task if any.
+func (ht *hcsTask) shutdownTimeout() time.Duration {
+	if ht.stop != nil && ht.stop.GracePeriod > 0 {
+		return ht.stop.GracePeriod
+	}
+	return defaultShutdownTimeout
+}
+
+// sidecarTask is a task that can be a sidecar in a pod.
+type sidecarTask interface {
+	// isSidecar returns `true` if the task is a sidecar, which is stopped once
+	// the other tasks of the pod have exited.
+	isSidecar() bool
+}
+
+func (ht *hcsTask) isSidecar() bool {
+	return ht.sidecar
+}
+
+// isSidecar returns `true` if `t` is a sidecar.
+func isSidecar(t shimTask) bool {
+	st, ok := t.(sidecarTask)
+	return ok && st.isSidecar()
+}
+
+// killAfterExit waits for the running init exec of each of `waitFor` to exit
+// and then sends `signal` to each of `tasks`, and to all of their execs if
+// `all`.
+//
+// This MUST be called via a goroutine to wait on a background thread.
+func killAfterExit(waitFor, tasks []shimTask, signal uint32, all bool) {
+	ctx, span := trace.StartSpan(context.Background(), "killAfterExit")
+	defer span.End()
+
+	for _, t := range waitFor {
+		if e, err := t.GetExec(""); err == nil && e.State() == shimExecStateRunning {
+			e.Wait()
+		}
+	}
+	for _, t := range tasks {
+		if err := t.KillExec(ctx, "", signal, all); err != nil {
+			log.G(ctx).WithFields(logrus.Fields{
+				"tid":           t.ID(),
+				logrus.ErrorKey: err,
+			}).Warn("failed to kill task")
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Microsoft/hcsshim/internal/oci"
+)
+
+func Test_hcsTask_KillExec_InitExecID_StopPolicy_Success(t *testing.T) {
+	lt, init, second := setupTestHcsTask(t)
+	lt.stop = &oci.StopPolicy{Signal: 0x6, GracePeriod: time.Hour}
+
+	err := lt.KillExec(context.TODO(), "", 0xf, true)
+	if err != nil {
+		t.Fatalf("should not have failed, got: %v", err)
+	}
+	if init.State() != shimExecStateExited || second.State() != shimExecStateExited {
+		t.Fatal("expected the stop signal to be sent to all execs")
+	}
+}
+
+func Test_hcsTask_ShutdownTimeout(t *testing.T) {
+	lt, _, _ := setupTestHcsTask(t)
+	if lt.shutdownTimeout() != defaultShutdownTimeout {
+		t.Fatalf("expected the default shutdown timeout, got: %v", lt.shutdownTimeout())
+	}
+	lt.stop = &oci.StopPolicy{Signal: 0x6, GracePeriod: 5 * time.Minute}
+	if lt.shutdownTimeout() != 5*time.Minute {
+		t.Fatalf("expected the grace period of the stop policy, got: %v", lt.shutdownTimeout())
+	}
+}
+
+func Test_isSidecar(t *testing.T) {
+	if isSidecar(&testShimTask{}) {
+		t.Fatal("expected a task not to be a sidecar")
+	}
+	if !isSidecar(&testShimTask{sidecar: true}) {
+		t.Fatal("expected a sidecar task")
+	}
+	if isSidecar(&wcowPodSandboxTask{}) {
+		t.Fatal("expected the pod sandbox task not to be a sidecar")
+	}
+}
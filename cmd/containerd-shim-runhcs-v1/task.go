@@ -61,7 +61,8 @@ type shimTask interface {
 	// `shimExecStateExited` state.
 	DeleteExec(ctx context.Context, eid string) (int, uint32, time.Time, error)
 	// Pids returns all process pid's in this `shimTask` including ones not
-	// created by the caller via a `CreateExec`.
+	// created by the caller via a `CreateExec`. The execs that exited but are
+	// not deleted yet are returned with their final usage.
 	Pids(ctx context.Context) ([]options.ProcessDetails, error)
 	// Waits for the the init task to complete.
 	//
@@ -80,7 +81,41 @@ type shimTask interface {
 	//
 	// If the host is not hypervisor isolated returns `""`.
 	DumpGuestStacks(ctx context.Context) string
+	// DeviceInventory returns the devices attached to this task host and the
+	// containers using them.
+	//
+	// If the host is not hypervisor isolated returns
+	// `errdefs.ErrFailedPrecondition`.
+	DeviceInventory(ctx context.Context) (*shimdiag.InventoryResponse, error)
+	// GuestLog returns the end of a guest log captured for this task host.
+	//
+	// If the host is not hypervisor isolated returns
+	// `errdefs.ErrFailedPrecondition`. If the guest log is not captured
+	// returns `errdefs.ErrNotFound`.
+	GuestLog(ctx context.Context, req *shimdiag.GuestLogRequest) (*shimdiag.GuestLogResponse, error)
+	// HostHealth returns the health of this task host, pinging its guest for
+	// at most `pingTimeout`.
+	//
+	// If the host is not hypervisor isolated returns
+	// `errdefs.ErrFailedPrecondition`.
+	HostHealth(ctx context.Context, pingTimeout time.Duration) (*shimdiag.UtilityVMHealth, error)
 	// Stats returns various metrics for the task. If the task owns the UVM,
 	// additional metrics on the UVM are returned as well.
 	Stats(ctx context.Context) (*stats.Statistics, error)
+	// Update updates the resources of the task to `resources`, which is a
+	// `*specs.WindowsResources` or `*specs.LinuxResources`. If the task owns
+	// the UVM the UVM is resized as well as the container, unless the task is
+	// the sandbox of a pod whose UVM is sized for all of its containers.
+	//
+	// If the task does not support updating its resources this task MUST
+	// return `errdefs.ErrNotImplemented`.
+	Update(ctx context.Context, resources interface{}) error
+	// Checkpoint saves the state of the UVM this task owns to the directory
+	// `path`. The UVM is resumed once saved, or terminated if `exit` is set.
+	// Only the UVM of a hypervisor isolated WCOW POD sandbox is restored from
+	// its checkpoint, when the POD is created with it.
+	//
+	// If the task does not own a UVM this task MUST return
+	// `errdefs.ErrFailedPrecondition`.
+	Checkpoint(ctx context.Context, path string, exit bool) error
 }
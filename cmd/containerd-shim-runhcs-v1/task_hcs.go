@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/Microsoft/hcsshim/cmd/containerd-shim-runhcs-v1/options"
@@ -17,6 +19,7 @@ import (
 	"github.com/Microsoft/hcsshim/internal/oc"
 	"github.com/Microsoft/hcsshim/internal/oci"
 	"github.com/Microsoft/hcsshim/internal/schema1"
+	hcsschema "github.com/Microsoft/hcsshim/internal/schema2"
 	"github.com/Microsoft/hcsshim/internal/shimdiag"
 	"github.com/Microsoft/hcsshim/internal/uvm"
 	"github.com/Microsoft/hcsshim/osversion"
@@ -60,6 +63,9 @@ func newHcsStandaloneTask(ctx context.Context, events publisher, req *task.Creat
 		switch opts.(type) {
 		case *uvm.OptionsLCOW:
 			lopts := (opts).(*uvm.OptionsLCOW)
+			lopts.LogCaptureDir = filepath.Join(req.Bundle, guestLogDir)
+			// Kernel panics are reported by default with the captured logs.
+			lopts.EnableCrashReporting = oci.ParseAnnotationsCrashReporting(ctx, s, true)
 			parent, err = uvm.CreateLCOW(ctx, lopts)
 			if err != nil {
 				return nil, err
@@ -125,7 +131,12 @@ func newHcsTask(
 
 	owner := filepath.Base(os.Args[0])
 
-	io, err := newNpipeIO(ctx, req.Stdin, req.Stdout, req.Stderr, req.Terminal)
+	stop, err := oci.ParseAnnotationsStopPolicy(s)
+	if err != nil {
+		return nil, errors.Wrap(errdefs.ErrInvalidArgument, err.Error())
+	}
+
+	io, err := newUpstreamIO(ctx, req.ID, req.ID, req.Stdin, req.Stdout, req.Stderr, req.Terminal)
 	if err != nil {
 		return nil, err
 	}
@@ -135,12 +146,21 @@ func newHcsTask(
 		s.Windows.Network != nil {
 		netNS = s.Windows.Network.NetworkNamespace
 	}
+	// Tear down anything left allocated by a previous shim for this bundle
+	// before allocating again.
+	journalPath := filepath.Join(req.Bundle, resourceJournalFile)
+	if err := hcsoci.RecoverJournal(ctx, journalPath, parent); err != nil {
+		log.G(ctx).WithError(err).Warn("failed to recover resource journal")
+	}
+
 	opts := hcsoci.CreateOptions{
 		ID:               req.ID,
 		Owner:            owner,
 		Spec:             s,
 		HostingSystem:    parent,
 		NetworkNamespace: netNS,
+		JournalPath:      journalPath,
+		Recoverable:      oci.ParseAnnotationsRestartRecovery(ctx, s),
 	}
 	system, resources, err := hcsoci.CreateContainer(ctx, &opts)
 	if err != nil {
@@ -155,14 +175,25 @@ func newHcsTask(
 		cr:       resources,
 		ownsHost: ownsParent,
 		host:     parent,
+		stop:     stop,
+		sidecar:  oci.ParseAnnotationsContainerSidecar(ctx, s),
 		closed:   make(chan struct{}),
 	}
+	if ct, _, _ := oci.GetSandboxTypeAndID(s.Annotations); ct == oci.KubernetesContainerTypeSandbox {
+		ht.isSandbox = true
+	}
+	if ht.isWCOW {
+		ht.sampler = newUsageSampler(system)
+	}
+	ht.memoryLimit = specMemoryLimitBytes(s)
+	ht.watchOOM(ctx)
 	ht.init = newHcsExec(
 		ctx,
 		events,
 		req.ID,
 		parent,
 		system,
+		ht.sampler,
 		req.ID,
 		req.Bundle,
 		ht.isWCOW,
@@ -199,6 +230,74 @@ func newHcsTask(
 	return ht, nil
 }
 
+// newRecoveredHcsTask reopens the container of the task `ts` recorded by a
+// shim that exited, within the reopened `parent`, and rebuilds its execs and
+// resources. No create or start events are published for the recovered task,
+// as they were published by the shim that created it.
+//
+// If `parent == nil` the container is on the host.
+func newRecoveredHcsTask(ctx context.Context, events publisher, parent *uvm.UtilityVM, ts *taskState) (_ shimTask, err error) {
+	ctx, span := trace.StartSpan(ctx, "newRecoveredHcsTask")
+	defer span.End()
+	defer func() { oc.SetSpanStatus(span, err) }()
+	span.AddAttributes(
+		trace.StringAttribute("tid", ts.ID),
+		trace.BoolAttribute("ownsParent", ts.OwnsHost))
+
+	system, err := hcs.OpenComputeSystem(ctx, ts.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			system.Close()
+		}
+	}()
+	resources, err := hcsoci.RecoverResources(ctx, ts.ID, filepath.Join(ts.Bundle, resourceJournalFile), parent)
+	if err != nil {
+		return nil, err
+	}
+
+	ht := &hcsTask{
+		events:   events,
+		id:       ts.ID,
+		isWCOW:   ts.IsWCOW,
+		c:        system,
+		cr:       resources,
+		ownsHost: ts.OwnsHost,
+		host:     parent,
+		stop:     ts.Stop,
+		sidecar:  ts.Sidecar,
+		closed:   make(chan struct{}),
+	}
+	if ht.isWCOW {
+		ht.sampler = newUsageSampler(system)
+	}
+	ht.watchOOM(ctx)
+	defer func() {
+		if err != nil && ht.oom != nil {
+			ht.oom.close()
+		}
+	}()
+	ht.init, err = newRecoveredHcsExec(ctx, events, ts.ID, parent, system, ht.sampler, ts.Bundle, ts.IsWCOW, ts.Init)
+	if err != nil {
+		return nil, err
+	}
+	for _, es := range ts.Execs {
+		he, err := newRecoveredHcsExec(ctx, events, ts.ID, parent, system, ht.sampler, ts.Bundle, ts.IsWCOW, es)
+		if err != nil {
+			return nil, err
+		}
+		ht.execs.Store(es.ID, he)
+	}
+
+	if parent != nil {
+		go ht.waitForHostExit()
+	}
+	go ht.waitInitExit()
+	return ht, nil
+}
+
 var _ = (shimTask)(&hcsTask{})
 
 // hcsTask is a generic task that represents a WCOW Container (process or
@@ -221,6 +320,12 @@ type hcsTask struct {
 	// It MUST be treated as read only in the lifetime of this task EXCEPT after
 	// a Kill to the init task in which it must be shutdown.
 	c cow.Container
+	// sampler samples the usage of the processes of `c` in a utility VM for
+	// the execs of this task, or nil for a LCOW container whose process usage
+	// is reported by the guest when each process exits.
+	//
+	// It MUST be treated as read only in the lifetime of this task.
+	sampler *usageSampler
 	// cr is the container resources this task is holding.
 	//
 	// It MUST be treated as read only in the lifetime of this task EXCEPT after
@@ -243,6 +348,21 @@ type hcsTask struct {
 	// NOTE: if `osversion.Get().Build < osversion.RS5` this will always be
 	// `nil`.
 	host *uvm.UtilityVM
+	// stop is how the init exec is stopped when the caller sends it a SIGTERM,
+	// or `nil` to send the signals of the caller as is.
+	//
+	// It MUST be treated as read only in the lifetime of the task.
+	stop *oci.StopPolicy
+	// sidecar is `true` if this task is a sidecar in a pod, which is stopped
+	// once the other tasks of the pod have exited.
+	//
+	// It MUST be treated as read only in the lifetime of the task.
+	sidecar bool
+	// isSandbox is `true` if this task is the sandbox container of a pod,
+	// whose UVM is sized for the whole pod rather than for this task.
+	//
+	// It MUST be treated as read only in the lifetime of the task.
+	isSandbox bool
 
 	// ecl is the exec create lock for all non-init execs and MUST be held
 	// durring create to prevent ID duplication.
@@ -254,6 +374,25 @@ type hcsTask struct {
 	// closeHostOnce is used to close `host`. This will only be used if
 	// `ownsHost==true` and `host != nil`.
 	closeHostOnce sync.Once
+
+	// memoryLimit is the memory limit of the container in bytes, used to size
+	// `host` when the limit is updated.
+	//
+	// NOTE: All accesses to this MUST be done atomically.
+	memoryLimit uint64
+	// oom watches the job object of a process isolated WCOW container for it
+	// running out of memory, or is nil if the container reports it itself or
+	// not at all.
+	//
+	// It MUST be treated as read only in the lifetime of this task.
+	oom *jobMemoryWatcher
+	// stopTimedOut is set to 1 if `host` was forcibly closed because the init
+	// exec did not stop within the grace period of a SIGKILL, or if the init
+	// exec was sent a SIGKILL because it did not stop within the grace periods
+	// of `stop`.
+	//
+	// NOTE: All accesses to this MUST be done atomically.
+	stopTimedOut uint32
 }
 
 func (ht *hcsTask) ID() string {
@@ -274,11 +413,11 @@ func (ht *hcsTask) CreateExec(ctx context.Context, req *task.ExecProcessRequest,
 		return errors.Wrapf(errdefs.ErrFailedPrecondition, "exec: '' in task: '%s' must be running to create additional execs", ht.id)
 	}
 
-	io, err := newNpipeIO(ctx, req.Stdin, req.Stdout, req.Stderr, req.Terminal)
+	io, err := newUpstreamIO(ctx, ht.id, req.ExecID, req.Stdin, req.Stdout, req.Stderr, req.Terminal)
 	if err != nil {
 		return err
 	}
-	he := newHcsExec(ctx, ht.events, ht.id, ht.host, ht.c, req.ExecID, ht.init.Status().Bundle, ht.isWCOW, spec, io)
+	he := newHcsExec(ctx, ht.events, ht.id, ht.host, ht.c, ht.sampler, req.ExecID, ht.init.Status().Bundle, ht.isWCOW, spec, io)
 	ht.execs.Store(req.ExecID, he)
 
 	// Publish the created event
@@ -312,6 +451,16 @@ func (ht *hcsTask) KillExec(ctx context.Context, eid string, signal uint32, all
 	if all && eid != "" {
 		return errors.Wrapf(errdefs.ErrFailedPrecondition, "cannot signal all for non-empty exec: '%s'", eid)
 	}
+	if eid == "" && signal == 0xf && ht.stop != nil {
+		// The caller is stopping the task. Stop it with the stop policy of
+		// the task instead.
+		return ht.stopInit(ctx, e, all)
+	}
+	return ht.signalExec(ctx, e, signal, all)
+}
+
+// signalExec sends `signal` to `e`, and to all execs if `all`.
+func (ht *hcsTask) signalExec(ctx context.Context, e shimExec, signal uint32, all bool) error {
 	if all {
 		// We are in a kill all on the init task. Signal everything.
 		ht.execs.Range(func(key, value interface{}) bool {
@@ -327,7 +476,7 @@ func (ht *hcsTask) KillExec(ctx context.Context, eid string, signal uint32, all
 			return false
 		})
 	}
-	if signal == 0x9 && eid == "" && ht.host != nil {
+	if signal == 0x9 && e == ht.init && ht.host != nil {
 		// If this is a SIGKILL against the init process we start a background
 		// timer and wait on either the timer expiring or the process exiting
 		// cleanly. If the timer exires first we forcibly close the UVM as we
@@ -345,6 +494,7 @@ func (ht *hcsTask) KillExec(ctx context.Context, eid string, signal uint32, all
 			case <-t.C:
 				// Safe to call multiple times if called previously on
 				// successful shutdown.
+				atomic.StoreUint32(&ht.stopTimedOut, 1)
 				ht.host.Close()
 			}
 		}()
@@ -396,16 +546,29 @@ func (ht *hcsTask) DeleteExec(ctx context.Context, eid string) (int, uint32, tim
 }
 
 func (ht *hcsTask) Pids(ctx context.Context) ([]options.ProcessDetails, error) {
-	// Map all user created exec's to pid/exec-id
+	// Map all user created exec's to pid/exec-id. The execs that exited but are
+	// not deleted yet are reported with their final usage instead.
 	pidMap := make(map[int]string)
-	ht.execs.Range(func(key, value interface{}) bool {
-		ex := value.(shimExec)
+	var exited []options.ProcessDetails
+	addExec := func(ex shimExec) {
+		if ex.State() == shimExecStateExited {
+			exited = append(exited, exitedProcessDetails(ctx, ex))
+			return
+		}
 		pidMap[ex.Pid()] = ex.ID()
+	}
+	ht.execs.Range(func(key, value interface{}) bool {
+		addExec(value.(shimExec))
 
 		// Iterate all
-		return false
+		return true
 	})
-	pidMap[ht.init.Pid()] = ht.init.ID()
+	addExec(ht.init)
+
+	// The container has no processes once the init exec has exited.
+	if ht.init.State() == shimExecStateExited {
+		return exited, nil
+	}
 
 	// Get the guest pids
 	props, err := ht.c.Properties(ctx, schema1.PropertyTypeProcessList)
@@ -423,13 +586,38 @@ func (ht *hcsTask) Pids(ctx context.Context) ([]options.ProcessDetails, error) {
 		pairs[i].MemoryWorkingSetPrivateBytes = p.MemoryWorkingSetPrivateBytes
 		pairs[i].MemoryWorkingSetSharedBytes = p.MemoryWorkingSetSharedBytes
 		pairs[i].ProcessID = p.ProcessId
-		pairs[i].UserTime_100Ns = p.KernelTime100ns
+		pairs[i].UserTime_100Ns = p.UserTime100ns
 
 		if eid, ok := pidMap[int(p.ProcessId)]; ok {
 			pairs[i].ExecID = eid
 		}
 	}
-	return pairs, nil
+	return append(pairs, exited...), nil
+}
+
+// exitedProcessDetails returns the details of the process of the exited exec
+// `ex` with its final usage, if it is known.
+func exitedProcessDetails(ctx context.Context, ex shimExec) options.ProcessDetails {
+	d := options.ProcessDetails{
+		ProcessID: uint32(ex.Pid()),
+		ExecID:    ex.ID(),
+		Exited:    true,
+	}
+	he, ok := ex.(*hcsExec)
+	if !ok {
+		return d
+	}
+	if usage := he.exitUsage(ctx); usage != nil {
+		d.KernelTime_100Ns = usage.KernelTime_100Ns
+		d.UserTime_100Ns = usage.UserTime_100Ns
+		d.MemoryCommitPeakBytes = usage.MemoryCommitPeakBytes
+		d.MemoryWorkingSetPeakBytes = usage.MemoryWorkingSetPeakBytes
+		d.ReadOperations = usage.ReadOperations
+		d.ReadBytes = usage.ReadBytes
+		d.WriteOperations = usage.WriteOperations
+		d.WriteBytes = usage.WriteBytes
+	}
+	return d
 }
 
 func (ht *hcsTask) Wait() *task.StateResponse {
@@ -506,7 +694,7 @@ func (ht *hcsTask) close(ctx context.Context) {
 			if err != nil {
 				log.G(ctx).WithError(err).Error("failed to shutdown container")
 			} else {
-				t := time.NewTimer(time.Second * 30)
+				t := time.NewTimer(ht.shutdownTimeout())
 				select {
 				case <-ch:
 					err = werr
@@ -547,6 +735,9 @@ func (ht *hcsTask) close(ctx context.Context) {
 			if err := ht.c.Close(); err != nil {
 				log.G(ctx).WithError(err).Error("failed to close container")
 			}
+			if ht.oom != nil {
+				ht.oom.close()
+			}
 		}
 		ht.closeHost(ctx)
 	})
@@ -570,8 +761,18 @@ func (ht *hcsTask) closeHost(ctx context.Context) {
 				log.G(ctx).WithError(err).Error("failed host vm shutdown")
 			}
 		}
-		// Send the `init` exec exit notification always.
+		// Send the `init` exec exit notification always, preceded by the
+		// resources it used and the reason if it is known. Closing the host
+		// above is not an unexpected exit, so the host is only a reason if it
+		// crashed.
 		exit := ht.init.Status()
+		if he, ok := ht.init.(*hcsExec); ok {
+			if usage := he.exitUsage(ctx); usage != nil {
+				publishExecUsage(ctx, ht.events, ht.id, exit.ID, usage)
+			}
+		}
+		f := ht.initExitFacts()
+		publishExitReason(ctx, ht.events, ht.id, exit.ID, &f)
 		ht.events.publishEvent(
 			ctx,
 			runtime.TaskExitEventTopic,
@@ -586,6 +787,45 @@ func (ht *hcsTask) closeHost(ctx context.Context) {
 	})
 }
 
+// initExitFacts returns what this task observed about the exit of its init
+// exec.
+func (ht *hcsTask) initExitFacts() exitFacts {
+	var f exitFacts
+	if he, ok := ht.init.(*hcsExec); ok {
+		f = he.exitFacts()
+	} else {
+		f.exitStatus = ht.init.Status().ExitStatus
+		f.setHost(ht.host)
+	}
+	f.stopTimedOut = atomic.LoadUint32(&ht.stopTimedOut) == 1
+	f.oomKilled = ht.oomKilled()
+	return f
+}
+
+// oomKilled returns true if the container of this task reported that it ran
+// out of memory.
+func (ht *hcsTask) oomKilled() bool {
+	if ht.oom != nil {
+		return ht.oom.OOMKilled()
+	}
+	return ht.c != nil && containerOOMKilled(ht.c)
+}
+
+// watchOOM starts watching the job object of the container of this task for
+// it running out of memory, if it is a process isolated WCOW container. For
+// other containers the guest reports it, if at all.
+func (ht *hcsTask) watchOOM(ctx context.Context) {
+	if !ht.isWCOW || ht.host != nil {
+		return
+	}
+	oom, err := watchJobMemoryLimit(siloJobObjectName(ht.id))
+	if err != nil {
+		log.G(ctx).WithError(err).Warn("failed to watch container job object, out of memory exits will not be reported")
+		return
+	}
+	ht.oom = oom
+}
+
 func (ht *hcsTask) ExecInHost(ctx context.Context, req *shimdiag.ExecProcessRequest) (int, error) {
 	if ht.host == nil {
 		return 0, errors.New("task is not isolated")
@@ -605,14 +845,292 @@ func (ht *hcsTask) DumpGuestStacks(ctx context.Context) string {
 	return ""
 }
 
+func (ht *hcsTask) DeviceInventory(ctx context.Context) (*shimdiag.InventoryResponse, error) {
+	if ht.host == nil {
+		return nil, errors.Wrapf(errdefs.ErrFailedPrecondition, "task '%s' is not isolated", ht.id)
+	}
+	return uvmInventory(ht.host), nil
+}
+
+func (ht *hcsTask) GuestLog(ctx context.Context, req *shimdiag.GuestLogRequest) (*shimdiag.GuestLogResponse, error) {
+	if ht.host == nil {
+		return nil, errors.Wrapf(errdefs.ErrFailedPrecondition, "task '%s' is not isolated", ht.id)
+	}
+	return capturedGuestLog(ht.host, req)
+}
+
+func (ht *hcsTask) HostHealth(ctx context.Context, pingTimeout time.Duration) (*shimdiag.UtilityVMHealth, error) {
+	if ht.host == nil {
+		return nil, errors.Wrapf(errdefs.ErrFailedPrecondition, "task '%s' is not isolated", ht.id)
+	}
+	return uvmHealth(ctx, ht.host, pingTimeout), nil
+}
+
+// Stats returns the statistics that could be collected, as the container and
+// the UVM it owns are queried separately. A query that fails is logged and its
+// statistics are left out.
 func (ht *hcsTask) Stats(ctx context.Context) (*stats.Statistics, error) {
-	stats := &stats.Statistics{}
+	s := &stats.Statistics{}
+	if ht.isWCOW {
+		props, err := ht.c.Properties(ctx, schema1.PropertyTypeStatistics)
+		if err != nil {
+			log.G(ctx).WithError(err).Warn("failed to get container statistics")
+		} else {
+			s.Container = &stats.Statistics_Windows{Windows: windowsContainerStats(&props.Statistics)}
+		}
+	} else if c, ok := ht.c.(linuxMetricsContainer); ok {
+		props, err := c.PropertiesV2(ctx, schema1.PropertyTypeStatistics)
+		if err != nil {
+			log.G(ctx).WithError(err).Warn("failed to get container statistics")
+		} else if l := linuxContainerStats(time.Now(), props.Metrics); l != nil {
+			s.Container = &stats.Statistics_Linux{Linux: l}
+		}
+	}
 	if ht.ownsHost && ht.host != nil {
 		vmStats, err := ht.host.Stats(ctx)
 		if err != nil {
-			return nil, err
+			log.G(ctx).WithError(err).Warn("failed to get utility VM statistics")
+		} else {
+			s.VM = vmStats
+		}
+	}
+	return s, nil
+}
+
+// windowsContainerStats converts the HCS statistics of a Windows container.
+func windowsContainerStats(s *schema1.Statistics) *stats.WindowsContainerStatistics {
+	return &stats.WindowsContainerStatistics{
+		Timestamp:          s.Timestamp,
+		ContainerStartTime: s.ContainerStartTime,
+		UptimeNS:           s.Uptime100ns * 100,
+		Processor: &stats.WindowsContainerProcessorStatistics{
+			TotalRuntimeNS:  s.Processor.TotalRuntime100ns * 100,
+			RuntimeUserNS:   s.Processor.RuntimeUser100ns * 100,
+			RuntimeKernelNS: s.Processor.RuntimeKernel100ns * 100,
+		},
+		Memory: &stats.WindowsContainerMemoryStatistics{
+			MemoryUsageCommitBytes:            s.Memory.UsageCommitBytes,
+			MemoryUsageCommitPeakBytes:        s.Memory.UsageCommitPeakBytes,
+			MemoryUsagePrivateWorkingSetBytes: s.Memory.UsagePrivateWorkingSetBytes,
+		},
+		Storage: &stats.WindowsContainerStorageStatistics{
+			ReadCountNormalized:  s.Storage.ReadCountNormalized,
+			ReadSizeBytes:        s.Storage.ReadSizeBytes,
+			WriteCountNormalized: s.Storage.WriteCountNormalized,
+			WriteSizeBytes:       s.Storage.WriteSizeBytes,
+		},
+		Network: containerNetworkStats(s.Network),
+	}
+}
+
+// linuxMetricsContainer is a Linux container that reports its cgroup
+// statistics, which both the GCS and the HCS containers of a Linux UVM do.
+type linuxMetricsContainer interface {
+	PropertiesV2(ctx context.Context, types ...schema1.PropertyType) (*hcsschema.Properties, error)
+}
+
+// linuxContainerStats converts the cgroup statistics `m` of a Linux container
+// queried from the guest at `now`. The working set is the memory usage without
+// the inactive file cache, as the kubelet computes it.
+//
+// Returns nil if the guest did not report statistics, which older guests do
+// not.
+func linuxContainerStats(now time.Time, m *hcsschema.LinuxMetrics) *stats.LinuxContainerStatistics {
+	if m == nil {
+		return nil
+	}
+	s := &stats.LinuxContainerStatistics{
+		Timestamp: now,
+		CPU:       &stats.LinuxContainerCPUStatistics{},
+		Memory:    &stats.LinuxContainerMemoryStatistics{},
+		Blkio:     &stats.LinuxContainerBlkioStatistics{},
+	}
+	if m.CPU != nil && m.CPU.Usage != nil {
+		s.CPU.UsageTotalNS = m.CPU.Usage.Total
+		s.CPU.UsageUserNS = m.CPU.Usage.User
+		s.CPU.UsageKernelNS = m.CPU.Usage.Kernel
+	}
+	if m.Memory != nil && m.Memory.Usage != nil {
+		s.Memory.UsageBytes = m.Memory.Usage.Usage
+		s.Memory.MaxUsageBytes = m.Memory.Usage.Max
+		s.Memory.LimitBytes = m.Memory.Usage.Limit
+		if m.Memory.Usage.Usage > m.Memory.TotalInactiveFile {
+			s.Memory.WorkingSetBytes = m.Memory.Usage.Usage - m.Memory.TotalInactiveFile
+		}
+	}
+	if m.Blkio != nil {
+		s.Blkio.ReadOps, s.Blkio.WriteOps = sumBlkio(m.Blkio.IoServicedRecursive)
+		s.Blkio.ReadBytes, s.Blkio.WriteBytes = sumBlkio(m.Blkio.IoServiceBytesRecursive)
+	}
+	if m.Pids != nil {
+		s.Pids = &stats.LinuxContainerPidsStatistics{
+			Current: m.Pids.Current,
+			Limit:   m.Pids.Limit,
+		}
+	}
+	return s
+}
+
+// sumBlkio returns the totals of the read and write entries of the cgroup
+// block I/O statistics `entries` over all devices.
+func sumBlkio(entries []hcsschema.LinuxBlkioEntry) (read, write uint64) {
+	for _, e := range entries {
+		switch e.Op {
+		case "Read":
+			read += e.Value
+		case "Write":
+			write += e.Value
 		}
-		stats.VM = vmStats
 	}
-	return stats, nil
+	return read, write
+}
+
+// containerNetworkStats converts the statistics of each network endpoint of a
+// container.
+func containerNetworkStats(endpoints []schema1.NetworkStats) []*stats.ContainerNetworkStatistics {
+	var network []*stats.ContainerNetworkStatistics
+	for _, e := range endpoints {
+		network = append(network, &stats.ContainerNetworkStatistics{
+			EndpointID:             e.EndpointId,
+			InstanceID:             e.InstanceId,
+			BytesReceived:          e.BytesReceived,
+			BytesSent:              e.BytesSent,
+			PacketsReceived:        e.PacketsReceived,
+			PacketsSent:            e.PacketsSent,
+			DroppedPacketsIncoming: e.DroppedPacketsIncoming,
+			DroppedPacketsOutgoing: e.DroppedPacketsOutgoing,
+		})
+	}
+	return network
+}
+
+func (ht *hcsTask) Update(ctx context.Context, resources interface{}) error {
+	var hostResources interface{}
+	switch r := resources.(type) {
+	case *specs.WindowsResources:
+		if !ht.isWCOW {
+			return errors.Wrapf(errdefs.ErrInvalidArgument, "cannot update Linux task '%s' with Windows resources", ht.id)
+		}
+		hostResources = r
+	case *specs.LinuxResources:
+		if ht.isWCOW {
+			return errors.Wrapf(errdefs.ErrInvalidArgument, "cannot update Windows task '%s' with Linux resources", ht.id)
+		}
+		// Linux CPU limits only apply to the container.
+		hostResources = &specs.LinuxResources{Memory: r.Memory}
+	default:
+		return errors.Wrapf(errdefs.ErrInvalidArgument, "invalid resources type %T", resources)
+	}
+	if ht.ownsHost && ht.host != nil && !ht.isSandbox {
+		// Resize the UVM first so that it has room for the new limits of the
+		// container.
+		if err := updateUVMResources(ctx, ht.host, hostResources, atomic.LoadUint64(&ht.memoryLimit)); err != nil {
+			return err
+		}
+	}
+
+	switch r := resources.(type) {
+	case *specs.WindowsResources:
+		if err := hcsoci.UpdateWCOWContainer(ctx, ht.c, r); err != nil {
+			return err
+		}
+		if r.Memory != nil && r.Memory.Limit != nil {
+			atomic.StoreUint64(&ht.memoryLimit, *r.Memory.Limit)
+		}
+	case *specs.LinuxResources:
+		if err := hcsoci.UpdateLCOWContainer(ctx, ht.c, r); err != nil {
+			return err
+		}
+		if r.Memory != nil && r.Memory.Limit != nil && *r.Memory.Limit > 0 {
+			atomic.StoreUint64(&ht.memoryLimit, uint64(*r.Memory.Limit))
+		}
+	}
+	return nil
+}
+
+// uvmCheckpointFile is the file in the checkpoint directory of a task that the
+// state of its UVM is saved to.
+const uvmCheckpointFile = "uvm.vmrs"
+
+func (ht *hcsTask) Checkpoint(ctx context.Context, path string, exit bool) error {
+	if !ht.ownsHost || ht.host == nil {
+		return errors.Wrapf(errdefs.ErrFailedPrecondition, "task '%s' does not own a UVM", ht.id)
+	}
+	return ht.host.Save(ctx, filepath.Join(path, uvmCheckpointFile), exit)
+}
+
+// updateUVMResources resizes `host` for its container to be updated to
+// `resources` from the memory limit `oldLimit`. The memory of the UVM is sized
+// as described by `uvmMemorySizeInMB`. Only the memory limit applies to an
+// LCOW UVM, as Linux CPU shares and quotas have no equivalent UVM setting.
+// Setting any Linux CPU field fails with `ErrNotImplemented` rather than being
+// ignored.
+func updateUVMResources(ctx context.Context, host *uvm.UtilityVM, resources interface{}, oldLimit uint64) error {
+	var (
+		memoryLimit                   uint64
+		cpuCount, cpuLimit, cpuWeight int32
+	)
+	switch r := resources.(type) {
+	case *specs.WindowsResources:
+		if r.Memory != nil && r.Memory.Limit != nil {
+			memoryLimit = *r.Memory.Limit
+		}
+		if r.CPU != nil {
+			if r.CPU.Count != nil {
+				cpuCount = int32(*r.CPU.Count)
+			}
+			if r.CPU.Maximum != nil {
+				cpuLimit = int32(*r.CPU.Maximum)
+			}
+			if r.CPU.Shares != nil {
+				cpuWeight = int32(*r.CPU.Shares)
+			}
+		}
+	case *specs.LinuxResources:
+		if r.CPU != nil && !reflect.DeepEqual(*r.CPU, specs.LinuxCPU{}) {
+			return errors.Wrap(errdefs.ErrNotImplemented, "updating the Linux CPU resources of a UVM is not supported")
+		}
+		if r.Memory != nil && r.Memory.Limit != nil && *r.Memory.Limit > 0 {
+			memoryLimit = uint64(*r.Memory.Limit)
+		}
+	default:
+		return errors.Wrapf(errdefs.ErrInvalidArgument, "invalid resources type %T", resources)
+	}
+
+	if memoryLimit != 0 {
+		usage, err := host.MemoryWorkingSet(ctx)
+		if err != nil {
+			return err
+		}
+		size := host.MemorySizeInMB()
+		if newSize := uvmMemorySizeInMB(size, usage, oldLimit, memoryLimit); newSize != size {
+			if err := host.UpdateMemory(ctx, newSize); err != nil {
+				return err
+			}
+		}
+	}
+	return host.UpdateProcessor(ctx, cpuCount, cpuLimit, cpuWeight)
+}
+
+// uvmMemorySizeInMB returns the memory a UVM of `sizeInMB`, of which
+// `usageBytes` are in use, must have once the memory limit of its container
+// changes from `oldLimit` to `newLimit` bytes. The UVM keeps the memory it has
+// on top of the old limit of its container for the guest itself. If that is
+// not known, because the container had no limit or one that exceeded the UVM,
+// the UVM only grows to fit the new limit. The UVM never shrinks below the
+// memory in use.
+func uvmMemorySizeInMB(sizeInMB int32, usageBytes, oldLimit, newLimit uint64) int32 {
+	const mb = 1024 * 1024
+	size := uint64(sizeInMB) * mb
+	target := size
+	if oldLimit != 0 && oldLimit < size {
+		target = newLimit + (size - oldLimit)
+	} else if newLimit > size {
+		target = newLimit
+	}
+	if target < usageBytes {
+		target = usageBytes
+	}
+	// OCI is in Bytes. The UVM is sized in MB.
+	return int32((target + mb - 1) / mb)
 }
@@ -2,12 +2,19 @@ package main
 
 import (
 	"context"
+	"errors"
 	"math/rand"
 	"strconv"
 	"testing"
 	"time"
 
+	"github.com/Microsoft/hcsshim/cmd/containerd-shim-runhcs-v1/stats"
+	"github.com/Microsoft/hcsshim/internal/cow"
+	"github.com/Microsoft/hcsshim/internal/schema1"
+	hcsschema "github.com/Microsoft/hcsshim/internal/schema2"
+	"github.com/Microsoft/hcsshim/internal/uvm"
 	"github.com/containerd/containerd/errdefs"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
 )
 
 func setupTestHcsTask(t *testing.T) (*hcsTask, *testShimExec, *testShimExec) {
@@ -301,3 +308,271 @@ func Test_hcsTask_DeleteExec_2ndExecID_ExitedState_Success(t *testing.T) {
 	}
 	verifyDeleteSuccessValues(t, pid, status, at, second)
 }
+
+func Test_windowsContainerStats(t *testing.T) {
+	now := time.Now()
+	s := windowsContainerStats(&schema1.Statistics{
+		Timestamp:   now,
+		Uptime100ns: 10,
+		Processor:   schema1.ProcessorStats{TotalRuntime100ns: 30, RuntimeUser100ns: 20, RuntimeKernel100ns: 10},
+		Memory:      schema1.MemoryStats{UsageCommitBytes: 4096, UsageCommitPeakBytes: 8192, UsagePrivateWorkingSetBytes: 2048},
+		Storage:     schema1.StorageStats{ReadSizeBytes: 512, WriteSizeBytes: 1024},
+		Network:     []schema1.NetworkStats{{EndpointId: "e1", BytesSent: 1}, {EndpointId: "e2", BytesReceived: 2}},
+	})
+	if !s.Timestamp.Equal(now) || s.UptimeNS != 1000 {
+		t.Fatalf("expected timestamp %s and uptime 1000ns, got %s and %dns", now, s.Timestamp, s.UptimeNS)
+	}
+	if s.Processor.TotalRuntimeNS != 3000 || s.Processor.RuntimeUserNS != 2000 || s.Processor.RuntimeKernelNS != 1000 {
+		t.Fatalf("unexpected processor statistics %+v", s.Processor)
+	}
+	if s.Memory.MemoryUsageCommitBytes != 4096 || s.Memory.MemoryUsageCommitPeakBytes != 8192 || s.Memory.MemoryUsagePrivateWorkingSetBytes != 2048 {
+		t.Fatalf("unexpected memory statistics %+v", s.Memory)
+	}
+	if s.Storage.ReadSizeBytes != 512 || s.Storage.WriteSizeBytes != 1024 {
+		t.Fatalf("unexpected storage statistics %+v", s.Storage)
+	}
+	if len(s.Network) != 2 || s.Network[0].EndpointID != "e1" || s.Network[0].BytesSent != 1 || s.Network[1].EndpointID != "e2" || s.Network[1].BytesReceived != 2 {
+		t.Fatalf("unexpected network statistics %+v", s.Network)
+	}
+}
+
+func Test_linuxContainerStats(t *testing.T) {
+	now := time.Now()
+	s := linuxContainerStats(now, &hcsschema.LinuxMetrics{
+		CPU: &hcsschema.LinuxCPUStats{Usage: &hcsschema.LinuxCPUUsage{Total: 3000, User: 2000, Kernel: 1000}},
+		Memory: &hcsschema.LinuxMemoryStats{
+			TotalInactiveFile: 2048,
+			Usage:             &hcsschema.LinuxMemoryEntry{Usage: 4096, Max: 8192, Limit: 16384},
+		},
+		Blkio: &hcsschema.LinuxBlkioStats{
+			IoServicedRecursive: []hcsschema.LinuxBlkioEntry{
+				{Op: "Read", Major: 8, Value: 1},
+				{Op: "Read", Major: 8, Minor: 16, Value: 1},
+				{Op: "Write", Major: 8, Value: 2},
+				{Op: "Total", Major: 8, Value: 4},
+			},
+			IoServiceBytesRecursive: []hcsschema.LinuxBlkioEntry{{Op: "Read", Value: 512}, {Op: "Write", Value: 1024}},
+		},
+		Pids: &hcsschema.LinuxPidsStats{Current: 3, Limit: 10},
+	})
+	if !s.Timestamp.Equal(now) {
+		t.Fatalf("expected the statistics at %v, got %v", now, s.Timestamp)
+	}
+	if s.CPU.UsageTotalNS != 3000 || s.CPU.UsageUserNS != 2000 || s.CPU.UsageKernelNS != 1000 {
+		t.Fatalf("unexpected cpu statistics %+v", s.CPU)
+	}
+	if s.Memory.UsageBytes != 4096 || s.Memory.MaxUsageBytes != 8192 || s.Memory.WorkingSetBytes != 2048 || s.Memory.LimitBytes != 16384 {
+		t.Fatalf("unexpected memory statistics %+v", s.Memory)
+	}
+	if s.Blkio.ReadOps != 2 || s.Blkio.ReadBytes != 512 || s.Blkio.WriteOps != 2 || s.Blkio.WriteBytes != 1024 {
+		t.Fatalf("unexpected blkio statistics %+v", s.Blkio)
+	}
+	if s.Pids.Current != 3 || s.Pids.Limit != 10 {
+		t.Fatalf("unexpected pids statistics %+v", s.Pids)
+	}
+}
+
+func Test_linuxContainerStats_NotReported(t *testing.T) {
+	if s := linuxContainerStats(time.Now(), nil); s != nil {
+		t.Fatalf("expected no statistics when the guest reports none, got %+v", s)
+	}
+}
+
+func Test_hcsTask_Pids_ExitedInit_FinalUsage(t *testing.T) {
+	usage := startExecUsage(context.TODO(), nil, 10, false)
+	usage.usage.UserTime_100Ns = 20
+	usage.usage.MemoryWorkingSetPeakBytes = 4096
+	ht := &hcsTask{
+		id: t.Name(),
+		init: &hcsExec{
+			tid:   t.Name(),
+			id:    t.Name(),
+			pid:   10,
+			state: shimExecStateExited,
+			usage: usage,
+		},
+		closed: make(chan struct{}),
+	}
+
+	pids, err := ht.Pids(context.TODO())
+	if err != nil {
+		t.Fatalf("should not have failed with error: %v", err)
+	}
+	if len(pids) != 1 {
+		t.Fatalf("expected 1 pid, got: %d", len(pids))
+	}
+	p := pids[0]
+	if p.ProcessID != 10 || p.ExecID != t.Name() || !p.Exited {
+		t.Fatalf("expected the exited init exec, got: %+v", p)
+	}
+	if p.UserTime_100Ns != 20 || p.MemoryWorkingSetPeakBytes != 4096 {
+		t.Fatalf("expected the final usage, got: %+v", p)
+	}
+}
+
+func Test_hcsTask_Stats_Linux(t *testing.T) {
+	lt, _, _ := setupTestHcsTask(t)
+	lt.c = &testLinuxContainer{
+		testContainer: testContainer{id: t.Name()},
+		metrics:       &hcsschema.LinuxMetrics{Memory: &hcsschema.LinuxMemoryStats{Usage: &hcsschema.LinuxMemoryEntry{Usage: 4096}}},
+	}
+
+	s, err := lt.Stats(context.Background())
+	if err != nil {
+		t.Fatalf("should not have failed with error: %v", err)
+	}
+	l, ok := s.Container.(*stats.Statistics_Linux)
+	if !ok || l.Linux.Memory.UsageBytes != 4096 {
+		t.Fatalf("expected the cgroup statistics of the container, got %+v", s.Container)
+	}
+}
+
+func Test_hcsTask_Stats_PropertiesError_Partial(t *testing.T) {
+	lt, _, _ := setupTestHcsTask(t)
+	lt.isWCOW = true
+	lt.c = &testContainer{id: t.Name(), propertiesErr: errors.New("container stopped")}
+
+	s, err := lt.Stats(context.Background())
+	if err != nil {
+		t.Fatalf("should not have failed with error: %v", err)
+	}
+	if s.Container != nil {
+		t.Fatalf("expected no container statistics, got %+v", s.Container)
+	}
+}
+
+// testContainer is a cow.Container that records the requests to modify it and
+// to query its properties.
+type testContainer struct {
+	cow.Container
+	id            string
+	modifies      []interface{}
+	processList   []schema1.ProcessListItem
+	properties    int
+	propertiesErr error
+}
+
+func (c *testContainer) ID() string {
+	return c.id
+}
+
+func (c *testContainer) Modify(ctx context.Context, config interface{}) error {
+	c.modifies = append(c.modifies, config)
+	return nil
+}
+
+func (c *testContainer) Properties(ctx context.Context, types ...schema1.PropertyType) (*schema1.ContainerProperties, error) {
+	c.properties++
+	if c.propertiesErr != nil {
+		return nil, c.propertiesErr
+	}
+	return &schema1.ContainerProperties{ProcessList: c.processList}, nil
+}
+
+// testLinuxContainer is a testContainer that reports cgroup statistics.
+type testLinuxContainer struct {
+	testContainer
+	metrics *hcsschema.LinuxMetrics
+}
+
+func (c *testLinuxContainer) PropertiesV2(ctx context.Context, types ...schema1.PropertyType) (*hcsschema.Properties, error) {
+	return &hcsschema.Properties{Metrics: c.metrics}, nil
+}
+
+func Test_hcsTask_Update_OwnedHost_UpdatesContainer(t *testing.T) {
+	lt, _, _ := setupTestHcsTask(t)
+	c := &testContainer{id: t.Name()}
+	lt.c = c
+	lt.ownsHost = true
+	limit := int64(512 * 1024 * 1024)
+
+	err := lt.Update(context.TODO(), &specs.LinuxResources{Memory: &specs.LinuxMemory{Limit: &limit}})
+
+	if err != nil {
+		t.Fatalf("expected nil err got: %v", err)
+	}
+	if len(c.modifies) != 1 {
+		t.Fatalf("expected the container to be updated once, got %d requests", len(c.modifies))
+	}
+	if lt.memoryLimit != uint64(limit) {
+		t.Fatalf("expected memory limit %d, got %d", limit, lt.memoryLimit)
+	}
+}
+
+func Test_hcsTask_Update_Sandbox_SkipsUVM(t *testing.T) {
+	lt, _, _ := setupTestHcsTask(t)
+	c := &testContainer{id: t.Name()}
+	lt.c = c
+	lt.ownsHost = true
+	lt.isSandbox = true
+	// The UVM of a pod is not resized, which would fail for this UVM.
+	lt.host = &uvm.UtilityVM{}
+	limit := int64(512 * 1024 * 1024)
+
+	err := lt.Update(context.TODO(), &specs.LinuxResources{Memory: &specs.LinuxMemory{Limit: &limit}})
+
+	if err != nil {
+		t.Fatalf("expected nil err got: %v", err)
+	}
+	if len(c.modifies) != 1 {
+		t.Fatalf("expected the container to be updated once, got %d requests", len(c.modifies))
+	}
+}
+
+func Test_uvmMemorySizeInMB(t *testing.T) {
+	const mb = 1024 * 1024
+	for _, tc := range []struct {
+		name               string
+		size               int32
+		usage              uint64
+		oldLimit, newLimit uint64
+		expected           int32
+	}{
+		{"Grow keeps overhead", 1024, 300 * mb, 512 * mb, 768 * mb, 1280},
+		{"Shrink keeps overhead", 1024, 300 * mb, 512 * mb, 256 * mb, 768},
+		{"Shrink stops at usage", 1024, 900 * mb, 512 * mb, 256 * mb, 900},
+		{"Usage rounds up", 1024, 900*mb + 1, 512 * mb, 256 * mb, 901},
+		{"No old limit grows to fit", 1024, 300 * mb, 0, 2048 * mb, 2048},
+		{"No old limit does not shrink", 1024, 300 * mb, 0, 256 * mb, 1024},
+		{"Old limit above UVM does not shrink", 1024, 300 * mb, 4096 * mb, 512 * mb, 1024},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if size := uvmMemorySizeInMB(tc.size, tc.usage, tc.oldLimit, tc.newLimit); size != tc.expected {
+				t.Fatalf("expected %dMB, got %dMB", tc.expected, size)
+			}
+		})
+	}
+}
+
+func Test_hcsTask_Update_WCOW_LinuxResources_Error(t *testing.T) {
+	lt, _, _ := setupTestHcsTask(t)
+	lt.isWCOW = true
+
+	err := lt.Update(context.TODO(), &specs.LinuxResources{})
+
+	verifyExpectedError(t, nil, err, errdefs.ErrInvalidArgument)
+}
+
+func Test_hcsTask_Update_LCOW_WindowsResources_Error(t *testing.T) {
+	lt, _, _ := setupTestHcsTask(t)
+
+	err := lt.Update(context.TODO(), &specs.WindowsResources{})
+
+	verifyExpectedError(t, nil, err, errdefs.ErrInvalidArgument)
+}
+
+func Test_hcsTask_Update_InvalidResourcesType_Error(t *testing.T) {
+	lt, _, _ := setupTestHcsTask(t)
+
+	err := lt.Update(context.TODO(), &specs.Process{})
+
+	verifyExpectedError(t, nil, err, errdefs.ErrInvalidArgument)
+}
+
+func Test_updateUVMResources_LinuxCPU_Error(t *testing.T) {
+	shares := uint64(512)
+
+	err := updateUVMResources(context.TODO(), nil, &specs.LinuxResources{CPU: &specs.LinuxCPU{Shares: &shares}}, 0)
+
+	verifyExpectedError(t, nil, err, errdefs.ErrNotImplemented)
+}
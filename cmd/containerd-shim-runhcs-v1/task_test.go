@@ -16,10 +16,18 @@ import (
 var _ = (shimTask)(&testShimTask{})
 
 type testShimTask struct {
-	id string
+	id      string
+	sidecar bool
 
 	exec  *testShimExec
 	execs map[string]*testShimExec
+
+	// checkpointExit is the `exit` of the last Checkpoint.
+	checkpointExit bool
+}
+
+func (tst *testShimTask) isSidecar() bool {
+	return tst.sidecar
 }
 
 func (tst *testShimTask) ID() string {
@@ -89,6 +97,27 @@ func (wpst *testShimTask) DumpGuestStacks(ctx context.Context) string {
 	return ""
 }
 
+func (tst *testShimTask) DeviceInventory(ctx context.Context) (*shimdiag.InventoryResponse, error) {
+	return nil, errdefs.ErrFailedPrecondition
+}
+
+func (tst *testShimTask) GuestLog(ctx context.Context, req *shimdiag.GuestLogRequest) (*shimdiag.GuestLogResponse, error) {
+	return nil, errdefs.ErrFailedPrecondition
+}
+
+func (tst *testShimTask) HostHealth(ctx context.Context, pingTimeout time.Duration) (*shimdiag.UtilityVMHealth, error) {
+	return nil, errdefs.ErrFailedPrecondition
+}
+
 func (tst *testShimTask) Stats(ctx context.Context) (*stats.Statistics, error) {
 	return nil, errdefs.ErrNotImplemented
 }
+
+func (tst *testShimTask) Update(ctx context.Context, resources interface{}) error {
+	return errdefs.ErrNotImplemented
+}
+
+func (tst *testShimTask) Checkpoint(ctx context.Context, path string, exit bool) error {
+	tst.checkpointExit = exit
+	return errdefs.ErrFailedPrecondition
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"path/filepath"
 	"sync"
 	"time"
 
@@ -51,6 +52,35 @@ func newWcowPodSandboxTask(ctx context.Context, events publisher, id, bundle str
 	return wpst
 }
 
+// newRecoveredWcowPodSandboxTask rebuilds the fake WCOW task `ts` recorded by
+// a shim that exited, in the reopened `parent`.
+func newRecoveredWcowPodSandboxTask(ctx context.Context, events publisher, parent *uvm.UtilityVM, ts *taskState) shimTask {
+	ctx, span := trace.StartSpan(ctx, "newRecoveredWcowPodSandboxTask")
+	defer span.End()
+	span.AddAttributes(trace.StringAttribute("tid", ts.ID))
+
+	init := newWcowPodSandboxExec(ctx, events, ts.ID, ts.Bundle)
+	init.state = ts.Init.State
+	init.pid = ts.Init.Pid
+	if ts.Init.State == shimExecStateExited {
+		init.exitStatus = ts.Init.ExitStatus
+		init.exitedAt = ts.Init.ExitedAt
+		close(init.exited)
+	}
+	wpst := &wcowPodSandboxTask{
+		events: events,
+		id:     ts.ID,
+		init:   init,
+		host:   parent,
+		closed: make(chan struct{}),
+	}
+	if parent != nil {
+		go wpst.waitParentExit()
+	}
+	go wpst.waitInitExit()
+	return wpst
+}
+
 var _ = (shimTask)(&wcowPodSandboxTask{})
 
 // wcowPodSandboxTask is a special task type that actually holds no real
@@ -174,8 +204,12 @@ func (wpst *wcowPodSandboxTask) close(ctx context.Context) {
 				log.G(ctx).WithError(err).Error("failed host vm shutdown")
 			}
 		}
-		// Send the `init` exec exit notification always.
+		// Send the `init` exec exit notification always, preceded by the
+		// reason if the host crashed.
 		exit := wpst.init.Status()
+		f := exitFacts{exitStatus: exit.ExitStatus}
+		f.setHost(wpst.host)
+		publishExitReason(ctx, wpst.events, wpst.id, exit.ID, &f)
 		wpst.events.publishEvent(
 			ctx,
 			runtime.TaskExitEventTopic,
@@ -239,7 +273,47 @@ func (wpst *wcowPodSandboxTask) DumpGuestStacks(ctx context.Context) string {
 	return ""
 }
 
+func (wpst *wcowPodSandboxTask) DeviceInventory(ctx context.Context) (*shimdiag.InventoryResponse, error) {
+	if wpst.host == nil {
+		return nil, errors.Wrapf(errdefs.ErrFailedPrecondition, "pod '%s' is not isolated", wpst.id)
+	}
+	return uvmInventory(wpst.host), nil
+}
+
+func (wpst *wcowPodSandboxTask) GuestLog(ctx context.Context, req *shimdiag.GuestLogRequest) (*shimdiag.GuestLogResponse, error) {
+	if wpst.host == nil {
+		return nil, errors.Wrapf(errdefs.ErrFailedPrecondition, "pod '%s' is not isolated", wpst.id)
+	}
+	return capturedGuestLog(wpst.host, req)
+}
+
+func (wpst *wcowPodSandboxTask) HostHealth(ctx context.Context, pingTimeout time.Duration) (*shimdiag.UtilityVMHealth, error) {
+	if wpst.host == nil {
+		return nil, errors.Wrapf(errdefs.ErrFailedPrecondition, "pod '%s' is not isolated", wpst.id)
+	}
+	return uvmHealth(ctx, wpst.host, pingTimeout), nil
+}
+
 func (wpst *wcowPodSandboxTask) Stats(ctx context.Context) (*stats.Statistics, error) {
 	// TODO: Add support for WCOW UVM stats here.
 	return nil, errdefs.ErrNotImplemented
 }
+
+// Update does nothing, as the sandbox of a WCOW pod has no container and the
+// UVM of the pod is sized for all of its containers rather than for the
+// sandbox.
+func (wpst *wcowPodSandboxTask) Update(ctx context.Context, resources interface{}) error {
+	switch resources.(type) {
+	case *specs.WindowsResources:
+		return nil
+	default:
+		return errors.Wrapf(errdefs.ErrInvalidArgument, "invalid resources type %T for Windows pod '%s'", resources, wpst.id)
+	}
+}
+
+func (wpst *wcowPodSandboxTask) Checkpoint(ctx context.Context, path string, exit bool) error {
+	if wpst.host == nil {
+		return errors.Wrapf(errdefs.ErrFailedPrecondition, "pod '%s' is not isolated", wpst.id)
+	}
+	return wpst.host.Save(ctx, filepath.Join(path, uvmCheckpointFile), exit)
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Microsoft/go-winio"
+	runhcsopts "github.com/Microsoft/hcsshim/cmd/containerd-shim-runhcs-v1/options"
+	"github.com/Microsoft/hcsshim/internal/log"
+	"github.com/Microsoft/hcsshim/internal/uvm"
+	"github.com/Microsoft/hcsshim/internal/uvmpool"
+	"github.com/containerd/typeurl"
+	"github.com/gogo/protobuf/proto"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+const (
+	// uvmPoolAddrFmt is the address of the utility VM pool of a namespace.
+	uvmPoolAddrFmt = "\\\\.\\pipe\\ProtectedPrefix\\Administrators\\containerd-shim-runhcs-v1-uvmpool-%s"
+	// uvmPoolIdleTimeout is how long the utility VM pool process keeps running
+	// without a request for a utility VM.
+	uvmPoolIdleTimeout = 30 * time.Minute
+	// pooledVMFile is the file in the bundle of a pod that holds the compute
+	// system ID of its pooled utility VM. A pooled utility VM is recoverable,
+	// so delete terminates it if the shim did not.
+	pooledVMFile = "pooled-vm"
+)
+
+// getPooledUVM returns a started utility VM for the pod in `bundle` from the
+// utility VM pool of the namespace, starting the pool process configured with
+// `config` if it is not running. The pool process logs as configured by
+// `shimOpts`. It returns nil if the pool has no idle utility VM for `opts`.
+func getPooledUVM(ctx context.Context, config uvmpool.Config, opts *uvm.OptionsLCOW, bundle string, shimOpts *runhcsopts.Options) (_ *uvm.UtilityVM, err error) {
+	address := fmt.Sprintf(uvmPoolAddrFmt, namespaceFlag)
+	timeout := time.Second
+	if c, err := winio.DialPipe(address, &timeout); err == nil {
+		c.Close()
+	} else if err := startUVMPool(address, config, shimOpts); err != nil {
+		return nil, err
+	}
+
+	vm, err := uvmpool.Get(ctx, address, opts)
+	if err != nil || vm == nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			vm.Close()
+		}
+	}()
+	if err := ioutil.WriteFile(filepath.Join(bundle, pooledVMFile), []byte(vm.SystemID()), 0644); err != nil {
+		return nil, err
+	}
+	return vm, nil
+}
+
+// startUVMPool starts the utility VM pool process serving `address` and
+// returns once it is serving. Another shim may have started it in the
+// meantime, in which case the new process fails and its error is ignored.
+//
+// The pool process reads `shimOpts` from stdin, as a shim does, to set up its
+// log output.
+func startUVMPool(address string, config uvmpool.Config, shimOpts *runhcsopts.Options) error {
+	self, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	c, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+	var opts []byte
+	if shimOpts != nil {
+		a, err := typeurl.MarshalAny(shimOpts)
+		if err != nil {
+			return err
+		}
+		opts, err = proto.Marshal(a)
+		if err != nil {
+			return err
+		}
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	defer w.Close()
+
+	cmd := &exec.Cmd{
+		Path: self,
+		Args: []string{
+			self,
+			"--namespace", namespaceFlag,
+			"--address", addressFlag,
+			"--publish-binary", containerdBinaryFlag,
+			"--id", "uvmpool",
+			"serve-uvmpool",
+			"--socket", address,
+			"--config", string(c),
+		},
+		Env:    os.Environ(),
+		Stdin:  bytes.NewReader(opts),
+		Stdout: w,
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	w.Close()
+	// The pool process closes stdout once it is serving, or exits.
+	io.Copy(ioutil.Discard, r)
+	return nil
+}
+
+var serveUVMPoolCommand = cli.Command{
+	Name:           "serve-uvmpool",
+	Hidden:         true,
+	SkipArgReorder: true,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "socket",
+			Usage: "the socket path to serve",
+		},
+		cli.StringFlag{
+			Name:  "config",
+			Usage: "the JSON configuration of the pool",
+		},
+	},
+	Action: func(ctx *cli.Context) error {
+		// The utility VM pool is served by its own process, shared by the
+		// shims of all pods in the namespace, so that the utility VMs booted
+		// ahead of time outlive the pod shims. The first shim that asks for a
+		// pooled utility VM starts it with the configuration of its pod. It
+		// closes the pool and exits once no utility VM was requested for
+		// `uvmPoolIdleTimeout` and the output of every utility VM it handed
+		// out stopped being relayed, or when it is interrupted.
+		//
+		// It logs as configured by the shim options of the shim that started
+		// it, to ETW or to the log pipe of the shim ID "uvmpool".
+		shimOpts := &runhcsopts.Options{
+			Debug:     false,
+			DebugType: runhcsopts.Options_NPIPE,
+		}
+		newShimOpts, err := readOptions(os.Stdin)
+		if err != nil {
+			return errors.Wrap(err, "failed to read shim options from stdin")
+		} else if newShimOpts != nil {
+			shimOpts = newShimOpts
+		}
+		os.Stdin.Close()
+		lerrs, logl, err := setupLogOutput(shimOpts)
+		if err != nil {
+			return err
+		}
+		if logl != nil {
+			defer logl.Close()
+		}
+
+		socket := ctx.String("socket")
+		if !strings.HasPrefix(socket, `\\.\pipe`) {
+			return errors.New("socket is required to be pipe address")
+		}
+		var config uvmpool.Config
+		if err := json.Unmarshal([]byte(ctx.String("config")), &config); err != nil {
+			return errors.Wrap(err, "failed to read pool configuration")
+		}
+
+		l, err := winio.ListenPipe(socket, nil)
+		if err != nil {
+			return err
+		}
+		p := uvmpool.New(config)
+		defer p.Close()
+
+		serrs := make(chan error, 1)
+		go func() {
+			serrs <- p.Serve(context.Background(), l)
+		}()
+		defer l.Close()
+		// Signal the shim that started us that the pool is served.
+		os.Stdout.Close()
+
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, os.Interrupt)
+		t := time.NewTicker(time.Minute)
+		defer t.Stop()
+		for {
+			select {
+			case err := <-serrs:
+				return err
+			case err := <-lerrs:
+				return err
+			case <-signals:
+				return nil
+			case <-t.C:
+				if time.Since(p.LastRequest()) > uvmPoolIdleTimeout && p.Relaying() == 0 {
+					log.G(context.Background()).Info("utility VM pool is idle, exiting")
+					return nil
+				}
+			}
+		}
+	},
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -516,6 +517,49 @@ func (c *container) Unmount(all bool) error {
 	return nil
 }
 
+// printContainerPlan writes the plan to create the container described by
+// `cfg` to `w` as JSON, without creating the container or its utility VM. For a
+// VM isolated container the plan is made against a utility VM built from the
+// spec's annotations, and the utility VM options are included.
+func printContainerPlan(cfg *containerConfig, w io.Writer) error {
+	ctx := context.Background()
+	opts := &hcsoci.CreateOptions{
+		ID:    cfg.ID,
+		Owner: cfg.Owner,
+		Spec:  cfg.Spec,
+	}
+	if cfg.Spec.Windows != nil && cfg.Spec.Windows.Network != nil {
+		opts.NetworkNamespace = cfg.Spec.Windows.Network.NetworkNamespace
+	}
+
+	var uvmOpts interface{}
+	if oci.IsIsolated(cfg.Spec) && (oci.IsLCOW(cfg.Spec) || osversion.Get().Build >= osversion.RS5) {
+		var err error
+		uvmOpts, err = oci.SpecToUVMCreateOpts(ctx, cfg.Spec, vmID(cfg.ID), cfg.Owner)
+		if err != nil {
+			return err
+		}
+		opts.DryRunHost, err = hcsoci.NewDryRunHost(uvmOpts)
+		if err != nil {
+			return err
+		}
+	}
+
+	plan, err := hcsoci.PlanContainer(ctx, opts)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(struct {
+		UVM       interface{} `json:",omitempty"`
+		Container *hcsoci.Plan
+	}{
+		UVM:       uvmOpts,
+		Container: plan,
+	})
+}
+
 func createContainerInHost(c *container, vm *uvm.UtilityVM) (err error) {
 	if c.hc != nil {
 		return errors.New("container already created")
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"os"
+
 	"github.com/Microsoft/hcsshim/internal/appargs"
 	"github.com/urfave/cli"
 )
@@ -54,13 +56,21 @@ The specification file includes an args parameter. The args parameter is used
 to specify command(s) that get run when the container is started. To change the
 command(s) that get executed on start, edit the args parameter of the spec. See
 "runc spec --help" for more explanation.`,
-	Flags:  append(createRunFlags),
+	Flags: append(createRunFlags,
+		cli.BoolFlag{
+			Name:  "dry-run",
+			Usage: "print the resources and documents that would be used to create the container as JSON, without creating it",
+		},
+	),
 	Before: appargs.Validate(argID),
 	Action: func(context *cli.Context) error {
 		cfg, err := containerConfigFromContext(context)
 		if err != nil {
 			return err
 		}
+		if context.Bool("dry-run") {
+			return printContainerPlan(cfg, os.Stdout)
+		}
 		_, err = createContainer(cfg)
 		if err != nil {
 			return err
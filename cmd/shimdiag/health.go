@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Microsoft/hcsshim/internal/appargs"
+	"github.com/Microsoft/hcsshim/internal/shimdiag"
+	"github.com/urfave/cli"
+)
+
+var healthTimeout time.Duration
+
+var healthCommand = cli.Command{
+	Name:      "health",
+	Usage:     "Reports the health of a shim, or a summary of the health of all shims",
+	ArgsUsage: "[shim name]",
+	Flags: []cli.Flag{
+		cli.DurationFlag{
+			Name:        "timeout",
+			Usage:       "how long a shim has to report its health before it is unresponsive",
+			Value:       10 * time.Second,
+			Destination: &healthTimeout},
+	},
+	Before: appargs.Validate(appargs.Optional(appargs.String)),
+	Action: func(c *cli.Context) error {
+		if c.NArg() == 0 {
+			return healthSummary()
+		}
+		shim, err := findShim(c.Args()[0])
+		if err != nil {
+			return err
+		}
+		resp, err := getHealth(shim)
+		if err != nil {
+			return err
+		}
+		printHealth(resp)
+		return nil
+	},
+}
+
+// getHealth returns the health of `shim`, failing if it does not respond
+// within `healthTimeout`.
+func getHealth(shim string) (*shimdiag.HealthResponse, error) {
+	client, err := getShim(shim)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), healthTimeout)
+	defer cancel()
+	// Leave the shim part of the timeout to report that its guest is
+	// unresponsive.
+	req := &shimdiag.HealthRequest{PingTimeoutMs: uint32(healthTimeout / 2 / time.Millisecond)}
+	return shimdiag.NewShimDiagClient(client).DiagHealth(ctx, req)
+}
+
+// healthStatus summarizes `resp` as `ok`, or `degraded` if the utility VM of
+// the shim has exited or its guest is unresponsive.
+func healthStatus(resp *shimdiag.HealthResponse) string {
+	if vm := resp.UtilityVm; vm != nil && (vm.State != "running" || !vm.GuestResponsive) {
+		return "degraded"
+	}
+	return "ok"
+}
+
+func healthSummary() error {
+	shims, err := findShims("")
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SHIM\tSTATUS\tGOROUTINES\tHANDLES\tRPCS\tEVENTS\tGUEST PING")
+	for _, shim := range shims {
+		resp, err := getHealth(shim)
+		if err != nil {
+			fmt.Fprintf(w, "%s\tunresponsive\t-\t-\t-\t-\t-\n", shim)
+			continue
+		}
+		ping := "-"
+		if vm := resp.UtilityVm; vm != nil {
+			if vm.GuestResponsive {
+				ping = (time.Duration(vm.PingMicroseconds) * time.Microsecond).String()
+			} else {
+				ping = "failed"
+			}
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t%d\t%s\n", shim, healthStatus(resp), resp.Goroutines, resp.Handles, resp.PendingRpcs, resp.EventBacklog, ping)
+	}
+	return w.Flush()
+}
+
+func printHealth(resp *shimdiag.HealthResponse) {
+	fmt.Printf("Shim: %s (%s)\n", resp.ID, healthStatus(resp))
+	fmt.Printf("Goroutines: %d\n", resp.Goroutines)
+	fmt.Printf("Handles: %d\n", resp.Handles)
+	fmt.Printf("Pending RPCs: %d\n", resp.PendingRpcs)
+	fmt.Printf("Event backlog: %d\n", resp.EventBacklog)
+	fmt.Printf("Events: %d published, %d retried, %d dropped\n", resp.EventsPublished, resp.EventsRetried, resp.EventsDropped)
+
+	if vm := resp.UtilityVm; vm != nil {
+		fmt.Printf("\nUtility VM: %s (%s)\n", vm.ID, vm.State)
+		if vm.ExitError != "" {
+			fmt.Printf("Exit error: %s\n", vm.ExitError)
+		}
+		if vm.State == "running" {
+			if vm.GuestResponsive {
+				fmt.Printf("Guest ping: %s\n", time.Duration(vm.PingMicroseconds)*time.Microsecond)
+			} else {
+				fmt.Printf("Guest ping: failed: %s\n", vm.PingError)
+			}
+			fmt.Printf("Pending guest RPCs: %d\n", vm.PendingGuestRpcs)
+		}
+	}
+
+	fmt.Println()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TASK\tSTATE\tEXECS\tLAST ERROR")
+	for _, t := range resp.Tasks {
+		lastError := "-"
+		if t.LastError != "" {
+			lastError = fmt.Sprintf("%s %s: %s", t.LastErrorAt, t.LastErrorMethod, t.LastError)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\n", t.ID, t.State, t.Execs, lastError)
+	}
+	w.Flush()
+}
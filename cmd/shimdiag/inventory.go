@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Microsoft/hcsshim/internal/appargs"
+	"github.com/Microsoft/hcsshim/internal/shimdiag"
+	"github.com/urfave/cli"
+)
+
+var inventoryCommand = cli.Command{
+	Name:      "inventory",
+	Usage:     "Lists the devices attached to the shim's utility VM and the containers using them",
+	ArgsUsage: "<shim name>",
+	Before:    appargs.Validate(appargs.String),
+	Action: func(c *cli.Context) error {
+		shim, err := getShim(c.Args()[0])
+		if err != nil {
+			return err
+		}
+		svc := shimdiag.NewShimDiagClient(shim)
+		resp, err := svc.DiagInventory(context.Background(), &shimdiag.InventoryRequest{})
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Utility VM: %s (%s)\n", resp.ID, resp.Os)
+		if resp.Os == "linux" {
+			fmt.Printf("VPMem: %d/%d devices\n", resp.VpmemDevices, resp.VpmemMaxDevices)
+		}
+		fmt.Printf("SCSI: %d/%d devices\n\n", resp.ScsiDevices, resp.ScsiMaxDevices)
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "KIND\tLOCATION\tHOST PATH\tUVM PATH\tREFS\tOWNERS")
+		for _, d := range resp.Devices {
+			hostPath := d.HostPath
+			if hostPath == "" {
+				hostPath = "-"
+			}
+			uvmPath := d.UvmPath
+			if d.NetworkNamespace != "" && d.Kind == "NIC" {
+				uvmPath = d.NetworkNamespace
+			}
+			if uvmPath == "" {
+				uvmPath = "-"
+			}
+			owners := strings.Join(d.Owners, ",")
+			if owners == "" {
+				owners = "-"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\n", d.Kind, d.Location, hostPath, uvmPath, d.RefCount, owners)
+		}
+		return w.Flush()
+	},
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/Microsoft/hcsshim/internal/appargs"
+	"github.com/Microsoft/hcsshim/internal/shimdiag"
+	"github.com/urfave/cli"
+)
+
+var (
+	logsConsole bool
+	logsTail    uint64
+)
+
+var logsCommand = cli.Command{
+	Name:      "logs",
+	Usage:     "Prints the GCS log or serial console captured from the shim's utility VM",
+	ArgsUsage: "<shim name>",
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:        "console",
+			Usage:       "print the serial console instead of the GCS log",
+			Destination: &logsConsole},
+		cli.Uint64Flag{
+			Name:        "tail",
+			Usage:       "print only the last `bytes` bytes",
+			Destination: &logsTail},
+	},
+	Before: appargs.Validate(appargs.String),
+	Action: func(c *cli.Context) error {
+		shim, err := getShim(c.Args()[0])
+		if err != nil {
+			return err
+		}
+		source := "gcs"
+		if logsConsole {
+			source = "console"
+		}
+		svc := shimdiag.NewShimDiagClient(shim)
+		resp, err := svc.DiagGuestLog(context.Background(), &shimdiag.GuestLogRequest{
+			Source:    source,
+			TailBytes: logsTail,
+		})
+		if err != nil {
+			return err
+		}
+
+		if resp.Truncated {
+			fmt.Fprintln(os.Stderr, "Earlier output omitted, see:")
+			for _, f := range resp.Files {
+				fmt.Fprintln(os.Stderr, " ", f)
+			}
+		}
+		_, err = os.Stdout.Write(resp.Data)
+		return err
+	},
+}
@@ -26,6 +26,9 @@ func main() {
 		listCommand,
 		execCommand,
 		stacksCommand,
+		inventoryCommand,
+		logsCommand,
+		healthCommand,
 	}
 	if err := app.Run(os.Args); err != nil {
 		fmt.Fprintln(os.Stderr, err)
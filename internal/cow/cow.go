@@ -65,6 +65,8 @@ type Container interface {
 	ID() string
 	// Properties returns the requested container properties.
 	Properties(ctx context.Context, types ...schema1.PropertyType) (*schema1.ContainerProperties, error)
+	// Modify sends a request to modify the settings of the container.
+	Modify(ctx context.Context, config interface{}) error
 	// Start starts a container.
 	Start(ctx context.Context) error
 	// Shutdown sends a shutdown request to the container (but does not wait for
@@ -241,6 +241,14 @@ func (brdg *bridge) RPC(ctx context.Context, proc rpcProc, req requestMessage, r
 	}
 }
 
+// pendingRPCs returns the number of RPCs that were sent and are waiting for a
+// response.
+func (brdg *bridge) pendingRPCs() int {
+	brdg.mu.Lock()
+	defer brdg.mu.Unlock()
+	return len(brdg.rpcs)
+}
+
 func (brdg *bridge) recvLoopRoutine() {
 	brdg.kill(brdg.recvLoop())
 	// Fail any remaining RPCs.
@@ -96,6 +96,29 @@ func TestBridgeRPC(t *testing.T) {
 	}
 }
 
+func TestBridgePendingRPCs(t *testing.T) {
+	b := startReflectedBridge(t, time.Millisecond*500)
+	defer b.Close()
+	req := testReq{X: 5}
+	var resp testResp
+	call, err := b.AsyncRPC(context.Background(), rpcCreate, &req, &resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for start := time.Now(); b.pendingRPCs() != 1; time.Sleep(time.Millisecond * 10) {
+		if time.Since(start) > time.Millisecond*400 {
+			t.Fatalf("expected 1 pending rpc, got %d", b.pendingRPCs())
+		}
+	}
+	call.Wait()
+	if err := call.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if n := b.pendingRPCs(); n != 0 {
+		t.Fatalf("expected no pending rpc, got %d", n)
+	}
+}
+
 func TestBridgeRPCResponseTimeout(t *testing.T) {
 	b := startReflectedBridge(t, time.Minute)
 	defer b.Close()
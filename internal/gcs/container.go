@@ -4,12 +4,14 @@ import (
 	"context"
 	"errors"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/Microsoft/hcsshim/internal/cow"
 	"github.com/Microsoft/hcsshim/internal/log"
 	"github.com/Microsoft/hcsshim/internal/oc"
 	"github.com/Microsoft/hcsshim/internal/schema1"
+	hcsschema "github.com/Microsoft/hcsshim/internal/schema2"
 	"go.opencensus.io/trace"
 )
 
@@ -25,6 +27,11 @@ type Container struct {
 	notifyCh  chan struct{}
 	closeCh   chan struct{}
 	closeOnce sync.Once
+	// oomKilled is set to 1 once the guest reports that a process of the
+	// container was killed because the container ran out of memory.
+	//
+	// NOTE: All accesses to this MUST be done atomically.
+	oomKilled uint32
 }
 
 var _ cow.Container = &Container{}
@@ -43,7 +50,7 @@ func (gc *GuestConnection) CreateContainer(ctx context.Context, cid string, conf
 		notifyCh: make(chan struct{}),
 		closeCh:  make(chan struct{}),
 	}
-	err = gc.requestNotify(cid, c.notifyCh)
+	err = gc.requestNotify(c)
 	if err != nil {
 		return nil, err
 	}
@@ -130,6 +137,28 @@ func (c *Container) Properties(ctx context.Context, types ...schema1.PropertyTyp
 	return (*schema1.ContainerProperties)(&resp.Properties), nil
 }
 
+// PropertiesV2 requests the properties of the container in the V2 schema. The
+// `Statistics` of a Linux container are its cgroup metrics.
+func (c *Container) PropertiesV2(ctx context.Context, types ...schema1.PropertyType) (_ *hcsschema.Properties, err error) {
+	ctx, span := trace.StartSpan(ctx, "gcs::Container::PropertiesV2")
+	defer span.End()
+	defer func() { oc.SetSpanStatus(span, err) }()
+	span.AddAttributes(trace.StringAttribute("cid", c.id))
+
+	req := containerGetPropertiesV2{
+		requestBase: makeRequest(ctx, c.id),
+	}
+	for _, t := range types {
+		req.Query.PropertyTypes = append(req.Query.PropertyTypes, string(t))
+	}
+	var resp containerGetPropertiesResponseV2
+	err = c.gc.brdg.RPC(ctx, rpcGetProperties, &req, &resp, true)
+	if err != nil {
+		return nil, err
+	}
+	return (*hcsschema.Properties)(&resp.Properties), nil
+}
+
 // Start starts the container.
 func (c *Container) Start(ctx context.Context) (err error) {
 	ctx, span := trace.StartSpan(ctx, "gcs::Container::Start")
@@ -198,6 +227,12 @@ func (c *Container) Wait() error {
 	}
 }
 
+// OOMKilled returns true if the guest reported that a process of the container
+// was killed because the container ran out of memory.
+func (c *Container) OOMKilled() bool {
+	return atomic.LoadUint32(&c.oomKilled) == 1
+}
+
 func (c *Container) waitBackground() {
 	ctx, span := trace.StartSpan(context.Background(), "gcs::Container::waitBackground")
 	defer span.End()
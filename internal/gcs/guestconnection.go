@@ -11,6 +11,8 @@ import (
 	"net"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/Microsoft/go-winio"
 	"github.com/Microsoft/go-winio/pkg/guid"
@@ -64,7 +66,7 @@ func (gcc *GuestConnectionConfig) Connect(ctx context.Context) (_ *GuestConnecti
 
 	gc := &GuestConnection{
 		nextPort:   firstIoChannelVsockPort,
-		notifyChs:  make(map[string]chan struct{}),
+		notifyCs:   make(map[string]*Container),
 		ioListenFn: gcc.IoListen,
 	}
 	gc.brdg = newBridge(gcc.Conn, gc.notify, gcc.Log)
@@ -87,7 +89,7 @@ type GuestConnection struct {
 	ioListenFn IoListenFunc
 	mu         sync.Mutex
 	nextPort   uint32
-	notifyChs  map[string]chan struct{}
+	notifyCs   map[string]*Container
 	caps       schema1.GuestDefinedCapabilities
 	os         string
 }
@@ -177,6 +179,31 @@ func (gc *GuestConnection) DumpStacks(ctx context.Context) (response string, err
 	return resp.GuestStacks, err
 }
 
+// Ping sends a request to the guest and returns how long the guest took to
+// respond. The guest is responsive even if it fails the request.
+func (gc *GuestConnection) Ping(ctx context.Context) (_ time.Duration, err error) {
+	ctx, span := trace.StartSpan(ctx, "gcs::GuestConnection::Ping")
+	defer span.End()
+	defer func() { oc.SetSpanStatus(span, err) }()
+
+	req := containerGetProperties{
+		requestBase: makeRequest(ctx, nullContainerID),
+	}
+	var resp containerGetPropertiesResponse
+	start := time.Now()
+	err = gc.brdg.RPC(ctx, rpcGetProperties, &req, &resp, true)
+	if _, ok := err.(*rpcError); ok {
+		err = nil
+	}
+	return time.Since(start), err
+}
+
+// PendingRPCs returns the number of requests sent to the guest that it has not
+// responded to yet.
+func (gc *GuestConnection) PendingRPCs() int {
+	return gc.brdg.pendingRPCs()
+}
+
 // Close terminates the guest connection. It is undefined to call any other
 // methods on the connection after this is called.
 func (gc *GuestConnection) Close() error {
@@ -218,40 +245,48 @@ func (gc *GuestConnection) newIoChannel() (*ioChannel, uint32, error) {
 	return newIoChannel(l), port, nil
 }
 
-func (gc *GuestConnection) requestNotify(cid string, ch chan struct{}) error {
+func (gc *GuestConnection) requestNotify(c *Container) error {
 	gc.mu.Lock()
 	defer gc.mu.Unlock()
-	if gc.notifyChs == nil {
+	if gc.notifyCs == nil {
 		return errors.New("guest connection closed")
 	}
-	if _, ok := gc.notifyChs[cid]; ok {
-		return fmt.Errorf("container %s already exists", cid)
+	if _, ok := gc.notifyCs[c.id]; ok {
+		return fmt.Errorf("container %s already exists", c.id)
 	}
-	gc.notifyChs[cid] = ch
+	gc.notifyCs[c.id] = c
 	return nil
 }
 
 func (gc *GuestConnection) notify(ntf *containerNotification) error {
 	cid := ntf.ContainerID
+	oom := ntf.Type == notificationTypeOom
 	gc.mu.Lock()
-	ch := gc.notifyChs[cid]
-	delete(gc.notifyChs, cid)
+	c := gc.notifyCs[cid]
+	if !oom {
+		delete(gc.notifyCs, cid)
+	}
 	gc.mu.Unlock()
-	if ch == nil {
+	if c == nil {
 		return fmt.Errorf("container %s not found", cid)
 	}
+	if oom {
+		logrus.WithField(logfields.ContainerID, cid).Info("container ran out of memory in guest")
+		atomic.StoreUint32(&c.oomKilled, 1)
+		return nil
+	}
 	logrus.WithField(logfields.ContainerID, cid).Info("container terminated in guest")
-	close(ch)
+	close(c.notifyCh)
 	return nil
 }
 
 func (gc *GuestConnection) clearNotifies() {
 	gc.mu.Lock()
-	chs := gc.notifyChs
-	gc.notifyChs = nil
+	cs := gc.notifyCs
+	gc.notifyCs = nil
 	gc.mu.Unlock()
-	for _, ch := range chs {
-		close(ch)
+	for _, c := range cs {
+		close(c.notifyCh)
 	}
 }
 
@@ -15,6 +15,8 @@ import (
 
 	"github.com/Microsoft/go-winio"
 	"github.com/Microsoft/go-winio/pkg/guid"
+	"github.com/Microsoft/hcsshim/internal/schema1"
+	hcsschema "github.com/Microsoft/hcsshim/internal/schema2"
 	"github.com/sirupsen/logrus"
 	"go.opencensus.io/trace"
 	"go.opencensus.io/trace/tracestate"
@@ -113,6 +115,24 @@ func simpleGcsLoop(t *testing.T, rw io.ReadWriter) error {
 			}
 		case rpcWaitForProcess:
 			// nothing
+		case rpcGetProperties:
+			var req containerGetPropertiesV2
+			err := json.Unmarshal(b, &req)
+			if err != nil {
+				return err
+			}
+			resp := &containerGetPropertiesResponseV2{}
+			for _, typ := range req.Query.PropertyTypes {
+				if typ == string(schema1.PropertyTypeStatistics) {
+					resp.Properties.Metrics = &hcsschema.LinuxMetrics{
+						Memory: &hcsschema.LinuxMemoryStats{Usage: &hcsschema.LinuxMemoryEntry{Usage: 4096}},
+					}
+				}
+			}
+			err = sendJSON(t, rw, msgType(msgTypeResponse|proc), id, resp)
+			if err != nil {
+				return err
+			}
 		case rpcShutdownForced:
 			var req requestBase
 			err = json.Unmarshal(b, &req)
@@ -124,6 +144,17 @@ func simpleGcsLoop(t *testing.T, rw io.ReadWriter) error {
 				return err
 			}
 			time.Sleep(50 * time.Millisecond)
+			if req.ContainerID == "oom" {
+				err = sendJSON(t, rw, msgType(msgTypeNotify|notifyContainer), 0, &containerNotification{
+					requestBase: requestBase{
+						ContainerID: req.ContainerID,
+					},
+					Type: notificationTypeOom,
+				})
+				if err != nil {
+					return err
+				}
+			}
 			err = sendJSON(t, rw, msgType(msgTypeNotify|notifyContainer), 0, &containerNotification{
 				requestBase: requestBase{
 					ContainerID: req.ContainerID,
@@ -175,6 +206,23 @@ func TestGcsCreateContainer(t *testing.T) {
 	c.Close()
 }
 
+func TestGcsContainerPropertiesV2(t *testing.T) {
+	gc := connectGcs(context.Background(), t)
+	defer gc.Close()
+	c, err := gc.CreateContainer(context.Background(), "foo", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	props, err := c.PropertiesV2(context.Background(), schema1.PropertyTypeStatistics)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if props.Metrics == nil || props.Metrics.Memory.Usage.Usage != 4096 {
+		t.Fatalf("expected the cgroup metrics of the container, got %+v", props.Metrics)
+	}
+}
+
 func TestGcsWaitContainer(t *testing.T) {
 	gc := connectGcs(context.Background(), t)
 	defer gc.Close()
@@ -193,6 +241,30 @@ func TestGcsWaitContainer(t *testing.T) {
 	}
 }
 
+func TestGcsWaitContainerOOMKilled(t *testing.T) {
+	gc := connectGcs(context.Background(), t)
+	defer gc.Close()
+	c, err := gc.CreateContainer(context.Background(), "oom", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	if c.OOMKilled() {
+		t.Fatal("expected the container to not be OOM killed before the notification")
+	}
+	err = c.Terminate(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = c.Wait()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !c.OOMKilled() {
+		t.Fatal("expected the container to be OOM killed")
+	}
+}
+
 func TestGcsWaitContainerBridgeTerminated(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -158,6 +158,25 @@ func (p *Process) CloseStdin(ctx context.Context) (err error) {
 	return p.stdinCloseWriteErr
 }
 
+// ProcessUsage is the resource usage of an exited process in the guest, from
+// the rusage of the process.
+type ProcessUsage struct {
+	UserTimeInUs   uint64
+	SystemTimeInUs uint64
+	MaxRssInKb     uint64
+	InBlocks       uint64
+	OutBlocks      uint64
+}
+
+// Usage returns the resource usage of the exited process as reported by the
+// guest, or nil if the process has not exited or the guest does not report it.
+func (p *Process) Usage() *ProcessUsage {
+	if !p.waitCall.Done() || p.waitCall.Err() != nil {
+		return nil
+	}
+	return p.waitResp.Usage
+}
+
 // ExitCode returns the process's exit code, or an error if the process is still
 // running or the exit code is otherwise unknown.
 func (p *Process) ExitCode() (_ int, err error) {
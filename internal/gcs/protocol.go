@@ -205,6 +205,11 @@ type uvmConfig struct {
 	SystemType string // must be "Container"
 }
 
+// notificationTypeOom is the type of the notification the guest sends when a
+// process of a container is killed because the container ran out of memory.
+// Unlike the other notifications it does not mean that the container exited.
+const notificationTypeOom = "Oom"
+
 type containerNotification struct {
 	requestBase
 	Type       string      // Compute.System.NotificationType
@@ -270,6 +275,21 @@ type containerGetProperties struct {
 	Query containerPropertiesQuery
 }
 
+type containerPropertiesQueryV2 hcsschema.PropertyQuery
+
+func (q *containerPropertiesQueryV2) MarshalText() ([]byte, error) {
+	return json.Marshal((*hcsschema.PropertyQuery)(q))
+}
+
+func (q *containerPropertiesQueryV2) UnmarshalText(b []byte) error {
+	return json.Unmarshal(b, (*hcsschema.PropertyQuery)(q))
+}
+
+type containerGetPropertiesV2 struct {
+	requestBase
+	Query containerPropertiesQueryV2
+}
+
 type containerModifySettings struct {
 	requestBase
 	Request interface{}
@@ -297,6 +317,9 @@ type containerExecuteProcessResponse struct {
 type containerWaitForProcessResponse struct {
 	responseBase
 	ExitCode uint32
+	// Usage is the resource usage of the exited process, if the guest reports
+	// it.
+	Usage *ProcessUsage `json:",omitempty"`
 }
 
 type containerProperties schema1.ContainerProperties
@@ -313,3 +336,18 @@ type containerGetPropertiesResponse struct {
 	responseBase
 	Properties containerProperties
 }
+
+type containerPropertiesV2 hcsschema.Properties
+
+func (p *containerPropertiesV2) MarshalText() ([]byte, error) {
+	return json.Marshal((*hcsschema.Properties)(p))
+}
+
+func (p *containerPropertiesV2) UnmarshalText(b []byte) error {
+	return json.Unmarshal(b, (*hcsschema.Properties)(p))
+}
+
+type containerGetPropertiesResponseV2 struct {
+	responseBase
+	Properties containerPropertiesV2
+}
@@ -2,6 +2,7 @@ package guestrequest
 
 import (
 	hcsschema "github.com/Microsoft/hcsshim/internal/schema2"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
 )
 
 // Arguably, many of these (at least CombinedLayers) should have been generated
@@ -49,6 +50,11 @@ type LCOWMappedVPMemDevice struct {
 	MountPath    string `json:"MountPath,omitempty"` // /tmp/pN
 }
 
+// LCOWContainerConstraints is the resource limits of a running Linux container.
+type LCOWContainerConstraints struct {
+	Linux *specs.LinuxResources `json:",omitempty"`
+}
+
 type LCOWNetworkAdapter struct {
 	NamespaceID     string `json:",omitempty"`
 	ID              string `json:",omitempty"`
@@ -72,6 +78,9 @@ const (
 	ResourceTypeNetworkNamespace  ResourceType = "NetworkNamespace"
 	ResourceTypeCombinedLayers    ResourceType = "CombinedLayers"
 	ResourceTypeVPMemDevice       ResourceType = "VPMemDevice"
+	// ResourceTypeContainerConstraints updates the resource limits of a
+	// container rather than adding a resource to the utility VM.
+	ResourceTypeContainerConstraints ResourceType = "ContainerConstraints"
 )
 
 // GuestRequest is for modify commands passed to the guest.
@@ -1,12 +1,15 @@
 package hcs
 
 import (
+	"encoding/json"
 	"fmt"
 	"sync"
 	"syscall"
+	"unsafe"
 
 	"github.com/Microsoft/hcsshim/internal/interop"
 	"github.com/Microsoft/hcsshim/internal/logfields"
+	hcsschema "github.com/Microsoft/hcsshim/internal/schema2"
 	"github.com/Microsoft/hcsshim/internal/vmcompute"
 	"github.com/sirupsen/logrus"
 )
@@ -93,6 +96,10 @@ type notifcationWatcherContext struct {
 
 	systemID  string
 	processID int
+
+	// exitType is the exit type of the system reported with its exit
+	// notification, if any. It is set before the notification is sent.
+	exitType string
 }
 
 type notificationChannels map[hcsNotification]notificationChannel
@@ -106,6 +113,7 @@ func newSystemChannels() notificationChannels {
 		hcsNotificationSystemStartCompleted,
 		hcsNotificationSystemPauseCompleted,
 		hcsNotificationSystemResumeCompleted,
+		hcsNotificationSystemSaveCompleted,
 	} {
 		channels[notif] = make(notificationChannel, 1)
 	}
@@ -152,6 +160,17 @@ func notificationWatcher(notificationType hcsNotification, callbackNumber uintpt
 	}
 	log.Debug("HCS notification")
 
+	if notificationType == hcsNotificationSystemExited && notificationData != nil {
+		// The data is owned by the HCS and only valid during the callback.
+		data := syscall.UTF16ToString((*[1 << 29]uint16)(unsafe.Pointer(notificationData))[:])
+		var status hcsschema.SystemExitStatus
+		if err := json.Unmarshal([]byte(data), &status); err != nil {
+			log.WithError(err).Debug("failed to read system exit status")
+		} else {
+			context.exitType = status.ExitType
+		}
+	}
+
 	if channel, ok := context.channels[notificationType]; ok {
 		channel <- result
 	}
@@ -15,6 +15,7 @@ import (
 	"github.com/Microsoft/hcsshim/internal/log"
 	"github.com/Microsoft/hcsshim/internal/oc"
 	"github.com/Microsoft/hcsshim/internal/schema1"
+	hcsschema "github.com/Microsoft/hcsshim/internal/schema2"
 	"github.com/Microsoft/hcsshim/internal/timeout"
 	"github.com/Microsoft/hcsshim/internal/vmcompute"
 	"go.opencensus.io/trace"
@@ -51,10 +52,19 @@ type System struct {
 	waitBlock      chan struct{}
 	waitError      error
 	exitError      error
+	exitType       string
 
 	os, typ string
 }
 
+// Exit types reported by the HCS with the exit notification of a compute
+// system.
+const (
+	GracefulExit   = "GracefulExit"
+	ForcedExit     = "ForcedExit"
+	UnexpectedExit = "UnexpectedExit"
+)
+
 func newSystem(id string) *System {
 	return &System{
 		id:        id,
@@ -308,6 +318,15 @@ func (computeSystem *System) waitBackground() {
 	span.AddAttributes(trace.StringAttribute("cid", computeSystem.id))
 
 	err := waitForNotification(ctx, computeSystem.callbackNumber, hcsNotificationSystemExited, nil)
+	callbackMapLock.RLock()
+	if context, ok := callbackMap[computeSystem.callbackNumber]; ok {
+		computeSystem.exitType = context.exitType
+	}
+	callbackMapLock.RUnlock()
+	span.AddAttributes(trace.StringAttribute("exitType", computeSystem.exitType))
+	if err == nil && computeSystem.exitType == UnexpectedExit {
+		err = ErrVmcomputeUnexpectedExit
+	}
 	switch err {
 	case nil:
 		log.G(ctx).Debug("system exited")
@@ -332,6 +351,18 @@ func (computeSystem *System) Wait() error {
 	return computeSystem.waitError
 }
 
+// ExitType returns the exit type the HCS reported for the compute system,
+// one of `GracefulExit`, `ForcedExit` or `UnexpectedExit`, or "" if the
+// compute system has not exited or the HCS did not report one.
+func (computeSystem *System) ExitType() string {
+	select {
+	case <-computeSystem.waitBlock:
+		return computeSystem.exitType
+	default:
+		return ""
+	}
+}
+
 // ExitError returns an error describing the reason the compute system terminated.
 func (computeSystem *System) ExitError() error {
 	select {
@@ -373,6 +404,40 @@ func (computeSystem *System) Properties(ctx context.Context, types ...schema1.Pr
 	return properties, nil
 }
 
+// PropertiesV2 returns the requested properties of a compute system created
+// with the V2 schema.
+func (computeSystem *System) PropertiesV2(ctx context.Context, types ...schema1.PropertyType) (*hcsschema.Properties, error) {
+	computeSystem.handleLock.RLock()
+	defer computeSystem.handleLock.RUnlock()
+
+	operation := "hcsshim::System::PropertiesV2"
+
+	query := hcsschema.PropertyQuery{}
+	for _, t := range types {
+		query.PropertyTypes = append(query.PropertyTypes, string(t))
+	}
+	queryBytes, err := json.Marshal(query)
+	if err != nil {
+		return nil, makeSystemError(computeSystem, operation, "", err, nil)
+	}
+
+	propertiesJSON, resultJSON, err := vmcompute.HcsGetComputeSystemProperties(ctx, computeSystem.handle, string(queryBytes))
+	events := processHcsResult(ctx, resultJSON)
+	if err != nil {
+		return nil, makeSystemError(computeSystem, operation, "", err, events)
+	}
+
+	if propertiesJSON == "" {
+		return nil, ErrUnexpectedValue
+	}
+	properties := &hcsschema.Properties{}
+	if err := json.Unmarshal([]byte(propertiesJSON), properties); err != nil {
+		return nil, makeSystemError(computeSystem, operation, "", err, nil)
+	}
+
+	return properties, nil
+}
+
 // Pause pauses the execution of the computeSystem. This feature is not enabled in TP5.
 func (computeSystem *System) Pause(ctx context.Context) (err error) {
 	operation := "hcsshim::System::Pause"
@@ -427,6 +492,39 @@ func (computeSystem *System) Resume(ctx context.Context) (err error) {
 	return nil
 }
 
+// Save saves the state of the computeSystem as described by `options`. The
+// computeSystem must be paused first.
+func (computeSystem *System) Save(ctx context.Context, options interface{}) (err error) {
+	operation := "hcsshim::System::Save"
+
+	// hcsSaveComputeSystemContext is an async operation. Start the outer span
+	// here to measure the full save time.
+	ctx, span := trace.StartSpan(ctx, operation)
+	defer span.End()
+	defer func() { oc.SetSpanStatus(span, err) }()
+	span.AddAttributes(trace.StringAttribute("cid", computeSystem.id))
+
+	optionsb, err := json.Marshal(options)
+	if err != nil {
+		return err
+	}
+
+	computeSystem.handleLock.RLock()
+	defer computeSystem.handleLock.RUnlock()
+
+	if computeSystem.handle == 0 {
+		return makeSystemError(computeSystem, operation, "", ErrAlreadyClosed, nil)
+	}
+
+	resultJSON, err := vmcompute.HcsSaveComputeSystem(ctx, computeSystem.handle, string(optionsb))
+	events, err := processAsyncHcsResult(ctx, err, resultJSON, computeSystem.callbackNumber, hcsNotificationSystemSaveCompleted, &timeout.SystemSave)
+	if err != nil {
+		return makeSystemError(computeSystem, operation, string(optionsb), err, events)
+	}
+
+	return nil
+}
+
 func (computeSystem *System) createProcess(ctx context.Context, operation string, c interface{}) (*Process, *vmcompute.HcsProcessInformation, error) {
 	computeSystem.handleLock.RLock()
 	defer computeSystem.handleLock.RUnlock()
@@ -1,16 +1,23 @@
+// +build windows
+
 package hcsoci
 
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"os"
+	"strconv"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/Microsoft/hcsshim/internal/cow"
 	hcsschema "github.com/Microsoft/hcsshim/internal/schema2"
+	"github.com/Microsoft/hcsshim/internal/signals"
 	specs "github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/sync/errgroup"
@@ -25,11 +32,33 @@ type Cmd struct {
 	// The OCI spec for the process.
 	Spec *specs.Process
 
+	// Env, if non-nil, replaces Spec.Env for the launched process. Each entry
+	// is of the form "key=value".
+	Env []string
+
+	// Dir, if non-empty, replaces Spec.Cwd as the working directory of the
+	// launched process.
+	Dir string
+
+	// User, if non-empty, replaces Spec.User for the launched process. For
+	// Windows process hosts this is the user name. For Linux process hosts
+	// this is a numeric "uid" or "uid:gid".
+	User string
+
+	// Group, if non-nil, is the process group the command joins once it is
+	// started. See ProcessGroup.
+	Group *ProcessGroup
+
 	// Standard IO streams to relay to/from the process.
 	Stdin  io.Reader
 	Stdout io.Writer
 	Stderr io.Writer
 
+	// ExtraFiles are additional open files for the process, as with os/exec.
+	// Process hosts only connect the standard IO of a process, so Start fails
+	// with ErrExtraFilesNotSupported if any are set.
+	ExtraFiles []*os.File
+
 	// Log provides a logrus entry to use in logging IO copying status.
 	Log *logrus.Entry
 
@@ -52,6 +81,22 @@ type Cmd struct {
 	iogrp     errgroup.Group
 	stdinErr  atomic.Value
 	allDoneCh chan struct{}
+
+	// exitedCh is closed once the process exits. It is made by the first Stop
+	// so that a single goroutine waits for the process however many times Stop
+	// is called.
+	exitedCh   chan struct{}
+	exitedOnce sync.Once
+
+	// stdoutPipe and stderrPipe are the pipe writers backing StdoutPipe and
+	// StderrPipe. They are closed once their relay completes.
+	stdoutPipe, stderrPipe *io.PipeWriter
+	// closeAfterWait are the pipe ends closed once Wait completes.
+	closeAfterWait []io.Closer
+	// attachedIO are the pipes of an attached process. They are closed once
+	// Wait completes, or when the relay times out as closing the process does
+	// not cancel their reads.
+	attachedIO []io.Closer
 }
 
 // ExitState contains whether a process has exited and with which exit code.
@@ -77,6 +122,16 @@ func (err *ExitError) Error() string {
 	return fmt.Sprintf("process exited with exit code %d", err.ExitCode())
 }
 
+var (
+	// ErrAlreadyStarted is returned when a Cmd is configured after Start.
+	ErrAlreadyStarted = errors.New("hcsoci: command already started")
+	// ErrNotStarted is returned when a Cmd operation requires a started
+	// process.
+	ErrNotStarted = errors.New("hcsoci: command not started")
+	// ErrExtraFilesNotSupported is returned by Start when ExtraFiles is set.
+	ErrExtraFilesNotSupported = errors.New("hcsoci: extra files are not supported by process hosts")
+)
+
 // Additional fields to hcsschema.ProcessParameters used by LCOW
 type lcowProcessParameters struct {
 	hcsschema.ProcessParameters
@@ -117,6 +172,29 @@ func CommandContext(ctx context.Context, host cow.ProcessHost, name string, arg
 	return cmd
 }
 
+// Attach makes `c` a started Cmd for the running process `p` in `c.Host`,
+// such as a process reopened after the process that started it exited. The
+// standard IO of `p`, `stdin`, `stdout` and `stderr`, reopened by the caller,
+// is relayed to and from c.Stdin, c.Stdout and c.Stderr as Start does, and is
+// closed once Wait completes. Any of them may be nil if the process has no such
+// pipe. As with Start, Wait must eventually be called.
+func (c *Cmd) Attach(p cow.Process, stdin io.WriteCloser, stdout, stderr io.ReadCloser) error {
+	if c.Process != nil {
+		return ErrAlreadyStarted
+	}
+	if c.Spec == nil {
+		c.Spec = &specs.Process{}
+	}
+	c.allDoneCh = make(chan struct{})
+	for _, f := range []io.Closer{stdin, stdout, stderr} {
+		if f != nil {
+			c.attachedIO = append(c.attachedIO, f)
+		}
+	}
+	c.start(p, stdin, stdout, stderr)
+	return nil
+}
+
 func copyAndLog(w io.Writer, r io.Reader, log *logrus.Entry, name string) (int64, error) {
 	n, err := io.Copy(w, r)
 	if log != nil {
@@ -137,29 +215,39 @@ func copyAndLog(w io.Writer, r io.Reader, log *logrus.Entry, name string) (int64
 // Start starts a command. The caller must ensure that if Start succeeds,
 // Wait is eventually called to clean up resources.
 func (c *Cmd) Start() error {
+	if c.Process != nil {
+		return ErrAlreadyStarted
+	}
+	if len(c.ExtraFiles) != 0 {
+		return ErrExtraFilesNotSupported
+	}
+	spec, err := c.processSpec()
+	if err != nil {
+		return err
+	}
 	c.allDoneCh = make(chan struct{})
 	var x interface{}
 	if !c.Host.IsOCI() {
 		wpp := &hcsschema.ProcessParameters{
-			CommandLine:      c.Spec.CommandLine,
-			User:             c.Spec.User.Username,
-			WorkingDirectory: c.Spec.Cwd,
-			EmulateConsole:   c.Spec.Terminal,
+			CommandLine:      spec.CommandLine,
+			User:             spec.User.Username,
+			WorkingDirectory: spec.Cwd,
+			EmulateConsole:   spec.Terminal,
 			CreateStdInPipe:  c.Stdin != nil,
 			CreateStdOutPipe: c.Stdout != nil,
 			CreateStdErrPipe: c.Stderr != nil,
 		}
 
-		if c.Spec.CommandLine == "" {
+		if spec.CommandLine == "" {
 			if c.Host.OS() == "windows" {
-				wpp.CommandLine = escapeArgs(c.Spec.Args)
+				wpp.CommandLine = escapeArgs(spec.Args)
 			} else {
-				wpp.CommandArgs = c.Spec.Args
+				wpp.CommandArgs = spec.Args
 			}
 		}
 
 		environment := make(map[string]string)
-		for _, v := range c.Spec.Env {
+		for _, v := range spec.Env {
 			s := strings.SplitN(v, "=", 2)
 			if len(s) == 2 && len(s[1]) > 0 {
 				environment[s[0]] = s[1]
@@ -167,10 +255,10 @@ func (c *Cmd) Start() error {
 		}
 		wpp.Environment = environment
 
-		if c.Spec.ConsoleSize != nil {
+		if spec.ConsoleSize != nil {
 			wpp.ConsoleSize = []int32{
-				int32(c.Spec.ConsoleSize.Height),
-				int32(c.Spec.ConsoleSize.Width),
+				int32(spec.ConsoleSize.Height),
+				int32(spec.ConsoleSize.Width),
 			}
 		}
 		x = wpp
@@ -181,7 +269,7 @@ func (c *Cmd) Start() error {
 				CreateStdOutPipe: c.Stdout != nil,
 				CreateStdErrPipe: c.Stderr != nil,
 			},
-			OCIProcess: c.Spec,
+			OCIProcess: spec,
 		}
 		x = lpp
 	}
@@ -190,16 +278,27 @@ func (c *Cmd) Start() error {
 	}
 	p, err := c.Host.CreateProcess(context.TODO(), x)
 	if err != nil {
+		c.closeDescriptors(c.closeAfterWait)
 		return err
 	}
+	stdin, stdout, stderr := p.Stdio()
+	c.start(p, stdin, stdout, stderr)
+	return nil
+}
+
+// start tracks the started process `p` and relays its IO `stdin`, `stdout` and
+// `stderr`.
+func (c *Cmd) start(p cow.Process, stdin io.Writer, stdout, stderr io.Reader) {
 	c.Process = p
 	if c.Log != nil {
 		c.Log = c.Log.WithField("pid", p.Pid())
 	}
+	if c.Group != nil {
+		c.Group.add(c)
+	}
 
 	// Start relaying process IO.
-	stdin, stdout, stderr := p.Stdio()
-	if c.Stdin != nil {
+	if c.Stdin != nil && stdin != nil {
 		// Do not make stdin part of the error group because there is no way for
 		// us or the caller to reliably unblock the c.Stdin read when the
 		// process exits.
@@ -216,16 +315,22 @@ func (c *Cmd) Start() error {
 		}()
 	}
 
-	if c.Stdout != nil {
+	if c.Stdout != nil && stdout != nil {
 		c.iogrp.Go(func() error {
 			_, err := copyAndLog(c.Stdout, stdout, c.Log, "stdout")
+			if c.stdoutPipe != nil {
+				c.stdoutPipe.CloseWithError(err)
+			}
 			return err
 		})
 	}
 
-	if c.Stderr != nil {
+	if c.Stderr != nil && stderr != nil {
 		c.iogrp.Go(func() error {
 			_, err := copyAndLog(c.Stderr, stderr, c.Log, "stderr")
+			if c.stderrPipe != nil {
+				c.stderrPipe.CloseWithError(err)
+			}
 			return err
 		})
 	}
@@ -239,7 +344,6 @@ func (c *Cmd) Start() error {
 			}
 		}()
 	}
-	return nil
 }
 
 // Wait waits for a command and its IO to complete and closes the underlying
@@ -266,6 +370,7 @@ func (c *Cmd) Wait() error {
 			case <-t.C:
 				// Close the process to cancel any reads to stdout or stderr.
 				c.Process.Close()
+				c.closeDescriptors(c.attachedIO)
 				if c.Log != nil {
 					c.Log.Warn("timed out waiting for stdio relay")
 				}
@@ -278,6 +383,11 @@ func (c *Cmd) Wait() error {
 	}
 	close(c.allDoneCh)
 	c.Process.Close()
+	c.closeDescriptors(c.closeAfterWait)
+	c.closeDescriptors(c.attachedIO)
+	if c.Group != nil {
+		c.Group.remove(c)
+	}
 	c.ExitState = state
 	if exitErr != nil {
 		return exitErr
@@ -305,3 +415,257 @@ func (c *Cmd) Output() ([]byte, error) {
 	err := c.Run()
 	return b.Bytes(), err
 }
+
+// CombinedOutput runs a command via Run and collects its stdout and stderr
+// into a single buffer, which it returns.
+func (c *Cmd) CombinedOutput() ([]byte, error) {
+	if c.Stdout != nil {
+		return nil, errors.New("hcsoci: Stdout already set")
+	}
+	if c.Stderr != nil {
+		return nil, errors.New("hcsoci: Stderr already set")
+	}
+	var b bytes.Buffer
+	w := &syncWriter{w: &b}
+	c.Stdout = w
+	c.Stderr = w
+	err := c.Run()
+	return b.Bytes(), err
+}
+
+// StdinPipe returns a pipe that will be connected to the command's stdin
+// when the command starts. The pipe is closed automatically after Wait
+// returns; the caller only needs to close it to signal EOF earlier.
+func (c *Cmd) StdinPipe() (io.WriteCloser, error) {
+	if c.Stdin != nil {
+		return nil, errors.New("hcsoci: Stdin already set")
+	}
+	if c.Process != nil {
+		return nil, ErrAlreadyStarted
+	}
+	pr, pw := io.Pipe()
+	c.Stdin = pr
+	c.closeAfterWait = append(c.closeAfterWait, pr)
+	return pw, nil
+}
+
+// StdoutPipe returns a pipe that will be connected to the command's stdout
+// when the command starts. The pipe reaches EOF once the process's stdout is
+// closed. As with os/exec, all reads from the pipe must complete before Wait
+// is called.
+func (c *Cmd) StdoutPipe() (io.ReadCloser, error) {
+	if c.Stdout != nil {
+		return nil, errors.New("hcsoci: Stdout already set")
+	}
+	if c.Process != nil {
+		return nil, ErrAlreadyStarted
+	}
+	pr, pw := io.Pipe()
+	c.Stdout = pw
+	c.stdoutPipe = pw
+	c.closeAfterWait = append(c.closeAfterWait, pr)
+	return pr, nil
+}
+
+// StderrPipe returns a pipe that will be connected to the command's stderr
+// when the command starts. It has the same semantics as StdoutPipe.
+func (c *Cmd) StderrPipe() (io.ReadCloser, error) {
+	if c.Stderr != nil {
+		return nil, errors.New("hcsoci: Stderr already set")
+	}
+	if c.Process != nil {
+		return nil, ErrAlreadyStarted
+	}
+	pr, pw := io.Pipe()
+	c.Stderr = pw
+	c.stderrPipe = pw
+	c.closeAfterWait = append(c.closeAfterWait, pr)
+	return pr, nil
+}
+
+// Stop gracefully stops a started command. It sends the host's default
+// termination signal (SIGTERM for Linux, CtrlShutdown for Windows) and waits
+// up to `timeout`, or until `ctx` is done, for the process to exit. If the
+// signal cannot be delivered or the process is still running afterwards, the
+// process is killed.
+//
+// Stop does not reap the process; the caller must still call Wait.
+func (c *Cmd) Stop(ctx context.Context, timeout time.Duration) error {
+	if c.Process == nil {
+		return ErrNotStarted
+	}
+	exited := c.exited()
+
+	delivered, err := c.Process.Signal(ctx, c.stopSignal())
+	if err == nil && delivered {
+		t := time.NewTimer(timeout)
+		defer t.Stop()
+		select {
+		case <-exited:
+			return nil
+		case <-t.C:
+		case <-ctx.Done():
+		}
+	} else if c.Log != nil {
+		c.Log.WithError(err).Debug("stop signal not delivered, killing process")
+	}
+
+	select {
+	case <-exited:
+		return nil
+	default:
+	}
+	// `ctx` may be done by now, which must not prevent the kill.
+	_, err = c.Process.Kill(context.Background())
+	return err
+}
+
+// exited returns a channel that is closed once the process exits. The
+// goroutine waiting for the process ends when the process exits.
+func (c *Cmd) exited() <-chan struct{} {
+	c.exitedOnce.Do(func() {
+		c.exitedCh = make(chan struct{})
+		go func() {
+			c.Process.Wait()
+			close(c.exitedCh)
+		}()
+	})
+	return c.exitedCh
+}
+
+// stopSignal returns the signal options used by Stop for the command's
+// host.
+func (c *Cmd) stopSignal() interface{} {
+	if c.Host.OS() == "windows" {
+		opts, _ := signals.ValidateSigstrWCOW("", true)
+		return opts
+	}
+	opts, _ := signals.ValidateSigstrLCOW("", true)
+	return opts
+}
+
+// processSpec returns a copy of Spec with the Env, Dir and User overrides
+// applied.
+func (c *Cmd) processSpec() (*specs.Process, error) {
+	spec := *c.Spec
+	if c.Env != nil {
+		spec.Env = c.Env
+	}
+	if c.Dir != "" {
+		spec.Cwd = c.Dir
+	}
+	if c.User != "" {
+		if c.Host.OS() == "windows" {
+			spec.User = specs.User{Username: c.User}
+		} else {
+			u, err := parseLinuxUser(c.User)
+			if err != nil {
+				return nil, err
+			}
+			spec.User = u
+		}
+	}
+	return &spec, nil
+}
+
+// parseLinuxUser parses a "uid" or "uid:gid" string into an OCI user. If the
+// gid is omitted it defaults to the uid.
+func parseLinuxUser(user string) (specs.User, error) {
+	parts := strings.SplitN(user, ":", 2)
+	uid, err := strconv.ParseUint(parts[0], 10, 32)
+	if err != nil {
+		return specs.User{}, fmt.Errorf("invalid uid in user %q: %s", user, err)
+	}
+	gid := uid
+	if len(parts) == 2 {
+		gid, err = strconv.ParseUint(parts[1], 10, 32)
+		if err != nil {
+			return specs.User{}, fmt.Errorf("invalid gid in user %q: %s", user, err)
+		}
+	}
+	return specs.User{UID: uint32(uid), GID: uint32(gid)}, nil
+}
+
+func (c *Cmd) closeDescriptors(closers []io.Closer) {
+	for _, fd := range closers {
+		fd.Close()
+	}
+}
+
+// syncWriter serializes writes to w so that a single writer can be shared by
+// the stdout and stderr relays.
+type syncWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (w *syncWriter) Write(b []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.w.Write(b)
+}
+
+// ProcessGroup is a set of started commands that are signaled and stopped
+// together. Commands join the group by setting Cmd.Group before Start, and
+// leave it when Wait completes.
+type ProcessGroup struct {
+	mu   sync.Mutex
+	cmds map[*Cmd]struct{}
+}
+
+func (g *ProcessGroup) add(c *Cmd) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.cmds == nil {
+		g.cmds = make(map[*Cmd]struct{})
+	}
+	g.cmds[c] = struct{}{}
+}
+
+func (g *ProcessGroup) remove(c *Cmd) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.cmds, c)
+}
+
+func (g *ProcessGroup) members() []*Cmd {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	cmds := make([]*Cmd, 0, len(g.cmds))
+	for c := range g.cmds {
+		cmds = append(cmds, c)
+	}
+	return cmds
+}
+
+// Len returns the number of running commands in the group.
+func (g *ProcessGroup) Len() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return len(g.cmds)
+}
+
+// Kill kills every running command in the group. It returns the first error
+// encountered but attempts to kill every member regardless.
+func (g *ProcessGroup) Kill(ctx context.Context) error {
+	var firstErr error
+	for _, c := range g.members() {
+		if _, err := c.Process.Kill(ctx); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// Stop calls Stop on every running command in the group concurrently, with
+// the same `timeout`. It returns the first error encountered.
+func (g *ProcessGroup) Stop(ctx context.Context, timeout time.Duration) error {
+	var eg errgroup.Group
+	for _, c := range g.members() {
+		c := c
+		eg.Go(func() error {
+			return c.Stop(ctx, timeout)
+		})
+	}
+	return eg.Wait()
+}
@@ -1,4 +1,4 @@
-// build +windows
+// +build windows
 
 package hcsoci
 
@@ -7,6 +7,7 @@ import (
 	"context"
 	"errors"
 	"io"
+	"io/ioutil"
 	"os"
 	"os/exec"
 	"strings"
@@ -247,3 +248,205 @@ func TestCmdStuckIo(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestCmdCombinedOutput(t *testing.T) {
+	cmd := Command(&localProcessHost{}, "cmd", "/c", "echo", "hello", "1>&2")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(output) != "hello \r\n" {
+		t.Fatalf("got %q", string(output))
+	}
+}
+
+func TestCmdStdoutPipe(t *testing.T) {
+	cmd := Command(&localProcessHost{}, "cmd", "/c", "echo", "hello")
+	r, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Wait(); err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "hello\r\n" {
+		t.Fatalf("got %q", string(b))
+	}
+}
+
+func TestCmdStdinPipe(t *testing.T) {
+	cmd := Command(&localProcessHost{}, "findstr", "x*")
+	w, err := cmd.StdinPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b bytes.Buffer
+	cmd.Stdout = &b
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	io.WriteString(w, "testing 1 2 3")
+	w.Close()
+	if err := cmd.Wait(); err != nil {
+		t.Fatal(err)
+	}
+	if b.String() != "testing 1 2 3\r\n" {
+		t.Fatalf("got %q", b.String())
+	}
+}
+
+func TestCmdStop(t *testing.T) {
+	cmd := Command(&localProcessHost{}, "cmd", "/c", "pause")
+	r, w := io.Pipe()
+	defer w.Close()
+	cmd.Stdin = r
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Stop(context.Background(), 100*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	err := cmd.Wait()
+	if e, ok := err.(*ExitError); !ok || e.ExitCode() != 1 {
+		t.Fatal(err)
+	}
+}
+
+// ctxProcess is a local process that ignores signals and cannot be killed
+// with a done context.
+type ctxProcess struct {
+	*localProcess
+}
+
+func (p *ctxProcess) Signal(ctx context.Context, _ interface{}) (bool, error) {
+	return false, nil
+}
+
+func (p *ctxProcess) Kill(ctx context.Context) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	return p.localProcess.Kill(ctx)
+}
+
+type ctxProcessHost struct {
+	localProcessHost
+}
+
+func (h *ctxProcessHost) CreateProcess(ctx context.Context, cfg interface{}) (cow.Process, error) {
+	p, err := h.localProcessHost.CreateProcess(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &ctxProcess{p.(*localProcess)}, nil
+}
+
+func TestCmdStopContextDone(t *testing.T) {
+	cmd := Command(&ctxProcessHost{}, "cmd", "/c", "pause")
+	r, w := io.Pipe()
+	defer w.Close()
+	cmd.Stdin = r
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := cmd.Stop(ctx, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	err := cmd.Wait()
+	if e, ok := err.(*ExitError); !ok || e.ExitCode() != 1 {
+		t.Fatal(err)
+	}
+}
+
+func TestCmdExtraFilesNotSupported(t *testing.T) {
+	cmd := Command(&localProcessHost{}, "cmd", "/c", "exit")
+	cmd.ExtraFiles = []*os.File{os.Stdin}
+	if err := cmd.Start(); err != ErrExtraFilesNotSupported {
+		t.Fatalf("expected %v, got: %v", ErrExtraFilesNotSupported, err)
+	}
+}
+
+func TestCmdProcessGroupKill(t *testing.T) {
+	g := &ProcessGroup{}
+	var cmds []*Cmd
+	for i := 0; i < 2; i++ {
+		cmd := Command(&localProcessHost{}, "cmd", "/c", "pause")
+		r, w := io.Pipe()
+		defer w.Close()
+		cmd.Stdin = r
+		cmd.Group = g
+		if err := cmd.Start(); err != nil {
+			t.Fatal(err)
+		}
+		cmds = append(cmds, cmd)
+	}
+	if g.Len() != 2 {
+		t.Fatalf("expected 2 group members, got %d", g.Len())
+	}
+	if err := g.Kill(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	for _, cmd := range cmds {
+		if _, ok := cmd.Wait().(*ExitError); !ok {
+			t.Fatal("expected killed process to return an exit error")
+		}
+	}
+	if g.Len() != 0 {
+		t.Fatalf("expected empty group after wait, got %d", g.Len())
+	}
+}
+
+func TestCmdOverrides(t *testing.T) {
+	cmd := Command(&localProcessHost{}, "cmd")
+	cmd.Env = []string{"A=B"}
+	cmd.Dir = `C:\Windows`
+	cmd.User = "ContainerUser"
+	spec, err := cmd.processSpec()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(spec.Env) != 1 || spec.Env[0] != "A=B" || spec.Cwd != `C:\Windows` || spec.User.Username != "ContainerUser" {
+		t.Fatalf("overrides not applied: %+v", spec)
+	}
+	if cmd.Spec.Cwd != `C:\` || cmd.Spec.User.Username != "" {
+		t.Fatal("overrides should not modify Spec")
+	}
+}
+
+func TestParseLinuxUser(t *testing.T) {
+	tests := []struct {
+		user     string
+		uid, gid uint32
+		err      bool
+	}{
+		{user: "1000", uid: 1000, gid: 1000},
+		{user: "1000:20", uid: 1000, gid: 20},
+		{user: "root", err: true},
+		{user: "0:wheel", err: true},
+	}
+	for _, test := range tests {
+		u, err := parseLinuxUser(test.user)
+		if test.err {
+			if err == nil {
+				t.Errorf("%q: expected error", test.user)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: %s", test.user, err)
+			continue
+		}
+		if u.UID != test.uid || u.GID != test.gid {
+			t.Errorf("%q: got %d:%d", test.user, u.UID, u.GID)
+		}
+	}
+}
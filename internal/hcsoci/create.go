@@ -12,7 +12,6 @@ import (
 
 	"github.com/Microsoft/go-winio/pkg/guid"
 	"github.com/Microsoft/hcsshim/internal/cow"
-	"github.com/Microsoft/hcsshim/internal/hcs"
 	"github.com/Microsoft/hcsshim/internal/log"
 	"github.com/Microsoft/hcsshim/internal/oci"
 	hcsschema "github.com/Microsoft/hcsshim/internal/schema2"
@@ -37,6 +36,22 @@ type CreateOptions struct {
 	HostingSystem    *uvm.UtilityVM     // Utility or service VM in which the container is to be created.
 	NetworkNamespace string             // Host network namespace to use (overrides anything in the spec)
 
+	// JournalPath is the path of a journal to record the container's resource
+	// allocations in. If the process dies before releasing them they can be
+	// torn down with RecoverJournal. The journal is deleted once every
+	// resource is released.
+	JournalPath string
+
+	// Recoverable keeps a v2 process-isolated container running when the last
+	// handle to it is closed so that it can be reopened after the process that
+	// created it exits. A container in a utility VM is recoverable if its
+	// utility VM is. See RecoverResources.
+	Recoverable bool
+
+	// DryRunHost is used by PlanContainer in place of HostingSystem to plan a
+	// hypervisor-isolated container before its utility VM is created.
+	DryRunHost *DryRunHost
+
 	// This is an advanced debugging parameter. It allows for diagnosibility by leaving a containers
 	// resources allocated in case of a failure. Thus you would be able to use tools such as hcsdiag
 	// to look at the state of a utility VM to see what resources were allocated. Obviously the caller
@@ -54,6 +69,10 @@ type createOptionsInternal struct {
 	actualID               string             // Identifier for the container
 	actualOwner            string             // Owner for the container
 	actualNetworkNamespace string
+
+	host     hostingSystem // HostingSystem or DryRunHost, nil for a process-isolated container
+	planning *Plan         // The plan being built, nil once the plan is executed
+	docHost  *documentHost // Machine facts used to generate the container documents
 }
 
 // CreateContainer creates a container. It can cope with a  wide variety of
@@ -62,18 +81,54 @@ type createOptionsInternal struct {
 // case of an error. This provides support for the debugging option not to
 // release the resources on failure, so that the client can make the necessary
 // call to release resources that have been allocated as part of calling this function.
-func CreateContainer(ctx context.Context, createOptions *CreateOptions) (_ cow.Container, _ *Resources, err error) {
+//
+// CreateContainer is equivalent to PlanContainer followed by Execute.
+func CreateContainer(ctx context.Context, createOptions *CreateOptions) (cow.Container, *Resources, error) {
+	plan, err := PlanContainer(ctx, createOptions)
+	if err != nil {
+		return nil, nil, err
+	}
+	return plan.Execute(ctx)
+}
+
+// PlanContainer computes the resources, utility VM paths and documents needed
+// to create a container without creating anything. The only side effect is
+// reserving the container's number in the hosting system.
+//
+// The caller's spec is not modified; the plan works on a copy.
+func PlanContainer(ctx context.Context, createOptions *CreateOptions) (*Plan, error) {
+	return planContainer(ctx, createOptions, localDocumentHost())
+}
+
+// planContainer is PlanContainer with the machine facts used to generate the
+// container documents supplied by `docHost`.
+func planContainer(ctx context.Context, createOptions *CreateOptions, docHost *documentHost) (_ *Plan, err error) {
+	if createOptions.Spec == nil {
+		return nil, fmt.Errorf("Spec must be supplied")
+	}
+	spec, err := deepCopySpec(createOptions.Spec)
+	if err != nil {
+		return nil, err
+	}
+	opts := *createOptions
+	opts.Spec = spec
 	coi := &createOptionsInternal{
-		CreateOptions: createOptions,
+		CreateOptions: &opts,
 		actualID:      createOptions.ID,
 		actualOwner:   createOptions.Owner,
+		docHost:       docHost,
+	}
+	if coi.HostingSystem != nil {
+		coi.host = coi.HostingSystem
+	} else if coi.DryRunHost != nil {
+		coi.host = coi.DryRunHost
 	}
 
 	// Defaults if omitted by caller.
 	if coi.actualID == "" {
 		g, err := guid.NewV4()
 		if err != nil {
-			return nil, nil, err
+			return nil, err
 		}
 		coi.actualID = g.String()
 	}
@@ -81,37 +136,34 @@ func CreateContainer(ctx context.Context, createOptions *CreateOptions) (_ cow.C
 		coi.actualOwner = filepath.Base(os.Args[0])
 	}
 
-	if coi.Spec == nil {
-		return nil, nil, fmt.Errorf("Spec must be supplied")
-	}
-
-	if coi.HostingSystem != nil {
+	if coi.host != nil {
 		// By definition, a hosting system can only be supplied for a v2 Xenon.
 		coi.actualSchemaVersion = schemaversion.SchemaV21()
 	} else {
-		coi.actualSchemaVersion = schemaversion.DetermineSchemaVersion(coi.SchemaVersion)
+		coi.actualSchemaVersion = schemaversion.DetermineSchemaVersionForBuild(coi.SchemaVersion, docHost.build)
 	}
 
 	log.G(ctx).WithFields(logrus.Fields{
 		"options": fmt.Sprintf("%+v", createOptions),
 		"schema":  coi.actualSchemaVersion,
-	}).Debug("hcsshim::CreateContainer")
-
-	resources := &Resources{}
-	defer func() {
-		if err != nil {
-			if !coi.DoNotReleaseResourcesOnFailure {
-				ReleaseResources(ctx, resources, coi.HostingSystem, true)
-			}
-		}
-	}()
+	}).Debug("hcsshim::PlanContainer")
+
+	plan := &Plan{
+		ID:            coi.actualID,
+		Owner:         coi.actualOwner,
+		SchemaVersion: coi.actualSchemaVersion,
+		Spec:          coi.Spec,
+		coi:           coi,
+	}
+	coi.planning = plan
 
-	if coi.HostingSystem != nil {
-		n := coi.HostingSystem.ContainerCounter()
+	if coi.host != nil {
+		plan.HostingSystemID = coi.host.ID()
+		n := coi.host.ContainerCounter()
 		if coi.Spec.Linux != nil {
-			resources.containerRootInUVM = "/run/gcs/c/" + strconv.FormatUint(n, 16)
+			plan.ContainerRootInUVM = "/run/gcs/c/" + strconv.FormatUint(n, 16)
 		} else {
-			resources.containerRootInUVM = `C:\c\` + strconv.FormatUint(n, 16)
+			plan.ContainerRootInUVM = `C:\c\` + strconv.FormatUint(n, 16)
 		}
 	}
 
@@ -121,104 +173,51 @@ func CreateContainer(ctx context.Context, createOptions *CreateOptions) (_ cow.C
 		schemaversion.IsV21(coi.actualSchemaVersion) {
 
 		if coi.NetworkNamespace != "" {
-			resources.netNS = coi.NetworkNamespace
+			plan.NetworkNamespace = coi.NetworkNamespace
+			coi.actualNetworkNamespace = coi.NetworkNamespace
 		} else {
-			err := createNetworkNamespace(ctx, coi, resources)
-			if err != nil {
-				return nil, resources, err
-			}
+			plan.addStep(PlanStep{
+				Type:      PlanStepCreateNetworkNamespace,
+				Endpoints: coi.Spec.Windows.Network.EndpointList,
+			})
+			coi.actualNetworkNamespace = plannedValue("netns", coi.actualID)
 		}
-		coi.actualNetworkNamespace = resources.netNS
-		if coi.HostingSystem != nil {
+		if coi.host != nil {
 			ct, _, err := oci.GetSandboxTypeAndID(coi.Spec.Annotations)
 			if err != nil {
-				return nil, resources, err
+				return nil, err
 			}
 			// Only add the network namespace to a standalone or sandbox
 			// container but not a workload container in a sandbox that inherits
 			// the namespace.
 			if ct == oci.KubernetesContainerTypeNone || ct == oci.KubernetesContainerTypeSandbox {
-				endpoints, err := GetNamespaceEndpoints(ctx, coi.actualNetworkNamespace)
-				if err != nil {
-					return nil, resources, err
-				}
-				err = coi.HostingSystem.AddNetNS(ctx, coi.actualNetworkNamespace)
-				if err != nil {
-					return nil, resources, err
-				}
-				err = coi.HostingSystem.AddEndpointsToNS(ctx, coi.actualNetworkNamespace, endpoints)
-				if err != nil {
-					// Best effort clean up the NS
-					coi.HostingSystem.RemoveNetNS(ctx, coi.actualNetworkNamespace)
-					return nil, resources, err
-				}
-				resources.addedNetNSToVM = true
+				plan.addStep(PlanStep{
+					Type:             PlanStepAddNetworkNamespace,
+					NetworkNamespace: plan.NetworkNamespace,
+				})
 			}
 		}
 	}
 
-	var hcsDocument, gcsDocument interface{}
-	log.G(ctx).Debug("hcsshim::CreateContainer allocating resources")
 	if coi.Spec.Linux != nil {
 		if schemaversion.IsV10(coi.actualSchemaVersion) {
-			return nil, resources, errors.New("LCOW v1 not supported")
+			return nil, errors.New("LCOW v1 not supported")
 		}
-		log.G(ctx).Debug("hcsshim::CreateContainer allocateLinuxResources")
-		err = allocateLinuxResources(ctx, coi, resources)
-		if err != nil {
-			log.G(ctx).WithError(err).Debug("failed to allocateLinuxResources")
-			return nil, resources, err
-		}
-		gcsDocument, err = createLinuxContainerDocument(ctx, coi, resources.containerRootInUVM)
-		if err != nil {
-			log.G(ctx).WithError(err).Debug("failed createHCSContainerDocument")
-			return nil, resources, err
+		log.G(ctx).Debug("hcsshim::PlanContainer planLinuxResources")
+		if err := planLinuxResources(ctx, coi, plan); err != nil {
+			log.G(ctx).WithError(err).Debug("failed to planLinuxResources")
+			return nil, err
 		}
 	} else {
-		err = allocateWindowsResources(ctx, coi, resources)
-		if err != nil {
-			log.G(ctx).WithError(err).Debug("failed to allocateWindowsResources")
-			return nil, resources, err
-		}
-		log.G(ctx).Debug("hcsshim::CreateContainer creating container document")
-		v1, v2, err := createWindowsContainerDocument(ctx, coi)
-		if err != nil {
-			log.G(ctx).WithError(err).Debug("failed createHCSContainerDocument")
-			return nil, resources, err
-		}
-
-		if schemaversion.IsV10(coi.actualSchemaVersion) {
-			// v1 Argon or Xenon. Pass the document directly to HCS.
-			hcsDocument = v1
-		} else if coi.HostingSystem != nil {
-			// v2 Xenon. Pass the container object to the UVM.
-			gcsDocument = &hcsschema.HostedSystem{
-				SchemaVersion: schemaversion.SchemaV21(),
-				Container:     v2,
-			}
-		} else {
-			// v2 Argon. Pass the container object to the HCS.
-			hcsDocument = &hcsschema.ComputeSystem{
-				Owner:                             coi.actualOwner,
-				SchemaVersion:                     schemaversion.SchemaV21(),
-				ShouldTerminateOnLastHandleClosed: true,
-				Container:                         v2,
-			}
-		}
-	}
-
-	log.G(ctx).Debug("hcsshim::CreateContainer creating compute system")
-	if gcsDocument != nil {
-		c, err := coi.HostingSystem.CreateContainer(ctx, coi.actualID, gcsDocument)
-		if err != nil {
-			return nil, resources, err
+		if err := planWindowsResources(ctx, coi, plan); err != nil {
+			log.G(ctx).WithError(err).Debug("failed to planWindowsResources")
+			return nil, err
 		}
-		return c, resources, nil
 	}
 
-	system, err := hcs.CreateComputeSystem(ctx, coi.actualID, hcsDocument)
+	plan.HCSDocument, plan.GCSDocument, err = createDocuments(ctx, coi, plan.ContainerRootInUVM)
 	if err != nil {
-		return nil, resources, err
+		return nil, err
 	}
-	return system, resources, nil
+	return plan, nil
 }
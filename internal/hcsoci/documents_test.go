@@ -0,0 +1,223 @@
+package hcsoci
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	hcsschema "github.com/Microsoft/hcsshim/internal/schema2"
+	"github.com/Microsoft/hcsshim/internal/schemaversion"
+	"github.com/Microsoft/hcsshim/osversion"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+var updateGolden = flag.Bool("update", false, "update the golden files in testdata")
+
+// goldenOptions are read from options.json in a golden test case directory.
+type goldenOptions struct {
+	SchemaVersion    *hcsschema.Version
+	NetworkNamespace string
+	// HostingSystem is "linux" or "windows" to create the container in a
+	// utility VM, or empty for a process-isolated container.
+	HostingSystem  string
+	ProcessorCount int32 // Processor count of the utility VM
+	Recoverable    bool
+}
+
+// fakeHostingSystem is a documentHostingSystem that has every share and disk
+// the container asks for, at its planned guest path.
+type fakeHostingSystem struct {
+	os             string
+	processorCount int32
+}
+
+func (h *fakeHostingSystem) ID() string            { return "test@vm" }
+func (h *fakeHostingSystem) OS() string            { return h.os }
+func (h *fakeHostingSystem) ProcessorCount() int32 { return h.processorCount }
+
+func (h *fakeHostingSystem) layerGuestPath(ctx context.Context, hostPath string) (string, error) {
+	return plannedValue("vsmb", hostPath), nil
+}
+
+func (h *fakeHostingSystem) mountGuestPath(ctx context.Context, hostPath string) (string, error) {
+	return plannedValue("vsmb", hostPath), nil
+}
+
+// fakeDocumentHost returns a documentHost for an RS5 machine. Layer IDs are
+// derived from the layer path.
+func fakeDocumentHost() *documentHost {
+	return &documentHost{
+		build:          osversion.RS5,
+		processorCount: 4,
+		layerID: func(path string) (string, error) {
+			return "id:" + path, nil
+		},
+		uvmImagePath: func(ctx context.Context, layerFolders []string) (string, error) {
+			return "", errors.New("the utility VM image must be set in the spec")
+		},
+	}
+}
+
+// TestDocuments creates the documents of each container in testdata/documents
+// and compares them against hcs.json and gcs.json. The config.json of a case is
+// the spec as it is after planning, and the documents are created without
+// planning so this runs on any OS. Run with -update to rewrite the golden files
+// after an intended change to the documents.
+func TestDocuments(t *testing.T) {
+	cases, err := filepath.Glob(filepath.Join("testdata", "documents", "*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cases) == 0 {
+		t.Fatal("no golden test cases found")
+	}
+	for _, dir := range cases {
+		dir := dir
+		t.Run(filepath.Base(dir), func(t *testing.T) {
+			testDocuments(t, dir)
+		})
+	}
+}
+
+func testDocuments(t *testing.T, dir string) {
+	spec, options, testdata := readGoldenCase(t, dir)
+
+	opts := &documentOptions{
+		spec:             spec,
+		id:               "test",
+		owner:            "test",
+		schemaVersion:    schemaversion.SchemaV21(),
+		networkNamespace: options.NetworkNamespace,
+		recoverable:      options.Recoverable,
+		docHost:          fakeDocumentHost(),
+	}
+	if options.SchemaVersion != nil {
+		if !schemaversion.IsV10(options.SchemaVersion) && !schemaversion.IsV21(options.SchemaVersion) {
+			t.Fatalf("unsupported schema version %s", schemaversion.String(options.SchemaVersion))
+		}
+		opts.schemaVersion = options.SchemaVersion
+	}
+	if options.HostingSystem != "" {
+		opts.vm = &fakeHostingSystem{os: options.HostingSystem, processorCount: options.ProcessorCount}
+	}
+
+	var hcsDocument, gcsDocument interface{}
+	if spec.Linux != nil {
+		doc, err := createLinuxContainerDocument(context.Background(), opts, "/run/gcs/c/test")
+		if err != nil {
+			t.Fatal(err)
+		}
+		gcsDocument = doc
+	} else {
+		v2, v1, err := createWindowsContainerDocument(context.Background(), opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if schemaversion.IsV10(opts.schemaVersion) {
+			hcsDocument = createV1ContainerDocument(opts, v2, v1)
+		} else {
+			hcsDocument, gcsDocument = createV2Documents(opts, v2)
+		}
+	}
+	compareGolden(t, filepath.Join(dir, "hcs.json"), hcsDocument, testdata)
+	compareGolden(t, filepath.Join(dir, "gcs.json"), gcsDocument, testdata)
+}
+
+// jsonPath returns `p` as it appears inside a JSON string.
+func jsonPath(t *testing.T, p string) string {
+	j, err := json.Marshal(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(j[1 : len(j)-1])
+}
+
+// readGoldenCase reads the config.json OCI spec of the golden test case in
+// `dir`, where {{testdata}} is replaced with the case directory, and its
+// optional options.json. It returns the case directory as it appears in JSON.
+func readGoldenCase(t *testing.T, dir string) (*specs.Spec, *goldenOptions, string) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testdata := jsonPath(t, abs)
+
+	j, err := ioutil.ReadFile(filepath.Join(dir, "config.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	spec := &specs.Spec{}
+	if err := json.Unmarshal(bytes.Replace(j, []byte("{{testdata}}"), []byte(testdata), -1), spec); err != nil {
+		t.Fatal(err)
+	}
+
+	options := &goldenOptions{}
+	if j, err := ioutil.ReadFile(filepath.Join(dir, "options.json")); err == nil {
+		if err := json.Unmarshal(j, options); err != nil {
+			t.Fatal(err)
+		}
+	} else if !os.IsNotExist(err) {
+		t.Fatal(err)
+	}
+	return spec, options, testdata
+}
+
+// compareGolden compares `doc` with the golden file `path`. A nil document
+// must not have a golden file.
+func compareGolden(t *testing.T, path string, doc interface{}, testdata string) {
+	var actual []byte
+	if !isNilDocument(doc) {
+		j, err := json.MarshalIndent(doc, "", "  ")
+		if err != nil {
+			t.Fatal(err)
+		}
+		actual = append(bytes.Replace(j, []byte(testdata), []byte("{{testdata}}"), -1), '\n')
+	}
+
+	if *updateGolden {
+		if actual == nil {
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				t.Fatal(err)
+			}
+			return
+		}
+		if err := ioutil.WriteFile(path, actual, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	expected, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		if actual != nil {
+			t.Fatalf("%s is missing, generated:\n%s", path, actual)
+		}
+		return
+	} else if err != nil {
+		t.Fatal(err)
+	}
+	if actual == nil {
+		t.Fatalf("no document was generated for %s", path)
+	}
+	// Tolerate line ending conversion on checkout.
+	expected = bytes.Replace(expected, []byte("\r\n"), []byte("\n"), -1)
+	if !bytes.Equal(expected, actual) {
+		t.Fatalf("%s does not match, generated:\n%s", path, strings.TrimSpace(string(actual)))
+	}
+}
+
+// isNilDocument returns true if `doc` is nil or a typed nil pointer.
+func isNilDocument(doc interface{}) bool {
+	if doc == nil {
+		return true
+	}
+	j, err := json.Marshal(doc)
+	return err == nil && string(j) == "null"
+}
@@ -0,0 +1,85 @@
+package hcsoci
+
+import (
+	"context"
+	"strings"
+
+	hcsschema "github.com/Microsoft/hcsshim/internal/schema2"
+	"github.com/Microsoft/hcsshim/internal/schemaversion"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+// UVMPathPrefix is the prefix of the source of a mount of a path that is
+// already in the container's utility VM, such as a share or disk added to the
+// utility VM of a pod for all of its containers. The path is mapped into the
+// container as is, without adding a share for it.
+//
+// Example: uvm://C:\pod\scratch destination:C:\data
+const UVMPathPrefix = "uvm://"
+
+// documentHost holds the facts about the machine that the container documents
+// depend on. Everything else in a document comes from the spec and the hosting
+// system, so with a fixed documentHost the documents can be generated without
+// HCS, a utility VM or real layers, and on any OS.
+type documentHost struct {
+	build          uint16                                                           // Windows build of the host
+	processorCount int32                                                            // Processor count of the host, used for process-isolated containers
+	layerID        func(path string) (string, error)                                // Returns the ID of the WCOW layer at path
+	uvmImagePath   func(ctx context.Context, layerFolders []string) (string, error) // Locates the utility VM image of a v1 Xenon
+}
+
+// documentHostingSystem is the utility VM that a hypervisor-isolated
+// container is created in, as seen by the document generation.
+type documentHostingSystem interface {
+	ID() string
+	OS() string
+	ProcessorCount() int32
+	// layerGuestPath returns the guest path of the VSMB share of a WCOW layer.
+	layerGuestPath(ctx context.Context, hostPath string) (string, error)
+	// mountGuestPath returns the guest path of the VSMB share or SCSI disk of
+	// a mount.
+	mountGuestPath(ctx context.Context, hostPath string) (string, error)
+}
+
+// documentOptions are the inputs of the container documents.
+type documentOptions struct {
+	spec             *specs.Spec
+	id               string
+	owner            string
+	schemaVersion    *hcsschema.Version
+	networkNamespace string
+	recoverable      bool
+	docHost          *documentHost
+	vm               documentHostingSystem // nil for a process-isolated container
+}
+
+// createV2Documents wraps the v2 container document `v2` in the HCS document
+// for a v2 Argon, or the GCS document for a v2 Xenon.
+func createV2Documents(opts *documentOptions, v2 *hcsschema.Container) (hcsDocument, gcsDocument interface{}) {
+	if opts.vm != nil {
+		// v2 Xenon. Pass the container object to the UVM.
+		return nil, &hcsschema.HostedSystem{
+			SchemaVersion: schemaversion.SchemaV21(),
+			Container:     v2,
+		}
+	}
+	// v2 Argon. Pass the container object to the HCS.
+	return &hcsschema.ComputeSystem{
+		Owner:                             opts.owner,
+		SchemaVersion:                     schemaversion.SchemaV21(),
+		ShouldTerminateOnLastHandleClosed: !opts.recoverable,
+		Container:                         v2,
+	}, nil
+}
+
+// plannedValue returns the placeholder used in a plan for a value of type
+// `kind` that is only known once the plan is executed.
+func plannedValue(kind, key string) string {
+	return "<" + kind + ":" + key + ">"
+}
+
+// isPlannedValue returns true if `s` is a placeholder returned by
+// plannedValue.
+func isPlannedValue(s string) bool {
+	return strings.HasPrefix(s, "<") && strings.HasSuffix(s, ">")
+}
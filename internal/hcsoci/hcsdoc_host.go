@@ -0,0 +1,99 @@
+// +build windows
+
+package hcsoci
+
+import (
+	"context"
+	"runtime"
+
+	"github.com/Microsoft/hcsshim/internal/log"
+	"github.com/Microsoft/hcsshim/internal/schemaversion"
+	"github.com/Microsoft/hcsshim/internal/uvm"
+	"github.com/Microsoft/hcsshim/internal/uvmfolder"
+	"github.com/Microsoft/hcsshim/internal/wclayer"
+	"github.com/Microsoft/hcsshim/osversion"
+)
+
+// localDocumentHost returns the documentHost of the machine we are running on.
+func localDocumentHost() *documentHost {
+	return &documentHost{
+		build:          osversion.Get().Build,
+		processorCount: int32(runtime.NumCPU()),
+		layerID:        layerID,
+		uvmImagePath:   uvmfolder.LocateUVMFolder,
+	}
+}
+
+// layerID returns the ID of the WCOW layer at `path`.
+func layerID(path string) (string, error) {
+	id, err := wclayer.LayerID(path)
+	if err != nil {
+		return "", err
+	}
+	return id.String(), nil
+}
+
+// createDocuments creates the HCS document for a container created directly on
+// the host, or the GCS document for a container created in a utility VM.
+func createDocuments(ctx context.Context, coi *createOptionsInternal, guestRoot string) (hcsDocument, gcsDocument interface{}, err error) {
+	opts := coi.documentOptions()
+	if coi.Spec.Linux != nil {
+		gcsDocument, err = createLinuxContainerDocument(ctx, opts, guestRoot)
+		if err != nil {
+			log.G(ctx).WithError(err).Debug("failed createHCSContainerDocument")
+			return nil, nil, err
+		}
+		return nil, gcsDocument, nil
+	}
+
+	v2, v1, err := createWindowsContainerDocument(ctx, opts)
+	if err != nil {
+		log.G(ctx).WithError(err).Debug("failed createHCSContainerDocument")
+		return nil, nil, err
+	}
+
+	if schemaversion.IsV10(coi.actualSchemaVersion) {
+		// v1 Argon or Xenon. Pass the document directly to HCS.
+		return createV1ContainerDocument(opts, v2, v1), nil, nil
+	}
+	hcsDocument, gcsDocument = createV2Documents(opts, v2)
+	return hcsDocument, gcsDocument, nil
+}
+
+// documentOptions returns the inputs of the container documents of `coi`.
+func (coi *createOptionsInternal) documentOptions() *documentOptions {
+	opts := &documentOptions{
+		spec:             coi.Spec,
+		id:               coi.actualID,
+		owner:            coi.actualOwner,
+		schemaVersion:    coi.actualSchemaVersion,
+		networkNamespace: coi.actualNetworkNamespace,
+		recoverable:      coi.Recoverable,
+		docHost:          coi.docHost,
+	}
+	if coi.host != nil {
+		opts.vm = &plannedHostingSystem{hostingSystem: coi.host, coi: coi}
+	}
+	return opts
+}
+
+// plannedHostingSystem is the documentHostingSystem of a container being
+// planned or created in a utility VM. Shares and disks added by the plan are
+// returned with their planned guest path.
+type plannedHostingSystem struct {
+	hostingSystem
+	coi *createOptionsInternal
+}
+
+func (h *plannedHostingSystem) layerGuestPath(ctx context.Context, hostPath string) (string, error) {
+	return h.coi.vsmbGuestPath(ctx, hostPath)
+}
+
+func (h *plannedHostingSystem) mountGuestPath(ctx context.Context, hostPath string) (string, error) {
+	uvmPath, err := h.coi.vsmbGuestPath(ctx, hostPath)
+	if err == uvm.ErrNotAttached {
+		// It could also be a scsi mount.
+		return h.coi.scsiGuestPath(ctx, hostPath)
+	}
+	return uvmPath, err
+}
@@ -1,5 +1,3 @@
-// +build windows
-
 package hcsoci
 
 import (
@@ -12,9 +10,9 @@ import (
 	specs "github.com/opencontainers/runtime-spec/specs-go"
 )
 
-func createLCOWSpec(coi *createOptionsInternal) (*specs.Spec, error) {
+func createLCOWSpec(ociSpec *specs.Spec) (*specs.Spec, error) {
 	// Remarshal the spec to perform a deep copy.
-	j, err := json.Marshal(coi.Spec)
+	j, err := json.Marshal(ociSpec)
 	if err != nil {
 		return nil, err
 	}
@@ -27,12 +25,12 @@ func createLCOWSpec(coi *createOptionsInternal) (*specs.Spec, error) {
 	// Linux containers don't care about Windows aspects of the spec except the
 	// network namespace
 	spec.Windows = nil
-	if coi.Spec.Windows != nil &&
-		coi.Spec.Windows.Network != nil &&
-		coi.Spec.Windows.Network.NetworkNamespace != "" {
+	if ociSpec.Windows != nil &&
+		ociSpec.Windows.Network != nil &&
+		ociSpec.Windows.Network.NetworkNamespace != "" {
 		spec.Windows = &specs.Windows{
 			Network: &specs.WindowsNetwork{
-				NetworkNamespace: coi.Spec.Windows.Network.NetworkNamespace,
+				NetworkNamespace: ociSpec.Windows.Network.NetworkNamespace,
 			},
 		}
 	}
@@ -60,8 +58,8 @@ type linuxHostedSystem struct {
 	OciSpecification *specs.Spec
 }
 
-func createLinuxContainerDocument(ctx context.Context, coi *createOptionsInternal, guestRoot string) (*linuxHostedSystem, error) {
-	spec, err := createLCOWSpec(coi)
+func createLinuxContainerDocument(ctx context.Context, opts *documentOptions, guestRoot string) (*linuxHostedSystem, error) {
+	spec, err := createLCOWSpec(opts.spec)
 	if err != nil {
 		return nil, err
 	}
@@ -1,5 +1,3 @@
-// +build windows
-
 package hcsoci
 
 import (
@@ -7,7 +5,6 @@ import (
 	"fmt"
 	"path/filepath"
 	"regexp"
-	"runtime"
 	"strings"
 
 	"github.com/Microsoft/hcsshim/internal/log"
@@ -16,63 +13,64 @@ import (
 	"github.com/Microsoft/hcsshim/internal/schema1"
 	hcsschema "github.com/Microsoft/hcsshim/internal/schema2"
 	"github.com/Microsoft/hcsshim/internal/schemaversion"
-	"github.com/Microsoft/hcsshim/internal/uvm"
-	"github.com/Microsoft/hcsshim/internal/uvmfolder"
-	"github.com/Microsoft/hcsshim/internal/wclayer"
 	"github.com/Microsoft/hcsshim/osversion"
 	"github.com/sirupsen/logrus"
 )
 
+// v1Settings are the settings of a v1 WCOW container document that are not
+// carried by its v2 container document.
+type v1Settings struct {
+	processorCount  int32
+	processorLimit  int32
+	processorWeight int32
+	// hvImagePath is the utility VM image of a v1 Xenon, or "" for an Argon.
+	hvImagePath string
+}
+
 // createWindowsContainerDocument creates documents for passing to HCS or GCS to create
-// a container, both hosted and process isolated. It creates both v1 and v2
-// container objects, WCOW only. The containers storage should have been mounted already.
-func createWindowsContainerDocument(ctx context.Context, coi *createOptionsInternal) (*schema1.ContainerConfig, *hcsschema.Container, error) {
+// a container, both hosted and process isolated. It creates the v2 container
+// object and the settings needed to create the v1 one, WCOW only. The
+// containers storage should have been mounted already.
+func createWindowsContainerDocument(ctx context.Context, opts *documentOptions) (*hcsschema.Container, *v1Settings, error) {
 	log.G(ctx).Debug("hcsshim: CreateHCSContainerDocument")
 	// TODO: Make this safe if exported so no null pointer dereferences.
 
-	if coi.Spec == nil {
+	if opts.spec == nil {
 		return nil, nil, fmt.Errorf("cannot create HCS container document - OCI spec is missing")
 	}
 
-	if coi.Spec.Windows == nil {
+	if opts.spec.Windows == nil {
 		return nil, nil, fmt.Errorf("cannot create HCS container document - OCI spec Windows section is missing ")
 	}
 
-	v1 := &schema1.ContainerConfig{
-		SystemType:              "Container",
-		Name:                    coi.actualID,
-		Owner:                   coi.actualOwner,
-		HvPartition:             false,
-		IgnoreFlushesDuringBoot: coi.Spec.Windows.IgnoreFlushesDuringBoot,
-	}
+	v1 := &v1Settings{}
 
 	// IgnoreFlushesDuringBoot is a property of the SCSI attachment for the scratch. Set when it's hot-added to the utility VM
 	// ID is a property on the create call in V2 rather than part of the schema.
 	v2Container := &hcsschema.Container{Storage: &hcsschema.Storage{}}
 
 	// TODO: Still want to revisit this.
-	if coi.Spec.Windows.LayerFolders == nil || len(coi.Spec.Windows.LayerFolders) < 2 {
+	if opts.spec.Windows.LayerFolders == nil || len(opts.spec.Windows.LayerFolders) < 2 {
 		return nil, nil, fmt.Errorf("invalid spec - not enough layer folders supplied")
 	}
 
-	if coi.Spec.Hostname != "" {
-		v1.HostName = coi.Spec.Hostname
-		v2Container.GuestOs = &hcsschema.GuestOs{HostName: coi.Spec.Hostname}
+	if opts.spec.Hostname != "" {
+		v2Container.GuestOs = &hcsschema.GuestOs{HostName: opts.spec.Hostname}
 	}
 
 	// CPU Resources
 	cpuNumSet := 0
-	cpuCount := oci.ParseAnnotationsCPUCount(ctx, coi.Spec, oci.AnnotationContainerProcessorCount, 0)
+	cpuCount := oci.ParseAnnotationsCPUCount(ctx, opts.spec, oci.AnnotationContainerProcessorCount, 0)
 	if cpuCount > 0 {
 		cpuNumSet++
 	}
 
-	cpuLimit := oci.ParseAnnotationsCPULimit(ctx, coi.Spec, oci.AnnotationContainerProcessorLimit, 0)
+	cpuLimit := oci.ParseAnnotationsCPULimit(ctx, opts.spec, oci.AnnotationContainerProcessorLimit, 0)
 	if cpuLimit > 0 {
 		cpuNumSet++
 	}
 
-	cpuWeight := oci.ParseAnnotationsCPUWeight(ctx, coi.Spec, oci.AnnotationContainerProcessorWeight, 0)
+	cpuWeight := oci.ParseAnnotationsCPUWeight(ctx, opts.spec, oci.AnnotationContainerProcessorWeight, 0)
 	if cpuWeight > 0 {
 		cpuNumSet++
 	}
@@ -80,17 +78,15 @@ func createWindowsContainerDocument(ctx context.Context, coi *createOptionsInter
 	if cpuNumSet > 1 {
 		return nil, nil, fmt.Errorf("invalid spec - Windows Process Container CPU Count: '%d', Limit: '%d', and Weight: '%d' are mutually exclusive", cpuCount, cpuLimit, cpuWeight)
 	} else if cpuNumSet == 1 {
-		var hostCPUCount int32
-		if coi.HostingSystem != nil {
+		hostCPUCount := opts.docHost.processorCount
+		if opts.vm != nil {
 			// Normalize to UVM size
-			hostCPUCount = coi.HostingSystem.ProcessorCount()
-		} else {
-			hostCPUCount = int32(runtime.NumCPU())
+			hostCPUCount = opts.vm.ProcessorCount()
 		}
 		if cpuCount > hostCPUCount {
-			l := log.G(ctx).WithField(logfields.ContainerID, coi.ID)
-			if coi.HostingSystem != nil {
-				l.Data[logfields.UVMID] = coi.HostingSystem.ID()
+			l := log.G(ctx).WithField(logfields.ContainerID, opts.id)
+			if opts.vm != nil {
+				l.Data[logfields.UVMID] = opts.vm.ID()
 			}
 			l.WithFields(logrus.Fields{
 				"requested": cpuCount,
@@ -99,18 +95,18 @@ func createWindowsContainerDocument(ctx context.Context, coi *createOptionsInter
 			cpuCount = hostCPUCount
 		}
 
-		v1.ProcessorCount = uint32(cpuCount)
-		v1.ProcessorMaximum = int64(cpuLimit)
-		v1.ProcessorWeight = uint64(cpuWeight)
+		v1.processorCount = cpuCount
+		v1.processorLimit = cpuLimit
+		v1.processorWeight = cpuWeight
 
 		if cpuCount == 0 {
 			// TODO: JTERRY75 - There is a Windows platform bug (VSO#20891779)
 			// for V2 that we cannot set Maximum or Weight. We have to silently
 			// ignore here until its fixed. When the bug is fixed fully remove
 			// this if/else and always assign the v2Container.Processor field.
-			l := log.G(ctx).WithField(logfields.ContainerID, coi.ID)
-			if coi.HostingSystem != nil {
-				l.Data[logfields.UVMID] = coi.HostingSystem.ID()
+			l := log.G(ctx).WithField(logfields.ContainerID, opts.id)
+			if opts.vm != nil {
+				l.Data[logfields.UVMID] = opts.vm.ID()
 			}
 			l.WithFields(logrus.Fields{
 				"limit":  cpuLimit,
@@ -126,20 +122,17 @@ func createWindowsContainerDocument(ctx context.Context, coi *createOptionsInter
 	}
 
 	// Memory Resources
-	memoryMaxInMB := oci.ParseAnnotationsMemory(ctx, coi.Spec, oci.AnnotationContainerMemorySizeInMB, 0)
+	memoryMaxInMB := oci.ParseAnnotationsMemory(ctx, opts.spec, oci.AnnotationContainerMemorySizeInMB, 0)
 	if memoryMaxInMB > 0 {
-		v1.MemoryMaximumInMB = int64(memoryMaxInMB)
 		v2Container.Memory = &hcsschema.Memory{
 			SizeInMB: memoryMaxInMB,
 		}
 	}
 
 	// Storage Resources
-	storageBandwidthMax := oci.ParseAnnotationsStorageBps(ctx, coi.Spec, oci.AnnotationContainerStorageQoSBandwidthMaximum, 0)
-	storageIopsMax := oci.ParseAnnotationsStorageIops(ctx, coi.Spec, oci.AnnotationContainerStorageQoSIopsMaximum, 0)
+	storageBandwidthMax := oci.ParseAnnotationsStorageBps(ctx, opts.spec, oci.AnnotationContainerStorageQoSBandwidthMaximum, 0)
+	storageIopsMax := oci.ParseAnnotationsStorageIops(ctx, opts.spec, oci.AnnotationContainerStorageQoSIopsMaximum, 0)
 	if storageBandwidthMax > 0 || storageIopsMax > 0 {
-		v1.StorageBandwidthMaximum = uint64(storageBandwidthMax)
-		v1.StorageIOPSMaximum = uint64(storageIopsMax)
 		v2Container.Storage.QoS = &hcsschema.StorageQoS{
 			BandwidthMaximum: storageBandwidthMax,
 			IopsMaximum:      storageIopsMax,
@@ -147,76 +140,64 @@ func createWindowsContainerDocument(ctx context.Context, coi *createOptionsInter
 	}
 
 	// TODO V2 networking. Only partial at the moment. v2.Container.Networking.Namespace specifically
-	if coi.Spec.Windows.Network != nil {
+	if opts.spec.Windows.Network != nil {
 		v2Container.Networking = &hcsschema.Networking{}
 
-		v1.EndpointList = coi.Spec.Windows.Network.EndpointList
-		v2Container.Networking.Namespace = coi.actualNetworkNamespace
+		v2Container.Networking.Namespace = opts.networkNamespace
+		v2Container.Networking.AllowUnqualifiedDnsQuery = opts.spec.Windows.Network.AllowUnqualifiedDNSQuery
 
-		v1.AllowUnqualifiedDNSQuery = coi.Spec.Windows.Network.AllowUnqualifiedDNSQuery
-		v2Container.Networking.AllowUnqualifiedDnsQuery = v1.AllowUnqualifiedDNSQuery
-
-		if coi.Spec.Windows.Network.DNSSearchList != nil {
-			v1.DNSSearchList = strings.Join(coi.Spec.Windows.Network.DNSSearchList, ",")
-			v2Container.Networking.DnsSearchList = v1.DNSSearchList
+		if opts.spec.Windows.Network.DNSSearchList != nil {
+			v2Container.Networking.DnsSearchList = strings.Join(opts.spec.Windows.Network.DNSSearchList, ",")
 		}
 
-		v1.NetworkSharedContainerName = coi.Spec.Windows.Network.NetworkSharedContainerName
-		v2Container.Networking.NetworkSharedContainerName = v1.NetworkSharedContainerName
-	}
-
-	//	// TODO V2 Credentials not in the schema yet.
-	if cs, ok := coi.Spec.Windows.CredentialSpec.(string); ok {
-		v1.Credentials = cs
+		v2Container.Networking.NetworkSharedContainerName = opts.spec.Windows.Network.NetworkSharedContainerName
 	}
 
-	if coi.Spec.Root == nil {
+	if opts.spec.Root == nil {
 		return nil, nil, fmt.Errorf("spec is invalid - root isn't populated")
 	}
 
-	if coi.Spec.Root.Readonly {
+	if opts.spec.Root.Readonly {
 		return nil, nil, fmt.Errorf(`invalid container spec - readonly is not supported for Windows containers`)
 	}
 
-	// Strip off the top-most RW/scratch layer as that's passed in separately to HCS for v1
-	v1.LayerFolderPath = coi.Spec.Windows.LayerFolders[len(coi.Spec.Windows.LayerFolders)-1]
-
-	if (schemaversion.IsV21(coi.actualSchemaVersion) && coi.HostingSystem == nil) ||
-		(schemaversion.IsV10(coi.actualSchemaVersion) && coi.Spec.Windows.HyperV == nil) {
+	if (schemaversion.IsV21(opts.schemaVersion) && opts.vm == nil) ||
+		(schemaversion.IsV10(opts.schemaVersion) && opts.spec.Windows.HyperV == nil) {
 		// Argon v1 or v2.
 		const volumeGUIDRegex = `^\\\\\?\\(Volume)\{{0,1}[0-9a-fA-F]{8}\-[0-9a-fA-F]{4}\-[0-9a-fA-F]{4}\-[0-9a-fA-F]{4}\-[0-9a-fA-F]{12}(\}){0,1}\}(|\\)$`
-		if matched, err := regexp.MatchString(volumeGUIDRegex, coi.Spec.Root.Path); !matched || err != nil {
-			return nil, nil, fmt.Errorf(`invalid container spec - Root.Path '%s' must be a volume GUID path in the format '\\?\Volume{GUID}\'`, coi.Spec.Root.Path)
+		// The volume of a planned Argon is only known once the plan is executed.
+		if !isPlannedValue(opts.spec.Root.Path) {
+			if matched, err := regexp.MatchString(volumeGUIDRegex, opts.spec.Root.Path); !matched || err != nil {
+				return nil, nil, fmt.Errorf(`invalid container spec - Root.Path '%s' must be a volume GUID path in the format '\\?\Volume{GUID}\'`, opts.spec.Root.Path)
+			}
 		}
-		if coi.Spec.Root.Path[len(coi.Spec.Root.Path)-1] != '\\' {
-			coi.Spec.Root.Path += `\` // Be nice to clients and make sure well-formed for back-compat
+		if opts.spec.Root.Path[len(opts.spec.Root.Path)-1] != '\\' {
+			opts.spec.Root.Path += `\` // Be nice to clients and make sure well-formed for back-compat
 		}
-		v1.VolumePath = coi.Spec.Root.Path[:len(coi.Spec.Root.Path)-1] // Strip the trailing backslash. Required for v1.
-		v2Container.Storage.Path = coi.Spec.Root.Path
+		v2Container.Storage.Path = opts.spec.Root.Path
 	} else {
 		// A hosting system was supplied, implying v2 Xenon; OR a v1 Xenon.
-		if schemaversion.IsV10(coi.actualSchemaVersion) {
+		if schemaversion.IsV10(opts.schemaVersion) {
 			// V1 Xenon
-			v1.HvPartition = true
-			if coi.Spec == nil || coi.Spec.Windows == nil || coi.Spec.Windows.HyperV == nil { // Be resilient to nil de-reference
+			if opts.spec.Windows.HyperV == nil { // Be resilient to nil de-reference
 				return nil, nil, fmt.Errorf(`invalid container spec - Spec.Windows.HyperV is nil`)
 			}
-			if coi.Spec.Windows.HyperV.UtilityVMPath != "" {
+			if opts.spec.Windows.HyperV.UtilityVMPath != "" {
 				// Client-supplied utility VM path
-				v1.HvRuntime = &schema1.HvRuntime{ImagePath: coi.Spec.Windows.HyperV.UtilityVMPath}
+				v1.hvImagePath = opts.spec.Windows.HyperV.UtilityVMPath
 			} else {
 				// Client was lazy. Let's locate it from the layer folders instead.
-				uvmImagePath, err := uvmfolder.LocateUVMFolder(ctx, coi.Spec.Windows.LayerFolders)
+				uvmImagePath, err := opts.docHost.uvmImagePath(ctx, opts.spec.Windows.LayerFolders)
 				if err != nil {
 					return nil, nil, err
 				}
-				v1.HvRuntime = &schema1.HvRuntime{ImagePath: filepath.Join(uvmImagePath, `UtilityVM`)}
+				v1.hvImagePath = filepath.Join(uvmImagePath, `UtilityVM`)
 			}
 		} else {
 			// Hosting system was supplied, so is v2 Xenon.
-			v2Container.Storage.Path = coi.Spec.Root.Path
-			if coi.HostingSystem.OS() == "windows" {
-				layers, err := computeV2Layers(ctx, coi.HostingSystem, coi.Spec.Windows.LayerFolders[:len(coi.Spec.Windows.LayerFolders)-1])
+			v2Container.Storage.Path = opts.spec.Root.Path
+			if opts.vm.OS() == "windows" {
+				layers, err := computeV2Layers(ctx, opts.vm.layerGuestPath, opts.docHost.layerID, opts.spec.Windows.LayerFolders[:len(opts.spec.Windows.LayerFolders)-1])
 				if err != nil {
 					return nil, nil, err
 				}
@@ -225,32 +206,28 @@ func createWindowsContainerDocument(ctx context.Context, coi *createOptionsInter
 		}
 	}
 
-	if coi.HostingSystem == nil { // Argon v1 or v2
-		for _, layerPath := range coi.Spec.Windows.LayerFolders[:len(coi.Spec.Windows.LayerFolders)-1] {
-			layerID, err := wclayer.LayerID(layerPath)
+	if opts.vm == nil { // Argon v1 or v2
+		for _, layerPath := range opts.spec.Windows.LayerFolders[:len(opts.spec.Windows.LayerFolders)-1] {
+			layerID, err := opts.docHost.layerID(layerPath)
 			if err != nil {
 				return nil, nil, err
 			}
-			v1.Layers = append(v1.Layers, schema1.Layer{ID: layerID.String(), Path: layerPath})
-			v2Container.Storage.Layers = append(v2Container.Storage.Layers, hcsschema.Layer{Id: layerID.String(), Path: layerPath})
+			v2Container.Storage.Layers = append(v2Container.Storage.Layers, hcsschema.Layer{Id: layerID, Path: layerPath})
 		}
 	}
 
 	// Add the mounts as mapped directories or mapped pipes
 	// TODO: Mapped pipes to add in v2 schema.
 	var (
-		mdsv1 []schema1.MappedDir
-		mpsv1 []schema1.MappedPipe
 		mdsv2 []hcsschema.MappedDirectory
 		mpsv2 []hcsschema.MappedPipe
 	)
-	for _, mount := range coi.Spec.Mounts {
+	for _, mount := range opts.spec.Mounts {
 		const pipePrefix = `\\.\pipe\`
 		if mount.Type != "" {
 			return nil, nil, fmt.Errorf("invalid container spec - Mount.Type '%s' must not be set", mount.Type)
 		}
 		if strings.HasPrefix(strings.ToLower(mount.Destination), pipePrefix) {
-			mpsv1 = append(mpsv1, schema1.MappedPipe{HostPath: mount.Source, ContainerPipeName: mount.Destination[len(pipePrefix):]})
 			mpsv2 = append(mpsv2, hcsschema.MappedPipe{HostPath: mount.Source, ContainerPipeName: mount.Destination[len(pipePrefix):]})
 		} else {
 			readOnly := false
@@ -259,36 +236,100 @@ func createWindowsContainerDocument(ctx context.Context, coi *createOptionsInter
 					readOnly = true
 				}
 			}
-			mdv1 := schema1.MappedDir{HostPath: mount.Source, ContainerPath: mount.Destination, ReadOnly: readOnly}
 			mdv2 := hcsschema.MappedDirectory{ContainerPath: mount.Destination, ReadOnly: readOnly}
-			if coi.HostingSystem == nil {
+			if opts.vm == nil {
 				mdv2.HostPath = mount.Source
+			} else if strings.HasPrefix(mount.Source, UVMPathPrefix) {
+				mdv2.HostPath = strings.TrimPrefix(mount.Source, UVMPathPrefix)
 			} else {
-				uvmPath, err := coi.HostingSystem.GetVSMBUvmPath(ctx, mount.Source)
+				uvmPath, err := opts.vm.mountGuestPath(ctx, mount.Source)
 				if err != nil {
-					if err == uvm.ErrNotAttached {
-						// It could also be a scsi mount.
-						uvmPath, err = coi.HostingSystem.GetScsiUvmPath(ctx, mount.Source)
-						if err != nil {
-							return nil, nil, err
-						}
-					} else {
-						return nil, nil, err
-					}
+					return nil, nil, err
 				}
 				mdv2.HostPath = uvmPath
 			}
-			mdsv1 = append(mdsv1, mdv1)
 			mdsv2 = append(mdsv2, mdv2)
 		}
 	}
 
-	v1.MappedDirectories = mdsv1
 	v2Container.MappedDirectories = mdsv2
-	if len(mpsv1) > 0 && osversion.Get().Build < osversion.RS3 {
+	if len(mpsv2) > 0 && opts.docHost.build < osversion.RS3 {
 		return nil, nil, fmt.Errorf("named pipe mounts are not supported on this version of Windows")
 	}
-	v1.MappedPipes = mpsv1
 	v2Container.MappedPipes = mpsv2
-	return v1, v2Container, nil
+	return v2Container, v1, nil
+}
+
+// computeV2Layers returns the WCOW layers for `paths`, using `vsmbUvmPath` to
+// look up the guest path of each layer's VSMB share and `layerID` to look up
+// each layer's ID.
+func computeV2Layers(ctx context.Context, vsmbUvmPath func(context.Context, string) (string, error), layerID func(string) (string, error), paths []string) (layers []hcsschema.Layer, err error) {
+	for _, path := range paths {
+		uvmPath, err := vsmbUvmPath(ctx, path)
+		if err != nil {
+			return nil, err
+		}
+		id, err := layerID(path)
+		if err != nil {
+			return nil, err
+		}
+		layers = append(layers, hcsschema.Layer{Id: id, Path: uvmPath})
+	}
+	return layers, nil
+}
+
+// createV1ContainerDocument creates the v1 document of a WCOW container from
+// its v2 container object `v2` and the v1 only settings `v1`.
+func createV1ContainerDocument(opts *documentOptions, v2 *hcsschema.Container, v1 *v1Settings) *schema1.ContainerConfig {
+	spec := opts.spec
+	doc := &schema1.ContainerConfig{
+		SystemType:              "Container",
+		Name:                    opts.id,
+		Owner:                   opts.owner,
+		HvPartition:             v1.hvImagePath != "",
+		IgnoreFlushesDuringBoot: spec.Windows.IgnoreFlushesDuringBoot,
+		HostName:                spec.Hostname,
+		ProcessorCount:          uint32(v1.processorCount),
+		ProcessorMaximum:        int64(v1.processorLimit),
+		ProcessorWeight:         uint64(v1.processorWeight),
+		// Strip off the top-most RW/scratch layer as that's passed in separately to HCS for v1
+		LayerFolderPath: spec.Windows.LayerFolders[len(spec.Windows.LayerFolders)-1],
+	}
+	if v1.hvImagePath != "" {
+		doc.HvRuntime = &schema1.HvRuntime{ImagePath: v1.hvImagePath}
+	}
+	if v2.Memory != nil {
+		doc.MemoryMaximumInMB = int64(v2.Memory.SizeInMB)
+	}
+	if v2.Storage.QoS != nil {
+		doc.StorageBandwidthMaximum = uint64(v2.Storage.QoS.BandwidthMaximum)
+		doc.StorageIOPSMaximum = uint64(v2.Storage.QoS.IopsMaximum)
+	}
+	if spec.Windows.Network != nil {
+		doc.EndpointList = spec.Windows.Network.EndpointList
+		doc.AllowUnqualifiedDNSQuery = spec.Windows.Network.AllowUnqualifiedDNSQuery
+		if spec.Windows.Network.DNSSearchList != nil {
+			doc.DNSSearchList = strings.Join(spec.Windows.Network.DNSSearchList, ",")
+		}
+		doc.NetworkSharedContainerName = spec.Windows.Network.NetworkSharedContainerName
+	}
+	// TODO V2 Credentials not in the schema yet.
+	if cs, ok := spec.Windows.CredentialSpec.(string); ok {
+		doc.Credentials = cs
+	}
+	if v2.Storage.Path != "" {
+		doc.VolumePath = v2.Storage.Path[:len(v2.Storage.Path)-1] // Strip the trailing backslash. Required for v1.
+	}
+	for _, l := range v2.Storage.Layers {
+		doc.Layers = append(doc.Layers, schema1.Layer{ID: l.Id, Path: l.Path})
+	}
+	// A v1 container is never in a utility VM we manage, so the v2 mounts
+	// have the host paths.
+	for _, md := range v2.MappedDirectories {
+		doc.MappedDirectories = append(doc.MappedDirectories, schema1.MappedDir{HostPath: md.HostPath, ContainerPath: md.ContainerPath, ReadOnly: md.ReadOnly})
+	}
+	for _, mp := range v2.MappedPipes {
+		doc.MappedPipes = append(doc.MappedPipes, schema1.MappedPipe{HostPath: mp.HostPath, ContainerPipeName: mp.ContainerPipeName})
+	}
+	return doc
 }
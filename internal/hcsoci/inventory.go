@@ -0,0 +1,140 @@
+// +build windows
+
+package hcsoci
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/Microsoft/hcsshim/internal/uvm"
+)
+
+// deviceOwners are the containers that the devices of a single utility VM were
+// attached for.
+type deviceOwners struct {
+	devices map[string][]string // Container IDs by host path or network namespace
+	roots   map[string]string   // Container ID by container root in the utility VM
+}
+
+// deviceOwnerTable is the host-wide table of device owners by utility VM. The
+// users of read-only layers are tracked by the layer table instead, as layers
+// are shared by the containers in a utility VM.
+type deviceOwnerTable struct {
+	m   sync.Mutex
+	vms map[*uvm.UtilityVM]*deviceOwners
+}
+
+var deviceOwnership = &deviceOwnerTable{vms: make(map[*uvm.UtilityVM]*deviceOwners)}
+
+// forUVM returns the device owners of `vm`, which are dropped from the table
+// when `vm` exits. `t.m` must be held.
+func (t *deviceOwnerTable) forUVM(vm *uvm.UtilityVM) *deviceOwners {
+	if o, ok := t.vms[vm]; ok {
+		return o
+	}
+	o := &deviceOwners{
+		devices: make(map[string][]string),
+		roots:   make(map[string]string),
+	}
+	t.vms[vm] = o
+	go func() {
+		_ = vm.Wait()
+		t.m.Lock()
+		delete(t.vms, vm)
+		t.m.Unlock()
+	}()
+	return o
+}
+
+// add records that `key`, the host path of a device or the ID of a network
+// namespace attached to `vm`, is used by the container `owner`.
+func (t *deviceOwnerTable) add(vm *uvm.UtilityVM, key, owner string) {
+	if owner == "" {
+		return
+	}
+	t.m.Lock()
+	defer t.m.Unlock()
+	o := t.forUVM(vm)
+	o.devices[key] = append(o.devices[key], owner)
+}
+
+// remove records that `key` is no longer used by the container `owner`.
+func (t *deviceOwnerTable) remove(vm *uvm.UtilityVM, key, owner string) {
+	t.m.Lock()
+	defer t.m.Unlock()
+	o, ok := t.vms[vm]
+	if !ok {
+		return
+	}
+	owners := o.devices[key]
+	for i := range owners {
+		if owners[i] == owner {
+			owners = append(owners[:i], owners[i+1:]...)
+			break
+		}
+	}
+	if len(owners) == 0 {
+		delete(o.devices, key)
+	} else {
+		o.devices[key] = owners
+	}
+}
+
+// addRoot records that `root` is the root in `vm` of the container `owner`, so
+// that the users of read-only layers can be named.
+func (t *deviceOwnerTable) addRoot(vm *uvm.UtilityVM, root, owner string) {
+	if owner == "" {
+		return
+	}
+	t.m.Lock()
+	defer t.m.Unlock()
+	t.forUVM(vm).roots[root] = owner
+}
+
+// owners returns the owners of the devices of `vm` by key, with the users of
+// the read-only layers in `layers` added.
+func (t *deviceOwnerTable) owners(vm *uvm.UtilityVM, layers []LayerAttachment) map[string][]string {
+	t.m.Lock()
+	defer t.m.Unlock()
+	owners := make(map[string][]string)
+	o, ok := t.vms[vm]
+	if ok {
+		for key, ids := range o.devices {
+			owners[key] = append([]string(nil), ids...)
+		}
+	}
+	for _, a := range layers {
+		for _, root := range a.Users {
+			id := root
+			if ok && o.roots[root] != "" {
+				id = o.roots[root]
+			}
+			owners[a.HostPath] = append(owners[a.HostPath], id)
+		}
+	}
+	return owners
+}
+
+// DeviceInventory returns the devices attached to `vm` with the IDs of the
+// containers created by this process that use them. A device without owners
+// was attached for the utility VM itself, or by another process.
+func DeviceInventory(vm *uvm.UtilityVM) uvm.Inventory {
+	inv := vm.Inventory()
+	var layers []LayerAttachment
+	for _, a := range LayerAttachments() {
+		if a.UtilityVM == inv.ID {
+			layers = append(layers, a)
+		}
+	}
+	owners := deviceOwnership.owners(vm, layers)
+	for i := range inv.Devices {
+		d := &inv.Devices[i]
+		key := d.HostPath
+		if d.Kind == uvm.DeviceNetworkNamespace || d.Kind == uvm.DeviceNIC {
+			key = d.NetNS
+		}
+		d.Owners = owners[key]
+		sort.Strings(d.Owners)
+	}
+	return inv
+}
@@ -0,0 +1,357 @@
+// +build windows
+
+package hcsoci
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/Microsoft/hcsshim/internal/hcs"
+	"github.com/Microsoft/hcsshim/internal/hns"
+	"github.com/Microsoft/hcsshim/internal/log"
+	"github.com/Microsoft/hcsshim/internal/logfields"
+	"github.com/Microsoft/hcsshim/internal/uvm"
+	"github.com/sirupsen/logrus"
+)
+
+// Kinds of resources recorded in a journal.
+const (
+	journalNetworkNamespace = "NetworkNamespace"
+	journalNetworkEndpoint  = "NetworkEndpoint"
+	journalVMNetNS          = "VMNetworkNamespace"
+	journalLayers           = "Layers"
+	journalSCSI             = "SCSI"
+	journalVSMB             = "VSMB"
+	journalPlan9            = "Plan9"
+)
+
+const (
+	journalAllocate = "allocate"
+	journalRelease  = "release"
+)
+
+// journalEntry is a single line of a journal.
+type journalEntry struct {
+	Op   string
+	Kind string
+	// Key identifies the resource within its kind: the host path of a mount,
+	// the scratch folder of the layers or the ID of a namespace or endpoint.
+	Key        string
+	NetNS      string   `json:",omitempty"` // Namespace of a network endpoint
+	UtilityVM  string   `json:",omitempty"` // ID of the utility VM the resource is attached to
+	SystemID   string   `json:",omitempty"` // Compute system ID of the utility VM
+	GuestRoot  string   `json:",omitempty"`
+	Layers     []string `json:",omitempty"`
+	AutoManage bool     `json:",omitempty"`
+}
+
+func (e *journalEntry) matches(o *journalEntry) bool {
+	return e.Kind == o.Kind && e.Key == o.Key && e.NetNS == o.NetNS
+}
+
+// journal is an append-only on-disk record of the resources allocated for a
+// container. Each allocation is recorded before it is applied and each release
+// after it succeeds, so if the process dies the outstanding allocations can be
+// torn down by RecoverJournal. All methods are no-ops on a nil journal.
+type journal struct {
+	m    sync.Mutex
+	path string
+	f    *os.File
+}
+
+// openJournal opens the journal at `path` for appending, creating it if it
+// does not exist.
+func openJournal(path string) (*journal, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open resource journal: %s", err)
+	}
+	return &journal{path: path, f: f}, nil
+}
+
+func (j *journal) write(e *journalEntry) error {
+	if j == nil {
+		return nil
+	}
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	j.m.Lock()
+	defer j.m.Unlock()
+	if j.f == nil {
+		return fmt.Errorf("resource journal %s is closed", j.path)
+	}
+	if _, err := j.f.Write(append(b, '\n')); err != nil {
+		return fmt.Errorf("failed to write resource journal: %s", err)
+	}
+	if err := j.f.Sync(); err != nil {
+		return fmt.Errorf("failed to flush resource journal: %s", err)
+	}
+	return nil
+}
+
+// allocate records that the resource described by `e` is about to be
+// allocated. The allocation must not be applied if this fails.
+func (j *journal) allocate(e journalEntry) error {
+	e.Op = journalAllocate
+	return j.write(&e)
+}
+
+// release records that the resource described by `e` has been released.
+// Failing to record a release only means recovery attempts it again, so the
+// error is logged rather than returned.
+func (j *journal) release(ctx context.Context, e journalEntry) {
+	e.Op = journalRelease
+	if err := j.write(&e); err != nil {
+		log.G(ctx).WithError(err).WithField("kind", e.Kind).Warn("failed to record resource release")
+	}
+}
+
+// remove closes and deletes the journal once every resource is released.
+func (j *journal) remove() error {
+	if j == nil {
+		return nil
+	}
+	j.m.Lock()
+	defer j.m.Unlock()
+	if j.f != nil {
+		j.f.Close()
+		j.f = nil
+	}
+	if err := os.Remove(j.path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// readJournal returns the allocations in the journal at `path` that have not
+// been released, in the order they were made. A truncated final line, left by
+// a crash during a write, is ignored.
+func readJournal(path string) ([]journalEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var outstanding []journalEntry
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		var e journalEntry
+		if err := json.Unmarshal(s.Bytes(), &e); err != nil {
+			break
+		}
+		switch e.Op {
+		case journalAllocate:
+			outstanding = append(outstanding, e)
+		case journalRelease:
+			for i := len(outstanding) - 1; i >= 0; i-- {
+				if outstanding[i].matches(&e) {
+					outstanding = append(outstanding[:i], outstanding[i+1:]...)
+					break
+				}
+			}
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return outstanding, nil
+}
+
+// RecoverJournal tears down the resources left allocated in the journal at
+// `path` by a process that died without releasing them, then deletes the
+// journal. It is a no-op if the journal does not exist.
+//
+// Network namespaces and endpoints, host-mounted layers and auto-managed
+// virtual disks are released. Attachments to a utility VM are removed from
+// `vm`, the utility VM reopened by the caller, if it is still running, as a
+// recoverable or pooled utility VM outlives the process that used it. They
+// are only skipped once the utility VM they were attached to has exited. If
+// that utility VM is still running but is not `vm`, the journal is kept and
+// an error returned.
+func RecoverJournal(ctx context.Context, path string, vm *uvm.UtilityVM) error {
+	outstanding, err := readJournal(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read resource journal: %s", err)
+	}
+
+	var retErr error
+	for i := len(outstanding) - 1; i >= 0; i-- {
+		e := &outstanding[i]
+		l := log.G(ctx).WithFields(logrus.Fields{
+			"kind": e.Kind,
+			"key":  e.Key,
+		})
+		if e.UtilityVM != "" {
+			l = l.WithField(logfields.UVMID, e.UtilityVM)
+		}
+		if err := recoverJournalEntry(ctx, e, vm); err != nil {
+			l.WithError(err).Error("failed to release orphaned resource")
+			if retErr == nil {
+				retErr = err
+			}
+			continue
+		}
+		l.Info("released orphaned resource")
+	}
+	if retErr != nil {
+		// Keep the journal so the remaining resources are retried.
+		return retErr
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func recoverJournalEntry(ctx context.Context, e *journalEntry, vm *uvm.UtilityVM) error {
+	switch e.Kind {
+	case journalNetworkEndpoint:
+		if err := hns.RemoveNamespaceEndpoint(e.NetNS, e.Key); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	case journalNetworkNamespace:
+		if err := hns.RemoveNamespace(e.Key); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	case journalLayers:
+		if e.UtilityVM == "" {
+			// Argon layers are mounted on the host.
+			return UnmountContainerLayers(ctx, e.Layers, e.GuestRoot, nil, UnmountOperationAll)
+		}
+	case journalVMNetNS, journalSCSI, journalVSMB, journalPlan9:
+	default:
+		return fmt.Errorf("unknown resource kind %q", e.Kind)
+	}
+
+	vm, err := journalUtilityVM(ctx, e, vm)
+	if err != nil {
+		return err
+	}
+	if vm != nil {
+		if err := recoverAttachment(ctx, e, vm); err != nil {
+			return err
+		}
+	}
+	if e.Kind == journalSCSI && e.AutoManage {
+		if err := os.Remove(e.Key); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// journalUtilityVM returns the running utility VM that the resource of `e` is
+// attached to, or nil if that utility VM has exited and took the attachment
+// with it. `vm` is the utility VM reopened by the caller, if any.
+func journalUtilityVM(ctx context.Context, e *journalEntry, vm *uvm.UtilityVM) (*uvm.UtilityVM, error) {
+	if vm != nil && vm.ID() == e.UtilityVM {
+		if vm.Exited() {
+			return nil, nil
+		}
+		return vm, nil
+	}
+	systemID := e.SystemID
+	if systemID == "" {
+		systemID = e.UtilityVM
+	}
+	system, err := hcs.OpenComputeSystem(ctx, systemID)
+	if err != nil {
+		if hcs.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open utility VM %s: %s", e.UtilityVM, err)
+	}
+	system.Close()
+	return nil, fmt.Errorf("utility VM %s is still running but was not reopened", e.UtilityVM)
+}
+
+// recoverAttachment removes the attachment of `e` from the running utility VM
+// `vm`. An attachment that is already gone is not an error.
+func recoverAttachment(ctx context.Context, e *journalEntry, vm *uvm.UtilityVM) error {
+	switch e.Kind {
+	case journalVMNetNS:
+		return vm.RemoveNetNS(ctx, e.Key)
+	case journalLayers:
+		return recoverLayers(ctx, e, vm)
+	case journalSCSI:
+		if err := vm.RemoveSCSI(ctx, e.Key); err != nil && err != uvm.ErrNotAttached {
+			return err
+		}
+	case journalVSMB:
+		if _, err := vm.GetVSMBUvmPath(ctx, e.Key); err != nil {
+			if err == uvm.ErrNotAttached || os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		return vm.RemoveVSMB(ctx, e.Key)
+	case journalPlan9:
+		share, err := vm.FindPlan9(e.Key)
+		if err != nil {
+			if err == uvm.ErrNotAttached {
+				return nil
+			}
+			return err
+		}
+		return vm.RemovePlan9(ctx, share)
+	}
+	return nil
+}
+
+// recoverLayers unmounts the layers of `e` from the running utility VM `vm`.
+// The read-only layers that are still attached are adopted first, so that a
+// layer shared with another container in `vm` is only removed once that
+// container releases it too.
+func recoverLayers(ctx context.Context, e *journalEntry, vm *uvm.UtilityVM) error {
+	if len(e.Layers) < 2 {
+		return nil
+	}
+	devices := make(map[string]uvm.Device)
+	for _, d := range vm.Inventory().Devices {
+		devices[d.HostPath] = d
+	}
+	op := UnmountOperation(0)
+	scratch := filepath.Join(e.Key, "sandbox.vhdx")
+	if _, ok := devices[scratch]; ok {
+		op |= UnmountOperationSCSI
+	}
+	vmLayers := layerAttachments.forUVM(vm)
+	var adopted []string
+	for _, layerPath := range e.Layers[:len(e.Layers)-1] {
+		hostPath := layerPath
+		if vm.OS() != "windows" {
+			hostPath = filepath.Join(layerPath, "layer.vhd")
+		}
+		d, ok := devices[hostPath]
+		if !ok {
+			continue
+		}
+		if err := vmLayers.adopt(ctx, hostPath, d.UVMPath, d.Kind, e.GuestRoot); err != nil {
+			return err
+		}
+		adopted = append(adopted, layerPath)
+	}
+	layers := e.Layers
+	if len(adopted) > 0 {
+		// Only the layers that were adopted are released.
+		layers = append(adopted, e.Key)
+		op |= UnmountOperationVSMB | UnmountOperationVPMEM
+	}
+	if op == 0 {
+		return nil
+	}
+	return UnmountContainerLayers(ctx, layers, e.GuestRoot, vm, op)
+}
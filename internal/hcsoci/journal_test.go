@@ -0,0 +1,121 @@
+// +build windows
+
+package hcsoci
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func newTestJournal(t *testing.T) (*journal, func()) {
+	dir, err := ioutil.TempDir("", "journal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	j, err := openJournal(filepath.Join(dir, "resources.journal"))
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	return j, func() {
+		j.remove()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestJournalOutstanding(t *testing.T) {
+	ctx := context.Background()
+	j, cleanup := newTestJournal(t)
+	defer cleanup()
+
+	entries := []journalEntry{
+		{Kind: journalNetworkNamespace, Key: "ns"},
+		{Kind: journalNetworkEndpoint, Key: "ep1", NetNS: "ns"},
+		{Kind: journalNetworkEndpoint, Key: "ep2", NetNS: "ns"},
+		{Kind: journalVSMB, Key: `C:\share`, UtilityVM: "vm"},
+	}
+	for _, e := range entries {
+		if err := j.allocate(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	j.release(ctx, journalEntry{Kind: journalNetworkEndpoint, Key: "ep1", NetNS: "ns"})
+	j.release(ctx, journalEntry{Kind: journalVSMB, Key: `C:\share`})
+
+	outstanding, err := readJournal(j.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(outstanding) != 2 || outstanding[0].Key != "ns" || outstanding[1].Key != "ep2" {
+		t.Fatalf("unexpected outstanding entries %+v", outstanding)
+	}
+}
+
+func TestJournalTruncated(t *testing.T) {
+	j, cleanup := newTestJournal(t)
+	defer cleanup()
+
+	if err := j.allocate(journalEntry{Kind: journalPlan9, Key: `C:\share`, UtilityVM: "vm"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := j.f.WriteString(`{"Op":"release","Kind":"Pla`); err != nil {
+		t.Fatal(err)
+	}
+	outstanding, err := readJournal(j.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(outstanding) != 1 {
+		t.Fatalf("expected the truncated release to be ignored, got %+v", outstanding)
+	}
+}
+
+func TestRecoverJournal(t *testing.T) {
+	ctx := context.Background()
+	j, cleanup := newTestJournal(t)
+	defer cleanup()
+
+	disk := filepath.Join(filepath.Dir(j.path), "disk.vhdx")
+	if err := ioutil.WriteFile(disk, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := j.allocate(journalEntry{Kind: journalSCSI, Key: disk, UtilityVM: "vm", AutoManage: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := j.allocate(journalEntry{Kind: journalVSMB, Key: `C:\share`, UtilityVM: "vm"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Simulate the process dying with the allocations outstanding. The utility
+	// VM "vm" does not exist, so its attachments are skipped but the
+	// auto-managed disk is still removed.
+	j.f.Close()
+	j.f = nil
+
+	if err := RecoverJournal(ctx, j.path, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(disk); !os.IsNotExist(err) {
+		t.Fatal("expected the auto-managed disk to be removed")
+	}
+	if _, err := os.Stat(j.path); !os.IsNotExist(err) {
+		t.Fatal("expected the journal to be removed")
+	}
+	if err := RecoverJournal(ctx, j.path, nil); err != nil {
+		t.Fatalf("recovering a missing journal must succeed: %s", err)
+	}
+}
+
+func TestNilJournal(t *testing.T) {
+	var j *journal
+	if err := j.allocate(journalEntry{Kind: journalVSMB, Key: "x"}); err != nil {
+		t.Fatal(err)
+	}
+	j.release(context.Background(), journalEntry{Kind: journalVSMB, Key: "x"})
+	if err := j.remove(); err != nil {
+		t.Fatal(err)
+	}
+}
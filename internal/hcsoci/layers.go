@@ -20,12 +20,6 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-type lcowLayerEntry struct {
-	hostPath string
-	uvmPath  string
-	scsi     bool
-}
-
 const scratchPath = "scratch"
 
 // mountContainerLayers is a helper for clients to hide all the complexity of layer mounting
@@ -90,62 +84,23 @@ func MountContainerLayers(ctx context.Context, layerFolders []string, guestRoot
 	// a GUID based on the folder path. For Linux, this is a VPMEM device, except where is over the
 	// max size supported, where we put it on SCSI instead.
 	//
-	//  Each layer is ref-counted so that multiple containers in the same utility VM can share them.
-	var wcowLayersAdded []string
-	var lcowlayersAdded []lcowLayerEntry
+	//  Each layer is attached once per utility VM and shared by all the containers in it using the
+	//  layer. See LayerAttachments.
+	vmLayers := layerAttachments.forUVM(uvm)
+	var layersAdded []LayerAttachment
 	attachedSCSIHostPath := ""
 
 	for _, layerPath := range layerFolders[:len(layerFolders)-1] {
-		var err error
-		if uvm.OS() == "windows" {
-			options := &hcsschema.VirtualSmbShareOptions{
-				ReadOnly:            true,
-				PseudoOplocks:       true,
-				TakeBackupPrivilege: true,
-				CacheIo:             true,
-				ShareRead:           true,
-			}
-			err = uvm.AddVSMB(ctx, layerPath, "", options)
-			if err == nil {
-				wcowLayersAdded = append(wcowLayersAdded, layerPath)
-			}
-		} else {
-			uvmPath := ""
-			hostPath := filepath.Join(layerPath, "layer.vhd")
-
-			var fi os.FileInfo
-			fi, err = os.Stat(hostPath)
-
-			if err == nil && uvm.ExceededVPMem(fi.Size()) {
-				// Too big for PMEM. Add on SCSI instead (at /tmp/S<C>/<L>).
-				var (
-					controller int
-					lun        int32
-				)
-				controller, lun, err = uvm.AddSCSILayer(ctx, hostPath)
-				if err == nil {
-					lcowlayersAdded = append(lcowlayersAdded,
-						lcowLayerEntry{
-							hostPath: hostPath,
-							uvmPath:  fmt.Sprintf("/tmp/S%d/%d", controller, lun),
-							scsi:     true,
-						})
-				}
-			} else {
-				_, uvmPath, err = uvm.AddVPMEM(ctx, hostPath, true) // UVM path is calculated. Will be /tmp/vN/
-				if err == nil {
-					lcowlayersAdded = append(lcowlayersAdded,
-						lcowLayerEntry{
-							hostPath: hostPath,
-							uvmPath:  uvmPath,
-						})
-				}
-			}
+		hostPath := layerPath
+		if uvm.OS() != "windows" {
+			hostPath = filepath.Join(layerPath, "layer.vhd")
 		}
+		a, err := vmLayers.acquire(ctx, hostPath, guestRoot)
 		if err != nil {
-			cleanupOnMountFailure(ctx, uvm, wcowLayersAdded, lcowlayersAdded, attachedSCSIHostPath)
+			cleanupOnMountFailure(ctx, uvm, vmLayers, guestRoot, layersAdded, attachedSCSIHostPath)
 			return nil, err
 		}
+		layersAdded = append(layersAdded, a)
 	}
 
 	// Add the scratch at an unused SCSI location. The container path inside the
@@ -156,7 +111,7 @@ func MountContainerLayers(ctx context.Context, layerFolders []string, guestRoot
 	containerScratchPathInUVM := ospath.Join(uvm.OS(), guestRoot, scratchPath)
 	_, _, err := uvm.AddSCSI(ctx, hostPath, containerScratchPathInUVM, false)
 	if err != nil {
-		cleanupOnMountFailure(ctx, uvm, wcowLayersAdded, lcowlayersAdded, attachedSCSIHostPath)
+		cleanupOnMountFailure(ctx, uvm, vmLayers, guestRoot, layersAdded, attachedSCSIHostPath)
 		return nil, err
 	}
 	attachedSCSIHostPath = hostPath
@@ -164,9 +119,13 @@ func MountContainerLayers(ctx context.Context, layerFolders []string, guestRoot
 	if uvm.OS() == "windows" {
 		// 	Load the filter at the C:\s<ID> location calculated above. We pass into this request each of the
 		// 	read-only layer folders.
-		layers, err := computeV2Layers(ctx, uvm, wcowLayersAdded)
+		var wcowLayers []string
+		for _, a := range layersAdded {
+			wcowLayers = append(wcowLayers, a.HostPath)
+		}
+		layers, err := computeV2Layers(ctx, uvm.GetVSMBUvmPath, layerID, wcowLayers)
 		if err != nil {
-			cleanupOnMountFailure(ctx, uvm, wcowLayersAdded, lcowlayersAdded, attachedSCSIHostPath)
+			cleanupOnMountFailure(ctx, uvm, vmLayers, guestRoot, layersAdded, attachedSCSIHostPath)
 			return nil, err
 		}
 		guestRequest := guestrequest.CombinedLayers{
@@ -181,7 +140,7 @@ func MountContainerLayers(ctx context.Context, layerFolders []string, guestRoot
 			},
 		}
 		if err := uvm.Modify(ctx, combinedLayersModification); err != nil {
-			cleanupOnMountFailure(ctx, uvm, wcowLayersAdded, lcowlayersAdded, attachedSCSIHostPath)
+			cleanupOnMountFailure(ctx, uvm, vmLayers, guestRoot, layersAdded, attachedSCSIHostPath)
 			return nil, err
 		}
 		log.G(ctx).Debug("hcsshim::mountContainerLayers Succeeded")
@@ -206,8 +165,8 @@ func MountContainerLayers(ctx context.Context, layerFolders []string, guestRoot
 	//       /dev/sd(b...) are scratch spaces for each container
 
 	layers := []hcsschema.Layer{}
-	for _, l := range lcowlayersAdded {
-		layers = append(layers, hcsschema.Layer{Path: l.uvmPath})
+	for _, a := range layersAdded {
+		layers = append(layers, hcsschema.Layer{Path: a.UVMPath})
 	}
 	guestRequest := guestrequest.CombinedLayers{
 		ContainerRootPath: path.Join(guestRoot, rootfsPath),
@@ -222,7 +181,7 @@ func MountContainerLayers(ctx context.Context, layerFolders []string, guestRoot
 		},
 	}
 	if err := uvm.Modify(ctx, combinedLayersModification); err != nil {
-		cleanupOnMountFailure(ctx, uvm, wcowLayersAdded, lcowlayersAdded, attachedSCSIHostPath)
+		cleanupOnMountFailure(ctx, uvm, vmLayers, guestRoot, layersAdded, attachedSCSIHostPath)
 		return nil, err
 	}
 	log.G(ctx).Debug("hcsshim::mountContainerLayers Succeeded")
@@ -230,6 +189,56 @@ func MountContainerLayers(ctx context.Context, layerFolders []string, guestRoot
 
 }
 
+// planContainerLayers returns the step that mounts `layerFolders` for a
+// container whose root in the utility VM is `guestRoot`. The step records how
+// each read-only layer is expected to be attached; the final decision is made
+// by MountContainerLayers when the plan is executed.
+func planContainerLayers(coi *createOptionsInternal, layerFolders []string, guestRoot string) (PlanStep, error) {
+	if len(layerFolders) < 2 {
+		return PlanStep{}, fmt.Errorf("need at least two layers - base and scratch")
+	}
+	scratchFolder := layerFolders[len(layerFolders)-1]
+	step := PlanStep{
+		Type:         PlanStepMountLayers,
+		HostPath:     scratchFolder,
+		LayerFolders: layerFolders,
+	}
+	if coi.host == nil {
+		// Argon. The volume path is only known once the layer is prepared.
+		for _, layerPath := range layerFolders[:len(layerFolders)-1] {
+			step.Layers = append(step.Layers, PlannedLayer{HostPath: layerPath, Device: PlannedLayerHost})
+		}
+		step.GuestPath = plannedValue("volume", scratchFolder)
+		return step, nil
+	}
+
+	for _, layerPath := range layerFolders[:len(layerFolders)-1] {
+		if coi.host.OS() == "windows" {
+			step.Layers = append(step.Layers, PlannedLayer{HostPath: layerPath, Device: PlannedLayerVSMB})
+			continue
+		}
+		hostPath := filepath.Join(layerPath, "layer.vhd")
+		fi, err := os.Stat(hostPath)
+		if err != nil {
+			return PlanStep{}, err
+		}
+		device := PlannedLayerVPMem
+		if a, ok := layerAttachments.find(coi.HostingSystem, hostPath); ok {
+			// Already attached for another container in the utility VM.
+			device = a.Device
+		} else if coi.host.ExceededVPMem(fi.Size()) {
+			device = PlannedLayerSCSI
+		}
+		step.Layers = append(step.Layers, PlannedLayer{HostPath: hostPath, Device: device})
+	}
+	if coi.host.OS() == "windows" {
+		step.GuestPath = ospath.Join("windows", guestRoot, scratchPath)
+	} else {
+		step.GuestPath = path.Join(guestRoot, rootfsPath)
+	}
+	return step, nil
+}
+
 // UnmountOperation is used when calling Unmount() to determine what type of unmount is
 // required. In V1 schema, this must be unmountOperationAll. In V2, client can
 // be more optimal and only unmount what they need which can be a minor performance
@@ -308,13 +317,20 @@ func UnmountContainerLayers(ctx context.Context, layerFolders []string, guestRoo
 		}
 	}
 
-	// Remove each of the read-only layers from VSMB. These's are ref-counted and
-	// only removed once the count drops to zero. This allows multiple containers
-	// to share layers.
-	if uvm.OS() == "windows" && len(layerFolders) > 1 && (op&UnmountOperationVSMB) == UnmountOperationVSMB {
+	// Release each of the read-only layers. These are shared by the containers in
+	// the utility VM and only removed once the last of them releases the layer.
+	// For LCOW this removes the layer from VPMEM, or from SCSI for large layers.
+	vmLayers := layerAttachments.forUVM(uvm)
+	releaseLayers := (uvm.OS() == "windows" && (op&UnmountOperationVSMB) == UnmountOperationVSMB) ||
+		(uvm.OS() == "linux" && (op&UnmountOperationVPMEM) == UnmountOperationVPMEM)
+	if releaseLayers {
 		for _, layerPath := range layerFolders[:len(layerFolders)-1] {
-			if e := uvm.RemoveVSMB(ctx, layerPath); e != nil {
-				log.G(ctx).WithError(e).Debug("remove VSMB failed")
+			hostPath := layerPath
+			if uvm.OS() == "linux" {
+				hostPath = filepath.Join(layerPath, "layer.vhd")
+			}
+			if e := vmLayers.release(ctx, hostPath, guestRoot); e != nil {
+				log.G(ctx).WithError(e).Debug("release layer failed")
 				if retError == nil {
 					retError = e
 				} else {
@@ -324,49 +340,15 @@ func UnmountContainerLayers(ctx context.Context, layerFolders []string, guestRoo
 		}
 	}
 
-	// Remove each of the read-only layers from VPMEM (or SCSI). These's are ref-counted
-	// and only removed once the count drops to zero. This allows multiple containers to
-	// share layers. Note that SCSI is used on large layers.
-	if uvm.OS() == "linux" && len(layerFolders) > 1 && (op&UnmountOperationVPMEM) == UnmountOperationVPMEM {
-		for _, layerPath := range layerFolders[:len(layerFolders)-1] {
-			hostPath := filepath.Join(layerPath, "layer.vhd")
-			if fi, err := os.Stat(hostPath); err != nil {
-				var e error
-				if uint64(fi.Size()) > uvm.PMemMaxSizeBytes() {
-					e = uvm.RemoveSCSI(ctx, hostPath)
-				} else {
-					e = uvm.RemoveVPMEM(ctx, hostPath)
-				}
-				if e != nil {
-					log.G(ctx).WithError(e).Debug("remove layer failed")
-					if retError == nil {
-						retError = e
-					} else {
-						retError = errors.Wrapf(retError, e.Error())
-					}
-				}
-			}
-		}
-	}
-
 	// TODO (possibly) Consider deleting the container directory in the utility VM
 
 	return retError
 }
 
-func cleanupOnMountFailure(ctx context.Context, uvm *uvm.UtilityVM, wcowLayers []string, lcowLayers []lcowLayerEntry, scratchHostPath string) {
-	for _, wl := range wcowLayers {
-		if err := uvm.RemoveVSMB(ctx, wl); err != nil {
-			log.G(ctx).WithError(err).Warn("Possibly leaked vsmbshare on error removal path")
-		}
-	}
-	for _, ll := range lcowLayers {
-		if ll.scsi {
-			if err := uvm.RemoveSCSI(ctx, ll.hostPath); err != nil {
-				log.G(ctx).WithError(err).Warn("Possibly leaked SCSI on error removal path")
-			}
-		} else if err := uvm.RemoveVPMEM(ctx, ll.hostPath); err != nil {
-			log.G(ctx).WithError(err).Warn("Possibly leaked vpmemdevice on error removal path")
+func cleanupOnMountFailure(ctx context.Context, uvm *uvm.UtilityVM, vmLayers *uvmLayers, guestRoot string, layers []LayerAttachment, scratchHostPath string) {
+	for _, a := range layers {
+		if err := vmLayers.release(ctx, a.HostPath, guestRoot); err != nil {
+			log.G(ctx).WithError(err).Warn("Possibly leaked layer on error removal path")
 		}
 	}
 	if scratchHostPath != "" {
@@ -375,18 +357,3 @@ func cleanupOnMountFailure(ctx context.Context, uvm *uvm.UtilityVM, wcowLayers [
 		}
 	}
 }
-
-func computeV2Layers(ctx context.Context, vm *uvm.UtilityVM, paths []string) (layers []hcsschema.Layer, err error) {
-	for _, path := range paths {
-		uvmPath, err := vm.GetVSMBUvmPath(ctx, path)
-		if err != nil {
-			return nil, err
-		}
-		layerID, err := wclayer.LayerID(path)
-		if err != nil {
-			return nil, err
-		}
-		layers = append(layers, hcsschema.Layer{Id: layerID.String(), Path: uvmPath})
-	}
-	return layers, nil
-}
@@ -0,0 +1,290 @@
+// +build windows
+
+package hcsoci
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/Microsoft/hcsshim/internal/log"
+	"github.com/Microsoft/hcsshim/internal/logfields"
+	hcsschema "github.com/Microsoft/hcsshim/internal/schema2"
+	"github.com/Microsoft/hcsshim/internal/uvm"
+	"github.com/sirupsen/logrus"
+)
+
+// LayerAttachment is a read-only layer attached to a utility VM. A layer is
+// attached once per utility VM and shared by every container in it that uses
+// the layer. It is removed when the last of those containers unmounts its
+// layers, or dropped when the utility VM exits.
+type LayerAttachment struct {
+	UtilityVM string // ID of the utility VM
+	HostPath  string // Layer folder (WCOW) or layer VHD (LCOW) on the host
+	UVMPath   string // Path of the layer in the utility VM
+	Device    string // How the layer is attached. One of the PlannedLayer* constants.
+	// Users are the roots in the utility VM of the containers using the layer.
+	Users []string
+}
+
+// uvmLayers are the layer attachments of a single utility VM.
+type uvmLayers struct {
+	id     string
+	attach func(ctx context.Context, hostPath string) (LayerAttachment, error)
+	detach func(ctx context.Context, a *LayerAttachment) error
+
+	// m is held while layers are attached or removed so that concurrent
+	// mounts of the same layer share a single attachment.
+	m           sync.Mutex
+	attachments map[string]*LayerAttachment // By host path
+}
+
+// layerTable is the host-wide table of layer attachments by utility VM.
+type layerTable struct {
+	m   sync.Mutex
+	vms map[*uvm.UtilityVM]*uvmLayers
+}
+
+var layerAttachments = &layerTable{vms: make(map[*uvm.UtilityVM]*uvmLayers)}
+
+// LayerAttachments returns a snapshot of the read-only layers attached to
+// utility VMs by this process, ordered by utility VM ID and host path.
+func LayerAttachments() []LayerAttachment {
+	return layerAttachments.snapshot()
+}
+
+// forUVM returns the layer attachments of `vm`, which are dropped from the
+// table when `vm` exits.
+func (t *layerTable) forUVM(vm *uvm.UtilityVM) *uvmLayers {
+	t.m.Lock()
+	defer t.m.Unlock()
+	if l, ok := t.vms[vm]; ok {
+		return l
+	}
+	l := &uvmLayers{
+		id: vm.ID(),
+		attach: func(ctx context.Context, hostPath string) (LayerAttachment, error) {
+			return attachLayer(ctx, vm, hostPath)
+		},
+		detach: func(ctx context.Context, a *LayerAttachment) error {
+			return detachLayer(ctx, vm, a)
+		},
+		attachments: make(map[string]*LayerAttachment),
+	}
+	t.vms[vm] = l
+	go func() {
+		_ = vm.Wait()
+		t.m.Lock()
+		delete(t.vms, vm)
+		t.m.Unlock()
+	}()
+	return l
+}
+
+// find returns the attachment of `hostPath` to `vm`, if any.
+func (t *layerTable) find(vm *uvm.UtilityVM, hostPath string) (LayerAttachment, bool) {
+	t.m.Lock()
+	l, ok := t.vms[vm]
+	t.m.Unlock()
+	if !ok {
+		return LayerAttachment{}, false
+	}
+	l.m.Lock()
+	defer l.m.Unlock()
+	if a, ok := l.attachments[hostPath]; ok {
+		return copyLayerAttachment(a), true
+	}
+	return LayerAttachment{}, false
+}
+
+func (t *layerTable) snapshot() []LayerAttachment {
+	t.m.Lock()
+	vms := make([]*uvmLayers, 0, len(t.vms))
+	for _, l := range t.vms {
+		vms = append(vms, l)
+	}
+	t.m.Unlock()
+
+	var s []LayerAttachment
+	for _, l := range vms {
+		l.m.Lock()
+		for _, a := range l.attachments {
+			s = append(s, copyLayerAttachment(a))
+		}
+		l.m.Unlock()
+	}
+	sort.Slice(s, func(i, j int) bool {
+		if s[i].UtilityVM != s[j].UtilityVM {
+			return s[i].UtilityVM < s[j].UtilityVM
+		}
+		return s[i].HostPath < s[j].HostPath
+	})
+	return s
+}
+
+func copyLayerAttachment(a *LayerAttachment) LayerAttachment {
+	c := *a
+	c.Users = append([]string(nil), a.Users...)
+	return c
+}
+
+// acquire adds `user` to the attachment of `hostPath`, attaching the layer
+// first if no other container in the utility VM uses it.
+func (l *uvmLayers) acquire(ctx context.Context, hostPath, user string) (LayerAttachment, error) {
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	a, ok := l.attachments[hostPath]
+	if !ok {
+		na, err := l.attach(ctx, hostPath)
+		if err != nil {
+			return LayerAttachment{}, err
+		}
+		na.UtilityVM = l.id
+		na.HostPath = hostPath
+		na.Users = nil
+		a = &na
+		l.attachments[hostPath] = a
+	}
+	for _, u := range a.Users {
+		if u == user {
+			return LayerAttachment{}, fmt.Errorf("layer %s is already used by %s in utility VM %s", hostPath, user, l.id)
+		}
+	}
+	a.Users = append(a.Users, user)
+	log.G(ctx).WithFields(logrus.Fields{
+		logfields.UVMID: l.id,
+		"hostPath":      hostPath,
+		"uvmPath":       a.UVMPath,
+		"device":        a.Device,
+		"users":         len(a.Users),
+	}).Debug("hcsshim::acquire layer")
+	return copyLayerAttachment(a), nil
+}
+
+// adopt adds `user` to the attachment of `hostPath`, which is already attached
+// to the utility VM on `device` at `uvmPath`, such as by a process that exited
+// before the utility VM was reopened.
+func (l *uvmLayers) adopt(ctx context.Context, hostPath, uvmPath, device, user string) error {
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	a, ok := l.attachments[hostPath]
+	if !ok {
+		a = &LayerAttachment{
+			UtilityVM: l.id,
+			HostPath:  hostPath,
+			UVMPath:   uvmPath,
+			Device:    device,
+		}
+		l.attachments[hostPath] = a
+	}
+	for _, u := range a.Users {
+		if u == user {
+			return fmt.Errorf("layer %s is already used by %s in utility VM %s", hostPath, user, l.id)
+		}
+	}
+	a.Users = append(a.Users, user)
+	log.G(ctx).WithFields(logrus.Fields{
+		logfields.UVMID: l.id,
+		"hostPath":      hostPath,
+		"uvmPath":       a.UVMPath,
+		"device":        a.Device,
+		"users":         len(a.Users),
+	}).Debug("hcsshim::adopt layer")
+	return nil
+}
+
+// release removes `user` from the attachment of `hostPath`, removing the layer
+// from the utility VM if it was the last user.
+func (l *uvmLayers) release(ctx context.Context, hostPath, user string) error {
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	a, ok := l.attachments[hostPath]
+	if !ok {
+		return fmt.Errorf("layer %s is not attached to utility VM %s", hostPath, l.id)
+	}
+	i := 0
+	for i < len(a.Users) && a.Users[i] != user {
+		i++
+	}
+	if i == len(a.Users) {
+		return fmt.Errorf("layer %s is not used by %s in utility VM %s", hostPath, user, l.id)
+	}
+	a.Users = append(a.Users[:i], a.Users[i+1:]...)
+	log.G(ctx).WithFields(logrus.Fields{
+		logfields.UVMID: l.id,
+		"hostPath":      hostPath,
+		"device":        a.Device,
+		"users":         len(a.Users),
+	}).Debug("hcsshim::release layer")
+	if len(a.Users) > 0 {
+		return nil
+	}
+	// The attachment is forgotten even if the removal fails so that a later
+	// mount attaches the layer again rather than relying on a device in an
+	// unknown state.
+	delete(l.attachments, hostPath)
+	return l.detach(ctx, a)
+}
+
+// attachLayer attaches the read-only layer at `hostPath` to `vm`. WCOW layers
+// are added as VSMB shares. LCOW layers are added to VPMem, or to SCSI if they
+// are too large or VPMem is full.
+func attachLayer(ctx context.Context, vm *uvm.UtilityVM, hostPath string) (LayerAttachment, error) {
+	if vm.OS() == "windows" {
+		options := &hcsschema.VirtualSmbShareOptions{
+			ReadOnly:            true,
+			PseudoOplocks:       true,
+			TakeBackupPrivilege: true,
+			CacheIo:             true,
+			ShareRead:           true,
+		}
+		if err := vm.AddVSMB(ctx, hostPath, "", options); err != nil {
+			return LayerAttachment{}, err
+		}
+		uvmPath, err := vm.GetVSMBUvmPath(ctx, hostPath)
+		if err != nil {
+			if err := vm.RemoveVSMB(ctx, hostPath); err != nil {
+				log.G(ctx).WithError(err).Warn("Possibly leaked vsmbshare on error removal path")
+			}
+			return LayerAttachment{}, err
+		}
+		return LayerAttachment{UVMPath: uvmPath, Device: PlannedLayerVSMB}, nil
+	}
+
+	fi, err := os.Stat(hostPath)
+	if err != nil {
+		return LayerAttachment{}, err
+	}
+	if vm.ExceededVPMem(fi.Size()) {
+		// Too big for PMEM. Add on SCSI instead (at /tmp/S<C>/<L>).
+		controller, lun, err := vm.AddSCSILayer(ctx, hostPath)
+		if err != nil {
+			return LayerAttachment{}, err
+		}
+		return LayerAttachment{UVMPath: fmt.Sprintf("/tmp/S%d/%d", controller, lun), Device: PlannedLayerSCSI}, nil
+	}
+	_, uvmPath, err := vm.AddVPMEM(ctx, hostPath, true) // UVM path is calculated. Will be /tmp/pN
+	if err != nil {
+		return LayerAttachment{}, err
+	}
+	return LayerAttachment{UVMPath: uvmPath, Device: PlannedLayerVPMem}, nil
+}
+
+// detachLayer removes the layer attachment `a` from `vm`.
+func detachLayer(ctx context.Context, vm *uvm.UtilityVM, a *LayerAttachment) error {
+	switch a.Device {
+	case PlannedLayerVSMB:
+		return vm.RemoveVSMB(ctx, a.HostPath)
+	case PlannedLayerVPMem:
+		return vm.RemoveVPMEM(ctx, a.HostPath)
+	case PlannedLayerSCSI:
+		return vm.RemoveSCSI(ctx, a.HostPath)
+	default:
+		return fmt.Errorf("unknown layer device %q", a.Device)
+	}
+}
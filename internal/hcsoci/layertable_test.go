@@ -0,0 +1,127 @@
+// +build windows
+
+package hcsoci
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/Microsoft/hcsshim/internal/uvm"
+)
+
+type fakeLayerDevices struct {
+	attached map[string]int
+	failNext bool
+}
+
+func newTestUVMLayers(id string, d *fakeLayerDevices) *uvmLayers {
+	return &uvmLayers{
+		id: id,
+		attach: func(ctx context.Context, hostPath string) (LayerAttachment, error) {
+			if d.failNext {
+				d.failNext = false
+				return LayerAttachment{}, errors.New("attach failed")
+			}
+			d.attached[hostPath]++
+			return LayerAttachment{UVMPath: fmt.Sprintf("/tmp/p%d", len(d.attached)-1), Device: PlannedLayerVPMem}, nil
+		},
+		detach: func(ctx context.Context, a *LayerAttachment) error {
+			d.attached[a.HostPath]--
+			if d.attached[a.HostPath] == 0 {
+				delete(d.attached, a.HostPath)
+			}
+			return nil
+		},
+		attachments: make(map[string]*LayerAttachment),
+	}
+}
+
+func TestLayerAttachmentShared(t *testing.T) {
+	ctx := context.Background()
+	d := &fakeLayerDevices{attached: make(map[string]int)}
+	l := newTestUVMLayers("vm", d)
+
+	a1, err := l.acquire(ctx, "base", "/run/gcs/c/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	a2, err := l.acquire(ctx, "base", "/run/gcs/c/2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.attached["base"] != 1 {
+		t.Fatalf("expected the layer to be attached once, got %d", d.attached["base"])
+	}
+	if a1.UVMPath != a2.UVMPath || len(a2.Users) != 2 {
+		t.Fatalf("expected a shared attachment, got %+v and %+v", a1, a2)
+	}
+	if _, err := l.acquire(ctx, "base", "/run/gcs/c/2"); err == nil {
+		t.Fatal("expected acquiring a layer twice for the same container to fail")
+	}
+
+	if err := l.release(ctx, "base", "/run/gcs/c/1"); err != nil {
+		t.Fatal(err)
+	}
+	if d.attached["base"] != 1 {
+		t.Fatal("layer removed while still in use")
+	}
+	if err := l.release(ctx, "base", "/run/gcs/c/1"); err == nil {
+		t.Fatal("expected releasing a layer twice for the same container to fail")
+	}
+	if err := l.release(ctx, "base", "/run/gcs/c/2"); err != nil {
+		t.Fatal(err)
+	}
+	if len(d.attached) != 0 || len(l.attachments) != 0 {
+		t.Fatalf("layer not reclaimed after the last user: %v", d.attached)
+	}
+	if err := l.release(ctx, "base", "/run/gcs/c/2"); err == nil {
+		t.Fatal("expected releasing a detached layer to fail")
+	}
+}
+
+func TestLayerAttachmentAttachFailure(t *testing.T) {
+	ctx := context.Background()
+	d := &fakeLayerDevices{attached: make(map[string]int), failNext: true}
+	l := newTestUVMLayers("vm", d)
+
+	if _, err := l.acquire(ctx, "base", "/run/gcs/c/1"); err == nil {
+		t.Fatal("expected attach failure")
+	}
+	if len(l.attachments) != 0 {
+		t.Fatal("failed attachment must not be recorded")
+	}
+	if _, err := l.acquire(ctx, "base", "/run/gcs/c/1"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLayerAttachmentsSnapshot(t *testing.T) {
+	ctx := context.Background()
+	vm1, vm2 := &uvm.UtilityVM{}, &uvm.UtilityVM{}
+	table := &layerTable{vms: map[*uvm.UtilityVM]*uvmLayers{
+		vm1: newTestUVMLayers("b", &fakeLayerDevices{attached: make(map[string]int)}),
+		vm2: newTestUVMLayers("a", &fakeLayerDevices{attached: make(map[string]int)}),
+	}}
+	for _, p := range []string{"2", "1"} {
+		if _, err := table.vms[vm1].acquire(ctx, p, "c1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := table.vms[vm2].acquire(ctx, "3", "c1"); err != nil {
+		t.Fatal(err)
+	}
+
+	s := table.snapshot()
+	if len(s) != 3 {
+		t.Fatalf("expected 3 attachments, got %+v", s)
+	}
+	if s[0].UtilityVM != "a" || s[1].HostPath != "1" || s[2].HostPath != "2" {
+		t.Fatalf("unexpected order %+v", s)
+	}
+	s[1].Users[0] = "changed"
+	if a, ok := table.find(vm1, "1"); !ok || a.Users[0] != "c1" {
+		t.Fatal("snapshot must not alias the table")
+	}
+}
@@ -1,3 +1,5 @@
+// +build windows
+
 package hcsoci
 
 import (
@@ -27,7 +29,15 @@ func createNetworkNamespace(ctx context.Context, coi *createOptionsInternal, res
 	}).Info("created network namespace for container")
 	resources.netNS = netID
 	resources.createdNetNS = true
+	// The namespace ID is only known once it is created so it cannot be
+	// recorded beforehand.
+	if err := resources.journal.allocate(journalEntry{Kind: journalNetworkNamespace, Key: netID}); err != nil {
+		return err
+	}
 	for _, endpointID := range coi.Spec.Windows.Network.EndpointList {
+		if err := resources.journal.allocate(journalEntry{Kind: journalNetworkEndpoint, Key: endpointID, NetNS: netID}); err != nil {
+			return err
+		}
 		err = hns.AddNamespaceEndpoint(netID, endpointID)
 		if err != nil {
 			return err
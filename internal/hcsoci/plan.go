@@ -0,0 +1,470 @@
+// +build windows
+
+package hcsoci
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Microsoft/hcsshim/internal/cow"
+	"github.com/Microsoft/hcsshim/internal/guestrequest"
+	"github.com/Microsoft/hcsshim/internal/hcs"
+	"github.com/Microsoft/hcsshim/internal/log"
+	hcsschema "github.com/Microsoft/hcsshim/internal/schema2"
+	"github.com/Microsoft/hcsshim/internal/uvm"
+	"github.com/Microsoft/hcsshim/internal/wclayer"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+// PlanStepType is the type of resource allocated by a PlanStep.
+type PlanStepType string
+
+const (
+	// PlanStepCreateNetworkNamespace creates a host network namespace and adds
+	// `Endpoints` to it.
+	PlanStepCreateNetworkNamespace PlanStepType = "CreateNetworkNamespace"
+	// PlanStepAddNetworkNamespace adds the container's network namespace and
+	// its endpoints to the utility VM.
+	PlanStepAddNetworkNamespace PlanStepType = "AddNetworkNamespace"
+	// PlanStepCreateScratch creates the WCOW scratch folder and sandbox.vhdx
+	// at `HostPath` if they do not exist.
+	PlanStepCreateScratch PlanStepType = "CreateScratch"
+	// PlanStepMountLayers mounts the container's read-only `Layers` and its
+	// scratch at `HostPath`, and combines them at `GuestPath`.
+	PlanStepMountLayers PlanStepType = "MountLayers"
+	// PlanStepSCSI attaches a virtual or physical disk to the utility VM.
+	PlanStepSCSI PlanStepType = "SCSI"
+	// PlanStepVSMB adds a VSMB share to a Windows utility VM.
+	PlanStepVSMB PlanStepType = "VSMB"
+	// PlanStepPlan9 adds a Plan9 share to a Linux utility VM.
+	PlanStepPlan9 PlanStepType = "Plan9"
+)
+
+// Layer device types reported in a PlannedLayer.
+const (
+	PlannedLayerHost  = "Host"
+	PlannedLayerVSMB  = "VSMB"
+	PlannedLayerVPMem = "VPMem"
+	PlannedLayerSCSI  = "SCSI"
+)
+
+// PlannedLayer is a read-only layer attached by a PlanStepMountLayers step.
+type PlannedLayer struct {
+	HostPath string
+	// Device is how the layer is attached. One of the PlannedLayer* constants.
+	Device string
+}
+
+// PlanStep is a single resource allocation performed when a Plan is
+// executed. Only the fields relevant to `Type` are set.
+type PlanStep struct {
+	Type PlanStepType
+
+	// HostPath is the host file, folder or disk backing the resource.
+	HostPath string `json:",omitempty"`
+	// GuestPath is where the resource is exposed in the utility VM. It is
+	// empty when the resource is not exposed at a fixed path.
+	GuestPath string `json:",omitempty"`
+	ReadOnly  bool   `json:",omitempty"`
+
+	// NetworkNamespace is the namespace added by PlanStepAddNetworkNamespace.
+	// It is empty if the namespace is created by an earlier step.
+	NetworkNamespace string `json:",omitempty"`
+	// Endpoints are the HNS endpoints added to the namespace.
+	Endpoints []string `json:",omitempty"`
+
+	// LayerFolders are the layer folders, including the scratch, mounted by
+	// PlanStepMountLayers and PlanStepCreateScratch.
+	LayerFolders []string `json:",omitempty"`
+	// Layers are the read-only layers mounted by PlanStepMountLayers.
+	Layers []PlannedLayer `json:",omitempty"`
+
+	// PhysicalDisk is set when a PlanStepSCSI attaches a host disk rather
+	// than a vhd/vhdx.
+	PhysicalDisk bool `json:",omitempty"`
+	// AutoManage is set when the vhd of a PlanStepSCSI is deleted when the
+	// container's resources are released.
+	AutoManage bool `json:",omitempty"`
+
+	// VSMBOptions are the share options of a PlanStepVSMB.
+	VSMBOptions *hcsschema.VirtualSmbShareOptions `json:",omitempty"`
+
+	// Restrict and AllowedNames restrict a PlanStepPlan9 share to a single
+	// file in `HostPath`.
+	Restrict     bool     `json:",omitempty"`
+	AllowedNames []string `json:",omitempty"`
+}
+
+// Plan is the set of resources and documents used to create a container. It is
+// produced by PlanContainer without modifying the host or the utility VM, and
+// applied by Execute.
+//
+// Values that are only known once the plan is executed, such as the guest
+// path of a VSMB share that is not yet attached, are shown in the plan's
+// documents as `<kind:key>` placeholders.
+type Plan struct {
+	ID              string
+	Owner           string
+	SchemaVersion   *hcsschema.Version
+	HostingSystemID string `json:",omitempty"`
+	// ContainerRootInUVM is the container's folder in the utility VM.
+	ContainerRootInUVM string `json:",omitempty"`
+	// NetworkNamespace is the network namespace used by the container. It is
+	// empty if the namespace is created when the plan is executed.
+	NetworkNamespace string `json:",omitempty"`
+	Steps            []PlanStep
+	// Spec is the container spec after host paths have been translated to
+	// utility VM paths.
+	Spec        *specs.Spec
+	HCSDocument interface{} `json:",omitempty"`
+	GCSDocument interface{} `json:",omitempty"`
+
+	coi      *createOptionsInternal
+	executed bool
+}
+
+// hostingSystem is the view of a utility VM used to plan a container and to
+// generate its documents. It is implemented by *uvm.UtilityVM and DryRunHost.
+type hostingSystem interface {
+	ID() string
+	OS() string
+	ProcessorCount() int32
+	ContainerCounter() uint64
+	ExceededVPMem(fileSize int64) bool
+	GetVSMBUvmPath(ctx context.Context, hostPath string) (string, error)
+	GetScsiUvmPath(ctx context.Context, hostPath string) (string, error)
+}
+
+// DryRunHost describes a utility VM that has not been created. It is used in
+// place of CreateOptions.HostingSystem to plan a hypervisor-isolated container
+// without a VM. Plans made against a DryRunHost cannot be executed.
+type DryRunHost struct {
+	id                string
+	os                string
+	processorCount    int32
+	vpmemMaxCount     uint32
+	vpmemMaxSizeBytes uint64
+	containerCounter  uint64
+}
+
+// NewDryRunHost returns a DryRunHost for the utility VM that would be created
+// from `opts`, which is either a *uvm.OptionsLCOW or a *uvm.OptionsWCOW.
+func NewDryRunHost(opts interface{}) (*DryRunHost, error) {
+	switch opts := opts.(type) {
+	case *uvm.OptionsLCOW:
+		return &DryRunHost{
+			id:                opts.ID,
+			os:                "linux",
+			processorCount:    opts.ProcessorCount,
+			vpmemMaxCount:     opts.VPMemDeviceCount,
+			vpmemMaxSizeBytes: opts.VPMemSizeBytes,
+		}, nil
+	case *uvm.OptionsWCOW:
+		return &DryRunHost{
+			id:             opts.ID,
+			os:             "windows",
+			processorCount: opts.ProcessorCount,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported utility VM options type %T", opts)
+	}
+}
+
+// ID returns the ID the utility VM would be created with.
+func (h *DryRunHost) ID() string {
+	return h.id
+}
+
+// OS returns the operating system of the utility VM.
+func (h *DryRunHost) OS() string {
+	return h.os
+}
+
+// ProcessorCount returns the number of processors of the utility VM.
+func (h *DryRunHost) ProcessorCount() int32 {
+	return h.processorCount
+}
+
+// ContainerCounter returns the next container number in the utility VM.
+func (h *DryRunHost) ContainerCounter() uint64 {
+	h.containerCounter++
+	return h.containerCounter
+}
+
+// ExceededVPMem returns true if a layer of `fileSize` bytes cannot be attached
+// to an empty utility VM over VPMem.
+func (h *DryRunHost) ExceededVPMem(fileSize int64) bool {
+	return uint64(fileSize) > h.vpmemMaxSizeBytes || h.vpmemMaxCount == 0
+}
+
+// GetVSMBUvmPath always returns uvm.ErrNotAttached.
+func (h *DryRunHost) GetVSMBUvmPath(ctx context.Context, hostPath string) (string, error) {
+	return "", uvm.ErrNotAttached
+}
+
+// GetScsiUvmPath always returns uvm.ErrNotAttached.
+func (h *DryRunHost) GetScsiUvmPath(ctx context.Context, hostPath string) (string, error) {
+	return "", uvm.ErrNotAttached
+}
+
+// deepCopySpec returns a deep copy of `s`.
+func deepCopySpec(s *specs.Spec) (*specs.Spec, error) {
+	j, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	spec := &specs.Spec{}
+	if err := json.Unmarshal(j, spec); err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+func (p *Plan) addStep(step PlanStep) {
+	p.Steps = append(p.Steps, step)
+}
+
+// findStep returns the first step of type `t` using `hostPath`, or nil.
+func (p *Plan) findStep(t PlanStepType, hostPath string) *PlanStep {
+	for i, s := range p.Steps {
+		if s.Type != t {
+			continue
+		}
+		if s.HostPath == hostPath {
+			return &p.Steps[i]
+		}
+		for _, l := range s.Layers {
+			if l.HostPath == hostPath {
+				return &p.Steps[i]
+			}
+		}
+	}
+	return nil
+}
+
+// vsmbGuestPath returns the guest path of the VSMB share for `hostPath`. While
+// planning, shares added by the plan are returned as placeholders.
+func (coi *createOptionsInternal) vsmbGuestPath(ctx context.Context, hostPath string) (string, error) {
+	uvmPath, err := coi.host.GetVSMBUvmPath(ctx, hostPath)
+	if err == uvm.ErrNotAttached && coi.planning != nil {
+		if coi.planning.findStep(PlanStepVSMB, hostPath) != nil || coi.planning.findStep(PlanStepMountLayers, hostPath) != nil {
+			return plannedValue("vsmb", hostPath), nil
+		}
+	}
+	return uvmPath, err
+}
+
+// scsiGuestPath returns the guest path of the SCSI disk for `hostPath`. While
+// planning, disks added by the plan are returned with their planned path.
+func (coi *createOptionsInternal) scsiGuestPath(ctx context.Context, hostPath string) (string, error) {
+	uvmPath, err := coi.host.GetScsiUvmPath(ctx, hostPath)
+	if err == uvm.ErrNotAttached && coi.planning != nil {
+		if s := coi.planning.findStep(PlanStepSCSI, hostPath); s != nil {
+			return s.GuestPath, nil
+		}
+	}
+	return uvmPath, err
+}
+
+// Execute applies the plan's steps in order and creates the container. If a
+// step fails, the resources allocated by the earlier steps are released unless
+// CreateOptions.DoNotReleaseResourcesOnFailure is set. As with
+// CreateContainer, the allocated resources are returned even on failure.
+//
+// A plan can only be executed once.
+func (p *Plan) Execute(ctx context.Context) (_ cow.Container, _ *Resources, err error) {
+	coi := p.coi
+	if coi.host != nil && coi.HostingSystem == nil {
+		return nil, nil, errors.New("a plan made against a dry-run host cannot be executed")
+	}
+	if p.executed {
+		return nil, nil, errors.New("plan has already been executed")
+	}
+	p.executed = true
+	coi.planning = nil
+
+	resources := &Resources{
+		id:                 coi.actualID,
+		containerRootInUVM: p.ContainerRootInUVM,
+		netNS:              p.NetworkNamespace,
+	}
+	if coi.JournalPath != "" {
+		if resources.journal, err = openJournal(coi.JournalPath); err != nil {
+			return nil, resources, err
+		}
+	}
+	defer func() {
+		if err != nil {
+			if !coi.DoNotReleaseResourcesOnFailure {
+				ReleaseResources(ctx, resources, coi.HostingSystem, true)
+			}
+		}
+	}()
+
+	log.G(ctx).Debug("hcsshim::CreateContainer allocating resources")
+	for i := range p.Steps {
+		if err := p.applyStep(ctx, &p.Steps[i], resources); err != nil {
+			log.G(ctx).WithError(err).WithField("step", p.Steps[i].Type).Debug("failed to apply plan step")
+			return nil, resources, err
+		}
+	}
+	coi.actualNetworkNamespace = resources.netNS
+
+	log.G(ctx).Debug("hcsshim::CreateContainer creating container document")
+	p.HCSDocument, p.GCSDocument, err = createDocuments(ctx, coi, resources.containerRootInUVM)
+	if err != nil {
+		return nil, resources, err
+	}
+
+	log.G(ctx).Debug("hcsshim::CreateContainer creating compute system")
+	if p.GCSDocument != nil {
+		c, err := coi.HostingSystem.CreateContainer(ctx, coi.actualID, p.GCSDocument)
+		if err != nil {
+			return nil, resources, err
+		}
+		return c, resources, nil
+	}
+
+	system, err := hcs.CreateComputeSystem(ctx, coi.actualID, p.HCSDocument)
+	if err != nil {
+		return nil, resources, err
+	}
+	return system, resources, nil
+}
+
+// applyStep performs the allocation described by `step` and records it in
+// `resources` so that it is undone by ReleaseResources.
+func (p *Plan) applyStep(ctx context.Context, step *PlanStep, resources *Resources) error {
+	coi := p.coi
+	vm := coi.HostingSystem
+	switch step.Type {
+	case PlanStepCreateNetworkNamespace:
+		return createNetworkNamespace(ctx, coi, resources)
+
+	case PlanStepAddNetworkNamespace:
+		endpoints, err := GetNamespaceEndpoints(ctx, resources.netNS)
+		if err != nil {
+			return err
+		}
+		if err := resources.journal.allocate(journalEntry{Kind: journalVMNetNS, Key: resources.netNS, UtilityVM: vm.ID(), SystemID: vm.SystemID()}); err != nil {
+			return err
+		}
+		if err := vm.AddNetNS(ctx, resources.netNS); err != nil {
+			return err
+		}
+		if err := vm.AddEndpointsToNS(ctx, resources.netNS, endpoints); err != nil {
+			// Best effort clean up the NS
+			vm.RemoveNetNS(ctx, resources.netNS)
+			return err
+		}
+		resources.addedNetNSToVM = true
+		deviceOwnership.add(vm, resources.netNS, resources.id)
+		return nil
+
+	case PlanStepCreateScratch:
+		return createScratch(ctx, step.HostPath, step.LayerFolders[:len(step.LayerFolders)-1])
+
+	case PlanStepMountLayers:
+		entry := journalEntry{
+			Kind:      journalLayers,
+			Key:       step.LayerFolders[len(step.LayerFolders)-1],
+			GuestRoot: resources.containerRootInUVM,
+			Layers:    step.LayerFolders,
+		}
+		if vm != nil {
+			entry.UtilityVM = vm.ID()
+			entry.SystemID = vm.SystemID()
+		}
+		if err := resources.journal.allocate(entry); err != nil {
+			return err
+		}
+		mcl, err := MountContainerLayers(ctx, step.LayerFolders, resources.containerRootInUVM, vm)
+		if err != nil {
+			return fmt.Errorf("failed to mount container storage: %s", err)
+		}
+		if vm == nil {
+			coi.Spec.Root.Path = mcl.(string) // Argon v1 or v2
+		} else {
+			coi.Spec.Root.Path = mcl.(guestrequest.CombinedLayers).ContainerRootPath // v2 Xenon
+			deviceOwnership.add(vm, filepath.Join(step.HostPath, "sandbox.vhdx"), resources.id)
+			deviceOwnership.addRoot(vm, resources.containerRootInUVM, resources.id)
+		}
+		step.GuestPath = coi.Spec.Root.Path
+		resources.layers = step.LayerFolders
+		return nil
+
+	case PlanStepSCSI:
+		if err := resources.journal.allocate(journalEntry{Kind: journalSCSI, Key: step.HostPath, UtilityVM: vm.ID(), SystemID: vm.SystemID(), AutoManage: step.AutoManage}); err != nil {
+			return err
+		}
+		l := log.G(ctx).WithField("hostPath", step.HostPath)
+		if step.PhysicalDisk {
+			l.Debug("hcsshim::CreateContainer Hot-adding SCSI physical disk for OCI mount")
+			if _, _, err := vm.AddSCSIPhysicalDisk(ctx, step.HostPath, step.GuestPath, step.ReadOnly); err != nil {
+				return fmt.Errorf("adding SCSI physical disk mount %s: %s", step.HostPath, err)
+			}
+		} else {
+			l.Debug("hcsshim::CreateContainer Hot-adding SCSI virtual disk for OCI mount")
+			if _, _, err := vm.AddSCSI(ctx, step.HostPath, step.GuestPath, step.ReadOnly); err != nil {
+				return fmt.Errorf("adding SCSI virtual disk mount %s: %s", step.HostPath, err)
+			}
+		}
+		resources.scsiMounts = append(resources.scsiMounts, scsiMount{path: step.HostPath, autoManage: step.AutoManage})
+		deviceOwnership.add(vm, step.HostPath, resources.id)
+		return nil
+
+	case PlanStepVSMB:
+		log.G(ctx).WithField("hostPath", step.HostPath).Debug("hcsshim::CreateContainer Hot-adding VSMB share for OCI mount")
+		if err := resources.journal.allocate(journalEntry{Kind: journalVSMB, Key: step.HostPath, UtilityVM: vm.ID(), SystemID: vm.SystemID()}); err != nil {
+			return err
+		}
+		options := *step.VSMBOptions
+		if err := vm.AddVSMB(ctx, step.HostPath, "", &options); err != nil {
+			return fmt.Errorf("failed to add VSMB share to utility VM for mount %s: %s", step.HostPath, err)
+		}
+		resources.vsmbMounts = append(resources.vsmbMounts, step.HostPath)
+		deviceOwnership.add(vm, step.HostPath, resources.id)
+		return nil
+
+	case PlanStepPlan9:
+		log.G(ctx).WithField("hostPath", step.HostPath).Debug("hcsshim::CreateContainer Hot-adding Plan9 for OCI mount")
+		if err := resources.journal.allocate(journalEntry{Kind: journalPlan9, Key: step.HostPath, UtilityVM: vm.ID(), SystemID: vm.SystemID()}); err != nil {
+			return err
+		}
+		share, err := vm.AddPlan9(ctx, step.HostPath, step.GuestPath, step.ReadOnly, step.Restrict, step.AllowedNames)
+		if err != nil {
+			return fmt.Errorf("adding plan9 mount %s: %s", step.HostPath, err)
+		}
+		resources.plan9Mounts = append(resources.plan9Mounts, plan9Mount{share: share, hostPath: step.HostPath})
+		deviceOwnership.add(vm, step.HostPath, resources.id)
+		return nil
+	}
+	return fmt.Errorf("unknown plan step type %q", step.Type)
+}
+
+// createScratch creates the scratch folder `scratchFolder` and its
+// sandbox.vhdx on top of `parentLayers` if they do not exist.
+func createScratch(ctx context.Context, scratchFolder string, parentLayers []string) error {
+	// TODO: Remove this code for auto-creation. Make the caller responsible.
+	// Create the directory for the RW scratch layer if it doesn't exist
+	if _, err := os.Stat(scratchFolder); os.IsNotExist(err) {
+		log.G(ctx).WithField("scratchFolder", scratchFolder).Debug("hcsshim::allocateWindowsResources container scratch folder does not exist so creating")
+		if err := os.MkdirAll(scratchFolder, 0777); err != nil {
+			return fmt.Errorf("failed to auto-create container scratch folder %s: %s", scratchFolder, err)
+		}
+	}
+
+	// Create sandbox.vhdx if it doesn't exist in the scratch folder. It's called sandbox.vhdx
+	// rather than scratch.vhdx as in the v1 schema, it's hard-coded in HCS.
+	if _, err := os.Stat(filepath.Join(scratchFolder, "sandbox.vhdx")); os.IsNotExist(err) {
+		log.G(ctx).WithField("scratchFolder", scratchFolder).Debug("hcsshim::allocateWindowsResources container sandbox.vhdx does not exist so creating")
+		if err := wclayer.CreateScratchLayer(scratchFolder, parentLayers); err != nil {
+			return fmt.Errorf("failed to CreateSandboxLayer %s", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+// +build windows
+
+package hcsoci
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Microsoft/hcsshim/internal/uvm"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+func newTestLCOWDryRunHost(t *testing.T) *DryRunHost {
+	opts := uvm.NewDefaultOptionsLCOW("test@vm", "")
+	opts.VPMemSizeBytes = 4096
+	h, err := NewDryRunHost(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return h
+}
+
+func writeTestFile(t *testing.T, path string, size int) {
+	if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path, make([]byte, size), 0666); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPlanContainerLCOW(t *testing.T) {
+	dir, err := ioutil.TempDir("", "plan")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	small := filepath.Join(dir, "small")
+	large := filepath.Join(dir, "large")
+	scratch := filepath.Join(dir, "scratch")
+	writeTestFile(t, filepath.Join(small, "layer.vhd"), 1024)
+	writeTestFile(t, filepath.Join(large, "layer.vhd"), 8192)
+	bindFile := filepath.Join(dir, "bind", "file.txt")
+	writeTestFile(t, bindFile, 1)
+
+	spec := &specs.Spec{
+		Linux:   &specs.Linux{},
+		Windows: &specs.Windows{LayerFolders: []string{small, large, scratch}},
+		Mounts: []specs.Mount{
+			{Type: "bind", Source: bindFile, Destination: "/file.txt", Options: []string{"ro"}},
+			{Type: "virtual-disk", Source: `C:\disk.vhdx`, Destination: "/disk"},
+		},
+	}
+	plan, err := PlanContainer(context.Background(), &CreateOptions{
+		ID:         "test",
+		Spec:       spec,
+		DryRunHost: newTestLCOWDryRunHost(t),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if plan.ContainerRootInUVM != "/run/gcs/c/1" {
+		t.Fatalf("unexpected container root %q", plan.ContainerRootInUVM)
+	}
+	if len(plan.Steps) != 3 {
+		t.Fatalf("expected 3 steps, got %+v", plan.Steps)
+	}
+	layers := plan.Steps[0]
+	if layers.Type != PlanStepMountLayers || layers.GuestPath != "/run/gcs/c/1/rootfs" {
+		t.Fatalf("unexpected layers step %+v", layers)
+	}
+	if layers.Layers[0].Device != PlannedLayerVPMem || layers.Layers[1].Device != PlannedLayerSCSI {
+		t.Fatalf("unexpected layer devices %+v", layers.Layers)
+	}
+	share := plan.Steps[1]
+	if share.Type != PlanStepPlan9 || share.GuestPath != "/run/gcs/c/1/m0" || !share.Restrict || !share.ReadOnly {
+		t.Fatalf("unexpected plan9 step %+v", share)
+	}
+	disk := plan.Steps[2]
+	if disk.Type != PlanStepSCSI || disk.GuestPath != "/run/gcs/c/1/m1" {
+		t.Fatalf("unexpected SCSI step %+v", disk)
+	}
+
+	if plan.Spec.Root.Path != "/run/gcs/c/1/rootfs" ||
+		plan.Spec.Mounts[0].Source != "/run/gcs/c/1/m0/file.txt" ||
+		plan.Spec.Mounts[1].Type != "none" {
+		t.Fatalf("spec not translated: %+v", plan.Spec)
+	}
+	if spec.Root != nil || spec.Mounts[0].Source != bindFile {
+		t.Fatal("planning must not modify the caller's spec")
+	}
+	if plan.GCSDocument == nil || plan.HCSDocument != nil {
+		t.Fatal("expected a GCS document only")
+	}
+
+	if _, _, err := plan.Execute(context.Background()); err == nil {
+		t.Fatal("expected executing a dry-run plan to fail")
+	}
+}
+
+func TestPlanContainerLCOWRequiresStorage(t *testing.T) {
+	_, err := PlanContainer(context.Background(), &CreateOptions{
+		Spec:       &specs.Spec{Linux: &specs.Linux{}},
+		DryRunHost: newTestLCOWDryRunHost(t),
+	})
+	if err == nil {
+		t.Fatal("expected an error without layers or a root path")
+	}
+}
+
+func TestPlanContainerLCOWUVMPathMount(t *testing.T) {
+	dir, err := ioutil.TempDir("", "plan")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	layer := filepath.Join(dir, "layer")
+	writeTestFile(t, filepath.Join(layer, "layer.vhd"), 1024)
+	spec := &specs.Spec{
+		Linux:   &specs.Linux{},
+		Windows: &specs.Windows{LayerFolders: []string{layer, filepath.Join(dir, "scratch")}},
+		Mounts: []specs.Mount{
+			{Type: "bind", Source: UVMPathPrefix + "/run/gcs/pod/shares/data", Destination: "/data"},
+		},
+	}
+	plan, err := PlanContainer(context.Background(), &CreateOptions{
+		ID:         "test",
+		Spec:       spec,
+		DryRunHost: newTestLCOWDryRunHost(t),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(plan.Steps) != 1 || plan.Steps[0].Type != PlanStepMountLayers {
+		t.Fatalf("expected only the layers step, got %+v", plan.Steps)
+	}
+	if plan.Spec.Mounts[0].Source != "/run/gcs/pod/shares/data" {
+		t.Fatalf("spec not translated: %+v", plan.Spec.Mounts)
+	}
+}
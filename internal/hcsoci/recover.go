@@ -0,0 +1,112 @@
+// +build windows
+
+package hcsoci
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/Microsoft/hcsshim/internal/log"
+	"github.com/Microsoft/hcsshim/internal/logfields"
+	"github.com/Microsoft/hcsshim/internal/uvm"
+	"github.com/sirupsen/logrus"
+)
+
+// RecoverResources rebuilds the resources of the container `id` from the
+// allocations outstanding in the journal at `journalPath`, and reopens the
+// journal to record their release. It is used to take over a running
+// container after the process that created it exits. `vm` is the reopened
+// utility VM of a hypervisor-isolated container, and nil otherwise.
+//
+// The read-only layers of the container are added to the layer table, shared
+// with the other containers recovered in `vm`.
+func RecoverResources(ctx context.Context, id, journalPath string, vm *uvm.UtilityVM) (_ *Resources, err error) {
+	outstanding, err := readJournal(journalPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read resource journal: %s", err)
+	}
+
+	r := &Resources{id: id}
+	for i := range outstanding {
+		e := &outstanding[i]
+		if e.UtilityVM != "" && (vm == nil || vm.ID() != e.UtilityVM) {
+			return nil, fmt.Errorf("%s %s is attached to utility VM %s, which was not reopened", e.Kind, e.Key, e.UtilityVM)
+		}
+		switch e.Kind {
+		case journalNetworkNamespace:
+			r.netNS = e.Key
+			r.createdNetNS = true
+		case journalNetworkEndpoint:
+			r.networkEndpoints = append(r.networkEndpoints, e.Key)
+		case journalVMNetNS:
+			r.netNS = e.Key
+			r.addedNetNSToVM = true
+			deviceOwnership.add(vm, e.Key, id)
+		case journalLayers:
+			r.layers = e.Layers
+			r.containerRootInUVM = e.GuestRoot
+			if vm != nil {
+				if err := adoptLayers(ctx, vm, e.Layers, e.GuestRoot); err != nil {
+					return nil, err
+				}
+				deviceOwnership.add(vm, filepath.Join(e.Key, "sandbox.vhdx"), id)
+				deviceOwnership.addRoot(vm, e.GuestRoot, id)
+			}
+		case journalSCSI:
+			r.scsiMounts = append(r.scsiMounts, scsiMount{path: e.Key, autoManage: e.AutoManage})
+			deviceOwnership.add(vm, e.Key, id)
+		case journalVSMB:
+			r.vsmbMounts = append(r.vsmbMounts, e.Key)
+			deviceOwnership.add(vm, e.Key, id)
+		case journalPlan9:
+			share, err := vm.FindPlan9(e.Key)
+			if err != nil {
+				return nil, fmt.Errorf("failed to find plan9 share %s: %s", e.Key, err)
+			}
+			r.plan9Mounts = append(r.plan9Mounts, plan9Mount{share: share, hostPath: e.Key})
+			deviceOwnership.add(vm, e.Key, id)
+		default:
+			return nil, fmt.Errorf("unknown resource kind %q", e.Kind)
+		}
+	}
+
+	r.journal, err = openJournal(journalPath)
+	if err != nil {
+		return nil, err
+	}
+	log.G(ctx).WithFields(logrus.Fields{
+		logfields.ContainerID: id,
+		"resources":           len(outstanding),
+	}).Debug("hcsshim::RecoverResources")
+	return r, nil
+}
+
+// adoptLayers adds the read-only layers of `layerFolders`, attached to `vm`
+// for the container at `guestRoot`, to the layer table.
+func adoptLayers(ctx context.Context, vm *uvm.UtilityVM, layerFolders []string, guestRoot string) error {
+	if len(layerFolders) == 0 {
+		return nil
+	}
+	// The kinds of the devices of the utility VM match the PlannedLayer*
+	// constants.
+	devices := make(map[string]uvm.Device)
+	for _, d := range vm.Inventory().Devices {
+		devices[d.HostPath] = d
+	}
+	vmLayers := layerAttachments.forUVM(vm)
+	for _, layerPath := range layerFolders[:len(layerFolders)-1] {
+		hostPath := layerPath
+		if vm.OS() != "windows" {
+			hostPath = filepath.Join(layerPath, "layer.vhd")
+		}
+		d, ok := devices[hostPath]
+		if !ok {
+			return fmt.Errorf("layer %s is not attached to utility VM %s", hostPath, vm.ID())
+		}
+		if err := vmLayers.adopt(ctx, hostPath, d.UVMPath, d.Kind, guestRoot); err != nil {
+			return err
+		}
+	}
+	return nil
+}
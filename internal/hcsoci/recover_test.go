@@ -0,0 +1,67 @@
+// +build windows
+
+package hcsoci
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRecoverResourcesHost(t *testing.T) {
+	ctx := context.Background()
+	j, cleanup := newTestJournal(t)
+	defer cleanup()
+
+	entries := []journalEntry{
+		{Kind: journalNetworkNamespace, Key: "ns"},
+		{Kind: journalNetworkEndpoint, Key: "ep1", NetNS: "ns"},
+		{Kind: journalNetworkEndpoint, Key: "ep2", NetNS: "ns"},
+		{Kind: journalLayers, Key: `C:\scratch`, Layers: []string{`C:\base`, `C:\scratch`}},
+	}
+	for _, e := range entries {
+		if err := j.allocate(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	j.release(ctx, journalEntry{Kind: journalNetworkEndpoint, Key: "ep2", NetNS: "ns"})
+	j.f.Close()
+	j.f = nil
+
+	r, err := RecoverResources(ctx, "c", j.path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.journal.remove()
+	if r.netNS != "ns" || !r.createdNetNS || len(r.networkEndpoints) != 1 || r.networkEndpoints[0] != "ep1" {
+		t.Fatalf("unexpected network resources %+v", r)
+	}
+	if len(r.layers) != 2 || r.layers[1] != `C:\scratch` {
+		t.Fatalf("unexpected layers %v", r.layers)
+	}
+
+	// Releases are recorded in the reopened journal.
+	r.journal.release(ctx, journalEntry{Kind: journalNetworkEndpoint, Key: "ep1", NetNS: "ns"})
+	outstanding, err := readJournal(j.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(outstanding) != 2 {
+		t.Fatalf("expected 2 outstanding allocations, got %+v", outstanding)
+	}
+}
+
+func TestRecoverResourcesMissingUVM(t *testing.T) {
+	ctx := context.Background()
+	j, cleanup := newTestJournal(t)
+	defer cleanup()
+
+	if err := j.allocate(journalEntry{Kind: journalVSMB, Key: `C:\share`, UtilityVM: "vm"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := RecoverResources(ctx, "c", j.path, nil); err == nil {
+		t.Fatal("expected recovering a utility VM attachment without the utility VM to fail")
+	}
+	if _, err := RecoverResources(ctx, "c", j.path+".missing", nil); err == nil {
+		t.Fatal("expected recovering a missing journal to fail")
+	}
+}
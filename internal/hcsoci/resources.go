@@ -1,8 +1,11 @@
+// +build windows
+
 package hcsoci
 
 import (
 	"context"
 	"os"
+	"path/filepath"
 
 	"github.com/Microsoft/hcsshim/internal/hns"
 	"github.com/Microsoft/hcsshim/internal/log"
@@ -20,6 +23,9 @@ func (r *Resources) NetNS() string {
 // it in a call to ReleaseResource to ensure everything is cleaned up when a
 // container exits.
 type Resources struct {
+	// id is the ID of the container the resources were allocated for.
+	id string
+
 	// containerRootInUVM is the base path in a utility VM where elements relating
 	// to a container are exposed. For example, the mounted filesystem; the runtime
 	// spec (in the case of LCOW); overlay and scratch (in the case of LCOW).
@@ -39,7 +45,7 @@ type Resources struct {
 
 	// plan9Mounts is an array of all the host paths which have been added to
 	// an LCOW utility VM
-	plan9Mounts []*uvm.Plan9Share
+	plan9Mounts []plan9Mount
 
 	// netNS is the network namespace
 	netNS string
@@ -56,6 +62,16 @@ type Resources struct {
 	// scsiMounts is an array of the vhd's mounted into a utility VM to support
 	// scsi device passthrough.
 	scsiMounts []scsiMount
+
+	// journal records the allocations and releases of the resources above.
+	// nil if CreateOptions.JournalPath was not set.
+	journal *journal
+}
+
+type plan9Mount struct {
+	share *uvm.Plan9Share
+	// hostPath is the host path of the share.
+	hostPath string
 }
 
 type scsiMount struct {
@@ -73,6 +89,8 @@ func ReleaseResources(ctx context.Context, r *Resources, vm *uvm.UtilityVM, all
 			log.G(ctx).Warn(err)
 		}
 		r.addedNetNSToVM = false
+		deviceOwnership.remove(vm, r.netNS, r.id)
+		r.journal.release(ctx, journalEntry{Kind: journalVMNetNS, Key: r.netNS})
 	}
 
 	if r.createdNetNS {
@@ -89,6 +107,7 @@ func ReleaseResources(ctx context.Context, r *Resources, vm *uvm.UtilityVM, all
 				}).Warn("removing endpoint from namespace: does not exist")
 			}
 			r.networkEndpoints = r.networkEndpoints[:len(r.networkEndpoints)-1]
+			r.journal.release(ctx, journalEntry{Kind: journalNetworkEndpoint, Key: endpoint, NetNS: r.netNS})
 		}
 		r.networkEndpoints = nil
 		err := hns.RemoveNamespace(r.netNS)
@@ -96,6 +115,7 @@ func ReleaseResources(ctx context.Context, r *Resources, vm *uvm.UtilityVM, all
 			return err
 		}
 		r.createdNetNS = false
+		r.journal.release(ctx, journalEntry{Kind: journalNetworkNamespace, Key: r.netNS})
 	}
 
 	if len(r.layers) != 0 {
@@ -107,6 +127,10 @@ func ReleaseResources(ctx context.Context, r *Resources, vm *uvm.UtilityVM, all
 		if err != nil {
 			return err
 		}
+		if vm != nil {
+			deviceOwnership.remove(vm, filepath.Join(r.layers[len(r.layers)-1], "sandbox.vhdx"), r.id)
+		}
+		r.journal.release(ctx, journalEntry{Kind: journalLayers, Key: r.layers[len(r.layers)-1]})
 		r.layers = nil
 	}
 
@@ -116,18 +140,23 @@ func ReleaseResources(ctx context.Context, r *Resources, vm *uvm.UtilityVM, all
 			if err := vm.RemoveVSMB(ctx, mount); err != nil {
 				return err
 			}
+			deviceOwnership.remove(vm, mount, r.id)
+			r.journal.release(ctx, journalEntry{Kind: journalVSMB, Key: mount})
 			r.vsmbMounts = r.vsmbMounts[:len(r.vsmbMounts)-1]
 		}
 
 		for len(r.plan9Mounts) != 0 {
 			mount := r.plan9Mounts[len(r.plan9Mounts)-1]
-			if err := vm.RemovePlan9(ctx, mount); err != nil {
+			if err := vm.RemovePlan9(ctx, mount.share); err != nil {
 				return err
 			}
+			deviceOwnership.remove(vm, mount.hostPath, r.id)
+			r.journal.release(ctx, journalEntry{Kind: journalPlan9, Key: mount.hostPath})
 			r.plan9Mounts = r.plan9Mounts[:len(r.plan9Mounts)-1]
 		}
 
-		for _, sm := range r.scsiMounts {
+		for len(r.scsiMounts) != 0 {
+			sm := r.scsiMounts[len(r.scsiMounts)-1]
 			if err := vm.RemoveSCSI(ctx, sm.path); err != nil {
 				return err
 			}
@@ -136,8 +165,16 @@ func ReleaseResources(ctx context.Context, r *Resources, vm *uvm.UtilityVM, all
 					log.G(ctx).WithError(err).Warnf("failed to remove automanage-virtual-disk at: %q", sm.path)
 				}
 			}
+			deviceOwnership.remove(vm, sm.path, r.id)
+			r.journal.release(ctx, journalEntry{Kind: journalSCSI, Key: sm.path})
+			r.scsiMounts = r.scsiMounts[:len(r.scsiMounts)-1]
+		}
+
+		// Everything is released so there is nothing left to recover.
+		if err := r.journal.remove(); err != nil {
+			log.G(ctx).WithError(err).Warn("failed to remove resource journal")
 		}
-		r.scsiMounts = nil
+		r.journal = nil
 	}
 
 	return nil
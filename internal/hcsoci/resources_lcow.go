@@ -14,7 +14,6 @@ import (
 	"strconv"
 	"strings"
 
-	"github.com/Microsoft/hcsshim/internal/guestrequest"
 	"github.com/Microsoft/hcsshim/internal/log"
 	specs "github.com/opencontainers/runtime-spec/specs-go"
 )
@@ -22,33 +21,32 @@ import (
 const rootfsPath = "rootfs"
 const mountPathPrefix = "m"
 
-func allocateLinuxResources(ctx context.Context, coi *createOptionsInternal, resources *Resources) error {
+// planLinuxResources adds the steps to `plan` that allocate the layers and
+// mounts of an LCOW container, and translates the host paths in the plan's
+// spec to their utility VM paths.
+func planLinuxResources(ctx context.Context, coi *createOptionsInternal, plan *Plan) error {
 	if coi.Spec.Root == nil {
 		coi.Spec.Root = &specs.Root{}
 	}
 	if coi.Spec.Windows != nil && len(coi.Spec.Windows.LayerFolders) > 0 {
-		log.G(ctx).Debug("hcsshim::allocateLinuxResources mounting storage")
-		mcl, err := MountContainerLayers(ctx, coi.Spec.Windows.LayerFolders, resources.containerRootInUVM, coi.HostingSystem)
+		log.G(ctx).Debug("hcsshim::planLinuxResources mounting storage")
+		step, err := planContainerLayers(coi, coi.Spec.Windows.LayerFolders, plan.ContainerRootInUVM)
 		if err != nil {
-			return fmt.Errorf("failed to mount container storage: %s", err)
+			return fmt.Errorf("failed to plan container storage: %s", err)
 		}
-		if coi.HostingSystem == nil {
-			coi.Spec.Root.Path = mcl.(string) // Argon v1 or v2
-		} else {
-			coi.Spec.Root.Path = mcl.(guestrequest.CombinedLayers).ContainerRootPath // v2 Xenon LCOW
-		}
-		resources.layers = coi.Spec.Windows.LayerFolders
+		plan.addStep(step)
+		coi.Spec.Root.Path = step.GuestPath
 	} else if coi.Spec.Root.Path != "" {
 		// This is the "Plan 9" root filesystem.
 		// TODO: We need a test for this. Ask @jstarks how you can even lay this out on Windows.
-		hostPath := coi.Spec.Root.Path
-		uvmPathForContainersFileSystem := path.Join(resources.containerRootInUVM, rootfsPath)
-		share, err := coi.HostingSystem.AddPlan9(ctx, hostPath, uvmPathForContainersFileSystem, coi.Spec.Root.Readonly, false, nil)
-		if err != nil {
-			return fmt.Errorf("adding plan9 root: %s", err)
-		}
+		uvmPathForContainersFileSystem := path.Join(plan.ContainerRootInUVM, rootfsPath)
+		plan.addStep(PlanStep{
+			Type:      PlanStepPlan9,
+			HostPath:  coi.Spec.Root.Path,
+			GuestPath: uvmPathForContainersFileSystem,
+			ReadOnly:  coi.Spec.Root.Readonly,
+		})
 		coi.Spec.Root.Path = uvmPathForContainersFileSystem
-		resources.plan9Mounts = append(resources.plan9Mounts, share)
 	} else {
 		return errors.New("must provide either Windows.LayerFolders or Root.Path")
 	}
@@ -67,9 +65,9 @@ func allocateLinuxResources(ctx context.Context, coi *createOptionsInternal, res
 			return fmt.Errorf("invalid OCI spec - a mount must have both source and a destination: %+v", mount)
 		}
 
-		if coi.HostingSystem != nil {
+		if coi.host != nil {
 			hostPath := mount.Source
-			uvmPathForShare := path.Join(resources.containerRootInUVM, mountPathPrefix+strconv.Itoa(i))
+			uvmPathForShare := path.Join(plan.ContainerRootInUVM, mountPathPrefix+strconv.Itoa(i))
 			uvmPathForFile := uvmPathForShare
 
 			readOnly := false
@@ -79,27 +77,22 @@ func allocateLinuxResources(ctx context.Context, coi *createOptionsInternal, res
 					break
 				}
 			}
-			l := log.G(ctx).WithField("mount", fmt.Sprintf("%+v", mount))
-			if mount.Type == "physical-disk" {
-				l.Debug("hcsshim::allocateLinuxResources Hot-adding SCSI physical disk for OCI mount")
-				_, _, err := coi.HostingSystem.AddSCSIPhysicalDisk(ctx, hostPath, uvmPathForShare, readOnly)
-				if err != nil {
-					return fmt.Errorf("adding SCSI physical disk mount %+v: %s", mount, err)
-				}
-				resources.scsiMounts = append(resources.scsiMounts, scsiMount{path: hostPath})
-				coi.Spec.Mounts[i].Type = "none"
-			} else if mount.Type == "virtual-disk" || mount.Type == "automanage-virtual-disk" {
-				l.Debug("hcsshim::allocateLinuxResources Hot-adding SCSI virtual disk for OCI mount")
-				_, _, err := coi.HostingSystem.AddSCSI(ctx, hostPath, uvmPathForShare, readOnly)
-				if err != nil {
-					return fmt.Errorf("adding SCSI virtual disk mount %+v: %s", mount, err)
-				}
-				resources.scsiMounts = append(resources.scsiMounts, scsiMount{path: hostPath, autoManage: mount.Type == "automanage-virtual-disk"})
+			if mount.Type == "physical-disk" || mount.Type == "virtual-disk" || mount.Type == "automanage-virtual-disk" {
+				plan.addStep(PlanStep{
+					Type:         PlanStepSCSI,
+					HostPath:     hostPath,
+					GuestPath:    uvmPathForShare,
+					ReadOnly:     readOnly,
+					PhysicalDisk: mount.Type == "physical-disk",
+					AutoManage:   mount.Type == "automanage-virtual-disk",
+				})
 				coi.Spec.Mounts[i].Type = "none"
 			} else if strings.HasPrefix(mount.Source, "sandbox://") {
 				// Mounts that map to a path in UVM are specified with 'sandbox://' prefix.
 				// example: sandbox:///a/dirInUvm destination:/b/dirInContainer
 				uvmPathForFile = mount.Source
+			} else if strings.HasPrefix(mount.Source, UVMPathPrefix) {
+				uvmPathForFile = strings.TrimPrefix(mount.Source, UVMPathPrefix)
 			} else {
 				st, err := os.Stat(hostPath)
 				if err != nil {
@@ -116,12 +109,14 @@ func allocateLinuxResources(ctx context.Context, coi *createOptionsInternal, res
 					restrictAccess = true
 					uvmPathForFile = path.Join(uvmPathForShare, fileName)
 				}
-				l.Debug("hcsshim::allocateLinuxResources Hot-adding Plan9 for OCI mount")
-				share, err := coi.HostingSystem.AddPlan9(ctx, hostPath, uvmPathForShare, readOnly, restrictAccess, allowedNames)
-				if err != nil {
-					return fmt.Errorf("adding plan9 mount %+v: %s", mount, err)
-				}
-				resources.plan9Mounts = append(resources.plan9Mounts, share)
+				plan.addStep(PlanStep{
+					Type:         PlanStepPlan9,
+					HostPath:     hostPath,
+					GuestPath:    uvmPathForShare,
+					ReadOnly:     readOnly,
+					Restrict:     restrictAccess,
+					AllowedNames: allowedNames,
+				})
 			}
 			coi.Spec.Mounts[i].Source = uvmPathForFile
 		}
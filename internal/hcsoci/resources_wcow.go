@@ -11,56 +11,43 @@ import (
 	"path/filepath"
 	"strings"
 
-	"github.com/Microsoft/hcsshim/internal/guestrequest"
 	"github.com/Microsoft/hcsshim/internal/log"
 	hcsschema "github.com/Microsoft/hcsshim/internal/schema2"
 	"github.com/Microsoft/hcsshim/internal/schemaversion"
-	"github.com/Microsoft/hcsshim/internal/wclayer"
 	specs "github.com/opencontainers/runtime-spec/specs-go"
 )
 
-func allocateWindowsResources(ctx context.Context, coi *createOptionsInternal, resources *Resources) error {
+// planWindowsResources adds the steps to `plan` that allocate the scratch,
+// layers and mounts of a WCOW container, and translates the host paths in the
+// plan's spec to their utility VM paths.
+func planWindowsResources(ctx context.Context, coi *createOptionsInternal, plan *Plan) error {
 	if coi.Spec == nil || coi.Spec.Windows == nil || coi.Spec.Windows.LayerFolders == nil {
 		return fmt.Errorf("field 'Spec.Windows.Layerfolders' is not populated")
 	}
 
 	scratchFolder := coi.Spec.Windows.LayerFolders[len(coi.Spec.Windows.LayerFolders)-1]
-	log.G(ctx).WithField("scratchFolder", scratchFolder).Debug("hcsshim::allocateWindowsResources scratch folder")
+	log.G(ctx).WithField("scratchFolder", scratchFolder).Debug("hcsshim::planWindowsResources scratch folder")
 
-	// TODO: Remove this code for auto-creation. Make the caller responsible.
-	// Create the directory for the RW scratch layer if it doesn't exist
-	if _, err := os.Stat(scratchFolder); os.IsNotExist(err) {
-		log.G(ctx).WithField("scratchFolder", scratchFolder).Debug("hcsshim::allocateWindowsResources container scratch folder does not exist so creating")
-		if err := os.MkdirAll(scratchFolder, 0777); err != nil {
-			return fmt.Errorf("failed to auto-create container scratch folder %s: %s", scratchFolder, err)
-		}
-	}
-
-	// Create sandbox.vhdx if it doesn't exist in the scratch folder. It's called sandbox.vhdx
-	// rather than scratch.vhdx as in the v1 schema, it's hard-coded in HCS.
 	if _, err := os.Stat(filepath.Join(scratchFolder, "sandbox.vhdx")); os.IsNotExist(err) {
-		log.G(ctx).WithField("scratchFolder", scratchFolder).Debug("hcsshim::allocateWindowsResources container sandbox.vhdx does not exist so creating")
-		if err := wclayer.CreateScratchLayer(scratchFolder, coi.Spec.Windows.LayerFolders[:len(coi.Spec.Windows.LayerFolders)-1]); err != nil {
-			return fmt.Errorf("failed to CreateSandboxLayer %s", err)
-		}
+		plan.addStep(PlanStep{
+			Type:         PlanStepCreateScratch,
+			HostPath:     scratchFolder,
+			LayerFolders: coi.Spec.Windows.LayerFolders,
+		})
 	}
 
 	if coi.Spec.Root == nil {
 		coi.Spec.Root = &specs.Root{}
 	}
 
-	if coi.Spec.Root.Path == "" && (coi.HostingSystem != nil || coi.Spec.Windows.HyperV == nil) {
-		log.G(ctx).Debug("hcsshim::allocateWindowsResources mounting storage")
-		mcl, err := MountContainerLayers(ctx, coi.Spec.Windows.LayerFolders, resources.containerRootInUVM, coi.HostingSystem)
+	if coi.Spec.Root.Path == "" && (coi.host != nil || coi.Spec.Windows.HyperV == nil) {
+		log.G(ctx).Debug("hcsshim::planWindowsResources mounting storage")
+		step, err := planContainerLayers(coi, coi.Spec.Windows.LayerFolders, plan.ContainerRootInUVM)
 		if err != nil {
-			return fmt.Errorf("failed to mount container storage: %s", err)
+			return fmt.Errorf("failed to plan container storage: %s", err)
 		}
-		if coi.HostingSystem == nil {
-			coi.Spec.Root.Path = mcl.(string) // Argon v1 or v2
-		} else {
-			coi.Spec.Root.Path = mcl.(guestrequest.CombinedLayers).ContainerRootPath // v2 Xenon WCOW
-		}
-		resources.layers = coi.Spec.Windows.LayerFolders
+		plan.addStep(step)
+		coi.Spec.Root.Path = step.GuestPath
 	}
 
 	// Validate each of the mounts. If this is a V2 Xenon, we have to add them as
@@ -79,7 +66,14 @@ func allocateWindowsResources(ctx context.Context, coi *createOptionsInternal, r
 			return fmt.Errorf("invalid OCI spec - Type '%s' not supported", mount.Type)
 		}
 
-		if coi.HostingSystem != nil && schemaversion.IsV21(coi.actualSchemaVersion) {
+		if strings.HasPrefix(mount.Source, UVMPathPrefix) {
+			if coi.host == nil || mount.Type != "" {
+				return fmt.Errorf("invalid OCI spec - a mount of a utility VM path must be a directory of a hypervisor isolated container: %+v", mount)
+			}
+			continue
+		}
+
+		if coi.host != nil && schemaversion.IsV21(coi.actualSchemaVersion) {
 			uvmPath := fmt.Sprintf("C:\\%s\\%d", coi.actualID, i)
 
 			readOnly := false
@@ -89,38 +83,30 @@ func allocateWindowsResources(ctx context.Context, coi *createOptionsInternal, r
 					break
 				}
 			}
-			l := log.G(ctx).WithField("mount", fmt.Sprintf("%+v", mount))
-			if mount.Type == "physical-disk" {
-				l.Debug("hcsshim::allocateWindowsResources Hot-adding SCSI physical disk for OCI mount")
-				_, _, err := coi.HostingSystem.AddSCSIPhysicalDisk(ctx, mount.Source, uvmPath, readOnly)
-				if err != nil {
-					return fmt.Errorf("adding SCSI physical disk mount %+v: %s", mount, err)
-				}
-				coi.Spec.Mounts[i].Type = ""
-				resources.scsiMounts = append(resources.scsiMounts, scsiMount{path: mount.Source})
-			} else if mount.Type == "virtual-disk" || mount.Type == "automanage-virtual-disk" {
-				l.Debug("hcsshim::allocateWindowsResources Hot-adding SCSI virtual disk for OCI mount")
-				_, _, err := coi.HostingSystem.AddSCSI(ctx, mount.Source, uvmPath, readOnly)
-				if err != nil {
-					return fmt.Errorf("adding SCSI virtual disk mount %+v: %s", mount, err)
-				}
+			if mount.Type == "physical-disk" || mount.Type == "virtual-disk" || mount.Type == "automanage-virtual-disk" {
+				plan.addStep(PlanStep{
+					Type:         PlanStepSCSI,
+					HostPath:     mount.Source,
+					GuestPath:    uvmPath,
+					ReadOnly:     readOnly,
+					PhysicalDisk: mount.Type == "physical-disk",
+					AutoManage:   mount.Type == "automanage-virtual-disk",
+				})
 				coi.Spec.Mounts[i].Type = ""
-				resources.scsiMounts = append(resources.scsiMounts, scsiMount{path: mount.Source, autoManage: mount.Type == "automanage-virtual-disk"})
 			} else {
-				l.Debug("hcsshim::allocateWindowsResources Hot-adding VSMB share for OCI mount")
 				options := &hcsschema.VirtualSmbShareOptions{}
 				if readOnly {
 					options.ReadOnly = true
 					options.CacheIo = true
 					options.ShareRead = true
 					options.ForceLevelIIOplocks = true
-					break
-				}
-
-				if err := coi.HostingSystem.AddVSMB(ctx, mount.Source, "", options); err != nil {
-					return fmt.Errorf("failed to add VSMB share to utility VM for mount %+v: %s", mount, err)
 				}
-				resources.vsmbMounts = append(resources.vsmbMounts, mount.Source)
+				plan.addStep(PlanStep{
+					Type:        PlanStepVSMB,
+					HostPath:    mount.Source,
+					ReadOnly:    readOnly,
+					VSMBOptions: options,
+				})
 			}
 		}
 	}
@@ -0,0 +1,145 @@
+// +build windows
+
+package hcsoci
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Microsoft/hcsshim/internal/cow"
+	"github.com/Microsoft/hcsshim/internal/guestrequest"
+	"github.com/Microsoft/hcsshim/internal/hcs"
+	"github.com/Microsoft/hcsshim/internal/log"
+	"github.com/Microsoft/hcsshim/internal/logfields"
+	"github.com/Microsoft/hcsshim/internal/requesttype"
+	hcsschema "github.com/Microsoft/hcsshim/internal/schema2"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	containerProcessorResourcePath  = "Container/Processor"
+	containerMemoryResourcePath     = "Container/Memory/SizeInMB"
+	containerStorageQoSResourcePath = "Container/Storage/QoS"
+)
+
+// UpdateWCOWContainer updates the resource limits of the running Windows
+// container `c`. Resources that are not set in `r` are left unchanged.
+func UpdateWCOWContainer(ctx context.Context, c cow.Container, r *specs.WindowsResources) error {
+	requests, err := wcowUpdateRequests(r)
+	if err != nil {
+		return err
+	}
+	for _, req := range requests {
+		log.G(ctx).WithFields(logrus.Fields{
+			logfields.ContainerID: c.ID(),
+			"resourcePath":        req.ResourcePath,
+		}).Debug("updating container resources")
+		if err := c.Modify(ctx, req); err != nil {
+			return fmt.Errorf("failed to update %s of container %s: %s", req.ResourcePath, c.ID(), err)
+		}
+	}
+	return nil
+}
+
+// wcowUpdateRequests returns the HCS requests that update the resource limits
+// of a Windows container to `r`.
+func wcowUpdateRequests(r *specs.WindowsResources) ([]*hcsschema.ModifySettingRequest, error) {
+	var requests []*hcsschema.ModifySettingRequest
+	if r.CPU != nil {
+		processor := &hcsschema.Processor{}
+		cpuNumSet := 0
+		if r.CPU.Count != nil && *r.CPU.Count > 0 {
+			processor.Count = int32(*r.CPU.Count)
+			cpuNumSet++
+		}
+		if r.CPU.Maximum != nil && *r.CPU.Maximum > 0 {
+			processor.Maximum = int32(*r.CPU.Maximum)
+			cpuNumSet++
+		}
+		if r.CPU.Shares != nil && *r.CPU.Shares > 0 {
+			processor.Weight = int32(*r.CPU.Shares)
+			cpuNumSet++
+		}
+		if cpuNumSet > 1 {
+			return nil, fmt.Errorf("invalid resources - Windows Container CPU Count: '%d', Limit: '%d', and Weight: '%d' are mutually exclusive", processor.Count, processor.Maximum, processor.Weight)
+		}
+		if cpuNumSet == 1 {
+			requests = append(requests, &hcsschema.ModifySettingRequest{
+				ResourcePath: containerProcessorResourcePath,
+				RequestType:  requesttype.Update,
+				Settings:     processor,
+			})
+		}
+	}
+	if r.Memory != nil && r.Memory.Limit != nil && *r.Memory.Limit > 0 {
+		// OCI is in Bytes. The container is sized in MB.
+		sizeInMB := *r.Memory.Limit / (1024 * 1024)
+		if sizeInMB == 0 {
+			return nil, errors.New("invalid resources - Windows Container memory limit must be at least 1MB")
+		}
+		requests = append(requests, &hcsschema.ModifySettingRequest{
+			ResourcePath: containerMemoryResourcePath,
+			RequestType:  requesttype.Update,
+			Settings:     sizeInMB,
+		})
+	}
+	if r.Storage != nil && (r.Storage.Bps != nil || r.Storage.Iops != nil) {
+		qos := &hcsschema.StorageQoS{}
+		if r.Storage.Bps != nil {
+			qos.BandwidthMaximum = int32(*r.Storage.Bps)
+		}
+		if r.Storage.Iops != nil {
+			qos.IopsMaximum = int32(*r.Storage.Iops)
+		}
+		requests = append(requests, &hcsschema.ModifySettingRequest{
+			ResourcePath: containerStorageQoSResourcePath,
+			RequestType:  requesttype.Update,
+			Settings:     qos,
+		})
+	}
+	return requests, nil
+}
+
+// UpdateLCOWContainer updates the CPU and memory limits of the running Linux
+// container `c` through the GCS. Resources that are not set in `r` are left
+// unchanged.
+func UpdateLCOWContainer(ctx context.Context, c cow.Container, r *specs.LinuxResources) error {
+	req := lcowUpdateRequest(r)
+	if req == nil {
+		return nil
+	}
+	log.G(ctx).WithField(logfields.ContainerID, c.ID()).Debug("updating container resources")
+
+	var err error
+	if _, ok := c.(*hcs.System); ok {
+		// A container created through the HCS forwards guest requests to the
+		// GCS.
+		err = c.Modify(ctx, &hcsschema.ModifySettingRequest{GuestRequest: req})
+	} else {
+		err = c.Modify(ctx, req)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update constraints of container %s: %s", c.ID(), err)
+	}
+	return nil
+}
+
+// lcowUpdateRequest returns the guest request that updates the CPU and memory
+// limits of a Linux container to `r`, or nil if `r` sets neither.
+func lcowUpdateRequest(r *specs.LinuxResources) *guestrequest.GuestRequest {
+	if r.CPU == nil && r.Memory == nil {
+		return nil
+	}
+	return &guestrequest.GuestRequest{
+		ResourceType: guestrequest.ResourceTypeContainerConstraints,
+		RequestType:  requesttype.Update,
+		Settings: guestrequest.LCOWContainerConstraints{
+			Linux: &specs.LinuxResources{
+				CPU:    r.CPU,
+				Memory: r.Memory,
+			},
+		},
+	}
+}
@@ -0,0 +1,66 @@
+// +build windows
+
+package hcsoci
+
+import (
+	"testing"
+
+	"github.com/Microsoft/hcsshim/internal/guestrequest"
+	hcsschema "github.com/Microsoft/hcsshim/internal/schema2"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+func Test_wcowUpdateRequests(t *testing.T) {
+	count := uint64(2)
+	limit := uint64(512 * 1024 * 1024)
+	iops := uint64(100)
+	requests, err := wcowUpdateRequests(&specs.WindowsResources{
+		CPU:     &specs.WindowsCPUResources{Count: &count},
+		Memory:  &specs.WindowsMemoryResources{Limit: &limit},
+		Storage: &specs.WindowsStorageResources{Iops: &iops},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(requests) != 3 {
+		t.Fatalf("expected 3 requests, got %d", len(requests))
+	}
+	if p, ok := requests[0].Settings.(*hcsschema.Processor); !ok || requests[0].ResourcePath != containerProcessorResourcePath || p.Count != 2 {
+		t.Fatalf("unexpected processor request %+v", requests[0])
+	}
+	if requests[1].ResourcePath != containerMemoryResourcePath || requests[1].Settings != uint64(512) {
+		t.Fatalf("unexpected memory request %+v", requests[1])
+	}
+	if q, ok := requests[2].Settings.(*hcsschema.StorageQoS); !ok || requests[2].ResourcePath != containerStorageQoSResourcePath || q.IopsMaximum != 100 || q.BandwidthMaximum != 0 {
+		t.Fatalf("unexpected storage QoS request %+v", requests[2])
+	}
+}
+
+func Test_wcowUpdateRequests_CPUMutuallyExclusive_Error(t *testing.T) {
+	count := uint64(2)
+	shares := uint16(100)
+	_, err := wcowUpdateRequests(&specs.WindowsResources{
+		CPU: &specs.WindowsCPUResources{Count: &count, Shares: &shares},
+	})
+	if err == nil {
+		t.Fatal("expected an error for both CPU count and shares")
+	}
+}
+
+func Test_lcowUpdateRequest(t *testing.T) {
+	if req := lcowUpdateRequest(&specs.LinuxResources{}); req != nil {
+		t.Fatalf("expected no request without CPU or memory, got %+v", req)
+	}
+	limit := int64(512 * 1024 * 1024)
+	req := lcowUpdateRequest(&specs.LinuxResources{
+		Memory:  &specs.LinuxMemory{Limit: &limit},
+		Devices: []specs.LinuxDeviceCgroup{{Allow: true}},
+	})
+	if req == nil || req.ResourceType != guestrequest.ResourceTypeContainerConstraints {
+		t.Fatalf("unexpected request %+v", req)
+	}
+	constraints := req.Settings.(guestrequest.LCOWContainerConstraints)
+	if constraints.Linux.Memory == nil || *constraints.Linux.Memory.Limit != limit || constraints.Linux.Devices != nil {
+		t.Fatalf("unexpected constraints %+v", constraints.Linux)
+	}
+}
@@ -1,7 +1,11 @@
 package oci
 
 import (
+	"context"
 	"fmt"
+	"strings"
+
+	specs "github.com/opencontainers/runtime-spec/specs-go"
 )
 
 // KubernetesContainerTypeAnnotation is the annotation used by CRI to define the `ContainerType`.
@@ -57,3 +61,98 @@ func GetSandboxTypeAndID(specAnnotations map[string]string) (KubernetesContainer
 	}
 	return ct, id, nil
 }
+
+const (
+	// AnnotationPodShares sets the host folders added to the utility VM of a
+	// hypervisor isolated pod to be shared by all of its containers. It is a
+	// comma separated list of `name=hostpath` entries, and an entry of
+	// `name=hostpath:ro` is shared read-only. A container mounts a share, or a
+	// folder in it, with a mount source of `podshare://name[/path]`. A host
+	// folder MUST be in the bundle of the pod sandbox or in one of the
+	// `pod_share_roots` of the shim options.
+	//
+	// It is read from the spec of the pod sandbox.
+	AnnotationPodShares = "io.microsoft.pod.shares"
+	// AnnotationPodScratchSizeInGB sets the size of a scratch disk attached to
+	// the utility VM of a hypervisor isolated pod to back the volumes shared by
+	// its containers, such as emptyDir volumes. A container mounts a volume
+	// with a mount source of `podscratch://name`, which is created on the disk
+	// the first time it is mounted. If `0` (the default) the pod has no scratch
+	// disk.
+	//
+	// It is read from the spec of the pod sandbox.
+	AnnotationPodScratchSizeInGB = "io.microsoft.pod.scratch.sizeingb"
+)
+
+const (
+	// PodShareMountPrefix is the prefix of the source of a mount of a share
+	// set by `AnnotationPodShares`.
+	PodShareMountPrefix = "podshare://"
+	// PodScratchMountPrefix is the prefix of the source of a mount of a volume
+	// on the scratch disk set by `AnnotationPodScratchSizeInGB`.
+	PodScratchMountPrefix = "podscratch://"
+)
+
+// PodShare is a host folder shared by the containers of a pod.
+type PodShare struct {
+	Name     string
+	HostPath string
+	ReadOnly bool
+}
+
+// ParseAnnotationsPodShares returns the shares set by `AnnotationPodShares` in
+// `s`.
+func ParseAnnotationsPodShares(s *specs.Spec) ([]PodShare, error) {
+	var shares []PodShare
+	seen := make(map[string]bool)
+	for _, e := range parseAnnotationsList(s.Annotations, AnnotationPodShares, nil) {
+		i := strings.Index(e, "=")
+		if i < 0 {
+			return nil, fmt.Errorf("invalid '%s' entry '%s': expected name=hostpath", AnnotationPodShares, e)
+		}
+		share := PodShare{
+			Name:     e[:i],
+			HostPath: e[i+1:],
+		}
+		if strings.HasSuffix(strings.ToLower(share.HostPath), ":ro") {
+			share.HostPath = share.HostPath[:len(share.HostPath)-len(":ro")]
+			share.ReadOnly = true
+		}
+		if !IsValidPodResourceName(share.Name) {
+			return nil, fmt.Errorf("invalid '%s' entry '%s': invalid share name '%s'", AnnotationPodShares, e, share.Name)
+		}
+		if share.HostPath == "" {
+			return nil, fmt.Errorf("invalid '%s' entry '%s': a host path is required", AnnotationPodShares, e)
+		}
+		if seen[share.Name] {
+			return nil, fmt.Errorf("invalid '%s': duplicate share name '%s'", AnnotationPodShares, share.Name)
+		}
+		seen[share.Name] = true
+		shares = append(shares, share)
+	}
+	return shares, nil
+}
+
+// ParseAnnotationsPodScratchSizeInGB returns the size of the pod scratch disk
+// set by `AnnotationPodScratchSizeInGB` in `s`, or `0` if not set.
+func ParseAnnotationsPodScratchSizeInGB(ctx context.Context, s *specs.Spec) uint64 {
+	return parseAnnotationsUint64(ctx, s.Annotations, AnnotationPodScratchSizeInGB, 0)
+}
+
+// IsValidPodResourceName returns `true` if `name` can name a pod share or a pod
+// scratch volume. It must be non-empty and only contain letters, digits, `-`,
+// `_` and `.`, and it cannot be `.` or `..`.
+func IsValidPodResourceName(name string) bool {
+	if name == "" || name == "." || name == ".." {
+		return false
+	}
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -1,6 +1,10 @@
 package oci
 
-import "testing"
+import (
+	"testing"
+
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
 
 func Test_GetSandboxTypeAndID_TypeContainer_NoID_Failure(t *testing.T) {
 	a := map[string]string{
@@ -96,3 +100,46 @@ func Test_GetSandboxTypeAndID_TypeSandbox_ValidID_Success(t *testing.T) {
 		t.Fatalf("should of returned valid id got: %s", id)
 	}
 }
+
+func Test_ParseAnnotationsPodShares(t *testing.T) {
+	s := &specs.Spec{
+		Annotations: map[string]string{
+			AnnotationPodShares: `data=C:\data, config=C:\config:RO`,
+		},
+	}
+	shares, err := ParseAnnotationsPodShares(s)
+	if err != nil {
+		t.Fatalf("should not have failed with error: %v", err)
+	}
+	expected := []PodShare{
+		{Name: "data", HostPath: `C:\data`},
+		{Name: "config", HostPath: `C:\config`, ReadOnly: true},
+	}
+	if len(shares) != len(expected) {
+		t.Fatalf("expected %d shares, got: %+v", len(expected), shares)
+	}
+	for i := range expected {
+		if shares[i] != expected[i] {
+			t.Fatalf("expected share: %+v, got: %+v", expected[i], shares[i])
+		}
+	}
+}
+
+func Test_ParseAnnotationsPodShares_Invalid_Failure(t *testing.T) {
+	for _, v := range []string{
+		`C:\data`,
+		`=C:\data`,
+		`../data=C:\data`,
+		`data=`,
+		`data=C:\a,data=C:\b`,
+	} {
+		s := &specs.Spec{
+			Annotations: map[string]string{
+				AnnotationPodShares: v,
+			},
+		}
+		if _, err := ParseAnnotationsPodShares(s); err == nil {
+			t.Fatalf("should have failed to parse '%s'", v)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package oci
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Microsoft/hcsshim/internal/signals"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+const (
+	// AnnotationContainerStopSignal sets the signal sent to the init process of
+	// a container in place of the SIGTERM that stops it. It is the text name or
+	// integer value of a signal, which for WCOW is a Windows CTRL event such as
+	// `CtrlShutdown` or `CtrlC`, or `TERM`/`KILL`.
+	AnnotationContainerStopSignal = "io.microsoft.container.stop.signal"
+	// AnnotationContainerStopGracePeriodInSeconds sets how long the init
+	// process of a container has to exit after each signal that stops it before
+	// the next signal is sent. Defaults to 30 seconds.
+	AnnotationContainerStopGracePeriodInSeconds = "io.microsoft.container.stop.graceperiodinseconds"
+	// AnnotationContainerStopEscalation sets a comma separated list of signals
+	// sent in order, one per grace period, to the init process of a container
+	// that did not exit after the stop signal. A SIGKILL always follows the
+	// last signal.
+	AnnotationContainerStopEscalation = "io.microsoft.container.stop.escalation"
+	// AnnotationContainerSidecar sets whether a container of a pod is a
+	// sidecar. When the pod is stopped its sidecars are stopped only once all
+	// of its other containers have exited. Defaults to false.
+	AnnotationContainerSidecar = "io.microsoft.container.sidecar"
+)
+
+// defaultStopGracePeriod is the grace period of a stop policy that does not set
+// `AnnotationContainerStopGracePeriodInSeconds`.
+const defaultStopGracePeriod = 30 * time.Second
+
+// StopPolicy is how the init process of a container is stopped.
+type StopPolicy struct {
+	// Signal is sent in place of the SIGTERM that stops the container.
+	Signal int
+	// GracePeriod is how long the process has to exit after each signal.
+	GracePeriod time.Duration
+	// Escalation are the signals sent in order if the process did not exit
+	// after `Signal`. A SIGKILL always follows the last signal.
+	Escalation []int
+}
+
+// ParseAnnotationsStopPolicy returns the stop policy set by
+// `AnnotationContainerStopSignal`, `AnnotationContainerStopGracePeriodInSeconds`
+// and `AnnotationContainerStopEscalation` in `s`, or `nil` if none of them are
+// set.
+func ParseAnnotationsStopPolicy(s *specs.Spec) (*StopPolicy, error) {
+	sigstr, hasSignal := s.Annotations[AnnotationContainerStopSignal]
+	grace, hasGrace := s.Annotations[AnnotationContainerStopGracePeriodInSeconds]
+	escalation := parseAnnotationsList(s.Annotations, AnnotationContainerStopEscalation, nil)
+	if !hasSignal && !hasGrace && len(escalation) == 0 {
+		return nil, nil
+	}
+
+	parse := signals.ParseSigstrLCOW
+	if IsWCOW(s) {
+		parse = signals.ParseSigstrWCOW
+	}
+	if !hasSignal {
+		sigstr = "TERM"
+	}
+	signal, err := parse(sigstr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid '%s' signal '%s': %s", AnnotationContainerStopSignal, sigstr, err)
+	}
+	p := &StopPolicy{
+		Signal:      signal,
+		GracePeriod: defaultStopGracePeriod,
+	}
+	if hasGrace {
+		seconds, err := strconv.ParseUint(grace, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid '%s' value '%s': %s", AnnotationContainerStopGracePeriodInSeconds, grace, err)
+		}
+		p.GracePeriod = time.Duration(seconds) * time.Second
+	}
+	for _, e := range escalation {
+		signal, err := parse(e)
+		if err != nil {
+			return nil, fmt.Errorf("invalid '%s' signal '%s': %s", AnnotationContainerStopEscalation, e, err)
+		}
+		p.Escalation = append(p.Escalation, signal)
+	}
+	return p, nil
+}
+
+// ParseAnnotationsContainerSidecar returns whether `s` sets
+// `AnnotationContainerSidecar`.
+func ParseAnnotationsContainerSidecar(ctx context.Context, s *specs.Spec) bool {
+	return parseAnnotationsBool(ctx, s.Annotations, AnnotationContainerSidecar, false)
+}
@@ -0,0 +1,70 @@
+package oci
+
+import (
+	"testing"
+	"time"
+
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+func Test_ParseAnnotationsStopPolicy_NotSet(t *testing.T) {
+	s := &specs.Spec{}
+	p, err := ParseAnnotationsStopPolicy(s)
+	if err != nil {
+		t.Fatalf("should not have failed, got: %v", err)
+	}
+	if p != nil {
+		t.Fatalf("expected no stop policy, got: %+v", p)
+	}
+}
+
+func Test_ParseAnnotationsStopPolicy_WCOW(t *testing.T) {
+	s := &specs.Spec{
+		Windows: &specs.Windows{},
+		Annotations: map[string]string{
+			AnnotationContainerStopSignal:               "CtrlShutdown",
+			AnnotationContainerStopGracePeriodInSeconds: "300",
+			AnnotationContainerStopEscalation:           "CtrlClose, CtrlC",
+		},
+	}
+	p, err := ParseAnnotationsStopPolicy(s)
+	if err != nil {
+		t.Fatalf("should not have failed, got: %v", err)
+	}
+	if p.Signal != 0x6 || p.GracePeriod != 300*time.Second || len(p.Escalation) != 2 || p.Escalation[0] != 0x2 || p.Escalation[1] != 0x0 {
+		t.Fatalf("unexpected stop policy: %+v", p)
+	}
+}
+
+func Test_ParseAnnotationsStopPolicy_Defaults(t *testing.T) {
+	s := &specs.Spec{
+		Linux: &specs.Linux{},
+		Annotations: map[string]string{
+			AnnotationContainerStopEscalation: "INT",
+		},
+	}
+	p, err := ParseAnnotationsStopPolicy(s)
+	if err != nil {
+		t.Fatalf("should not have failed, got: %v", err)
+	}
+	if p.Signal != 0xf || p.GracePeriod != defaultStopGracePeriod || len(p.Escalation) != 1 || p.Escalation[0] != 0x2 {
+		t.Fatalf("unexpected stop policy: %+v", p)
+	}
+}
+
+func Test_ParseAnnotationsStopPolicy_Invalid_Failure(t *testing.T) {
+	tests := []map[string]string{
+		{AnnotationContainerStopSignal: "CtrlShutdown"},
+		{AnnotationContainerStopGracePeriodInSeconds: "-1"},
+		{AnnotationContainerStopEscalation: "TERM,NOPE"},
+	}
+	for _, a := range tests {
+		s := &specs.Spec{
+			Linux:       &specs.Linux{},
+			Annotations: a,
+		}
+		if _, err := ParseAnnotationsStopPolicy(s); err == nil {
+			t.Fatalf("expected an error for annotations: %v", a)
+		}
+	}
+}
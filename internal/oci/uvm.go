@@ -2,14 +2,12 @@ package oci
 
 import (
 	"context"
-	"errors"
 	"strconv"
 	"strings"
 
 	runhcsopts "github.com/Microsoft/hcsshim/cmd/containerd-shim-runhcs-v1/options"
 	"github.com/Microsoft/hcsshim/internal/log"
 	"github.com/Microsoft/hcsshim/internal/logfields"
-	"github.com/Microsoft/hcsshim/internal/uvm"
 	"github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/sirupsen/logrus"
 )
@@ -78,9 +76,17 @@ const (
 	// used via OCI runtimes and rather use
 	// `spec.Windows.Resources.Storage.Iops`.
 	AnnotationContainerStorageQoSIopsMaximum = "io.microsoft.container.storage.qos.iopsmaximum"
-	annotationAllowOvercommit                = "io.microsoft.virtualmachine.computetopology.memory.allowovercommit"
-	annotationEnableDeferredCommit           = "io.microsoft.virtualmachine.computetopology.memory.enabledeferredcommit"
-	annotationEnableColdDiscardHint          = "io.microsoft.virtualmachine.computetopology.memory.enablecolddiscardhint"
+	// AnnotationRestartRecovery sets whether the tasks of a shim keep running
+	// when the shim exits unexpectedly, so that a shim restarted by `start`
+	// can reattach to them. It is read from the spec of the first task created
+	// by the shim and defaults to false. Recovery only lasts until containerd
+	// gives up on the shim: `delete` still terminates the tasks, as containerd
+	// removes the bundle and the saved shim state afterwards. A recoverable
+	// LCOW utility VM always uses the guest connection of the HCS.
+	AnnotationRestartRecovery       = "io.microsoft.shim.restartrecovery"
+	annotationAllowOvercommit       = "io.microsoft.virtualmachine.computetopology.memory.allowovercommit"
+	annotationEnableDeferredCommit  = "io.microsoft.virtualmachine.computetopology.memory.enabledeferredcommit"
+	annotationEnableColdDiscardHint = "io.microsoft.virtualmachine.computetopology.memory.enablecolddiscardhint"
 	// annotationMemorySizeInMB overrides the container memory size set via the
 	// OCI spec.
 	//
@@ -111,11 +117,57 @@ const (
 	// the UVM are not mutually exclusive and can be set together.
 	annotationProcessorWeight            = "io.microsoft.virtualmachine.computetopology.processor.weight"
 	annotationVPMemCount                 = "io.microsoft.virtualmachine.devices.virtualpmem.maximumcount"
+	annotationSCSIControllerCount        = "io.microsoft.virtualmachine.devices.scsi.controllercount"
 	annotationVPMemSize                  = "io.microsoft.virtualmachine.devices.virtualpmem.maximumsizebytes"
 	annotationPreferredRootFSType        = "io.microsoft.virtualmachine.lcow.preferredrootfstype"
 	annotationBootFilesRootPath          = "io.microsoft.virtualmachine.lcow.bootfilesrootpath"
 	annotationStorageQoSBandwidthMaximum = "io.microsoft.virtualmachine.storageqos.bandwidthmaximum"
 	annotationStorageQoSIopsMaximum      = "io.microsoft.virtualmachine.storageqos.iopsmaximum"
+	// annotationUVMPoolSize sets the number of booted, idle utility VMs kept
+	// ready for pods with the same utility VM configuration. If `0` (the
+	// default) utility VMs are created on demand.
+	annotationUVMPoolSize = "io.microsoft.virtualmachine.pool.size"
+	// annotationUVMPoolMaxIdleAgeInSeconds sets how long a pooled utility VM
+	// may stay idle before it is replaced.
+	annotationUVMPoolMaxIdleAgeInSeconds = "io.microsoft.virtualmachine.pool.maxidleageinseconds"
+	// annotationUVMPoolMinAvailableMemoryInMB sets the host memory below which
+	// pooled utility VMs are evicted.
+	annotationUVMPoolMinAvailableMemoryInMB = "io.microsoft.virtualmachine.pool.minavailablememoryinmb"
+	// annotationLogCaptureConsole sets whether the serial console of an LCOW
+	// utility VM is captured along with the GCS log. Defaults to false.
+	annotationLogCaptureConsole = "io.microsoft.virtualmachine.lcow.logcapture.console"
+	// annotationLogCaptureMaxSizeInMB sets the size at which a captured guest
+	// log file is rotated.
+	annotationLogCaptureMaxSizeInMB = "io.microsoft.virtualmachine.lcow.logcapture.maxsizeinmb"
+	// annotationLogCaptureMaxFiles sets the number of files kept for each
+	// captured guest log.
+	annotationLogCaptureMaxFiles = "io.microsoft.virtualmachine.lcow.logcapture.maxfiles"
+	// annotationCrashReporting sets whether a kernel panic in an LCOW utility
+	// VM is detected and reported. Defaults to true when the guest logs are
+	// captured, as the shim does, since the panic report is saved with them,
+	// and to false otherwise.
+	annotationCrashReporting = "io.microsoft.virtualmachine.lcow.crashreporting"
+	// annotationWorkingSetTrimIdleTimeoutInSeconds sets how long a utility VM
+	// backed by virtual memory must be idle before the working set of its
+	// vmmem process is trimmed. If `0` (the default) it is never trimmed
+	// automatically.
+	annotationWorkingSetTrimIdleTimeoutInSeconds = "io.microsoft.virtualmachine.computetopology.memory.trimidletimeoutinseconds"
+	// annotationWorkingSetTrimIdleProcessorPercent sets the processor usage, in
+	// percent of one vCPU, below which a utility VM is idle.
+	annotationWorkingSetTrimIdleProcessorPercent = "io.microsoft.virtualmachine.computetopology.memory.trimidleprocessorpercent"
+	// annotationKernelFile sets the file name under the boot files path of
+	// the kernel that boots an LCOW utility VM.
+	annotationKernelFile = "io.microsoft.virtualmachine.lcow.kernelfile"
+	// annotationKernelDirectBoot sets whether the kernel of an LCOW utility VM
+	// is booted directly rather than through UEFI.
+	annotationKernelDirectBoot = "io.microsoft.virtualmachine.lcow.kerneldirectboot"
+	// annotationKernelBootOptions sets kernel arguments that replace the
+	// default arguments of an LCOW utility VM with the same name.
+	annotationKernelBootOptions = "io.microsoft.virtualmachine.lcow.kernelbootoptions"
+	// annotationAdditionalInitrds sets a comma separated list of initrds under
+	// the boot files path loaded after the initrd root file system of an LCOW
+	// utility VM.
+	annotationAdditionalInitrds = "io.microsoft.virtualmachine.lcow.additionalinitrds"
 )
 
 // parseAnnotationsBool searches `a` for `key` and if found verifies that the
@@ -138,6 +190,18 @@ func parseAnnotationsBool(ctx context.Context, a map[string]string, key string,
 	return def
 }
 
+// ParseAnnotationsRestartRecovery returns whether `s` sets
+// `AnnotationRestartRecovery`.
+func ParseAnnotationsRestartRecovery(ctx context.Context, s *specs.Spec) bool {
+	return parseAnnotationsBool(ctx, s.Annotations, AnnotationRestartRecovery, false)
+}
+
+// ParseAnnotationsCrashReporting returns whether `s` enables crash reporting
+// for an LCOW utility VM, defaulting to `def`.
+func ParseAnnotationsCrashReporting(ctx context.Context, s *specs.Spec, def bool) bool {
+	return parseAnnotationsBool(ctx, s.Annotations, annotationCrashReporting, def)
+}
+
 // ParseAnnotationsCPUCount searches `s.Annotations` for the CPU annotation. If
 // not found searches `s` for the Windows CPU section. If neither are found
 // returns `def`.
@@ -242,25 +306,6 @@ func ParseAnnotationsMemory(ctx context.Context, s *specs.Spec, annotation strin
 	return def
 }
 
-// parseAnnotationsPreferredRootFSType searches `a` for `key` and verifies that the
-// value is in the set of allowed values. If `key` is not found returns `def`.
-func parseAnnotationsPreferredRootFSType(ctx context.Context, a map[string]string, key string, def uvm.PreferredRootFSType) uvm.PreferredRootFSType {
-	if v, ok := a[key]; ok {
-		switch v {
-		case "initrd":
-			return uvm.PreferredRootFSTypeInitRd
-		case "vhd":
-			return uvm.PreferredRootFSTypeVHD
-		default:
-			log.G(ctx).WithFields(logrus.Fields{
-				"annotation": key,
-				"value":      v,
-			}).Warn("annotation value must be 'initrd' or 'vhd'")
-		}
-	}
-	return def
-}
-
 // parseAnnotationsUint32 searches `a` for `key` and if found verifies that the
 // value is a 32 bit unsigned integer. If `key` is not found returns `def`.
 func parseAnnotationsUint32(ctx context.Context, a map[string]string, key string, def uint32) uint32 {
@@ -306,47 +351,20 @@ func parseAnnotationsString(a map[string]string, key string, def string) string
 	return def
 }
 
-// SpecToUVMCreateOpts parses `s` and returns either `*uvm.OptionsLCOW` or
-// `*uvm.OptionsWCOW`.
-func SpecToUVMCreateOpts(ctx context.Context, s *specs.Spec, id, owner string) (interface{}, error) {
-	if !IsIsolated(s) {
-		return nil, errors.New("cannot create UVM opts for non-isolated spec")
+// parseAnnotationsList searches `a` for `key` and if found splits the value
+// at commas, ignoring empty elements. If `key` is not found returns `def`.
+func parseAnnotationsList(a map[string]string, key string, def []string) []string {
+	v, ok := a[key]
+	if !ok {
+		return def
 	}
-	if IsLCOW(s) {
-		lopts := uvm.NewDefaultOptionsLCOW(id, owner)
-		lopts.MemorySizeInMB = ParseAnnotationsMemory(ctx, s, annotationMemorySizeInMB, lopts.MemorySizeInMB)
-		lopts.AllowOvercommit = parseAnnotationsBool(ctx, s.Annotations, annotationAllowOvercommit, lopts.AllowOvercommit)
-		lopts.EnableDeferredCommit = parseAnnotationsBool(ctx, s.Annotations, annotationEnableDeferredCommit, lopts.EnableDeferredCommit)
-		lopts.EnableColdDiscardHint = parseAnnotationsBool(ctx, s.Annotations, annotationEnableColdDiscardHint, lopts.EnableColdDiscardHint)
-		lopts.ProcessorCount = ParseAnnotationsCPUCount(ctx, s, annotationProcessorCount, lopts.ProcessorCount)
-		lopts.ProcessorLimit = ParseAnnotationsCPULimit(ctx, s, annotationProcessorLimit, lopts.ProcessorLimit)
-		lopts.ProcessorWeight = ParseAnnotationsCPUWeight(ctx, s, annotationProcessorWeight, lopts.ProcessorWeight)
-		lopts.VPMemDeviceCount = parseAnnotationsUint32(ctx, s.Annotations, annotationVPMemCount, lopts.VPMemDeviceCount)
-		lopts.VPMemSizeBytes = parseAnnotationsUint64(ctx, s.Annotations, annotationVPMemSize, lopts.VPMemSizeBytes)
-		lopts.StorageQoSBandwidthMaximum = ParseAnnotationsStorageBps(ctx, s, annotationStorageQoSBandwidthMaximum, lopts.StorageQoSBandwidthMaximum)
-		lopts.StorageQoSIopsMaximum = ParseAnnotationsStorageIops(ctx, s, annotationStorageQoSIopsMaximum, lopts.StorageQoSIopsMaximum)
-		lopts.PreferredRootFSType = parseAnnotationsPreferredRootFSType(ctx, s.Annotations, annotationPreferredRootFSType, lopts.PreferredRootFSType)
-		switch lopts.PreferredRootFSType {
-		case uvm.PreferredRootFSTypeInitRd:
-			lopts.RootFSFile = uvm.InitrdFile
-		case uvm.PreferredRootFSTypeVHD:
-			lopts.RootFSFile = uvm.VhdFile
+	var l []string
+	for _, e := range strings.Split(v, ",") {
+		if e = strings.TrimSpace(e); e != "" {
+			l = append(l, e)
 		}
-		lopts.BootFilesPath = parseAnnotationsString(s.Annotations, annotationBootFilesRootPath, lopts.BootFilesPath)
-		return lopts, nil
-	} else if IsWCOW(s) {
-		wopts := uvm.NewDefaultOptionsWCOW(id, owner)
-		wopts.MemorySizeInMB = ParseAnnotationsMemory(ctx, s, annotationMemorySizeInMB, wopts.MemorySizeInMB)
-		wopts.AllowOvercommit = parseAnnotationsBool(ctx, s.Annotations, annotationAllowOvercommit, wopts.AllowOvercommit)
-		wopts.EnableDeferredCommit = parseAnnotationsBool(ctx, s.Annotations, annotationEnableDeferredCommit, wopts.EnableDeferredCommit)
-		wopts.ProcessorCount = ParseAnnotationsCPUCount(ctx, s, annotationProcessorCount, wopts.ProcessorCount)
-		wopts.ProcessorLimit = ParseAnnotationsCPULimit(ctx, s, annotationProcessorLimit, wopts.ProcessorLimit)
-		wopts.ProcessorWeight = ParseAnnotationsCPUWeight(ctx, s, annotationProcessorWeight, wopts.ProcessorWeight)
-		wopts.StorageQoSBandwidthMaximum = ParseAnnotationsStorageBps(ctx, s, annotationStorageQoSBandwidthMaximum, wopts.StorageQoSBandwidthMaximum)
-		wopts.StorageQoSIopsMaximum = ParseAnnotationsStorageIops(ctx, s, annotationStorageQoSIopsMaximum, wopts.StorageQoSIopsMaximum)
-		return wopts, nil
 	}
-	return nil, errors.New("cannot create UVM opts spec is not LCOW or WCOW")
+	return l
 }
 
 // UpdateSpecFromOptions sets extra annotations on the OCI spec based on the
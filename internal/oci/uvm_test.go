@@ -0,0 +1,23 @@
+package oci
+
+import (
+	"context"
+	"testing"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+)
+
+func Test_ParseAnnotationsCrashReporting(t *testing.T) {
+	ctx := context.Background()
+	s := &specs.Spec{}
+	if !ParseAnnotationsCrashReporting(ctx, s, true) {
+		t.Fatal("expected crash reporting to default to on")
+	}
+	if ParseAnnotationsCrashReporting(ctx, s, false) {
+		t.Fatal("expected crash reporting to default to off")
+	}
+	s.Annotations = map[string]string{annotationCrashReporting: "false"}
+	if ParseAnnotationsCrashReporting(ctx, s, true) {
+		t.Fatal("expected the annotation to turn crash reporting off")
+	}
+}
@@ -0,0 +1,115 @@
+// +build windows
+
+package oci
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Microsoft/hcsshim/internal/log"
+	"github.com/Microsoft/hcsshim/internal/uvm"
+	"github.com/Microsoft/hcsshim/internal/uvmpool"
+	"github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/sirupsen/logrus"
+)
+
+// parseAnnotationsPreferredRootFSType searches `a` for `key` and verifies that the
+// value is in the set of allowed values. If `key` is not found returns `def`.
+func parseAnnotationsPreferredRootFSType(ctx context.Context, a map[string]string, key string, def uvm.PreferredRootFSType) uvm.PreferredRootFSType {
+	if v, ok := a[key]; ok {
+		switch v {
+		case "initrd":
+			return uvm.PreferredRootFSTypeInitRd
+		case "vhd":
+			return uvm.PreferredRootFSTypeVHD
+		default:
+			log.G(ctx).WithFields(logrus.Fields{
+				"annotation": key,
+				"value":      v,
+			}).Warn("annotation value must be 'initrd' or 'vhd'")
+		}
+	}
+	return def
+}
+
+// SpecToUVMCreateOpts parses `s` and returns either `*uvm.OptionsLCOW` or
+// `*uvm.OptionsWCOW`.
+func SpecToUVMCreateOpts(ctx context.Context, s *specs.Spec, id, owner string) (interface{}, error) {
+	if !IsIsolated(s) {
+		return nil, errors.New("cannot create UVM opts for non-isolated spec")
+	}
+	if IsLCOW(s) {
+		lopts := uvm.NewDefaultOptionsLCOW(id, owner)
+		lopts.MemorySizeInMB = ParseAnnotationsMemory(ctx, s, annotationMemorySizeInMB, lopts.MemorySizeInMB)
+		lopts.AllowOvercommit = parseAnnotationsBool(ctx, s.Annotations, annotationAllowOvercommit, lopts.AllowOvercommit)
+		lopts.EnableDeferredCommit = parseAnnotationsBool(ctx, s.Annotations, annotationEnableDeferredCommit, lopts.EnableDeferredCommit)
+		lopts.EnableColdDiscardHint = parseAnnotationsBool(ctx, s.Annotations, annotationEnableColdDiscardHint, lopts.EnableColdDiscardHint)
+		lopts.WorkingSetTrimPolicy = parseAnnotationsWorkingSetTrimPolicy(ctx, s.Annotations)
+		lopts.ProcessorCount = ParseAnnotationsCPUCount(ctx, s, annotationProcessorCount, lopts.ProcessorCount)
+		lopts.ProcessorLimit = ParseAnnotationsCPULimit(ctx, s, annotationProcessorLimit, lopts.ProcessorLimit)
+		lopts.ProcessorWeight = ParseAnnotationsCPUWeight(ctx, s, annotationProcessorWeight, lopts.ProcessorWeight)
+		lopts.VPMemDeviceCount = parseAnnotationsUint32(ctx, s.Annotations, annotationVPMemCount, lopts.VPMemDeviceCount)
+		lopts.VPMemSizeBytes = parseAnnotationsUint64(ctx, s.Annotations, annotationVPMemSize, lopts.VPMemSizeBytes)
+		lopts.SCSIControllerCount = parseAnnotationsUint32(ctx, s.Annotations, annotationSCSIControllerCount, lopts.SCSIControllerCount)
+		lopts.StorageQoSBandwidthMaximum = ParseAnnotationsStorageBps(ctx, s, annotationStorageQoSBandwidthMaximum, lopts.StorageQoSBandwidthMaximum)
+		lopts.StorageQoSIopsMaximum = ParseAnnotationsStorageIops(ctx, s, annotationStorageQoSIopsMaximum, lopts.StorageQoSIopsMaximum)
+		lopts.PreferredRootFSType = parseAnnotationsPreferredRootFSType(ctx, s.Annotations, annotationPreferredRootFSType, lopts.PreferredRootFSType)
+		switch lopts.PreferredRootFSType {
+		case uvm.PreferredRootFSTypeInitRd:
+			lopts.RootFSFile = uvm.InitrdFile
+		case uvm.PreferredRootFSTypeVHD:
+			lopts.RootFSFile = uvm.VhdFile
+		}
+		lopts.BootFilesPath = parseAnnotationsString(s.Annotations, annotationBootFilesRootPath, lopts.BootFilesPath)
+		lopts.KernelFile = parseAnnotationsString(s.Annotations, annotationKernelFile, lopts.KernelFile)
+		lopts.KernelDirect = parseAnnotationsBool(ctx, s.Annotations, annotationKernelDirectBoot, lopts.KernelDirect)
+		lopts.KernelBootOptions = parseAnnotationsString(s.Annotations, annotationKernelBootOptions, lopts.KernelBootOptions)
+		lopts.AdditionalInitrdFiles = parseAnnotationsList(s.Annotations, annotationAdditionalInitrds, lopts.AdditionalInitrdFiles)
+		lopts.CaptureConsole = parseAnnotationsBool(ctx, s.Annotations, annotationLogCaptureConsole, lopts.CaptureConsole)
+		lopts.LogCaptureMaxSizeInMB = parseAnnotationsUint32(ctx, s.Annotations, annotationLogCaptureMaxSizeInMB, lopts.LogCaptureMaxSizeInMB)
+		lopts.LogCaptureMaxFiles = parseAnnotationsUint32(ctx, s.Annotations, annotationLogCaptureMaxFiles, lopts.LogCaptureMaxFiles)
+		lopts.EnableCrashReporting = ParseAnnotationsCrashReporting(ctx, s, lopts.EnableCrashReporting)
+		lopts.Recoverable = ParseAnnotationsRestartRecovery(ctx, s)
+		if lopts.Recoverable && lopts.ExternalGuestConnection {
+			// Only containers created through the HCS can be reopened.
+			log.G(ctx).WithField(AnnotationRestartRecovery, true).Warn("restart recovery requires the guest connection of the HCS, not using the external guest connection")
+			lopts.ExternalGuestConnection = false
+		}
+		return lopts, nil
+	} else if IsWCOW(s) {
+		wopts := uvm.NewDefaultOptionsWCOW(id, owner)
+		wopts.MemorySizeInMB = ParseAnnotationsMemory(ctx, s, annotationMemorySizeInMB, wopts.MemorySizeInMB)
+		wopts.AllowOvercommit = parseAnnotationsBool(ctx, s.Annotations, annotationAllowOvercommit, wopts.AllowOvercommit)
+		wopts.EnableDeferredCommit = parseAnnotationsBool(ctx, s.Annotations, annotationEnableDeferredCommit, wopts.EnableDeferredCommit)
+		wopts.WorkingSetTrimPolicy = parseAnnotationsWorkingSetTrimPolicy(ctx, s.Annotations)
+		wopts.ProcessorCount = ParseAnnotationsCPUCount(ctx, s, annotationProcessorCount, wopts.ProcessorCount)
+		wopts.ProcessorLimit = ParseAnnotationsCPULimit(ctx, s, annotationProcessorLimit, wopts.ProcessorLimit)
+		wopts.ProcessorWeight = ParseAnnotationsCPUWeight(ctx, s, annotationProcessorWeight, wopts.ProcessorWeight)
+		wopts.SCSIControllerCount = parseAnnotationsUint32(ctx, s.Annotations, annotationSCSIControllerCount, wopts.SCSIControllerCount)
+		wopts.StorageQoSBandwidthMaximum = ParseAnnotationsStorageBps(ctx, s, annotationStorageQoSBandwidthMaximum, wopts.StorageQoSBandwidthMaximum)
+		wopts.StorageQoSIopsMaximum = ParseAnnotationsStorageIops(ctx, s, annotationStorageQoSIopsMaximum, wopts.StorageQoSIopsMaximum)
+		wopts.Recoverable = ParseAnnotationsRestartRecovery(ctx, s)
+		return wopts, nil
+	}
+	return nil, errors.New("cannot create UVM opts spec is not LCOW or WCOW")
+}
+
+// parseAnnotationsWorkingSetTrimPolicy returns the working set trim policy of a
+// utility VM set by `a`.
+func parseAnnotationsWorkingSetTrimPolicy(ctx context.Context, a map[string]string) uvm.WorkingSetTrimPolicy {
+	return uvm.WorkingSetTrimPolicy{
+		IdleTimeout:          time.Duration(parseAnnotationsUint64(ctx, a, annotationWorkingSetTrimIdleTimeoutInSeconds, 0)) * time.Second,
+		IdleProcessorPercent: parseAnnotationsUint32(ctx, a, annotationWorkingSetTrimIdleProcessorPercent, 0),
+	}
+}
+
+// SpecToUVMPoolConfig returns the configuration of the utility VM pool set by
+// the annotations of `s`.
+func SpecToUVMPoolConfig(ctx context.Context, s *specs.Spec) uvmpool.Config {
+	return uvmpool.Config{
+		Size:                   int(parseAnnotationsUint32(ctx, s.Annotations, annotationUVMPoolSize, 0)),
+		MaxIdleAge:             time.Duration(parseAnnotationsUint64(ctx, s.Annotations, annotationUVMPoolMaxIdleAgeInSeconds, 0)) * time.Second,
+		MinAvailableMemoryInMB: parseAnnotationsUint64(ctx, s.Annotations, annotationUVMPoolMinAvailableMemoryInMB, 0),
+	}
+}
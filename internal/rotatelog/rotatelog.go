@@ -0,0 +1,186 @@
+// Package rotatelog implements log files that are rotated by size.
+package rotatelog
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// File is a log file that is rotated once a write would grow it past a maximum
+// size: `path` is renamed to `path.1`, `path.1` to `path.2` and so on, and
+// `path` is started anew. At most `maxFiles` files are kept, the oldest being
+// removed.
+type File struct {
+	m        sync.Mutex
+	path     string
+	maxSize  int64
+	maxFiles int
+	f        *os.File
+	size     int64
+}
+
+// Open opens the log file at `path` for appending, creating it and its
+// directory if they do not exist.
+func Open(path string, maxSize int64, maxFiles int) (*File, error) {
+	if maxSize <= 0 {
+		return nil, errors.New("maximum log file size must be greater than 0")
+	}
+	if maxFiles < 1 {
+		return nil, errors.New("maximum log file count must be at least 1")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &File{
+		path:     path,
+		maxSize:  maxSize,
+		maxFiles: maxFiles,
+		f:        f,
+		size:     fi.Size(),
+	}, nil
+}
+
+// Write writes `p` to the log file, rotating it first if `p` does not fit. A
+// write larger than the maximum size is not split.
+func (lf *File) Write(p []byte) (int, error) {
+	lf.m.Lock()
+	defer lf.m.Unlock()
+	if lf.f == nil {
+		return 0, os.ErrClosed
+	}
+	if lf.size > 0 && lf.size+int64(len(p)) > lf.maxSize {
+		if err := lf.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := lf.f.Write(p)
+	lf.size += int64(n)
+	return n, err
+}
+
+// rotate shifts the rotated files by one and starts a new log file. `lf.m`
+// must be held.
+func (lf *File) rotate() error {
+	if err := lf.f.Close(); err != nil {
+		return err
+	}
+	lf.f = nil
+	if lf.maxFiles > 1 {
+		if err := os.Remove(rotatedName(lf.path, lf.maxFiles-1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		for i := lf.maxFiles - 2; i > 0; i-- {
+			if err := os.Rename(rotatedName(lf.path, i), rotatedName(lf.path, i+1)); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+		if err := os.Rename(lf.path, rotatedName(lf.path, 1)); err != nil {
+			return err
+		}
+	}
+	f, err := os.OpenFile(lf.path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	lf.f = f
+	lf.size = 0
+	return nil
+}
+
+// Close closes the log file. Writes after Close fail with `os.ErrClosed`.
+func (lf *File) Close() error {
+	lf.m.Lock()
+	defer lf.m.Unlock()
+	if lf.f == nil {
+		return nil
+	}
+	err := lf.f.Close()
+	lf.f = nil
+	return err
+}
+
+func rotatedName(path string, i int) string {
+	return fmt.Sprintf("%s.%d", path, i)
+}
+
+// Files returns the existing files of the log file at `path` that keeps
+// `maxFiles` files, oldest first.
+func Files(path string, maxFiles int) []string {
+	var files []string
+	for i := maxFiles - 1; i >= 0; i-- {
+		name := path
+		if i > 0 {
+			name = rotatedName(path, i)
+		}
+		if _, err := os.Stat(name); err == nil {
+			files = append(files, name)
+		}
+	}
+	return files
+}
+
+// Tail returns the last `n` bytes written to the log file at `path` that keeps
+// `maxFiles` files, or all of them if `n` is 0, and whether earlier bytes were
+// left out.
+func Tail(path string, maxFiles int, n int64) ([]byte, bool, error) {
+	files := Files(path, maxFiles)
+	sizes := make([]int64, len(files))
+	var total int64
+	for i, name := range files {
+		fi, err := os.Stat(name)
+		if err != nil {
+			return nil, false, err
+		}
+		sizes[i] = fi.Size()
+		total += sizes[i]
+	}
+	var skip int64
+	if n > 0 && total > n {
+		skip = total - n
+	}
+	var buf bytes.Buffer
+	for i, name := range files {
+		if skip >= sizes[i] {
+			skip -= sizes[i]
+			continue
+		}
+		if err := readRange(&buf, name, skip, sizes[i]-skip); err != nil {
+			return nil, false, err
+		}
+		skip = 0
+	}
+	return buf.Bytes(), n > 0 && total > n, nil
+}
+
+// readRange appends `n` bytes of the file `name` starting at `offset` to `buf`.
+// The file may have been rotated away since it was listed, in which case
+// nothing is appended.
+func readRange(buf *bytes.Buffer, name string, offset, n int64) error {
+	f, err := os.Open(name)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer f.Close()
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+	_, err = io.Copy(buf, io.LimitReader(f, n))
+	return err
+}
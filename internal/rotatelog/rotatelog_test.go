@@ -0,0 +1,123 @@
+package rotatelog
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeAll(t *testing.T, lf *File, writes ...string) {
+	for _, w := range writes {
+		if _, err := lf.Write([]byte(w)); err != nil {
+			t.Fatalf("failed to write %q: %s", w, err)
+		}
+	}
+}
+
+func readFile(t *testing.T, name string) string {
+	b, err := ioutil.ReadFile(name)
+	if err != nil {
+		t.Fatalf("failed to read %s: %s", name, err)
+	}
+	return string(b)
+}
+
+func TestRotate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rotatelog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "logs", "gcs.log")
+
+	lf, err := Open(path, 8, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeAll(t, lf, "aaaa", "bbbb", "cccc", "dddd", "eeeeeeeeeeee", "ff")
+	if err := lf.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	files := Files(path, 3)
+	expected := []string{path + ".2", path + ".1", path}
+	if !reflect.DeepEqual(files, expected) {
+		t.Fatalf("expected files %v, got %v", expected, files)
+	}
+	for i, content := range []string{"ccccdddd", "eeeeeeeeeeee", "ff"} {
+		if got := readFile(t, files[i]); got != content {
+			t.Fatalf("expected %s to contain %q, got %q", files[i], content, got)
+		}
+	}
+	if _, err := lf.Write([]byte("g")); err != os.ErrClosed {
+		t.Fatalf("expected write after close to fail with %v, got %v", os.ErrClosed, err)
+	}
+}
+
+func TestReopenAppends(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rotatelog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "console.log")
+
+	for _, w := range []string{"abcde", "fgh", "ij"} {
+		lf, err := Open(path, 8, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		writeAll(t, lf, w)
+		lf.Close()
+	}
+	if got := readFile(t, path); got != "ij" {
+		t.Fatalf("expected a single file rotated in place to contain %q, got %q", "ij", got)
+	}
+	if files := Files(path, 1); len(files) != 1 {
+		t.Fatalf("expected one file, got %v", files)
+	}
+}
+
+func TestTail(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rotatelog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "gcs.log")
+
+	lf, err := Open(path, 4, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeAll(t, lf, "0123", "4567", "89")
+	lf.Close()
+
+	for _, tc := range []struct {
+		n         int64
+		expected  string
+		truncated bool
+	}{
+		{0, "0123456789", false},
+		{10, "0123456789", false},
+		{5, "56789", true},
+		{2, "89", true},
+	} {
+		b, truncated, err := Tail(path, 3, tc.n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != tc.expected || truncated != tc.truncated {
+			t.Fatalf("tail %d: expected %q (truncated %t), got %q (truncated %t)", tc.n, tc.expected, tc.truncated, b, truncated)
+		}
+	}
+}
+
+func TestTailMissing(t *testing.T) {
+	b, truncated, err := Tail(filepath.Join(os.TempDir(), "rotatelog-missing", "gcs.log"), 2, 0)
+	if err != nil || len(b) != 0 || truncated {
+		t.Fatalf("expected nothing for a missing log, got %q %t %v", b, truncated, err)
+	}
+}
@@ -0,0 +1,32 @@
+// +build windows
+
+package schema1
+
+import (
+	"github.com/Microsoft/go-winio/pkg/guid"
+)
+
+// ContainerProperties holds the properties for a container and the processes running in that container
+//
+// It is only built on Windows as its runtime ID is a Windows GUID, unlike the
+// documents in the rest of the package which are generated on any OS.
+type ContainerProperties struct {
+	ID                           string `json:"Id"`
+	State                        string
+	Name                         string
+	SystemType                   string
+	RuntimeOSType                string `json:"RuntimeOsType,omitempty"`
+	Owner                        string
+	SiloGUID                     string                              `json:"SiloGuid,omitempty"`
+	RuntimeID                    guid.GUID                           `json:"RuntimeId,omitempty"`
+	IsRuntimeTemplate            bool                                `json:",omitempty"`
+	RuntimeImagePath             string                              `json:",omitempty"`
+	Stopped                      bool                                `json:",omitempty"`
+	ExitType                     string                              `json:",omitempty"`
+	AreUpdatesPending            bool                                `json:",omitempty"`
+	ObRoot                       string                              `json:",omitempty"`
+	Statistics                   Statistics                          `json:",omitempty"`
+	ProcessList                  []ProcessListItem                   `json:",omitempty"`
+	MappedVirtualDiskControllers map[int]MappedVirtualDiskController `json:",omitempty"`
+	GuestConnectionInfo          GuestConnectionInfo                 `json:",omitempty"`
+}
@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"time"
 
-	"github.com/Microsoft/go-winio/pkg/guid"
 	hcsschema "github.com/Microsoft/hcsshim/internal/schema2"
 )
 
@@ -122,34 +121,13 @@ const (
 	PropertyTypeProcessList                    = "ProcessList"       // V1 and V2
 	PropertyTypeMappedVirtualDisk              = "MappedVirtualDisk" // Not supported in V2 schema call
 	PropertyTypeGuestConnection                = "GuestConnection"   // V1 and V2. Nil return from HCS before RS5
+	PropertyTypeMemory                         = "Memory"            // V2 only
 )
 
 type PropertyQuery struct {
 	PropertyTypes []PropertyType `json:",omitempty"`
 }
 
-// ContainerProperties holds the properties for a container and the processes running in that container
-type ContainerProperties struct {
-	ID                           string `json:"Id"`
-	State                        string
-	Name                         string
-	SystemType                   string
-	RuntimeOSType                string `json:"RuntimeOsType,omitempty"`
-	Owner                        string
-	SiloGUID                     string                              `json:"SiloGuid,omitempty"`
-	RuntimeID                    guid.GUID                           `json:"RuntimeId,omitempty"`
-	IsRuntimeTemplate            bool                                `json:",omitempty"`
-	RuntimeImagePath             string                              `json:",omitempty"`
-	Stopped                      bool                                `json:",omitempty"`
-	ExitType                     string                              `json:",omitempty"`
-	AreUpdatesPending            bool                                `json:",omitempty"`
-	ObRoot                       string                              `json:",omitempty"`
-	Statistics                   Statistics                          `json:",omitempty"`
-	ProcessList                  []ProcessListItem                   `json:",omitempty"`
-	MappedVirtualDiskControllers map[int]MappedVirtualDiskController `json:",omitempty"`
-	GuestConnectionInfo          GuestConnectionInfo                 `json:",omitempty"`
-}
-
 // MemoryStats holds the memory statistics for a container
 type MemoryStats struct {
 	UsageCommitBytes            uint64 `json:"MemoryUsageCommitBytes,omitempty"`
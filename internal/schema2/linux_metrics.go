@@ -0,0 +1,66 @@
+package hcsschema
+
+// LinuxMetrics are the cgroup statistics of a container in a Linux utility
+// VM, as the GCS reports them for a `Statistics` property query. They are the
+// JSON form of the cgroup v1 metrics of the guest, of which only the fields
+// used by the host are described here.
+type LinuxMetrics struct {
+	Pids *LinuxPidsStats `json:"pids,omitempty"`
+
+	CPU *LinuxCPUStats `json:"cpu,omitempty"`
+
+	Memory *LinuxMemoryStats `json:"memory,omitempty"`
+
+	Blkio *LinuxBlkioStats `json:"blkio,omitempty"`
+}
+
+type LinuxPidsStats struct {
+	Current uint64 `json:"current,omitempty"`
+
+	Limit uint64 `json:"limit,omitempty"`
+}
+
+type LinuxCPUStats struct {
+	Usage *LinuxCPUUsage `json:"usage,omitempty"`
+}
+
+// LinuxCPUUsage is the processor time used in nanoseconds.
+type LinuxCPUUsage struct {
+	Total uint64 `json:"total,omitempty"`
+
+	Kernel uint64 `json:"kernel,omitempty"`
+
+	User uint64 `json:"user,omitempty"`
+}
+
+type LinuxMemoryStats struct {
+	TotalInactiveFile uint64 `json:"total_inactive_file,omitempty"`
+
+	Usage *LinuxMemoryEntry `json:"usage,omitempty"`
+}
+
+type LinuxMemoryEntry struct {
+	Limit uint64 `json:"limit,omitempty"`
+
+	Usage uint64 `json:"usage,omitempty"`
+
+	Max uint64 `json:"max,omitempty"`
+
+	Failcnt uint64 `json:"failcnt,omitempty"`
+}
+
+type LinuxBlkioStats struct {
+	IoServiceBytesRecursive []LinuxBlkioEntry `json:"io_service_bytes_recursive,omitempty"`
+
+	IoServicedRecursive []LinuxBlkioEntry `json:"io_serviced_recursive,omitempty"`
+}
+
+type LinuxBlkioEntry struct {
+	Op string `json:"op,omitempty"`
+
+	Major uint64 `json:"major,omitempty"`
+
+	Minor uint64 `json:"minor,omitempty"`
+
+	Value uint64 `json:"value,omitempty"`
+}
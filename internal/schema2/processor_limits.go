@@ -0,0 +1,20 @@
+/*
+ * HCS API
+ *
+ * No description provided (generated by Swagger Codegen https://github.com/swagger-api/swagger-codegen)
+ *
+ * API version: 2.1
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package hcsschema
+
+type ProcessorLimits struct {
+	Limit uint64 `json:"Limit,omitempty"`
+
+	Weight uint64 `json:"Weight,omitempty"`
+
+	Reservation uint64 `json:"Reservation,omitempty"`
+
+	MaximumFrequencyMHz uint32 `json:"MaximumFrequencyMHz,omitempty"`
+}
@@ -43,4 +43,7 @@ type Properties struct {
 	SharedMemoryRegionInfo []SharedMemoryRegionInfo `json:"SharedMemoryRegionInfo,omitempty"`
 
 	GuestConnectionInfo *GuestConnectionInfo `json:"GuestConnectionInfo,omitempty"`
+
+	// Metrics are the cgroup statistics of a container in a Linux utility VM.
+	Metrics *LinuxMetrics `json:"LinuxMetrics,omitempty"`
 }
@@ -0,0 +1,18 @@
+/*
+ * HCS API
+ *
+ * No description provided (generated by Swagger Codegen https://github.com/swagger-api/swagger-codegen)
+ *
+ * API version: 2.1
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package hcsschema
+
+//  Notification data that is indicated when a compute system exits.
+type SystemExitStatus struct {
+	Status int32 `json:"Status,omitempty"`
+
+	//  One of "GracefulExit", "ForcedExit", "UnexpectedExit" or "Unknown".
+	ExitType string `json:"ExitType,omitempty"`
+}
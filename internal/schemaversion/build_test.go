@@ -0,0 +1,26 @@
+package schemaversion
+
+import (
+	"testing"
+
+	hcsschema "github.com/Microsoft/hcsshim/internal/schema2"
+	"github.com/Microsoft/hcsshim/osversion"
+)
+
+func TestDetermineSchemaVersionForBuild(t *testing.T) {
+	if sv := DetermineSchemaVersionForBuild(nil, osversion.RS5); !IsV21(sv) {
+		t.Fatalf("expected v2 on RS5")
+	}
+	if sv := DetermineSchemaVersionForBuild(SchemaV10(), osversion.RS5); !IsV10(sv) {
+		t.Fatalf("expected requested v1 on RS5")
+	}
+	if sv := DetermineSchemaVersionForBuild(&hcsschema.Version{}, osversion.RS5); !IsV21(sv) {
+		t.Fatalf("expected v2 for an unknown requested version on RS5")
+	}
+	if sv := DetermineSchemaVersionForBuild(nil, osversion.RS4); !IsV10(sv) {
+		t.Fatalf("expected v1 on RS4")
+	}
+	if sv := DetermineSchemaVersionForBuild(SchemaV21(), osversion.RS4); !IsV10(sv) {
+		t.Fatalf("expected v1 for requested v2 on RS4")
+	}
+}
@@ -0,0 +1,19 @@
+// +build windows
+
+package schemaversion
+
+import (
+	hcsschema "github.com/Microsoft/hcsshim/internal/schema2"
+	"github.com/Microsoft/hcsshim/osversion"
+)
+
+// isSupported determines if a given schema version is supported
+func IsSupported(sv *hcsschema.Version) error {
+	return IsSupportedOnBuild(sv, osversion.Get().Build)
+}
+
+// DetermineSchemaVersion works out what schema version to use based on build and
+// requested option.
+func DetermineSchemaVersion(requestedSV *hcsschema.Version) *hcsschema.Version {
+	return DetermineSchemaVersionForBuild(requestedSV, osversion.Get().Build)
+}
@@ -1,5 +1,3 @@
-// +build windows
-
 package schemaversion
 
 import (
@@ -21,13 +19,14 @@ func SchemaV21() *hcsschema.Version {
 	return &hcsschema.Version{Major: 2, Minor: 1}
 }
 
-// isSupported determines if a given schema version is supported
-func IsSupported(sv *hcsschema.Version) error {
+// IsSupportedOnBuild determines if a given schema version is supported on
+// Windows build `build`.
+func IsSupportedOnBuild(sv *hcsschema.Version, build uint16) error {
 	if IsV10(sv) {
 		return nil
 	}
 	if IsV21(sv) {
-		if osversion.Get().Build < osversion.RS5 {
+		if build < osversion.RS5 {
 			return fmt.Errorf("unsupported on this Windows build")
 		}
 		return nil
@@ -63,15 +62,15 @@ func String(sv *hcsschema.Version) string {
 	return string(b[:])
 }
 
-// DetermineSchemaVersion works out what schema version to use based on build and
-// requested option.
-func DetermineSchemaVersion(requestedSV *hcsschema.Version) *hcsschema.Version {
+// DetermineSchemaVersionForBuild works out what schema version to use on
+// Windows build `build` based on the requested option.
+func DetermineSchemaVersionForBuild(requestedSV *hcsschema.Version, build uint16) *hcsschema.Version {
 	sv := SchemaV10()
-	if osversion.Get().Build >= osversion.RS5 {
+	if build >= osversion.RS5 {
 		sv = SchemaV21()
 	}
 	if requestedSV != nil {
-		if err := IsSupported(requestedSV); err == nil {
+		if err := IsSupportedOnBuild(requestedSV, build); err == nil {
 			sv = requestedSV
 		} else {
 			logrus.WithField("schemaVersion", requestedSV).Warn("Ignoring unsupported requested schema version")
@@ -186,11 +186,405 @@ func (m *StacksResponse) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_StacksResponse proto.InternalMessageInfo
 
+type InventoryRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *InventoryRequest) Reset()      { *m = InventoryRequest{} }
+func (*InventoryRequest) ProtoMessage() {}
+func (*InventoryRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c7933dc6ffbb8784, []int{4}
+}
+func (m *InventoryRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *InventoryRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_InventoryRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *InventoryRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_InventoryRequest.Merge(m, src)
+}
+func (m *InventoryRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *InventoryRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_InventoryRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_InventoryRequest proto.InternalMessageInfo
+
+type InventoryResponse struct {
+	ID                   string    `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Os                   string    `protobuf:"bytes,2,opt,name=os,proto3" json:"os,omitempty"`
+	Devices              []*Device `protobuf:"bytes,3,rep,name=devices,proto3" json:"devices,omitempty"`
+	VpmemDevices         uint32    `protobuf:"varint,4,opt,name=vpmem_devices,json=vpmemDevices,proto3" json:"vpmem_devices,omitempty"`
+	VpmemMaxDevices      uint32    `protobuf:"varint,5,opt,name=vpmem_max_devices,json=vpmemMaxDevices,proto3" json:"vpmem_max_devices,omitempty"`
+	ScsiDevices          uint32    `protobuf:"varint,6,opt,name=scsi_devices,json=scsiDevices,proto3" json:"scsi_devices,omitempty"`
+	ScsiMaxDevices       uint32    `protobuf:"varint,7,opt,name=scsi_max_devices,json=scsiMaxDevices,proto3" json:"scsi_max_devices,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *InventoryResponse) Reset()      { *m = InventoryResponse{} }
+func (*InventoryResponse) ProtoMessage() {}
+func (*InventoryResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c7933dc6ffbb8784, []int{5}
+}
+func (m *InventoryResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *InventoryResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_InventoryResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *InventoryResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_InventoryResponse.Merge(m, src)
+}
+func (m *InventoryResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *InventoryResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_InventoryResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_InventoryResponse proto.InternalMessageInfo
+
+type Device struct {
+	Kind                 string   `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`
+	Location             string   `protobuf:"bytes,2,opt,name=location,proto3" json:"location,omitempty"`
+	HostPath             string   `protobuf:"bytes,3,opt,name=host_path,json=hostPath,proto3" json:"host_path,omitempty"`
+	UvmPath              string   `protobuf:"bytes,4,opt,name=uvm_path,json=uvmPath,proto3" json:"uvm_path,omitempty"`
+	NetworkNamespace     string   `protobuf:"bytes,5,opt,name=network_namespace,json=networkNamespace,proto3" json:"network_namespace,omitempty"`
+	RefCount             uint32   `protobuf:"varint,6,opt,name=ref_count,json=refCount,proto3" json:"ref_count,omitempty"`
+	Owners               []string `protobuf:"bytes,7,rep,name=owners,proto3" json:"owners,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Device) Reset()      { *m = Device{} }
+func (*Device) ProtoMessage() {}
+func (*Device) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c7933dc6ffbb8784, []int{6}
+}
+func (m *Device) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Device) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Device.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *Device) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Device.Merge(m, src)
+}
+func (m *Device) XXX_Size() int {
+	return m.Size()
+}
+func (m *Device) XXX_DiscardUnknown() {
+	xxx_messageInfo_Device.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Device proto.InternalMessageInfo
+
+type GuestLogRequest struct {
+	Source               string   `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
+	TailBytes            uint64   `protobuf:"varint,2,opt,name=tail_bytes,json=tailBytes,proto3" json:"tail_bytes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GuestLogRequest) Reset()      { *m = GuestLogRequest{} }
+func (*GuestLogRequest) ProtoMessage() {}
+func (*GuestLogRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c7933dc6ffbb8784, []int{7}
+}
+func (m *GuestLogRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *GuestLogRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_GuestLogRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *GuestLogRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GuestLogRequest.Merge(m, src)
+}
+func (m *GuestLogRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *GuestLogRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GuestLogRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GuestLogRequest proto.InternalMessageInfo
+
+type GuestLogResponse struct {
+	Data                 []byte   `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	Truncated            bool     `protobuf:"varint,2,opt,name=truncated,proto3" json:"truncated,omitempty"`
+	Files                []string `protobuf:"bytes,3,rep,name=files,proto3" json:"files,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GuestLogResponse) Reset()      { *m = GuestLogResponse{} }
+func (*GuestLogResponse) ProtoMessage() {}
+func (*GuestLogResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c7933dc6ffbb8784, []int{8}
+}
+func (m *GuestLogResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *GuestLogResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_GuestLogResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *GuestLogResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GuestLogResponse.Merge(m, src)
+}
+func (m *GuestLogResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *GuestLogResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GuestLogResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GuestLogResponse proto.InternalMessageInfo
+
+type HealthRequest struct {
+	PingTimeoutMs        uint32   `protobuf:"varint,1,opt,name=ping_timeout_ms,json=pingTimeoutMs,proto3" json:"ping_timeout_ms,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *HealthRequest) Reset()      { *m = HealthRequest{} }
+func (*HealthRequest) ProtoMessage() {}
+func (*HealthRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c7933dc6ffbb8784, []int{9}
+}
+func (m *HealthRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *HealthRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_HealthRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *HealthRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HealthRequest.Merge(m, src)
+}
+func (m *HealthRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *HealthRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_HealthRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HealthRequest proto.InternalMessageInfo
+
+type HealthResponse struct {
+	ID                   string           `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Goroutines           int64            `protobuf:"varint,2,opt,name=goroutines,proto3" json:"goroutines,omitempty"`
+	Handles              uint32           `protobuf:"varint,3,opt,name=handles,proto3" json:"handles,omitempty"`
+	PendingRpcs          uint32           `protobuf:"varint,4,opt,name=pending_rpcs,json=pendingRpcs,proto3" json:"pending_rpcs,omitempty"`
+	EventBacklog         uint32           `protobuf:"varint,5,opt,name=event_backlog,json=eventBacklog,proto3" json:"event_backlog,omitempty"`
+	UtilityVm            *UtilityVMHealth `protobuf:"bytes,6,opt,name=utility_vm,json=utilityVm,proto3" json:"utility_vm,omitempty"`
+	Tasks                []*TaskHealth    `protobuf:"bytes,7,rep,name=tasks,proto3" json:"tasks,omitempty"`
+	EventsPublished      uint64           `protobuf:"varint,8,opt,name=events_published,json=eventsPublished,proto3" json:"events_published,omitempty"`
+	EventsRetried        uint64           `protobuf:"varint,9,opt,name=events_retried,json=eventsRetried,proto3" json:"events_retried,omitempty"`
+	EventsDropped        uint64           `protobuf:"varint,10,opt,name=events_dropped,json=eventsDropped,proto3" json:"events_dropped,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *HealthResponse) Reset()      { *m = HealthResponse{} }
+func (*HealthResponse) ProtoMessage() {}
+func (*HealthResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c7933dc6ffbb8784, []int{10}
+}
+func (m *HealthResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *HealthResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_HealthResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *HealthResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HealthResponse.Merge(m, src)
+}
+func (m *HealthResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *HealthResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_HealthResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HealthResponse proto.InternalMessageInfo
+
+type UtilityVMHealth struct {
+	ID                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	State                string   `protobuf:"bytes,2,opt,name=state,proto3" json:"state,omitempty"`
+	ExitError            string   `protobuf:"bytes,3,opt,name=exit_error,json=exitError,proto3" json:"exit_error,omitempty"`
+	GuestResponsive      bool     `protobuf:"varint,4,opt,name=guest_responsive,json=guestResponsive,proto3" json:"guest_responsive,omitempty"`
+	PingMicroseconds     uint64   `protobuf:"varint,5,opt,name=ping_microseconds,json=pingMicroseconds,proto3" json:"ping_microseconds,omitempty"`
+	PingError            string   `protobuf:"bytes,6,opt,name=ping_error,json=pingError,proto3" json:"ping_error,omitempty"`
+	PendingGuestRpcs     uint32   `protobuf:"varint,7,opt,name=pending_guest_rpcs,json=pendingGuestRpcs,proto3" json:"pending_guest_rpcs,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UtilityVMHealth) Reset()      { *m = UtilityVMHealth{} }
+func (*UtilityVMHealth) ProtoMessage() {}
+func (*UtilityVMHealth) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c7933dc6ffbb8784, []int{11}
+}
+func (m *UtilityVMHealth) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *UtilityVMHealth) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_UtilityVMHealth.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *UtilityVMHealth) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UtilityVMHealth.Merge(m, src)
+}
+func (m *UtilityVMHealth) XXX_Size() int {
+	return m.Size()
+}
+func (m *UtilityVMHealth) XXX_DiscardUnknown() {
+	xxx_messageInfo_UtilityVMHealth.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UtilityVMHealth proto.InternalMessageInfo
+
+type TaskHealth struct {
+	ID                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	State                string   `protobuf:"bytes,2,opt,name=state,proto3" json:"state,omitempty"`
+	Execs                uint32   `protobuf:"varint,3,opt,name=execs,proto3" json:"execs,omitempty"`
+	LastError            string   `protobuf:"bytes,4,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`
+	LastErrorMethod      string   `protobuf:"bytes,5,opt,name=last_error_method,json=lastErrorMethod,proto3" json:"last_error_method,omitempty"`
+	LastErrorAt          string   `protobuf:"bytes,6,opt,name=last_error_at,json=lastErrorAt,proto3" json:"last_error_at,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TaskHealth) Reset()      { *m = TaskHealth{} }
+func (*TaskHealth) ProtoMessage() {}
+func (*TaskHealth) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c7933dc6ffbb8784, []int{12}
+}
+func (m *TaskHealth) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TaskHealth) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TaskHealth.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *TaskHealth) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TaskHealth.Merge(m, src)
+}
+func (m *TaskHealth) XXX_Size() int {
+	return m.Size()
+}
+func (m *TaskHealth) XXX_DiscardUnknown() {
+	xxx_messageInfo_TaskHealth.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TaskHealth proto.InternalMessageInfo
+
 func init() {
 	proto.RegisterType((*ExecProcessRequest)(nil), "containerd.runhcs.v1.diag.ExecProcessRequest")
 	proto.RegisterType((*ExecProcessResponse)(nil), "containerd.runhcs.v1.diag.ExecProcessResponse")
 	proto.RegisterType((*StacksRequest)(nil), "containerd.runhcs.v1.diag.StacksRequest")
 	proto.RegisterType((*StacksResponse)(nil), "containerd.runhcs.v1.diag.StacksResponse")
+	proto.RegisterType((*InventoryRequest)(nil), "containerd.runhcs.v1.diag.InventoryRequest")
+	proto.RegisterType((*InventoryResponse)(nil), "containerd.runhcs.v1.diag.InventoryResponse")
+	proto.RegisterType((*Device)(nil), "containerd.runhcs.v1.diag.Device")
+	proto.RegisterType((*GuestLogRequest)(nil), "containerd.runhcs.v1.diag.GuestLogRequest")
+	proto.RegisterType((*GuestLogResponse)(nil), "containerd.runhcs.v1.diag.GuestLogResponse")
+	proto.RegisterType((*HealthRequest)(nil), "containerd.runhcs.v1.diag.HealthRequest")
+	proto.RegisterType((*HealthResponse)(nil), "containerd.runhcs.v1.diag.HealthResponse")
+	proto.RegisterType((*UtilityVMHealth)(nil), "containerd.runhcs.v1.diag.UtilityVMHealth")
+	proto.RegisterType((*TaskHealth)(nil), "containerd.runhcs.v1.diag.TaskHealth")
 }
 
 func init() {
@@ -198,33 +592,80 @@ func init() {
 }
 
 var fileDescriptor_c7933dc6ffbb8784 = []byte{
-	// 409 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x52, 0x4d, 0x6f, 0xd4, 0x30,
-	0x14, 0x5c, 0xd3, 0xee, 0xb2, 0xfb, 0x80, 0x22, 0x99, 0x0a, 0x99, 0x45, 0x8a, 0x96, 0x9c, 0xc2,
-	0x01, 0x47, 0x94, 0x03, 0x07, 0xc4, 0x85, 0x0f, 0x09, 0x84, 0x90, 0x20, 0xbd, 0x20, 0x2e, 0x95,
-	0xeb, 0x98, 0xc4, 0x6a, 0xe3, 0x57, 0x6c, 0x07, 0x7a, 0xe4, 0xc7, 0xf0, 0x63, 0x7a, 0xe4, 0xc8,
-	0x09, 0xd1, 0xfc, 0x12, 0x64, 0x27, 0x59, 0xa9, 0x42, 0xa0, 0xed, 0x29, 0x6f, 0x26, 0x33, 0x99,
-	0xb1, 0xf3, 0xe0, 0x69, 0xa5, 0x7d, 0xdd, 0x1e, 0x72, 0x89, 0x4d, 0xfe, 0x56, 0x4b, 0x8b, 0x0e,
-	0x3f, 0xf9, 0xbc, 0x96, 0xce, 0xd5, 0xba, 0xc9, 0xb5, 0xf1, 0xca, 0x1a, 0x71, 0x9c, 0x07, 0x54,
-	0x6a, 0x51, 0xad, 0x07, 0x7e, 0x62, 0xd1, 0x23, 0xbd, 0x23, 0xd1, 0x78, 0xa1, 0x8d, 0xb2, 0x25,
-	0xb7, 0xad, 0xa9, 0xa5, 0xe3, 0x5f, 0x1e, 0xf2, 0x20, 0x58, 0xee, 0x56, 0x58, 0x61, 0x54, 0xe5,
-	0x61, 0xea, 0x0d, 0xe9, 0x77, 0x02, 0xf4, 0xe5, 0xa9, 0x92, 0xef, 0x2c, 0x4a, 0xe5, 0x5c, 0xa1,
-	0x3e, 0xb7, 0xca, 0x79, 0x4a, 0x61, 0x5b, 0xd8, 0xca, 0x31, 0xb2, 0xda, 0xca, 0x16, 0x45, 0x9c,
-	0x29, 0x83, 0xab, 0x5f, 0xd1, 0x1e, 0x95, 0xda, 0xb2, 0x2b, 0x2b, 0x92, 0x2d, 0x8a, 0x11, 0xd2,
-	0x25, 0xcc, 0xbd, 0xb2, 0x8d, 0x36, 0xe2, 0x98, 0x6d, 0xad, 0x48, 0x36, 0x2f, 0xd6, 0x98, 0xee,
-	0xc2, 0xd4, 0xf9, 0x52, 0x1b, 0xb6, 0x1d, 0x3d, 0x3d, 0xa0, 0xb7, 0x61, 0xe6, 0x7c, 0x89, 0xad,
-	0x67, 0xd3, 0x48, 0x0f, 0x68, 0xe0, 0x95, 0xb5, 0x6c, 0xb6, 0xe6, 0x95, 0xb5, 0xe9, 0x1e, 0xdc,
-	0xba, 0xd0, 0xd2, 0x9d, 0xa0, 0x71, 0x8a, 0xde, 0x85, 0x85, 0x3a, 0xd5, 0xfe, 0x40, 0x62, 0xa9,
-	0x18, 0x59, 0x91, 0x6c, 0x5a, 0xcc, 0x03, 0xf1, 0x1c, 0x4b, 0x95, 0xde, 0x84, 0x1b, 0xfb, 0x5e,
-	0xc8, 0xa3, 0xf1, 0x50, 0xe9, 0x1b, 0xd8, 0x19, 0x89, 0xc1, 0x1f, 0xe3, 0x02, 0x13, 0xcd, 0x31,
-	0x2e, 0x20, 0x7a, 0x0f, 0xae, 0x57, 0xc1, 0x72, 0x30, 0xbc, 0xed, 0xcf, 0x7b, 0x2d, 0x72, 0xfd,
-	0x27, 0xf6, 0x7e, 0x11, 0x98, 0xef, 0xd7, 0xba, 0x79, 0xa1, 0x45, 0x45, 0x11, 0x76, 0xc2, 0x33,
-	0x54, 0x7c, 0x6d, 0x5e, 0xa1, 0xf3, 0xf4, 0x01, 0xff, 0xe7, 0x9f, 0xe0, 0x7f, 0xdf, 0xf7, 0x92,
-	0x6f, 0x2a, 0x1f, 0x8a, 0x0b, 0x80, 0x10, 0xd8, 0x77, 0xa1, 0xd9, 0x7f, 0xdc, 0x17, 0xae, 0x60,
-	0x79, 0x7f, 0x03, 0x65, 0x1f, 0xf1, 0xec, 0xfd, 0xd9, 0x79, 0x32, 0xf9, 0x79, 0x9e, 0x4c, 0xbe,
-	0x75, 0x09, 0x39, 0xeb, 0x12, 0xf2, 0xa3, 0x4b, 0xc8, 0xef, 0x2e, 0x21, 0x1f, 0x1f, 0x5f, 0x6e,
-	0x47, 0x9f, 0x8c, 0xc3, 0x87, 0xc9, 0xe1, 0x2c, 0x6e, 0xdd, 0xa3, 0x3f, 0x01, 0x00, 0x00, 0xff,
-	0xff, 0x49, 0xfc, 0x83, 0xd9, 0xe7, 0x02, 0x00, 0x00,
+	// 1153 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x56, 0x4d, 0x6f, 0x1c, 0x45,
+	0x13, 0xce, 0xec, 0x97, 0x77, 0x6b, 0x3f, 0xdd, 0x6f, 0xf4, 0x6a, 0xb2, 0xc0, 0xe2, 0x0c, 0x0a,
+	0xda, 0x7c, 0xb0, 0x16, 0xe6, 0x90, 0x43, 0xc4, 0x01, 0xc7, 0x11, 0xb1, 0x60, 0x51, 0x98, 0x84,
+	0x08, 0xe5, 0x32, 0x6a, 0xcf, 0xb4, 0x67, 0x9a, 0xdd, 0x99, 0x1e, 0xba, 0x7b, 0x36, 0xf6, 0x0d,
+	0x89, 0xff, 0x90, 0x5f, 0xc0, 0x8d, 0x1f, 0xc1, 0x35, 0x47, 0xc4, 0x89, 0x13, 0x22, 0xfe, 0x25,
+	0xa8, 0x3f, 0x66, 0xbc, 0x36, 0xca, 0xe2, 0x9c, 0x76, 0xea, 0xe9, 0xa7, 0xab, 0xba, 0xba, 0x9e,
+	0xaa, 0x5e, 0xf8, 0x3c, 0xa6, 0x32, 0x29, 0x8e, 0x66, 0x21, 0x4b, 0x77, 0xe7, 0x34, 0xe4, 0x4c,
+	0xb0, 0x63, 0xb9, 0x9b, 0x84, 0x42, 0x24, 0x34, 0xdd, 0xa5, 0x99, 0x24, 0x3c, 0xc3, 0xcb, 0x5d,
+	0x65, 0x45, 0x14, 0xc7, 0xd5, 0xc7, 0x2c, 0xe7, 0x4c, 0x32, 0x74, 0x23, 0x64, 0x99, 0xc4, 0x34,
+	0x23, 0x3c, 0x9a, 0xf1, 0x22, 0x4b, 0x42, 0x31, 0x5b, 0x7d, 0x3a, 0x53, 0x84, 0xf1, 0xf5, 0x98,
+	0xc5, 0x4c, 0xb3, 0x76, 0xd5, 0x97, 0xd9, 0xe0, 0xfd, 0xe2, 0x00, 0x7a, 0x74, 0x42, 0xc2, 0x27,
+	0x9c, 0x85, 0x44, 0x08, 0x9f, 0xfc, 0x58, 0x10, 0x21, 0x11, 0x82, 0x06, 0xe6, 0xb1, 0x70, 0x9d,
+	0x9d, 0xfa, 0xb4, 0xe3, 0xeb, 0x6f, 0xe4, 0xc2, 0xd6, 0x4b, 0xc6, 0x17, 0x11, 0xe5, 0x6e, 0x6d,
+	0xc7, 0x99, 0x76, 0xfc, 0xd2, 0x44, 0x63, 0x68, 0x4b, 0xc2, 0x53, 0x9a, 0xe1, 0xa5, 0x5b, 0xdf,
+	0x71, 0xa6, 0x6d, 0xbf, 0xb2, 0xd1, 0x75, 0x68, 0x0a, 0x19, 0xd1, 0xcc, 0x6d, 0xe8, 0x3d, 0xc6,
+	0x40, 0xff, 0x87, 0x96, 0x90, 0x11, 0x2b, 0xa4, 0xdb, 0xd4, 0xb0, 0xb5, 0x2c, 0x4e, 0x38, 0x77,
+	0x5b, 0x15, 0x4e, 0x38, 0xf7, 0xf6, 0xe0, 0x7f, 0x17, 0x4e, 0x29, 0x72, 0x96, 0x09, 0x82, 0xde,
+	0x83, 0x0e, 0x39, 0xa1, 0x32, 0x08, 0x59, 0x44, 0x5c, 0x67, 0xc7, 0x99, 0x36, 0xfd, 0xb6, 0x02,
+	0x1e, 0xb2, 0x88, 0x78, 0x43, 0xe8, 0x3f, 0x95, 0x38, 0x5c, 0x94, 0x49, 0x79, 0x5f, 0xc1, 0xa0,
+	0x04, 0xec, 0x7e, 0x1d, 0x4e, 0x21, 0xae, 0x53, 0x86, 0x53, 0x16, 0xba, 0x09, 0xbd, 0x58, 0x6d,
+	0x09, 0xec, 0xaa, 0xc9, 0xb7, 0xab, 0x31, 0xe3, 0xc2, 0x43, 0x30, 0x3a, 0xcc, 0x56, 0x24, 0x93,
+	0x8c, 0x9f, 0x96, 0x01, 0x5e, 0xd5, 0x60, 0x7b, 0x0d, 0xac, 0x82, 0xd4, 0x68, 0x64, 0x02, 0xec,
+	0xb7, 0xce, 0xfe, 0xfa, 0xb0, 0x76, 0x78, 0xe0, 0xd7, 0x68, 0x84, 0x06, 0x50, 0x63, 0xa5, 0xeb,
+	0x1a, 0x13, 0xe8, 0x01, 0x6c, 0x45, 0x64, 0x45, 0x43, 0x22, 0xdc, 0xfa, 0x4e, 0x7d, 0xda, 0xdd,
+	0xbb, 0x39, 0x7b, 0x6b, 0x35, 0x67, 0x07, 0x9a, 0xe9, 0x97, 0x3b, 0xd0, 0x47, 0xd0, 0x5f, 0xe5,
+	0x29, 0x49, 0x83, 0xd2, 0x85, 0xba, 0xee, 0xbe, 0xdf, 0xd3, 0xe0, 0x81, 0x25, 0xdd, 0x81, 0x6d,
+	0x43, 0x4a, 0xf1, 0x49, 0x45, 0x6c, 0x6a, 0xe2, 0x50, 0x2f, 0xcc, 0xf1, 0x49, 0xc9, 0xbd, 0x09,
+	0x3d, 0x11, 0x0a, 0x5a, 0xd1, 0x5a, 0x9a, 0xd6, 0x55, 0x58, 0x49, 0x99, 0xc2, 0x48, 0x53, 0xd6,
+	0xbd, 0x6d, 0x69, 0xda, 0x40, 0xe1, 0xe7, 0xce, 0xbc, 0x3f, 0x1c, 0x68, 0x99, 0x6f, 0xa5, 0xac,
+	0x05, 0xcd, 0xec, 0x7d, 0xf8, 0xfa, 0x5b, 0xe9, 0x67, 0xc9, 0x42, 0x2c, 0x29, 0xcb, 0xec, 0x7d,
+	0x54, 0xb6, 0x2a, 0x71, 0xc2, 0x84, 0x0c, 0x72, 0x2c, 0x13, 0x2d, 0xae, 0x8e, 0xdf, 0x56, 0xc0,
+	0x13, 0x2c, 0x13, 0x74, 0x03, 0xda, 0xc5, 0x2a, 0x35, 0x6b, 0x46, 0x5f, 0x5b, 0xc5, 0x2a, 0xd5,
+	0x4b, 0x77, 0x61, 0x3b, 0x23, 0x52, 0x29, 0x34, 0xc8, 0x70, 0x4a, 0x44, 0x8e, 0x43, 0x62, 0xc5,
+	0x36, 0xb2, 0x0b, 0xdf, 0x94, 0xb8, 0x0a, 0xc2, 0xc9, 0x71, 0x10, 0xb2, 0x22, 0x93, 0x36, 0xd3,
+	0x36, 0x27, 0xc7, 0x0f, 0x95, 0xad, 0x44, 0xc2, 0x5e, 0x66, 0x84, 0xab, 0xe4, 0x54, 0x37, 0x58,
+	0xcb, 0x7b, 0x0c, 0xc3, 0x2f, 0x55, 0xd9, 0xbf, 0x66, 0x71, 0xd9, 0x36, 0x4a, 0x4f, 0xac, 0xe0,
+	0x21, 0xa9, 0xf4, 0xa4, 0x2d, 0xf4, 0x01, 0x80, 0xc4, 0x74, 0x19, 0x1c, 0x9d, 0x4a, 0x62, 0x4a,
+	0xde, 0xf0, 0x3b, 0x0a, 0xd9, 0x57, 0x80, 0xf7, 0x02, 0x46, 0xe7, 0x9e, 0xac, 0x6a, 0x10, 0x34,
+	0x22, 0x2c, 0xb1, 0x76, 0xd4, 0xf3, 0xf5, 0x37, 0x7a, 0x1f, 0x3a, 0x92, 0x17, 0x59, 0x88, 0x25,
+	0x89, 0xb4, 0x97, 0xb6, 0x7f, 0x0e, 0xa8, 0x4e, 0x3b, 0xa6, 0x4b, 0xab, 0x9e, 0x8e, 0x6f, 0x0c,
+	0xef, 0x3e, 0xf4, 0x1f, 0x13, 0xbc, 0x94, 0x49, 0x79, 0xc6, 0x8f, 0x61, 0x98, 0xd3, 0x2c, 0x0e,
+	0x24, 0x4d, 0x09, 0x2b, 0x64, 0x90, 0x1a, 0xf1, 0xf7, 0xfd, 0xbe, 0x82, 0x9f, 0x19, 0x74, 0x2e,
+	0xbc, 0x5f, 0xeb, 0x30, 0x28, 0x77, 0xfe, 0x87, 0x92, 0x27, 0x00, 0x31, 0xe3, 0xac, 0x90, 0x34,
+	0xb3, 0xe9, 0xd5, 0xfd, 0x35, 0x44, 0x4d, 0x8e, 0x04, 0x67, 0x91, 0x39, 0x9b, 0x0a, 0x55, 0x9a,
+	0x4a, 0x65, 0x39, 0xc9, 0x22, 0x75, 0x1e, 0x9e, 0x87, 0xa5, 0x6a, 0xbb, 0x16, 0xf3, 0xf3, 0x50,
+	0x2b, 0x9b, 0xa8, 0x96, 0x0a, 0x8e, 0x70, 0xb8, 0x58, 0xb2, 0xd8, 0x0a, 0xb6, 0xa7, 0xc1, 0x7d,
+	0x83, 0xa1, 0x43, 0x80, 0x42, 0xd2, 0x25, 0x95, 0xa7, 0xc1, 0x2a, 0xd5, 0x15, 0xec, 0xee, 0xdd,
+	0xd9, 0xd0, 0x3e, 0xdf, 0x19, 0xf2, 0xf3, 0xb9, 0xcd, 0xb0, 0x63, 0x77, 0x3f, 0x4f, 0xd1, 0x03,
+	0x68, 0x4a, 0x2c, 0x16, 0xa6, 0xda, 0xdd, 0xbd, 0x5b, 0x1b, 0xbc, 0x3c, 0xc3, 0x62, 0x61, 0x1d,
+	0x98, 0x3d, 0xe8, 0x36, 0x8c, 0xf4, 0xb9, 0x44, 0x90, 0x17, 0x47, 0x4b, 0x2a, 0x12, 0x12, 0xb9,
+	0x6d, 0x5d, 0xee, 0xa1, 0xc1, 0x9f, 0x94, 0x30, 0xba, 0x05, 0x03, 0x4b, 0xe5, 0x44, 0x72, 0x4a,
+	0x22, 0xb7, 0xa3, 0x89, 0x26, 0x5b, 0xe1, 0x1b, 0x70, 0x8d, 0x16, 0x71, 0x96, 0xe7, 0x24, 0x72,
+	0x61, 0x9d, 0x76, 0x60, 0x40, 0xef, 0xe7, 0x1a, 0x0c, 0x2f, 0x25, 0xf5, 0xd6, 0x72, 0xe9, 0x91,
+	0x8c, 0x25, 0xb1, 0xbd, 0x66, 0x0c, 0xa5, 0x51, 0x3d, 0x4b, 0x09, 0xe7, 0x8c, 0xdb, 0x4e, 0xd3,
+	0xd3, 0xf5, 0x91, 0x02, 0x54, 0x66, 0x66, 0x24, 0x72, 0xa3, 0x06, 0xba, 0x22, 0xba, 0x5a, 0x6d,
+	0x7f, 0xa8, 0x71, 0xbf, 0x82, 0x55, 0xeb, 0x69, 0x85, 0xa5, 0xfa, 0xfd, 0x22, 0x21, 0xcb, 0x22,
+	0x33, 0x66, 0x1a, 0xfe, 0x48, 0x2d, 0xcc, 0xd7, 0x70, 0x15, 0x56, 0x93, 0x4d, 0x58, 0x33, 0xf5,
+	0x3b, 0x0a, 0x31, 0x61, 0xef, 0x01, 0x2a, 0x05, 0x62, 0xc3, 0xe7, 0x61, 0x39, 0x65, 0x46, 0x76,
+	0x45, 0xf7, 0x8e, 0xd2, 0x8a, 0xf7, 0x9b, 0x03, 0x70, 0x5e, 0x94, 0x77, 0xbc, 0x80, 0xeb, 0xd0,
+	0x24, 0x27, 0x24, 0x2c, 0x35, 0x6a, 0x0c, 0x75, 0xbe, 0x25, 0x16, 0xe5, 0xb5, 0x98, 0x21, 0xd3,
+	0x51, 0x88, 0x39, 0xdf, 0x1d, 0xd8, 0x3e, 0x5f, 0x0e, 0x52, 0x22, 0x13, 0x16, 0xd9, 0x31, 0x33,
+	0xac, 0x58, 0x73, 0x0d, 0x23, 0x0f, 0xfa, 0x6b, 0x5c, 0x2c, 0x6d, 0xb6, 0xdd, 0x8a, 0xf7, 0x85,
+	0xdc, 0x7b, 0xd5, 0x80, 0xf6, 0xd3, 0x84, 0xa6, 0x07, 0x14, 0xc7, 0x88, 0xc1, 0x40, 0xfd, 0xaa,
+	0x97, 0xef, 0x30, 0x7b, 0xcc, 0x84, 0x44, 0x9f, 0x6c, 0x50, 0xe3, 0xbf, 0x9f, 0xf1, 0xf1, 0xec,
+	0xaa, 0x74, 0xdb, 0xe0, 0x18, 0x40, 0x05, 0x34, 0x4f, 0x1c, 0x9a, 0x6e, 0xd8, 0x7d, 0xe1, 0x65,
+	0x1d, 0xdf, 0xbe, 0x02, 0xd3, 0x86, 0xf8, 0x01, 0xfa, 0x2a, 0x44, 0xf5, 0x4c, 0xa2, 0xbb, 0x1b,
+	0xf6, 0x5e, 0x7e, 0x61, 0xc7, 0xf7, 0xae, 0x46, 0xb6, 0xb1, 0x62, 0xe8, 0xa9, 0x58, 0xe5, 0x6c,
+	0x45, 0x9b, 0x26, 0xc2, 0xa5, 0x51, 0x3e, 0xbe, 0x7b, 0x25, 0xee, 0xc5, 0x7b, 0xb3, 0xb2, 0xdb,
+	0x74, 0x6f, 0x17, 0x66, 0xf1, 0xf8, 0xf6, 0x15, 0x98, 0x26, 0xc4, 0xfe, 0xb7, 0xaf, 0xdf, 0x4c,
+	0xae, 0xfd, 0xf9, 0x66, 0x72, 0xed, 0xa7, 0xb3, 0x89, 0xf3, 0xfa, 0x6c, 0xe2, 0xfc, 0x7e, 0x36,
+	0x71, 0xfe, 0x3e, 0x9b, 0x38, 0x2f, 0xee, 0xbf, 0xdb, 0x5f, 0xc6, 0x07, 0xe5, 0xc7, 0xf7, 0xd7,
+	0x8e, 0x5a, 0xfa, 0x4f, 0xe0, 0x67, 0xff, 0x0c, 0x00, 0x0f, 0xb3, 0xf2, 0xb9, 0x76, 0x0a, 0x00,
+	0x00,
 }
 
 func (m *ExecProcessRequest) Marshal() (dAtA []byte, err error) {
@@ -377,220 +818,2775 @@ func (m *StacksResponse) MarshalTo(dAtA []byte) (int, error) {
 	return i, nil
 }
 
-func encodeVarintShimdiag(dAtA []byte, offset int, v uint64) int {
-	for v >= 1<<7 {
-		dAtA[offset] = uint8(v&0x7f | 0x80)
-		v >>= 7
-		offset++
+func (m *InventoryRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
 	}
-	dAtA[offset] = uint8(v)
-	return offset + 1
+	return dAtA[:n], nil
 }
-func (m *ExecProcessRequest) Size() (n int) {
-	if m == nil {
-		return 0
-	}
+
+func (m *InventoryRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
 	var l int
 	_ = l
-	if len(m.Args) > 0 {
-		for _, s := range m.Args {
-			l = len(s)
-			n += 1 + l + sovShimdiag(uint64(l))
-		}
-	}
-	l = len(m.Workdir)
-	if l > 0 {
-		n += 1 + l + sovShimdiag(uint64(l))
-	}
-	if m.Terminal {
-		n += 2
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
 	}
-	l = len(m.Stdin)
-	if l > 0 {
-		n += 1 + l + sovShimdiag(uint64(l))
+	return i, nil
+}
+
+func (m *InventoryResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
 	}
-	l = len(m.Stdout)
-	if l > 0 {
-		n += 1 + l + sovShimdiag(uint64(l))
-	}
-	l = len(m.Stderr)
-	if l > 0 {
-		n += 1 + l + sovShimdiag(uint64(l))
-	}
-	if m.XXX_unrecognized != nil {
-		n += len(m.XXX_unrecognized)
-	}
-	return n
+	return dAtA[:n], nil
 }
 
-func (m *ExecProcessResponse) Size() (n int) {
-	if m == nil {
-		return 0
-	}
+func (m *InventoryResponse) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
 	var l int
 	_ = l
-	if m.ExitCode != 0 {
-		n += 1 + sovShimdiag(uint64(m.ExitCode))
+	if len(m.ID) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(len(m.ID)))
+		i += copy(dAtA[i:], m.ID)
+	}
+	if len(m.Os) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(len(m.Os)))
+		i += copy(dAtA[i:], m.Os)
+	}
+	if len(m.Devices) > 0 {
+		for _, msg := range m.Devices {
+			dAtA[i] = 0x1a
+			i++
+			i = encodeVarintShimdiag(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if m.VpmemDevices != 0 {
+		dAtA[i] = 0x20
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(m.VpmemDevices))
+	}
+	if m.VpmemMaxDevices != 0 {
+		dAtA[i] = 0x28
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(m.VpmemMaxDevices))
+	}
+	if m.ScsiDevices != 0 {
+		dAtA[i] = 0x30
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(m.ScsiDevices))
+	}
+	if m.ScsiMaxDevices != 0 {
+		dAtA[i] = 0x38
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(m.ScsiMaxDevices))
 	}
 	if m.XXX_unrecognized != nil {
-		n += len(m.XXX_unrecognized)
+		i += copy(dAtA[i:], m.XXX_unrecognized)
 	}
-	return n
+	return i, nil
 }
 
-func (m *StacksRequest) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *Device) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *Device) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
 	var l int
 	_ = l
+	if len(m.Kind) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(len(m.Kind)))
+		i += copy(dAtA[i:], m.Kind)
+	}
+	if len(m.Location) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(len(m.Location)))
+		i += copy(dAtA[i:], m.Location)
+	}
+	if len(m.HostPath) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(len(m.HostPath)))
+		i += copy(dAtA[i:], m.HostPath)
+	}
+	if len(m.UvmPath) > 0 {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(len(m.UvmPath)))
+		i += copy(dAtA[i:], m.UvmPath)
+	}
+	if len(m.NetworkNamespace) > 0 {
+		dAtA[i] = 0x2a
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(len(m.NetworkNamespace)))
+		i += copy(dAtA[i:], m.NetworkNamespace)
+	}
+	if m.RefCount != 0 {
+		dAtA[i] = 0x30
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(m.RefCount))
+	}
+	if len(m.Owners) > 0 {
+		for _, s := range m.Owners {
+			dAtA[i] = 0x3a
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
 	if m.XXX_unrecognized != nil {
-		n += len(m.XXX_unrecognized)
+		i += copy(dAtA[i:], m.XXX_unrecognized)
 	}
-	return n
+	return i, nil
 }
 
-func (m *StacksResponse) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *GuestLogRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *GuestLogRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
 	var l int
 	_ = l
-	l = len(m.Stacks)
-	if l > 0 {
-		n += 1 + l + sovShimdiag(uint64(l))
+	if len(m.Source) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(len(m.Source)))
+		i += copy(dAtA[i:], m.Source)
 	}
-	l = len(m.GuestStacks)
-	if l > 0 {
-		n += 1 + l + sovShimdiag(uint64(l))
+	if m.TailBytes != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(m.TailBytes))
 	}
 	if m.XXX_unrecognized != nil {
-		n += len(m.XXX_unrecognized)
+		i += copy(dAtA[i:], m.XXX_unrecognized)
 	}
-	return n
+	return i, nil
 }
 
-func sovShimdiag(x uint64) (n int) {
-	for {
-		n++
-		x >>= 7
-		if x == 0 {
-			break
-		}
-	}
-	return n
-}
-func sozShimdiag(x uint64) (n int) {
-	return sovShimdiag(uint64((x << 1) ^ uint64((int64(x) >> 63))))
-}
-func (this *ExecProcessRequest) String() string {
-	if this == nil {
-		return "nil"
+func (m *GuestLogResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
 	}
-	s := strings.Join([]string{`&ExecProcessRequest{`,
-		`Args:` + fmt.Sprintf("%v", this.Args) + `,`,
-		`Workdir:` + fmt.Sprintf("%v", this.Workdir) + `,`,
-		`Terminal:` + fmt.Sprintf("%v", this.Terminal) + `,`,
-		`Stdin:` + fmt.Sprintf("%v", this.Stdin) + `,`,
-		`Stdout:` + fmt.Sprintf("%v", this.Stdout) + `,`,
-		`Stderr:` + fmt.Sprintf("%v", this.Stderr) + `,`,
-		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
-		`}`,
-	}, "")
-	return s
+	return dAtA[:n], nil
 }
-func (this *ExecProcessResponse) String() string {
-	if this == nil {
-		return "nil"
+
+func (m *GuestLogResponse) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Data) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(len(m.Data)))
+		i += copy(dAtA[i:], m.Data)
 	}
-	s := strings.Join([]string{`&ExecProcessResponse{`,
-		`ExitCode:` + fmt.Sprintf("%v", this.ExitCode) + `,`,
-		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func (this *StacksRequest) String() string {
-	if this == nil {
-		return "nil"
+	if m.Truncated {
+		dAtA[i] = 0x10
+		i++
+		if m.Truncated {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
 	}
-	s := strings.Join([]string{`&StacksRequest{`,
-		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func (this *StacksResponse) String() string {
-	if this == nil {
-		return "nil"
+	if len(m.Files) > 0 {
+		for _, s := range m.Files {
+			dAtA[i] = 0x1a
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
 	}
-	s := strings.Join([]string{`&StacksResponse{`,
-		`Stacks:` + fmt.Sprintf("%v", this.Stacks) + `,`,
-		`GuestStacks:` + fmt.Sprintf("%v", this.GuestStacks) + `,`,
-		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func valueToStringShimdiag(v interface{}) string {
-	rv := reflect.ValueOf(v)
-	if rv.IsNil() {
-		return "nil"
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
 	}
-	pv := reflect.Indirect(rv).Interface()
-	return fmt.Sprintf("*%v", pv)
+	return i, nil
 }
 
-type ShimDiagService interface {
-	DiagExecInHost(ctx context.Context, req *ExecProcessRequest) (*ExecProcessResponse, error)
-	DiagStacks(ctx context.Context, req *StacksRequest) (*StacksResponse, error)
+func (m *HealthRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
 }
 
-func RegisterShimDiagService(srv *github_com_containerd_ttrpc.Server, svc ShimDiagService) {
-	srv.Register("containerd.runhcs.v1.diag.ShimDiag", map[string]github_com_containerd_ttrpc.Method{
-		"DiagExecInHost": func(ctx context.Context, unmarshal func(interface{}) error) (interface{}, error) {
-			var req ExecProcessRequest
-			if err := unmarshal(&req); err != nil {
-				return nil, err
-			}
-			return svc.DiagExecInHost(ctx, &req)
-		},
-		"DiagStacks": func(ctx context.Context, unmarshal func(interface{}) error) (interface{}, error) {
-			var req StacksRequest
-			if err := unmarshal(&req); err != nil {
-				return nil, err
-			}
-			return svc.DiagStacks(ctx, &req)
-		},
-	})
+func (m *HealthRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.PingTimeoutMs != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(m.PingTimeoutMs))
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
 }
 
-type shimDiagClient struct {
-	client *github_com_containerd_ttrpc.Client
+func (m *HealthResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
 }
 
-func NewShimDiagClient(client *github_com_containerd_ttrpc.Client) ShimDiagService {
-	return &shimDiagClient{
-		client: client,
+func (m *HealthResponse) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.ID) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(len(m.ID)))
+		i += copy(dAtA[i:], m.ID)
+	}
+	if m.Goroutines != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(m.Goroutines))
+	}
+	if m.Handles != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(m.Handles))
+	}
+	if m.PendingRpcs != 0 {
+		dAtA[i] = 0x20
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(m.PendingRpcs))
+	}
+	if m.EventBacklog != 0 {
+		dAtA[i] = 0x28
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(m.EventBacklog))
+	}
+	if m.UtilityVm != nil {
+		dAtA[i] = 0x32
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(m.UtilityVm.Size()))
+		n1, err := m.UtilityVm.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n1
+	}
+	if len(m.Tasks) > 0 {
+		for _, msg := range m.Tasks {
+			dAtA[i] = 0x3a
+			i++
+			i = encodeVarintShimdiag(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if m.EventsPublished != 0 {
+		dAtA[i] = 0x40
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(m.EventsPublished))
+	}
+	if m.EventsRetried != 0 {
+		dAtA[i] = 0x48
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(m.EventsRetried))
 	}
+	if m.EventsDropped != 0 {
+		dAtA[i] = 0x50
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(m.EventsDropped))
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
 }
 
-func (c *shimDiagClient) DiagExecInHost(ctx context.Context, req *ExecProcessRequest) (*ExecProcessResponse, error) {
-	var resp ExecProcessResponse
-	if err := c.client.Call(ctx, "containerd.runhcs.v1.diag.ShimDiag", "DiagExecInHost", req, &resp); err != nil {
+func (m *UtilityVMHealth) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *UtilityVMHealth) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.ID) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(len(m.ID)))
+		i += copy(dAtA[i:], m.ID)
+	}
+	if len(m.State) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(len(m.State)))
+		i += copy(dAtA[i:], m.State)
+	}
+	if len(m.ExitError) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(len(m.ExitError)))
+		i += copy(dAtA[i:], m.ExitError)
+	}
+	if m.GuestResponsive {
+		dAtA[i] = 0x20
+		i++
+		if m.GuestResponsive {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if m.PingMicroseconds != 0 {
+		dAtA[i] = 0x28
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(m.PingMicroseconds))
+	}
+	if len(m.PingError) > 0 {
+		dAtA[i] = 0x32
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(len(m.PingError)))
+		i += copy(dAtA[i:], m.PingError)
+	}
+	if m.PendingGuestRpcs != 0 {
+		dAtA[i] = 0x38
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(m.PendingGuestRpcs))
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func (m *TaskHealth) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
 		return nil, err
 	}
-	return &resp, nil
-}
+	return dAtA[:n], nil
+}
+
+func (m *TaskHealth) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.ID) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(len(m.ID)))
+		i += copy(dAtA[i:], m.ID)
+	}
+	if len(m.State) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(len(m.State)))
+		i += copy(dAtA[i:], m.State)
+	}
+	if m.Execs != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(m.Execs))
+	}
+	if len(m.LastError) > 0 {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(len(m.LastError)))
+		i += copy(dAtA[i:], m.LastError)
+	}
+	if len(m.LastErrorMethod) > 0 {
+		dAtA[i] = 0x2a
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(len(m.LastErrorMethod)))
+		i += copy(dAtA[i:], m.LastErrorMethod)
+	}
+	if len(m.LastErrorAt) > 0 {
+		dAtA[i] = 0x32
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(len(m.LastErrorAt)))
+		i += copy(dAtA[i:], m.LastErrorAt)
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func encodeVarintShimdiag(dAtA []byte, offset int, v uint64) int {
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return offset + 1
+}
+func (m *ExecProcessRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Args) > 0 {
+		for _, s := range m.Args {
+			l = len(s)
+			n += 1 + l + sovShimdiag(uint64(l))
+		}
+	}
+	l = len(m.Workdir)
+	if l > 0 {
+		n += 1 + l + sovShimdiag(uint64(l))
+	}
+	if m.Terminal {
+		n += 2
+	}
+	l = len(m.Stdin)
+	if l > 0 {
+		n += 1 + l + sovShimdiag(uint64(l))
+	}
+	l = len(m.Stdout)
+	if l > 0 {
+		n += 1 + l + sovShimdiag(uint64(l))
+	}
+	l = len(m.Stderr)
+	if l > 0 {
+		n += 1 + l + sovShimdiag(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *ExecProcessResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.ExitCode != 0 {
+		n += 1 + sovShimdiag(uint64(m.ExitCode))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *StacksRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *StacksResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Stacks)
+	if l > 0 {
+		n += 1 + l + sovShimdiag(uint64(l))
+	}
+	l = len(m.GuestStacks)
+	if l > 0 {
+		n += 1 + l + sovShimdiag(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *InventoryRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *InventoryResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.ID)
+	if l > 0 {
+		n += 1 + l + sovShimdiag(uint64(l))
+	}
+	l = len(m.Os)
+	if l > 0 {
+		n += 1 + l + sovShimdiag(uint64(l))
+	}
+	if len(m.Devices) > 0 {
+		for _, e := range m.Devices {
+			l = e.Size()
+			n += 1 + l + sovShimdiag(uint64(l))
+		}
+	}
+	if m.VpmemDevices != 0 {
+		n += 1 + sovShimdiag(uint64(m.VpmemDevices))
+	}
+	if m.VpmemMaxDevices != 0 {
+		n += 1 + sovShimdiag(uint64(m.VpmemMaxDevices))
+	}
+	if m.ScsiDevices != 0 {
+		n += 1 + sovShimdiag(uint64(m.ScsiDevices))
+	}
+	if m.ScsiMaxDevices != 0 {
+		n += 1 + sovShimdiag(uint64(m.ScsiMaxDevices))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *Device) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Kind)
+	if l > 0 {
+		n += 1 + l + sovShimdiag(uint64(l))
+	}
+	l = len(m.Location)
+	if l > 0 {
+		n += 1 + l + sovShimdiag(uint64(l))
+	}
+	l = len(m.HostPath)
+	if l > 0 {
+		n += 1 + l + sovShimdiag(uint64(l))
+	}
+	l = len(m.UvmPath)
+	if l > 0 {
+		n += 1 + l + sovShimdiag(uint64(l))
+	}
+	l = len(m.NetworkNamespace)
+	if l > 0 {
+		n += 1 + l + sovShimdiag(uint64(l))
+	}
+	if m.RefCount != 0 {
+		n += 1 + sovShimdiag(uint64(m.RefCount))
+	}
+	if len(m.Owners) > 0 {
+		for _, s := range m.Owners {
+			l = len(s)
+			n += 1 + l + sovShimdiag(uint64(l))
+		}
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *GuestLogRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Source)
+	if l > 0 {
+		n += 1 + l + sovShimdiag(uint64(l))
+	}
+	if m.TailBytes != 0 {
+		n += 1 + sovShimdiag(uint64(m.TailBytes))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *GuestLogResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Data)
+	if l > 0 {
+		n += 1 + l + sovShimdiag(uint64(l))
+	}
+	if m.Truncated {
+		n += 2
+	}
+	if len(m.Files) > 0 {
+		for _, s := range m.Files {
+			l = len(s)
+			n += 1 + l + sovShimdiag(uint64(l))
+		}
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *HealthRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.PingTimeoutMs != 0 {
+		n += 1 + sovShimdiag(uint64(m.PingTimeoutMs))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *HealthResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.ID)
+	if l > 0 {
+		n += 1 + l + sovShimdiag(uint64(l))
+	}
+	if m.Goroutines != 0 {
+		n += 1 + sovShimdiag(uint64(m.Goroutines))
+	}
+	if m.Handles != 0 {
+		n += 1 + sovShimdiag(uint64(m.Handles))
+	}
+	if m.PendingRpcs != 0 {
+		n += 1 + sovShimdiag(uint64(m.PendingRpcs))
+	}
+	if m.EventBacklog != 0 {
+		n += 1 + sovShimdiag(uint64(m.EventBacklog))
+	}
+	if m.UtilityVm != nil {
+		l = m.UtilityVm.Size()
+		n += 1 + l + sovShimdiag(uint64(l))
+	}
+	if len(m.Tasks) > 0 {
+		for _, e := range m.Tasks {
+			l = e.Size()
+			n += 1 + l + sovShimdiag(uint64(l))
+		}
+	}
+	if m.EventsPublished != 0 {
+		n += 1 + sovShimdiag(uint64(m.EventsPublished))
+	}
+	if m.EventsRetried != 0 {
+		n += 1 + sovShimdiag(uint64(m.EventsRetried))
+	}
+	if m.EventsDropped != 0 {
+		n += 1 + sovShimdiag(uint64(m.EventsDropped))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *UtilityVMHealth) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.ID)
+	if l > 0 {
+		n += 1 + l + sovShimdiag(uint64(l))
+	}
+	l = len(m.State)
+	if l > 0 {
+		n += 1 + l + sovShimdiag(uint64(l))
+	}
+	l = len(m.ExitError)
+	if l > 0 {
+		n += 1 + l + sovShimdiag(uint64(l))
+	}
+	if m.GuestResponsive {
+		n += 2
+	}
+	if m.PingMicroseconds != 0 {
+		n += 1 + sovShimdiag(uint64(m.PingMicroseconds))
+	}
+	l = len(m.PingError)
+	if l > 0 {
+		n += 1 + l + sovShimdiag(uint64(l))
+	}
+	if m.PendingGuestRpcs != 0 {
+		n += 1 + sovShimdiag(uint64(m.PendingGuestRpcs))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *TaskHealth) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.ID)
+	if l > 0 {
+		n += 1 + l + sovShimdiag(uint64(l))
+	}
+	l = len(m.State)
+	if l > 0 {
+		n += 1 + l + sovShimdiag(uint64(l))
+	}
+	if m.Execs != 0 {
+		n += 1 + sovShimdiag(uint64(m.Execs))
+	}
+	l = len(m.LastError)
+	if l > 0 {
+		n += 1 + l + sovShimdiag(uint64(l))
+	}
+	l = len(m.LastErrorMethod)
+	if l > 0 {
+		n += 1 + l + sovShimdiag(uint64(l))
+	}
+	l = len(m.LastErrorAt)
+	if l > 0 {
+		n += 1 + l + sovShimdiag(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func sovShimdiag(x uint64) (n int) {
+	for {
+		n++
+		x >>= 7
+		if x == 0 {
+			break
+		}
+	}
+	return n
+}
+func sozShimdiag(x uint64) (n int) {
+	return sovShimdiag(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (this *ExecProcessRequest) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&ExecProcessRequest{`,
+		`Args:` + fmt.Sprintf("%v", this.Args) + `,`,
+		`Workdir:` + fmt.Sprintf("%v", this.Workdir) + `,`,
+		`Terminal:` + fmt.Sprintf("%v", this.Terminal) + `,`,
+		`Stdin:` + fmt.Sprintf("%v", this.Stdin) + `,`,
+		`Stdout:` + fmt.Sprintf("%v", this.Stdout) + `,`,
+		`Stderr:` + fmt.Sprintf("%v", this.Stderr) + `,`,
+		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *ExecProcessResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&ExecProcessResponse{`,
+		`ExitCode:` + fmt.Sprintf("%v", this.ExitCode) + `,`,
+		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *StacksRequest) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&StacksRequest{`,
+		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *StacksResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&StacksResponse{`,
+		`Stacks:` + fmt.Sprintf("%v", this.Stacks) + `,`,
+		`GuestStacks:` + fmt.Sprintf("%v", this.GuestStacks) + `,`,
+		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *InventoryRequest) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&InventoryRequest{`,
+		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *InventoryResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&InventoryResponse{`,
+		`ID:` + fmt.Sprintf("%v", this.ID) + `,`,
+		`Os:` + fmt.Sprintf("%v", this.Os) + `,`,
+		`Devices:` + strings.Replace(fmt.Sprintf("%v", this.Devices), "Device", "Device", 1) + `,`,
+		`VpmemDevices:` + fmt.Sprintf("%v", this.VpmemDevices) + `,`,
+		`VpmemMaxDevices:` + fmt.Sprintf("%v", this.VpmemMaxDevices) + `,`,
+		`ScsiDevices:` + fmt.Sprintf("%v", this.ScsiDevices) + `,`,
+		`ScsiMaxDevices:` + fmt.Sprintf("%v", this.ScsiMaxDevices) + `,`,
+		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Device) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Device{`,
+		`Kind:` + fmt.Sprintf("%v", this.Kind) + `,`,
+		`Location:` + fmt.Sprintf("%v", this.Location) + `,`,
+		`HostPath:` + fmt.Sprintf("%v", this.HostPath) + `,`,
+		`UvmPath:` + fmt.Sprintf("%v", this.UvmPath) + `,`,
+		`NetworkNamespace:` + fmt.Sprintf("%v", this.NetworkNamespace) + `,`,
+		`RefCount:` + fmt.Sprintf("%v", this.RefCount) + `,`,
+		`Owners:` + fmt.Sprintf("%v", this.Owners) + `,`,
+		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *GuestLogRequest) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&GuestLogRequest{`,
+		`Source:` + fmt.Sprintf("%v", this.Source) + `,`,
+		`TailBytes:` + fmt.Sprintf("%v", this.TailBytes) + `,`,
+		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *GuestLogResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&GuestLogResponse{`,
+		`Data:` + fmt.Sprintf("%v", this.Data) + `,`,
+		`Truncated:` + fmt.Sprintf("%v", this.Truncated) + `,`,
+		`Files:` + fmt.Sprintf("%v", this.Files) + `,`,
+		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *HealthRequest) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&HealthRequest{`,
+		`PingTimeoutMs:` + fmt.Sprintf("%v", this.PingTimeoutMs) + `,`,
+		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *HealthResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&HealthResponse{`,
+		`ID:` + fmt.Sprintf("%v", this.ID) + `,`,
+		`Goroutines:` + fmt.Sprintf("%v", this.Goroutines) + `,`,
+		`Handles:` + fmt.Sprintf("%v", this.Handles) + `,`,
+		`PendingRpcs:` + fmt.Sprintf("%v", this.PendingRpcs) + `,`,
+		`EventBacklog:` + fmt.Sprintf("%v", this.EventBacklog) + `,`,
+		`UtilityVm:` + strings.Replace(fmt.Sprintf("%v", this.UtilityVm), "UtilityVMHealth", "UtilityVMHealth", 1) + `,`,
+		`Tasks:` + strings.Replace(fmt.Sprintf("%v", this.Tasks), "TaskHealth", "TaskHealth", 1) + `,`,
+		`EventsPublished:` + fmt.Sprintf("%v", this.EventsPublished) + `,`,
+		`EventsRetried:` + fmt.Sprintf("%v", this.EventsRetried) + `,`,
+		`EventsDropped:` + fmt.Sprintf("%v", this.EventsDropped) + `,`,
+		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *UtilityVMHealth) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&UtilityVMHealth{`,
+		`ID:` + fmt.Sprintf("%v", this.ID) + `,`,
+		`State:` + fmt.Sprintf("%v", this.State) + `,`,
+		`ExitError:` + fmt.Sprintf("%v", this.ExitError) + `,`,
+		`GuestResponsive:` + fmt.Sprintf("%v", this.GuestResponsive) + `,`,
+		`PingMicroseconds:` + fmt.Sprintf("%v", this.PingMicroseconds) + `,`,
+		`PingError:` + fmt.Sprintf("%v", this.PingError) + `,`,
+		`PendingGuestRpcs:` + fmt.Sprintf("%v", this.PendingGuestRpcs) + `,`,
+		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *TaskHealth) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&TaskHealth{`,
+		`ID:` + fmt.Sprintf("%v", this.ID) + `,`,
+		`State:` + fmt.Sprintf("%v", this.State) + `,`,
+		`Execs:` + fmt.Sprintf("%v", this.Execs) + `,`,
+		`LastError:` + fmt.Sprintf("%v", this.LastError) + `,`,
+		`LastErrorMethod:` + fmt.Sprintf("%v", this.LastErrorMethod) + `,`,
+		`LastErrorAt:` + fmt.Sprintf("%v", this.LastErrorAt) + `,`,
+		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func valueToStringShimdiag(v interface{}) string {
+	rv := reflect.ValueOf(v)
+	if rv.IsNil() {
+		return "nil"
+	}
+	pv := reflect.Indirect(rv).Interface()
+	return fmt.Sprintf("*%v", pv)
+}
+
+type ShimDiagService interface {
+	DiagExecInHost(ctx context.Context, req *ExecProcessRequest) (*ExecProcessResponse, error)
+	DiagStacks(ctx context.Context, req *StacksRequest) (*StacksResponse, error)
+	DiagInventory(ctx context.Context, req *InventoryRequest) (*InventoryResponse, error)
+	DiagGuestLog(ctx context.Context, req *GuestLogRequest) (*GuestLogResponse, error)
+	DiagHealth(ctx context.Context, req *HealthRequest) (*HealthResponse, error)
+}
+
+func RegisterShimDiagService(srv *github_com_containerd_ttrpc.Server, svc ShimDiagService) {
+	srv.Register("containerd.runhcs.v1.diag.ShimDiag", map[string]github_com_containerd_ttrpc.Method{
+		"DiagExecInHost": func(ctx context.Context, unmarshal func(interface{}) error) (interface{}, error) {
+			var req ExecProcessRequest
+			if err := unmarshal(&req); err != nil {
+				return nil, err
+			}
+			return svc.DiagExecInHost(ctx, &req)
+		},
+		"DiagStacks": func(ctx context.Context, unmarshal func(interface{}) error) (interface{}, error) {
+			var req StacksRequest
+			if err := unmarshal(&req); err != nil {
+				return nil, err
+			}
+			return svc.DiagStacks(ctx, &req)
+		},
+		"DiagInventory": func(ctx context.Context, unmarshal func(interface{}) error) (interface{}, error) {
+			var req InventoryRequest
+			if err := unmarshal(&req); err != nil {
+				return nil, err
+			}
+			return svc.DiagInventory(ctx, &req)
+		},
+		"DiagGuestLog": func(ctx context.Context, unmarshal func(interface{}) error) (interface{}, error) {
+			var req GuestLogRequest
+			if err := unmarshal(&req); err != nil {
+				return nil, err
+			}
+			return svc.DiagGuestLog(ctx, &req)
+		},
+		"DiagHealth": func(ctx context.Context, unmarshal func(interface{}) error) (interface{}, error) {
+			var req HealthRequest
+			if err := unmarshal(&req); err != nil {
+				return nil, err
+			}
+			return svc.DiagHealth(ctx, &req)
+		},
+	})
+}
+
+type shimDiagClient struct {
+	client *github_com_containerd_ttrpc.Client
+}
+
+func NewShimDiagClient(client *github_com_containerd_ttrpc.Client) ShimDiagService {
+	return &shimDiagClient{
+		client: client,
+	}
+}
+
+func (c *shimDiagClient) DiagExecInHost(ctx context.Context, req *ExecProcessRequest) (*ExecProcessResponse, error) {
+	var resp ExecProcessResponse
+	if err := c.client.Call(ctx, "containerd.runhcs.v1.diag.ShimDiag", "DiagExecInHost", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *shimDiagClient) DiagStacks(ctx context.Context, req *StacksRequest) (*StacksResponse, error) {
+	var resp StacksResponse
+	if err := c.client.Call(ctx, "containerd.runhcs.v1.diag.ShimDiag", "DiagStacks", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *shimDiagClient) DiagInventory(ctx context.Context, req *InventoryRequest) (*InventoryResponse, error) {
+	var resp InventoryResponse
+	if err := c.client.Call(ctx, "containerd.runhcs.v1.diag.ShimDiag", "DiagInventory", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *shimDiagClient) DiagGuestLog(ctx context.Context, req *GuestLogRequest) (*GuestLogResponse, error) {
+	var resp GuestLogResponse
+	if err := c.client.Call(ctx, "containerd.runhcs.v1.diag.ShimDiag", "DiagGuestLog", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *shimDiagClient) DiagHealth(ctx context.Context, req *HealthRequest) (*HealthResponse, error) {
+	var resp HealthResponse
+	if err := c.client.Call(ctx, "containerd.runhcs.v1.diag.ShimDiag", "DiagHealth", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+func (m *ExecProcessRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowShimdiag
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ExecProcessRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ExecProcessRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Args", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Args = append(m.Args, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Workdir", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Workdir = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Terminal", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Terminal = bool(v != 0)
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Stdin", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Stdin = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Stdout", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Stdout = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Stderr", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Stderr = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipShimdiag(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ExecProcessResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowShimdiag
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ExecProcessResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ExecProcessResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ExitCode", wireType)
+			}
+			m.ExitCode = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ExitCode |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipShimdiag(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *StacksRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowShimdiag
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: StacksRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: StacksRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipShimdiag(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *StacksResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowShimdiag
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: StacksResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: StacksResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Stacks", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Stacks = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field GuestStacks", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.GuestStacks = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipShimdiag(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *InventoryRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowShimdiag
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: InventoryRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: InventoryRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipShimdiag(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *InventoryResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowShimdiag
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: InventoryResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: InventoryResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ID", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Os", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Os = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Devices", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Devices = append(m.Devices, &Device{})
+			if err := m.Devices[len(m.Devices)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field VpmemDevices", wireType)
+			}
+			m.VpmemDevices = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.VpmemDevices |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field VpmemMaxDevices", wireType)
+			}
+			m.VpmemMaxDevices = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.VpmemMaxDevices |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ScsiDevices", wireType)
+			}
+			m.ScsiDevices = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ScsiDevices |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ScsiMaxDevices", wireType)
+			}
+			m.ScsiMaxDevices = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ScsiMaxDevices |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipShimdiag(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Device) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowShimdiag
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Device: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Device: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Kind", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Kind = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Location", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Location = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field HostPath", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.HostPath = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field UvmPath", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.UvmPath = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NetworkNamespace", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.NetworkNamespace = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RefCount", wireType)
+			}
+			m.RefCount = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.RefCount |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Owners", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Owners = append(m.Owners, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipShimdiag(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *GuestLogRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowShimdiag
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: GuestLogRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: GuestLogRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Source", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Source = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TailBytes", wireType)
+			}
+			m.TailBytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.TailBytes |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipShimdiag(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *GuestLogResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowShimdiag
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: GuestLogResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: GuestLogResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Data", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Data = append(m.Data[:0], dAtA[iNdEx:postIndex]...)
+			if m.Data == nil {
+				m.Data = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Truncated", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Truncated = bool(v != 0)
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Files", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Files = append(m.Files, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipShimdiag(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *HealthRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowShimdiag
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: HealthRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: HealthRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PingTimeoutMs", wireType)
+			}
+			m.PingTimeoutMs = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.PingTimeoutMs |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipShimdiag(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *HealthResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowShimdiag
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: HealthResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: HealthResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ID", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Goroutines", wireType)
+			}
+			m.Goroutines = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Goroutines |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Handles", wireType)
+			}
+			m.Handles = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Handles |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PendingRpcs", wireType)
+			}
+			m.PendingRpcs = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.PendingRpcs |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EventBacklog", wireType)
+			}
+			m.EventBacklog = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.EventBacklog |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field UtilityVm", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.UtilityVm == nil {
+				m.UtilityVm = &UtilityVMHealth{}
+			}
+			if err := m.UtilityVm.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Tasks", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Tasks = append(m.Tasks, &TaskHealth{})
+			if err := m.Tasks[len(m.Tasks)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 8:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EventsPublished", wireType)
+			}
+			m.EventsPublished = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.EventsPublished |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 9:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EventsRetried", wireType)
+			}
+			m.EventsRetried = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.EventsRetried |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 10:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EventsDropped", wireType)
+			}
+			m.EventsDropped = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.EventsDropped |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipShimdiag(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
 
-func (c *shimDiagClient) DiagStacks(ctx context.Context, req *StacksRequest) (*StacksResponse, error) {
-	var resp StacksResponse
-	if err := c.client.Call(ctx, "containerd.runhcs.v1.diag.ShimDiag", "DiagStacks", req, &resp); err != nil {
-		return nil, err
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
 	}
-	return &resp, nil
+	return nil
 }
-func (m *ExecProcessRequest) Unmarshal(dAtA []byte) error {
+func (m *UtilityVMHealth) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -600,28 +3596,60 @@ func (m *ExecProcessRequest) Unmarshal(dAtA []byte) error {
 			if shift >= 64 {
 				return ErrIntOverflowShimdiag
 			}
-			if iNdEx >= l {
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: UtilityVMHealth: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: UtilityVMHealth: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ID", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= uint64(b&0x7F) << shift
-			if b < 0x80 {
-				break
-			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: ExecProcessRequest: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: ExecProcessRequest: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
+			m.ID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Args", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field State", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -649,11 +3677,11 @@ func (m *ExecProcessRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Args = append(m.Args, string(dAtA[iNdEx:postIndex]))
+			m.State = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 2:
+		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Workdir", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ExitError", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -681,11 +3709,11 @@ func (m *ExecProcessRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Workdir = string(dAtA[iNdEx:postIndex])
+			m.ExitError = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 3:
+		case 4:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Terminal", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field GuestResponsive", wireType)
 			}
 			var v int
 			for shift := uint(0); ; shift += 7 {
@@ -702,12 +3730,12 @@ func (m *ExecProcessRequest) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
-			m.Terminal = bool(v != 0)
-		case 4:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Stdin", wireType)
+			m.GuestResponsive = bool(v != 0)
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PingMicroseconds", wireType)
 			}
-			var stringLen uint64
+			m.PingMicroseconds = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowShimdiag
@@ -717,27 +3745,14 @@ func (m *ExecProcessRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				m.PingMicroseconds |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthShimdiag
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthShimdiag
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Stdin = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		case 5:
+		case 6:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Stdout", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field PingError", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -765,13 +3780,13 @@ func (m *ExecProcessRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Stdout = string(dAtA[iNdEx:postIndex])
+			m.PingError = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 6:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Stderr", wireType)
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PendingGuestRpcs", wireType)
 			}
-			var stringLen uint64
+			m.PendingGuestRpcs = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowShimdiag
@@ -781,24 +3796,11 @@ func (m *ExecProcessRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				m.PendingGuestRpcs |= uint32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthShimdiag
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthShimdiag
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Stderr = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipShimdiag(dAtA[iNdEx:])
@@ -824,7 +3826,7 @@ func (m *ExecProcessRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *ExecProcessResponse) Unmarshal(dAtA []byte) error {
+func (m *TaskHealth) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -847,17 +3849,17 @@ func (m *ExecProcessResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: ExecProcessResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: TaskHealth: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: ExecProcessResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: TaskHealth: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ExitCode", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ID", wireType)
 			}
-			m.ExitCode = 0
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowShimdiag
@@ -867,122 +3869,110 @@ func (m *ExecProcessResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.ExitCode |= int32(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		default:
-			iNdEx = preIndex
-			skippy, err := skipShimdiag(dAtA[iNdEx:])
-			if err != nil {
-				return err
-			}
-			if skippy < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthShimdiag
 			}
-			if (iNdEx + skippy) < 0 {
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
 				return ErrInvalidLengthShimdiag
 			}
-			if (iNdEx + skippy) > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *StacksRequest) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowShimdiag
-			}
-			if iNdEx >= l {
+			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= uint64(b&0x7F) << shift
-			if b < 0x80 {
-				break
+			m.ID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field State", wireType)
 			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: StacksRequest: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: StacksRequest: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		default:
-			iNdEx = preIndex
-			skippy, err := skipShimdiag(dAtA[iNdEx:])
-			if err != nil {
-				return err
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			if skippy < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthShimdiag
 			}
-			if (iNdEx + skippy) < 0 {
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
 				return ErrInvalidLengthShimdiag
 			}
-			if (iNdEx + skippy) > l {
+			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *StacksResponse) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowShimdiag
+			m.State = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Execs", wireType)
 			}
-			if iNdEx >= l {
-				return io.ErrUnexpectedEOF
+			m.Execs = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Execs |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= uint64(b&0x7F) << shift
-			if b < 0x80 {
-				break
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LastError", wireType)
 			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: StacksResponse: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: StacksResponse: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.LastError = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Stacks", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field LastErrorMethod", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -1010,11 +4000,11 @@ func (m *StacksResponse) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Stacks = string(dAtA[iNdEx:postIndex])
+			m.LastErrorMethod = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 2:
+		case 6:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field GuestStacks", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field LastErrorAt", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -1042,7 +4032,7 @@ func (m *StacksResponse) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.GuestStacks = string(dAtA[iNdEx:postIndex])
+			m.LastErrorAt = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -199,3 +199,34 @@ func ValidateWCOW(signal int, signalsSupported bool) (*guestrequest.SignalProces
 		return &guestrequest.SignalProcessOptionsWCOW{Signal: signalString}, nil
 	}
 }
+
+// ParseSigstrLCOW returns the value of the LCOW signal `sigstr`.
+//
+// `sigstr` may either be the text name or integer value of the signal.
+func ParseSigstrLCOW(sigstr string) (int, error) {
+	return parseSigstr(sigstr, signalMapLcow)
+}
+
+// ParseSigstrWCOW returns the value of the WCOW signal `sigstr`, which is
+// either a Windows CTRL event or the UNIX SIGTERM/SIGKILL that `ValidateWCOW`
+// translates.
+//
+// `sigstr` may either be the text name or integer value of the signal.
+func ParseSigstrWCOW(sigstr string) (int, error) {
+	return parseSigstr(sigstr, signalMapWcow)
+}
+
+func parseSigstr(sigstr string, signalMap map[string]int) (int, error) {
+	if signal, err := strconv.Atoi(sigstr); err == nil {
+		for _, v := range signalMap {
+			if signal == v {
+				return signal, nil
+			}
+		}
+		return 0, ErrInvalidSignal
+	}
+	if v, ok := signalMap[strings.TrimPrefix(strings.ToUpper(sigstr), "SIG")]; ok {
+		return v, nil
+	}
+	return 0, ErrInvalidSignal
+}
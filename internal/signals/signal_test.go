@@ -310,3 +310,39 @@ func Test_ValidateWCOW_SignalsSupported(t *testing.T) {
 		}
 	}
 }
+
+func Test_ParseSigstr_LCOW(t *testing.T) {
+	cases := map[string]int{"TERM": sigTerm, "sigterm": sigTerm, "9": sigKill, "usr1": 0xa}
+	for sigstr, value := range cases {
+		ret, err := ParseSigstrLCOW(sigstr)
+		if err != nil {
+			t.Fatalf("expected nil err for signal: %v got: %v", sigstr, err)
+		}
+		if ret != value {
+			t.Fatalf("expected signal: %v, got: %v", value, ret)
+		}
+	}
+	for _, sigstr := range []string{"", "CTRLSHUTDOWN", "99"} {
+		if _, err := ParseSigstrLCOW(sigstr); err != ErrInvalidSignal {
+			t.Fatalf("expected invalid signal err for signal: %v got: %v", sigstr, err)
+		}
+	}
+}
+
+func Test_ParseSigstr_WCOW(t *testing.T) {
+	cases := map[string]int{"CtrlShutdown": ctrlShutdown, "ctrlc": ctrlC, "TERM": sigTerm, "6": ctrlShutdown, "9": sigKill}
+	for sigstr, value := range cases {
+		ret, err := ParseSigstrWCOW(sigstr)
+		if err != nil {
+			t.Fatalf("expected nil err for signal: %v got: %v", sigstr, err)
+		}
+		if ret != value {
+			t.Fatalf("expected signal: %v, got: %v", value, ret)
+		}
+	}
+	for _, sigstr := range []string{"", "USR1", "10"} {
+		if _, err := ParseSigstrWCOW(sigstr); err != ErrInvalidSignal {
+			t.Fatalf("expected invalid signal err for signal: %v got: %v", sigstr, err)
+		}
+	}
+}
@@ -49,3 +49,13 @@ const (
 	ctrlLogOff   = 0x5
 	ctrlShutdown = 0x6
 )
+
+var signalMapWcow = map[string]int{
+	"CTRLC":        ctrlC,
+	"CTRLBREAK":    ctrlBreak,
+	"CTRLCLOSE":    ctrlClose,
+	"CTRLLOGOFF":   ctrlLogOff,
+	"CTRLSHUTDOWN": ctrlShutdown,
+	"TERM":         sigTerm,
+	"KILL":         sigKill,
+}
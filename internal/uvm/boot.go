@@ -0,0 +1,80 @@
+package uvm
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/Microsoft/hcsshim/cmd/containerd-shim-runhcs-v1/stats"
+	"github.com/Microsoft/hcsshim/internal/logfields"
+	"github.com/Microsoft/hcsshim/internal/oc"
+	"go.opencensus.io/trace"
+)
+
+// bootPhase is a phase of creating and starting a utility VM.
+type bootPhase int
+
+const (
+	// bootPhaseBuildDocument builds the HCS document of the utility VM.
+	bootPhaseBuildDocument bootPhase = iota
+	// bootPhaseHCSCreate creates the HCS compute system.
+	bootPhaseHCSCreate
+	// bootPhaseHCSStart starts the HCS compute system.
+	bootPhaseHCSStart
+	// bootPhaseGuestConnect waits for the guest to connect to the entropy,
+	// log and GCS sockets of the utility VM.
+	bootPhaseGuestConnect
+	// bootPhaseGCSNegotiate negotiates the GCS protocol with the guest.
+	bootPhaseGCSNegotiate
+	// bootPhaseFirstContainer creates the first container in the utility VM.
+	bootPhaseFirstContainer
+
+	bootPhaseCount
+)
+
+// bootPhaseSpans are the names of the spans emitted for each boot phase.
+var bootPhaseSpans = [bootPhaseCount]string{
+	"uvm::BuildDocument",
+	"uvm::HCSCreate",
+	"uvm::HCSStart",
+	"uvm::GuestConnect",
+	"uvm::GCSNegotiate",
+	"uvm::FirstContainer",
+}
+
+// startBootPhase starts the span for `phase`. The returned function ends the
+// span with the status of `err` and, on success, records the duration of the
+// phase for Stats. Only the first call of the returned function has any
+// effect, so it can also be deferred to end the span on early returns.
+func (uvm *UtilityVM) startBootPhase(ctx context.Context, phase bootPhase) (context.Context, func(err error)) {
+	ctx, span := trace.StartSpan(ctx, bootPhaseSpans[phase])
+	span.AddAttributes(trace.StringAttribute(logfields.UVMID, uvm.id))
+	start := time.Now()
+	var ended uint32
+	return ctx, func(err error) {
+		if !atomic.CompareAndSwapUint32(&ended, 0, 1) {
+			return
+		}
+		oc.SetSpanStatus(span, err)
+		span.End()
+		if err == nil {
+			atomic.StoreInt64(&uvm.bootDurations[phase], int64(time.Since(start)))
+		}
+	}
+}
+
+// bootStatistics returns the durations of the boot phases of the utility VM
+// that have completed.
+func (uvm *UtilityVM) bootStatistics() *stats.VirtualMachineBootStatistics {
+	d := func(phase bootPhase) uint64 {
+		return uint64(atomic.LoadInt64(&uvm.bootDurations[phase]))
+	}
+	return &stats.VirtualMachineBootStatistics{
+		BuildDocumentNS:  d(bootPhaseBuildDocument),
+		HCSCreateNS:      d(bootPhaseHCSCreate),
+		HCSStartNS:       d(bootPhaseHCSStart),
+		GuestConnectNS:   d(bootPhaseGuestConnect),
+		GCSNegotiateNS:   d(bootPhaseGCSNegotiate),
+		FirstContainerNS: d(bootPhaseFirstContainer),
+	}
+}
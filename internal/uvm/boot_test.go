@@ -0,0 +1,32 @@
+package uvm
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBootPhases(t *testing.T) {
+	uvm := &UtilityVM{id: "vm"}
+
+	_, end := uvm.startBootPhase(context.Background(), bootPhaseHCSStart)
+	time.Sleep(time.Millisecond)
+	end(nil)
+	d := uvm.bootStatistics().HCSStartNS
+	if d < uint64(time.Millisecond) {
+		t.Fatalf("expected the HCS start duration to be recorded, got %d", d)
+	}
+	// Only the first call ends the phase.
+	end(nil)
+	if uvm.bootStatistics().HCSStartNS != d {
+		t.Fatal("expected the duration not to change once the phase has ended")
+	}
+
+	_, end = uvm.startBootPhase(context.Background(), bootPhaseGCSNegotiate)
+	end(errors.New("failed"))
+	end(nil)
+	if s := uvm.bootStatistics(); s.GCSNegotiateNS != 0 || s.FirstContainerNS != 0 {
+		t.Fatalf("expected failed and pending phases not to be recorded, got %+v", s)
+	}
+}
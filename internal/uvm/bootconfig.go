@@ -0,0 +1,231 @@
+package uvm
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	hcsschema "github.com/Microsoft/hcsshim/internal/schema2"
+)
+
+// repeatableKernelArgs are the kernel arguments that may be given more than
+// once with different values. Any other argument replaces an earlier argument
+// with the same name.
+var repeatableKernelArgs = map[string]bool{
+	"console": true,
+	"initrd":  true,
+}
+
+// LCOWBootConfig is the boot configuration of an LCOW utility VM. It composes
+// the kernel command line and the chipset that boots the kernel.
+type LCOWBootConfig struct {
+	// BootFilesPath is the folder in which the kernel, the root file system
+	// and the initrds reside. The other files are relative to it.
+	BootFilesPath string
+	// KernelFile is the kernel to boot.
+	KernelFile string
+	// KernelDirect boots `KernelFile` directly rather than through UEFI.
+	KernelDirect bool
+	// RootFSType is the type of `RootFSFile`.
+	RootFSType PreferredRootFSType
+	// RootFSFile is the initrd or VHD that holds the root file system.
+	RootFSFile string
+	// AdditionalInitrdFiles are loaded after an initrd root file system, in
+	// order. The kernel unpacks each initrd over the previous ones. They
+	// require UEFI boot.
+	AdditionalInitrdFiles []string
+	// KernelBootOptions are kernel arguments added after all others, so that
+	// they replace the arguments of the boot configuration.
+	KernelBootOptions string
+	// InitCommandLine is passed to init after the kernel arguments.
+	InitCommandLine string
+
+	kernelArgs []string
+}
+
+// NewLCOWBootConfig returns the boot configuration set by `opts`.
+func NewLCOWBootConfig(opts *OptionsLCOW) *LCOWBootConfig {
+	return &LCOWBootConfig{
+		BootFilesPath:         opts.BootFilesPath,
+		KernelFile:            opts.KernelFile,
+		KernelDirect:          opts.KernelDirect,
+		RootFSType:            opts.PreferredRootFSType,
+		RootFSFile:            opts.RootFSFile,
+		AdditionalInitrdFiles: opts.AdditionalInitrdFiles,
+		KernelBootOptions:     opts.KernelBootOptions,
+	}
+}
+
+// Validate checks that the boot files are present under `BootFilesPath` and
+// can be booted with the configuration.
+func (c *LCOWBootConfig) Validate() error {
+	if err := c.checkBootFile("kernel", c.KernelFile); err != nil {
+		return err
+	}
+	if err := c.checkBootFile("boot file", c.RootFSFile); err != nil {
+		return err
+	}
+	for _, f := range c.AdditionalInitrdFiles {
+		if err := c.checkBootFile("initrd", f); err != nil {
+			return err
+		}
+	}
+	if len(c.AdditionalInitrdFiles) > 0 {
+		if c.RootFSType != PreferredRootFSTypeInitRd {
+			return fmt.Errorf("additional initrds require an initrd root file system")
+		}
+		if c.KernelDirect {
+			return fmt.Errorf("additional initrds are not supported with KernelDirect boot")
+		}
+	}
+	return nil
+}
+
+// checkBootFile checks that the boot file `f` is present under
+// `BootFilesPath`.
+func (c *LCOWBootConfig) checkBootFile(kind, f string) error {
+	clean := filepath.Clean(f)
+	if f == "" || filepath.IsAbs(clean) || filepath.VolumeName(clean) != "" || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("%s: '%s' must be a file under the boot files path", kind, f)
+	}
+	full := filepath.Join(c.BootFilesPath, clean)
+	if _, err := os.Stat(full); os.IsNotExist(err) {
+		return fmt.Errorf("%s: '%s' not found", kind, full)
+	}
+	return nil
+}
+
+// KernelFilePath returns the full path of the kernel.
+func (c *LCOWBootConfig) KernelFilePath() string {
+	return filepath.Join(c.BootFilesPath, c.KernelFile)
+}
+
+// RootFSFilePath returns the full path of the root file system.
+func (c *LCOWBootConfig) RootFSFilePath() string {
+	return filepath.Join(c.BootFilesPath, c.RootFSFile)
+}
+
+// RootFSImageFormat returns the image format of a VHD root file system.
+func (c *LCOWBootConfig) RootFSImageFormat() string {
+	if strings.ToLower(filepath.Ext(c.RootFSFile)) == ".vhdx" {
+		return "Vhdx"
+	}
+	return "Vhd1"
+}
+
+// AddKernelArgs adds the space separated kernel arguments `args`. An argument
+// replaces an earlier argument with the same name unless the argument may be
+// repeated, such as "console".
+func (c *LCOWBootConfig) AddKernelArgs(args string) {
+	c.kernelArgs = append(c.kernelArgs, splitKernelArgs(args)...)
+}
+
+// KernelArgs returns the kernel arguments: the arguments that load the root
+// file system, then the arguments added with AddKernelArgs, then
+// `KernelBootOptions`, without the arguments that were replaced.
+func (c *LCOWBootConfig) KernelArgs() []string {
+	var args []string
+	switch c.RootFSType {
+	case PreferredRootFSTypeInitRd:
+		if !c.KernelDirect {
+			for _, f := range append([]string{c.RootFSFile}, c.AdditionalInitrdFiles...) {
+				args = append(args, "initrd=/"+filepath.ToSlash(filepath.Clean(f)))
+			}
+		}
+	case PreferredRootFSTypeVHD:
+		args = append(args, "root=/dev/pmem0", "ro", "rootwait", "init=/init")
+	}
+	args = append(args, c.kernelArgs...)
+	args = append(args, splitKernelArgs(c.KernelBootOptions)...)
+	return dedupKernelArgs(args)
+}
+
+// KernelCommandLine returns the kernel arguments followed by the command line
+// of init.
+func (c *LCOWBootConfig) KernelCommandLine() string {
+	args := c.KernelArgs()
+	if c.InitCommandLine != "" {
+		args = append(args, "--", c.InitCommandLine)
+	}
+	return strings.Join(args, " ")
+}
+
+// Chipset returns the chipset that boots the kernel with the kernel command
+// line.
+func (c *LCOWBootConfig) Chipset() *hcsschema.Chipset {
+	if !c.KernelDirect {
+		return &hcsschema.Chipset{
+			Uefi: &hcsschema.Uefi{
+				BootThis: &hcsschema.UefiBootEntry{
+					DevicePath:    `\` + c.KernelFile,
+					DeviceType:    "VmbFs",
+					VmbFsRootPath: c.BootFilesPath,
+					OptionalData:  c.KernelCommandLine(),
+				},
+			},
+		}
+	}
+	direct := &hcsschema.LinuxKernelDirect{
+		KernelFilePath: c.KernelFilePath(),
+		KernelCmdLine:  c.KernelCommandLine(),
+	}
+	if c.RootFSType == PreferredRootFSTypeInitRd {
+		direct.InitRdPath = c.RootFSFilePath()
+	}
+	return &hcsschema.Chipset{LinuxKernelDirect: direct}
+}
+
+// splitKernelArgs splits space separated kernel arguments. Spaces within
+// double quotes do not separate arguments, as for the kernel.
+func splitKernelArgs(s string) []string {
+	var (
+		args   []string
+		arg    strings.Builder
+		quoted bool
+	)
+	for _, r := range s {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			arg.WriteRune(r)
+		case !quoted && (r == ' ' || r == '\t' || r == '\n'):
+			if arg.Len() > 0 {
+				args = append(args, arg.String())
+				arg.Reset()
+			}
+		default:
+			arg.WriteRune(r)
+		}
+	}
+	if arg.Len() > 0 {
+		args = append(args, arg.String())
+	}
+	return args
+}
+
+// dedupKernelArgs removes the kernel arguments in `args` that are replaced by
+// a later argument with the same name, or repeated with the same value.
+func dedupKernelArgs(args []string) []string {
+	key := func(arg string) string {
+		name := arg
+		if i := strings.IndexByte(arg, '='); i >= 0 {
+			name = arg[:i]
+		}
+		if repeatableKernelArgs[name] {
+			return arg
+		}
+		return name
+	}
+	last := make(map[string]int)
+	for i, arg := range args {
+		last[key(arg)] = i
+	}
+	var deduped []string
+	for i, arg := range args {
+		if last[key(arg)] == i {
+			deduped = append(deduped, arg)
+		}
+	}
+	return deduped
+}
@@ -0,0 +1,167 @@
+package uvm
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitKernelArgs(t *testing.T) {
+	args := splitKernelArgs(` quiet  dyndbg="file a.c +p"	panic=-1 `)
+	expected := []string{"quiet", `dyndbg="file a.c +p"`, "panic=-1"}
+	if !reflect.DeepEqual(args, expected) {
+		t.Fatalf("expected %q, got %q", expected, args)
+	}
+}
+
+func TestLCOWBootConfigKernelCommandLine(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		config   LCOWBootConfig
+		args     []string
+		expected string
+	}{
+		{
+			name: "UEFI initrd",
+			config: LCOWBootConfig{
+				RootFSType:            PreferredRootFSTypeInitRd,
+				RootFSFile:            "initrd.img",
+				AdditionalInitrdFiles: []string{"modules.img"},
+				InitCommandLine:       "/bin/vsockexec /bin/gcs",
+			},
+			args:     []string{"8250_core.nr_uarts=0", "panic=-1 quiet", "nr_cpus=2 pci=off"},
+			expected: "initrd=/initrd.img initrd=/modules.img 8250_core.nr_uarts=0 panic=-1 quiet nr_cpus=2 pci=off -- /bin/vsockexec /bin/gcs",
+		},
+		{
+			name: "KernelDirect initrd",
+			config: LCOWBootConfig{
+				KernelDirect: true,
+				RootFSType:   PreferredRootFSTypeInitRd,
+				RootFSFile:   "initrd.img",
+			},
+			args:     []string{"quiet"},
+			expected: "quiet",
+		},
+		{
+			name: "VHD",
+			config: LCOWBootConfig{
+				RootFSType: PreferredRootFSTypeVHD,
+				RootFSFile: "rootfs.vhd",
+			},
+			args:     []string{"quiet"},
+			expected: "root=/dev/pmem0 ro rootwait init=/init quiet",
+		},
+		{
+			name: "Replaced and repeated",
+			config: LCOWBootConfig{
+				RootFSType:        PreferredRootFSTypeVHD,
+				RootFSFile:        "rootfs.vhd",
+				KernelBootOptions: "nr_cpus=4 console=tty quiet",
+			},
+			args:     []string{"8250_core.nr_uarts=1 console=ttyS0,115200", "console=tty", "quiet nr_cpus=2 pci=off"},
+			expected: "root=/dev/pmem0 ro rootwait init=/init 8250_core.nr_uarts=1 console=ttyS0,115200 pci=off nr_cpus=4 console=tty quiet",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, args := range tc.args {
+				tc.config.AddKernelArgs(args)
+			}
+			if cmdline := tc.config.KernelCommandLine(); cmdline != tc.expected {
+				t.Fatalf("expected %q, got %q", tc.expected, cmdline)
+			}
+		})
+	}
+}
+
+func TestLCOWBootConfigChipset(t *testing.T) {
+	config := LCOWBootConfig{
+		BootFilesPath:   "boot",
+		KernelFile:      "vmlinux",
+		RootFSType:      PreferredRootFSTypeInitRd,
+		RootFSFile:      "initrd.img",
+		InitCommandLine: "/bin/gcs",
+	}
+	chipset := config.Chipset()
+	if chipset.LinuxKernelDirect != nil || chipset.Uefi == nil {
+		t.Fatal("expected UEFI boot")
+	}
+	if entry := chipset.Uefi.BootThis; entry.DevicePath != `\vmlinux` || entry.VmbFsRootPath != "boot" || entry.OptionalData != "initrd=/initrd.img -- /bin/gcs" {
+		t.Fatalf("unexpected boot entry %+v", entry)
+	}
+
+	config.KernelDirect = true
+	chipset = config.Chipset()
+	if chipset.Uefi != nil || chipset.LinuxKernelDirect == nil {
+		t.Fatal("expected KernelDirect boot")
+	}
+	direct := chipset.LinuxKernelDirect
+	if direct.KernelFilePath != filepath.Join("boot", "vmlinux") || direct.InitRdPath != filepath.Join("boot", "initrd.img") || direct.KernelCmdLine != "-- /bin/gcs" {
+		t.Fatalf("unexpected KernelDirect boot %+v", direct)
+	}
+}
+
+func TestLCOWBootConfigValidate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bootconfig")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	for _, f := range []string{"kernel", "initrd.img", "modules.img"} {
+		if err := ioutil.WriteFile(filepath.Join(dir, f), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, tc := range []struct {
+		name   string
+		config LCOWBootConfig
+		err    string
+	}{
+		{
+			name:   "Valid",
+			config: LCOWBootConfig{KernelFile: "kernel", RootFSType: PreferredRootFSTypeInitRd, RootFSFile: "initrd.img", AdditionalInitrdFiles: []string{"modules.img"}},
+		},
+		{
+			name:   "Missing kernel",
+			config: LCOWBootConfig{KernelFile: "vmlinux", RootFSType: PreferredRootFSTypeInitRd, RootFSFile: "initrd.img"},
+			err:    "not found",
+		},
+		{
+			name:   "Missing initrd",
+			config: LCOWBootConfig{KernelFile: "kernel", RootFSType: PreferredRootFSTypeInitRd, RootFSFile: "initrd.img", AdditionalInitrdFiles: []string{"extra.img"}},
+			err:    "not found",
+		},
+		{
+			name:   "Outside boot files path",
+			config: LCOWBootConfig{KernelFile: filepath.Join("..", "kernel"), RootFSType: PreferredRootFSTypeInitRd, RootFSFile: "initrd.img"},
+			err:    "must be a file under the boot files path",
+		},
+		{
+			name:   "Additional initrds with KernelDirect",
+			config: LCOWBootConfig{KernelFile: "kernel", KernelDirect: true, RootFSType: PreferredRootFSTypeInitRd, RootFSFile: "initrd.img", AdditionalInitrdFiles: []string{"modules.img"}},
+			err:    "not supported with KernelDirect",
+		},
+		{
+			name:   "Additional initrds with VHD",
+			config: LCOWBootConfig{KernelFile: "kernel", RootFSType: PreferredRootFSTypeVHD, RootFSFile: "initrd.img", AdditionalInitrdFiles: []string{"modules.img"}},
+			err:    "require an initrd root file system",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.config.BootFilesPath = dir
+			err := tc.config.Validate()
+			if tc.err == "" {
+				if err != nil {
+					t.Fatalf("expected no error, got %s", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("expected an error containing %q, got %v", tc.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,215 @@
+package uvm
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Microsoft/go-winio"
+	"github.com/Microsoft/hcsshim/internal/logfields"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// consoleDialTimeout is how long to wait for the serial console pipe of a
+	// started utility VM.
+	consoleDialTimeout = 10 * time.Second
+
+	// KernelPanicReportFile is the name of the file in `LogCaptureDir` that the
+	// report of a guest kernel panic is saved to.
+	KernelPanicReportFile = "kernel-panic.log"
+
+	// kernelPanicMarker starts the message the Linux kernel logs when it
+	// panics.
+	kernelPanicMarker = "Kernel panic - not syncing: "
+	// kernelPanicEndMarker ends the output of a Linux kernel panic.
+	kernelPanicEndMarker = "---[ end Kernel panic"
+	// panicContextLines is the number of console lines before the panic
+	// message that are reported, which hold the oops and call trace that led
+	// to it.
+	panicContextLines = 100
+	// maxPanicReportLines limits the console lines reported after the panic
+	// message in case the end of the panic is never logged.
+	maxPanicReportLines = 100
+	// maxConsoleLine is the length after which a console line without a line
+	// break is split.
+	maxConsoleLine = 4096
+)
+
+// GuestPanicError is returned by ExitError when the utility VM exited after a
+// kernel panic in the guest.
+type GuestPanicError struct {
+	ID         string // ID of the utility VM
+	Message    string // The panic message of the kernel
+	ReportPath string // The file the panic report was saved to, if any
+	Err        error  // The exit error reported by the HCS, if any
+}
+
+func (e *GuestPanicError) Error() string {
+	s := fmt.Sprintf("utility VM %s exited after a guest kernel panic: %s", e.ID, e.Message)
+	if e.ReportPath != "" {
+		s += fmt.Sprintf(" (report saved to %s)", e.ReportPath)
+	}
+	return s
+}
+
+// panicDetector scans the serial console output of a Linux utility VM for a
+// kernel panic and keeps the console lines around it.
+type panicDetector struct {
+	m       sync.Mutex
+	partial []byte   // The last console line, until it is complete
+	context []string // The console lines before the panic
+	report  []string // The console lines from the panic on
+	message string   // The panic message, once the kernel panicked
+	done    bool     // Whether the panic report is complete
+}
+
+func (d *panicDetector) Write(p []byte) (int, error) {
+	d.m.Lock()
+	defer d.m.Unlock()
+	d.partial = append(d.partial, p...)
+	for {
+		i := bytes.IndexByte(d.partial, '\n')
+		if i < 0 {
+			if len(d.partial) < maxConsoleLine {
+				break
+			}
+			i = maxConsoleLine
+		}
+		d.line(strings.TrimRight(string(d.partial[:i]), "\r"))
+		if i < len(d.partial) && d.partial[i] == '\n' {
+			i++
+		}
+		d.partial = d.partial[i:]
+	}
+	return len(p), nil
+}
+
+// line processes a complete console line. `d.m` must be held.
+func (d *panicDetector) line(l string) {
+	switch {
+	case d.done:
+	case d.message == "":
+		if i := strings.Index(l, kernelPanicMarker); i >= 0 {
+			d.message = strings.TrimSpace(l[i+len(kernelPanicMarker):])
+			d.report = append(d.context, l)
+			d.context = nil
+			return
+		}
+		if len(d.context) == panicContextLines {
+			d.context = append(d.context[:0], d.context[1:]...)
+		}
+		d.context = append(d.context, l)
+	default:
+		d.report = append(d.report, l)
+		if strings.Contains(l, kernelPanicEndMarker) || len(d.report) >= panicContextLines+maxPanicReportLines {
+			d.done = true
+		}
+	}
+}
+
+// detected returns the panic message and the panic report, or "" if the kernel
+// did not panic.
+func (d *panicDetector) detected() (string, string) {
+	d.m.Lock()
+	defer d.m.Unlock()
+	if d.message == "" {
+		return "", ""
+	}
+	report := d.report
+	if !d.done && len(d.partial) > 0 {
+		report = append(report, string(d.partial))
+	}
+	return d.message, strings.Join(report, "\n") + "\n"
+}
+
+// consolePipeName returns the pipe that the serial console of the utility VM
+// `id` is served on when it is read by this package.
+func consolePipeName(id string) string {
+	return `\\.\pipe\` + id + "-console"
+}
+
+// readConsole copies the serial console of the started utility VM to its
+// capture and panic detector until the utility VM exits, then saves the panic
+// report if the kernel panicked.
+func (uvm *UtilityVM) readConsole() {
+	defer close(uvm.consoleDone)
+
+	timeout := consoleDialTimeout
+	conn, err := winio.DialPipe(uvm.consolePipe, &timeout)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			logfields.UVMID: uvm.id,
+			"pipe":          uvm.consolePipe,
+			logrus.ErrorKey: err,
+		}).Warn("failed to connect to serial console")
+		return
+	}
+	uvm.m.Lock()
+	uvm.consoleConn = conn
+	uvm.m.Unlock()
+	var w []io.Writer
+	if c, ok := uvm.logCaptures[GuestLogConsole]; ok {
+		w = append(w, c)
+	}
+	if uvm.panics != nil {
+		w = append(w, uvm.panics)
+	}
+	_, _ = io.Copy(io.MultiWriter(w...), conn)
+	conn.Close()
+
+	if uvm.panics == nil {
+		return
+	}
+	message, report := uvm.panics.detected()
+	if message == "" {
+		return
+	}
+	entry := logrus.WithFields(logrus.Fields{
+		logfields.UVMID: uvm.id,
+		"message":       message,
+	})
+	if uvm.logCaptureDir != "" {
+		path := filepath.Join(uvm.logCaptureDir, KernelPanicReportFile)
+		err := os.MkdirAll(uvm.logCaptureDir, 0700)
+		if err == nil {
+			err = ioutil.WriteFile(path, []byte(report), 0600)
+		}
+		if err != nil {
+			entry.WithError(err).Warn("failed to save guest kernel panic report")
+		} else {
+			uvm.panicReportPath = path
+			entry = entry.WithField("report", path)
+		}
+	}
+	entry.Error("guest kernel panic")
+}
+
+// guestPanic returns the kernel panic the utility VM exited after, if any,
+// once the serial console has been read to its end.
+func (uvm *UtilityVM) guestPanic(exitErr error) *GuestPanicError {
+	if uvm.panics == nil || uvm.consoleDone == nil {
+		return nil
+	}
+	select {
+	case <-uvm.consoleDone:
+	default:
+		return nil
+	}
+	message, _ := uvm.panics.detected()
+	if message == "" {
+		return nil
+	}
+	return &GuestPanicError{
+		ID:         uvm.id,
+		Message:    message,
+		ReportPath: uvm.panicReportPath,
+		Err:        exitErr,
+	}
+}
@@ -0,0 +1,59 @@
+package uvm
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestPanicDetector(t *testing.T) {
+	d := &panicDetector{}
+	for i := 0; i < panicContextLines+10; i++ {
+		fmt.Fprintf(d, "[    0.%06d] boot line %d\r\n", i, i)
+	}
+	if message, _ := d.detected(); message != "" {
+		t.Fatalf("expected no panic, got %q", message)
+	}
+
+	// Write the panic in pieces that split lines.
+	for _, s := range []string{
+		"[    5.000001] Call Trace:\n[    5.0000",
+		"02] Kernel panic - not syncing: Attempted to kill init! exitcode=0x00000100\n",
+		"[    5.000003] Kernel Offset: disabled\n",
+		"[    5.000004] ---[ end Kernel panic - not syncing: Attempted to kill init! exitcode=0x00000100 ]---\n",
+		"[    5.000005] after the end\n",
+	} {
+		d.Write([]byte(s))
+	}
+
+	message, report := d.detected()
+	if message != "Attempted to kill init! exitcode=0x00000100" {
+		t.Fatalf("unexpected panic message %q", message)
+	}
+	lines := strings.Split(strings.TrimSuffix(report, "\n"), "\n")
+	if len(lines) != panicContextLines+3 {
+		t.Fatalf("expected %d report lines, got %d", panicContextLines+3, len(lines))
+	}
+	if lines[panicContextLines-1] != "[    5.000001] Call Trace:" {
+		t.Fatalf("expected the line before the panic to be reported, got %q", lines[panicContextLines-1])
+	}
+	if !strings.HasSuffix(lines[0], "boot line 11") || strings.Contains(report, "after the end") {
+		t.Fatalf("unexpected report contents %q", report)
+	}
+	if !strings.HasPrefix(lines[len(lines)-1], "[    5.000004] ---[ end Kernel panic") {
+		t.Fatalf("expected the report to end with the end of the panic, got %q", lines[len(lines)-1])
+	}
+}
+
+func TestPanicDetectorUnterminated(t *testing.T) {
+	d := &panicDetector{}
+	d.Write([]byte("Kernel panic - not syncing: VFS: Unable to mount root fs\nCPU: 0 PID: 1"))
+
+	message, report := d.detected()
+	if message != "VFS: Unable to mount root fs" {
+		t.Fatalf("unexpected panic message %q", message)
+	}
+	if report != "Kernel panic - not syncing: VFS: Unable to mount root fs\nCPU: 0 PID: 1\n" {
+		t.Fatalf("expected the incomplete last line to be reported, got %q", report)
+	}
+}
@@ -14,6 +14,26 @@ const (
 	// DefaultVPMemSizeBytes is the default size of a VPMem device if the create request
 	// doesn't specify.
 	DefaultVPMemSizeBytes = 4 * 1024 * 1024 * 1024 // 4GB
+
+	// MaxSCSIControllers is the maximum number of SCSI controllers that may be
+	// added to a utility VM.
+	MaxSCSIControllers = 4
+
+	// DefaultSCSIControllerCount is the default number of SCSI controllers
+	// added to a utility VM if the create request doesn't specify how many.
+	DefaultSCSIControllerCount = 1
+
+	// DefaultLogCaptureMaxSizeInMB is the default size at which a captured
+	// guest log file is rotated.
+	DefaultLogCaptureMaxSizeInMB = 10
+
+	// DefaultLogCaptureMaxFiles is the default number of files kept for each
+	// captured guest log.
+	DefaultLogCaptureMaxFiles = 2
 )
 
 var errNotSupported = fmt.Errorf("not supported")
+
+// ErrLogNotCaptured is returned when reading a guest log that the utility VM
+// does not capture.
+var ErrLogNotCaptured = fmt.Errorf("guest log is not captured")
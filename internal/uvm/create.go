@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"sync/atomic"
 
 	"github.com/Microsoft/hcsshim/internal/cow"
 	"github.com/Microsoft/hcsshim/internal/hcs"
@@ -60,6 +61,16 @@ type Options struct {
 	// ExternalGuestConnection sets whether the guest RPC connection is performed
 	// internally by the OS platform or externally by this package.
 	ExternalGuestConnection bool
+
+	// WorkingSetTrimPolicy sets when the working set of the UVM is trimmed
+	// automatically. Only applies when `AllowOvercommit` is set.
+	WorkingSetTrimPolicy WorkingSetTrimPolicy
+
+	// Recoverable keeps the UVM, and the containers created in it, running
+	// when the last handle to them is closed so that they can be reopened with
+	// Open after the process that created them exits. Requires the guest
+	// connection of the HCS, so `ExternalGuestConnection` must not be set.
+	Recoverable bool
 }
 
 // newDefaultOptions returns the default base options for WCOW and LCOW.
@@ -84,9 +95,26 @@ func newDefaultOptions(id, owner string) *Options {
 	return opts
 }
 
-// ID returns the ID of the VM's compute system.
+// ID returns the ID of the utility VM. It is the ID of its compute system
+// unless the utility VM was renamed.
 func (uvm *UtilityVM) ID() string {
-	return uvm.hcsSystem.ID()
+	return uvm.id
+}
+
+// SystemID returns the ID of the VM's compute system.
+func (uvm *UtilityVM) SystemID() string {
+	return uvm.systemID
+}
+
+// Rename changes the ID the utility VM is known by, in its logs, inventory and
+// description, to `id`. The ID of its compute system cannot be changed and is
+// still returned by SystemID.
+//
+// It is used to give a utility VM created ahead of time, such as a pooled one,
+// the ID of the pod it is handed to. It MUST be called before the utility VM is
+// used.
+func (uvm *UtilityVM) Rename(id string) {
+	uvm.id = id
 }
 
 // OS returns the operating system of the utility VM.
@@ -94,8 +122,14 @@ func (uvm *UtilityVM) OS() string {
 	return uvm.operatingSystem
 }
 
-func (uvm *UtilityVM) create(ctx context.Context, doc interface{}) error {
-	uvm.exitCh = make(chan struct{})
+func (uvm *UtilityVM) create(ctx context.Context, doc interface{}) (err error) {
+	ctx, endPhase := uvm.startBootPhase(ctx, bootPhaseHCSCreate)
+	defer func() { endPhase(err) }()
+
+	uvm.document, err = toDocument(doc)
+	if err != nil {
+		return err
+	}
 	system, err := hcs.CreateComputeSystem(ctx, uvm.id, doc)
 	if err != nil {
 		return err
@@ -114,6 +148,7 @@ func (uvm *UtilityVM) create(ctx context.Context, doc interface{}) error {
 	}
 	uvm.runtimeID = properties.RuntimeID
 	uvm.hcsSystem = system
+	uvm.systemID = uvm.id
 	system = nil
 
 	log.G(ctx).WithFields(logrus.Fields{
@@ -151,14 +186,61 @@ func (uvm *UtilityVM) Close() (err error) {
 		uvm.outputListener.Close()
 		uvm.outputListener = nil
 	}
+	uvm.closeLogCaptures()
 	if uvm.hcsSystem != nil {
 		return uvm.hcsSystem.Close()
 	}
 	return nil
 }
 
+// Release closes the handles to a recoverable utility VM without terminating
+// it, so that it keeps running to be reopened with Open, possibly by another
+// process. The utility VM must not be used after Release.
+//
+// The output forwarded by the guest keeps being read, and is relayed to the
+// process that reopens the utility VM and calls AttachOutput, until the
+// utility VM exits. See OutputRelayed.
+func (uvm *UtilityVM) Release() (err error) {
+	ctx, span := trace.StartSpan(context.Background(), "uvm::Release")
+	defer span.End()
+	defer func() { oc.SetSpanStatus(span, err) }()
+	span.AddAttributes(trace.StringAttribute(logfields.UVMID, uvm.id))
+
+	if !uvm.recoverable {
+		return fmt.Errorf("utility VM %s is not recoverable", uvm.id)
+	}
+
+	windows.Close(uvm.vmmemProcess)
+
+	if uvm.outputListener != nil {
+		close(uvm.outputProcessingDone)
+		uvm.outputListener.Close()
+		uvm.outputListener = nil
+	}
+	if uvm.output != nil {
+		if err := uvm.output.release(); err != nil {
+			return fmt.Errorf("failed to relay the output of utility VM %s: %s", uvm.id, err)
+		}
+	}
+	// Disconnect from the serial console so that the process that reopens
+	// the utility VM can connect to it.
+	uvm.m.Lock()
+	if uvm.consoleConn != nil {
+		uvm.consoleConn.Close()
+	}
+	uvm.m.Unlock()
+	uvm.closeLogCaptures()
+	log.G(ctx).WithField(logfields.UVMID, uvm.id).Debug("released utility VM")
+	return uvm.hcsSystem.Close()
+}
+
 // CreateContainer creates a container in the utility VM.
-func (uvm *UtilityVM) CreateContainer(ctx context.Context, id string, settings interface{}) (cow.Container, error) {
+func (uvm *UtilityVM) CreateContainer(ctx context.Context, id string, settings interface{}) (_ cow.Container, err error) {
+	if atomic.CompareAndSwapUint32(&uvm.firstContainer, 0, 1) {
+		var endPhase func(error)
+		ctx, endPhase = uvm.startBootPhase(ctx, bootPhaseFirstContainer)
+		defer func() { endPhase(err) }()
+	}
 	if uvm.gc != nil {
 		c, err := uvm.gc.CreateContainer(ctx, id, settings)
 		if err != nil {
@@ -167,10 +249,10 @@ func (uvm *UtilityVM) CreateContainer(ctx context.Context, id string, settings i
 		return c, nil
 	}
 	doc := hcsschema.ComputeSystem{
-		HostingSystemId:                   uvm.id,
+		HostingSystemId:                   uvm.systemID,
 		Owner:                             uvm.owner,
 		SchemaVersion:                     schemaversion.SchemaV21(),
-		ShouldTerminateOnLastHandleClosed: true,
+		ShouldTerminateOnLastHandleClosed: !uvm.recoverable,
 		HostedSystem:                      settings,
 	}
 	c, err := hcs.CreateComputeSystem(ctx, id, &doc)
@@ -199,9 +281,14 @@ func (uvm *UtilityVM) Terminate(ctx context.Context) error {
 	return uvm.hcsSystem.Terminate(ctx)
 }
 
-// ExitError returns an error if the utility VM has terminated unexpectedly.
+// ExitError returns an error if the utility VM has terminated unexpectedly. If
+// the guest kernel panicked the error is a `*GuestPanicError`.
 func (uvm *UtilityVM) ExitError() error {
-	return uvm.hcsSystem.ExitError()
+	err := uvm.hcsSystem.ExitError()
+	if p := uvm.guestPanic(err); p != nil {
+		return p
+	}
+	return err
 }
 
 func defaultProcessorCount() int32 {
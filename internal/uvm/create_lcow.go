@@ -7,7 +7,7 @@ import (
 	"net"
 	"os"
 	"path/filepath"
-	"strings"
+	"strconv"
 
 	"github.com/Microsoft/go-winio"
 	"github.com/Microsoft/go-winio/pkg/guid"
@@ -59,7 +59,7 @@ type OptionsLCOW struct {
 	KernelBootOptions     string              // Additional boot options for the kernel
 	EnableGraphicsConsole bool                // If true, enable a graphics console for the utility VM
 	ConsolePipe           string              // The named pipe path to use for the serial console.  eg \\.\pipe\vmpipe
-	SCSIControllerCount   uint32              // The number of SCSI controllers. Defaults to 1. Currently we only support 0 or 1.
+	SCSIControllerCount   uint32              // The number of SCSI controllers. Defaults to `DefaultSCSIControllerCount`. Limit at `MaxSCSIControllers`.
 	UseGuestConnection    bool                // Whether the HCS should connect to the UVM's GCS. Defaults to true
 	ExecCommandLine       string              // The command line to exec from init. Defaults to GCS
 	ForwardStdout         bool                // Whether stdout will be forwarded from the executed program. Defaults to false
@@ -69,6 +69,12 @@ type OptionsLCOW struct {
 	VPMemSizeBytes        uint64              // Size of the VPMem devices. Defaults to `DefaultVPMemSizeBytes`.
 	PreferredRootFSType   PreferredRootFSType // If `KernelFile` is `InitrdFile` use `PreferredRootFSTypeInitRd`. If `KernelFile` is `VhdFile` use `PreferredRootFSTypeVHD`
 	EnableColdDiscardHint bool                // Whether the HCS should use cold discard hints. Defaults to false
+	LogCaptureDir         string              // Directory to capture the forwarded output of the executed program, and the serial console if `CaptureConsole`, to as rotated files. Defaults to no capture
+	LogCaptureMaxSizeInMB uint32              // Size at which a captured log file is rotated. Defaults to `DefaultLogCaptureMaxSizeInMB`
+	LogCaptureMaxFiles    uint32              // Number of files kept for each captured log. Defaults to `DefaultLogCaptureMaxFiles`
+	CaptureConsole        bool                // Whether to capture the serial console to `LogCaptureDir`. Ignored if `ConsolePipe` is set. Defaults to false
+	EnableCrashReporting  bool                // Whether to scan the serial console for a kernel panic, reported by `ExitError` and saved to `LogCaptureDir`. Ignored if `ConsolePipe` is set. Defaults to false
+	AdditionalInitrdFiles []string            // Filenames under `BootFilesPath` of initrds loaded after an initrd root file system. Requires `KernelDirect` to be false
 }
 
 // defaultLCOWOSBootFilesPath returns the default path used to locate the LCOW
@@ -102,7 +108,7 @@ func NewDefaultOptionsLCOW(id, owner string) *OptionsLCOW {
 		KernelBootOptions:     "",
 		EnableGraphicsConsole: false,
 		ConsolePipe:           "",
-		SCSIControllerCount:   1,
+		SCSIControllerCount:   DefaultSCSIControllerCount,
 		UseGuestConnection:    true,
 		ExecCommandLine:       fmt.Sprintf("/bin/gcs -v4 -log-format json -loglevel %s", logrus.StandardLogger().Level.String()),
 		ForwardStdout:         false,
@@ -112,6 +118,10 @@ func NewDefaultOptionsLCOW(id, owner string) *OptionsLCOW {
 		VPMemSizeBytes:        DefaultVPMemSizeBytes,
 		PreferredRootFSType:   PreferredRootFSTypeInitRd,
 		EnableColdDiscardHint: false,
+		LogCaptureMaxSizeInMB: DefaultLogCaptureMaxSizeInMB,
+		LogCaptureMaxFiles:    DefaultLogCaptureMaxFiles,
+		CaptureConsole:        false,
+		EnableCrashReporting:  false,
 	}
 
 	// LCOW has more reliable behavior with the external bridge.
@@ -162,14 +172,22 @@ func CreateLCOW(ctx context.Context, opts *OptionsLCOW) (_ *UtilityVM, err error
 		owner:               opts.Owner,
 		operatingSystem:     "linux",
 		scsiControllerCount: opts.SCSIControllerCount,
+		allowOvercommit:     opts.AllowOvercommit,
 		vpmemMaxCount:       opts.VPMemDeviceCount,
 		vpmemMaxSizeBytes:   opts.VPMemSizeBytes,
+		recoverable:         opts.Recoverable,
 	}
 	defer func() {
 		if err != nil {
 			uvm.Close()
 		}
 	}()
+	if opts.AllowOvercommit {
+		uvm.trim.state.Policy = opts.WorkingSetTrimPolicy
+	}
+
+	_, endBuildDocument := uvm.startBootPhase(ctx, bootPhaseBuildDocument)
+	defer func() { endBuildDocument(err) }()
 
 	// To maintain compatability with Docker we need to automatically downgrade
 	// a user CPU count if the setting is not possible.
@@ -178,17 +196,13 @@ func CreateLCOW(ctx context.Context, opts *OptionsLCOW) (_ *UtilityVM, err error
 	// Align the requested memory size.
 	memorySizeInMB := uvm.normalizeMemorySize(ctx, opts.MemorySizeInMB)
 
-	kernelFullPath := filepath.Join(opts.BootFilesPath, opts.KernelFile)
-	if _, err := os.Stat(kernelFullPath); os.IsNotExist(err) {
-		return nil, fmt.Errorf("kernel: '%s' not found", kernelFullPath)
-	}
-	rootfsFullPath := filepath.Join(opts.BootFilesPath, opts.RootFSFile)
-	if _, err := os.Stat(rootfsFullPath); os.IsNotExist(err) {
-		return nil, fmt.Errorf("boot file: '%s' not found", rootfsFullPath)
+	boot := NewLCOWBootConfig(opts)
+	if err := boot.Validate(); err != nil {
+		return nil, err
 	}
 
-	if opts.SCSIControllerCount > 1 {
-		return nil, fmt.Errorf("SCSI controller count must be 0 or 1") // Future extension here for up to 4
+	if opts.SCSIControllerCount > MaxSCSIControllers {
+		return nil, fmt.Errorf("SCSI controller count cannot be greater than %d", MaxSCSIControllers)
 	}
 	if opts.VPMemDeviceCount > MaxVPMEMCount {
 		return nil, fmt.Errorf("vpmem device count cannot be greater than %d", MaxVPMEMCount)
@@ -209,14 +223,16 @@ func CreateLCOW(ctx context.Context, opts *OptionsLCOW) (_ *UtilityVM, err error
 	if opts.EnableColdDiscardHint && osversion.Get().Build < 18967 {
 		return nil, fmt.Errorf("EnableColdDiscardHint is not supported on builds older than 18967")
 	}
+	if opts.Recoverable && opts.UseGuestConnection && opts.ExternalGuestConnection {
+		return nil, fmt.Errorf("a recoverable utility VM requires the guest connection of the HCS")
+	}
 
 	doc := &hcsschema.ComputeSystem{
 		Owner:                             uvm.owner,
 		SchemaVersion:                     schemaversion.SchemaV21(),
-		ShouldTerminateOnLastHandleClosed: true,
+		ShouldTerminateOnLastHandleClosed: !opts.Recoverable,
 		VirtualMachine: &hcsschema.VirtualMachine{
 			StopOnReset: true,
-			Chipset:     &hcsschema.Chipset{},
 			ComputeTopology: &hcsschema.Topology{
 				Memory: &hcsschema.Memory2{
 					SizeInMB:              memorySizeInMB,
@@ -259,11 +275,11 @@ func CreateLCOW(ctx context.Context, opts *OptionsLCOW) (_ *UtilityVM, err error
 	}
 
 	if uvm.scsiControllerCount > 0 {
-		// TODO: JTERRY75 - this should enumerate scsicount and add an entry per value.
-		doc.VirtualMachine.Devices.Scsi = map[string]hcsschema.Scsi{
-			"0": {
+		doc.VirtualMachine.Devices.Scsi = make(map[string]hcsschema.Scsi)
+		for i := 0; i < int(uvm.scsiControllerCount); i++ {
+			doc.VirtualMachine.Devices.Scsi[strconv.Itoa(i)] = hcsschema.Scsi{
 				Attachments: make(map[string]hcsschema.Attachment),
-			},
+			}
 		}
 	}
 	if uvm.vpmemMaxCount > 0 {
@@ -273,24 +289,13 @@ func CreateLCOW(ctx context.Context, opts *OptionsLCOW) (_ *UtilityVM, err error
 		}
 	}
 
-	var kernelArgs string
-	switch opts.PreferredRootFSType {
-	case PreferredRootFSTypeInitRd:
-		if !opts.KernelDirect {
-			kernelArgs = "initrd=/" + opts.RootFSFile
-		}
-	case PreferredRootFSTypeVHD:
+	if opts.PreferredRootFSType == PreferredRootFSTypeVHD {
 		// Support for VPMem VHD(X) booting rather than initrd..
-		kernelArgs = "root=/dev/pmem0 ro rootwait init=/init"
-		imageFormat := "Vhd1"
-		if strings.ToLower(filepath.Ext(opts.RootFSFile)) == "vhdx" {
-			imageFormat = "Vhdx"
-		}
 		doc.VirtualMachine.Devices.VirtualPMem.Devices = map[string]hcsschema.VirtualPMemDevice{
 			"0": {
-				HostPath:    rootfsFullPath,
+				HostPath:    boot.RootFSFilePath(),
 				ReadOnly:    true,
-				ImageFormat: imageFormat,
+				ImageFormat: boot.RootFSImageFormat(),
 			},
 		}
 		// Add to our internal structure
@@ -302,22 +307,58 @@ func CreateLCOW(ctx context.Context, opts *OptionsLCOW) (_ *UtilityVM, err error
 		uvm.vpmemNumDevices++
 	}
 
+	outputHandler := opts.OutputHandler
+	if opts.LogCaptureDir != "" {
+		uvm.logCaptureDir = opts.LogCaptureDir
+		if opts.ForwardStdout || opts.ForwardStderr {
+			c, err := uvm.startLogCapture(GuestLogGCS, opts.LogCaptureMaxSizeInMB, opts.LogCaptureMaxFiles)
+			if err != nil {
+				return nil, fmt.Errorf("failed to capture gcs log: %s", err)
+			}
+			handler := outputHandler
+			outputHandler = func(r io.Reader) { handler(io.TeeReader(r, c)) }
+		}
+		if opts.CaptureConsole && opts.ConsolePipe == "" {
+			if _, err := uvm.startLogCapture(GuestLogConsole, opts.LogCaptureMaxSizeInMB, opts.LogCaptureMaxFiles); err != nil {
+				return nil, fmt.Errorf("failed to capture serial console: %s", err)
+			}
+			uvm.consolePipe = consolePipeName(uvm.id)
+		}
+	}
+	// A recoverable utility VM serves its serial console even if it is not
+	// captured, so that the process that reopens it can capture it.
+	if opts.Recoverable && opts.CaptureConsole && opts.ConsolePipe == "" {
+		uvm.consolePipe = consolePipeName(uvm.id)
+	}
+	// The HCS only supports crash reporting for Windows guests, so a kernel
+	// panic is detected from the serial console instead.
+	if opts.EnableCrashReporting && opts.ConsolePipe == "" {
+		uvm.panics = &panicDetector{}
+		uvm.consolePipe = consolePipeName(uvm.id)
+	}
+
+	// Only a console pipe passed by the caller is for debugging, so a captured
+	// console keeps the utility VM terminating on a kernel panic.
 	vmDebugging := false
+	consolePipe := uvm.consolePipe
 	if opts.ConsolePipe != "" {
 		vmDebugging = true
-		kernelArgs += " 8250_core.nr_uarts=1 8250_core.skip_txen_test=1 console=ttyS0,115200"
+		consolePipe = opts.ConsolePipe
+	}
+	if consolePipe != "" {
+		boot.AddKernelArgs("8250_core.nr_uarts=1 8250_core.skip_txen_test=1 console=ttyS0,115200")
 		doc.VirtualMachine.Devices.ComPorts = map[string]hcsschema.ComPort{
 			"0": { // Which is actually COM1
-				NamedPipe: opts.ConsolePipe,
+				NamedPipe: consolePipe,
 			},
 		}
 	} else {
-		kernelArgs += " 8250_core.nr_uarts=0"
+		boot.AddKernelArgs("8250_core.nr_uarts=0")
 	}
 
 	if opts.EnableGraphicsConsole {
 		vmDebugging = true
-		kernelArgs += " console=tty"
+		boot.AddKernelArgs("console=tty")
 		doc.VirtualMachine.Devices.Keyboard = &hcsschema.Keyboard{}
 		doc.VirtualMachine.Devices.EnhancedModeVideo = &hcsschema.EnhancedModeVideo{}
 		doc.VirtualMachine.Devices.VideoMonitor = &hcsschema.VideoMonitor{}
@@ -325,11 +366,7 @@ func CreateLCOW(ctx context.Context, opts *OptionsLCOW) (_ *UtilityVM, err error
 
 	if !vmDebugging {
 		// Terminate the VM if there is a kernel panic.
-		kernelArgs += " panic=-1 quiet"
-	}
-
-	if opts.KernelBootOptions != "" {
-		kernelArgs += " " + opts.KernelBootOptions
+		boot.AddKernelArgs("panic=-1 quiet")
 	}
 
 	// Inject initial entropy over vsock during init launch.
@@ -354,32 +391,15 @@ func CreateLCOW(ctx context.Context, opts *OptionsLCOW) (_ *UtilityVM, err error
 		initArgs = `sh -c "` + initArgs + ` & exec sh"`
 	}
 
-	kernelArgs += fmt.Sprintf(" nr_cpus=%d", opts.ProcessorCount)
-	kernelArgs += ` pci=off brd.rd_nr=0 pmtmr=0 -- ` + initArgs
-
-	if !opts.KernelDirect {
-		doc.VirtualMachine.Chipset.Uefi = &hcsschema.Uefi{
-			BootThis: &hcsschema.UefiBootEntry{
-				DevicePath:    `\` + opts.KernelFile,
-				DeviceType:    "VmbFs",
-				VmbFsRootPath: opts.BootFilesPath,
-				OptionalData:  kernelArgs,
-			},
-		}
-	} else {
-		doc.VirtualMachine.Chipset.LinuxKernelDirect = &hcsschema.LinuxKernelDirect{
-			KernelFilePath: kernelFullPath,
-			KernelCmdLine:  kernelArgs,
-		}
-		if opts.PreferredRootFSType == PreferredRootFSTypeInitRd {
-			doc.VirtualMachine.Chipset.LinuxKernelDirect.InitRdPath = rootfsFullPath
-		}
-	}
+	boot.AddKernelArgs(fmt.Sprintf("nr_cpus=%d pci=off brd.rd_nr=0 pmtmr=0", opts.ProcessorCount))
+	boot.InitCommandLine = initArgs
+	doc.VirtualMachine.Chipset = boot.Chipset()
 
 	fullDoc, err := mergemaps.MergeJSON(doc, ([]byte)(opts.AdditionHCSDocumentJSON))
 	if err != nil {
 		return nil, fmt.Errorf("failed to merge additional JSON '%s': %s", opts.AdditionHCSDocumentJSON, err)
 	}
+	endBuildDocument(nil)
 
 	err = uvm.create(ctx, fullDoc)
 	if err != nil {
@@ -395,7 +415,7 @@ func CreateLCOW(ctx context.Context, opts *OptionsLCOW) (_ *UtilityVM, err error
 	// Create a socket that the executed program can send to. This is usually
 	// used by GCS to send log data.
 	if opts.ForwardStdout || opts.ForwardStderr {
-		uvm.outputHandler = opts.OutputHandler
+		uvm.outputHandler = outputHandler
 		uvm.outputProcessingDone = make(chan struct{})
 		uvm.outputListener, err = uvm.listenVsock(linuxLogVsockPort)
 		if err != nil {
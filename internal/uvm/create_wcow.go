@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 
 	"github.com/Microsoft/go-winio"
 	"github.com/Microsoft/go-winio/pkg/guid"
@@ -24,7 +25,8 @@ import (
 type OptionsWCOW struct {
 	*Options
 
-	LayerFolders []string // Set of folders for base layers and scratch. Ordered from top most read-only through base read-only layer, followed by scratch
+	LayerFolders        []string // Set of folders for base layers and scratch. Ordered from top most read-only through base read-only layer, followed by scratch
+	SCSIControllerCount uint32   // The number of SCSI controllers. Defaults to `DefaultSCSIControllerCount`. Must be at least 1 for the scratch. Limit at `MaxSCSIControllers`.
 }
 
 // NewDefaultOptionsWCOW creates the default options for a bootable version of
@@ -36,7 +38,8 @@ type OptionsWCOW struct {
 // executable files name.
 func NewDefaultOptionsWCOW(id, owner string) *OptionsWCOW {
 	return &OptionsWCOW{
-		Options: newDefaultOptions(id, owner),
+		Options:             newDefaultOptions(id, owner),
+		SCSIControllerCount: DefaultSCSIControllerCount,
 	}
 }
 
@@ -65,7 +68,9 @@ func CreateWCOW(ctx context.Context, opts *OptionsWCOW) (_ *UtilityVM, err error
 		id:                  opts.ID,
 		owner:               opts.Owner,
 		operatingSystem:     "windows",
-		scsiControllerCount: 1,
+		scsiControllerCount: opts.SCSIControllerCount,
+		allowOvercommit:     opts.AllowOvercommit,
+		recoverable:         opts.Recoverable,
 		vsmbDirShares:       make(map[string]*vsmbShare),
 		vsmbFileShares:      make(map[string]*vsmbShare),
 	}
@@ -74,6 +79,12 @@ func CreateWCOW(ctx context.Context, opts *OptionsWCOW) (_ *UtilityVM, err error
 			uvm.Close()
 		}
 	}()
+	if opts.AllowOvercommit {
+		uvm.trim.state.Policy = opts.WorkingSetTrimPolicy
+	}
+
+	_, endBuildDocument := uvm.startBootPhase(ctx, bootPhaseBuildDocument)
+	defer func() { endBuildDocument(err) }()
 
 	// To maintain compatability with Docker we need to automatically downgrade
 	// a user CPU count if the setting is not possible.
@@ -85,6 +96,12 @@ func CreateWCOW(ctx context.Context, opts *OptionsWCOW) (_ *UtilityVM, err error
 	if len(opts.LayerFolders) < 2 {
 		return nil, fmt.Errorf("at least 2 LayerFolders must be supplied")
 	}
+	if opts.SCSIControllerCount == 0 || opts.SCSIControllerCount > MaxSCSIControllers {
+		return nil, fmt.Errorf("SCSI controller count must be between 1 and %d", MaxSCSIControllers)
+	}
+	if opts.Recoverable && opts.ExternalGuestConnection {
+		return nil, fmt.Errorf("a recoverable utility VM requires the guest connection of the HCS")
+	}
 	uvmFolder, err := uvmfolder.LocateUVMFolder(ctx, opts.LayerFolders)
 	if err != nil {
 		return nil, fmt.Errorf("failed to locate utility VM folder from layer folders: %s", err)
@@ -116,7 +133,7 @@ func CreateWCOW(ctx context.Context, opts *OptionsWCOW) (_ *UtilityVM, err error
 	doc := &hcsschema.ComputeSystem{
 		Owner:                             uvm.owner,
 		SchemaVersion:                     schemaversion.SchemaV21(),
-		ShouldTerminateOnLastHandleClosed: true,
+		ShouldTerminateOnLastHandleClosed: !opts.Recoverable,
 		VirtualMachine: &hcsschema.VirtualMachine{
 			StopOnReset: true,
 			Chipset: &hcsschema.Chipset{
@@ -142,16 +159,6 @@ func CreateWCOW(ctx context.Context, opts *OptionsWCOW) (_ *UtilityVM, err error
 				},
 			},
 			Devices: &hcsschema.Devices{
-				Scsi: map[string]hcsschema.Scsi{
-					"0": {
-						Attachments: map[string]hcsschema.Attachment{
-							"0": {
-								Path:  scratchPath,
-								Type_: "VirtualDisk",
-							},
-						},
-					},
-				},
 				HvSocket: &hcsschema.HvSocket2{
 					HvSocketConfig: &hcsschema.HvSocketSystemConfig{
 						// Allow administrators and SYSTEM to bind to vsock sockets
@@ -191,12 +198,25 @@ func CreateWCOW(ctx context.Context, opts *OptionsWCOW) (_ *UtilityVM, err error
 		}
 	}
 
-	uvm.scsiLocations[0][0].hostPath = doc.VirtualMachine.Devices.Scsi["0"].Attachments["0"].Path
+	// The scratch is always attached to the first controller.
+	doc.VirtualMachine.Devices.Scsi = make(map[string]hcsschema.Scsi)
+	for i := 0; i < int(uvm.scsiControllerCount); i++ {
+		doc.VirtualMachine.Devices.Scsi[strconv.Itoa(i)] = hcsschema.Scsi{
+			Attachments: make(map[string]hcsschema.Attachment),
+		}
+	}
+	doc.VirtualMachine.Devices.Scsi["0"].Attachments["0"] = hcsschema.Attachment{
+		Path:  scratchPath,
+		Type_: "VirtualDisk",
+	}
+
+	uvm.scsiLocations[0][0].hostPath = scratchPath
 
 	fullDoc, err := mergemaps.MergeJSON(doc, ([]byte)(opts.AdditionHCSDocumentJSON))
 	if err != nil {
 		return nil, fmt.Errorf("failed to merge additional JSON '%s': %s", opts.AdditionHCSDocumentJSON, err)
 	}
+	endBuildDocument(nil)
 
 	err = uvm.create(ctx, fullDoc)
 	if err != nil {
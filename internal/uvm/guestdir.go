@@ -0,0 +1,47 @@
+package uvm
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Microsoft/hcsshim/internal/log"
+	"github.com/Microsoft/hcsshim/internal/logfields"
+	hcsschema "github.com/Microsoft/hcsshim/internal/schema2"
+	"github.com/sirupsen/logrus"
+)
+
+// MakeGuestDirectory creates the directory `guestPath` in the utility VM, along
+// with any missing parents. It succeeds if the directory already exists.
+func (uvm *UtilityVM) MakeGuestDirectory(ctx context.Context, guestPath string) error {
+	log.G(ctx).WithFields(logrus.Fields{
+		logfields.UVMID: uvm.id,
+		"guestPath":     guestPath,
+	}).Debug("uvm::MakeGuestDirectory")
+
+	if uvm.operatingSystem != "windows" {
+		if _, err := uvm.guestOutput(ctx, "mkdir", "-p", guestPath); err != nil {
+			return fmt.Errorf("failed to create directory %s in %s: %s", guestPath, uvm.id, err)
+		}
+		return nil
+	}
+
+	p, err := uvm.CreateProcess(ctx, &hcsschema.ProcessParameters{
+		CommandLine:      fmt.Sprintf(`cmd /c if not exist "%s" mkdir "%s"`, guestPath, guestPath),
+		WorkingDirectory: `C:\`,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create directory %s in %s: %s", guestPath, uvm.id, err)
+	}
+	defer p.Close()
+	if err := p.Wait(); err != nil {
+		return err
+	}
+	code, err := p.ExitCode()
+	if err != nil {
+		return err
+	}
+	if code != 0 {
+		return fmt.Errorf("failed to create directory %s in %s: mkdir exited with %d", guestPath, uvm.id, code)
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package uvm
+
+import (
+	"context"
+	"time"
+
+	"github.com/Microsoft/hcsshim/internal/schema1"
+)
+
+// PingGuest sends a request to the guest and returns how long the guest took
+// to respond. If the utility VM has no guest connection of its own the request
+// goes through the HCS.
+func (uvm *UtilityVM) PingGuest(ctx context.Context) (time.Duration, error) {
+	if uvm.gc != nil {
+		return uvm.gc.Ping(ctx)
+	}
+	start := time.Now()
+	_, err := uvm.hcsSystem.Properties(ctx, schema1.PropertyTypeProcessList)
+	return time.Since(start), err
+}
+
+// PendingGuestRPCs returns the number of requests sent to the guest that it
+// has not responded to yet. It is always `0` if the utility VM has no guest
+// connection of its own.
+func (uvm *UtilityVM) PendingGuestRPCs() int {
+	if uvm.gc == nil {
+		return 0
+	}
+	return uvm.gc.PendingRPCs()
+}
@@ -0,0 +1,186 @@
+package uvm
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+)
+
+// Kinds of devices reported by Inventory.
+const (
+	DeviceVSMB             = "VSMB"
+	DeviceVPMem            = "VPMem"
+	DeviceSCSI             = "SCSI"
+	DevicePlan9            = "Plan9"
+	DeviceNetworkNamespace = "NetworkNamespace"
+	DeviceNIC              = "NIC"
+)
+
+// Device is a device attached to a utility VM.
+type Device struct {
+	Kind string // One of the Device* constants
+	// Location is where the device is attached: the VSMB or Plan9 share name,
+	// the VPMem device number, the SCSI controller:LUN or the NIC ID.
+	Location string
+	HostPath string // Path on the host, or the HNS endpoint ID of a NIC
+	UVMPath  string // Path in the utility VM, if the device is mounted
+	NetNS    string // Network namespace of a network namespace or NIC
+	RefCount uint32
+	// Owners are the IDs of the containers using the device. The utility VM
+	// does not know the containers its devices are attached for, so this is
+	// set by the caller (see hcsoci.DeviceInventory).
+	Owners []string
+}
+
+// Inventory is a snapshot of the devices attached to a utility VM.
+type Inventory struct {
+	ID      string
+	OS      string
+	Devices []Device
+
+	VPMemDevices    uint32 // Number of VPMem devices in use
+	VPMemMaxDevices uint32
+	SCSIDevices     uint32 // Number of SCSI locations in use
+	SCSIMaxDevices  uint32
+}
+
+// Inventory returns a snapshot of the devices attached to the utility VM,
+// ordered by kind and location.
+func (uvm *UtilityVM) Inventory() Inventory {
+	uvm.m.Lock()
+	defer uvm.m.Unlock()
+
+	inv := Inventory{
+		ID:              uvm.id,
+		OS:              uvm.operatingSystem,
+		VPMemDevices:    uvm.vpmemNumDevices,
+		VPMemMaxDevices: uvm.vpmemMaxCount,
+		SCSIMaxDevices:  uvm.scsiControllerCount * uint32(len(uvm.scsiLocations[0])),
+	}
+
+	for _, m := range []map[string]*vsmbShare{uvm.vsmbDirShares, uvm.vsmbFileShares} {
+		for hostPath, share := range m {
+			d := Device{
+				Kind:     DeviceVSMB,
+				Location: share.name,
+				HostPath: hostPath,
+				UVMPath:  share.GuestPath(),
+				RefCount: share.refCount,
+			}
+			if share.allowedFiles == nil {
+				inv.Devices = append(inv.Devices, d)
+				continue
+			}
+			// Report each file of a single-file mapping as the owners of
+			// mappings refer to the file rather than the share.
+			for _, f := range share.allowedFiles {
+				fd := d
+				fd.HostPath = f
+				fd.UVMPath = filepath.Join(d.UVMPath, filepath.Base(f))
+				inv.Devices = append(inv.Devices, fd)
+			}
+		}
+	}
+
+	for i, vi := range uvm.vpmemDevices {
+		if vi.hostPath == "" {
+			continue
+		}
+		inv.Devices = append(inv.Devices, Device{
+			Kind:     DeviceVPMem,
+			Location: strconv.Itoa(i),
+			HostPath: vi.hostPath,
+			UVMPath:  vi.uvmPath,
+			RefCount: vi.refCount,
+		})
+	}
+
+	for controller := range uvm.scsiLocations {
+		for lun, si := range uvm.scsiLocations[controller] {
+			if si.hostPath == "" {
+				continue
+			}
+			inv.SCSIDevices++
+			uvmPath := si.uvmPath
+			if si.isLayer && uvmPath == "" {
+				uvmPath = fmt.Sprintf("/tmp/S%d/%d", controller, lun)
+			}
+			refCount := si.refCount
+			if !si.isLayer {
+				// Only layers are ref-counted.
+				refCount = 1
+			}
+			inv.Devices = append(inv.Devices, Device{
+				Kind:     DeviceSCSI,
+				Location: fmt.Sprintf("%d:%d", controller, lun),
+				HostPath: si.hostPath,
+				UVMPath:  uvmPath,
+				RefCount: refCount,
+			})
+		}
+	}
+
+	for name, share := range uvm.plan9Shares {
+		inv.Devices = append(inv.Devices, Device{
+			Kind:     DevicePlan9,
+			Location: name,
+			HostPath: share.hostPath,
+			UVMPath:  share.uvmPath,
+			RefCount: 1,
+		})
+	}
+
+	for id, ns := range uvm.namespaces {
+		inv.Devices = append(inv.Devices, Device{
+			Kind:     DeviceNetworkNamespace,
+			Location: id,
+			NetNS:    id,
+			RefCount: 1,
+		})
+		for endpointID, nic := range ns.nics {
+			if nic == nil {
+				continue
+			}
+			inv.Devices = append(inv.Devices, Device{
+				Kind:     DeviceNIC,
+				Location: nic.ID.String(),
+				HostPath: endpointID,
+				NetNS:    id,
+				RefCount: 1,
+			})
+		}
+	}
+
+	sort.SliceStable(inv.Devices, func(i, j int) bool {
+		a, b := &inv.Devices[i], &inv.Devices[j]
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.Location != b.Location {
+			return lessLocation(a.Location, b.Location)
+		}
+		return a.HostPath < b.HostPath
+	})
+	return inv
+}
+
+// lessLocation orders VPMem device numbers, Plan9 share names and SCSI
+// locations numerically and others lexically.
+func lessLocation(a, b string) bool {
+	var ac, al, bc, bl int
+	if n, _ := fmt.Sscanf(a, "%d:%d", &ac, &al); n == 2 {
+		if n, _ := fmt.Sscanf(b, "%d:%d", &bc, &bl); n == 2 {
+			if ac != bc {
+				return ac < bc
+			}
+			return al < bl
+		}
+	}
+	an, aerr := strconv.Atoi(a)
+	bn, berr := strconv.Atoi(b)
+	if aerr == nil && berr == nil {
+		return an < bn
+	}
+	return a < b
+}
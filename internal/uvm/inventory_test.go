@@ -0,0 +1,69 @@
+package uvm
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInventoryLCOW(t *testing.T) {
+	uvm := &UtilityVM{
+		id:                  "vm",
+		operatingSystem:     "linux",
+		scsiControllerCount: 2,
+		vpmemMaxCount:       DefaultVPMEMCount,
+		vpmemNumDevices:     2,
+		plan9Shares: map[string]plan9Info{
+			"10": {hostPath: `C:\share10`, uvmPath: "/run/mounts/m10"},
+			"2":  {hostPath: `C:\share2`, uvmPath: "/run/mounts/m2"},
+		},
+		namespaces: map[string]*namespaceInfo{
+			"ns": {nics: map[string]*nicInfo{"removed": nil}},
+		},
+	}
+	uvm.vpmemDevices[1] = vpmemInfo{hostPath: `C:\layer1\layer.vhd`, uvmPath: "/tmp/p1", refCount: 2}
+	uvm.vpmemDevices[0] = vpmemInfo{hostPath: `C:\layer0\layer.vhd`, uvmPath: "/tmp/p0", refCount: 1}
+	uvm.scsiLocations[1][3] = scsiInfo{hostPath: `C:\big\layer.vhd`, isLayer: true, refCount: 3}
+	uvm.scsiLocations[0][1] = scsiInfo{hostPath: `C:\scratch\sandbox.vhdx`, uvmPath: "/run/gcs/c/1/scratch"}
+
+	inv := uvm.Inventory()
+	expected := []Device{
+		{Kind: DeviceNetworkNamespace, Location: "ns", NetNS: "ns", RefCount: 1},
+		{Kind: DevicePlan9, Location: "2", HostPath: `C:\share2`, UVMPath: "/run/mounts/m2", RefCount: 1},
+		{Kind: DevicePlan9, Location: "10", HostPath: `C:\share10`, UVMPath: "/run/mounts/m10", RefCount: 1},
+		{Kind: DeviceSCSI, Location: "0:1", HostPath: `C:\scratch\sandbox.vhdx`, UVMPath: "/run/gcs/c/1/scratch", RefCount: 1},
+		{Kind: DeviceSCSI, Location: "1:3", HostPath: `C:\big\layer.vhd`, UVMPath: "/tmp/S1/3", RefCount: 3},
+		{Kind: DeviceVPMem, Location: "0", HostPath: `C:\layer0\layer.vhd`, UVMPath: "/tmp/p0", RefCount: 1},
+		{Kind: DeviceVPMem, Location: "1", HostPath: `C:\layer1\layer.vhd`, UVMPath: "/tmp/p1", RefCount: 2},
+	}
+	if !reflect.DeepEqual(inv.Devices, expected) {
+		t.Fatalf("expected devices %+v, got %+v", expected, inv.Devices)
+	}
+	if inv.SCSIDevices != 2 || inv.SCSIMaxDevices != 128 || inv.VPMemDevices != 2 || inv.VPMemMaxDevices != DefaultVPMEMCount {
+		t.Fatalf("unexpected slot usage %+v", inv)
+	}
+}
+
+func TestInventoryVSMBFileShare(t *testing.T) {
+	uvm := &UtilityVM{
+		id:                  "vm",
+		operatingSystem:     "windows",
+		scsiControllerCount: 1,
+		vsmbDirShares: map[string]*vsmbShare{
+			`C:\layer`: {name: "s1", refCount: 2},
+		},
+		vsmbFileShares: map[string]*vsmbShare{
+			`C:\files`: {name: "s2", refCount: 2, allowedFiles: []string{`C:\files\a`, `C:\files\b`}},
+		},
+	}
+
+	inv := uvm.Inventory()
+	guestPath := `\\?\VMSMB\VSMB-{dcc079ae-60ba-4d07-847c-3493609c0870}\`
+	expected := []Device{
+		{Kind: DeviceVSMB, Location: "s1", HostPath: `C:\layer`, UVMPath: guestPath + "s1", RefCount: 2},
+		{Kind: DeviceVSMB, Location: "s2", HostPath: `C:\files\a`, UVMPath: guestPath + `s2\a`, RefCount: 2},
+		{Kind: DeviceVSMB, Location: "s2", HostPath: `C:\files\b`, UVMPath: guestPath + `s2\b`, RefCount: 2},
+	}
+	if !reflect.DeepEqual(inv.Devices, expected) {
+		t.Fatalf("expected devices %+v, got %+v", expected, inv.Devices)
+	}
+}
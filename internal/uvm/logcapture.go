@@ -0,0 +1,87 @@
+package uvm
+
+import (
+	"os"
+	"path/filepath"
+	"sync/atomic"
+
+	"github.com/Microsoft/hcsshim/internal/logfields"
+	"github.com/Microsoft/hcsshim/internal/rotatelog"
+	"github.com/sirupsen/logrus"
+)
+
+// Sources of the guest logs captured by `OptionsLCOW.LogCaptureDir`.
+const (
+	// GuestLogGCS is the stdout/stderr stream of the GCS.
+	GuestLogGCS = "gcs"
+	// GuestLogConsole is the serial console of the utility VM.
+	GuestLogConsole = "console"
+)
+
+// logCapture writes a guest log to rotated files. Write errors are logged once
+// and otherwise ignored so that they never interrupt the live processing of
+// the guest log.
+type logCapture struct {
+	uvmID  string
+	source string
+	f      *rotatelog.File
+	failed uint32 // Set once a write failed. Must be accessed atomically.
+}
+
+func (c *logCapture) Write(p []byte) (int, error) {
+	if _, err := c.f.Write(p); err != nil && err != os.ErrClosed && atomic.CompareAndSwapUint32(&c.failed, 0, 1) {
+		logrus.WithFields(logrus.Fields{
+			logfields.UVMID: c.uvmID,
+			"source":        c.source,
+			logrus.ErrorKey: err,
+		}).Warn("failed to capture guest log")
+	}
+	return len(p), nil
+}
+
+// startLogCapture opens the rotated files that the guest log `source` is
+// captured to under `uvm.logCaptureDir`. A `maxSizeInMB` or `maxFiles` of 0
+// selects the default.
+func (uvm *UtilityVM) startLogCapture(source string, maxSizeInMB, maxFiles uint32) (*logCapture, error) {
+	if maxSizeInMB == 0 {
+		maxSizeInMB = DefaultLogCaptureMaxSizeInMB
+	}
+	if maxFiles == 0 {
+		maxFiles = DefaultLogCaptureMaxFiles
+	}
+	f, err := rotatelog.Open(filepath.Join(uvm.logCaptureDir, source+".log"), int64(maxSizeInMB)*1024*1024, int(maxFiles))
+	if err != nil {
+		return nil, err
+	}
+	c := &logCapture{uvmID: uvm.id, source: source, f: f}
+	if uvm.logCaptures == nil {
+		uvm.logCaptures = make(map[string]*logCapture)
+	}
+	uvm.logCaptureMaxFiles = int(maxFiles)
+	uvm.logCaptures[source] = c
+	return c, nil
+}
+
+// closeLogCaptures closes the files of the captured guest logs. They remain
+// readable with CapturedLog.
+func (uvm *UtilityVM) closeLogCaptures() {
+	for _, c := range uvm.logCaptures {
+		c.f.Close()
+	}
+}
+
+// CapturedLog returns the last `n` bytes captured of the guest log `source`,
+// or all of them if `n` is 0, whether earlier bytes were left out, and the
+// files the guest log is captured to, oldest first. Returns
+// `ErrLogNotCaptured` if `source` is not captured.
+func (uvm *UtilityVM) CapturedLog(source string, n int64) ([]byte, bool, []string, error) {
+	if _, ok := uvm.logCaptures[source]; !ok {
+		return nil, false, nil, ErrLogNotCaptured
+	}
+	path := filepath.Join(uvm.logCaptureDir, source+".log")
+	b, truncated, err := rotatelog.Tail(path, uvm.logCaptureMaxFiles, n)
+	if err != nil {
+		return nil, false, nil, err
+	}
+	return b, truncated, rotatelog.Files(path, uvm.logCaptureMaxFiles), nil
+}
@@ -0,0 +1,388 @@
+package uvm
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Microsoft/go-winio/pkg/process"
+	"github.com/Microsoft/hcsshim/internal/log"
+	"github.com/Microsoft/hcsshim/internal/logfields"
+	"github.com/Microsoft/hcsshim/internal/oc"
+	"github.com/Microsoft/hcsshim/internal/schema1"
+	hcsschema "github.com/Microsoft/hcsshim/internal/schema2"
+	"github.com/sirupsen/logrus"
+	"go.opencensus.io/trace"
+	"golang.org/x/sys/windows"
+)
+
+//go:generate go run ../../mksyscall_windows.go -output zsyscall_windows.go memory.go
+
+//sys setProcessWorkingSetSizeEx(process windows.Handle, minimumWorkingSetSize uintptr, maximumWorkingSetSize uintptr, flags uint32) (err error) = kernel32.SetProcessWorkingSetSizeEx
+
+const (
+	// workingSetTrimCheckInterval is how often the processor usage of a
+	// utility VM is checked for its working set trim policy.
+	workingSetTrimCheckInterval = 30 * time.Second
+
+	// DefaultWorkingSetTrimIdleProcessorPercent is the processor usage, in
+	// percent of one vCPU, below which a utility VM is considered idle.
+	DefaultWorkingSetTrimIdleProcessorPercent = 1
+
+	// guestMemoryInfoTTL is how long the memory available in a Linux utility
+	// VM is reused once read from the guest.
+	guestMemoryInfoTTL = 30 * time.Second
+
+	// guestDropCachesScript drops the clean page cache and slab objects of
+	// a Linux utility VM so that the memory backing them is free.
+	guestDropCachesScript = "sync && echo 3 > /proc/sys/vm/drop_caches"
+)
+
+// WorkingSetTrimPolicy controls when the working set of a utility VM is
+// trimmed automatically. The zero value never trims the working set.
+type WorkingSetTrimPolicy struct {
+	// IdleTimeout is how long the utility VM must be idle before its working
+	// set is trimmed. The working set is trimmed once each time the utility
+	// VM becomes idle. If `0` it is never trimmed automatically.
+	IdleTimeout time.Duration
+
+	// IdleProcessorPercent is the processor usage, in percent of one vCPU,
+	// below which the utility VM is idle. If `0` defaults to
+	// `DefaultWorkingSetTrimIdleProcessorPercent`.
+	IdleProcessorPercent uint32
+}
+
+// WorkingSetTrimState reports the trims of the working set of a utility VM.
+type WorkingSetTrimState struct {
+	Policy WorkingSetTrimPolicy
+	// Idle is whether the utility VM is idle according to the policy.
+	Idle bool
+	// Trims is the number of times the working set was trimmed.
+	Trims uint64
+	// TrimmedBytes is the total size the working set was trimmed by.
+	TrimmedBytes uint64
+	// LastTrim is when the working set was last trimmed.
+	LastTrim time.Time
+	// LastTrimmedBytes is the size the working set was trimmed by the last
+	// time.
+	LastTrimmedBytes uint64
+}
+
+// workingSetTrim is the working set trim policy and state of a utility VM.
+type workingSetTrim struct {
+	m       sync.Mutex
+	state   WorkingSetTrimState
+	started bool // Whether the utility VM is started
+	running bool // Whether the policy is applied in the background
+}
+
+// idleTracker decides when an idle utility VM should have its working set
+// trimmed from samples of its total processor runtime.
+type idleTracker struct {
+	sampled   bool
+	lastTime  time.Time
+	lastNS    uint64
+	idleSince time.Time
+	trimmed   bool // Whether the working set was trimmed since the VM became idle
+}
+
+// observe records that the utility VM has used `runtimeNS` of processor time
+// in total at `now`, and returns whether it is idle and whether its working
+// set should be trimmed according to `policy`.
+func (t *idleTracker) observe(now time.Time, runtimeNS uint64, policy WorkingSetTrimPolicy) (idle, trim bool) {
+	defer func() {
+		t.sampled = true
+		t.lastTime = now
+		t.lastNS = runtimeNS
+	}()
+	if !t.sampled || runtimeNS < t.lastNS || !now.After(t.lastTime) {
+		return false, false
+	}
+	percent := policy.IdleProcessorPercent
+	if percent == 0 {
+		percent = DefaultWorkingSetTrimIdleProcessorPercent
+	}
+	elapsed := uint64(now.Sub(t.lastTime))
+	if (runtimeNS-t.lastNS)*100 >= elapsed*uint64(percent) {
+		t.idleSince = time.Time{}
+		t.trimmed = false
+		return false, false
+	}
+	if t.idleSince.IsZero() {
+		t.idleSince = t.lastTime
+	}
+	if policy.IdleTimeout == 0 || t.trimmed || now.Sub(t.idleSince) < policy.IdleTimeout {
+		return true, false
+	}
+	t.trimmed = true
+	return true, true
+}
+
+// guestMemoryInfo caches the memory available in a Linux guest, which is read
+// by running a process in the guest, so that it is read at most once every
+// `guestMemoryInfoTTL` however often statistics are requested.
+type guestMemoryInfo struct {
+	m         sync.Mutex
+	sampled   time.Time
+	available uint64
+	err       error
+}
+
+// get returns the memory available in the guest at `now`, calling `read` if
+// the cached value is older than `guestMemoryInfoTTL`. Concurrent callers
+// wait for a single read.
+func (g *guestMemoryInfo) get(now time.Time, read func() (uint64, error)) (uint64, error) {
+	g.m.Lock()
+	defer g.m.Unlock()
+	if g.sampled.IsZero() || now.Sub(g.sampled) >= guestMemoryInfoTTL {
+		g.available, g.err = read()
+		g.sampled = now
+	}
+	return g.available, g.err
+}
+
+// invalidate discards the cached value, for the next get to read it again.
+func (g *guestMemoryInfo) invalidate() {
+	g.m.Lock()
+	defer g.m.Unlock()
+	g.sampled = time.Time{}
+}
+
+// parseMemAvailable returns the memory available in a Linux guest in bytes
+// from the contents of its /proc/meminfo.
+func parseMemAvailable(meminfo []byte) (uint64, error) {
+	s := bufio.NewScanner(bytes.NewReader(meminfo))
+	for s.Scan() {
+		fields := strings.Fields(s.Text())
+		if len(fields) < 2 || fields[0] != "MemAvailable:" {
+			continue
+		}
+		kb, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid MemAvailable %q: %s", fields[1], err)
+		}
+		return kb * 1024, nil
+	}
+	return 0, fmt.Errorf("MemAvailable not found in /proc/meminfo")
+}
+
+// SetWorkingSetTrimPolicy changes the working set trim policy of the utility VM.
+// It takes effect once the utility VM is started, and only if the utility VM is
+// backed by virtual memory.
+func (uvm *UtilityVM) SetWorkingSetTrimPolicy(policy WorkingSetTrimPolicy) {
+	uvm.trim.m.Lock()
+	defer uvm.trim.m.Unlock()
+	uvm.trim.state.Policy = policy
+	if policy.IdleTimeout != 0 && uvm.allowOvercommit && uvm.trim.started && !uvm.trim.running {
+		uvm.trim.running = true
+		go uvm.trimWhenIdle(uvm.exitCh)
+	}
+}
+
+// WorkingSetTrimState returns the working set trim policy of the utility VM and
+// the trims so far.
+func (uvm *UtilityVM) WorkingSetTrimState() WorkingSetTrimState {
+	uvm.trim.m.Lock()
+	defer uvm.trim.m.Unlock()
+	return uvm.trim.state
+}
+
+// startWorkingSetTrim applies the working set trim policy of the started
+// utility VM in the background.
+func (uvm *UtilityVM) startWorkingSetTrim() {
+	uvm.trim.m.Lock()
+	uvm.trim.started = true
+	policy := uvm.trim.state.Policy
+	uvm.trim.m.Unlock()
+	uvm.SetWorkingSetTrimPolicy(policy)
+}
+
+// trimWhenIdle trims the working set of the utility VM when it is idle
+// according to its policy, until the utility VM exits or the policy no longer
+// trims it.
+func (uvm *UtilityVM) trimWhenIdle(exitCh <-chan struct{}) {
+	entry := logrus.WithField(logfields.UVMID, uvm.id)
+	ticker := time.NewTicker(workingSetTrimCheckInterval)
+	defer ticker.Stop()
+	var tracker idleTracker
+	for {
+		select {
+		case <-exitCh:
+			return
+		case <-ticker.C:
+		}
+		ctx := context.Background()
+		runtimeNS, err := uvm.processorRuntime(ctx)
+		if err != nil {
+			entry.WithError(err).Debug("failed to get processor runtime for working set trim")
+			continue
+		}
+		uvm.trim.m.Lock()
+		policy := uvm.trim.state.Policy
+		if policy.IdleTimeout == 0 {
+			uvm.trim.state.Idle = false
+			uvm.trim.running = false
+			uvm.trim.m.Unlock()
+			return
+		}
+		idle, trim := tracker.observe(time.Now(), runtimeNS, policy)
+		uvm.trim.state.Idle = idle
+		uvm.trim.m.Unlock()
+		if !trim {
+			continue
+		}
+		if _, err := uvm.TrimWorkingSet(ctx); err != nil {
+			entry.WithError(err).Warn("failed to trim the working set of idle utility VM")
+		}
+	}
+}
+
+// processorRuntime returns the total processor runtime of the utility VM in
+// nanoseconds.
+func (uvm *UtilityVM) processorRuntime(ctx context.Context) (uint64, error) {
+	props, err := uvm.hcsSystem.Properties(ctx, schema1.PropertyTypeStatistics)
+	if err != nil {
+		return 0, err
+	}
+	return props.Statistics.Processor.TotalRuntime100ns * 100, nil
+}
+
+// TrimWorkingSet trims the working set of the vmmem process of a utility VM
+// that is backed by virtual memory, and returns the size the working set was
+// trimmed by in bytes. A Linux utility VM is first asked to drop its page
+// cache so that the guest touches fewer pages afterwards.
+//
+// This is not a memory balloon: the memory assigned to the guest does not
+// change, and the host pages the trimmed memory back in as soon as the guest
+// uses it again. A utility VM backed by physical memory has no working set to
+// trim.
+func (uvm *UtilityVM) TrimWorkingSet(ctx context.Context) (_ uint64, err error) {
+	ctx, span := trace.StartSpan(ctx, "uvm::TrimWorkingSet")
+	defer span.End()
+	defer func() { oc.SetSpanStatus(span, err) }()
+	span.AddAttributes(trace.StringAttribute(logfields.UVMID, uvm.id))
+
+	if !uvm.allowOvercommit {
+		return 0, fmt.Errorf("trimming the working set of utility VM %s backed by physical memory: %s", uvm.id, errNotSupported)
+	}
+	vmmem, err := uvm.getVMMEMProcess(ctx)
+	if err != nil {
+		return 0, err
+	}
+	before, err := process.GetProcessMemoryInfo(vmmem)
+	if err != nil {
+		return 0, err
+	}
+
+	if uvm.operatingSystem == "linux" && uvm.gc != nil {
+		if _, err := uvm.guestOutput(ctx, "sh", "-c", guestDropCachesScript); err != nil {
+			// The working set is still trimmed.
+			log.G(ctx).WithField(logfields.UVMID, uvm.id).WithError(err).Warn("failed to drop the guest page cache")
+		}
+	}
+
+	pid, err := windows.GetProcessId(vmmem)
+	if err != nil {
+		return 0, err
+	}
+	h, err := windows.OpenProcess(windows.PROCESS_SET_QUOTA, false, pid)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open vmmem process %d: %s", pid, err)
+	}
+	defer windows.Close(h)
+	// Passing -1 for both sizes removes as many pages as possible from the
+	// working set.
+	if err := setProcessWorkingSetSizeEx(h, ^uintptr(0), ^uintptr(0), 0); err != nil {
+		return 0, fmt.Errorf("failed to trim the working set of vmmem process %d: %s", pid, err)
+	}
+
+	after, err := process.GetProcessMemoryInfo(vmmem)
+	if err != nil {
+		return 0, err
+	}
+	var trimmed uint64
+	if after.WorkingSetSize < before.WorkingSetSize {
+		trimmed = uint64(before.WorkingSetSize - after.WorkingSetSize)
+	}
+
+	// Dropping the page cache changed the memory available in the guest.
+	uvm.guestMemory.invalidate()
+
+	uvm.trim.m.Lock()
+	uvm.trim.state.Trims++
+	uvm.trim.state.TrimmedBytes += trimmed
+	uvm.trim.state.LastTrim = time.Now()
+	uvm.trim.state.LastTrimmedBytes = trimmed
+	uvm.trim.m.Unlock()
+
+	log.G(ctx).WithFields(logrus.Fields{
+		logfields.UVMID: uvm.id,
+		"trimmedBytes":  trimmed,
+	}).Debug("trimmed utility VM working set")
+	return trimmed, nil
+}
+
+// guestAvailableMemory returns the memory the guest of a Linux utility VM
+// reports as available, from MemAvailable in its /proc/meminfo. It is the
+// memory the guest could give up and is not comparable with the working set
+// of the utility VM on the host, which includes the pages of the guest memory
+// that the guest does not use but has touched. It is only known for Linux
+// utility VMs with an external guest connection, and `0` otherwise. The value
+// is cached for `guestMemoryInfoTTL`.
+func (uvm *UtilityVM) guestAvailableMemory(ctx context.Context) uint64 {
+	if uvm.operatingSystem != "linux" || uvm.gc == nil {
+		return 0
+	}
+	available, err := uvm.guestMemory.get(time.Now(), func() (uint64, error) {
+		meminfo, err := uvm.guestOutput(ctx, "cat", "/proc/meminfo")
+		if err != nil {
+			return 0, err
+		}
+		return parseMemAvailable(meminfo)
+	})
+	if err != nil {
+		log.G(ctx).WithField(logfields.UVMID, uvm.id).WithError(err).Debug("failed to get the available guest memory")
+		return 0
+	}
+	return available
+}
+
+// guestOutput runs `args` in a Linux utility VM and returns its stdout.
+func (uvm *UtilityVM) guestOutput(ctx context.Context, args ...string) ([]byte, error) {
+	p, err := uvm.CreateProcess(ctx, &hcsschema.ProcessParameters{
+		CommandArgs:      args,
+		WorkingDirectory: "/",
+		Environment:      map[string]string{"PATH": "/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin"},
+		CreateStdOutPipe: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer p.Close()
+	_, stdout, _ := p.Stdio()
+	var out []byte
+	if stdout != nil {
+		out, err = ioutil.ReadAll(io.LimitReader(stdout, 1024*1024))
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := p.Wait(); err != nil {
+		return nil, err
+	}
+	code, err := p.ExitCode()
+	if err != nil {
+		return nil, err
+	}
+	if code != 0 {
+		return nil, fmt.Errorf("%s exited with %d", args[0], code)
+	}
+	return out, nil
+}
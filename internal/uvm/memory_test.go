@@ -0,0 +1,78 @@
+package uvm
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIdleTracker(t *testing.T) {
+	policy := WorkingSetTrimPolicy{IdleTimeout: 2 * time.Minute}
+	start := time.Now()
+	var tracker idleTracker
+	var runtimeNS uint64
+	for i, tc := range []struct {
+		used time.Duration // Processor time used since the last sample
+		idle bool
+		trim bool
+	}{
+		{0, false, false},                     // First sample
+		{time.Second, false, false},           // 1.6% of a vCPU
+		{100 * time.Millisecond, true, false}, // Idle for 1 minute
+		{0, true, true},                       // Idle for 2 minutes
+		{0, true, false},                      // Already trimmed
+		{time.Minute, false, false},           // Busy
+		{0, true, false},                      // Idle for 1 minute again
+		{0, true, true},                       // Idle for 2 minutes again
+	} {
+		runtimeNS += uint64(tc.used)
+		idle, trim := tracker.observe(start.Add(time.Duration(i)*time.Minute), runtimeNS, policy)
+		if idle != tc.idle || trim != tc.trim {
+			t.Fatalf("sample %d: expected idle %t and trim %t, got %t and %t", i, tc.idle, tc.trim, idle, trim)
+		}
+	}
+}
+
+func TestParseMemAvailable(t *testing.T) {
+	meminfo := []byte("MemTotal:        1015484 kB\nMemFree:          771488 kB\nMemAvailable:     824132 kB\nBuffers:           10192 kB\n")
+	available, err := parseMemAvailable(meminfo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if available != 824132*1024 {
+		t.Fatalf("expected %d bytes available, got %d", 824132*1024, available)
+	}
+	if _, err := parseMemAvailable([]byte("MemTotal: 1015484 kB\n")); err == nil {
+		t.Fatal("expected an error without MemAvailable")
+	}
+}
+
+func TestGuestMemoryInfo(t *testing.T) {
+	var g guestMemoryInfo
+	reads := 0
+	read := func() (uint64, error) {
+		reads++
+		return uint64(reads), nil
+	}
+	start := time.Now()
+	for i, tc := range []struct {
+		at         time.Duration
+		invalidate bool
+		available  uint64
+	}{
+		{0, false, 1},                               // First read
+		{10 * time.Second, false, 1},                // Cached
+		{guestMemoryInfoTTL, false, 2},              // Expired
+		{guestMemoryInfoTTL + time.Second, true, 3}, // Invalidated
+	} {
+		if tc.invalidate {
+			g.invalidate()
+		}
+		available, err := g.get(start.Add(tc.at), read)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if available != tc.available {
+			t.Fatalf("get %d: expected %d, got %d", i, tc.available, available)
+		}
+	}
+}
@@ -11,6 +11,11 @@ import (
 
 // Modify modifies the compute system by sending a request to HCS.
 func (uvm *UtilityVM) Modify(ctx context.Context, doc *hcsschema.ModifySettingRequest) (err error) {
+	defer func() {
+		if err == nil {
+			uvm.updateDocument(ctx, doc)
+		}
+	}()
 	if doc.GuestRequest == nil || uvm.gc == nil {
 		return uvm.hcsSystem.Modify(ctx, doc)
 	}
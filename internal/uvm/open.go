@@ -0,0 +1,96 @@
+package uvm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Microsoft/hcsshim/internal/hcs"
+	"github.com/Microsoft/hcsshim/internal/log"
+	"github.com/Microsoft/hcsshim/internal/logfields"
+	"github.com/Microsoft/hcsshim/internal/oc"
+	"github.com/Microsoft/hcsshim/internal/schema1"
+	"github.com/sirupsen/logrus"
+	"go.opencensus.io/trace"
+)
+
+// Describe returns a description of the running utility VM and its devices,
+// which Open uses to reopen it after the process that created it exits. The
+// utility VM must have been created with `Options.Recoverable`.
+func (uvm *UtilityVM) Describe() ([]byte, error) {
+	if !uvm.recoverable {
+		return nil, fmt.Errorf("utility VM %s is not recoverable", uvm.id)
+	}
+	uvm.m.Lock()
+	defer uvm.m.Unlock()
+	saved, err := uvm.describe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe utility VM %s: %s", uvm.id, err)
+	}
+	return json.Marshal(saved)
+}
+
+// Open reopens the running utility VM `description`, returned by Describe,
+// and its guest connection through the HCS.
+//
+// The output of the guest, its logs and its serial console are not captured
+// from an opened utility VM until AttachOutput is called. As with a created
+// utility VM, Close terminates it.
+func Open(ctx context.Context, description []byte) (_ *UtilityVM, err error) {
+	ctx, span := trace.StartSpan(ctx, "uvm::Open")
+	defer span.End()
+	defer func() { oc.SetSpanStatus(span, err) }()
+
+	var saved savedUtilityVM
+	if err := json.Unmarshal(description, &saved); err != nil {
+		return nil, fmt.Errorf("failed to read the description of utility VM: %s", err)
+	}
+	span.AddAttributes(trace.StringAttribute(logfields.UVMID, saved.ID))
+	if !saved.Recoverable || saved.ExternalGuestConnection {
+		return nil, fmt.Errorf("utility VM %s is not recoverable", saved.ID)
+	}
+
+	uvm, err := fromDescription(&saved)
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore the devices of utility VM %s: %s", saved.ID, err)
+	}
+	uvm.document = saved.Document
+	uvm.outputPipe = saved.OutputPipe
+	uvm.attachConsolePipe = saved.ConsolePipe
+	// The utility VM has already booted its first container.
+	uvm.firstContainer = 1
+
+	uvm.systemID = saved.SystemID
+	if uvm.systemID == "" {
+		uvm.systemID = uvm.id
+	}
+	system, err := hcs.OpenComputeSystem(ctx, uvm.systemID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		// Leave a utility VM that could not be reopened running, as the
+		// process that created it would have.
+		if err != nil {
+			system.Close()
+		}
+	}()
+	properties, err := system.Properties(ctx, schema1.PropertyTypeGuestConnection)
+	if err != nil {
+		return nil, err
+	}
+	uvm.runtimeID = properties.RuntimeID
+	uvm.guestCaps = properties.GuestConnectionInfo.GuestDefinedCapabilities
+	uvm.protocol = properties.GuestConnectionInfo.ProtocolVersion
+	uvm.hcsSystem = system
+
+	uvm.exitCh = make(chan struct{})
+	go uvm.waitBackground()
+	uvm.startWorkingSetTrim()
+
+	log.G(ctx).WithFields(logrus.Fields{
+		logfields.UVMID: uvm.id,
+		"runtime-id":    uvm.runtimeID,
+	}).Debug("opened utility VM")
+	return uvm, nil
+}
@@ -0,0 +1,248 @@
+package uvm
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"sync"
+
+	"github.com/Microsoft/go-winio"
+	"github.com/Microsoft/hcsshim/internal/logfields"
+	"github.com/sirupsen/logrus"
+)
+
+// maxPendingOutput is the guest output kept by the output relay of a released
+// utility VM until the process that reopens it connects. Later output is
+// dropped.
+const maxPendingOutput = 1024 * 1024
+
+// outputPipeName returns the pipe that the output of the released utility VM
+// with compute system ID `systemID` is relayed on.
+func outputPipeName(systemID string) string {
+	return `\\.\pipe\` + systemID + "-output"
+}
+
+// outputRelay reads the output forwarded by the guest of a recoverable
+// utility VM and passes it to the output handler of the process that created
+// the utility VM. Once the utility VM is released, the output is served on a
+// pipe instead for the process that reopens it, which connects with
+// AttachOutput. The relay keeps running until the utility VM exits, so the
+// guest never writes to a closed connection.
+type outputRelay struct {
+	uvmID string
+	conn  net.Conn
+	pipe  string
+
+	m        sync.Mutex
+	local    *io.PipeWriter // Feeds the output handler until released
+	listener net.Listener
+	client   net.Conn
+	pending  bytes.Buffer // Output read since release, until a client connects
+	dropped  bool         // Whether output was dropped since release
+
+	done chan struct{} // Closed once the guest closes the connection
+}
+
+// newOutputRelay starts relaying the output of the guest read from `conn` to
+// `handler`.
+func newOutputRelay(uvmID, pipe string, conn net.Conn, handler OutputHandler, handlerDone chan struct{}) *outputRelay {
+	r, w := io.Pipe()
+	relay := &outputRelay{
+		uvmID: uvmID,
+		conn:  conn,
+		pipe:  pipe,
+		local: w,
+		done:  make(chan struct{}),
+	}
+	go func() {
+		handler(r)
+		// Keep draining in case the handler stopped early.
+		_, _ = io.Copy(ioutil.Discard, r)
+		close(handlerDone)
+	}()
+	go relay.run()
+	return relay
+}
+
+func (r *outputRelay) run() {
+	defer close(r.done)
+	b := make([]byte, 4096)
+	for {
+		n, err := r.conn.Read(b)
+		if n > 0 {
+			r.write(b[:n])
+		}
+		if err != nil {
+			break
+		}
+	}
+	r.conn.Close()
+
+	r.m.Lock()
+	defer r.m.Unlock()
+	if r.local != nil {
+		r.local.Close()
+		r.local = nil
+	}
+	if r.listener != nil {
+		r.listener.Close()
+	}
+	if r.client != nil {
+		r.client.Close()
+		r.client = nil
+	}
+}
+
+func (r *outputRelay) write(p []byte) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	switch {
+	case r.local != nil:
+		_, _ = r.local.Write(p)
+	case r.client != nil:
+		if _, err := r.client.Write(p); err == nil {
+			return
+		}
+		// The process that reopened the utility VM is gone. Keep the output
+		// for the next one.
+		r.client.Close()
+		r.client = nil
+		r.buffer(p)
+	default:
+		r.buffer(p)
+	}
+}
+
+// buffer keeps `p` until a client connects. `r.m` must be held.
+func (r *outputRelay) buffer(p []byte) {
+	if r.pending.Len()+len(p) > maxPendingOutput {
+		if !r.dropped {
+			r.dropped = true
+			logrus.WithField(logfields.UVMID, r.uvmID).Warn("dropping guest output of released utility VM")
+		}
+		return
+	}
+	r.pending.Write(p)
+}
+
+// release stops passing the output to the local output handler and serves it
+// on `r.pipe` instead.
+func (r *outputRelay) release() error {
+	l, err := winio.ListenPipe(r.pipe, &winio.PipeConfig{
+		SecurityDescriptor: "D:P(A;;GA;;;SY)(A;;GA;;;BA)",
+	})
+	if err != nil {
+		return err
+	}
+	r.m.Lock()
+	select {
+	case <-r.done:
+		r.m.Unlock()
+		l.Close()
+		return nil
+	default:
+	}
+	r.listener = l
+	if r.local != nil {
+		r.local.Close()
+		r.local = nil
+	}
+	r.m.Unlock()
+	go r.accept(l)
+	return nil
+}
+
+// accept hands the output to each process that connects, one at a time.
+func (r *outputRelay) accept(l net.Listener) {
+	for {
+		c, err := l.Accept()
+		if err != nil {
+			return
+		}
+		r.m.Lock()
+		if r.client != nil {
+			r.client.Close()
+		}
+		if _, err := c.Write(r.pending.Bytes()); err != nil {
+			c.Close()
+			r.m.Unlock()
+			continue
+		}
+		r.pending.Reset()
+		r.dropped = false
+		r.client = c
+		r.m.Unlock()
+	}
+}
+
+// OutputRelayed returns a channel that is closed once the output of the
+// released utility VM is no longer relayed, when the utility VM exits. The
+// process that released it must keep running until then for the output to
+// reach the process that reopened it. The channel is closed already if the
+// output is not relayed.
+func (uvm *UtilityVM) OutputRelayed() <-chan struct{} {
+	if uvm.output == nil {
+		done := make(chan struct{})
+		close(done)
+		return done
+	}
+	return uvm.output.done
+}
+
+// AttachOutput passes the output of the guest of the opened utility VM to a
+// handler and captures its guest logs and serial console as `opts`, the
+// options it would have been created with, asks for. The output and serial
+// console are only available if the utility VM was released by the process
+// that created it while they were forwarded and served, such as for a pooled
+// utility VM; the options that depend on them are ignored otherwise.
+func (uvm *UtilityVM) AttachOutput(opts *OptionsLCOW) error {
+	outputHandler := opts.OutputHandler
+	if outputHandler == nil {
+		outputHandler = parseLogrus(uvm.id)
+	}
+	forwarded := uvm.outputPipe != "" && (opts.ForwardStdout || opts.ForwardStderr)
+	consolePipe := ""
+	if opts.LogCaptureDir != "" {
+		uvm.logCaptureDir = opts.LogCaptureDir
+		if forwarded {
+			c, err := uvm.startLogCapture(GuestLogGCS, opts.LogCaptureMaxSizeInMB, opts.LogCaptureMaxFiles)
+			if err != nil {
+				return fmt.Errorf("failed to capture gcs log: %s", err)
+			}
+			handler := outputHandler
+			outputHandler = func(r io.Reader) { handler(io.TeeReader(r, c)) }
+		}
+		if opts.CaptureConsole && uvm.attachConsolePipe != "" {
+			if _, err := uvm.startLogCapture(GuestLogConsole, opts.LogCaptureMaxSizeInMB, opts.LogCaptureMaxFiles); err != nil {
+				return fmt.Errorf("failed to capture serial console: %s", err)
+			}
+			consolePipe = uvm.attachConsolePipe
+		}
+	}
+	if opts.EnableCrashReporting && uvm.attachConsolePipe != "" {
+		uvm.panics = &panicDetector{}
+		consolePipe = uvm.attachConsolePipe
+	}
+
+	if forwarded {
+		timeout := consoleDialTimeout
+		conn, err := winio.DialPipe(uvm.outputPipe, &timeout)
+		if err != nil {
+			return fmt.Errorf("failed to connect to the output of utility VM %s: %s", uvm.id, err)
+		}
+		uvm.outputProcessingDone = make(chan struct{})
+		go func() {
+			outputHandler(conn)
+			conn.Close()
+			close(uvm.outputProcessingDone)
+		}()
+	}
+	if consolePipe != "" {
+		uvm.consolePipe = consolePipe
+		uvm.consoleDone = make(chan struct{})
+		go uvm.readConsole()
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package uvm
+
+import (
+	"io"
+	"io/ioutil"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/Microsoft/go-winio"
+)
+
+func TestOutputRelay(t *testing.T) {
+	guest, host := net.Pipe()
+	pipe := outputPipeName("TestOutputRelay")
+
+	local := make(chan []byte, 1)
+	handlerDone := make(chan struct{})
+	r := newOutputRelay("vm", pipe, host, func(rd io.Reader) {
+		b := make([]byte, len("before"))
+		io.ReadFull(rd, b)
+		local <- b
+	}, handlerDone)
+
+	if _, err := guest.Write([]byte("before")); err != nil {
+		t.Fatal(err)
+	}
+	if b := <-local; string(b) != "before" {
+		t.Fatalf("expected the local handler to get the output, got %q", b)
+	}
+	if err := r.release(); err != nil {
+		t.Fatal(err)
+	}
+	<-handlerDone
+
+	// Output written before the reopening process connects is kept for it.
+	if _, err := guest.Write([]byte("after")); err != nil {
+		t.Fatal(err)
+	}
+	timeout := time.Second
+	c, err := winio.DialPipe(pipe, &timeout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	b := make([]byte, len("after"))
+	if _, err := io.ReadFull(c, b); err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "after" {
+		t.Fatalf("expected the relayed output, got %q", b)
+	}
+	guest.Close()
+	if b, _ := ioutil.ReadAll(c); len(b) != 0 {
+		t.Fatalf("unexpected output %q", b)
+	}
+	select {
+	case <-r.done:
+	case <-time.After(time.Second):
+		t.Fatal("expected the relay to end once the guest closed its connection")
+	}
+}
@@ -83,6 +83,13 @@ func (uvm *UtilityVM) AddPlan9(ctx context.Context, hostPath string, uvmPath str
 		return nil, err
 	}
 
+	uvm.m.Lock()
+	if uvm.plan9Shares == nil {
+		uvm.plan9Shares = make(map[string]plan9Info)
+	}
+	uvm.plan9Shares[name] = plan9Info{hostPath: hostPath, uvmPath: uvmPath}
+	uvm.m.Unlock()
+
 	share := &Plan9Share{name: name, uvmPath: uvmPath}
 	return share, nil
 }
@@ -115,5 +122,21 @@ func (uvm *UtilityVM) RemovePlan9(ctx context.Context, share *Plan9Share) error
 	if err := uvm.Modify(ctx, modification); err != nil {
 		return fmt.Errorf("failed to remove plan9 share %s from %s: %+v: %s", share.name, uvm.id, modification, err)
 	}
+	uvm.m.Lock()
+	delete(uvm.plan9Shares, share.name)
+	uvm.m.Unlock()
 	return nil
 }
+
+// FindPlan9 returns the Plan9 share of `hostPath` in the utility VM, such as to
+// take over the shares of a utility VM that was reopened with Open.
+func (uvm *UtilityVM) FindPlan9(hostPath string) (*Plan9Share, error) {
+	uvm.m.Lock()
+	defer uvm.m.Unlock()
+	for name, share := range uvm.plan9Shares {
+		if share.hostPath == hostPath {
+			return &Plan9Share{name: name, uvmPath: share.uvmPath}, nil
+		}
+	}
+	return nil, ErrNotAttached
+}
@@ -0,0 +1,545 @@
+package uvm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync/atomic"
+
+	"github.com/Microsoft/go-winio"
+	"github.com/Microsoft/hcsshim/internal/gcs"
+	"github.com/Microsoft/hcsshim/internal/log"
+	"github.com/Microsoft/hcsshim/internal/logfields"
+	"github.com/Microsoft/hcsshim/internal/oc"
+	"github.com/Microsoft/hcsshim/internal/requesttype"
+	hcsschema "github.com/Microsoft/hcsshim/internal/schema2"
+	"github.com/sirupsen/logrus"
+	"go.opencensus.io/trace"
+)
+
+// savedStateSuffix is appended to the path of the saved state of a utility VM
+// to get the path of the file that describes it.
+const savedStateSuffix = ".json"
+
+// listResourcePaths are the resource paths of the HCS document that hold a
+// list of resources, which are added, updated and removed by their "Name".
+var listResourcePaths = map[string]bool{
+	"VirtualMachine/Devices/Plan9/Shares":      true,
+	"VirtualMachine/Devices/VirtualSmb/Shares": true,
+}
+
+// mergedResourcePaths maps the resource paths that do not exist in the HCS
+// document to the path their settings are merged into.
+var mergedResourcePaths = map[string]string{
+	processorLimitsResourcePath: "VirtualMachine/ComputeTopology/Processor",
+}
+
+// savedUtilityVM describes a utility VM so that it can be reopened with Open.
+// It is also written next to the saved state of the utility VM.
+type savedUtilityVM struct {
+	ID                      string
+	SystemID                string `json:",omitempty"` // Defaults to `ID`
+	Owner                   string
+	OperatingSystem         string
+	Document                map[string]interface{}
+	ExternalGuestConnection bool
+	ProcessorCount          int32
+	AllowOvercommit         bool
+	Recoverable             bool
+	WorkingSetTrimPolicy    WorkingSetTrimPolicy
+	ContainerCounter        uint64
+
+	SCSIControllerCount uint32
+	SCSI                []savedSCSI
+
+	VPMemMaxCount     uint32
+	VPMemMaxSizeBytes uint64
+	VPMem             []savedVPMem
+
+	Plan9Counter uint64
+	Plan9        []savedPlan9
+
+	VSMBCounter uint64
+	VSMB        []savedVSMB
+
+	Namespaces map[string][]*nicInfo
+
+	OutputPipe  string `json:",omitempty"` // Where the output of the guest is relayed once released
+	ConsolePipe string `json:",omitempty"` // Where the serial console is served
+}
+
+type savedSCSI struct {
+	Controller int
+	LUN        int
+	HostPath   string
+	UVMPath    string
+	IsLayer    bool
+	RefCount   uint32
+}
+
+type savedVPMem struct {
+	DeviceNumber int
+	HostPath     string
+	UVMPath      string
+	RefCount     uint32
+}
+
+type savedPlan9 struct {
+	Name     string
+	HostPath string
+	UVMPath  string
+}
+
+type savedVSMB struct {
+	HostPath     string
+	Name         string
+	RefCount     uint32
+	AllowedFiles []string
+	File         bool // Whether the share is restricted to single files
+}
+
+// toDocument converts the HCS document `doc` to its generic JSON form.
+func toDocument(doc interface{}) (map[string]interface{}, error) {
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// patchDocument applies a host modification of `resourcePath` to the HCS
+// document `doc`.
+func patchDocument(doc map[string]interface{}, resourcePath, requestType string, settings interface{}) error {
+	b, err := json.Marshal(settings)
+	if err != nil {
+		return err
+	}
+	var value interface{}
+	if err := json.Unmarshal(b, &value); err != nil {
+		return err
+	}
+
+	merge := false
+	if p, ok := mergedResourcePaths[resourcePath]; ok {
+		resourcePath = p
+		merge = true
+	}
+	keys := strings.Split(resourcePath, "/")
+	m := doc
+	for _, k := range keys[:len(keys)-1] {
+		next, ok := m[k].(map[string]interface{})
+		if !ok {
+			if requestType == requesttype.Remove {
+				return nil
+			}
+			next = make(map[string]interface{})
+			m[k] = next
+		}
+		m = next
+	}
+	key := keys[len(keys)-1]
+
+	switch {
+	case merge:
+		values, ok := value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("settings of %s must be an object", resourcePath)
+		}
+		target, ok := m[key].(map[string]interface{})
+		if !ok {
+			target = make(map[string]interface{})
+			m[key] = target
+		}
+		for k, v := range values {
+			target[k] = v
+		}
+	case listResourcePaths[resourcePath]:
+		values, ok := value.(map[string]interface{})
+		if !ok || values["Name"] == nil {
+			return fmt.Errorf("settings of %s must be an object with a name", resourcePath)
+		}
+		list, _ := m[key].([]interface{})
+		var patched []interface{}
+		for _, item := range list {
+			if item, ok := item.(map[string]interface{}); ok && item["Name"] == values["Name"] {
+				continue
+			}
+			patched = append(patched, item)
+		}
+		if requestType != requesttype.Remove {
+			patched = append(patched, values)
+		}
+		if len(patched) == 0 {
+			delete(m, key)
+		} else {
+			m[key] = patched
+		}
+	case requestType == requesttype.Remove:
+		delete(m, key)
+	default:
+		m[key] = value
+	}
+	return nil
+}
+
+// updateDocument applies the host side of the successful modification `req`
+// to the HCS document of the utility VM.
+func (uvm *UtilityVM) updateDocument(ctx context.Context, req *hcsschema.ModifySettingRequest) {
+	if req.ResourcePath == "" || req.RequestType == requesttype.PreAdd {
+		return
+	}
+	uvm.documentLock.Lock()
+	defer uvm.documentLock.Unlock()
+	if uvm.document == nil {
+		return
+	}
+	if err := patchDocument(uvm.document, req.ResourcePath, req.RequestType, req.Settings); err != nil {
+		log.G(ctx).WithFields(logrus.Fields{
+			logfields.UVMID: uvm.id,
+			"resourcePath":  req.ResourcePath,
+			logrus.ErrorKey: err,
+		}).Warn("failed to update the document of the utility VM")
+	}
+}
+
+// Save saves the state of the utility VM to the file `path`, and a
+// description of the utility VM to `path` + ".json".
+//
+// Save pauses the utility VM while it is saved and then resumes it, unless
+// `exit` is set in which case the utility VM is terminated once saved. If the
+// save fails the utility VM is resumed either way. Restore creates a utility VM
+// from the saved state.
+func (uvm *UtilityVM) Save(ctx context.Context, path string, exit bool) (err error) {
+	ctx, span := trace.StartSpan(ctx, "uvm::Save")
+	defer span.End()
+	defer func() { oc.SetSpanStatus(span, err) }()
+	span.AddAttributes(
+		trace.StringAttribute(logfields.UVMID, uvm.id),
+		trace.StringAttribute("path", path),
+		trace.BoolAttribute("exit", exit))
+
+	// Hold the device lock until the utility VM is saved so that its
+	// description matches the saved state.
+	uvm.m.Lock()
+	defer uvm.m.Unlock()
+
+	saved, err := uvm.describe()
+	if err != nil {
+		return fmt.Errorf("failed to describe utility VM %s: %s", uvm.id, err)
+	}
+	b, err := json.Marshal(saved)
+	if err != nil {
+		return err
+	}
+
+	if err := uvm.hcsSystem.Pause(ctx); err != nil {
+		return fmt.Errorf("failed to pause utility VM %s: %s", uvm.id, err)
+	}
+	defer func() {
+		if err == nil && exit {
+			return
+		}
+		if rerr := uvm.hcsSystem.Resume(ctx); rerr != nil {
+			log.G(ctx).WithField(logfields.UVMID, uvm.id).WithError(rerr).Error("failed to resume utility VM after save")
+			if err == nil {
+				err = fmt.Errorf("failed to resume utility VM %s: %s", uvm.id, rerr)
+			}
+		}
+	}()
+
+	err = uvm.hcsSystem.Save(ctx, &hcsschema.SaveOptions{
+		SaveType:          "ToFile",
+		SaveStateFilePath: path,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save utility VM %s: %s", uvm.id, err)
+	}
+	if err := ioutil.WriteFile(path+savedStateSuffix, b, 0600); err != nil {
+		os.Remove(path)
+		return err
+	}
+	log.G(ctx).WithFields(logrus.Fields{
+		logfields.UVMID: uvm.id,
+		"path":          path,
+	}).Debug("saved utility VM")
+	if exit {
+		if err := uvm.hcsSystem.Terminate(ctx); err != nil {
+			return fmt.Errorf("failed to terminate saved utility VM %s: %s", uvm.id, err)
+		}
+	}
+	return nil
+}
+
+// Restore creates the utility VM `id` from the state saved to the file `path`
+// by Save, with the devices and settings of the description saved next to it.
+// If `id` is empty the ID of the saved utility VM is used, which must have
+// exited. As with Create, the restored utility VM must be started with Start,
+// which reconnects its guest connection, and closed with Close.
+//
+// The output of the guest and its serial console are not captured from a
+// restored utility VM.
+func Restore(ctx context.Context, id, path string) (_ *UtilityVM, err error) {
+	ctx, span := trace.StartSpan(ctx, "uvm::Restore")
+	defer span.End()
+	defer func() { oc.SetSpanStatus(span, err) }()
+	span.AddAttributes(trace.StringAttribute("path", path))
+
+	b, err := ioutil.ReadFile(path + savedStateSuffix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the description of the saved utility VM: %s", err)
+	}
+	var saved savedUtilityVM
+	if err := json.Unmarshal(b, &saved); err != nil {
+		return nil, fmt.Errorf("failed to read the description of the saved utility VM: %s", err)
+	}
+	if id == "" {
+		id = saved.ID
+	}
+	span.AddAttributes(trace.StringAttribute(logfields.UVMID, id))
+
+	uvm, err := fromDescription(&saved)
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore the devices of utility VM %s: %s", id, err)
+	}
+	uvm.id = id
+	// The saved utility VM has already booted its first container.
+	uvm.firstContainer = 1
+
+	doc, err := restoreDocument(saved.Document, path)
+	if err != nil {
+		return nil, err
+	}
+	if err := uvm.create(ctx, doc); err != nil {
+		return nil, fmt.Errorf("failed to restore utility VM %s: %s", id, err)
+	}
+	defer func() {
+		if err != nil {
+			uvm.Close()
+		}
+	}()
+	// Keep the document without the saved state, as it is saved again.
+	uvm.document = saved.Document
+
+	if saved.ExternalGuestConnection {
+		// The GCS connects again once the restored utility VM is resumed.
+		serviceID := gcs.WindowsGcsHvsockServiceID
+		if uvm.operatingSystem == "linux" {
+			serviceID = winio.VsockServiceID(gcs.LinuxGcsVsockPort)
+		}
+		l, err := winio.ListenHvsock(&winio.HvsockAddr{
+			VMID:      uvm.runtimeID,
+			ServiceID: serviceID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		uvm.gcListener = l
+	}
+
+	log.G(ctx).WithFields(logrus.Fields{
+		logfields.UVMID: uvm.id,
+		"path":          path,
+	}).Debug("restored utility VM")
+	return uvm, nil
+}
+
+// restoreDocument returns a copy of the HCS document `doc` of a saved utility
+// VM that creates it from the state saved to the file `path`.
+func restoreDocument(doc map[string]interface{}, path string) (map[string]interface{}, error) {
+	restored, err := toDocument(doc)
+	if err != nil {
+		return nil, err
+	}
+	vm, ok := restored["VirtualMachine"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("the saved document has no virtual machine")
+	}
+	vm["RestoreState"] = &hcsschema.RestoreState{SaveStateFilePath: path}
+	return restored, nil
+}
+
+// describe returns the description of the utility VM that is saved with its
+// state. `uvm.m` must be held.
+func (uvm *UtilityVM) describe() (*savedUtilityVM, error) {
+	uvm.documentLock.Lock()
+	doc, err := toDocument(uvm.document)
+	uvm.documentLock.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	saved := &savedUtilityVM{
+		ID:                      uvm.id,
+		SystemID:                uvm.systemID,
+		Owner:                   uvm.owner,
+		OperatingSystem:         uvm.operatingSystem,
+		Document:                doc,
+		ExternalGuestConnection: uvm.gc != nil,
+		ProcessorCount:          uvm.processorCount,
+		AllowOvercommit:         uvm.allowOvercommit,
+		Recoverable:             uvm.recoverable,
+		WorkingSetTrimPolicy:    uvm.WorkingSetTrimState().Policy,
+		ContainerCounter:        atomic.LoadUint64(&uvm.containerCounter),
+		SCSIControllerCount:     uvm.scsiControllerCount,
+		VPMemMaxCount:           uvm.vpmemMaxCount,
+		VPMemMaxSizeBytes:       uvm.vpmemMaxSizeBytes,
+		Plan9Counter:            uvm.plan9Counter,
+		VSMBCounter:             uvm.vsmbCounter,
+		Namespaces:              make(map[string][]*nicInfo),
+		ConsolePipe:             uvm.consolePipe,
+	}
+	if uvm.output != nil {
+		saved.OutputPipe = uvm.output.pipe
+	} else {
+		saved.OutputPipe = uvm.outputPipe
+	}
+	if saved.ConsolePipe == "" {
+		saved.ConsolePipe = uvm.attachConsolePipe
+	}
+	for controller := range uvm.scsiLocations {
+		for lun, info := range uvm.scsiLocations[controller] {
+			if info.hostPath == "" {
+				continue
+			}
+			saved.SCSI = append(saved.SCSI, savedSCSI{
+				Controller: controller,
+				LUN:        lun,
+				HostPath:   info.hostPath,
+				UVMPath:    info.uvmPath,
+				IsLayer:    info.isLayer,
+				RefCount:   info.refCount,
+			})
+		}
+	}
+	for i, info := range uvm.vpmemDevices {
+		if info.hostPath == "" {
+			continue
+		}
+		saved.VPMem = append(saved.VPMem, savedVPMem{
+			DeviceNumber: i,
+			HostPath:     info.hostPath,
+			UVMPath:      info.uvmPath,
+			RefCount:     info.refCount,
+		})
+	}
+	for name, info := range uvm.plan9Shares {
+		saved.Plan9 = append(saved.Plan9, savedPlan9{
+			Name:     name,
+			HostPath: info.hostPath,
+			UVMPath:  info.uvmPath,
+		})
+	}
+	for i, m := range []map[string]*vsmbShare{uvm.vsmbDirShares, uvm.vsmbFileShares} {
+		for hostPath, share := range m {
+			saved.VSMB = append(saved.VSMB, savedVSMB{
+				HostPath:     hostPath,
+				Name:         share.name,
+				RefCount:     share.refCount,
+				AllowedFiles: share.allowedFiles,
+				File:         i == 1,
+			})
+		}
+	}
+	for id, ns := range uvm.namespaces {
+		nics := []*nicInfo{}
+		for _, nic := range ns.nics {
+			if nic != nil {
+				nics = append(nics, nic)
+			}
+		}
+		saved.Namespaces[id] = nics
+	}
+	return saved, nil
+}
+
+// fromDescription returns a utility VM, not yet created or opened, with the
+// settings and devices of the described utility VM `saved`.
+func fromDescription(saved *savedUtilityVM) (*UtilityVM, error) {
+	uvm := &UtilityVM{
+		id:                  saved.ID,
+		owner:               saved.Owner,
+		operatingSystem:     saved.OperatingSystem,
+		processorCount:      saved.ProcessorCount,
+		allowOvercommit:     saved.AllowOvercommit,
+		recoverable:         saved.Recoverable,
+		containerCounter:    saved.ContainerCounter,
+		scsiControllerCount: saved.SCSIControllerCount,
+		vpmemMaxCount:       saved.VPMemMaxCount,
+		vpmemMaxSizeBytes:   saved.VPMemMaxSizeBytes,
+		plan9Counter:        saved.Plan9Counter,
+		vsmbCounter:         saved.VSMBCounter,
+	}
+	uvm.trim.state.Policy = saved.WorkingSetTrimPolicy
+	if err := uvm.restoreDevices(saved); err != nil {
+		return nil, err
+	}
+	return uvm, nil
+}
+
+// restoreDevices restores the devices of the saved utility VM `saved`.
+func (uvm *UtilityVM) restoreDevices(saved *savedUtilityVM) error {
+	for _, s := range saved.SCSI {
+		if s.Controller >= len(uvm.scsiLocations) || s.LUN >= len(uvm.scsiLocations[s.Controller]) {
+			return fmt.Errorf("invalid SCSI location %d:%d", s.Controller, s.LUN)
+		}
+		uvm.scsiLocations[s.Controller][s.LUN] = scsiInfo{
+			hostPath: s.HostPath,
+			uvmPath:  s.UVMPath,
+			isLayer:  s.IsLayer,
+			refCount: s.RefCount,
+		}
+	}
+	for _, v := range saved.VPMem {
+		if v.DeviceNumber >= len(uvm.vpmemDevices) {
+			return fmt.Errorf("invalid VPMem device %d", v.DeviceNumber)
+		}
+		uvm.vpmemDevices[v.DeviceNumber] = vpmemInfo{
+			hostPath: v.HostPath,
+			uvmPath:  v.UVMPath,
+			refCount: v.RefCount,
+		}
+		uvm.vpmemNumDevices++
+	}
+	for _, p := range saved.Plan9 {
+		if uvm.plan9Shares == nil {
+			uvm.plan9Shares = make(map[string]plan9Info)
+		}
+		uvm.plan9Shares[p.Name] = plan9Info{hostPath: p.HostPath, uvmPath: p.UVMPath}
+	}
+	if uvm.operatingSystem == "windows" {
+		uvm.vsmbDirShares = make(map[string]*vsmbShare)
+		uvm.vsmbFileShares = make(map[string]*vsmbShare)
+	}
+	for _, s := range saved.VSMB {
+		m := uvm.vsmbDirShares
+		if s.File {
+			m = uvm.vsmbFileShares
+		}
+		if m == nil {
+			return fmt.Errorf("VSMB share %s in a %s utility VM", s.Name, uvm.operatingSystem)
+		}
+		m[s.HostPath] = &vsmbShare{
+			refCount:     s.RefCount,
+			name:         s.Name,
+			allowedFiles: s.AllowedFiles,
+		}
+	}
+	for id, nics := range saved.Namespaces {
+		if uvm.namespaces == nil {
+			uvm.namespaces = make(map[string]*namespaceInfo)
+		}
+		ns := &namespaceInfo{nics: make(map[string]*nicInfo)}
+		for _, nic := range nics {
+			ns.nics[nic.Endpoint.Id] = nic
+		}
+		uvm.namespaces[id] = ns
+	}
+	return nil
+}
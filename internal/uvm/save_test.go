@@ -0,0 +1,140 @@
+package uvm
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/Microsoft/hcsshim/internal/hns"
+	"github.com/Microsoft/hcsshim/internal/requesttype"
+	hcsschema "github.com/Microsoft/hcsshim/internal/schema2"
+)
+
+func TestPatchDocument(t *testing.T) {
+	doc, err := toDocument(&hcsschema.ComputeSystem{
+		VirtualMachine: &hcsschema.VirtualMachine{
+			ComputeTopology: &hcsschema.Topology{
+				Memory:    &hcsschema.Memory2{SizeInMB: 1024},
+				Processor: &hcsschema.Processor2{Count: 2},
+			},
+			Devices: &hcsschema.Devices{
+				Scsi: map[string]hcsschema.Scsi{"0": {}},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, m := range []struct {
+		path        string
+		requestType string
+		settings    interface{}
+	}{
+		{"VirtualMachine/Devices/Scsi/0/Attachments/0", requesttype.Add, hcsschema.Attachment{Path: "a.vhdx", Type_: "VirtualDisk"}},
+		{"VirtualMachine/Devices/Scsi/0/Attachments/1", requesttype.Add, hcsschema.Attachment{Path: "b.vhdx", Type_: "VirtualDisk"}},
+		{"VirtualMachine/Devices/Scsi/0/Attachments/0", requesttype.Remove, nil},
+		{"VirtualMachine/Devices/VirtualPMem/Devices/3", requesttype.Remove, nil},
+		{"VirtualMachine/Devices/Plan9/Shares", requesttype.Add, hcsschema.Plan9Share{Name: "1", Path: "p1"}},
+		{"VirtualMachine/Devices/Plan9/Shares", requesttype.Add, hcsschema.Plan9Share{Name: "2", Path: "p2"}},
+		{"VirtualMachine/Devices/Plan9/Shares", requesttype.Remove, hcsschema.Plan9Share{Name: "1"}},
+		{"VirtualMachine/Devices/VirtualSmb/Shares", requesttype.Add, hcsschema.VirtualSmbShare{Name: "s1", Path: "d"}},
+		{"VirtualMachine/Devices/VirtualSmb/Shares", requesttype.Update, hcsschema.VirtualSmbShare{Name: "s1", Path: "d", AllowedFiles: []string{"f"}}},
+		{memoryResourcePath, requesttype.Update, 2048},
+		{processorLimitsResourcePath, requesttype.Update, hcsschema.ProcessorLimits{Limit: 5000}},
+	} {
+		if err := patchDocument(doc, m.path, m.requestType, m.settings); err != nil {
+			t.Fatalf("failed to %s %s: %s", m.requestType, m.path, err)
+		}
+	}
+
+	var expected map[string]interface{}
+	if err := json.Unmarshal([]byte(`{
+		"VirtualMachine": {
+			"ComputeTopology": {
+				"Memory": {"SizeInMB": 2048},
+				"Processor": {"Count": 2, "Limit": 5000}
+			},
+			"Devices": {
+				"Scsi": {"0": {"Attachments": {"1": {"Path": "b.vhdx", "Type": "VirtualDisk"}}}},
+				"Plan9": {"Shares": [{"Name": "2", "Path": "p2"}]},
+				"VirtualSmb": {"Shares": [{"Name": "s1", "Path": "d", "AllowedFiles": ["f"]}]}
+			}
+		}
+	}`), &expected); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(doc, expected) {
+		b, _ := json.Marshal(doc)
+		t.Fatalf("unexpected document %s", b)
+	}
+}
+
+func TestRestoreDocument(t *testing.T) {
+	doc := map[string]interface{}{
+		"Owner":          "test",
+		"VirtualMachine": map[string]interface{}{"Chipset": map[string]interface{}{}},
+	}
+	restored, err := restoreDocument(doc, `C:\saved\uvm.vmrs`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vm := restored["VirtualMachine"].(map[string]interface{})
+	if rs, ok := vm["RestoreState"].(*hcsschema.RestoreState); !ok || rs.SaveStateFilePath != `C:\saved\uvm.vmrs` {
+		t.Fatalf("expected the saved state to be restored, got %v", vm["RestoreState"])
+	}
+	if restored["Owner"] != "test" || vm["Chipset"] == nil {
+		t.Fatalf("expected the rest of the document to be kept, got %v", restored)
+	}
+	// The saved document is kept as is, as the utility VM is saved with it.
+	if _, ok := doc["VirtualMachine"].(map[string]interface{})["RestoreState"]; ok {
+		t.Fatal("expected the saved document not to be modified")
+	}
+
+	if _, err := restoreDocument(map[string]interface{}{}, "uvm.vmrs"); err == nil {
+		t.Fatal("expected an error for a document without a virtual machine")
+	}
+}
+
+func TestDescriptionRoundTrip(t *testing.T) {
+	saved := &savedUtilityVM{
+		ID:                  "vm",
+		SystemID:            "vm",
+		Owner:               "test",
+		OperatingSystem:     "windows",
+		Document:            map[string]interface{}{"Owner": "test"},
+		ProcessorCount:      2,
+		Recoverable:         true,
+		ContainerCounter:    3,
+		SCSIControllerCount: 1,
+		SCSI:                []savedSCSI{{Controller: 0, LUN: 1, HostPath: "a.vhdx", UVMPath: "C:\\a", RefCount: 2}},
+		VSMBCounter:         1,
+		VSMB:                []savedVSMB{{HostPath: "C:\\share", Name: "s1", RefCount: 1}},
+		Namespaces:          map[string][]*nicInfo{"ns": {{Endpoint: &hns.HNSEndpoint{Id: "e"}}}},
+	}
+	b, err := json.Marshal(saved)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var read savedUtilityVM
+	if err := json.Unmarshal(b, &read); err != nil {
+		t.Fatal(err)
+	}
+	vm, err := fromDescription(&read)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vm.systemID = read.SystemID
+	vm.document = read.Document
+	described, err := vm.describe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	after, err := json.Marshal(described)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(after) != string(b) {
+		t.Fatalf("expected the description to be kept\n%s\ngot\n%s", b, after)
+	}
+}
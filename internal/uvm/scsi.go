@@ -25,8 +25,14 @@ var (
 // SCSI controllers associated with a utility VM to use.
 // Lock must be held when calling this function
 func (uvm *UtilityVM) allocateSCSI(ctx context.Context, hostPath string, uvmPath string, isLayer bool) (int, int32, error) {
-	for controller, luns := range uvm.scsiLocations {
-		for lun, si := range luns {
+	controllers := int(uvm.scsiControllerCount)
+	if uvm.operatingSystem == "windows" && uvmPath != "" {
+		// The WCOW guest identifies a disk to mount by LUN only, so disks
+		// mounted in the guest must be on the first controller.
+		controllers = 1
+	}
+	for controller := 0; controller < controllers; controller++ {
+		for lun, si := range uvm.scsiLocations[controller] {
 			if si.hostPath == "" {
 				uvm.scsiLocations[controller][lun].hostPath = hostPath
 				uvm.scsiLocations[controller][lun].uvmPath = uvmPath
@@ -150,7 +156,7 @@ func (uvm *UtilityVM) addSCSIActual(ctx context.Context, hostPath, uvmPath, atta
 
 	// Ensure the utility VM has access
 	if !isLayer {
-		if err := wclayer.GrantVmAccess(uvm.id, hostPath); err != nil {
+		if err := wclayer.GrantVmAccess(uvm.systemID, hostPath); err != nil {
 			return -1, -1, err
 		}
 	}
@@ -195,11 +201,6 @@ func (uvm *UtilityVM) addSCSIActual(ctx context.Context, hostPath, uvmPath, atta
 	// See comment higher up. Now safe to release the lock.
 	uvm.m.Unlock()
 
-	// Note: Can remove this check post-RS5 if multiple controllers are supported
-	if controller > 0 {
-		return -1, -1, ErrTooManyAttachments
-	}
-
 	SCSIModification := &hcsschema.ModifySettingRequest{
 		RequestType: requesttype.Add,
 		Settings: hcsschema.Attachment{
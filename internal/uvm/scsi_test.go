@@ -0,0 +1,47 @@
+package uvm
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+func TestAllocateSCSIControllers(t *testing.T) {
+	ctx := context.Background()
+	uvm := &UtilityVM{operatingSystem: "linux", scsiControllerCount: 2}
+
+	for i := 0; i < 2*len(uvm.scsiLocations[0]); i++ {
+		controller, lun, err := uvm.allocateSCSI(ctx, fmt.Sprintf("disk%d", i), "", false)
+		if err != nil {
+			t.Fatalf("allocation %d: %s", i, err)
+		}
+		if controller != i/64 || lun != int32(i%64) {
+			t.Fatalf("allocation %d: got %d:%d", i, controller, lun)
+		}
+	}
+	if _, _, err := uvm.allocateSCSI(ctx, "full", "", false); err != ErrNoAvailableLocation {
+		t.Fatalf("expected ErrNoAvailableLocation beyond the controller count, got %v", err)
+	}
+
+	uvm.scsiLocations[1][5] = scsiInfo{}
+	controller, lun, err := uvm.allocateSCSI(ctx, "reused", "", false)
+	if err != nil || controller != 1 || lun != 5 {
+		t.Fatalf("expected the freed location 1:5, got %d:%d %v", controller, lun, err)
+	}
+}
+
+func TestAllocateSCSIWCOWGuestMount(t *testing.T) {
+	ctx := context.Background()
+	uvm := &UtilityVM{operatingSystem: "windows", scsiControllerCount: 2}
+
+	for lun := range uvm.scsiLocations[0] {
+		uvm.scsiLocations[0][lun].hostPath = fmt.Sprintf("disk%d", lun)
+	}
+	if _, _, err := uvm.allocateSCSI(ctx, "mounted", `C:\mount`, false); err != ErrNoAvailableLocation {
+		t.Fatalf("expected a guest mounted disk to be limited to controller 0, got %v", err)
+	}
+	controller, lun, err := uvm.allocateSCSI(ctx, "attached", "", false)
+	if err != nil || controller != 1 || lun != 0 {
+		t.Fatalf("expected an attach-only disk at 1:0, got %d:%d %v", controller, lun, err)
+	}
+}
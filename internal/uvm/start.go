@@ -150,6 +150,12 @@ func (uvm *UtilityVM) Start(ctx context.Context) (err error) {
 				close(uvm.outputProcessingDone)
 				return fmt.Errorf("failed to connect to log socket: %s", err)
 			}
+			if uvm.recoverable {
+				// Relay the output so that it can be handed to the process
+				// that reopens the utility VM once released.
+				uvm.output = newOutputRelay(uvm.id, outputPipeName(uvm.systemID), conn, uvm.outputHandler, uvm.outputProcessingDone)
+				return nil
+			}
 			go func() {
 				uvm.outputHandler(conn)
 				close(uvm.outputProcessingDone)
@@ -158,7 +164,9 @@ func (uvm *UtilityVM) Start(ctx context.Context) (err error) {
 		})
 	}
 
+	_, endHCSStart := uvm.startBootPhase(ctx, bootPhaseHCSStart)
 	err = uvm.hcsSystem.Start(ctx)
+	endHCSStart(err)
 	if err != nil {
 		return err
 	}
@@ -169,16 +177,18 @@ func (uvm *UtilityVM) Start(ctx context.Context) (err error) {
 		}
 	}()
 
+	// The serial console pipe is served by the utility VM once started.
+	if uvm.consolePipe != "" {
+		uvm.consoleDone = make(chan struct{})
+		go uvm.readConsole()
+	}
+
 	// Start waiting on the utility VM.
 	uvm.exitCh = make(chan struct{})
-	go func() {
-		err := uvm.hcsSystem.Wait()
-		if err == nil {
-			err = uvm.hcsSystem.ExitError()
-		}
-		uvm.exitErr = err
-		close(uvm.exitCh)
-	}()
+	go uvm.waitBackground()
+
+	_, endGuestConnect := uvm.startBootPhase(ctx, bootPhaseGuestConnect)
+	defer func() { endGuestConnect(err) }()
 
 	// Collect any errors from writing entropy or establishing the log
 	// connection.
@@ -193,6 +203,9 @@ func (uvm *UtilityVM) Start(ctx context.Context) (err error) {
 		if err != nil {
 			return fmt.Errorf("failed to connect to GCS: %s", err)
 		}
+		endGuestConnect(nil)
+
+		_, endGCSNegotiate := uvm.startBootPhase(ctx, bootPhaseGCSNegotiate)
 		// Start the GCS protocol.
 		gcc := &gcs.GuestConnectionConfig{
 			Conn:     conn,
@@ -200,20 +213,26 @@ func (uvm *UtilityVM) Start(ctx context.Context) (err error) {
 			IoListen: gcs.HvsockIoListen(uvm.runtimeID),
 		}
 		uvm.gc, err = gcc.Connect(ctx)
+		endGCSNegotiate(err)
 		if err != nil {
 			return err
 		}
 		uvm.guestCaps = *uvm.gc.Capabilities()
 		uvm.protocol = uvm.gc.Protocol()
 	} else {
+		endGuestConnect(nil)
+
 		// Cache the guest connection properties.
+		_, endGCSNegotiate := uvm.startBootPhase(ctx, bootPhaseGCSNegotiate)
 		properties, err := uvm.hcsSystem.Properties(ctx, schema1.PropertyTypeGuestConnection)
+		endGCSNegotiate(err)
 		if err != nil {
 			return err
 		}
 		uvm.guestCaps = properties.GuestConnectionInfo.GuestDefinedCapabilities
 		uvm.protocol = properties.GuestConnectionInfo.ProtocolVersion
 	}
+	uvm.startWorkingSetTrim()
 	return nil
 }
 
@@ -8,6 +8,7 @@ import (
 	"github.com/Microsoft/go-winio/pkg/process"
 	"github.com/Microsoft/hcsshim/cmd/containerd-shim-runhcs-v1/stats"
 	"github.com/Microsoft/hcsshim/internal/log"
+	"github.com/Microsoft/hcsshim/internal/logfields"
 	"github.com/Microsoft/hcsshim/internal/schema1"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
@@ -106,9 +107,24 @@ func (uvm *UtilityVM) getVMMEMProcess(ctx context.Context) (windows.Handle, erro
 	return uvm.vmmemProcess, uvm.vmmemErr
 }
 
+// MemoryWorkingSet returns the working set of the utility VM on the host, the
+// memory of the guest that is in use. All the memory of a utility VM backed by
+// physical memory is in use.
+func (uvm *UtilityVM) MemoryWorkingSet(ctx context.Context) (uint64, error) {
+	vmmemProc, err := uvm.getVMMEMProcess(ctx)
+	if err != nil {
+		return 0, err
+	}
+	memCounters, err := process.GetProcessMemoryInfo(vmmemProc)
+	if err != nil {
+		return 0, err
+	}
+	return uint64(memCounters.WorkingSetSize), nil
+}
+
 // Stats returns various UVM statistics.
 func (uvm *UtilityVM) Stats(ctx context.Context) (*stats.VirtualMachineStatistics, error) {
-	s := &stats.VirtualMachineStatistics{}
+	s := &stats.VirtualMachineStatistics{Boot: uvm.bootStatistics()}
 	statsV1, err := uvm.hcsSystem.Properties(ctx, schema1.PropertyTypeStatistics)
 	if err != nil {
 		return nil, err
@@ -120,16 +136,32 @@ func (uvm *UtilityVM) Stats(ctx context.Context) (*stats.VirtualMachineStatistic
 	// working set size for a VA-backed UVM. To work around this, we instead
 	// locate the vmmem process for the VM, and query that process's working set
 	// instead, which will be the working set for the VM.
-	vmmemProc, err := uvm.getVMMEMProcess(ctx)
-	if err != nil {
-		return nil, err
-	}
-	memCounters, err := process.GetProcessMemoryInfo(vmmemProc)
+	workingSet, err := uvm.MemoryWorkingSet(ctx)
 	if err != nil {
 		return nil, err
 	}
+	trim := uvm.WorkingSetTrimState()
 	s.Memory = &stats.VirtualMachineMemoryStatistics{
-		WorkingSetBytes: uint64(memCounters.WorkingSetSize),
+		WorkingSetBytes:     workingSet,
+		GuestAvailableBytes: uvm.guestAvailableMemory(ctx),
+		TrimmedBytes:        trim.TrimmedBytes,
+		Trims:               trim.Trims,
+		Idle:                trim.Idle,
+		LastTrim:            trim.LastTrim,
+		LastTrimmedBytes:    trim.LastTrimmedBytes,
+	}
+
+	// The memory the host assigned to the utility VM, and whether it is
+	// balanced with dynamic memory, is not reported by every host.
+	props, err := uvm.hcsSystem.PropertiesV2(ctx, schema1.PropertyTypeMemory)
+	if err != nil {
+		log.G(ctx).WithField(logfields.UVMID, uvm.id).WithError(err).Debug("failed to query utility VM memory")
+	} else if props.Memory != nil && props.Memory.VirtualMachineMemory != nil {
+		m := props.Memory.VirtualMachineMemory
+		// Assigned memory is reported in 4KB pages.
+		s.Memory.AssignedBytes = uint64(m.AssignedMemory) * 4096
+		s.Memory.BalancingEnabled = m.BalancingEnabled
+		s.Memory.BalancingInProgress = m.DmOperationInProgress
 	}
 
 	return s, nil
@@ -49,6 +49,13 @@ type vpmemInfo struct {
 	refCount uint32
 }
 
+// plan9Info is an internal structure used for reporting the Plan9 shares
+// mapped to a Linux utility VM.
+type plan9Info struct {
+	hostPath string
+	uvmPath  string
+}
+
 type nicInfo struct {
 	ID       guid.GUID
 	Endpoint *hns.HNSEndpoint
@@ -60,7 +67,8 @@ type namespaceInfo struct {
 
 // UtilityVM is the object used by clients representing a utility VM
 type UtilityVM struct {
-	id              string               // Identifier for the utility VM (user supplied or generated)
+	id              string               // Identifier for the utility VM (user supplied or generated, or set by Rename)
+	systemID        string               // Identifier of the compute system, which is `id` unless the utility VM was renamed
 	runtimeID       guid.GUID            // Hyper-V VM ID
 	owner           string               // Owner for the utility VM (user supplied or generated)
 	operatingSystem string               // "windows" or "linux"
@@ -68,11 +76,34 @@ type UtilityVM struct {
 	gcListener      net.Listener         // The GCS connection listener
 	gc              *gcs.GuestConnection // The GCS connection
 	processorCount  int32
+	allowOvercommit bool       // Whether the memory of the utility VM is backed by virtual memory
+	recoverable     bool       // Whether the utility VM and its containers outlive their handles. See Options.Recoverable
 	m               sync.Mutex // Lock for adding/removing devices
 
+	// trim is the working set trim policy and state.
+	trim workingSetTrim
+	// guestMemory caches the memory available in a Linux guest.
+	guestMemory guestMemoryInfo
+
+	// document is the HCS document of the utility VM, updated with the host
+	// resources added, updated and removed since it was created so that it
+	// matches the state saved by Save.
+	document     map[string]interface{}
+	documentLock sync.Mutex // Lock for `document`, which is updated in Modify
+
 	exitErr error
 	exitCh  chan struct{}
 
+	// bootDurations are the durations in nanoseconds of the boot phases that
+	// have completed, by `bootPhase`.
+	//
+	// NOTE: All accesses to this MUST be done atomically.
+	bootDurations [bootPhaseCount]int64
+	// firstContainer is set once the first container in the UVM is created.
+	//
+	// NOTE: All accesses to this MUST be done atomically.
+	firstContainer uint32
+
 	// GCS bridge protocol and capabilities
 	protocol  uint32
 	guestCaps schema1.GuestDefinedCapabilities
@@ -101,17 +132,38 @@ type UtilityVM struct {
 	vpmemMaxSizeBytes uint64                   // Actual max size of VPMem devices
 
 	// SCSI devices that are mapped into a Windows or Linux utility VM
-	scsiLocations       [4][64]scsiInfo // Hyper-V supports 4 controllers, 64 slots per controller. Limited to 1 controller for now though.
-	scsiControllerCount uint32          // Number of SCSI controllers in the utility VM
+	scsiLocations       [MaxSCSIControllers][64]scsiInfo // Hyper-V supports 4 controllers, 64 slots per controller.
+	scsiControllerCount uint32                           // Number of SCSI controllers in the utility VM
 
 	// Plan9 are directories mapped into a Linux utility VM
-	plan9Counter uint64 // Each newly-added plan9 share has a counter used as its ID in the ResourceURI and for the name
+	plan9Counter uint64               // Each newly-added plan9 share has a counter used as its ID in the ResourceURI and for the name
+	plan9Shares  map[string]plan9Info // Plan9 shares by name
 
 	namespaces map[string]*namespaceInfo
 
 	outputListener       net.Listener
 	outputProcessingDone chan struct{}
 	outputHandler        OutputHandler
+	// The output of a recoverable utility VM, relayed so that it can be handed
+	// to the process that reopens it.
+	output *outputRelay
+	// The pipes that the output and the serial console of an opened utility
+	// VM are served on, if any, for AttachOutput.
+	outputPipe        string
+	attachConsolePipe string
+
+	// Guest logs captured to rotated files under `logCaptureDir` by source.
+	logCaptureDir      string
+	logCaptureMaxFiles int
+	logCaptures        map[string]*logCapture
+
+	// The serial console of a Linux utility VM, if captured or scanned for
+	// kernel panics.
+	consolePipe     string
+	consoleDone     chan struct{} // Closed once the serial console is read to its end
+	consoleConn     net.Conn      // Closed on Release so that the reopening process can connect
+	panics          *panicDetector
+	panicReportPath string
 
 	entropyListener net.Listener
 
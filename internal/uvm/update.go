@@ -0,0 +1,86 @@
+package uvm
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Microsoft/hcsshim/internal/log"
+	"github.com/Microsoft/hcsshim/internal/logfields"
+	"github.com/Microsoft/hcsshim/internal/requesttype"
+	hcsschema "github.com/Microsoft/hcsshim/internal/schema2"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	memoryResourcePath          = "VirtualMachine/ComputeTopology/Memory/SizeInMB"
+	processorLimitsResourcePath = "VirtualMachine/ComputeTopology/Processor/Limits"
+)
+
+// MemorySizeInMB returns the memory assigned to the utility VM, as it was
+// created with or last updated to with UpdateMemory.
+func (uvm *UtilityVM) MemorySizeInMB() int32 {
+	uvm.documentLock.Lock()
+	defer uvm.documentLock.Unlock()
+	vm, _ := uvm.document["VirtualMachine"].(map[string]interface{})
+	topology, _ := vm["ComputeTopology"].(map[string]interface{})
+	memory, _ := topology["Memory"].(map[string]interface{})
+	size, _ := memory["SizeInMB"].(float64)
+	return int32(size)
+}
+
+// UpdateMemory changes the memory assigned to a running utility VM to
+// `sizeInMB`, aligned up to 2MB. Shrinking the utility VM relies on the guest
+// releasing the memory and may only partially succeed.
+func (uvm *UtilityVM) UpdateMemory(ctx context.Context, sizeInMB int32) error {
+	if sizeInMB <= 0 {
+		return fmt.Errorf("invalid memory size %dMB", sizeInMB)
+	}
+	actual := uvm.normalizeMemorySize(ctx, sizeInMB)
+	req := &hcsschema.ModifySettingRequest{
+		RequestType:  requesttype.Update,
+		ResourcePath: memoryResourcePath,
+		Settings:     actual,
+	}
+	if err := uvm.Modify(ctx, req); err != nil {
+		return fmt.Errorf("failed to update memory of utility VM %s: %s", uvm.id, err)
+	}
+	log.G(ctx).WithFields(logrus.Fields{
+		logfields.UVMID: uvm.id,
+		"sizeInMB":      actual,
+	}).Debug("updated utility VM memory")
+	return nil
+}
+
+// UpdateProcessor changes the processor limit and weight of a running utility
+// VM. A value of `0` leaves the setting unchanged.
+//
+// The HCS does not support changing the vCPU count of a running utility VM, so
+// `count` must be `0` or the current count.
+func (uvm *UtilityVM) UpdateProcessor(ctx context.Context, count, limit, weight int32) error {
+	if count != 0 && count != uvm.processorCount {
+		return fmt.Errorf("cannot change the processor count of running utility VM %s from %d to %d: %s", uvm.id, uvm.processorCount, count, errNotSupported)
+	}
+	if limit < 0 || weight < 0 {
+		return fmt.Errorf("invalid processor limit %d or weight %d", limit, weight)
+	}
+	if limit == 0 && weight == 0 {
+		return nil
+	}
+	req := &hcsschema.ModifySettingRequest{
+		RequestType:  requesttype.Update,
+		ResourcePath: processorLimitsResourcePath,
+		Settings: hcsschema.ProcessorLimits{
+			Limit:  uint64(limit),
+			Weight: uint64(weight),
+		},
+	}
+	if err := uvm.Modify(ctx, req); err != nil {
+		return fmt.Errorf("failed to update processor limits of utility VM %s: %s", uvm.id, err)
+	}
+	log.G(ctx).WithFields(logrus.Fields{
+		logfields.UVMID: uvm.id,
+		"limit":         limit,
+		"weight":        weight,
+	}).Debug("updated utility VM processor limits")
+	return nil
+}
@@ -8,11 +8,43 @@ import (
 // Wait waits synchronously for a utility VM to terminate.
 func (uvm *UtilityVM) Wait() error {
 	err := uvm.hcsSystem.Wait()
+	// Wait for the exit to be recorded so that Exited is true once Wait
+	// returns.
+	if uvm.exitCh != nil {
+		<-uvm.exitCh
+	}
 
 	logrus.WithField(logfields.UVMID, uvm.id).Debug("uvm exited, waiting for output processing to complete")
 	if uvm.outputProcessingDone != nil {
 		<-uvm.outputProcessingDone
 	}
+	if uvm.consoleDone != nil {
+		<-uvm.consoleDone
+	}
 
 	return err
 }
+
+// waitBackground waits for the utility VM to terminate, then records its exit
+// error and closes `uvm.exitCh`.
+//
+// This MUST be called via a goroutine once the utility VM is started.
+func (uvm *UtilityVM) waitBackground() {
+	err := uvm.hcsSystem.Wait()
+	if err == nil {
+		err = uvm.hcsSystem.ExitError()
+	}
+	uvm.exitErr = err
+	close(uvm.exitCh)
+}
+
+// Exited returns true if the utility VM has terminated. Once it returns true
+// ExitError returns the reason the utility VM terminated, if unexpected.
+func (uvm *UtilityVM) Exited() bool {
+	select {
+	case <-uvm.exitCh:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,30 @@
+package uvmpool
+
+import "unsafe"
+
+//go:generate go run ../../mksyscall_windows.go -output zsyscall_windows.go memory.go
+
+//sys globalMemoryStatusEx(buffer *memoryStatusEx) (err error) = kernel32.GlobalMemoryStatusEx
+
+// memoryStatusEx is MEMORYSTATUSEX.
+type memoryStatusEx struct {
+	Length               uint32
+	MemoryLoad           uint32
+	TotalPhys            uint64
+	AvailPhys            uint64
+	TotalPageFile        uint64
+	AvailPageFile        uint64
+	TotalVirtual         uint64
+	AvailVirtual         uint64
+	AvailExtendedVirtual uint64
+}
+
+// availableMemoryInMB returns the physical memory available on the host.
+func availableMemoryInMB() (uint64, error) {
+	s := memoryStatusEx{}
+	s.Length = uint32(unsafe.Sizeof(s))
+	if err := globalMemoryStatusEx(&s); err != nil {
+		return 0, err
+	}
+	return s.AvailPhys / (1024 * 1024), nil
+}
@@ -0,0 +1,450 @@
+// Package uvmpool keeps booted, idle utility VMs ready to be handed to new
+// pods so that they do not pay for a utility VM boot.
+//
+// The pool runs in a long-lived process shared by the shims of the pods, which
+// serves it with Serve. A shim takes an idle utility VM from the pool with Get,
+// which reopens it in the shim with uvm.Open and renames it for its pod.
+package uvmpool
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/Microsoft/hcsshim/internal/log"
+	"github.com/Microsoft/hcsshim/internal/logfields"
+	"github.com/Microsoft/hcsshim/internal/uvm"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultHealthCheckInterval is how often idle utility VMs are checked if
+	// `Config.HealthCheckInterval` is not set.
+	DefaultHealthCheckInterval = 30 * time.Second
+
+	// healthCheckTimeout bounds the health check of a single utility VM.
+	healthCheckTimeout = 10 * time.Second
+)
+
+// Config configures a Pool.
+type Config struct {
+	// Size is the number of idle utility VMs kept for each configuration. If
+	// `0` every utility VM is created on demand.
+	Size int
+
+	// MaxIdleAge is how long a utility VM may stay idle before it is replaced
+	// with a freshly booted one. If `0` idle utility VMs never expire.
+	MaxIdleAge time.Duration
+
+	// HealthCheckInterval is how often idle utility VMs are health checked,
+	// expired and replenished. Defaults to `DefaultHealthCheckInterval`.
+	HealthCheckInterval time.Duration
+
+	// MinAvailableMemoryInMB is the host memory that must remain available.
+	// While less is available, idle utility VMs are evicted oldest first and
+	// no new ones are booted. If `0` memory pressure is ignored.
+	MinAvailableMemoryInMB uint64
+}
+
+// instance is the part of a utility VM used by the pool.
+type instance interface {
+	ID() string
+	Close() error
+	PingGuest(ctx context.Context) (time.Duration, error)
+	Describe() ([]byte, error)
+	Release() error
+	OutputRelayed() <-chan struct{}
+}
+
+type idleVM struct {
+	vm         instance
+	hash       string
+	memoryInMB uint64
+	created    time.Time
+}
+
+// Pool is a set of booted, idle LCOW utility VMs keyed by a hash of the
+// options they were created with. WCOW utility VMs are not pooled as their
+// scratch is created in the layer folders of the pod.
+//
+// Pooled utility VMs are created recoverable so that they keep running when
+// the pool hands them to another process, which means they use the guest
+// connection of the HCS. Their guest output is relayed to the process that
+// takes them, which captures it and their serial console as it asked for with
+// uvm.AttachOutput, so the pool process must keep running while they do.
+//
+// A configuration is added to the pool the first time a utility VM is
+// requested for it. The pool is then replenished in the background after
+// every request, and on every health check.
+type Pool struct {
+	config              Config
+	create              func(ctx context.Context, opts *uvm.OptionsLCOW) (instance, error)
+	availableMemoryInMB func() (uint64, error)
+	now                 func() time.Time
+
+	m       sync.Mutex
+	configs map[string]*uvm.OptionsLCOW
+	idle    []*idleVM // Oldest first
+	closed  bool
+	// lastRequest is when a utility VM was last requested from the pool.
+	lastRequest time.Time
+	// relaying is the number of utility VMs handed out whose output is still
+	// relayed by this process.
+	relaying int
+
+	kick chan struct{}
+	stop chan struct{}
+	done chan struct{}
+}
+
+// New creates a pool and starts its background health checks.
+func New(config Config) *Pool {
+	p := newPool(config, createLCOW, availableMemoryInMB, time.Now)
+	go p.run()
+	return p
+}
+
+func newPool(config Config, create func(context.Context, *uvm.OptionsLCOW) (instance, error), availableMemoryInMB func() (uint64, error), now func() time.Time) *Pool {
+	if config.HealthCheckInterval == 0 {
+		config.HealthCheckInterval = DefaultHealthCheckInterval
+	}
+	return &Pool{
+		config:              config,
+		create:              create,
+		availableMemoryInMB: availableMemoryInMB,
+		now:                 now,
+		configs:             make(map[string]*uvm.OptionsLCOW),
+		lastRequest:         now(),
+		kick:                make(chan struct{}, 1),
+		stop:                make(chan struct{}),
+		done:                make(chan struct{}),
+	}
+}
+
+// createLCOW creates and starts an LCOW utility VM.
+func createLCOW(ctx context.Context, opts *uvm.OptionsLCOW) (instance, error) {
+	vm, err := uvm.CreateLCOW(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := vm.Start(ctx); err != nil {
+		vm.Close()
+		return nil, err
+	}
+	return vm, nil
+}
+
+// take removes a healthy idle utility VM created with the same options as
+// `opts`, ignoring their ID, from the pool and returns it. It returns nil if
+// there is none. Either way the pool is then replenished in the background for
+// the next request for `opts`.
+func (p *Pool) take(ctx context.Context, opts *uvm.OptionsLCOW) (instance, error) {
+	if p.config.Size == 0 {
+		return nil, nil
+	}
+	hash, template, err := configHash(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	p.m.Lock()
+	p.lastRequest = p.now()
+	if p.closed {
+		p.m.Unlock()
+		return nil, nil
+	}
+	if _, ok := p.configs[hash]; !ok {
+		p.configs[hash] = template
+	}
+	p.m.Unlock()
+	defer p.replenish()
+
+	for {
+		v := p.takeIdle(hash)
+		if v == nil {
+			return nil, nil
+		}
+		if err := p.check(ctx, v); err != nil {
+			log.G(ctx).WithError(err).WithField(logfields.UVMID, v.vm.ID()).Warn("discarding unhealthy pooled utility VM")
+			v.vm.Close()
+			continue
+		}
+		log.G(ctx).WithFields(logrus.Fields{
+			logfields.UVMID: v.vm.ID(),
+			"idle":          p.now().Sub(v.created),
+		}).Debug("using pooled utility VM")
+		return v.vm, nil
+	}
+}
+
+// handedOut tracks the relayed output of `vm`, which was handed to another
+// process.
+func (p *Pool) handedOut(vm instance) {
+	p.m.Lock()
+	p.relaying++
+	p.m.Unlock()
+	go func() {
+		<-vm.OutputRelayed()
+		p.m.Lock()
+		p.relaying--
+		p.m.Unlock()
+	}()
+}
+
+// Relaying returns the number of utility VMs handed out whose output is still
+// relayed by the pool. The process serving the pool must not exit before it
+// drops to 0, or the guest output of those utility VMs is lost.
+func (p *Pool) Relaying() int {
+	p.m.Lock()
+	defer p.m.Unlock()
+	return p.relaying
+}
+
+// LastRequest returns when a utility VM was last requested from the pool, or
+// when the pool was created if none was requested yet.
+func (p *Pool) LastRequest() time.Time {
+	p.m.Lock()
+	defer p.m.Unlock()
+	return p.lastRequest
+}
+
+// takeIdle removes the oldest idle utility VM for `hash` from the pool.
+func (p *Pool) takeIdle(hash string) *idleVM {
+	p.m.Lock()
+	defer p.m.Unlock()
+	for i, v := range p.idle {
+		if v.hash == hash {
+			p.idle = append(p.idle[:i], p.idle[i+1:]...)
+			return v
+		}
+	}
+	return nil
+}
+
+// check returns an error if `v` is no longer usable.
+func (p *Pool) check(ctx context.Context, v *idleVM) error {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+	_, err := v.vm.PingGuest(ctx)
+	return err
+}
+
+// replenish schedules the pool to be refilled in the background.
+func (p *Pool) replenish() {
+	select {
+	case p.kick <- struct{}{}:
+	default:
+	}
+}
+
+func (p *Pool) run() {
+	defer close(p.done)
+	t := time.NewTicker(p.config.HealthCheckInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-t.C:
+			p.maintain(context.Background(), true)
+		case <-p.kick:
+			p.maintain(context.Background(), false)
+		}
+	}
+}
+
+// maintain evicts expired, unhealthy (if `healthCheck`) and, under memory
+// pressure, excess idle utility VMs, then refills the pool.
+func (p *Pool) maintain(ctx context.Context, healthCheck bool) {
+	now := p.now()
+	var evict []*idleVM
+	p.m.Lock()
+	idle := p.idle[:0]
+	for _, v := range p.idle {
+		if p.config.MaxIdleAge != 0 && now.Sub(v.created) > p.config.MaxIdleAge {
+			evict = append(evict, v)
+			continue
+		}
+		idle = append(idle, v)
+	}
+	p.idle = idle
+	p.m.Unlock()
+	p.evict(ctx, evict, "expired")
+
+	if healthCheck {
+		evict = nil
+		p.m.Lock()
+		idle := append([]*idleVM(nil), p.idle...)
+		p.m.Unlock()
+		for _, v := range idle {
+			if err := p.check(ctx, v); err != nil {
+				log.G(ctx).WithError(err).WithField(logfields.UVMID, v.vm.ID()).Warn("pooled utility VM failed health check")
+				if p.remove(v) {
+					evict = append(evict, v)
+				}
+			}
+		}
+		p.evict(ctx, evict, "unhealthy")
+	}
+
+	if p.relieveMemoryPressure(ctx) {
+		return
+	}
+	p.fill(ctx)
+}
+
+// remove removes `v` from the idle utility VMs. It returns false if `v` was
+// handed out in the meantime.
+func (p *Pool) remove(v *idleVM) bool {
+	p.m.Lock()
+	defer p.m.Unlock()
+	for i := range p.idle {
+		if p.idle[i] == v {
+			p.idle = append(p.idle[:i], p.idle[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (p *Pool) evict(ctx context.Context, vms []*idleVM, reason string) {
+	for _, v := range vms {
+		log.G(ctx).WithFields(logrus.Fields{
+			logfields.UVMID: v.vm.ID(),
+			"reason":        reason,
+		}).Info("evicting pooled utility VM")
+		if err := v.vm.Close(); err != nil {
+			log.G(ctx).WithError(err).WithField(logfields.UVMID, v.vm.ID()).Warn("failed to close pooled utility VM")
+		}
+	}
+}
+
+// relieveMemoryPressure evicts the oldest idle utility VMs until their memory
+// covers the shortfall below `Config.MinAvailableMemoryInMB`. It returns true
+// if the host was under memory pressure.
+func (p *Pool) relieveMemoryPressure(ctx context.Context) bool {
+	if p.config.MinAvailableMemoryInMB == 0 {
+		return false
+	}
+	available, err := p.availableMemoryInMB()
+	if err != nil {
+		log.G(ctx).WithError(err).Warn("failed to query available host memory")
+		return false
+	}
+	if available >= p.config.MinAvailableMemoryInMB {
+		return false
+	}
+	shortfall := p.config.MinAvailableMemoryInMB - available
+	var evict []*idleVM
+	p.m.Lock()
+	var freed uint64
+	for len(p.idle) > 0 && freed < shortfall {
+		evict = append(evict, p.idle[0])
+		freed += p.idle[0].memoryInMB
+		p.idle = p.idle[1:]
+	}
+	p.m.Unlock()
+	p.evict(ctx, evict, "memory pressure")
+	return true
+}
+
+// fill boots utility VMs until every configuration has `Config.Size` idle.
+func (p *Pool) fill(ctx context.Context) {
+	for {
+		p.m.Lock()
+		if p.closed {
+			p.m.Unlock()
+			return
+		}
+		counts := make(map[string]int)
+		for _, v := range p.idle {
+			counts[v.hash]++
+		}
+		var hash string
+		var opts *uvm.OptionsLCOW
+		for h, o := range p.configs {
+			if counts[h] < p.config.Size {
+				hash, opts = h, o
+				break
+			}
+		}
+		p.m.Unlock()
+		if opts == nil {
+			return
+		}
+
+		vm, err := p.create(ctx, copyOptions(opts))
+		if err != nil {
+			// Retried on the next health check.
+			log.G(ctx).WithError(err).Warn("failed to create pooled utility VM")
+			return
+		}
+		v := &idleVM{
+			vm:         vm,
+			hash:       hash,
+			memoryInMB: uint64(opts.MemorySizeInMB),
+			created:    p.now(),
+		}
+		p.m.Lock()
+		if p.closed {
+			p.m.Unlock()
+			p.evict(ctx, []*idleVM{v}, "pool closed")
+			return
+		}
+		p.idle = append(p.idle, v)
+		p.m.Unlock()
+		log.G(ctx).WithField(logfields.UVMID, vm.ID()).Debug("added utility VM to pool")
+	}
+}
+
+// Close stops the background health checks and closes the idle utility VMs.
+// Utility VMs already handed out are not affected.
+func (p *Pool) Close() {
+	p.m.Lock()
+	if p.closed {
+		p.m.Unlock()
+		return
+	}
+	p.closed = true
+	idle := p.idle
+	p.idle = nil
+	p.m.Unlock()
+
+	close(p.stop)
+	<-p.done
+	p.evict(context.Background(), idle, "pool closed")
+}
+
+// configHash returns the hash of `opts` ignoring its ID, and a copy of `opts`
+// without an ID to create pooled utility VMs from.
+func configHash(opts *uvm.OptionsLCOW) (string, *uvm.OptionsLCOW, error) {
+	template := copyOptions(opts)
+	b, err := json.Marshal(template)
+	if err != nil {
+		return "", nil, err
+	}
+	h := sha256.Sum256(b)
+	return hex.EncodeToString(h[:]), template, nil
+}
+
+// copyOptions returns a copy of `opts` without an ID or output handler, so
+// that both are generated for each utility VM created from it. The log capture
+// directory is dropped too as it belongs to the requesting pod: the process
+// that takes the utility VM captures its guest logs and passes its output to
+// its own handler with uvm.AttachOutput. The copy is recoverable so that the
+// utility VM can be handed to another process, which requires the guest
+// connection of the HCS.
+func copyOptions(opts *uvm.OptionsLCOW) *uvm.OptionsLCOW {
+	c := *opts
+	o := *opts.Options
+	o.ID = ""
+	o.Recoverable = true
+	o.ExternalGuestConnection = false
+	c.Options = &o
+	c.OutputHandler = nil
+	c.LogCaptureDir = ""
+	return &c
+}
@@ -0,0 +1,252 @@
+package uvmpool
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Microsoft/hcsshim/internal/uvm"
+)
+
+type fakeVM struct {
+	id        string
+	unhealthy bool
+	closed    bool
+	released  bool
+	relayed   chan struct{}
+}
+
+func (vm *fakeVM) ID() string { return vm.id }
+
+func (vm *fakeVM) Close() error {
+	vm.closed = true
+	return nil
+}
+
+func (vm *fakeVM) PingGuest(ctx context.Context) (time.Duration, error) {
+	if vm.unhealthy {
+		return 0, errors.New("unhealthy")
+	}
+	return time.Millisecond, nil
+}
+
+func (vm *fakeVM) Describe() ([]byte, error) {
+	return []byte(vm.id), nil
+}
+
+func (vm *fakeVM) Release() error {
+	vm.released = true
+	return nil
+}
+
+func (vm *fakeVM) OutputRelayed() <-chan struct{} {
+	return vm.relayed
+}
+
+type fakeHost struct {
+	created   []*fakeVM
+	available uint64
+	now       time.Time
+}
+
+func newTestPool(config Config) (*Pool, *fakeHost) {
+	h := &fakeHost{available: 1 << 20, now: time.Unix(0, 0)}
+	create := func(ctx context.Context, opts *uvm.OptionsLCOW) (instance, error) {
+		id := opts.ID
+		if id == "" {
+			id = fmt.Sprintf("pooled%d", len(h.created))
+		}
+		vm := &fakeVM{id: id, relayed: make(chan struct{})}
+		h.created = append(h.created, vm)
+		return vm, nil
+	}
+	available := func() (uint64, error) { return h.available, nil }
+	now := func() time.Time { return h.now }
+	return newPool(config, create, available, now), h
+}
+
+func testOptions(id string, memoryInMB int32) *uvm.OptionsLCOW {
+	return &uvm.OptionsLCOW{Options: &uvm.Options{ID: id, MemorySizeInMB: memoryInMB}}
+}
+
+func TestPoolReusesIdleVM(t *testing.T) {
+	ctx := context.Background()
+	p, h := newTestPool(Config{Size: 1})
+
+	vm, err := p.take(ctx, testOptions("pod1@vm", 1024))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vm != nil || len(h.created) != 0 {
+		t.Fatal("expected no utility VM before the pool is filled")
+	}
+	p.maintain(ctx, false)
+	if len(h.created) != 1 || len(p.idle) != 1 {
+		t.Fatalf("expected the pool to be filled, got %d idle", len(p.idle))
+	}
+
+	vm, err = p.take(ctx, testOptions("pod2@vm", 1024))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vm == nil || vm.ID() != "pooled0" || len(p.idle) != 0 {
+		t.Fatal("expected the pooled utility VM")
+	}
+
+	vm, err = p.take(ctx, testOptions("pod3@vm", 2048))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vm != nil {
+		t.Fatalf("expected no utility VM for a different configuration, got %s", vm.ID())
+	}
+	p.maintain(ctx, false)
+	if len(p.idle) != 2 {
+		t.Fatalf("expected one idle utility VM per configuration, got %d", len(p.idle))
+	}
+}
+
+func TestPoolDisabled(t *testing.T) {
+	ctx := context.Background()
+	p, h := newTestPool(Config{})
+	vm, err := p.take(ctx, testOptions("pod@vm", 1024))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.maintain(ctx, true)
+	if vm != nil || len(h.created) != 0 || len(p.idle) != 0 {
+		t.Fatal("expected no utility VMs to be pooled")
+	}
+}
+
+func TestPoolEvictsUnhealthyAndExpired(t *testing.T) {
+	ctx := context.Background()
+	p, h := newTestPool(Config{Size: 2, MaxIdleAge: time.Minute})
+	if _, err := p.take(ctx, testOptions("pod@vm", 1024)); err != nil {
+		t.Fatal(err)
+	}
+	p.maintain(ctx, false)
+	first, second := h.created[0], h.created[1]
+
+	first.unhealthy = true
+	p.maintain(ctx, true)
+	if !first.closed || second.closed || len(p.idle) != 2 {
+		t.Fatal("expected the unhealthy utility VM to be replaced")
+	}
+
+	h.now = h.now.Add(2 * time.Minute)
+	p.maintain(ctx, false)
+	if !second.closed || len(p.idle) != 2 || len(h.created) != 5 {
+		t.Fatalf("expected the expired utility VMs to be replaced, created %d", len(h.created))
+	}
+
+	// An unhealthy utility VM is discarded rather than handed out.
+	p.idle[0].vm.(*fakeVM).unhealthy = true
+	vm, err := p.take(ctx, testOptions("pod2@vm", 1024))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vm == nil || vm.(*fakeVM).unhealthy || !h.created[3].closed {
+		t.Fatal("expected the unhealthy utility VM to be discarded")
+	}
+}
+
+func TestPoolMemoryPressure(t *testing.T) {
+	ctx := context.Background()
+	p, h := newTestPool(Config{Size: 3, MinAvailableMemoryInMB: 4096})
+	if _, err := p.take(ctx, testOptions("pod@vm", 1024)); err != nil {
+		t.Fatal(err)
+	}
+	p.maintain(ctx, false)
+	if len(p.idle) != 3 {
+		t.Fatalf("expected 3 idle utility VMs, got %d", len(p.idle))
+	}
+
+	h.available = 2500
+	p.maintain(ctx, false)
+	if len(p.idle) != 1 || !h.created[0].closed || !h.created[1].closed || h.created[2].closed {
+		t.Fatal("expected the two oldest utility VMs to be evicted")
+	}
+	if len(h.created) != 3 {
+		t.Fatal("expected the pool not to be refilled under memory pressure")
+	}
+
+	h.available = 8192
+	p.maintain(ctx, false)
+	if len(p.idle) != 3 {
+		t.Fatal("expected the pool to be refilled once memory is available")
+	}
+}
+
+func TestPoolClose(t *testing.T) {
+	ctx := context.Background()
+	p, h := newTestPool(Config{Size: 1})
+	if _, err := p.take(ctx, testOptions("pod@vm", 1024)); err != nil {
+		t.Fatal(err)
+	}
+	p.maintain(ctx, false)
+	if _, err := p.take(ctx, testOptions("pod@vm", 1024)); err != nil {
+		t.Fatal(err)
+	}
+	p.maintain(ctx, false)
+	go p.run()
+	p.Close()
+	for _, vm := range h.created[1:] {
+		if !vm.closed {
+			t.Fatalf("expected %s to be closed", vm.id)
+		}
+	}
+	if h.created[0].closed {
+		t.Fatal("utility VMs handed out must not be closed")
+	}
+	vm, err := p.take(ctx, testOptions("pod2@vm", 1024))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vm != nil {
+		t.Fatal("expected no utility VM from a closed pool")
+	}
+}
+
+func TestPoolServiceTake(t *testing.T) {
+	ctx := context.Background()
+	p, h := newTestPool(Config{Size: 1})
+	s := &poolService{p: p}
+	opts, err := json.Marshal(testOptions("pod@vm", 1024))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := s.Take(ctx, &TakeRequest{Options: opts})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Description) != 0 {
+		t.Fatal("expected an empty response when no utility VM is idle")
+	}
+
+	p.maintain(ctx, false)
+	resp, err = s.Take(ctx, &TakeRequest{Options: opts})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(resp.Description) != "pooled0" {
+		t.Fatalf("expected the description of the pooled utility VM, got %q", resp.Description)
+	}
+	if vm := h.created[0]; !vm.released || vm.closed {
+		t.Fatal("expected the utility VM to be released without being closed")
+	}
+	if n := p.Relaying(); n != 1 {
+		t.Fatalf("expected the output of the handed out utility VM to be relayed, got %d", n)
+	}
+	close(h.created[0].relayed)
+	for i := 0; p.Relaying() != 0; i++ {
+		if i == 100 {
+			t.Fatal("expected the relay to end once the utility VM exited")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,107 @@
+package uvmpool
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+
+	"github.com/Microsoft/go-winio"
+	"github.com/Microsoft/hcsshim/internal/log"
+	"github.com/Microsoft/hcsshim/internal/logfields"
+	"github.com/Microsoft/hcsshim/internal/uvm"
+	"github.com/containerd/ttrpc"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Serve serves the pool on `l`, handing its idle utility VMs to the shims that
+// request them with Get, until `l` is closed.
+//
+// A utility VM is handed out by describing it and releasing it without
+// terminating it, for the shim to reopen it. From then on the shim owns it,
+// but its guest output is relayed by this process until it exits. See
+// Pool.Relaying.
+func (p *Pool) Serve(ctx context.Context, l net.Listener) error {
+	s, err := ttrpc.NewServer()
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+	RegisterPoolService(s, &poolService{p: p})
+	return s.Serve(ctx, l)
+}
+
+type poolService struct {
+	p *Pool
+}
+
+var _ = (PoolService)(&poolService{})
+
+func (s *poolService) Take(ctx context.Context, req *TakeRequest) (*TakeResponse, error) {
+	opts := &uvm.OptionsLCOW{}
+	if err := json.Unmarshal(req.Options, opts); err != nil {
+		return nil, errors.Wrap(err, "failed to read utility VM options")
+	}
+	if opts.Options == nil {
+		return nil, errors.New("utility VM options are missing")
+	}
+	vm, err := s.p.take(ctx, opts)
+	if err != nil || vm == nil {
+		return &TakeResponse{}, err
+	}
+	description, err := vm.Describe()
+	if err == nil {
+		err = vm.Release()
+	}
+	if err != nil {
+		vm.Close()
+		return nil, errors.Wrapf(err, "failed to hand out pooled utility VM %s", vm.ID())
+	}
+	s.p.handedOut(vm)
+	log.G(ctx).WithField(logfields.UVMID, vm.ID()).Info("handed out pooled utility VM")
+	return &TakeResponse{Description: description}, nil
+}
+
+// Get takes an idle utility VM created with the same options as `opts`,
+// ignoring their ID, from the pool served at `address`, reopens it and renames
+// it to `opts.ID`. It returns nil if the pool has no such utility VM, in which
+// case the caller is expected to create one.
+//
+// The utility VM is recoverable and uses the guest connection of the HCS,
+// whatever `opts` asks for. Its guest output is passed to `opts.OutputHandler`
+// and its guest logs, serial console and kernel panics are captured as `opts`
+// asks for, as for a utility VM created with `opts`.
+func Get(ctx context.Context, address string, opts *uvm.OptionsLCOW) (_ *uvm.UtilityVM, err error) {
+	b, err := json.Marshal(opts)
+	if err != nil {
+		return nil, err
+	}
+	c, err := winio.DialPipeContext(ctx, address)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to connect to utility VM pool")
+	}
+	cl := ttrpc.NewClient(c, ttrpc.WithOnClose(func() { c.Close() }))
+	defer cl.Close()
+
+	resp, err := NewPoolClient(cl).Take(ctx, &TakeRequest{Options: b})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to take utility VM from pool")
+	}
+	if len(resp.Description) == 0 {
+		return nil, nil
+	}
+	vm, err := uvm.Open(ctx, resp.Description)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open pooled utility VM")
+	}
+	log.G(ctx).WithFields(logrus.Fields{
+		logfields.UVMID: opts.ID,
+		"system-id":     vm.SystemID(),
+	}).Debug("using pooled utility VM")
+	vm.Rename(opts.ID)
+	if err := vm.AttachOutput(opts); err != nil {
+		vm.Close()
+		return nil, errors.Wrap(err, "failed to attach to the output of pooled utility VM")
+	}
+	return vm, nil
+}
@@ -0,0 +1,59 @@
+// +build functional uvmclose
+
+package functional
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Microsoft/hcsshim/internal/uvm"
+	"github.com/Microsoft/hcsshim/osversion"
+	testutilities "github.com/Microsoft/hcsshim/test/functional/utilities"
+)
+
+// closeWithTimeout closes `vm` and fails the test if Close does not return
+// in time.
+func closeWithTimeout(t *testing.T, vm *uvm.UtilityVM) {
+	done := make(chan error, 1)
+	go func() {
+		done <- vm.Close()
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Logf("close: %s", err)
+		}
+	case <-time.After(time.Minute):
+		t.Fatal("timed out closing the utility VM")
+	}
+}
+
+func TestCloseNotStarted_LCOW(t *testing.T) {
+	testutilities.RequiresBuild(t, osversion.RS5)
+
+	vm, err := uvm.CreateLCOW(context.Background(), uvm.NewDefaultOptionsLCOW(t.Name(), ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	closeWithTimeout(t, vm)
+	if vm.Exited() {
+		t.Fatal("a utility VM that was never started should not report an exit")
+	}
+}
+
+func TestCloseFailedStart_LCOW(t *testing.T) {
+	testutilities.RequiresBuild(t, osversion.RS5)
+
+	vm, err := uvm.CreateLCOW(context.Background(), uvm.NewDefaultOptionsLCOW(t.Name(), ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := vm.Start(ctx); err == nil {
+		vm.Close()
+		t.Fatal("expected start with a cancelled context to fail")
+	}
+	closeWithTimeout(t, vm)
+}